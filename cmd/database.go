@@ -117,6 +117,22 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction tag index table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionSplit))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction split table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionSplitTagIndex))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction split tag index table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionTemplate))
 
 	if err != nil {
@@ -141,6 +157,14 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] user custom exchange rate table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.UserCustomDatedExchangeRate))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] user custom dated exchange rate table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.UserApplicationCloudSetting))
 
 	if err != nil {
@@ -181,6 +205,38 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] stock table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.CryptocurrencyPriceHistory))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] cryptocurrency price history table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.StockPriceHistory))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] stock price history table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.ExchangeRateHistory))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] exchange rate history table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.InvestmentTrade))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] investment trade table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.ExternalDataSourceConfig))
 
 	if err != nil {
@@ -189,6 +245,70 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] external data source config table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.StockDataSourceRoute))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] stock data source route table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.StockCorporateAction))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] stock corporate action table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.StockCorporateActionAppliedRecord))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] stock corporate action applied record table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.PriceAlert))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] price alert table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.Budget))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] budget table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.Goal))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] savings goal table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionReconciliation))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction reconciliation table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionRefund))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction refund table maintained successfully")
+
 	err = seedDefaultData(c)
 	if err != nil {
 		return err
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/urfave/cli/v3"
+
+	clis "github.com/mayswind/ezbookkeeping/pkg/cli"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+)
+
+// ExchangeRates represents the exchange rates command
+var ExchangeRates = &cli.Command{
+	Name:  "exchangerates",
+	Usage: "ezBookkeeping exchange rates maintenance",
+	Commands: []*cli.Command{
+		{
+			Name:   "backfill",
+			Usage:  "Fetch historical exchange rates from current exchange rates data source and save them to exchange rate history",
+			Action: bindAction(backfillExchangeRates),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "from",
+					Aliases:  []string{"f"},
+					Required: true,
+					Usage:    "Start date (yyyy-mm-dd, inclusive)",
+				},
+				&cli.StringFlag{
+					Name:     "to",
+					Aliases:  []string{"t"},
+					Required: true,
+					Usage:    "End date (yyyy-mm-dd, inclusive)",
+				},
+			},
+		},
+	},
+}
+
+func backfillExchangeRates(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	startDate := c.String("from")
+	endDate := c.String("to")
+	savedDays, err := clis.ExchangeRates.BackfillExchangeRates(c, startDate, endDate)
+
+	if err != nil {
+		log.CliErrorf(c, "[exchange_rates.backfillExchangeRates] error occurs when backfilling exchange rates")
+		return err
+	}
+
+	log.CliInfof(c, "[exchange_rates.backfillExchangeRates] exchange rates of %d days from \"%s\" to \"%s\" have been saved", savedDays, startDate, endDate)
+
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/storage"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+	"github.com/mayswind/ezbookkeeping/pkg/wallets"
 )
 
 func initializeSystem(c *core.CliContext) (*settings.Config, error) {
@@ -165,6 +166,15 @@ func initializeSystem(c *core.CliContext) (*settings.Config, error) {
 		return nil, err
 	}
 
+	err = wallets.InitializeWalletBalanceReader(config)
+
+	if err != nil {
+		if !isDisableBootLog {
+			log.BootErrorf(c, "[initializer.initializeSystem] initializes wallet balance reader failed, because %s", err.Error())
+		}
+		return nil, err
+	}
+
 	cfgJson, _ := json.Marshal(getConfigWithoutSensitiveData(config))
 
 	if !isDisableBootLog {
@@ -232,5 +242,10 @@ func getConfigWithoutSensitiveData(config *settings.Config) *settings.Config {
 		clonedConfig.StockAPIKey = "****"
 	}
 
+	// the json-rpc url may contain the credentials or api key of the node
+	if clonedConfig.WalletBalanceJsonRpcUrl != "" {
+		clonedConfig.WalletBalanceJsonRpcUrl = "****"
+	}
+
 	return clonedConfig
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
@@ -21,6 +22,11 @@ func seedDefaultData(c *core.CliContext) error {
 		return err
 	}
 
+	err = seedUserWatchlists(c)
+	if err != nil {
+		return err
+	}
+
 	err = seedExternalDataSourceConfigs(c)
 	if err != nil {
 		return err
@@ -34,7 +40,7 @@ func seedCryptocurrencies(c *core.CliContext) error {
 	sess := db.NewSession(c)
 	defer sess.Close()
 
-	count, err := sess.Count(new(models.Cryptocurrency))
+	count, err := sess.Where("uid=?", 0).Count(new(models.Cryptocurrency))
 	if err != nil {
 		return err
 	}
@@ -43,7 +49,7 @@ func seedCryptocurrencies(c *core.CliContext) error {
 		return nil
 	}
 
-	log.BootInfof(c, "[seeding.seedCryptocurrencies] seeding default cryptocurrencies")
+	log.BootInfof(c, "[seeding.seedCryptocurrencies] seeding global default cryptocurrency watchlist")
 
 	now := time.Now().Unix()
 	defaultCryptos := []models.Cryptocurrency{
@@ -68,7 +74,7 @@ func seedStocks(c *core.CliContext) error {
 	sess := db.NewSession(c)
 	defer sess.Close()
 
-	count, err := sess.Count(new(models.Stock))
+	count, err := sess.Where("uid=?", 0).Count(new(models.Stock))
 	if err != nil {
 		return err
 	}
@@ -77,7 +83,7 @@ func seedStocks(c *core.CliContext) error {
 		return nil
 	}
 
-	log.BootInfof(c, "[seeding.seedStocks] seeding default stocks")
+	log.BootInfof(c, "[seeding.seedStocks] seeding global default stock watchlist")
 
 	now := time.Now().Unix()
 	defaultStocks := []models.Stock{
@@ -100,6 +106,30 @@ func seedStocks(c *core.CliContext) error {
 	return nil
 }
 
+// seedUserWatchlists copies the global default watchlists to the users created before the watchlists became per-user,
+// the users who have ever had any stock or cryptocurrency in their watchlists are skipped
+func seedUserWatchlists(c *core.CliContext) error {
+	for i := 0; i < datastore.Container.UserStore.Count(); i++ {
+		var users []*models.User
+		err := datastore.Container.UserStore.Get(i).NewSession(c).Cols("uid").Where("deleted=?", false).Find(&users)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			if err := services.Cryptocurrencies.InitUserCryptocurrencies(c, user.Uid); err != nil {
+				return err
+			}
+
+			if err := services.Stocks.InitUserStocks(c, user.Uid); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func seedExternalDataSourceConfigs(c *core.CliContext) error {
 	db := datastore.Container.UserDataStore.Choose(0)
 	sess := db.NewSession(c)
@@ -372,6 +372,7 @@ func startWebServer(c *core.CliContext) error {
 			// Accounts
 			apiV1Route.GET("/accounts/list.json", bindApi(api.Accounts.AccountListHandler))
 			apiV1Route.GET("/accounts/get.json", bindApi(api.Accounts.AccountGetHandler))
+			apiV1Route.GET("/accounts/net_worth.json", bindApi(api.Accounts.AccountNetWorthHandler))
 			apiV1Route.POST("/accounts/add.json", bindApi(api.Accounts.AccountCreateHandler))
 			apiV1Route.POST("/accounts/modify.json", bindApi(api.Accounts.AccountModifyHandler))
 			apiV1Route.POST("/accounts/hide.json", bindApi(api.Accounts.AccountHideHandler))
@@ -388,6 +389,8 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.GET("/transactions/statistics.json", bindApi(api.Transactions.TransactionStatisticsHandler))
 			apiV1Route.GET("/transactions/statistics/trends.json", bindApi(api.Transactions.TransactionStatisticsTrendsHandler))
 			apiV1Route.GET("/transactions/statistics/asset_trends.json", bindApi(api.Transactions.TransactionStatisticsAssetTrendsHandler))
+			apiV1Route.GET("/transactions/statistics/holding_income.json", bindApi(api.Transactions.TransactionStatisticsHoldingIncomeHandler))
+			apiV1Route.GET("/transactions/statistics/fx_gain_loss.json", bindApi(api.Transactions.TransactionStatisticsFxGainLossHandler))
 			apiV1Route.GET("/transactions/amounts.json", bindApi(api.Transactions.TransactionAmountsHandler))
 			apiV1Route.GET("/transactions/get.json", bindApi(api.Transactions.TransactionGetHandler))
 			apiV1Route.POST("/transactions/add.json", bindApi(api.Transactions.TransactionCreateHandler))
@@ -455,8 +458,12 @@ func startWebServer(c *core.CliContext) error {
 
 			// Exchange Rates
 			apiV1Route.GET("/exchange_rates/latest.json", bindApi(api.ExchangeRates.LatestExchangeRateHandler))
+			apiV1Route.GET("/exchange_rates/history.json", bindApi(api.ExchangeRates.ExchangeRateHistoryHandler))
 			apiV1Route.POST("/exchange_rates/user_custom/update.json", bindApi(api.ExchangeRates.UserCustomExchangeRateUpdateHandler))
 			apiV1Route.POST("/exchange_rates/user_custom/delete.json", bindApi(api.ExchangeRates.UserCustomExchangeRateDeleteHandler))
+			apiV1Route.GET("/exchange_rates/user_custom/dated/list.json", bindApi(api.ExchangeRates.UserCustomDatedExchangeRateListHandler))
+			apiV1Route.POST("/exchange_rates/user_custom/dated/add.json", bindApi(api.ExchangeRates.UserCustomDatedExchangeRateCreateHandler))
+			apiV1Route.POST("/exchange_rates/user_custom/dated/delete.json", bindApi(api.ExchangeRates.UserCustomDatedExchangeRateDeleteHandler))
 
 			// Cryptocurrency
 			apiV1Route.GET("/cryptocurrencies/list.json", bindApi(api.Cryptocurrencies.CryptocurrencyListHandler))
@@ -464,9 +471,15 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/cryptocurrencies/modify.json", bindApi(api.Cryptocurrencies.CryptocurrencyModifyHandler))
 			apiV1Route.POST("/cryptocurrencies/hide.json", bindApi(api.Cryptocurrencies.CryptocurrencyHideHandler))
 			apiV1Route.POST("/cryptocurrencies/delete.json", bindApi(api.Cryptocurrencies.CryptocurrencyDeleteHandler))
+			apiV1Route.GET("/cryptocurrencies/defaults/list.json", bindApi(api.Cryptocurrencies.DefaultCryptocurrencyListHandler))
+			apiV1Route.POST("/cryptocurrencies/defaults/add.json", bindApi(api.Cryptocurrencies.DefaultCryptocurrencyAddHandler))
+			apiV1Route.POST("/cryptocurrencies/defaults/modify.json", bindApi(api.Cryptocurrencies.DefaultCryptocurrencyModifyHandler))
+			apiV1Route.POST("/cryptocurrencies/defaults/hide.json", bindApi(api.Cryptocurrencies.DefaultCryptocurrencyHideHandler))
+			apiV1Route.POST("/cryptocurrencies/defaults/delete.json", bindApi(api.Cryptocurrencies.DefaultCryptocurrencyDeleteHandler))
 			apiV1Route.GET("/cryptocurrencies/config/get.json", bindApi(api.Cryptocurrencies.CryptocurrencyConfigGetHandler))
 			apiV1Route.POST("/cryptocurrencies/config/save.json", bindApi(api.Cryptocurrencies.CryptocurrencyConfigSaveHandler))
 			apiV1Route.GET("/cryptocurrency/latest.json", bindApi(api.Cryptocurrencies.LatestCryptocurrencyPriceHandler))
+			apiV1Route.GET("/cryptocurrencies/history.json", bindApi(api.Cryptocurrencies.CryptocurrencyPriceHistoryHandler))
 
 			// Stocks
 			apiV1Route.GET("/stocks/list.json", bindApi(api.Stocks.StockListHandler))
@@ -474,9 +487,70 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/stocks/modify.json", bindApi(api.Stocks.StockModifyHandler))
 			apiV1Route.POST("/stocks/hide.json", bindApi(api.Stocks.StockHideHandler))
 			apiV1Route.POST("/stocks/delete.json", bindApi(api.Stocks.StockDeleteHandler))
+			apiV1Route.GET("/stocks/defaults/list.json", bindApi(api.Stocks.DefaultStockListHandler))
+			apiV1Route.POST("/stocks/defaults/add.json", bindApi(api.Stocks.DefaultStockAddHandler))
+			apiV1Route.POST("/stocks/defaults/modify.json", bindApi(api.Stocks.DefaultStockModifyHandler))
+			apiV1Route.POST("/stocks/defaults/hide.json", bindApi(api.Stocks.DefaultStockHideHandler))
+			apiV1Route.POST("/stocks/defaults/delete.json", bindApi(api.Stocks.DefaultStockDeleteHandler))
 			apiV1Route.GET("/stocks/config/get.json", bindApi(api.Stocks.StockConfigGetHandler))
 			apiV1Route.POST("/stocks/config/save.json", bindApi(api.Stocks.StockConfigSaveHandler))
+			apiV1Route.GET("/stocks/config/list.json", bindApi(api.Stocks.StockConfigListHandler))
+			apiV1Route.POST("/stocks/config/delete.json", bindApi(api.Stocks.StockConfigDeleteHandler))
+			apiV1Route.GET("/stocks/routes/list.json", bindApi(api.Stocks.StockDataSourceRouteListHandler))
+			apiV1Route.POST("/stocks/routes/save.json", bindApi(api.Stocks.StockDataSourceRouteSaveHandler))
+			apiV1Route.POST("/stocks/routes/delete.json", bindApi(api.Stocks.StockDataSourceRouteDeleteHandler))
 			apiV1Route.GET("/stocks/latest.json", bindApi(api.Stocks.LatestStockPriceHandler))
+			apiV1Route.GET("/stocks/history.json", bindApi(api.Stocks.StockPriceHistoryHandler))
+			apiV1Route.GET("/stocks/corporate_actions/list.json", bindApi(api.Stocks.StockCorporateActionListHandler))
+			apiV1Route.POST("/stocks/corporate_actions/add.json", bindApi(api.Stocks.StockCorporateActionAddHandler))
+			apiV1Route.POST("/stocks/corporate_actions/apply.json", bindApi(api.Stocks.StockCorporateActionApplyHandler))
+			apiV1Route.POST("/stocks/corporate_actions/delete.json", bindApi(api.Stocks.StockCorporateActionDeleteHandler))
+
+			// Investments
+			apiV1Route.GET("/investments/trades/list.json", bindApi(api.Investments.TradeListHandler))
+			apiV1Route.POST("/investments/trades/add.json", bindApi(api.Investments.TradeCreateHandler))
+			apiV1Route.POST("/investments/trades/delete.json", bindApi(api.Investments.TradeDeleteHandler))
+			apiV1Route.GET("/investments/holdings.json", bindApi(api.Investments.HoldingListHandler))
+			apiV1Route.GET("/investments/realized_gains.json", bindApi(api.Investments.RealizedGainListHandler))
+			apiV1Route.GET("/investments/performance.json", bindApi(api.Investments.PerformanceHandler))
+
+			if config.EnableDataExport {
+				apiV1Route.GET("/investments/realized_gains.csv", bindCsv(api.Investments.RealizedGainExportHandler))
+			}
+
+			// Price Alerts
+			apiV1Route.GET("/price_alerts/list.json", bindApi(api.PriceAlerts.AlertListHandler))
+			apiV1Route.POST("/price_alerts/add.json", bindApi(api.PriceAlerts.AlertCreateHandler))
+			apiV1Route.POST("/price_alerts/delete.json", bindApi(api.PriceAlerts.AlertDeleteHandler))
+
+			// Budgets
+			apiV1Route.GET("/budgets/list.json", bindApi(api.Budgets.BudgetListHandler))
+			apiV1Route.GET("/budgets/get.json", bindApi(api.Budgets.BudgetGetHandler))
+			apiV1Route.POST("/budgets/add.json", bindApi(api.Budgets.BudgetCreateHandler))
+			apiV1Route.POST("/budgets/modify.json", bindApi(api.Budgets.BudgetModifyHandler))
+			apiV1Route.POST("/budgets/delete.json", bindApi(api.Budgets.BudgetDeleteHandler))
+			apiV1Route.GET("/budgets/progress.json", bindApi(api.Budgets.BudgetProgressHandler))
+
+			// Savings Goals
+			apiV1Route.GET("/goals/list.json", bindApi(api.Goals.GoalListHandler))
+			apiV1Route.GET("/goals/get.json", bindApi(api.Goals.GoalGetHandler))
+			apiV1Route.POST("/goals/add.json", bindApi(api.Goals.GoalCreateHandler))
+			apiV1Route.POST("/goals/modify.json", bindApi(api.Goals.GoalModifyHandler))
+			apiV1Route.POST("/goals/delete.json", bindApi(api.Goals.GoalDeleteHandler))
+			apiV1Route.GET("/goals/progress.json", bindApi(api.Goals.GoalProgressHandler))
+
+			// Transaction Reconciliations
+			apiV1Route.POST("/transactions/reconciliation_status/modify.json", bindApi(api.TransactionReconciliations.TransactionReconciliationStatusModifyHandler))
+			apiV1Route.GET("/reconciliations/list.json", bindApi(api.TransactionReconciliations.TransactionReconciliationListHandler))
+			apiV1Route.GET("/reconciliations/preview.json", bindApi(api.TransactionReconciliations.TransactionReconciliationPreviewHandler))
+			apiV1Route.POST("/reconciliations/add.json", bindApi(api.TransactionReconciliations.TransactionReconciliationCreateHandler))
+			apiV1Route.POST("/reconciliations/delete.json", bindApi(api.TransactionReconciliations.TransactionReconciliationDeleteHandler))
+
+			// Transaction Refunds
+			apiV1Route.GET("/transactions/refunds/list.json", bindApi(api.TransactionRefunds.TransactionRefundListHandler))
+			apiV1Route.POST("/transactions/refunds/add.json", bindApi(api.TransactionRefunds.TransactionRefundCreateHandler))
+			apiV1Route.POST("/transactions/refunds/delete.json", bindApi(api.TransactionRefunds.TransactionRefundDeleteHandler))
+			apiV1Route.GET("/transactions/reimbursements/outstanding.json", bindApi(api.TransactionRefunds.TransactionOutstandingReimbursementListHandler))
 
 			// System
 			apiV1Route.GET("/systems/version.json", bindApi(api.Systems.VersionHandler))
@@ -4,15 +4,12 @@ import (
 	"sort"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
-	"github.com/mayswind/ezbookkeeping/pkg/cryptocurrency"
 	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
-	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
-	"github.com/mayswind/ezbookkeeping/pkg/stocks"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 	"github.com/mayswind/ezbookkeeping/pkg/validators"
 )
@@ -21,11 +18,10 @@ import (
 type AccountsApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
-	accounts                  *services.AccountService
-	users                     *services.UserService
-	externalDataSourceConfigs *services.ExternalDataSourceConfigService
-	cryptocurrencies          *services.CryptocurrencyService
-	stocks                    *services.StockService
+	ApiUsingMarketPrices
+	accounts              *services.AccountService
+	transactionCategories *services.TransactionCategoryService
+	users                 *services.UserService
 }
 
 // Initialize an account api singleton instance
@@ -40,11 +36,10 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
-		accounts:                  services.Accounts,
-		users:                     services.Users,
-		externalDataSourceConfigs: services.ExternalDataSourceConfigs,
-		cryptocurrencies:          services.Cryptocurrencies,
-		stocks:                    services.Stocks,
+		ApiUsingMarketPrices:  apiUsingMarketPrices,
+		accounts:              services.Accounts,
+		transactionCategories: services.TransactionCategories,
+		users:                 services.Users,
 	}
 )
 
@@ -267,6 +262,27 @@ func (a *AccountsApi) AccountCreateHandler(c *core.WebContext) (any, *errs.Error
 	mainAccount := a.createNewAccountModel(uid, &accountCreateReq, false, maxOrderId+1)
 	childrenAccounts, childrenAccountBalanceTimes := a.createSubAccountModels(uid, &accountCreateReq)
 
+	if mainAccount.GetWalletAddress() != "" && (mainAccount.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT || mainAccount.GetAssetType() != models.ACCOUNT_ASSET_TYPE_CRYPTO) {
+		log.Warnf(c, "[accounts.AccountCreateHandler] cannot set wallet address for non cryptocurrency account")
+		return nil, errs.ErrWalletAddressOnlyForCryptocurrencyAccount
+	}
+
+	for i := 0; i < len(childrenAccounts); i++ {
+		if childrenAccounts[i].GetWalletAddress() != "" && childrenAccounts[i].GetAssetType() != models.ACCOUNT_ASSET_TYPE_CRYPTO {
+			log.Warnf(c, "[accounts.AccountCreateHandler] cannot set wallet address for non cryptocurrency sub-account#%d", i)
+			return nil, errs.ErrWalletAddressOnlyForCryptocurrencyAccount
+		}
+	}
+
+	for _, account := range append([]*models.Account{mainAccount}, childrenAccounts...) {
+		err = a.checkWalletBalanceCategories(c, uid, account.GetWalletAddress(), account.Extend.WalletIncomeCategoryId, account.Extend.WalletExpenseCategoryId)
+
+		if err != nil {
+			log.Warnf(c, "[accounts.AccountCreateHandler] wallet balance adjustment categories of account \"%s\" are invalid, because %s", account.Name, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
 	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && accountCreateReq.ClientSessionId != "" {
 		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_ACCOUNT, uid, accountCreateReq.ClientSessionId)
 
@@ -400,6 +416,22 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 		}
 	}
 
+	if a.isWalletSettingsModified(&accountModifyReq) {
+		walletAddress, walletIncomeCategoryId, walletExpenseCategoryId := a.getModifiedWalletSettings(&accountModifyReq, mainAccount)
+
+		if walletAddress != "" && (mainAccount.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT || mainAccount.GetAssetType() != models.ACCOUNT_ASSET_TYPE_CRYPTO) {
+			log.Warnf(c, "[accounts.AccountModifyHandler] cannot set wallet address for non cryptocurrency account")
+			return nil, errs.ErrWalletAddressOnlyForCryptocurrencyAccount
+		}
+
+		err = a.checkWalletBalanceCategories(c, uid, walletAddress, walletIncomeCategoryId, walletExpenseCategoryId)
+
+		if err != nil {
+			log.Warnf(c, "[accounts.AccountModifyHandler] wallet balance adjustment categories are invalid, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
 	if mainAccount.Type == models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
 		if len(accountModifyReq.SubAccounts) > 0 {
 			log.Warnf(c, "[accounts.AccountModifyHandler] account cannot have any sub-accounts")
@@ -488,6 +520,22 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 				log.Warnf(c, "[accounts.AccountModifyHandler] sub-account#%d cannot set statement date", i)
 				return nil, errs.ErrCannotSetStatementDateForSubAccount
 			}
+
+			if a.isWalletSettingsModified(subAccountReq) {
+				walletAddress, walletIncomeCategoryId, walletExpenseCategoryId := a.getModifiedWalletSettings(subAccountReq, accountMap[subAccountReq.Id])
+
+				if walletAddress != "" && mainAccount.GetAssetType() != models.ACCOUNT_ASSET_TYPE_CRYPTO {
+					log.Warnf(c, "[accounts.AccountModifyHandler] cannot set wallet address for non cryptocurrency sub-account#%d", i)
+					return nil, errs.ErrWalletAddressOnlyForCryptocurrencyAccount
+				}
+
+				err = a.checkWalletBalanceCategories(c, uid, walletAddress, walletIncomeCategoryId, walletExpenseCategoryId)
+
+				if err != nil {
+					log.Warnf(c, "[accounts.AccountModifyHandler] wallet balance adjustment categories of sub-account#%d are invalid, because %s", i, err.Error())
+					return nil, errs.Or(err, errs.ErrOperationFailed)
+				}
+			}
 		}
 	}
 
@@ -774,7 +822,10 @@ func (a *AccountsApi) SubAccountDeleteHandler(c *core.WebContext) (any, *errs.Er
 
 func (a *AccountsApi) createNewAccountModel(uid int64, accountCreateReq *models.AccountCreateRequest, isSubAccount bool, order int32) *models.Account {
 	accountExtend := &models.AccountExtend{
-		AssetType: accountCreateReq.AssetType,
+		AssetType:               accountCreateReq.AssetType,
+		WalletAddress:           accountCreateReq.WalletAddress,
+		WalletIncomeCategoryId:  a.getWalletBalanceCategoryId(accountCreateReq.WalletAddress, accountCreateReq.WalletIncomeCategoryId),
+		WalletExpenseCategoryId: a.getWalletBalanceCategoryId(accountCreateReq.WalletAddress, accountCreateReq.WalletExpenseCategoryId),
 	}
 
 	if !isSubAccount && accountCreateReq.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
@@ -803,8 +854,13 @@ func (a *AccountsApi) createNewSubAccountModelForModify(uid int64, accountType m
 		assetType = *accountModifyReq.AssetType
 	}
 
+	walletAddress, walletIncomeCategoryId, walletExpenseCategoryId := a.getModifiedWalletSettings(accountModifyReq, nil)
+
 	accountExtend := &models.AccountExtend{
-		AssetType: assetType,
+		AssetType:               assetType,
+		WalletAddress:           walletAddress,
+		WalletIncomeCategoryId:  walletIncomeCategoryId,
+		WalletExpenseCategoryId: walletExpenseCategoryId,
 	}
 
 	return &models.Account{
@@ -850,8 +906,13 @@ func (a *AccountsApi) getToUpdateAccount(uid int64, accountModifyReq *models.Acc
 		assetType = oldAccount.Extend.AssetType
 	}
 
+	walletAddress, walletIncomeCategoryId, walletExpenseCategoryId := a.getModifiedWalletSettings(accountModifyReq, oldAccount)
+
 	newAccountExtend := &models.AccountExtend{
-		AssetType: assetType,
+		AssetType:               assetType,
+		WalletAddress:           walletAddress,
+		WalletIncomeCategoryId:  walletIncomeCategoryId,
+		WalletExpenseCategoryId: walletExpenseCategoryId,
 	}
 
 	if !isSubAccount && accountModifyReq.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
@@ -887,13 +948,98 @@ func (a *AccountsApi) getToUpdateAccount(uid int64, accountModifyReq *models.Acc
 	oldAccountExtend := oldAccount.Extend
 
 	if newAccountExtend.AssetType != oldAccountExtend.AssetType ||
-		newAccountExtend.CreditCardStatementDate != oldAccountExtend.CreditCardStatementDate {
+		newAccountExtend.CreditCardStatementDate != oldAccountExtend.CreditCardStatementDate ||
+		newAccountExtend.WalletAddress != oldAccountExtend.WalletAddress ||
+		newAccountExtend.WalletIncomeCategoryId != oldAccountExtend.WalletIncomeCategoryId ||
+		newAccountExtend.WalletExpenseCategoryId != oldAccountExtend.WalletExpenseCategoryId {
 		return newAccount
 	}
 
 	return nil
 }
 
+// checkWalletBalanceCategories checks whether the income and expense categories used to record the balance differences of the wallet address are valid
+func (a *AccountsApi) checkWalletBalanceCategories(c *core.WebContext, uid int64, walletAddress string, incomeCategoryId int64, expenseCategoryId int64) error {
+	if walletAddress == "" {
+		return nil
+	}
+
+	if incomeCategoryId <= 0 || expenseCategoryId <= 0 {
+		return errs.ErrWalletBalanceCategoryRequired
+	}
+
+	categoryTypes := map[int64]models.TransactionCategoryType{
+		incomeCategoryId:  models.CATEGORY_TYPE_INCOME,
+		expenseCategoryId: models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	if len(categoryTypes) < 2 {
+		return errs.ErrTransactionCategoryTypeInvalid
+	}
+
+	for categoryId, categoryType := range categoryTypes {
+		category, err := a.transactionCategories.GetCategoryByCategoryId(c, uid, categoryId)
+
+		if err != nil {
+			return err
+		}
+
+		if category.Type != categoryType {
+			return errs.ErrTransactionCategoryTypeInvalid
+		}
+
+		if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+			return errs.ErrCannotUsePrimaryCategoryForTransaction
+		}
+
+		if category.Hidden {
+			return errs.ErrCannotUseHiddenTransactionCategory
+		}
+	}
+
+	return nil
+}
+
+func (a *AccountsApi) getWalletBalanceCategoryId(walletAddress string, categoryId int64) int64 {
+	if walletAddress == "" {
+		return 0
+	}
+
+	return categoryId
+}
+
+func (a *AccountsApi) isWalletSettingsModified(accountModifyReq *models.AccountModifyRequest) bool {
+	return accountModifyReq.WalletAddress != nil || accountModifyReq.WalletIncomeCategoryId != nil || accountModifyReq.WalletExpenseCategoryId != nil
+}
+
+// getModifiedWalletSettings returns the wallet address and the wallet balance adjustment categories after modification,
+// the settings which are not in the request are kept unchanged
+func (a *AccountsApi) getModifiedWalletSettings(accountModifyReq *models.AccountModifyRequest, oldAccount *models.Account) (string, int64, int64) {
+	var walletAddress string
+	var walletIncomeCategoryId int64
+	var walletExpenseCategoryId int64
+
+	if oldAccount != nil && oldAccount.Extend != nil {
+		walletAddress = oldAccount.Extend.WalletAddress
+		walletIncomeCategoryId = oldAccount.Extend.WalletIncomeCategoryId
+		walletExpenseCategoryId = oldAccount.Extend.WalletExpenseCategoryId
+	}
+
+	if accountModifyReq.WalletAddress != nil {
+		walletAddress = *accountModifyReq.WalletAddress
+	}
+
+	if accountModifyReq.WalletIncomeCategoryId != nil {
+		walletIncomeCategoryId = *accountModifyReq.WalletIncomeCategoryId
+	}
+
+	if accountModifyReq.WalletExpenseCategoryId != nil {
+		walletExpenseCategoryId = *accountModifyReq.WalletExpenseCategoryId
+	}
+
+	return walletAddress, a.getWalletBalanceCategoryId(walletAddress, walletIncomeCategoryId), a.getWalletBalanceCategoryId(walletAddress, walletExpenseCategoryId)
+}
+
 func (a *AccountsApi) getToDeleteSubAccountIds(accountModifyReq *models.AccountModifyRequest, mainAccount *models.Account, accountAndSubAccounts []*models.Account) []int64 {
 	newSubAccountIds := make(map[int64]bool, len(accountModifyReq.SubAccounts))
 
@@ -919,271 +1065,115 @@ func (a *AccountsApi) getToDeleteSubAccountIds(accountModifyReq *models.AccountM
 }
 
 func (a *AccountsApi) calculateAccountValuations(c *core.WebContext, uid int64, accountResps []*models.AccountInfoResponse) {
-	// 1. Get user's default currency
 	user, err := a.users.GetUserById(c, uid)
+
 	if err != nil {
+		log.Warnf(c, "[accounts.calculateAccountValuations] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
 		return
 	}
-	defaultCurrency := user.DefaultCurrency
 
-	// 2. Fetch all rates/prices
-	exchangeRateResponse, _ := exchangerates.Container.GetLatestExchangeRates(c, uid, a.CurrentConfig())
+	accounts := make([]*models.Account, 0, len(accountResps))
+	a.collectValuationAccounts(accountResps, &accounts)
 
-	// Cryptocurrency prices
-	var cryptoPriceResponse *models.LatestCryptocurrencyPriceResponse
-	cryptoConfig, err := a.externalDataSourceConfigs.GetConfig(c, models.EXTERNAL_DATA_SOURCE_TYPE_CRYPTOCURRENCY)
-	if err == nil {
-		cryptos, err := a.cryptocurrencies.GetAllVisibleCryptocurrencies(c)
-		if err == nil {
-			cryptoSymbols := make([]string, len(cryptos))
-			for i, crypto := range cryptos {
-				cryptoSymbols[i] = crypto.Symbol
-			}
-			cryptoPriceResponse, _ = cryptocurrency.Container.GetLatestCryptocurrencyPrices(c, uid, cryptoConfig, cryptoSymbols)
-		}
+	valuator := a.GetAssetValuator(c, uid, user.DefaultCurrency, accounts, 0, 0)
+
+	for i := 0; i < len(accountResps); i++ {
+		a.calculateSingleAccountValuation(accountResps[i], valuator)
 	}
+}
 
-	// Stock prices
-	var stockPriceResponse *models.LatestStockPriceResponse
-	stockConfig, err := a.externalDataSourceConfigs.GetConfig(c, models.EXTERNAL_DATA_SOURCE_TYPE_STOCK)
-	if err == nil {
-		stockList, err := a.stocks.GetAllVisibleStocks(c)
-		if err == nil {
-			stockSymbols := make([]string, len(stockList))
-			for i, stock := range stockList {
-				stockSymbols[i] = stock.Symbol
-			}
-			stockPriceResponse, _ = stocks.Container.GetLatestStockPrices(c, uid, stockConfig, stockSymbols)
-		}
+func (a *AccountsApi) collectValuationAccounts(accountResps []*models.AccountInfoResponse, accounts *[]*models.Account) {
+	for i := 0; i < len(accountResps); i++ {
+		*accounts = append(*accounts, &models.Account{
+			Currency: accountResps[i].Currency,
+			Extend: &models.AccountExtend{
+				AssetType: accountResps[i].AssetType,
+			},
+		})
+
+		a.collectValuationAccounts(accountResps[i].SubAccounts, accounts)
 	}
+}
 
-	// 3. Create maps for quick lookup
-	exchangeRates := make(map[string]float64)
-	exchangeBaseCurrency := ""
-	if exchangeRateResponse != nil {
-		exchangeBaseCurrency = exchangeRateResponse.BaseCurrency
-		for _, rate := range exchangeRateResponse.ExchangeRates {
-			val, _ := utils.StringToFloat64(rate.Rate)
-			exchangeRates[rate.Currency] = val
-		}
+func (a *AccountsApi) calculateSingleAccountValuation(account *models.AccountInfoResponse, valuator *models.AssetValuator) {
+	account.TotalBalance, _ = valuator.GetValue(account.AssetType, account.Currency, account.Balance, 0)
+
+	for i := 0; i < len(account.SubAccounts); i++ {
+		a.calculateSingleAccountValuation(account.SubAccounts[i], valuator)
 	}
-	if _, ok := exchangeRates[exchangeBaseCurrency]; !ok && exchangeBaseCurrency != "" {
-		exchangeRates[exchangeBaseCurrency] = 1.0
-	}
-
-	// Cryptocurrency prices in USD
-	// Convert crypto prices to USD if they're not already in USD
-	cryptoPricesInUSD := make(map[string]float64)
-	if cryptoPriceResponse != nil {
-		cryptoBaseCurrency := cryptoPriceResponse.BaseCurrency
-		
-		// Stablecoins that are typically pegged to USD (1:1)
-		stablecoinsToUSD := map[string]bool{
-			"USDT": true,
-			"USDC": true,
-			"DAI":  true,
-			"BUSD": true,
-			"TUSD": true,
-			"PAX":  true,
-			"USD":  true,
-		}
-		
-		for _, price := range cryptoPriceResponse.Prices {
-			val, _ := utils.StringToFloat64(price.Price)
-			if val > 0 {
-				if cryptoBaseCurrency == "USD" || stablecoinsToUSD[cryptoBaseCurrency] {
-					// Already in USD or stablecoin pegged to USD (1:1)
-					// Note: CoinGecko returns BaseCurrency as "USDT" but prices are actually in USD
-					cryptoPricesInUSD[price.Symbol] = val
-				} else if cryptoBaseCurrency != "" {
-					// Convert from cryptoBaseCurrency to USD
-					// Get rate from cryptoBaseCurrency to exchangeBaseCurrency
-					rateToCryptoBase, ok := exchangeRates[cryptoBaseCurrency]
-					if ok && rateToCryptoBase > 0 {
-						// 1 crypto = val cryptoBaseCurrency
-						// 1 cryptoBaseCurrency = rateToCryptoBase exchangeBaseCurrency
-						// Now convert exchangeBaseCurrency to USD
-						if exchangeBaseCurrency == "USD" {
-							// exchangeBaseCurrency is USD, so rateToCryptoBase is the rate from cryptoBaseCurrency to USD
-							// 1 crypto = val * rateToCryptoBase USD
-							cryptoPricesInUSD[price.Symbol] = val * rateToCryptoBase
-						} else {
-							// Need to convert exchangeBaseCurrency to USD
-							usdRate, hasUSDRate := exchangeRates["USD"]
-							if hasUSDRate && usdRate > 0 {
-								// 1 crypto = val cryptoBaseCurrency
-								// 1 cryptoBaseCurrency = rateToCryptoBase exchangeBaseCurrency
-								// 1 USD = usdRate exchangeBaseCurrency
-								// 1 exchangeBaseCurrency = 1 / usdRate USD
-								// 1 crypto = val * rateToCryptoBase / usdRate USD
-								cryptoPricesInUSD[price.Symbol] = val * rateToCryptoBase / usdRate
-							}
-							// If can't convert, skip this price
-						}
-					} else {
-						// If cryptoBaseCurrency is not in exchangeRates, and it's not a known stablecoin,
-						// we can't convert it, so skip this price
-					}
-				}
-			}
+
+	// the total balance of the account with multiple sub-accounts is the sum of sub-accounts
+	if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+		account.TotalBalance = 0
+
+		for i := 0; i < len(account.SubAccounts); i++ {
+			account.TotalBalance += account.SubAccounts[i].TotalBalance
 		}
 	}
+}
 
-	// Stock prices in USD
-	// Stocks are priced in USD (or should be converted to USD)
-	stockPricesInUSD := make(map[string]float64)
-	if stockPriceResponse != nil {
-		for _, price := range stockPriceResponse.Prices {
-			val, _ := utils.StringToFloat64(price.Price)
-			if val > 0 {
-				if price.Currency == "USD" {
-					// Already in USD
-					stockPricesInUSD[price.Symbol] = val
-				} else {
-					// Convert from stock currency to USD
-					rateToStockCurrency, ok := exchangeRates[price.Currency]
-					if ok && rateToStockCurrency > 0 {
-						// 1 stock = val stockCurrency
-						// 1 stockCurrency = rateToStockCurrency exchangeBaseCurrency
-						// Now convert exchangeBaseCurrency to USD
-						if exchangeBaseCurrency == "USD" {
-							// exchangeBaseCurrency is USD, so rateToStockCurrency is the rate from stockCurrency to USD
-							// 1 stock = val * rateToStockCurrency USD
-							stockPricesInUSD[price.Symbol] = val * rateToStockCurrency
-						} else {
-							// Need to convert exchangeBaseCurrency to USD
-							usdRate, hasUSDRate := exchangeRates["USD"]
-							if hasUSDRate && usdRate > 0 {
-								// 1 stock = val stockCurrency
-								// 1 stockCurrency = rateToStockCurrency exchangeBaseCurrency
-								// 1 USD = usdRate exchangeBaseCurrency
-								// 1 exchangeBaseCurrency = 1 / usdRate USD
-								// 1 stock = val * rateToStockCurrency / usdRate USD
-								stockPricesInUSD[price.Symbol] = val * rateToStockCurrency / usdRate
-							}
-							// If can't convert, skip this price
-						}
-					}
-					// If can't convert, skip this price
-				}
-			}
+// AccountNetWorthHandler returns the net worth of current user, the balances of stock and cryptocurrency accounts are valued by the latest prices
+func (a *AccountsApi) AccountNetWorthHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[accounts.AccountNetWorthHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
 		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountNetWorthHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	// 4. Calculate valuation for each account
-	for _, account := range accountResps {
-		a.calculateSingleAccountValuation(account, defaultCurrency, exchangeBaseCurrency, exchangeRates, cryptoPricesInUSD, stockPricesInUSD)
+	valuator := a.GetAssetValuator(c, uid, user.DefaultCurrency, accounts, 0, 0)
+
+	netWorthResp := &models.NetWorthResponse{
+		Currency: user.DefaultCurrency,
+		Accounts: make([]*models.NetWorthAccountResponse, 0, len(accounts)),
 	}
-}
 
-func (a *AccountsApi) calculateSingleAccountValuation(account *models.AccountInfoResponse, defaultCurrency string, exchangeBaseCurrency string, exchangeRates map[string]float64, cryptoPricesInUSD map[string]float64, stockPricesInUSD map[string]float64) {
-	if account.AssetType == models.ACCOUNT_ASSET_TYPE_FIAT {
-		if account.Currency == defaultCurrency {
-			account.TotalBalance = account.Balance
-		} else {
-			rateSrc, okSrc := exchangeRates[account.Currency]
-			rateDst, okDst := exchangeRates[defaultCurrency]
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
 
-			if okSrc && okDst && rateSrc != 0 {
-				sourceFraction := a.getCurrencyFraction(account.Currency)
-				targetFraction := a.getCurrencyFraction(defaultCurrency)
-				account.TotalBalance = int64(float64(account.Balance) / rateSrc * rateDst / utils.Pow10(sourceFraction-targetFraction))
-			} else {
-				account.TotalBalance = 0
-			}
+		if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			continue
 		}
-	} else if account.AssetType == models.ACCOUNT_ASSET_TYPE_CRYPTO {
-		// Cryptocurrency: use price (in USD) to calculate USD value, then convert to default currency using exchange rate
-		priceInUSD, ok := cryptoPricesInUSD[account.Currency]
-		if !ok || priceInUSD <= 0 {
-			account.TotalBalance = 0
-		} else {
-			// Calculate USD value: quantity * price
-			sourceFraction := a.getCurrencyFraction(account.Currency)
-			quantity := float64(account.Balance) / utils.Pow10(sourceFraction)
-			usdValue := quantity * priceInUSD
-			
-			// Convert USD value to default currency
-			if defaultCurrency == "USD" {
-				targetFraction := a.getCurrencyFraction(defaultCurrency)
-				account.TotalBalance = int64(usdValue * utils.Pow10(targetFraction))
-			} else {
-				usdRate, okUSD := exchangeRates["USD"]
-				rateDst, okDst := exchangeRates[defaultCurrency]
-				
-				if okUSD && okDst && usdRate > 0 && rateDst > 0 {
-					targetFraction := a.getCurrencyFraction(defaultCurrency)
-					// Formula: Amount(B) = Amount(A) / Rate(A) * Rate(B)
-					// Here: Amount(defaultCurrency) = usdValue / Rate("USD") * Rate(defaultCurrency)
-					account.TotalBalance = int64(usdValue / usdRate * rateDst * utils.Pow10(targetFraction))
-				} else {
-					account.TotalBalance = 0
-				}
-			}
+
+		assetType := account.GetAssetType()
+		value, hasValue := valuator.GetValue(assetType, account.Currency, account.Balance, 0)
+
+		accountResp := &models.NetWorthAccountResponse{
+			AccountId: account.AccountId,
+			Currency:  account.Currency,
+			AssetType: assetType,
+			Balance:   account.Balance,
+			Value:     value,
+			HasValue:  hasValue,
 		}
-	} else if account.AssetType == models.ACCOUNT_ASSET_TYPE_STOCK {
-		// Stock: use price (in USD) to calculate USD value, then convert to default currency using exchange rate
-		priceInUSD, ok := stockPricesInUSD[account.Currency]
-		if !ok || priceInUSD <= 0 {
-			account.TotalBalance = 0
-		} else {
-			// Calculate USD value: quantity * price
-			sourceFraction := a.getCurrencyFraction(account.Currency)
-			quantity := float64(account.Balance) / utils.Pow10(sourceFraction)
-			usdValue := quantity * priceInUSD
-			
-			// Convert USD value to default currency
-			if defaultCurrency == "USD" {
-				targetFraction := a.getCurrencyFraction(defaultCurrency)
-				account.TotalBalance = int64(usdValue * utils.Pow10(targetFraction))
-			} else {
-				usdRate, okUSD := exchangeRates["USD"]
-				rateDst, okDst := exchangeRates[defaultCurrency]
-				
-				if okUSD && okDst && usdRate > 0 && rateDst > 0 {
-					targetFraction := a.getCurrencyFraction(defaultCurrency)
-					// Formula: Amount(B) = Amount(A) / Rate(A) * Rate(B)
-					// Here: Amount(defaultCurrency) = usdValue / Rate("USD") * Rate(defaultCurrency)
-					account.TotalBalance = int64(usdValue / usdRate * rateDst * utils.Pow10(targetFraction))
-				} else {
-					account.TotalBalance = 0
-				}
+
+		if assetType == models.ACCOUNT_ASSET_TYPE_STOCK || assetType == models.ACCOUNT_ASSET_TYPE_CRYPTO {
+			if price := valuator.GetPrice(assetType, account.Currency, 0); price != nil {
+				accountResp.Price = utils.Float64ToString(price.Price)
+				accountResp.PriceCurrency = price.Currency
 			}
 		}
-	} else {
-		account.TotalBalance = account.Balance
-	}
-
-	// Recurse for sub-accounts
-	for _, subAccount := range account.SubAccounts {
-		a.calculateSingleAccountValuation(subAccount, defaultCurrency, exchangeBaseCurrency, exchangeRates, cryptoPricesInUSD, stockPricesInUSD)
-	}
 
-	// If it's a multi-sub-accounts parent, the total balance should be the sum of sub-accounts
-	if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
-		account.TotalBalance = 0
-		for _, subAccount := range account.SubAccounts {
-			account.TotalBalance += subAccount.TotalBalance
+		if account.Category.IsLiability() {
+			netWorthResp.TotalLiabilities += value
+		} else {
+			netWorthResp.TotalAssets += value
 		}
-	}
-}
 
-func (a *AccountsApi) getCurrencyFraction(currency string) int {
-	if fraction, ok := commonCurrencyFractions[currency]; ok {
-		// For cryptocurrencies with fraction > 6, limit to 6 to avoid int64 overflow
-		// int64 max value: 9223372036854775807
-		// With fraction=8: can store up to ~92,233,720,368 BTC (more than enough)
-		// With fraction=18: can only store ~9.22 ETH (not enough)
-		if fraction > 8 {
-			return 8
-		}
-		return fraction
+		netWorthResp.NetWorth += value
+		netWorthResp.Accounts = append(netWorthResp.Accounts, accountResp)
 	}
-	return 2
-}
 
-var commonCurrencyFractions = map[string]int{
-	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
-	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
-	"BTC": 8, "ETH": 5, "BNB": 5, "SOL": 5, "ADA": 4, "XRP": 4, "DOT": 3, "DOGE": 2, "MATIC": 4, "USDT": 2, "USDC": 2, "DAI": 2, "LTC": 4, "BCH": 4, "LINK": 4, "XLM": 4, "UNI": 4, "ATOM": 4, "XMR": 4, "ETC": 4,
+	return netWorthResp, nil
 }
@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/avatars"
@@ -11,6 +12,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
@@ -103,6 +105,48 @@ func (a *ApiUsingConfig) GetAfterOpenNotificationContent(userLanguage string, cl
 	return a.CurrentConfig().AfterOpenNotification.DefaultContent
 }
 
+// IsAdministrator returns whether the user is one of the administrators in the config, all users are administrators if no administrator is configured
+func (a *ApiUsingConfig) IsAdministrator(user *models.User) bool {
+	if len(a.CurrentConfig().Administrators) < 1 {
+		return true
+	}
+
+	for _, username := range a.CurrentConfig().Administrators {
+		if strings.EqualFold(username, user.Username) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ApiUsingAdministrator represents an api that need to check whether the current user is an administrator
+type ApiUsingAdministrator struct {
+	ApiUsingConfig
+	users *services.UserService
+}
+
+// CheckCurrentUserIsAdministrator returns an error if the current user is not an administrator
+func (a *ApiUsingAdministrator) CheckCurrentUserIsAdministrator(c *core.WebContext) *errs.Error {
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[base.CheckCurrentUserIsAdministrator] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		}
+
+		return errs.ErrUserNotFound
+	}
+
+	if !a.IsAdministrator(user) {
+		log.Warnf(c, "[base.CheckCurrentUserIsAdministrator] user \"uid:%d\" is not an administrator", uid)
+		return errs.ErrNotPermittedToPerformThisAction
+	}
+
+	return nil
+}
+
 // ApiUsingDuplicateChecker represents an api that need to use duplicate checker
 type ApiUsingDuplicateChecker struct {
 	ApiUsingConfig
@@ -0,0 +1,375 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// BudgetsApi represents budget api
+type BudgetsApi struct {
+	ApiUsingConfig
+	ApiUsingMarketPrices
+	budgets      *services.BudgetService
+	transactions *services.TransactionService
+	categories   *services.TransactionCategoryService
+	accounts     *services.AccountService
+	users        *services.UserService
+}
+
+// Initialize a budget api singleton instance
+var (
+	Budgets = &BudgetsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingMarketPrices: apiUsingMarketPrices,
+		budgets:              services.Budgets,
+		transactions:         services.Transactions,
+		categories:           services.TransactionCategories,
+		accounts:             services.Accounts,
+		users:                services.Users,
+	}
+)
+
+// BudgetListHandler returns budget list of current user
+func (a *BudgetsApi) BudgetListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	budgets, err := a.budgets.GetAllBudgetsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetListHandler] failed to get budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budgetResps := make([]*models.BudgetInfoResponse, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		budgetResps[i] = budgets[i].ToBudgetInfoResponse()
+	}
+
+	return budgetResps, nil
+}
+
+// BudgetGetHandler returns one specific budget of current user
+func (a *BudgetsApi) BudgetGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetGetReq models.BudgetGetRequest
+	err := c.ShouldBindQuery(&budgetGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, budgetGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetGetHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return budget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetCreateHandler saves a new budget by request parameters for current user
+func (a *BudgetsApi) BudgetCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetCreateReq models.BudgetCreateRequest
+	err := c.ShouldBindJSON(&budgetCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	categoryIds, err := a.getBudgetCategoryIds(budgetCreateReq.CategoryIds)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] parse category ids failed, because %s", err.Error())
+		return nil, errs.ErrBudgetCategoryInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	maxOrderId, err := a.budgets.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	startUnixTime := budgetCreateReq.StartTime
+
+	if startUnixTime <= 0 {
+		startUnixTime = time.Now().Unix()
+	}
+
+	budget := &models.Budget{
+		Uid:           uid,
+		Name:          budgetCreateReq.Name,
+		PeriodType:    budgetCreateReq.PeriodType,
+		CategoryIds:   categoryIds,
+		Amount:        budgetCreateReq.Amount,
+		Rollover:      budgetCreateReq.Rollover,
+		StartUnixTime: startUnixTime,
+		DisplayOrder:  maxOrderId + 1,
+	}
+
+	err = a.budgets.CreateBudget(c, budget)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetCreateHandler] failed to create budget for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetCreateHandler] user \"uid:%d\" has created a new budget \"id:%d\" successfully", uid, budget.BudgetId)
+
+	return budget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetModifyHandler saves an existed budget by request parameters for current user
+func (a *BudgetsApi) BudgetModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetModifyReq models.BudgetModifyRequest
+	err := c.ShouldBindJSON(&budgetModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	categoryIds, err := a.getBudgetCategoryIds(budgetModifyReq.CategoryIds)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] parse category ids failed, because %s", err.Error())
+		return nil, errs.ErrBudgetCategoryInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, budgetModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetModifyHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budget.Name = budgetModifyReq.Name
+	budget.PeriodType = budgetModifyReq.PeriodType
+	budget.CategoryIds = categoryIds
+	budget.Amount = budgetModifyReq.Amount
+	budget.Rollover = budgetModifyReq.Rollover
+
+	if budgetModifyReq.StartTime > 0 {
+		budget.StartUnixTime = budgetModifyReq.StartTime
+	}
+
+	err = a.budgets.ModifyBudget(c, budget)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetModifyHandler] failed to update budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetModifyHandler] user \"uid:%d\" has updated budget \"id:%d\" successfully", uid, budgetModifyReq.Id)
+
+	return budget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetDeleteHandler deletes an existed budget by request parameters for current user
+func (a *BudgetsApi) BudgetDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetDeleteReq models.BudgetDeleteRequest
+	err := c.ShouldBindJSON(&budgetDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.budgets.DeleteBudget(c, uid, budgetDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetDeleteHandler] failed to delete budget \"id:%d\" for user \"uid:%d\", because %s", budgetDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetDeleteHandler] user \"uid:%d\" has deleted budget \"id:%d\"", uid, budgetDeleteReq.Id)
+	return true, nil
+}
+
+// BudgetProgressHandler returns the spending progress of one specific budget in the period which contains the specified time for current user
+func (a *BudgetsApi) BudgetProgressHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetProgressReq models.BudgetProgressRequest
+	err := c.ShouldBindQuery(&budgetProgressReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetProgressHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetProgressHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	noTags := budgetProgressReq.TagFilter == models.TransactionNoTagFilterValue
+	var tagFilters []*models.TransactionTagFilter
+
+	if !noTags {
+		tagFilters, err = models.ParseTransactionTagFilter(budgetProgressReq.TagFilter)
+
+		if err != nil {
+			log.Warnf(c, "[budgets.BudgetProgressHandler] parse transaction tag filters error, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	uid := c.GetCurrentUid()
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, budgetProgressReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetProgressReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allCategoryIds, err := a.categories.GetCategoryOrSubCategoryIds(c, budget.CategoryIds, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get categories of budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	categoryIds := make(map[int64]bool, len(allCategoryIds))
+
+	for i := 0; i < len(allCategoryIds); i++ {
+		categoryIds[allCategoryIds[i]] = true
+	}
+
+	progressUnixTime := budgetProgressReq.Time
+
+	if progressUnixTime <= 0 {
+		progressUnixTime = time.Now().Unix()
+	}
+
+	periodStartUnixTime, periodEndUnixTime, err := budget.GetPeriodTimeRange(progressUnixTime, user.FirstDayOfWeek, user.FiscalYearStart, clientTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get period of budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var previousPeriodStartUnixTimes []int64
+
+	if budget.Rollover {
+		previousPeriodStartUnixTimes, err = a.getBudgetPreviousPeriodStartUnixTimes(budget, user, periodStartUnixTime, clientTimezone)
+
+		if err != nil {
+			log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get previous periods of budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	// all the rolled-over periods and the current period are in ascending order
+	periodStartUnixTimes := make([]int64, 0, len(previousPeriodStartUnixTimes)+1)
+
+	for i := len(previousPeriodStartUnixTimes) - 1; i >= 0; i-- {
+		periodStartUnixTimes = append(periodStartUnixTimes, previousPeriodStartUnixTimes[i])
+	}
+
+	periodStartUnixTimes = append(periodStartUnixTimes, periodStartUnixTime)
+	amountConverter := a.GetTransactionAmountConverter(c, uid, user.DefaultCurrency, accounts, periodStartUnixTimes[0], periodEndUnixTime)
+	periodTotalAmounts, err := a.transactions.GetAccountsAndCategoriesPeriodicTotalInflowAndOutflow(c, uid, periodStartUnixTimes, periodEndUnixTime-1, tagFilters, noTags, budgetProgressReq.Keyword, clientTimezone, budgetProgressReq.UseTransactionTimezone, amountConverter)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get spent amount of budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	complete := true
+	rolloverAmount := int64(0)
+
+	for i := 0; i < len(periodTotalAmounts)-1; i++ {
+		spentAmount, periodComplete := models.GetBudgetSpentAmount(periodTotalAmounts[i], categoryIds)
+		complete = complete && periodComplete
+		rolloverAmount = models.GetBudgetRemainingAmountToRollover(budget.Amount+rolloverAmount, spentAmount)
+	}
+
+	spentAmount, periodComplete := models.GetBudgetSpentAmount(periodTotalAmounts[len(periodTotalAmounts)-1], categoryIds)
+	complete = complete && periodComplete
+	totalAmount := budget.Amount + rolloverAmount
+
+	return &models.BudgetProgressResponse{
+		Id:               budget.BudgetId,
+		PeriodStartTime:  periodStartUnixTime,
+		PeriodEndTime:    periodEndUnixTime,
+		Currency:         user.DefaultCurrency,
+		Amount:           budget.Amount,
+		RolloverAmount:   rolloverAmount,
+		TotalAmount:      totalAmount,
+		SpentAmount:      spentAmount,
+		RemainingAmount:  totalAmount - spentAmount,
+		IncompleteAmount: !complete,
+	}, nil
+}
+
+func (a *BudgetsApi) getBudgetCategoryIds(categoryIdStrings []string) (string, error) {
+	categoryIds, err := utils.StringArrayToInt64Array(categoryIdStrings)
+
+	if err != nil {
+		return "", err
+	}
+
+	categoryIds = utils.ToUniqueInt64Slice(categoryIds)
+
+	return strings.Join(utils.Int64ArrayToStringArray(categoryIds), ","), nil
+}
+
+// getBudgetPreviousPeriodStartUnixTimes returns the start unix times of the previous periods from the period which contains the start time of the budget,
+// the latest period is the first item and the count of periods would not exceed the maximum rollover periods
+func (a *BudgetsApi) getBudgetPreviousPeriodStartUnixTimes(budget *models.Budget, user *models.User, periodStartUnixTime int64, clientTimezone *time.Location) ([]int64, error) {
+	firstPeriodStartUnixTime, _, err := budget.GetPeriodTimeRange(budget.StartUnixTime, user.FirstDayOfWeek, user.FiscalYearStart, clientTimezone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	previousPeriodStartUnixTimes := make([]int64, 0)
+	currentPeriodStartUnixTime := periodStartUnixTime
+
+	for len(previousPeriodStartUnixTimes) < models.MaximumBudgetRolloverPeriods && currentPeriodStartUnixTime > firstPeriodStartUnixTime {
+		previousPeriodStartUnixTime, _, err := budget.GetPeriodTimeRange(currentPeriodStartUnixTime-1, user.FirstDayOfWeek, user.FiscalYearStart, clientTimezone)
+
+		if err != nil {
+			return nil, err
+		}
+
+		previousPeriodStartUnixTimes = append(previousPeriodStartUnixTimes, previousPeriodStartUnixTime)
+		currentPeriodStartUnixTime = previousPeriodStartUnixTime
+	}
+
+	return previousPeriodStartUnixTimes, nil
+}
@@ -1,42 +1,52 @@
 package api
 
 import (
+	"sort"
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/cryptocurrency"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // CryptocurrencyApi represents cryptocurrency api
 type CryptocurrencyApi struct {
-	ApiUsingConfig
-	externalDataSourceConfigs *services.ExternalDataSourceConfigService
-	cryptocurrencies          *services.CryptocurrencyService
+	ApiUsingAdministrator
+	externalDataSourceConfigs    *services.ExternalDataSourceConfigService
+	cryptocurrencies             *services.CryptocurrencyService
+	cryptocurrencyPriceHistories *services.CryptocurrencyPriceHistoryService
 }
 
 // Initialize a cryptocurrency api singleton instance
 var (
 	Cryptocurrencies = &CryptocurrencyApi{
-		ApiUsingConfig: ApiUsingConfig{
-			container: settings.Container,
+		ApiUsingAdministrator: ApiUsingAdministrator{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			users: services.Users,
 		},
-		externalDataSourceConfigs: services.ExternalDataSourceConfigs,
-		cryptocurrencies:          services.Cryptocurrencies,
+		externalDataSourceConfigs:    services.ExternalDataSourceConfigs,
+		cryptocurrencies:             services.Cryptocurrencies,
+		cryptocurrencyPriceHistories: services.CryptocurrencyPriceHistories,
 	}
 )
 
 // LatestCryptocurrencyPriceHandler returns latest cryptocurrency price data
 func (a *CryptocurrencyApi) LatestCryptocurrencyPriceHandler(c *core.WebContext) (any, *errs.Error) {
 	config, err := a.externalDataSourceConfigs.GetConfig(c, models.EXTERNAL_DATA_SOURCE_TYPE_CRYPTOCURRENCY)
-	
+
 	if err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	cryptos, err := a.cryptocurrencies.GetAllVisibleCryptocurrencies(c)
-	
+	cryptos, err := a.cryptocurrencies.GetAllVisibleCryptocurrencies(c, c.GetCurrentUid())
+
 	if err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
@@ -55,9 +65,74 @@ func (a *CryptocurrencyApi) LatestCryptocurrencyPriceHandler(c *core.WebContext)
 	return cryptocurrencyPriceResponse, nil
 }
 
-// CryptocurrencyListHandler returns cryptocurrency list
+// CryptocurrencyListHandler returns cryptocurrency list in the watchlist of current user
 func (a *CryptocurrencyApi) CryptocurrencyListHandler(c *core.WebContext) (any, *errs.Error) {
-	cryptos, err := a.cryptocurrencies.GetAllCryptocurrencies(c)
+	return a.getCryptocurrencyList(c, c.GetCurrentUid())
+}
+
+// CryptocurrencyAddHandler adds a new cryptocurrency to the watchlist of current user
+func (a *CryptocurrencyApi) CryptocurrencyAddHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.addCryptocurrency(c, c.GetCurrentUid())
+}
+
+// CryptocurrencyModifyHandler modifies a cryptocurrency in the watchlist of current user
+func (a *CryptocurrencyApi) CryptocurrencyModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.modifyCryptocurrency(c, c.GetCurrentUid())
+}
+
+// CryptocurrencyHideHandler hides or unhides a cryptocurrency in the watchlist of current user
+func (a *CryptocurrencyApi) CryptocurrencyHideHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.hideCryptocurrency(c, c.GetCurrentUid())
+}
+
+// CryptocurrencyDeleteHandler deletes a cryptocurrency from the watchlist of current user
+func (a *CryptocurrencyApi) CryptocurrencyDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.deleteCryptocurrency(c, c.GetCurrentUid())
+}
+
+// DefaultCryptocurrencyListHandler returns cryptocurrency list in the global default watchlist
+func (a *CryptocurrencyApi) DefaultCryptocurrencyListHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.getCryptocurrencyList(c, 0)
+}
+
+// DefaultCryptocurrencyAddHandler adds a new cryptocurrency to the global default watchlist, only administrators can perform this action
+func (a *CryptocurrencyApi) DefaultCryptocurrencyAddHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	return a.addCryptocurrency(c, 0)
+}
+
+// DefaultCryptocurrencyModifyHandler modifies a cryptocurrency in the global default watchlist, only administrators can perform this action
+func (a *CryptocurrencyApi) DefaultCryptocurrencyModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	return a.modifyCryptocurrency(c, 0)
+}
+
+// DefaultCryptocurrencyHideHandler hides or unhides a cryptocurrency in the global default watchlist, only administrators can perform this action
+func (a *CryptocurrencyApi) DefaultCryptocurrencyHideHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	return a.hideCryptocurrency(c, 0)
+}
+
+// DefaultCryptocurrencyDeleteHandler deletes a cryptocurrency from the global default watchlist, only administrators can perform this action
+func (a *CryptocurrencyApi) DefaultCryptocurrencyDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	return a.deleteCryptocurrency(c, 0)
+}
+
+func (a *CryptocurrencyApi) getCryptocurrencyList(c *core.WebContext, uid int64) (any, *errs.Error) {
+	cryptos, err := a.cryptocurrencies.GetAllCryptocurrencies(c, uid)
 	if err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
@@ -70,14 +145,14 @@ func (a *CryptocurrencyApi) CryptocurrencyListHandler(c *core.WebContext) (any,
 	return responses, nil
 }
 
-// CryptocurrencyAddHandler adds a new cryptocurrency
-func (a *CryptocurrencyApi) CryptocurrencyAddHandler(c *core.WebContext) (any, *errs.Error) {
+func (a *CryptocurrencyApi) addCryptocurrency(c *core.WebContext, uid int64) (any, *errs.Error) {
 	var req models.CryptocurrencyCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
 	crypto := &models.Cryptocurrency{
+		Uid:          uid,
 		Symbol:       req.Symbol,
 		Name:         req.Name,
 		DisplayOrder: req.DisplayOrder,
@@ -91,14 +166,14 @@ func (a *CryptocurrencyApi) CryptocurrencyAddHandler(c *core.WebContext) (any, *
 	return crypto.ToCryptocurrencyInfoResponse(), nil
 }
 
-// CryptocurrencyModifyHandler modifies a cryptocurrency
-func (a *CryptocurrencyApi) CryptocurrencyModifyHandler(c *core.WebContext) (any, *errs.Error) {
+func (a *CryptocurrencyApi) modifyCryptocurrency(c *core.WebContext, uid int64) (any, *errs.Error) {
 	var req models.CryptocurrencyModifyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
 	crypto := &models.Cryptocurrency{
+		Uid:          uid,
 		Symbol:       req.Symbol,
 		Name:         req.Name,
 		IsHidden:     req.IsHidden,
@@ -112,28 +187,26 @@ func (a *CryptocurrencyApi) CryptocurrencyModifyHandler(c *core.WebContext) (any
 	return crypto.ToCryptocurrencyInfoResponse(), nil
 }
 
-// CryptocurrencyHideHandler hides or unhides a cryptocurrency
-func (a *CryptocurrencyApi) CryptocurrencyHideHandler(c *core.WebContext) (any, *errs.Error) {
+func (a *CryptocurrencyApi) hideCryptocurrency(c *core.WebContext, uid int64) (any, *errs.Error) {
 	var req models.CryptocurrencyHideRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	if err := a.cryptocurrencies.HideCryptocurrency(c, req.Symbol, req.Hidden); err != nil {
+	if err := a.cryptocurrencies.HideCryptocurrency(c, uid, req.Symbol, req.Hidden); err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
 	return true, nil
 }
 
-// CryptocurrencyDeleteHandler deletes a cryptocurrency
-func (a *CryptocurrencyApi) CryptocurrencyDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+func (a *CryptocurrencyApi) deleteCryptocurrency(c *core.WebContext, uid int64) (any, *errs.Error) {
 	var req models.CryptocurrencyDeleteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	if err := a.cryptocurrencies.DeleteCryptocurrency(c, req.Symbol); err != nil {
+	if err := a.cryptocurrencies.DeleteCryptocurrency(c, uid, req.Symbol); err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
@@ -181,3 +254,43 @@ func (a *CryptocurrencyApi) CryptocurrencyConfigSaveHandler(c *core.WebContext)
 
 	return config.ToExternalDataSourceConfigResponse(), nil
 }
+
+// CryptocurrencyPriceHistoryHandler returns the daily price history of a cryptocurrency
+func (a *CryptocurrencyApi) CryptocurrencyPriceHistoryHandler(c *core.WebContext) (any, *errs.Error) {
+	var req models.CryptocurrencyPriceHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Warnf(c, "[cryptocurrency.CryptocurrencyPriceHistoryHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if req.StartTime > 0 && req.EndTime > 0 && req.StartTime > req.EndTime {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	startDate := int32(0)
+	endDate := int32(0)
+
+	if req.StartTime > 0 {
+		startDate = utils.FormatUnixTimeToNumericYearMonthDay(req.StartTime, time.UTC)
+	}
+
+	if req.EndTime > 0 {
+		endDate = utils.FormatUnixTimeToNumericYearMonthDay(req.EndTime, time.UTC)
+	}
+
+	histories, err := a.cryptocurrencyPriceHistories.GetPriceHistories(c, req.Symbol, req.Currency, req.DataSource, startDate, endDate)
+
+	if err != nil {
+		log.Errorf(c, "[cryptocurrency.CryptocurrencyPriceHistoryHandler] failed to get price history of \"symbol:%s\", because %s", req.Symbol, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	responses := make(models.CryptocurrencyPriceHistoryResponseSlice, len(histories))
+	for i, history := range histories {
+		responses[i] = history.ToCryptocurrencyPriceHistoryResponse()
+	}
+
+	sort.Sort(responses)
+
+	return responses, nil
+}
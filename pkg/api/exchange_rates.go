@@ -1,6 +1,9 @@
 package api
 
 import (
+	"sort"
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/cryptocurrency"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -10,6 +13,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 	"github.com/mayswind/ezbookkeeping/pkg/stocks"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // ExchangeRatesApi represents exchange rate api
@@ -17,6 +21,7 @@ type ExchangeRatesApi struct {
 	ApiUsingConfig
 	users                   *services.UserService
 	userCustomExchangeRates *services.UserCustomExchangeRatesService
+	exchangeRateHistories   *services.ExchangeRateHistoryService
 	cryptocurrency          *cryptocurrency.CryptocurrencyPriceDataProviderContainer
 	stocks                  *stocks.StockPriceDataProviderContainer
 }
@@ -29,14 +34,29 @@ var (
 		},
 		users:                   services.Users,
 		userCustomExchangeRates: services.UserCustomExchangeRates,
+		exchangeRateHistories:   services.ExchangeRateHistories,
 		cryptocurrency:          cryptocurrency.Container,
 		stocks:                  stocks.Container,
 	}
 )
 
-// LatestExchangeRateHandler returns latest exchange rate data
+// LatestExchangeRateHandler returns latest exchange rate data, or the user custom exchange rate data effective at the specified time
 func (a *ExchangeRatesApi) LatestExchangeRateHandler(c *core.WebContext) (any, *errs.Error) {
-	exchangeRateResponse, err := exchangerates.Container.GetLatestExchangeRates(c, c.GetCurrentUid(), a.CurrentConfig())
+	var req models.LatestExchangeRateRequest
+	err := c.ShouldBindQuery(&req)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.LatestExchangeRateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	var exchangeRateResponse *models.LatestExchangeRateResponse
+
+	if req.Time > 0 {
+		exchangeRateResponse, err = exchangerates.Container.GetUserCustomExchangeRatesAtTime(c, c.GetCurrentUid(), req.Time, a.CurrentConfig())
+	} else {
+		exchangeRateResponse, err = exchangerates.Container.GetLatestExchangeRates(c, c.GetCurrentUid(), a.CurrentConfig())
+	}
 
 	if err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
@@ -45,6 +65,61 @@ func (a *ExchangeRatesApi) LatestExchangeRateHandler(c *core.WebContext) (any, *
 	return exchangeRateResponse, nil
 }
 
+// ExchangeRateHistoryHandler returns the daily exchange rate history, the current exchange rates data source would be used if the data source is not specified
+func (a *ExchangeRatesApi) ExchangeRateHistoryHandler(c *core.WebContext) (any, *errs.Error) {
+	var req models.ExchangeRateHistoryRequest
+	err := c.ShouldBindQuery(&req)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.ExchangeRateHistoryHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if req.StartTime > 0 && req.EndTime > 0 && req.StartTime > req.EndTime {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	dataSource := req.DataSource
+
+	if dataSource == "" {
+		exchangeRateResponse, err := exchangerates.Container.GetLatestExchangeRates(c, c.GetCurrentUid(), a.CurrentConfig())
+
+		if err != nil {
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		dataSource = exchangeRateResponse.DataSource
+	}
+
+	startDate := int32(0)
+	endDate := int32(0)
+
+	if req.StartTime > 0 {
+		startDate = utils.FormatUnixTimeToNumericYearMonthDay(req.StartTime, time.UTC)
+	}
+
+	if req.EndTime > 0 {
+		endDate = utils.FormatUnixTimeToNumericYearMonthDay(req.EndTime, time.UTC)
+	}
+
+	histories, err := a.exchangeRateHistories.GetExchangeRateHistories(c, dataSource, req.Currency, startDate, endDate)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.ExchangeRateHistoryHandler] failed to get exchange rate history of \"currency:%s\" from data source \"%s\", because %s", req.Currency, dataSource, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	responses := make(models.ExchangeRateHistoryResponseSlice, len(histories))
+
+	for i := 0; i < len(histories); i++ {
+		responses[i] = histories[i].ToExchangeRateHistoryResponse()
+	}
+
+	sort.Sort(responses)
+
+	return responses, nil
+}
+
 // UserCustomExchangeRateUpdateHandler updates user custom exchange rates data by request parameters for current user
 func (a *ExchangeRatesApi) UserCustomExchangeRateUpdateHandler(c *core.WebContext) (any, *errs.Error) {
 	var customExchangeRateUpdateReq models.UserCustomExchangeRateUpdateRequest
@@ -110,3 +185,94 @@ func (a *ExchangeRatesApi) UserCustomExchangeRateDeleteHandler(c *core.WebContex
 	log.Infof(c, "[exchange_rates.UserCustomExchangeRateDeleteHandler] user \"uid:%d\" has deleted user custom exchange rate \"currency:%s\"", uid, customExchangeRateDeleteReq.Currency)
 	return true, nil
 }
+
+// UserCustomDatedExchangeRateListHandler returns user custom dated exchange rate list of current user
+func (a *ExchangeRatesApi) UserCustomDatedExchangeRateListHandler(c *core.WebContext) (any, *errs.Error) {
+	var datedExchangeRateListReq models.UserCustomDatedExchangeRateListRequest
+	err := c.ShouldBindQuery(&datedExchangeRateListReq)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.UserCustomDatedExchangeRateListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	datedExchangeRates, err := a.userCustomExchangeRates.GetAllDatedExchangeRatesByUid(c, uid, datedExchangeRateListReq.Currency)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.UserCustomDatedExchangeRateListHandler] failed to get user custom dated exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	responses := make([]*models.UserCustomDatedExchangeRateInfoResponse, len(datedExchangeRates))
+
+	for i := 0; i < len(datedExchangeRates); i++ {
+		responses[i] = datedExchangeRates[i].ToUserCustomDatedExchangeRateInfoResponse()
+	}
+
+	return responses, nil
+}
+
+// UserCustomDatedExchangeRateCreateHandler saves a new user custom dated exchange rate by request parameters for current user
+func (a *ExchangeRatesApi) UserCustomDatedExchangeRateCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var datedExchangeRateCreateReq models.UserCustomDatedExchangeRateCreateRequest
+	err := c.ShouldBindJSON(&datedExchangeRateCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.UserCustomDatedExchangeRateCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.UserCustomDatedExchangeRateCreateHandler] failed to get user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if datedExchangeRateCreateReq.Currency == user.DefaultCurrency {
+		return nil, errs.ErrCannotUpdateExchangeRateForDefaultCurrency
+	}
+
+	datedExchangeRate := &models.UserCustomDatedExchangeRate{
+		Uid:                uid,
+		Currency:           datedExchangeRateCreateReq.Currency,
+		BaseCurrency:       user.DefaultCurrency,
+		Rate:               datedExchangeRateCreateReq.Rate,
+		EffectiveStartTime: datedExchangeRateCreateReq.EffectiveStartTime,
+		EffectiveEndTime:   datedExchangeRateCreateReq.EffectiveEndTime,
+	}
+
+	err = a.userCustomExchangeRates.CreateDatedExchangeRate(c, datedExchangeRate)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.UserCustomDatedExchangeRateCreateHandler] failed to create user custom dated exchange rate \"currency:%s\" for user \"uid:%d\", because %s", datedExchangeRateCreateReq.Currency, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[exchange_rates.UserCustomDatedExchangeRateCreateHandler] user \"uid:%d\" has created a new user custom dated exchange rate \"id:%d\" successfully", uid, datedExchangeRate.RateId)
+	return datedExchangeRate.ToUserCustomDatedExchangeRateInfoResponse(), nil
+}
+
+// UserCustomDatedExchangeRateDeleteHandler deletes an existed user custom dated exchange rate by request parameters for current user
+func (a *ExchangeRatesApi) UserCustomDatedExchangeRateDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var datedExchangeRateDeleteReq models.UserCustomDatedExchangeRateDeleteRequest
+	err := c.ShouldBindJSON(&datedExchangeRateDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.UserCustomDatedExchangeRateDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.userCustomExchangeRates.DeleteDatedExchangeRate(c, uid, datedExchangeRateDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.UserCustomDatedExchangeRateDeleteHandler] failed to delete user custom dated exchange rate \"id:%d\" for user \"uid:%d\", because %s", datedExchangeRateDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[exchange_rates.UserCustomDatedExchangeRateDeleteHandler] user \"uid:%d\" has deleted user custom dated exchange rate \"id:%d\"", uid, datedExchangeRateDeleteReq.Id)
+	return true, nil
+}
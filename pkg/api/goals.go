@@ -0,0 +1,249 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// GoalsApi represents savings goal api
+type GoalsApi struct {
+	ApiUsingConfig
+	ApiUsingMarketPrices
+	goals    *services.GoalService
+	accounts *services.AccountService
+}
+
+// Initialize a savings goal api singleton instance
+var (
+	Goals = &GoalsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingMarketPrices: apiUsingMarketPrices,
+		goals:                services.Goals,
+		accounts:             services.Accounts,
+	}
+)
+
+// GoalListHandler returns savings goal list of current user
+func (a *GoalsApi) GoalListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	goals, err := a.goals.GetAllGoalsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[goals.GoalListHandler] failed to get savings goals for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	goalResps := make([]*models.GoalInfoResponse, len(goals))
+
+	for i := 0; i < len(goals); i++ {
+		goalResps[i] = goals[i].ToGoalInfoResponse()
+	}
+
+	return goalResps, nil
+}
+
+// GoalGetHandler returns one specific savings goal of current user
+func (a *GoalsApi) GoalGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var goalGetReq models.GoalGetRequest
+	err := c.ShouldBindQuery(&goalGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[goals.GoalGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	goal, err := a.goals.GetGoalByGoalId(c, uid, goalGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[goals.GoalGetHandler] failed to get savings goal \"id:%d\" for user \"uid:%d\", because %s", goalGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return goal.ToGoalInfoResponse(), nil
+}
+
+// GoalCreateHandler saves a new savings goal by request parameters for current user
+func (a *GoalsApi) GoalCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var goalCreateReq models.GoalCreateRequest
+	err := c.ShouldBindJSON(&goalCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[goals.GoalCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	accountIds, err := a.getGoalAccountIds(goalCreateReq.AccountIds)
+
+	if err != nil {
+		log.Warnf(c, "[goals.GoalCreateHandler] parse account ids failed, because %s", err.Error())
+		return nil, errs.ErrGoalAccountInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	maxOrderId, err := a.goals.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[goals.GoalCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	goal := &models.Goal{
+		Uid:            uid,
+		Name:           goalCreateReq.Name,
+		AccountIds:     accountIds,
+		Currency:       goalCreateReq.Currency,
+		TargetAmount:   goalCreateReq.TargetAmount,
+		TargetUnixTime: goalCreateReq.TargetTime,
+		DisplayOrder:   maxOrderId + 1,
+	}
+
+	err = a.goals.CreateGoal(c, goal)
+
+	if err != nil {
+		log.Errorf(c, "[goals.GoalCreateHandler] failed to create savings goal for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[goals.GoalCreateHandler] user \"uid:%d\" has created a new savings goal \"id:%d\" successfully", uid, goal.GoalId)
+
+	return goal.ToGoalInfoResponse(), nil
+}
+
+// GoalModifyHandler saves an existed savings goal by request parameters for current user
+func (a *GoalsApi) GoalModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var goalModifyReq models.GoalModifyRequest
+	err := c.ShouldBindJSON(&goalModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[goals.GoalModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	accountIds, err := a.getGoalAccountIds(goalModifyReq.AccountIds)
+
+	if err != nil {
+		log.Warnf(c, "[goals.GoalModifyHandler] parse account ids failed, because %s", err.Error())
+		return nil, errs.ErrGoalAccountInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	goal, err := a.goals.GetGoalByGoalId(c, uid, goalModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[goals.GoalModifyHandler] failed to get savings goal \"id:%d\" for user \"uid:%d\", because %s", goalModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	goal.Name = goalModifyReq.Name
+	goal.AccountIds = accountIds
+	goal.Currency = goalModifyReq.Currency
+	goal.TargetAmount = goalModifyReq.TargetAmount
+	goal.TargetUnixTime = goalModifyReq.TargetTime
+
+	err = a.goals.ModifyGoal(c, goal)
+
+	if err != nil {
+		log.Errorf(c, "[goals.GoalModifyHandler] failed to update savings goal \"id:%d\" for user \"uid:%d\", because %s", goalModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[goals.GoalModifyHandler] user \"uid:%d\" has updated savings goal \"id:%d\" successfully", uid, goalModifyReq.Id)
+
+	return goal.ToGoalInfoResponse(), nil
+}
+
+// GoalDeleteHandler deletes an existed savings goal by request parameters for current user
+func (a *GoalsApi) GoalDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var goalDeleteReq models.GoalDeleteRequest
+	err := c.ShouldBindJSON(&goalDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[goals.GoalDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.goals.DeleteGoal(c, uid, goalDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[goals.GoalDeleteHandler] failed to delete savings goal \"id:%d\" for user \"uid:%d\", because %s", goalDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[goals.GoalDeleteHandler] user \"uid:%d\" has deleted savings goal \"id:%d\"", uid, goalDeleteReq.Id)
+	return true, nil
+}
+
+// GoalProgressHandler returns the progress, the required monthly contribution and the projected completion time of one specific savings goal for current user
+func (a *GoalsApi) GoalProgressHandler(c *core.WebContext) (any, *errs.Error) {
+	var goalGetReq models.GoalGetRequest
+	err := c.ShouldBindQuery(&goalGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[goals.GoalProgressHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[goals.GoalProgressHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	goal, err := a.goals.GetGoalByGoalId(c, uid, goalGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[goals.GoalProgressHandler] failed to get savings goal \"id:%d\" for user \"uid:%d\", because %s", goalGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[goals.GoalProgressHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	now := time.Now()
+	monthEndBalances, err := a.goals.GetGoalMonthEndBalances(c, uid, accounts, now, clientTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[goals.GoalProgressHandler] failed to get month-end account balances for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	valuator := a.GetAssetValuator(c, uid, goal.Currency, accounts, models.GetGoalBalanceHistoryStartTime(now, clientTimezone).Unix(), now.Unix())
+	progress, err := a.goals.GetGoalProgress(c, goal, accounts, monthEndBalances, valuator, now, clientTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[goals.GoalProgressHandler] failed to get progress of savings goal \"id:%d\" for user \"uid:%d\", because %s", goal.GoalId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return progress, nil
+}
+
+func (a *GoalsApi) getGoalAccountIds(accountIdStrings []string) (string, error) {
+	accountIds, err := utils.StringArrayToInt64Array(accountIdStrings)
+
+	if err != nil {
+		return "", err
+	}
+
+	accountIds = utils.ToUniqueInt64Slice(accountIds)
+
+	return strings.Join(utils.Int64ArrayToStringArray(accountIds), ","), nil
+}
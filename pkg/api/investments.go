@@ -0,0 +1,643 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// InvestmentsApi represents investment api
+type InvestmentsApi struct {
+	ApiUsingConfig
+	ApiUsingMarketPrices
+	accounts      *services.AccountService
+	users         *services.UserService
+	trades        *services.InvestmentTradeService
+	realizedGains *services.InvestmentRealizedGainService
+	performances  *services.InvestmentPerformanceService
+}
+
+// Initialize an investment api singleton instance
+var (
+	Investments = &InvestmentsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingMarketPrices: apiUsingMarketPrices,
+		accounts:             services.Accounts,
+		users:                services.Users,
+		trades:               services.InvestmentTrades,
+		realizedGains:        services.InvestmentRealizedGains,
+		performances:         services.InvestmentPerformances,
+	}
+)
+
+// TradeListHandler returns investment trade list of specified account of current user
+func (a *InvestmentsApi) TradeListHandler(c *core.WebContext) (any, *errs.Error) {
+	var tradeListReq models.InvestmentTradeListRequest
+	err := c.ShouldBindQuery(&tradeListReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.TradeListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	trades, err := a.trades.GetAllTradesByAccountIds(c, uid, []int64{tradeListReq.AccountId})
+
+	if err != nil {
+		log.Errorf(c, "[investments.TradeListHandler] failed to get trades of account \"id:%d\" for user \"uid:%d\", because %s", tradeListReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tradeResps := make(models.InvestmentTradeInfoResponseSlice, len(trades))
+
+	for i := 0; i < len(trades); i++ {
+		tradeResps[i] = trades[i].ToInvestmentTradeInfoResponse()
+	}
+
+	sort.Sort(tradeResps)
+
+	return tradeResps, nil
+}
+
+// TradeCreateHandler saves a new investment trade by request parameters for current user
+func (a *InvestmentsApi) TradeCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var tradeCreateReq models.InvestmentTradeCreateRequest
+	err := c.ShouldBindJSON(&tradeCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.TradeCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if tradeCreateReq.Type < models.INVESTMENT_TRADE_TYPE_BUY || tradeCreateReq.Type > models.INVESTMENT_TRADE_TYPE_SPLIT {
+		log.Warnf(c, "[investments.TradeCreateHandler] trade type invalid, type is %d", tradeCreateReq.Type)
+		return nil, errs.ErrInvestmentTradeTypeInvalid
+	}
+
+	uid := c.GetCurrentUid()
+
+	trade := &models.InvestmentTrade{
+		Uid:              uid,
+		AccountId:        tradeCreateReq.AccountId,
+		TradeTime:        tradeCreateReq.Time,
+		Type:             tradeCreateReq.Type,
+		Quantity:         tradeCreateReq.Quantity,
+		Amount:           tradeCreateReq.Amount,
+		Fee:              tradeCreateReq.Fee,
+		Currency:         tradeCreateReq.Currency,
+		SplitNumerator:   tradeCreateReq.SplitNumerator,
+		SplitDenominator: tradeCreateReq.SplitDenominator,
+		TransactionId:    tradeCreateReq.TransactionId,
+		Comment:          tradeCreateReq.Comment,
+	}
+
+	err = a.trades.CreateTrade(c, trade)
+
+	if err != nil {
+		log.Errorf(c, "[investments.TradeCreateHandler] failed to create trade for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.TradeCreateHandler] user \"uid:%d\" has created a new trade \"id:%d\" successfully", uid, trade.TradeId)
+
+	return trade.ToInvestmentTradeInfoResponse(), nil
+}
+
+// TradeDeleteHandler deletes an existed investment trade by request parameters for current user
+func (a *InvestmentsApi) TradeDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var tradeDeleteReq models.InvestmentTradeDeleteRequest
+	err := c.ShouldBindJSON(&tradeDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.TradeDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.trades.DeleteTrade(c, uid, tradeDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[investments.TradeDeleteHandler] failed to delete trade \"id:%d\" for user \"uid:%d\", because %s", tradeDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.TradeDeleteHandler] user \"uid:%d\" has deleted trade \"id:%d\"", uid, tradeDeleteReq.Id)
+	return true, nil
+}
+
+// HoldingListHandler returns the holdings of investment accounts of current user
+func (a *InvestmentsApi) HoldingListHandler(c *core.WebContext) (any, *errs.Error) {
+	var holdingListReq models.InvestmentHoldingListRequest
+	err := c.ShouldBindQuery(&holdingListReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.HoldingListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	lotMatchingMethod, err := models.ParseInvestmentLotMatchingMethod(holdingListReq.LotMatching)
+
+	if err != nil {
+		log.Warnf(c, "[investments.HoldingListHandler] lot matching method invalid, method is \"%s\"", holdingListReq.LotMatching)
+		return nil, errs.Or(err, errs.ErrInvestmentLotMatchingMethodInvalid)
+	}
+
+	uid := c.GetCurrentUid()
+	accounts, err := a.getInvestmentAccounts(c, uid, holdingListReq.AccountIds)
+
+	if err != nil {
+		log.Errorf(c, "[investments.HoldingListHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	holdingResps := make([]*models.InvestmentHoldingInfoResponse, 0, len(accounts))
+
+	if len(accounts) < 1 {
+		return holdingResps, nil
+	}
+
+	accountIds := make([]int64, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		accountIds[i] = accounts[i].AccountId
+	}
+
+	trades, err := a.trades.GetAllTradesByAccountIds(c, uid, accountIds)
+
+	if err != nil {
+		log.Errorf(c, "[investments.HoldingListHandler] failed to get trades for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountTrades := make(map[int64][]*models.InvestmentTrade, len(accounts))
+
+	for i := 0; i < len(trades); i++ {
+		accountTrades[trades[i].AccountId] = append(accountTrades[trades[i].AccountId], trades[i])
+	}
+
+	marketPrices := a.GetLatestMarketPrices(c, uid, accounts)
+	exchangeRates := a.GetLatestExchangeRates(c, uid)
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		position, err := a.trades.CalculatePosition(accountTrades[account.AccountId], lotMatchingMethod)
+
+		if err != nil {
+			log.Errorf(c, "[investments.HoldingListHandler] failed to calculate position of account \"id:%d\" for user \"uid:%d\", because %s", account.AccountId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		holdingResps = append(holdingResps, a.getHoldingResponse(account, position, marketPrices[account.Extend.AssetType][strings.ToUpper(account.Currency)], exchangeRates))
+	}
+
+	return holdingResps, nil
+}
+
+// RealizedGainListHandler returns the realized gains of investment accounts in specified fiscal year of current user
+func (a *InvestmentsApi) RealizedGainListHandler(c *core.WebContext) (any, *errs.Error) {
+	report, _, _, err := a.getRealizedGainReport(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// RealizedGainExportHandler returns the realized gains of investment accounts in specified fiscal year of current user in csv format
+func (a *InvestmentsApi) RealizedGainExportHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	report, accounts, user, err := a.getRealizedGainReport(c)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	clientTimezone, err2 := c.GetClientTimezone()
+
+	if err2 != nil {
+		log.Warnf(c, "[investments.RealizedGainExportHandler] cannot get client timezone, because %s", err2.Error())
+		clientTimezone = time.Local
+	}
+
+	accountMap := a.accounts.GetAccountMapByList(accounts)
+	result, err2 := a.getRealizedGainCsvContent(report, accountMap, clientTimezone)
+
+	if err2 != nil {
+		log.Errorf(c, "[investments.RealizedGainExportHandler] failed to write csv content for user \"uid:%d\", because %s", user.Uid, err2.Error())
+		return nil, "", errs.ErrOperationFailed
+	}
+
+	fileName := fmt.Sprintf("%s_realized_gains_%d.csv", user.Username, report.FiscalYear)
+
+	return result, fileName, nil
+}
+
+// PerformanceHandler returns the time-weighted and money-weighted returns of investment accounts, account groups and the whole portfolio of current user
+func (a *InvestmentsApi) PerformanceHandler(c *core.WebContext) (any, *errs.Error) {
+	var performanceReq models.InvestmentPerformanceRequest
+	err := c.ShouldBindQuery(&performanceReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.PerformanceHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	endTime := performanceReq.EndTime
+
+	if endTime <= 0 {
+		endTime = time.Now().Unix()
+	}
+
+	if performanceReq.StartTime > endTime {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[investments.PerformanceHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[investments.PerformanceHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	accounts, err := a.getInvestmentAccounts(c, uid, performanceReq.AccountIds)
+
+	if err != nil {
+		log.Errorf(c, "[investments.PerformanceHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	performanceResp := &models.InvestmentPerformanceResponse{
+		Currency:  user.DefaultCurrency,
+		StartTime: performanceReq.StartTime,
+		EndTime:   endTime,
+		Groups:    make([]*models.InvestmentPerformanceInfoResponse, 0),
+		Accounts:  make([]*models.InvestmentPerformanceInfoResponse, 0, len(accounts)),
+	}
+
+	if len(accounts) < 1 {
+		performanceResp.Portfolio = a.getPerformanceResponse(0, nil, false)
+		return performanceResp, nil
+	}
+
+	valuator := a.GetAssetValuator(c, uid, user.DefaultCurrency, accounts, performanceReq.StartTime, endTime)
+	accountPoints, err := a.performances.GetAccountsValuationPoints(c, uid, accounts, performanceReq.StartTime, endTime, valuator, clientTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[investments.PerformanceHandler] failed to get valuation points for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allPoints := make([][]*models.InvestmentValuationPoint, 0, len(accounts))
+	groupIds := make([]int64, 0)
+	groupPoints := make(map[int64][][]*models.InvestmentValuationPoint)
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		points := accountPoints[account.AccountId]
+
+		allPoints = append(allPoints, points)
+		performanceResp.Accounts = append(performanceResp.Accounts, a.getPerformanceResponse(account.AccountId, points, false))
+
+		if account.ParentAccountId > 0 {
+			if _, exists := groupPoints[account.ParentAccountId]; !exists {
+				groupIds = append(groupIds, account.ParentAccountId)
+			}
+
+			groupPoints[account.ParentAccountId] = append(groupPoints[account.ParentAccountId], points)
+		}
+	}
+
+	for i := 0; i < len(groupIds); i++ {
+		mergedPoints, incomplete := a.performances.MergeValuationPoints(groupPoints[groupIds[i]])
+		performanceResp.Groups = append(performanceResp.Groups, a.getPerformanceResponse(groupIds[i], mergedPoints, incomplete))
+	}
+
+	mergedPoints, incomplete := a.performances.MergeValuationPoints(allPoints)
+	performanceResp.Portfolio = a.getPerformanceResponse(0, mergedPoints, incomplete)
+
+	return performanceResp, nil
+}
+
+func (a *InvestmentsApi) getRealizedGainReport(c *core.WebContext) (*models.InvestmentRealizedGainReportResponse, []*models.Account, *models.User, *errs.Error) {
+	var realizedGainListReq models.InvestmentRealizedGainListRequest
+	err := c.ShouldBindQuery(&realizedGainListReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.getRealizedGainReport] parse request failed, because %s", err.Error())
+		return nil, nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	lotMatchingMethod, err := models.ParseInvestmentLotMatchingMethod(realizedGainListReq.LotMatching)
+
+	if err != nil {
+		log.Warnf(c, "[investments.getRealizedGainReport] lot matching method invalid, method is \"%s\"", realizedGainListReq.LotMatching)
+		return nil, nil, nil, errs.Or(err, errs.ErrInvestmentLotMatchingMethodInvalid)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[investments.getRealizedGainReport] cannot get client timezone, because %s", err.Error())
+		clientTimezone = time.Local
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[investments.getRealizedGainReport] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, nil, nil, errs.ErrUserNotFound
+	}
+
+	fiscalYearStart := user.FiscalYearStart
+
+	if fiscalYearStart < core.FISCAL_YEAR_START_MIN || fiscalYearStart > core.FISCAL_YEAR_START_MAX {
+		fiscalYearStart = core.FISCAL_YEAR_START_DEFAULT
+	}
+
+	startTime, endTime, err := fiscalYearStart.GetFiscalYearTimeRange(realizedGainListReq.FiscalYear, clientTimezone)
+
+	if err != nil {
+		log.Warnf(c, "[investments.getRealizedGainReport] failed to get time range of fiscal year %d for user \"uid:%d\", because %s", realizedGainListReq.FiscalYear, uid, err.Error())
+		return nil, nil, nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accounts, err := a.getInvestmentAccounts(c, uid, realizedGainListReq.AccountIds)
+
+	if err != nil {
+		log.Errorf(c, "[investments.getRealizedGainReport] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountIds := make([]int64, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		accountIds[i] = accounts[i].AccountId
+	}
+
+	gains, err := a.realizedGains.GetRealizedGains(c, uid, accountIds, startTime, endTime, lotMatchingMethod)
+
+	if err != nil {
+		log.Errorf(c, "[investments.getRealizedGainReport] failed to get realized gains for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	report := &models.InvestmentRealizedGainReportResponse{
+		FiscalYear: realizedGainListReq.FiscalYear,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Gains:      make([]*models.InvestmentRealizedGainInfoResponse, len(gains)),
+		Summaries:  make([]*models.InvestmentRealizedGainSummaryResponse, 0),
+	}
+
+	summaryMap := make(map[string]*models.InvestmentRealizedGainSummaryResponse)
+
+	for i := 0; i < len(gains); i++ {
+		gainResp := gains[i].ToInvestmentRealizedGainInfoResponse()
+		report.Gains[i] = gainResp
+
+		summary, exists := summaryMap[gainResp.Currency]
+
+		if !exists {
+			summary = &models.InvestmentRealizedGainSummaryResponse{
+				Currency: gainResp.Currency,
+			}
+			summaryMap[gainResp.Currency] = summary
+			report.Summaries = append(report.Summaries, summary)
+		}
+
+		if gainResp.HoldingPeriod == models.INVESTMENT_HOLDING_PERIOD_LONG_TERM {
+			summary.LongTermGain += gainResp.Gain
+		} else {
+			summary.ShortTermGain += gainResp.Gain
+		}
+
+		summary.TotalProceeds += gainResp.Proceeds
+		summary.TotalCostBasis += gainResp.CostBasis
+		summary.TotalGain += gainResp.Gain
+	}
+
+	return report, accounts, user, nil
+}
+
+func (a *InvestmentsApi) getRealizedGainCsvContent(report *models.InvestmentRealizedGainReportResponse, accountMap map[int64]*models.Account, timezone *time.Location) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	err := writer.Write([]string{"Account", "Symbol", "Currency", "Acquired Date", "Sold Date", "Quantity", "Cost Basis", "Proceeds", "Gain", "Holding Period"})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(report.Gains); i++ {
+		gain := report.Gains[i]
+		accountName := ""
+
+		if account, exists := accountMap[gain.AccountId]; exists {
+			accountName = account.Name
+		}
+
+		costFraction := models.GetCurrencyFraction(gain.Currency)
+
+		err = writer.Write([]string{
+			accountName,
+			gain.Symbol,
+			gain.Currency,
+			utils.FormatUnixTimeToLongDate(gain.AcquiredTime, timezone),
+			utils.FormatUnixTimeToLongDate(gain.SoldTime, timezone),
+			formatInvestmentAmount(gain.Quantity, models.GetCurrencyFraction(gain.Symbol)),
+			formatInvestmentAmount(gain.CostBasis, costFraction),
+			formatInvestmentAmount(gain.Proceeds, costFraction),
+			formatInvestmentAmount(gain.Gain, costFraction),
+			gain.HoldingPeriod.String(),
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+
+	if err = writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (a *InvestmentsApi) getInvestmentAccounts(c *core.WebContext, uid int64, accountIds string) ([]*models.Account, error) {
+	var accounts []*models.Account
+
+	if accountIds == "" {
+		allAccounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = allAccounts
+	} else {
+		ids, err := utils.StringArrayToInt64Array(strings.Split(accountIds, ","))
+
+		if err != nil {
+			return nil, errs.ErrAccountIdInvalid
+		}
+
+		accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, ids)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(ids); i++ {
+			account, exists := accountMap[ids[i]]
+
+			if !exists {
+				return nil, errs.ErrAccountNotFound
+			}
+
+			accounts = append(accounts, account)
+		}
+	}
+
+	investmentAccounts := make([]*models.Account, 0, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || account.Extend == nil || (account.Extend.AssetType != models.ACCOUNT_ASSET_TYPE_STOCK && account.Extend.AssetType != models.ACCOUNT_ASSET_TYPE_CRYPTO) {
+			continue
+		}
+
+		investmentAccounts = append(investmentAccounts, account)
+	}
+
+	return investmentAccounts, nil
+}
+
+func (a *InvestmentsApi) getHoldingResponse(account *models.Account, position *models.InvestmentPosition, marketPrice *marketPrice, exchangeRates map[string]float64) *models.InvestmentHoldingInfoResponse {
+	lotResps := make([]*models.InvestmentLotInfoResponse, len(position.OpenLots))
+
+	for i := 0; i < len(position.OpenLots); i++ {
+		lotResps[i] = position.OpenLots[i].ToInvestmentLotInfoResponse()
+	}
+
+	holdingResp := &models.InvestmentHoldingInfoResponse{
+		AccountId:             account.AccountId,
+		Symbol:                account.Currency,
+		Quantity:              position.Quantity,
+		CostCurrency:          position.Currency,
+		TotalCost:             position.TotalCost,
+		RealizedProfitAndLoss: position.RealizedProfitAndLoss,
+		TotalDividends:        position.TotalDividends,
+		Lots:                  lotResps,
+	}
+
+	quantityFraction := models.GetCurrencyFraction(account.Currency)
+	costFraction := models.GetCurrencyFraction(position.Currency)
+	quantity := float64(position.Quantity) / utils.Pow10(quantityFraction)
+
+	if quantity > 0 {
+		holdingResp.AverageCost = utils.Float64ToString(float64(position.TotalCost) / utils.Pow10(costFraction) / quantity)
+	}
+
+	if marketPrice == nil {
+		return holdingResp
+	}
+
+	price, err := utils.StringToFloat64(marketPrice.price)
+
+	if err != nil || price <= 0 {
+		return holdingResp
+	}
+
+	holdingResp.MarketPrice = marketPrice.price
+	holdingResp.MarketPriceCurrency = marketPrice.currency
+
+	if position.Currency == "" {
+		return holdingResp
+	}
+
+	marketValue := quantity * price
+
+	if marketPrice.currency != position.Currency {
+		sourceRate, sourceExists := exchangeRates[marketPrice.currency]
+		targetRate, targetExists := exchangeRates[position.Currency]
+
+		if !sourceExists || !targetExists {
+			return holdingResp
+		}
+
+		marketValue = marketValue / sourceRate * targetRate
+	}
+
+	holdingResp.HasMarketPrice = true
+	holdingResp.MarketValue = int64(math.Round(marketValue * utils.Pow10(costFraction)))
+	holdingResp.UnrealizedProfitAndLoss = holdingResp.MarketValue - position.TotalCost
+
+	return holdingResp
+}
+
+func (a *InvestmentsApi) getPerformanceResponse(accountId int64, points []*models.InvestmentValuationPoint, incomplete bool) *models.InvestmentPerformanceInfoResponse {
+	performanceResp := &models.InvestmentPerformanceInfoResponse{
+		AccountId:       accountId,
+		IncompleteValue: incomplete,
+	}
+
+	if points == nil {
+		return performanceResp
+	}
+
+	performance := a.performances.CalculatePerformance(points)
+
+	performanceResp.StartValue = performance.StartValue
+	performanceResp.EndValue = performance.EndValue
+	performanceResp.NetCashFlow = performance.NetCashFlow
+	performanceResp.Gain = performance.EndValue - performance.StartValue - performance.NetCashFlow
+	performanceResp.HasValue = true
+
+	if performance.HasTimeWeightedReturn {
+		performanceResp.TimeWeightedReturn = strconv.FormatFloat(performance.TimeWeightedReturn*100, 'f', 2, 64)
+	}
+
+	if performance.HasMoneyWeightedReturn {
+		performanceResp.MoneyWeightedReturn = strconv.FormatFloat(performance.MoneyWeightedReturn*100, 'f', 2, 64)
+	}
+
+	return performanceResp
+}
+
+func formatInvestmentAmount(value int64, fraction int) string {
+	return strconv.FormatFloat(float64(value)/utils.Pow10(fraction), 'f', fraction, 64)
+}
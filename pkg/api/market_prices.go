@@ -0,0 +1,448 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/cryptocurrency"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/stocks"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+var usdPeggedStablecoins = map[string]bool{
+	"USDT": true,
+	"USDC": true,
+	"DAI":  true,
+	"BUSD": true,
+	"TUSD": true,
+	"PAX":  true,
+}
+
+const marketPriceHistoryDateRangeMargin = 24 * time.Hour
+
+type marketPrice struct {
+	price         string
+	currency      string
+	quoteCurrency string
+	dataSource    string
+}
+
+type priceHistorySource struct {
+	currency   string
+	dataSource string
+}
+
+type assetPriceHistoryReader interface {
+	GetPriceHistoriesOfSymbolsInDateRange(c core.Context, symbols []string, currency string, dataSource string, startDate int32, endDate int32) (map[string][]*models.AssetPriceHistory, error)
+	GetLatestPriceHistories(c core.Context, symbols []string) (map[string]*models.AssetPriceHistory, error)
+}
+
+// ApiUsingMarketPrices represents an api that need to use the prices of stocks and cryptocurrencies and the exchange rates
+type ApiUsingMarketPrices struct {
+	ApiUsingConfig
+	externalDataSourceConfigs    *services.ExternalDataSourceConfigService
+	stockDataSourceRoutes        *services.StockDataSourceRouteService
+	stockPriceHistories          *services.StockPriceHistoryService
+	cryptocurrencyPriceHistories *services.CryptocurrencyPriceHistoryService
+	exchangeRateHistories        *services.ExchangeRateHistoryService
+}
+
+// Initialize the market prices helper shared by all apis which need to value the accounts
+var (
+	apiUsingMarketPrices = ApiUsingMarketPrices{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		externalDataSourceConfigs:    services.ExternalDataSourceConfigs,
+		stockDataSourceRoutes:        services.StockDataSourceRoutes,
+		stockPriceHistories:          services.StockPriceHistories,
+		cryptocurrencyPriceHistories: services.CryptocurrencyPriceHistories,
+		exchangeRateHistories:        services.ExchangeRateHistories,
+	}
+)
+
+// GetLatestMarketPrices returns the latest prices of the stocks and cryptocurrencies held by the specified accounts
+func (a *ApiUsingMarketPrices) GetLatestMarketPrices(c *core.WebContext, uid int64, accounts []*models.Account) map[models.AccountAssetType]map[string]*marketPrice {
+	stockSymbols, cryptoSymbols := a.getAccountSymbols(accounts)
+
+	return map[models.AccountAssetType]map[string]*marketPrice{
+		models.ACCOUNT_ASSET_TYPE_STOCK:  a.getLatestStockPrices(c, uid, stockSymbols),
+		models.ACCOUNT_ASSET_TYPE_CRYPTO: a.getLatestCryptocurrencyPrices(c, uid, cryptoSymbols),
+	}
+}
+
+// GetLatestExchangeRates returns the latest exchange rates relative to the base currency of current exchange rates data source
+func (a *ApiUsingMarketPrices) GetLatestExchangeRates(c *core.WebContext, uid int64) map[string]float64 {
+	exchangeRates, _, _ := a.getLatestExchangeRates(c, uid)
+	return exchangeRates
+}
+
+// GetAssetValuator returns an asset valuator which values the balances of specified accounts in the target currency,
+// the daily price histories of stocks and cryptocurrencies and the daily exchange rate histories between the history start time and the history end time
+// would also be loaded if the history end time is greater than 0 (the history start time 0 means unlimited)
+func (a *ApiUsingMarketPrices) GetAssetValuator(c *core.WebContext, uid int64, targetCurrency string, accounts []*models.Account, historyStartTime int64, historyEndTime int64) *models.AssetValuator {
+	exchangeRates, exchangeRatesDataSource, baseCurrency := a.getLatestExchangeRates(c, uid)
+	valuator := models.NewAssetValuator(targetCurrency, exchangeRates)
+	latestPrices := a.GetLatestMarketPrices(c, uid, accounts)
+	today := utils.FormatUnixTimeToNumericYearMonthDay(time.Now().Unix(), time.UTC)
+	currencies := map[string]bool{
+		targetCurrency: true,
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		if assetType := accounts[i].GetAssetType(); assetType != models.ACCOUNT_ASSET_TYPE_STOCK && assetType != models.ACCOUNT_ASSET_TYPE_CRYPTO {
+			currencies[accounts[i].Currency] = true
+		}
+	}
+
+	for assetType, prices := range latestPrices {
+		for symbol, price := range prices {
+			priceValue, err := utils.StringToFloat64(price.price)
+
+			if err != nil {
+				continue
+			}
+
+			valuator.SetLatestPrice(assetType, symbol, &models.AssetPrice{
+				Price:     priceValue,
+				Currency:  price.currency,
+				PriceDate: today,
+			})
+
+			currencies[price.currency] = true
+		}
+	}
+
+	if historyEndTime <= 0 {
+		return valuator
+	}
+
+	// the dates of histories are in UTC, so the date range is extended by one day to cover the dates in all time zones
+	historyStartDate := int32(0)
+	historyEndDate := utils.FormatUnixTimeToNumericYearMonthDay(historyEndTime+int64(marketPriceHistoryDateRangeMargin.Seconds()), time.UTC)
+
+	if historyStartTime > 0 {
+		historyStartDate = utils.FormatUnixTimeToNumericYearMonthDay(historyStartTime-int64(marketPriceHistoryDateRangeMargin.Seconds()), time.UTC)
+	}
+
+	stockSymbols, cryptoSymbols := a.getAccountSymbols(accounts)
+	a.setPriceHistories(c, valuator, models.ACCOUNT_ASSET_TYPE_STOCK, a.stockPriceHistories, stockSymbols, latestPrices[models.ACCOUNT_ASSET_TYPE_STOCK], historyStartDate, historyEndDate, currencies)
+	a.setPriceHistories(c, valuator, models.ACCOUNT_ASSET_TYPE_CRYPTO, a.cryptocurrencyPriceHistories, cryptoSymbols, latestPrices[models.ACCOUNT_ASSET_TYPE_CRYPTO], historyStartDate, historyEndDate, currencies)
+
+	if exchangeRatesDataSource == "" {
+		return valuator
+	}
+
+	if exchangerates.Container.IsUserCustomDataSource() {
+		histories, err := exchangerates.Container.GetUserCustomExchangeRateHistories(c, uid, baseCurrency, exchangeRates)
+
+		if err != nil {
+			log.Warnf(c, "[market_prices.GetAssetValuator] failed to get user custom exchange rate histories, because %s", err.Error())
+			return valuator
+		}
+
+		for currency, rates := range histories {
+			valuator.SetExchangeRateHistories(currency, rates)
+		}
+
+		return valuator
+	}
+
+	valuator.SetLatestExchangeRateDate(today)
+
+	// the stored histories may be relative to another base currency (e.g. saved before the data source was changed), so they are converted by the histories of the latest base currency
+	historyCurrencies := make([]string, 0, len(currencies)+1)
+	historyCurrencies = append(historyCurrencies, baseCurrency)
+
+	for currency := range currencies {
+		historyCurrencies = append(historyCurrencies, currency)
+	}
+
+	currencyHistories, err := a.exchangeRateHistories.GetExchangeRateHistoriesOfCurrenciesInDateRange(c, exchangeRatesDataSource, historyCurrencies, historyStartDate, historyEndDate)
+
+	if err != nil {
+		log.Warnf(c, "[market_prices.GetAssetValuator] failed to get exchange rate histories of currencies \"%s\", because %s", strings.Join(historyCurrencies, ","), err.Error())
+	}
+
+	baseCurrencyHistories := currencyHistories[strings.ToUpper(baseCurrency)]
+
+	for currency := range currencies {
+		valuator.SetExchangeRateHistories(currency, models.GetHistoricalExchangeRatesInBaseCurrency(currencyHistories[strings.ToUpper(currency)], baseCurrency, baseCurrencyHistories))
+	}
+
+	return valuator
+}
+
+// GetTransactionAmountConverter returns a converter which converts the transaction amounts of specified accounts to the target currency
+// by the exchange rates and the prices at the transaction dates between the start time and the end time (the start time 0 means unlimited and the end time 0 means now)
+func (a *ApiUsingMarketPrices) GetTransactionAmountConverter(c *core.WebContext, uid int64, targetCurrency string, accounts []*models.Account, startTime int64, endTime int64) models.TransactionAmountConverter {
+	if endTime <= 0 {
+		endTime = time.Now().Unix()
+	}
+
+	valuator := a.GetAssetValuator(c, uid, targetCurrency, accounts, startTime, endTime)
+	accountMap := make(map[int64]*models.Account, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		accountMap[accounts[i].AccountId] = accounts[i]
+	}
+
+	return func(accountId int64, amount int64, yearMonthDay int32) (int64, bool) {
+		account, exists := accountMap[accountId]
+
+		if !exists {
+			return 0, false
+		}
+
+		return valuator.GetValue(account.GetAssetType(), account.Currency, amount, yearMonthDay)
+	}
+}
+
+func (a *ApiUsingMarketPrices) getLatestExchangeRates(c *core.WebContext, uid int64) (map[string]float64, string, string) {
+	exchangeRates := make(map[string]float64)
+	exchangeRateResponse, err := exchangerates.Container.GetLatestExchangeRates(c, uid, a.CurrentConfig())
+
+	if err != nil {
+		log.Warnf(c, "[market_prices.getLatestExchangeRates] failed to get latest exchange rates, because %s", err.Error())
+		return exchangeRates, "", ""
+	}
+
+	for i := 0; i < len(exchangeRateResponse.ExchangeRates); i++ {
+		rate, err := utils.StringToFloat64(exchangeRateResponse.ExchangeRates[i].Rate)
+
+		if err == nil && rate > 0 {
+			exchangeRates[exchangeRateResponse.ExchangeRates[i].Currency] = rate
+		}
+	}
+
+	exchangeRates[exchangeRateResponse.BaseCurrency] = 1
+
+	return exchangeRates, exchangeRateResponse.DataSource, exchangeRateResponse.BaseCurrency
+}
+
+func (a *ApiUsingMarketPrices) getAccountSymbols(accounts []*models.Account) ([]string, []string) {
+	stockSymbols := make([]string, 0, len(accounts))
+	cryptoSymbols := make([]string, 0, len(accounts))
+	addedStockSymbols := make(map[string]bool, len(accounts))
+	addedCryptoSymbols := make(map[string]bool, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		assetType := accounts[i].GetAssetType()
+		symbol := strings.ToUpper(accounts[i].Currency)
+
+		if assetType == models.ACCOUNT_ASSET_TYPE_STOCK && !addedStockSymbols[symbol] {
+			stockSymbols = append(stockSymbols, symbol)
+			addedStockSymbols[symbol] = true
+		} else if assetType == models.ACCOUNT_ASSET_TYPE_CRYPTO && !addedCryptoSymbols[symbol] {
+			cryptoSymbols = append(cryptoSymbols, symbol)
+			addedCryptoSymbols[symbol] = true
+		}
+	}
+
+	return stockSymbols, cryptoSymbols
+}
+
+// setPriceHistories sets the daily price histories of the specified symbols between the start date and the end date to the valuator,
+// the histories of the symbols which have the same currency and data source are loaded together, and the currencies of the prices are added to the currencies
+func (a *ApiUsingMarketPrices) setPriceHistories(c *core.WebContext, valuator *models.AssetValuator, assetType models.AccountAssetType, priceHistories assetPriceHistoryReader, symbols []string, latestPrices map[string]*marketPrice, startDate int32, endDate int32, currencies map[string]bool) {
+	if len(symbols) < 1 {
+		return
+	}
+
+	sourceSymbols := make(map[priceHistorySource][]string)
+
+	for symbol, source := range a.getPriceHistorySources(c, assetType, priceHistories, symbols, latestPrices) {
+		sourceSymbols[source] = append(sourceSymbols[source], symbol)
+	}
+
+	symbolPrices := make(map[string][]*models.AssetPrice, len(symbols))
+
+	for source, symbolsOfSource := range sourceSymbols {
+		symbolHistories, err := priceHistories.GetPriceHistoriesOfSymbolsInDateRange(c, symbolsOfSource, source.currency, source.dataSource, startDate, endDate)
+
+		if err != nil {
+			log.Warnf(c, "[market_prices.setPriceHistories] failed to get price histories of %s \"%s\", because %s", getAssetTypeName(assetType), strings.Join(symbolsOfSource, ","), err.Error())
+			continue
+		}
+
+		for symbol, histories := range symbolHistories {
+			for i := 0; i < len(histories); i++ {
+				priceValue, err := utils.StringToFloat64(histories[i].Price)
+
+				if err != nil {
+					continue
+				}
+
+				currency := histories[i].Currency
+
+				if assetType == models.ACCOUNT_ASSET_TYPE_CRYPTO {
+					currency = getCryptocurrencyPriceCurrency(currency)
+				}
+
+				symbolPrices[symbol] = append(symbolPrices[symbol], &models.AssetPrice{
+					Price:     priceValue,
+					Currency:  currency,
+					PriceDate: histories[i].PriceDate,
+				})
+				currencies[currency] = true
+			}
+		}
+	}
+
+	for i := 0; i < len(symbols); i++ {
+		prices := symbolPrices[symbols[i]]
+
+		if prices == nil {
+			prices = make([]*models.AssetPrice, 0)
+		}
+
+		valuator.SetPriceHistories(assetType, symbols[i], prices)
+	}
+}
+
+// getPriceHistorySources returns the currencies and the data sources of the price histories of the specified symbols,
+// which are the same as the latest prices, or the latest saved price histories if the latest prices are not available
+func (a *ApiUsingMarketPrices) getPriceHistorySources(c *core.WebContext, assetType models.AccountAssetType, priceHistories assetPriceHistoryReader, symbols []string, latestPrices map[string]*marketPrice) map[string]priceHistorySource {
+	sources := make(map[string]priceHistorySource, len(symbols))
+	symbolsWithoutLatestPrice := make([]string, 0, len(symbols))
+
+	for i := 0; i < len(symbols); i++ {
+		latestPrice := latestPrices[symbols[i]]
+
+		if latestPrice != nil && latestPrice.quoteCurrency != "" && latestPrice.dataSource != "" {
+			sources[symbols[i]] = priceHistorySource{
+				currency:   latestPrice.quoteCurrency,
+				dataSource: latestPrice.dataSource,
+			}
+		} else {
+			symbolsWithoutLatestPrice = append(symbolsWithoutLatestPrice, symbols[i])
+		}
+	}
+
+	if len(symbolsWithoutLatestPrice) < 1 {
+		return sources
+	}
+
+	latestHistories, err := priceHistories.GetLatestPriceHistories(c, symbolsWithoutLatestPrice)
+
+	if err != nil {
+		log.Warnf(c, "[market_prices.getPriceHistorySources] failed to get latest price histories of %s \"%s\", because %s", getAssetTypeName(assetType), strings.Join(symbolsWithoutLatestPrice, ","), err.Error())
+		return sources
+	}
+
+	for symbol, history := range latestHistories {
+		sources[symbol] = priceHistorySource{
+			currency:   history.Currency,
+			dataSource: history.DataSource,
+		}
+	}
+
+	return sources
+}
+
+func (a *ApiUsingMarketPrices) getLatestStockPrices(c *core.WebContext, uid int64, symbols []string) map[string]*marketPrice {
+	prices := make(map[string]*marketPrice)
+
+	if len(symbols) < 1 {
+		return prices
+	}
+
+	stockRouting, err := a.stockDataSourceRoutes.GetRouting(c)
+
+	if err != nil {
+		log.Warnf(c, "[market_prices.getLatestStockPrices] failed to get stock data source routing, because %s", err.Error())
+		return prices
+	}
+
+	priceResponse, err := stocks.Container.GetLatestStockPrices(c, uid, stockRouting, symbols)
+
+	if err != nil {
+		log.Warnf(c, "[market_prices.getLatestStockPrices] failed to get latest stock prices, because %s", err.Error())
+		return prices
+	}
+
+	for i := 0; i < len(priceResponse.Prices); i++ {
+		price := priceResponse.Prices[i]
+		currency := price.Currency
+
+		if currency == "" {
+			currency = priceResponse.BaseCurrency
+		}
+
+		dataSource := price.DataSource
+
+		if dataSource == "" {
+			dataSource = priceResponse.DataSource
+		}
+
+		prices[strings.ToUpper(price.Symbol)] = &marketPrice{
+			price:         price.Price,
+			currency:      currency,
+			quoteCurrency: strings.ToUpper(currency),
+			dataSource:    dataSource,
+		}
+	}
+
+	return prices
+}
+
+func (a *ApiUsingMarketPrices) getLatestCryptocurrencyPrices(c *core.WebContext, uid int64, symbols []string) map[string]*marketPrice {
+	prices := make(map[string]*marketPrice)
+
+	if len(symbols) < 1 {
+		return prices
+	}
+
+	cryptocurrencyConfig, err := a.externalDataSourceConfigs.GetConfig(c, models.EXTERNAL_DATA_SOURCE_TYPE_CRYPTOCURRENCY)
+
+	if err != nil {
+		log.Warnf(c, "[market_prices.getLatestCryptocurrencyPrices] failed to get cryptocurrency data source config, because %s", err.Error())
+		return prices
+	}
+
+	priceResponse, err := cryptocurrency.Container.GetLatestCryptocurrencyPrices(c, uid, cryptocurrencyConfig, symbols)
+
+	if err != nil {
+		log.Warnf(c, "[market_prices.getLatestCryptocurrencyPrices] failed to get latest cryptocurrency prices, because %s", err.Error())
+		return prices
+	}
+
+	currency := getCryptocurrencyPriceCurrency(priceResponse.BaseCurrency)
+
+	for i := 0; i < len(priceResponse.Prices); i++ {
+		price := priceResponse.Prices[i]
+
+		prices[strings.ToUpper(price.Symbol)] = &marketPrice{
+			price:         price.Price,
+			currency:      currency,
+			quoteCurrency: strings.ToUpper(priceResponse.BaseCurrency),
+			dataSource:    cryptocurrencyConfig.DataSource,
+		}
+	}
+
+	return prices
+}
+
+func getAssetTypeName(assetType models.AccountAssetType) string {
+	if assetType == models.ACCOUNT_ASSET_TYPE_CRYPTO {
+		return "cryptocurrency"
+	}
+
+	return "stock"
+}
+
+// getCryptocurrencyPriceCurrency returns the fiat currency of cryptocurrency prices,
+// prices quoted in stablecoins pegged to USD are treated as prices in USD
+func getCryptocurrencyPriceCurrency(currency string) string {
+	if _, exists := usdPeggedStablecoins[currency]; exists {
+		return "USD"
+	}
+
+	return currency
+}
@@ -18,12 +18,15 @@ const mcpServerName = core.ApplicationName + "-mcp"
 // ModelContextProtocolAPI represents model context protocol api
 type ModelContextProtocolAPI struct {
 	ApiUsingConfig
+	ApiUsingMarketPrices
 	transactions          *services.TransactionService
 	transactionCategories *services.TransactionCategoryService
 	transactionTags       *services.TransactionTagService
+	transactionSplits     *services.TransactionSplitService
 	accounts              *services.AccountService
 	users                 *services.UserService
 	tokens                *services.TokenService
+	goals                 *services.GoalService
 }
 
 // Initialize a model context protocol api singleton instance
@@ -32,12 +35,15 @@ var (
 		ApiUsingConfig: ApiUsingConfig{
 			container: settings.Container,
 		},
+		ApiUsingMarketPrices:  apiUsingMarketPrices,
 		transactions:          services.Transactions,
 		transactionCategories: services.TransactionCategories,
 		transactionTags:       services.TransactionTags,
+		transactionSplits:     services.TransactionSplits,
 		accounts:              services.Accounts,
 		users:                 services.Users,
 		tokens:                services.Tokens,
+		goals:                 services.Goals,
 	}
 )
 
@@ -249,6 +255,11 @@ func (a *ModelContextProtocolAPI) GetTransactionTagService() *services.Transacti
 	return a.transactionTags
 }
 
+// GetTransactionSplitService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetTransactionSplitService() *services.TransactionSplitService {
+	return a.transactionSplits
+}
+
 // GetAccountService implements the MCPAvailableServices interface
 func (a *ModelContextProtocolAPI) GetAccountService() *services.AccountService {
 	return a.accounts
@@ -259,6 +270,11 @@ func (a *ModelContextProtocolAPI) GetUserService() *services.UserService {
 	return a.users
 }
 
+// GetGoalService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetGoalService() *services.GoalService {
+	return a.goals
+}
+
 // getMCPVersion returns the MCP protocol version from the request header
 func (a *ModelContextProtocolAPI) getMCPVersion(c *core.WebContext) string {
 	return c.GetHeader(mcp.MCPProtocolVersionHeaderName)
@@ -32,6 +32,8 @@ type OAuth2AuthenticationApi struct {
 	users             *services.UserService
 	tokens            *services.TokenService
 	userExternalAuths *services.UserExternalAuthService
+	stocks            *services.StockService
+	cryptocurrencies  *services.CryptocurrencyService
 }
 
 // Initialize a OAuth 2.0 authentication api singleton instance
@@ -49,6 +51,8 @@ var (
 		users:             services.Users,
 		tokens:            services.Tokens,
 		userExternalAuths: services.UserExternalAuths,
+		stocks:            services.Stocks,
+		cryptocurrencies:  services.Cryptocurrencies,
 	}
 )
 
@@ -322,6 +326,18 @@ func (a *OAuth2AuthenticationApi) CallbackHandler(c *core.WebContext) (string, *
 
 			log.Infof(c, "[oauth2_authentications.CallbackHandler] user \"%s\" has registered successfully, uid is %d", user.Username, user.Uid)
 
+			err = a.stocks.InitUserStocks(c, user.Uid)
+
+			if err != nil {
+				log.Warnf(c, "[oauth2_authentications.CallbackHandler] failed to initialize stock watchlist for user \"uid:%d\", because %s", user.Uid, err.Error())
+			}
+
+			err = a.cryptocurrencies.InitUserCryptocurrencies(c, user.Uid)
+
+			if err != nil {
+				log.Warnf(c, "[oauth2_authentications.CallbackHandler] failed to initialize cryptocurrency watchlist for user \"uid:%d\", because %s", user.Uid, err.Error())
+			}
+
 			userExternalAuth = &models.UserExternalAuth{
 				Uid:              user.Uid,
 				ExternalAuthType: userExternalAuthType,
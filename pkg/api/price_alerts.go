@@ -0,0 +1,104 @@
+package api
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// PriceAlertsApi represents price alert api
+type PriceAlertsApi struct {
+	ApiUsingConfig
+	alerts *services.PriceAlertService
+}
+
+// Initialize a price alert api singleton instance
+var (
+	PriceAlerts = &PriceAlertsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		alerts: services.PriceAlerts,
+	}
+)
+
+// AlertListHandler returns price alert list of current user
+func (a *PriceAlertsApi) AlertListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	alerts, err := a.alerts.GetAllAlertsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[price_alerts.AlertListHandler] failed to get price alerts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	alertResps := make([]*models.PriceAlertInfoResponse, len(alerts))
+
+	for i := 0; i < len(alerts); i++ {
+		alertResps[i] = alerts[i].ToPriceAlertInfoResponse()
+	}
+
+	return alertResps, nil
+}
+
+// AlertCreateHandler saves a new price alert by request parameters for current user
+func (a *PriceAlertsApi) AlertCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var alertCreateReq models.PriceAlertCreateRequest
+	err := c.ShouldBindJSON(&alertCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[price_alerts.AlertCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !a.CurrentConfig().EnableSMTP {
+		return nil, errs.ErrSMTPServerNotEnabled
+	}
+
+	uid := c.GetCurrentUid()
+	alert := &models.PriceAlert{
+		Uid:        uid,
+		AssetType:  alertCreateReq.AssetType,
+		Symbol:     alertCreateReq.Symbol,
+		Currency:   alertCreateReq.Currency,
+		Type:       alertCreateReq.Type,
+		Threshold:  alertCreateReq.Threshold,
+		PeriodDays: alertCreateReq.PeriodDays,
+	}
+
+	err = a.alerts.CreateAlert(c, alert)
+
+	if err != nil {
+		log.Errorf(c, "[price_alerts.AlertCreateHandler] failed to create price alert for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[price_alerts.AlertCreateHandler] user \"uid:%d\" has created a new price alert \"id:%d\" successfully", uid, alert.AlertId)
+
+	return alert.ToPriceAlertInfoResponse(), nil
+}
+
+// AlertDeleteHandler deletes an existed price alert by request parameters for current user
+func (a *PriceAlertsApi) AlertDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var alertDeleteReq models.PriceAlertDeleteRequest
+	err := c.ShouldBindJSON(&alertDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[price_alerts.AlertDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.alerts.DeleteAlert(c, uid, alertDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[price_alerts.AlertDeleteHandler] failed to delete price alert \"id:%d\" for user \"uid:%d\", because %s", alertDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[price_alerts.AlertDeleteHandler] user \"uid:%d\" has deleted price alert \"id:%d\"", uid, alertDeleteReq.Id)
+	return true, nil
+}
@@ -1,42 +1,56 @@
 package api
 
 import (
+	"sort"
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 	"github.com/mayswind/ezbookkeeping/pkg/stocks"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // StockApi represents stock api
 type StockApi struct {
-	ApiUsingConfig
+	ApiUsingAdministrator
 	externalDataSourceConfigs *services.ExternalDataSourceConfigService
 	stocks                    *services.StockService
+	stockPriceHistories       *services.StockPriceHistoryService
+	stockDataSourceRoutes     *services.StockDataSourceRouteService
+	stockCorporateActions     *services.StockCorporateActionService
 }
 
 // Initialize a stock api singleton instance
 var (
 	Stocks = &StockApi{
-		ApiUsingConfig: ApiUsingConfig{
-			container: settings.Container,
+		ApiUsingAdministrator: ApiUsingAdministrator{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			users: services.Users,
 		},
 		externalDataSourceConfigs: services.ExternalDataSourceConfigs,
 		stocks:                    services.Stocks,
+		stockPriceHistories:       services.StockPriceHistories,
+		stockDataSourceRoutes:     services.StockDataSourceRoutes,
+		stockCorporateActions:     services.StockCorporateActions,
 	}
 )
 
 // LatestStockPriceHandler returns latest stock price data
 func (a *StockApi) LatestStockPriceHandler(c *core.WebContext) (any, *errs.Error) {
-	config, err := a.externalDataSourceConfigs.GetConfig(c, models.EXTERNAL_DATA_SOURCE_TYPE_STOCK)
-	
+	routing, err := a.stockDataSourceRoutes.GetRouting(c)
+
 	if err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	stockList, err := a.stocks.GetAllVisibleStocks(c)
-	
+	stockList, err := a.stocks.GetAllVisibleStocks(c, c.GetCurrentUid())
+
 	if err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
@@ -46,7 +60,7 @@ func (a *StockApi) LatestStockPriceHandler(c *core.WebContext) (any, *errs.Error
 		symbols[i] = stock.Symbol
 	}
 
-	stockPriceResponse, err := stocks.Container.GetLatestStockPrices(c, c.GetCurrentUid(), config, symbols)
+	stockPriceResponse, err := stocks.Container.GetLatestStockPrices(c, c.GetCurrentUid(), routing, symbols)
 
 	if err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
@@ -55,9 +69,74 @@ func (a *StockApi) LatestStockPriceHandler(c *core.WebContext) (any, *errs.Error
 	return stockPriceResponse, nil
 }
 
-// StockListHandler returns stock list
+// StockListHandler returns stock list in the watchlist of current user
 func (a *StockApi) StockListHandler(c *core.WebContext) (any, *errs.Error) {
-	stockList, err := a.stocks.GetAllStocks(c)
+	return a.getStockList(c, c.GetCurrentUid())
+}
+
+// StockAddHandler adds a new stock to the watchlist of current user
+func (a *StockApi) StockAddHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.addStock(c, c.GetCurrentUid())
+}
+
+// StockModifyHandler modifies a stock in the watchlist of current user
+func (a *StockApi) StockModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.modifyStock(c, c.GetCurrentUid())
+}
+
+// StockHideHandler hides or unhides a stock in the watchlist of current user
+func (a *StockApi) StockHideHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.hideStock(c, c.GetCurrentUid())
+}
+
+// StockDeleteHandler deletes a stock from the watchlist of current user
+func (a *StockApi) StockDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.deleteStock(c, c.GetCurrentUid())
+}
+
+// DefaultStockListHandler returns stock list in the global default watchlist
+func (a *StockApi) DefaultStockListHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.getStockList(c, 0)
+}
+
+// DefaultStockAddHandler adds a new stock to the global default watchlist, only administrators can perform this action
+func (a *StockApi) DefaultStockAddHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	return a.addStock(c, 0)
+}
+
+// DefaultStockModifyHandler modifies a stock in the global default watchlist, only administrators can perform this action
+func (a *StockApi) DefaultStockModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	return a.modifyStock(c, 0)
+}
+
+// DefaultStockHideHandler hides or unhides a stock in the global default watchlist, only administrators can perform this action
+func (a *StockApi) DefaultStockHideHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	return a.hideStock(c, 0)
+}
+
+// DefaultStockDeleteHandler deletes a stock from the global default watchlist, only administrators can perform this action
+func (a *StockApi) DefaultStockDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	return a.deleteStock(c, 0)
+}
+
+func (a *StockApi) getStockList(c *core.WebContext, uid int64) (any, *errs.Error) {
+	stockList, err := a.stocks.GetAllStocks(c, uid)
 	if err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
@@ -70,14 +149,14 @@ func (a *StockApi) StockListHandler(c *core.WebContext) (any, *errs.Error) {
 	return responses, nil
 }
 
-// StockAddHandler adds a new stock
-func (a *StockApi) StockAddHandler(c *core.WebContext) (any, *errs.Error) {
+func (a *StockApi) addStock(c *core.WebContext, uid int64) (any, *errs.Error) {
 	var req models.StockCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
 	stock := &models.Stock{
+		Uid:          uid,
 		Symbol:       req.Symbol,
 		Name:         req.Name,
 		Market:       req.Market,
@@ -89,17 +168,19 @@ func (a *StockApi) StockAddHandler(c *core.WebContext) (any, *errs.Error) {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	a.stockDataSourceRoutes.InvalidateSymbolMarkets()
+
 	return stock.ToStockInfoResponse(), nil
 }
 
-// StockModifyHandler modifies a stock
-func (a *StockApi) StockModifyHandler(c *core.WebContext) (any, *errs.Error) {
+func (a *StockApi) modifyStock(c *core.WebContext, uid int64) (any, *errs.Error) {
 	var req models.StockModifyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
 	stock := &models.Stock{
+		Uid:          uid,
 		Symbol:       req.Symbol,
 		Name:         req.Name,
 		Market:       req.Market,
@@ -111,34 +192,36 @@ func (a *StockApi) StockModifyHandler(c *core.WebContext) (any, *errs.Error) {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	a.stockDataSourceRoutes.InvalidateSymbolMarkets()
+
 	return stock.ToStockInfoResponse(), nil
 }
 
-// StockHideHandler hides or unhides a stock
-func (a *StockApi) StockHideHandler(c *core.WebContext) (any, *errs.Error) {
+func (a *StockApi) hideStock(c *core.WebContext, uid int64) (any, *errs.Error) {
 	var req models.StockHideRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	if err := a.stocks.HideStock(c, req.Symbol, req.Hidden); err != nil {
+	if err := a.stocks.HideStock(c, uid, req.Symbol, req.Hidden); err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
 	return true, nil
 }
 
-// StockDeleteHandler deletes a stock
-func (a *StockApi) StockDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+func (a *StockApi) deleteStock(c *core.WebContext, uid int64) (any, *errs.Error) {
 	var req models.StockDeleteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	if err := a.stocks.DeleteStock(c, req.Symbol); err != nil {
+	if err := a.stocks.DeleteStock(c, uid, req.Symbol); err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	a.stockDataSourceRoutes.InvalidateSymbolMarkets()
+
 	return true, nil
 }
 
@@ -156,8 +239,12 @@ func (a *StockApi) StockConfigGetHandler(c *core.WebContext) (any, *errs.Error)
 	return config.ToExternalDataSourceConfigResponse(), nil
 }
 
-// StockConfigSaveHandler saves stock config
+// StockConfigSaveHandler saves stock config, only administrators can perform this action
 func (a *StockApi) StockConfigSaveHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
 	var req models.ExternalDataSourceConfigSaveRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
@@ -183,3 +270,240 @@ func (a *StockApi) StockConfigSaveHandler(c *core.WebContext) (any, *errs.Error)
 
 	return config.ToExternalDataSourceConfigResponse(), nil
 }
+
+// StockConfigListHandler returns all stock data source configs without their api keys, only administrators can perform this action
+func (a *StockApi) StockConfigListHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	configs, err := a.externalDataSourceConfigs.GetConfigs(c, models.EXTERNAL_DATA_SOURCE_TYPE_STOCK)
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	responses := make([]*models.ExternalDataSourceConfigSummaryResponse, len(configs))
+	for i, config := range configs {
+		responses[i] = config.ToExternalDataSourceConfigSummaryResponse()
+	}
+
+	return responses, nil
+}
+
+// StockConfigDeleteHandler deletes the stock data source config of specified data source, only administrators can perform this action
+func (a *StockApi) StockConfigDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	var req models.ExternalDataSourceConfigDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if err := a.externalDataSourceConfigs.DeleteStockConfig(c, req.DataSource); err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return true, nil
+}
+
+// StockDataSourceRouteListHandler returns all stock data source routes of markets
+func (a *StockApi) StockDataSourceRouteListHandler(c *core.WebContext) (any, *errs.Error) {
+	routes, err := a.stockDataSourceRoutes.GetAllRoutes(c)
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	responses := make([]*models.StockDataSourceRouteInfoResponse, len(routes))
+	for i, route := range routes {
+		responses[i] = route.ToStockDataSourceRouteInfoResponse()
+	}
+
+	return responses, nil
+}
+
+// StockDataSourceRouteSaveHandler saves the ordered stock data sources of a market, only administrators can perform this action
+func (a *StockApi) StockDataSourceRouteSaveHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	var req models.StockDataSourceRouteSaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	route, err := a.stockDataSourceRoutes.SaveRoute(c, req.Market, req.DataSources)
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return route.ToStockDataSourceRouteInfoResponse(), nil
+}
+
+// StockDataSourceRouteDeleteHandler deletes the stock data source route of a market, only administrators can perform this action
+func (a *StockApi) StockDataSourceRouteDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	var req models.StockDataSourceRouteDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if err := a.stockDataSourceRoutes.DeleteRoute(c, req.Market); err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return true, nil
+}
+
+// StockPriceHistoryHandler returns the daily price history of a stock
+func (a *StockApi) StockPriceHistoryHandler(c *core.WebContext) (any, *errs.Error) {
+	var req models.StockPriceHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Warnf(c, "[stocks.StockPriceHistoryHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if req.StartTime > 0 && req.EndTime > 0 && req.StartTime > req.EndTime {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	startDate := int32(0)
+	endDate := int32(0)
+
+	if req.StartTime > 0 {
+		startDate = utils.FormatUnixTimeToNumericYearMonthDay(req.StartTime, time.UTC)
+	}
+
+	if req.EndTime > 0 {
+		endDate = utils.FormatUnixTimeToNumericYearMonthDay(req.EndTime, time.UTC)
+	}
+
+	histories, err := a.stockPriceHistories.GetPriceHistories(c, req.Symbol, req.Currency, req.DataSource, startDate, endDate)
+
+	if err != nil {
+		log.Errorf(c, "[stocks.StockPriceHistoryHandler] failed to get price history of \"symbol:%s\", because %s", req.Symbol, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	responses := make(models.StockPriceHistoryResponseSlice, len(histories))
+	for i, history := range histories {
+		responses[i] = history.ToStockPriceHistoryResponse()
+	}
+
+	sort.Sort(responses)
+
+	return responses, nil
+}
+
+// StockCorporateActionListHandler returns the corporate actions of all stocks or the specified stock
+func (a *StockApi) StockCorporateActionListHandler(c *core.WebContext) (any, *errs.Error) {
+	var req models.StockCorporateActionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Warnf(c, "[stocks.StockCorporateActionListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	actions, err := a.stockCorporateActions.GetAllActions(c, req.Symbol)
+	if err != nil {
+		log.Errorf(c, "[stocks.StockCorporateActionListHandler] failed to get corporate actions of \"symbol:%s\", because %s", req.Symbol, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	responses := make(models.StockCorporateActionInfoResponseSlice, len(actions))
+	for i, action := range actions {
+		responses[i] = action.ToStockCorporateActionInfoResponse()
+	}
+
+	sort.Sort(responses)
+
+	return responses, nil
+}
+
+// StockCorporateActionAddHandler adds a new corporate action of a stock and adjusts the related price histories and trades
+func (a *StockApi) StockCorporateActionAddHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	var req models.StockCorporateActionCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf(c, "[stocks.StockCorporateActionAddHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	action := &models.StockCorporateAction{
+		Symbol:           req.Symbol,
+		Type:             req.Type,
+		NewSymbol:        req.NewSymbol,
+		RatioNumerator:   req.RatioNumerator,
+		RatioDenominator: req.RatioDenominator,
+		EffectiveTime:    req.EffectiveTime,
+		Comment:          req.Comment,
+	}
+
+	if err := a.stockCorporateActions.CreateAction(c, action); err != nil {
+		log.Errorf(c, "[stocks.StockCorporateActionAddHandler] failed to create corporate action of \"symbol:%s\", because %s", req.Symbol, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	a.stockDataSourceRoutes.InvalidateSymbolMarkets()
+
+	log.Infof(c, "[stocks.StockCorporateActionAddHandler] user \"uid:%d\" has created a new corporate action \"id:%d\" of \"symbol:%s\" successfully", c.GetCurrentUid(), action.ActionId, action.Symbol)
+
+	return action.ToStockCorporateActionInfoResponse(), nil
+}
+
+// StockCorporateActionApplyHandler applies an existed corporate action of a stock again if its previous applying failed partway
+func (a *StockApi) StockCorporateActionApplyHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	var req models.StockCorporateActionApplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf(c, "[stocks.StockCorporateActionApplyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	action, err := a.stockCorporateActions.ApplyAction(c, req.Id)
+
+	if err != nil {
+		log.Errorf(c, "[stocks.StockCorporateActionApplyHandler] failed to apply corporate action \"id:%d\", because %s", req.Id, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	a.stockDataSourceRoutes.InvalidateSymbolMarkets()
+
+	log.Infof(c, "[stocks.StockCorporateActionApplyHandler] user \"uid:%d\" has applied corporate action \"id:%d\" successfully", c.GetCurrentUid(), req.Id)
+
+	return action.ToStockCorporateActionInfoResponse(), nil
+}
+
+// StockCorporateActionDeleteHandler deletes the latest corporate action of a stock and reverts the adjustments it made
+func (a *StockApi) StockCorporateActionDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	if err := a.CheckCurrentUserIsAdministrator(c); err != nil {
+		return nil, err
+	}
+
+	var req models.StockCorporateActionDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf(c, "[stocks.StockCorporateActionDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if err := a.stockCorporateActions.DeleteAction(c, req.Id); err != nil {
+		log.Errorf(c, "[stocks.StockCorporateActionDeleteHandler] failed to delete corporate action \"id:%d\", because %s", req.Id, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	a.stockDataSourceRoutes.InvalidateSymbolMarkets()
+
+	log.Infof(c, "[stocks.StockCorporateActionDeleteHandler] user \"uid:%d\" has deleted corporate action \"id:%d\"", c.GetCurrentUid(), req.Id)
+
+	return true, nil
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionReconciliationsApi represents transaction reconciliation api
+type TransactionReconciliationsApi struct {
+	ApiUsingConfig
+	reconciliations *services.TransactionReconciliationService
+	accounts        *services.AccountService
+}
+
+// Initialize a transaction reconciliation api singleton instance
+var (
+	TransactionReconciliations = &TransactionReconciliationsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		reconciliations: services.TransactionReconciliations,
+		accounts:        services.Accounts,
+	}
+)
+
+// TransactionReconciliationStatusModifyHandler marks the specified transactions of one account as cleared or uncleared for current user
+func (a *TransactionReconciliationsApi) TransactionReconciliationStatusModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var statusModifyReq models.TransactionReconciliationStatusModifyRequest
+	err := c.ShouldBindJSON(&statusModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_reconciliations.TransactionReconciliationStatusModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	transactionIds, err := utils.StringArrayToInt64Array(statusModifyReq.Ids)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_reconciliations.TransactionReconciliationStatusModifyHandler] parse transaction ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	account, err := a.getReconcilableAccount(c, uid, statusModifyReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_reconciliations.TransactionReconciliationStatusModifyHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", statusModifyReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionIds = utils.ToUniqueInt64Slice(transactionIds)
+	err = a.reconciliations.ModifyTransactionsReconciliationStatus(c, uid, account.AccountId, transactionIds, statusModifyReq.Status)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_reconciliations.TransactionReconciliationStatusModifyHandler] failed to update reconciliation status of transactions in account \"id:%d\" for user \"uid:%d\", because %s", account.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_reconciliations.TransactionReconciliationStatusModifyHandler] user \"uid:%d\" has marked %d transactions in account \"id:%d\" as %s", uid, len(transactionIds), account.AccountId, statusModifyReq.Status)
+
+	return true, nil
+}
+
+// TransactionReconciliationListHandler returns all reconciliations of one account for current user
+func (a *TransactionReconciliationsApi) TransactionReconciliationListHandler(c *core.WebContext) (any, *errs.Error) {
+	var reconciliationListReq models.TransactionReconciliationListRequest
+	err := c.ShouldBindQuery(&reconciliationListReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_reconciliations.TransactionReconciliationListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	reconciliations, err := a.reconciliations.GetAllReconciliationsByAccountId(c, uid, reconciliationListReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_reconciliations.TransactionReconciliationListHandler] failed to get reconciliations of account \"id:%d\" for user \"uid:%d\", because %s", reconciliationListReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	reconciliationResps := make([]*models.TransactionReconciliationInfoResponse, len(reconciliations))
+
+	for i := 0; i < len(reconciliations); i++ {
+		reconciliationResps[i] = reconciliations[i].ToTransactionReconciliationInfoResponse()
+	}
+
+	return reconciliationResps, nil
+}
+
+// TransactionReconciliationPreviewHandler returns the cleared balance of one account before the statement time for current user
+func (a *TransactionReconciliationsApi) TransactionReconciliationPreviewHandler(c *core.WebContext) (any, *errs.Error) {
+	var reconciliationPreviewReq models.TransactionReconciliationPreviewRequest
+	err := c.ShouldBindQuery(&reconciliationPreviewReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_reconciliations.TransactionReconciliationPreviewHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	account, err := a.getReconcilableAccount(c, uid, reconciliationPreviewReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_reconciliations.TransactionReconciliationPreviewHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", reconciliationPreviewReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	preview, err := a.reconciliations.GetReconciliationPreview(c, uid, account.AccountId, reconciliationPreviewReq.StatementTime)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_reconciliations.TransactionReconciliationPreviewHandler] failed to get reconciliation preview of account \"id:%d\" for user \"uid:%d\", because %s", account.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return preview, nil
+}
+
+// TransactionReconciliationCreateHandler reconciles all cleared transactions of one account against the statement balance for current user
+func (a *TransactionReconciliationsApi) TransactionReconciliationCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var reconciliationCreateReq models.TransactionReconciliationCreateRequest
+	err := c.ShouldBindJSON(&reconciliationCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_reconciliations.TransactionReconciliationCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	account, err := a.getReconcilableAccount(c, uid, reconciliationCreateReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_reconciliations.TransactionReconciliationCreateHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", reconciliationCreateReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	reconciliation := &models.TransactionReconciliation{
+		Uid:               uid,
+		AccountId:         account.AccountId,
+		StatementUnixTime: reconciliationCreateReq.StatementTime,
+		StatementBalance:  reconciliationCreateReq.StatementBalance,
+	}
+
+	err = a.reconciliations.CreateReconciliation(c, reconciliation)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_reconciliations.TransactionReconciliationCreateHandler] failed to reconcile account \"id:%d\" for user \"uid:%d\", because %s", account.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_reconciliations.TransactionReconciliationCreateHandler] user \"uid:%d\" has reconciled %d transactions in account \"id:%d\" by reconciliation \"id:%d\" successfully", uid, reconciliation.TransactionCount, account.AccountId, reconciliation.ReconciliationId)
+
+	return reconciliation.ToTransactionReconciliationInfoResponse(), nil
+}
+
+// TransactionReconciliationDeleteHandler deletes the latest reconciliation of one account and unlocks its transactions for current user
+func (a *TransactionReconciliationsApi) TransactionReconciliationDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var reconciliationDeleteReq models.TransactionReconciliationDeleteRequest
+	err := c.ShouldBindJSON(&reconciliationDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_reconciliations.TransactionReconciliationDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.reconciliations.DeleteReconciliation(c, uid, reconciliationDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_reconciliations.TransactionReconciliationDeleteHandler] failed to delete reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_reconciliations.TransactionReconciliationDeleteHandler] user \"uid:%d\" has deleted reconciliation \"id:%d\"", uid, reconciliationDeleteReq.Id)
+
+	return true, nil
+}
+
+func (a *TransactionReconciliationsApi) getReconcilableAccount(c *core.WebContext, uid int64, accountId int64) (*models.Account, error) {
+	account, err := a.accounts.GetAccountByAccountId(c, uid, accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		return nil, errs.ErrTransactionReconciliationAccountTypeInvalid
+	}
+
+	if account.Hidden {
+		return nil, errs.ErrCannotModifyTransactionInHiddenAccount
+	}
+
+	return account, nil
+}
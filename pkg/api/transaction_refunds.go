@@ -0,0 +1,140 @@
+package api
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionRefundsApi represents transaction refund api
+type TransactionRefundsApi struct {
+	ApiUsingConfig
+	refunds *services.TransactionRefundService
+}
+
+// Initialize a transaction refund api singleton instance
+var (
+	TransactionRefunds = &TransactionRefundsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		refunds: services.TransactionRefunds,
+	}
+)
+
+// TransactionRefundListHandler returns all refunds linked to one transaction of current user
+func (a *TransactionRefundsApi) TransactionRefundListHandler(c *core.WebContext) (any, *errs.Error) {
+	var refundListReq models.TransactionRefundListRequest
+	err := c.ShouldBindQuery(&refundListReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_refunds.TransactionRefundListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	refunds, err := a.refunds.GetAllRefundsByTransactionId(c, uid, refundListReq.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_refunds.TransactionRefundListHandler] failed to get refunds of transaction \"id:%d\" for user \"uid:%d\", because %s", refundListReq.TransactionId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	refundResps := make([]*models.TransactionRefundInfoResponse, len(refunds))
+
+	for i := 0; i < len(refunds); i++ {
+		refundResps[i] = refunds[i].ToTransactionRefundInfoResponse()
+	}
+
+	return refundResps, nil
+}
+
+// TransactionRefundCreateHandler links an income transaction as a refund of an expense transaction for current user
+func (a *TransactionRefundsApi) TransactionRefundCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var refundCreateReq models.TransactionRefundCreateRequest
+	err := c.ShouldBindJSON(&refundCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_refunds.TransactionRefundCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	refund := &models.TransactionRefund{
+		Uid:                  uid,
+		ExpenseTransactionId: refundCreateReq.ExpenseTransactionId,
+		IncomeTransactionId:  refundCreateReq.IncomeTransactionId,
+		Amount:               refundCreateReq.Amount,
+	}
+
+	err = a.refunds.CreateRefund(c, refund)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_refunds.TransactionRefundCreateHandler] failed to link transaction \"id:%d\" as refund of transaction \"id:%d\" for user \"uid:%d\", because %s", refund.IncomeTransactionId, refund.ExpenseTransactionId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_refunds.TransactionRefundCreateHandler] user \"uid:%d\" has linked transaction \"id:%d\" as refund of transaction \"id:%d\" successfully", uid, refund.IncomeTransactionId, refund.ExpenseTransactionId)
+
+	return refund.ToTransactionRefundInfoResponse(), nil
+}
+
+// TransactionRefundDeleteHandler deletes an existed transaction refund link by request parameters for current user
+func (a *TransactionRefundsApi) TransactionRefundDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var refundDeleteReq models.TransactionRefundDeleteRequest
+	err := c.ShouldBindJSON(&refundDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_refunds.TransactionRefundDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.refunds.DeleteRefund(c, uid, refundDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_refunds.TransactionRefundDeleteHandler] failed to delete transaction refund \"id:%d\" for user \"uid:%d\", because %s", refundDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_refunds.TransactionRefundDeleteHandler] user \"uid:%d\" has deleted transaction refund \"id:%d\"", uid, refundDeleteReq.Id)
+
+	return true, nil
+}
+
+// TransactionOutstandingReimbursementListHandler returns all reimbursable expense transactions which have not been fully reimbursed of current user
+func (a *TransactionRefundsApi) TransactionOutstandingReimbursementListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	transactions, reimbursedAmounts, err := a.refunds.GetOutstandingReimbursableTransactions(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_refunds.TransactionOutstandingReimbursementListHandler] failed to get outstanding reimbursable transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	reimbursementResps := make([]*models.TransactionOutstandingReimbursementResponse, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		reimbursedAmount := reimbursedAmounts[transaction.TransactionId]
+
+		reimbursementResps[i] = &models.TransactionOutstandingReimbursementResponse{
+			Id:                transaction.TransactionId,
+			Time:              utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime),
+			UtcOffset:         transaction.TimezoneUtcOffset,
+			CategoryId:        transaction.CategoryId,
+			AccountId:         transaction.AccountId,
+			Amount:            transaction.Amount,
+			ReimbursedAmount:  reimbursedAmount,
+			OutstandingAmount: transaction.Amount - reimbursedAmount,
+			Comment:           transaction.Comment,
+		}
+	}
+
+	return reimbursementResps, nil
+}
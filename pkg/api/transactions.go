@@ -6,6 +6,7 @@ import (
 	"io"
 	"math"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,10 +31,12 @@ const pageCountForAccountStatement = 1000
 type TransactionsApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
+	ApiUsingMarketPrices
 	transactions          *services.TransactionService
 	transactionCategories *services.TransactionCategoryService
 	transactionTags       *services.TransactionTagService
 	transactionPictures   *services.TransactionPictureService
+	transactionSplits     *services.TransactionSplitService
 	accounts              *services.AccountService
 	users                 *services.UserService
 }
@@ -50,10 +53,12 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
+		ApiUsingMarketPrices:  apiUsingMarketPrices,
 		transactions:          services.Transactions,
 		transactionCategories: services.TransactionCategories,
 		transactionTags:       services.TransactionTags,
 		transactionPictures:   services.TransactionPictures,
+		transactionSplits:     services.TransactionSplits,
 		accounts:              services.Accounts,
 		users:                 services.Users,
 	}
@@ -454,10 +459,12 @@ func (a *TransactionsApi) TransactionReconciliationStatementHandler(c *core.WebC
 		transactionResult := transactionResult[i]
 		accountOpeningBalance := int64(0)
 		accountClosingBalance := int64(0)
+		reconciliationStatus := models.TRANSACTION_RECONCILIATION_STATUS_UNCLEARED
 
 		if transactionWithBalance, exists := transactionAccountBalanceMap[transactionResult.Id]; exists {
 			accountOpeningBalance = transactionWithBalance.AccountOpeningBalance
 			accountClosingBalance = transactionWithBalance.AccountClosingBalance
+			reconciliationStatus = transactionWithBalance.ReconciliationStatus
 		} else {
 			log.Warnf(c, "[transactions.TransactionReconciliationStatementHandler] missing account balance for transaction \"id:%d\" of user \"uid:%d\"", transactionResult.Id, uid)
 		}
@@ -466,6 +473,7 @@ func (a *TransactionsApi) TransactionReconciliationStatementHandler(c *core.WebC
 			TransactionInfoResponse: transactionResult,
 			AccountOpeningBalance:   accountOpeningBalance,
 			AccountClosingBalance:   accountClosingBalance,
+			ReconciliationStatus:    reconciliationStatus,
 		}
 	}
 
@@ -510,7 +518,18 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 	}
 
 	uid := c.GetCurrentUid()
-	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, statisticReq.StartTime, statisticReq.EndTime, tagFilters, noTags, statisticReq.Keyword, clientTimezone, statisticReq.UseTransactionTimezone)
+	var amountConverter models.TransactionAmountConverter
+
+	if statisticReq.UseHistoricalExchangeRates {
+		var errResp *errs.Error
+		amountConverter, errResp = a.getDefaultCurrencyAmountConverter(c, uid, statisticReq.StartTime, statisticReq.EndTime)
+
+		if errResp != nil {
+			return nil, errResp
+		}
+	}
+
+	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, statisticReq.StartTime, statisticReq.EndTime, tagFilters, noTags, statisticReq.Keyword, clientTimezone, statisticReq.UseTransactionTimezone, amountConverter)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -527,9 +546,11 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 	for i := 0; i < len(totalAmounts); i++ {
 		totalAmountItem := totalAmounts[i]
 		statisticResp.Items[i] = &models.TransactionStatisticResponseItem{
-			CategoryId:  totalAmountItem.CategoryId,
-			AccountId:   totalAmountItem.AccountId,
-			TotalAmount: totalAmountItem.Amount,
+			CategoryId:               totalAmountItem.CategoryId,
+			AccountId:                totalAmountItem.AccountId,
+			TotalAmount:              totalAmountItem.Amount,
+			DefaultCurrencyAmount:    totalAmountItem.ConvertedAmount,
+			HasDefaultCurrencyAmount: totalAmountItem.HasConvertedAmount,
 		}
 
 		if totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
@@ -578,7 +599,29 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 	}
 
 	uid := c.GetCurrentUid()
-	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, uid, startYear, startMonth, endYear, endMonth, tagFilters, noTags, statisticTrendsReq.Keyword, clientTimezone, statisticTrendsReq.UseTransactionTimezone)
+	var amountConverter models.TransactionAmountConverter
+
+	if statisticTrendsReq.UseHistoricalExchangeRates {
+		startUnixTime := int64(0)
+		endUnixTime := int64(0)
+
+		if startYear > 0 {
+			startUnixTime = time.Date(int(startYear), time.Month(startMonth), 1, 0, 0, 0, 0, clientTimezone).Unix()
+		}
+
+		if endYear > 0 {
+			endUnixTime = time.Date(int(endYear), time.Month(endMonth)+1, 1, 0, 0, 0, 0, clientTimezone).Unix() - 1
+		}
+
+		var errResp *errs.Error
+		amountConverter, errResp = a.getDefaultCurrencyAmountConverter(c, uid, startUnixTime, endUnixTime)
+
+		if errResp != nil {
+			return nil, errResp
+		}
+	}
+
+	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, uid, startYear, startMonth, endYear, endMonth, tagFilters, noTags, statisticTrendsReq.Keyword, clientTimezone, statisticTrendsReq.UseTransactionTimezone, amountConverter)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -597,9 +640,11 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 		for i := 0; i < len(monthlyTotalAmounts); i++ {
 			totalAmountItem := monthlyTotalAmounts[i]
 			monthlyStatisticResp.Items[i] = &models.TransactionStatisticResponseItem{
-				CategoryId:  totalAmountItem.CategoryId,
-				AccountId:   totalAmountItem.AccountId,
-				TotalAmount: totalAmountItem.Amount,
+				CategoryId:               totalAmountItem.CategoryId,
+				AccountId:                totalAmountItem.AccountId,
+				TotalAmount:              totalAmountItem.Amount,
+				DefaultCurrencyAmount:    totalAmountItem.ConvertedAmount,
+				HasDefaultCurrencyAmount: totalAmountItem.HasConvertedAmount,
 			}
 
 			if totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
@@ -654,6 +699,36 @@ func (a *TransactionsApi) TransactionStatisticsAssetTrendsHandler(c *core.WebCon
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transactions.TransactionStatisticsAssetTrendsHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsAssetTrendsHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountMap := make(map[int64]*models.Account, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		accountMap[accounts[i].AccountId] = accounts[i]
+	}
+
+	valuatorEndTime := statisticAssetTrendsReq.EndTime
+
+	if valuatorEndTime <= 0 {
+		valuatorEndTime = time.Now().Unix()
+	}
+
+	valuator := a.GetAssetValuator(c, uid, user.DefaultCurrency, accounts, statisticAssetTrendsReq.StartTime, valuatorEndTime)
 	statisticAssetTrendsResp := make(models.TransactionStatisticAssetTrendsResponseItemSlice, 0)
 
 	for yearMonthDay, dailyAccountBalances := range accountDailyBalances {
@@ -666,11 +741,25 @@ func (a *TransactionsApi) TransactionStatisticsAssetTrendsHandler(c *core.WebCon
 
 		for i := 0; i < len(dailyAccountBalances); i++ {
 			accountBalance := dailyAccountBalances[i]
-			dailyStatisticResp.Items[i] = &models.TransactionStatisticAssetTrendsResponseDataItem{
+			dataItem := &models.TransactionStatisticAssetTrendsResponseDataItem{
 				AccountId:             accountBalance.AccountId,
 				AccountOpeningBalance: accountBalance.AccountOpeningBalance,
 				AccountClosingBalance: accountBalance.AccountClosingBalance,
 			}
+
+			if account, exists := accountMap[accountBalance.AccountId]; exists {
+				assetType := account.GetAssetType()
+				openingValue, hasOpeningValue := valuator.GetValue(assetType, account.Currency, accountBalance.AccountOpeningBalance, yearMonthDay)
+				closingValue, hasClosingValue := valuator.GetValue(assetType, account.Currency, accountBalance.AccountClosingBalance, yearMonthDay)
+
+				if hasOpeningValue && hasClosingValue {
+					dataItem.AccountOpeningValue = openingValue
+					dataItem.AccountClosingValue = closingValue
+					dataItem.HasValue = true
+				}
+			}
+
+			dailyStatisticResp.Items[i] = dataItem
 		}
 
 		statisticAssetTrendsResp = append(statisticAssetTrendsResp, dailyStatisticResp)
@@ -681,6 +770,336 @@ func (a *TransactionsApi) TransactionStatisticsAssetTrendsHandler(c *core.WebCon
 	return statisticAssetTrendsResp, nil
 }
 
+// TransactionStatisticsHoldingIncomeHandler returns the yearly income and dividend yield of holdings of current user
+func (a *TransactionsApi) TransactionStatisticsHoldingIncomeHandler(c *core.WebContext) (any, *errs.Error) {
+	var statisticHoldingIncomeReq models.TransactionStatisticHoldingIncomeRequest
+	err := c.ShouldBindQuery(&statisticHoldingIncomeReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionStatisticsHoldingIncomeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if statisticHoldingIncomeReq.StartYear > 0 && statisticHoldingIncomeReq.EndYear > 0 && statisticHoldingIncomeReq.StartYear > statisticHoldingIncomeReq.EndYear {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionStatisticsHoldingIncomeHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transactions.TransactionStatisticsHoldingIncomeHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	yearlyIncomes, err := a.transactions.GetHoldingsYearlyIncome(c, uid, statisticHoldingIncomeReq.StartYear, statisticHoldingIncomeReq.EndYear, clientTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsHoldingIncomeHandler] failed to get holding income for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	holdingIncomeResp := &models.TransactionStatisticHoldingIncomeResponse{
+		Currency: user.DefaultCurrency,
+		Holdings: make(models.TransactionStatisticHoldingIncomeResponseHoldingItemSlice, 0),
+	}
+
+	if len(yearlyIncomes) < 1 {
+		return holdingIncomeResp, nil
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsHoldingIncomeHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountMap := a.accounts.GetAccountMapByList(accounts)
+	now := time.Now()
+	today := utils.FormatUnixTimeToNumericYearMonthDay(now.Unix(), clientTimezone)
+
+	years := make([]int32, 0, len(yearlyIncomes))
+
+	for year := range yearlyIncomes {
+		years = append(years, year)
+	}
+
+	sort.Slice(years, func(i, j int) bool {
+		return years[i] < years[j]
+	})
+
+	firstYearStartTime := time.Date(int(years[0]), time.January, 1, 0, 0, 0, 0, clientTimezone).Unix()
+	valuator := a.GetAssetValuator(c, uid, user.DefaultCurrency, accounts, firstYearStartTime, now.Unix())
+	dailyBalances, err := a.transactions.GetAllAccountsDailyOpeningAndClosingBalance(c, uid, 0, utils.GetMinTransactionTimeFromUnixTime(firstYearStartTime), clientTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsHoldingIncomeHandler] failed to get daily account balances for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	holdingAccountIds := make(map[int64]bool)
+	balanceDates := make([]int32, len(years))
+
+	for _, incomes := range yearlyIncomes {
+		for i := 0; i < len(incomes); i++ {
+			holdingAccountIds[incomes[i].HoldingAccountId] = true
+		}
+	}
+
+	for i := 0; i < len(years); i++ {
+		balanceDates[i] = years[i]*10000 + 1231
+
+		if balanceDates[i] > today {
+			balanceDates[i] = today
+		}
+	}
+
+	yearEndBalances := models.GetAccountBalancesAtDates(dailyBalances, holdingAccountIds, balanceDates)
+	holdingItems := make(map[int64]*models.TransactionStatisticHoldingIncomeResponseHoldingItem)
+
+	for i := 0; i < len(years); i++ {
+		year := years[i]
+		yearEndDate := year*10000 + 1231
+
+		if yearEndDate >= today {
+			yearEndDate = 0
+		}
+
+		yearItems := make(map[int64]*models.TransactionStatisticHoldingIncomeResponseYearItem)
+
+		for _, income := range yearlyIncomes[year] {
+			holdingItem, exists := holdingItems[income.HoldingAccountId]
+
+			if !exists {
+				holdingItem = &models.TransactionStatisticHoldingIncomeResponseHoldingItem{
+					HoldingAccountId: income.HoldingAccountId,
+					Years:            make([]*models.TransactionStatisticHoldingIncomeResponseYearItem, 0, len(years)),
+				}
+
+				if holdingAccount, exists := accountMap[income.HoldingAccountId]; exists {
+					holdingItem.Symbol = holdingAccount.Currency
+					holdingItem.AssetType = holdingAccount.GetAssetType()
+				}
+
+				holdingItems[income.HoldingAccountId] = holdingItem
+				holdingIncomeResp.Holdings = append(holdingIncomeResp.Holdings, holdingItem)
+			}
+
+			yearItem, exists := yearItems[income.HoldingAccountId]
+
+			if !exists {
+				yearItem = &models.TransactionStatisticHoldingIncomeResponseYearItem{
+					Year: year,
+				}
+
+				yearItems[income.HoldingAccountId] = yearItem
+				holdingItem.Years = append(holdingItem.Years, yearItem)
+			}
+
+			incomeAccount, exists := accountMap[income.AccountId]
+
+			if !exists {
+				holdingItem.HasUnconvertedIncome = true
+				continue
+			}
+
+			// the income is converted at the date it is received
+			incomeDate := utils.FormatUnixTimeToNumericYearMonthDay(utils.GetUnixTimeFromTransactionTime(income.TransactionTime), clientTimezone)
+			value, hasValue := valuator.GetValue(incomeAccount.GetAssetType(), incomeAccount.Currency, income.Amount, incomeDate)
+
+			if !hasValue {
+				holdingItem.HasUnconvertedIncome = true
+				continue
+			}
+
+			yearItem.Income += value
+		}
+
+		for holdingAccountId, yearItem := range yearItems {
+			holdingItem := holdingItems[holdingAccountId]
+			holdingItem.TotalIncome += yearItem.Income
+			yearItem.CumulativeIncome = holdingItem.TotalIncome
+
+			holdingAccount, exists := accountMap[holdingAccountId]
+
+			if !exists {
+				continue
+			}
+
+			// the holding value is estimated by the quantity and the price at the end of the year
+			holdingValue, hasValue := valuator.GetValue(holdingAccount.GetAssetType(), holdingAccount.Currency, yearEndBalances[i][holdingAccountId], yearEndDate)
+
+			if hasValue && holdingValue > 0 {
+				yearItem.HoldingValue = holdingValue
+				yearItem.DividendYield = strconv.FormatFloat(float64(yearItem.Income)/float64(holdingValue)*100, 'f', 2, 64)
+			}
+		}
+	}
+
+	sort.Sort(holdingIncomeResp.Holdings)
+
+	return holdingIncomeResp, nil
+}
+
+// TransactionStatisticsFxGainLossHandler returns the effective exchange rates and the foreign exchange gain and loss of all cross-currency transfers of current user
+func (a *TransactionsApi) TransactionStatisticsFxGainLossHandler(c *core.WebContext) (any, *errs.Error) {
+	var statisticFxGainLossReq models.TransactionStatisticFxGainLossRequest
+	err := c.ShouldBindQuery(&statisticFxGainLossReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionStatisticsFxGainLossHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if statisticFxGainLossReq.StartTime > 0 && statisticFxGainLossReq.EndTime > 0 && statisticFxGainLossReq.StartTime > statisticFxGainLossReq.EndTime {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionStatisticsFxGainLossHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transactions.TransactionStatisticsFxGainLossHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	fxGainLossResp := &models.TransactionStatisticFxGainLossResponse{
+		Currency:  user.DefaultCurrency,
+		Periods:   make([]*models.TransactionStatisticFxGainLossResponsePeriodItem, 0),
+		Transfers: make([]*models.TransactionStatisticFxGainLossResponseTransferItem, 0),
+	}
+
+	transactions, err := a.transactions.GetAllTransferOutTransactions(c, uid, statisticFxGainLossReq.StartTime, statisticFxGainLossReq.EndTime)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsFxGainLossHandler] failed to get transfer transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if len(transactions) < 1 {
+		return fxGainLossResp, nil
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsFxGainLossHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountMap := a.accounts.GetAccountMapByList(accounts)
+	valuatorEndTime := statisticFxGainLossReq.EndTime
+
+	if valuatorEndTime <= 0 {
+		valuatorEndTime = time.Now().Unix()
+	}
+
+	valuator := a.GetAssetValuator(c, uid, user.DefaultCurrency, accounts, statisticFxGainLossReq.StartTime, valuatorEndTime)
+	periodItems := make(map[int32]*models.TransactionStatisticFxGainLossResponsePeriodItem)
+
+	for i := len(transactions) - 1; i >= 0; i-- {
+		transaction := transactions[i]
+		sourceAccount, sourceExists := accountMap[transaction.AccountId]
+		destinationAccount, destinationExists := accountMap[transaction.RelatedAccountId]
+
+		if !sourceExists || !destinationExists || sourceAccount.Currency == destinationAccount.Currency {
+			continue
+		}
+
+		if sourceAccount.GetAssetType() != models.ACCOUNT_ASSET_TYPE_FIAT || destinationAccount.GetAssetType() != models.ACCOUNT_ASSET_TYPE_FIAT {
+			continue
+		}
+
+		timeZone := clientTimezone
+
+		if statisticFxGainLossReq.UseTransactionTimezone {
+			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		}
+
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		yearMonth := utils.FormatUnixTimeToNumericYearMonth(transactionUnixTime, timeZone)
+		fxGainLoss := valuator.GetTransferFxGainLoss(sourceAccount.Currency, transaction.Amount, destinationAccount.Currency, transaction.RelatedAccountAmount, utils.FormatUnixTimeToNumericYearMonthDay(transactionUnixTime, timeZone))
+
+		transferItem := &models.TransactionStatisticFxGainLossResponseTransferItem{
+			TransactionId:        transaction.TransactionId,
+			Time:                 transactionUnixTime,
+			SourceAccountId:      sourceAccount.AccountId,
+			SourceCurrency:       sourceAccount.Currency,
+			SourceAmount:         transaction.Amount,
+			DestinationAccountId: destinationAccount.AccountId,
+			DestinationCurrency:  destinationAccount.Currency,
+			DestinationAmount:    transaction.RelatedAccountAmount,
+			EffectiveRate:        utils.Float64ToString(fxGainLoss.EffectiveRate),
+			GainLoss:             fxGainLoss.GainLoss,
+			HasGainLoss:          fxGainLoss.HasGainLoss,
+		}
+
+		if fxGainLoss.HasReferenceRate {
+			transferItem.ReferenceRate = utils.Float64ToString(fxGainLoss.ReferenceRate)
+			transferItem.ReferenceAmount = fxGainLoss.ReferenceAmount
+		}
+
+		fxGainLossResp.Transfers = append(fxGainLossResp.Transfers, transferItem)
+
+		periodItem, exists := periodItems[yearMonth]
+
+		if !exists {
+			periodItem = &models.TransactionStatisticFxGainLossResponsePeriodItem{
+				Year:  yearMonth / 100,
+				Month: yearMonth % 100,
+			}
+
+			periodItems[yearMonth] = periodItem
+			fxGainLossResp.Periods = append(fxGainLossResp.Periods, periodItem)
+		}
+
+		periodItem.TransferCount++
+
+		if !fxGainLoss.HasGainLoss {
+			fxGainLossResp.HasUnconvertedAmount = true
+			continue
+		}
+
+		if fxGainLoss.GainLoss < 0 {
+			periodItem.Fees -= fxGainLoss.GainLoss
+			fxGainLossResp.TotalFees -= fxGainLoss.GainLoss
+		} else {
+			periodItem.Gains += fxGainLoss.GainLoss
+			fxGainLossResp.TotalGains += fxGainLoss.GainLoss
+		}
+
+		periodItem.NetGainLoss += fxGainLoss.GainLoss
+		fxGainLossResp.NetGainLoss += fxGainLoss.GainLoss
+	}
+
+	sort.Slice(fxGainLossResp.Periods, func(i, j int) bool {
+		return fxGainLossResp.Periods[i].Year*100+fxGainLossResp.Periods[i].Month < fxGainLossResp.Periods[j].Year*100+fxGainLossResp.Periods[j].Month
+	})
+
+	return fxGainLossResp, nil
+}
+
 // TransactionAmountsHandler returns transaction amounts of current user
 func (a *TransactionsApi) TransactionAmountsHandler(c *core.WebContext) (any, *errs.Error) {
 	var transactionAmountsReq models.TransactionAmountsRequest
@@ -919,9 +1338,17 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		}
 	}
 
+	allTransactionSplits, err := a.transactionSplits.GetAllSplitsOfTransactions(c, uid, []int64{transaction.TransactionId})
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	transactionEditable := transaction.IsEditable(user, clientTimezone, accountMap[transaction.AccountId], accountMap[transaction.RelatedAccountId])
 	transactionTagIds := allTransactionTagIds[transaction.TransactionId]
 	transactionResp := transaction.ToTransactionInfoResponse(transactionTagIds, transactionEditable)
+	transactionResp.Splits = models.GetTransactionSplitInfoResponses(allTransactionSplits[transaction.TransactionId])
 
 	if !transactionGetReq.TrimAccount {
 		if sourceAccount := accountMap[transaction.AccountId]; sourceAccount != nil {
@@ -989,6 +1416,13 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionHasTooManyPictures
 	}
 
+	splits, err := a.createNewTransactionSplitModels(transactionCreateReq.Splits)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCreateHandler] parse splits failed, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionTagIdInvalid)
+	}
+
 	if transactionCreateReq.Type < models.TRANSACTION_TYPE_MODIFY_BALANCE || transactionCreateReq.Type > models.TRANSACTION_TYPE_TRANSFER {
 		log.Warnf(c, "[transactions.TransactionCreateHandler] transaction type is invalid")
 		return nil, errs.ErrTransactionTypeInvalid
@@ -1071,7 +1505,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds)
+	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds, splits)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionCreateHandler] failed to create transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
@@ -1083,6 +1517,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, transactionCreateReq.ClientSessionId, utils.Int64ToString(transaction.TransactionId))
 	transactionResp := transaction.ToTransactionInfoResponse(tagIds, transactionEditable)
 	transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
+	transactionResp.Splits = models.GetTransactionSplitInfoResponses(splits)
 
 	return transactionResp, nil
 }
@@ -1126,6 +1561,13 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionHasTooManyPictures
 	}
 
+	splits, err := a.createNewTransactionSplitModels(transactionModifyReq.Splits)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionModifyHandler] parse splits failed, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionTagIdInvalid)
+	}
+
 	uid := c.GetCurrentUid()
 	user, err := a.users.GetUserById(c, uid)
 
@@ -1187,7 +1629,9 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		TimezoneUtcOffset: transactionModifyReq.UtcOffset,
 		AccountId:         transactionModifyReq.SourceAccountId,
 		Amount:            transactionModifyReq.SourceAmount,
+		HoldingAccountId:  transactionModifyReq.HoldingAccountId,
 		HideAmount:        transactionModifyReq.HideAmount,
+		Reimbursable:      transactionModifyReq.Reimbursable,
 		Comment:           transactionModifyReq.Comment,
 		GeoLongitude:      transaction.GeoLongitude,
 		GeoLatitude:       transaction.GeoLatitude,
@@ -1210,12 +1654,15 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		newTransaction.Amount == transaction.Amount &&
 		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountId == transaction.RelatedAccountId) &&
 		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountAmount == transaction.RelatedAccountAmount) &&
+		newTransaction.HoldingAccountId == transaction.HoldingAccountId &&
 		newTransaction.HideAmount == transaction.HideAmount &&
+		newTransaction.Reimbursable == transaction.Reimbursable &&
 		newTransaction.Comment == transaction.Comment &&
 		newTransaction.GeoLongitude == transaction.GeoLongitude &&
 		newTransaction.GeoLatitude == transaction.GeoLatitude &&
 		utils.Int64SliceEquals(tagIds, transactionTagIds) &&
-		utils.Int64SliceEquals(pictureIds, transactionPictureIds) {
+		utils.Int64SliceEquals(pictureIds, transactionPictureIds) &&
+		splits == nil {
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
@@ -1273,7 +1720,7 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	err = a.transactions.ModifyTransaction(c, newTransaction, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds)
+	err = a.transactions.ModifyTransaction(c, newTransaction, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds, splits)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
@@ -1286,6 +1733,18 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 	newTransactionResp := newTransaction.ToTransactionInfoResponse(tagIds, transactionEditable)
 	newTransactionResp.Pictures = a.GetTransactionPictureInfoResponseList(newPictureInfos)
 
+	if splits != nil {
+		newTransactionResp.Splits = models.GetTransactionSplitInfoResponses(splits)
+	} else {
+		allTransactionSplits, err := a.transactionSplits.GetAllSplitsOfTransactions(c, uid, []int64{newTransaction.TransactionId})
+
+		if err != nil {
+			log.Warnf(c, "[transactions.TransactionModifyHandler] failed to get transaction splits for user \"uid:%d\", because %s", uid, err.Error())
+		} else {
+			newTransactionResp.Splits = models.GetTransactionSplitInfoResponses(allTransactionSplits[newTransaction.TransactionId])
+		}
+	}
+
 	return newTransactionResp, nil
 }
 
@@ -1754,6 +2213,7 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 	}
 
 	newTransactionTagIdsMap := make(map[int][]int64, len(transactionImportReq.Transactions))
+	newTransactionSplitsMap := make(map[int][]*models.TransactionSplit)
 
 	for i := 0; i < len(transactionImportReq.Transactions); i++ {
 		transactionCreateReq := transactionImportReq.Transactions[i]
@@ -1768,6 +2228,13 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 			return nil, errs.ErrTransactionHasTooManyTags
 		}
 
+		splits, err := a.createNewTransactionSplitModels(transactionCreateReq.Splits)
+
+		if err != nil {
+			log.Warnf(c, "[transactions.TransactionImportHandler] parse splits failed of transaction \"index:%d\", because %s", i, err.Error())
+			return nil, errs.Or(err, errs.ErrTransactionTagIdInvalid)
+		}
+
 		if transactionCreateReq.Type < models.TRANSACTION_TYPE_MODIFY_BALANCE || transactionCreateReq.Type > models.TRANSACTION_TYPE_TRANSFER {
 			log.Warnf(c, "[transactions.TransactionImportHandler] transaction type of transaction \"index:%d\" is invalid", i)
 			return nil, errs.ErrTransactionTypeInvalid
@@ -1792,6 +2259,10 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		}
 
 		newTransactionTagIdsMap[i] = tagIds
+
+		if len(splits) > 0 {
+			newTransactionSplitsMap[i] = splits
+		}
 	}
 
 	user, err := a.users.GetUserById(c, uid)
@@ -1822,7 +2293,7 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		newTransactions[i] = transaction
 	}
 
-	err = a.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, func(currentProcess float64) {
+	err = a.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, newTransactionSplitsMap, func(currentProcess float64) {
 		a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, fmt.Sprintf("processing:%.2f", currentProcess))
 	})
 	count := len(newTransactions)
@@ -1890,6 +2361,27 @@ func (a *TransactionsApi) TransactionImportProcessHandler(c *core.WebContext) (a
 	return process, nil
 }
 
+func (a *TransactionsApi) getDefaultCurrencyAmountConverter(c *core.WebContext, uid int64, startTime int64, endTime int64) (models.TransactionAmountConverter, *errs.Error) {
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transactions.getDefaultCurrencyAmountConverter] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getDefaultCurrencyAmountConverter] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return a.GetTransactionAmountConverter(c, uid, user.DefaultCurrency, accounts, startTime, endTime), nil
+}
+
 func (a *TransactionsApi) filterTransactions(c *core.WebContext, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account) []*models.Transaction {
 	finalTransactions := make([]*models.Transaction, 0, len(transactions))
 
@@ -1969,6 +2461,13 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 		return nil, err
 	}
 
+	allTransactionSplits, err := a.transactionSplits.GetAllSplitsOfTransactions(c, uid, transactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getTransactionResponseListResult] failed to get transactions splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
 	var categoryMap map[int64]*models.TransactionCategory
 	var tagMap map[int64]*models.TransactionTag
 	var pictureInfoMap map[int64][]*models.TransactionPictureInfo
@@ -2012,6 +2511,7 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 		transactionEditable := transaction.IsEditable(user, clientTimezone, allAccounts[transaction.AccountId], allAccounts[transaction.RelatedAccountId])
 		transactionTagIds := allTransactionTagIds[transaction.TransactionId]
 		result[i] = transaction.ToTransactionInfoResponse(transactionTagIds, transactionEditable)
+		result[i].Splits = models.GetTransactionSplitInfoResponses(allTransactionSplits[transaction.TransactionId])
 
 		if !trimAccount {
 			if sourceAccount := allAccounts[transaction.AccountId]; sourceAccount != nil {
@@ -2047,6 +2547,42 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 	return result, nil
 }
 
+func (a *TransactionsApi) createNewTransactionSplitModels(splitCreateReqs []*models.TransactionSplitCreateRequest) ([]*models.TransactionSplit, error) {
+	if splitCreateReqs == nil {
+		return nil, nil
+	}
+
+	if len(splitCreateReqs) > models.MaximumSplitsCountOfTransaction {
+		return nil, errs.ErrTransactionSplitsCountInvalid
+	}
+
+	splits := make([]*models.TransactionSplit, len(splitCreateReqs))
+
+	for i := 0; i < len(splitCreateReqs); i++ {
+		splitCreateReq := splitCreateReqs[i]
+		tagIds, err := utils.StringArrayToInt64Array(splitCreateReq.TagIds)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tagIds = utils.ToUniqueInt64Slice(tagIds)
+
+		if len(tagIds) > models.MaximumTagsCountOfTransaction {
+			return nil, errs.ErrTransactionHasTooManyTags
+		}
+
+		splits[i] = &models.TransactionSplit{
+			CategoryId: splitCreateReq.CategoryId,
+			Amount:     splitCreateReq.Amount,
+			TagIds:     strings.Join(utils.Int64ArrayToStringArray(tagIds), ","),
+			Comment:    splitCreateReq.Comment,
+		}
+	}
+
+	return splits, nil
+}
+
 func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreateReq *models.TransactionCreateRequest, clientIp string) *models.Transaction {
 	var transactionDbType models.TransactionDbType
 
@@ -2068,7 +2604,9 @@ func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreate
 		TimezoneUtcOffset: transactionCreateReq.UtcOffset,
 		AccountId:         transactionCreateReq.SourceAccountId,
 		Amount:            transactionCreateReq.SourceAmount,
+		HoldingAccountId:  transactionCreateReq.HoldingAccountId,
 		HideAmount:        transactionCreateReq.HideAmount,
+		Reimbursable:      transactionCreateReq.Reimbursable,
 		Comment:           transactionCreateReq.Comment,
 		CreatedIp:         clientIp,
 	}
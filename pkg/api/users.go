@@ -22,9 +22,11 @@ import (
 type UsersApi struct {
 	ApiUsingConfig
 	ApiWithUserInfo
-	users    *services.UserService
-	tokens   *services.TokenService
-	accounts *services.AccountService
+	users            *services.UserService
+	tokens           *services.TokenService
+	accounts         *services.AccountService
+	stocks           *services.StockService
+	cryptocurrencies *services.CryptocurrencyService
 }
 
 // Initialize a user api singleton instance
@@ -41,9 +43,11 @@ var (
 				container: avatars.Container,
 			},
 		},
-		users:    services.Users,
-		tokens:   services.Tokens,
-		accounts: services.Accounts,
+		users:            services.Users,
+		tokens:           services.Tokens,
+		accounts:         services.Accounts,
+		stocks:           services.Stocks,
+		cryptocurrencies: services.Cryptocurrencies,
 	}
 )
 
@@ -92,6 +96,18 @@ func (a *UsersApi) UserRegisterHandler(c *core.WebContext) (any, *errs.Error) {
 
 	log.Infof(c, "[users.UserRegisterHandler] user \"%s\" has registered successfully, uid is %d", user.Username, user.Uid)
 
+	err = a.stocks.InitUserStocks(c, user.Uid)
+
+	if err != nil {
+		log.Warnf(c, "[users.UserRegisterHandler] failed to initialize stock watchlist for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
+	err = a.cryptocurrencies.InitUserCryptocurrencies(c, user.Uid)
+
+	if err != nil {
+		log.Warnf(c, "[users.UserRegisterHandler] failed to initialize cryptocurrency watchlist for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
 	presetCategoriesSaved := false
 
 	if len(userRegisterReq.Categories) > 0 {
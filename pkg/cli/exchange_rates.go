@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+const exchangeRatesBackfillDateFormat = "2006-01-02"
+
+// ExchangeRatesCli represents exchange rates cli
+type ExchangeRatesCli struct {
+	CliUsingConfig
+	exchangeRateHistories *services.ExchangeRateHistoryService
+}
+
+// Initialize an exchange rates cli singleton instance
+var (
+	ExchangeRates = &ExchangeRatesCli{
+		CliUsingConfig: CliUsingConfig{
+			container: settings.Container,
+		},
+		exchangeRateHistories: services.ExchangeRateHistories,
+	}
+)
+
+// BackfillExchangeRates fetches the historical exchange rates between the start date and the end date (both in yyyy-mm-dd format and inclusive)
+// from the current exchange rates data source and saves them to the exchange rate history, returns the count of saved days
+func (l *ExchangeRatesCli) BackfillExchangeRates(c *core.CliContext, startDate string, endDate string) (int, error) {
+	startTime, err := time.ParseInLocation(exchangeRatesBackfillDateFormat, startDate, time.UTC)
+
+	if err != nil {
+		log.CliErrorf(c, "[exchange_rates.BackfillExchangeRates] start date \"%s\" is invalid", startDate)
+		return 0, errs.ErrInvalidExchangeRatesDateRange
+	}
+
+	endTime, err := time.ParseInLocation(exchangeRatesBackfillDateFormat, endDate, time.UTC)
+
+	if err != nil {
+		log.CliErrorf(c, "[exchange_rates.BackfillExchangeRates] end date \"%s\" is invalid", endDate)
+		return 0, errs.ErrInvalidExchangeRatesDateRange
+	}
+
+	if startTime.After(endTime) {
+		log.CliErrorf(c, "[exchange_rates.BackfillExchangeRates] start date \"%s\" is later than end date \"%s\"", startDate, endDate)
+		return 0, errs.ErrInvalidExchangeRatesDateRange
+	}
+
+	exchangeRateResponses, err := exchangerates.Container.GetHistoricalExchangeRates(c, startTime, endTime)
+
+	if err != nil {
+		log.CliErrorf(c, "[exchange_rates.BackfillExchangeRates] failed to get historical exchange rates from \"%s\" to \"%s\", because %s", startDate, endDate, err.Error())
+		return 0, err
+	}
+
+	for i := 0; i < len(exchangeRateResponses); i++ {
+		err = l.exchangeRateHistories.SaveLatestExchangeRates(c, exchangeRateResponses[i])
+
+		if err != nil {
+			log.CliErrorf(c, "[exchange_rates.BackfillExchangeRates] failed to save exchange rates of \"%s\", because %s", time.Unix(exchangeRateResponses[i].UpdateTime, 0).UTC().Format(exchangeRatesBackfillDateFormat), err.Error())
+			return i, err
+		}
+	}
+
+	return len(exchangeRateResponses), nil
+}
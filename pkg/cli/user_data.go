@@ -26,10 +26,13 @@ type UserDataCli struct {
 	transactions            *services.TransactionService
 	categories              *services.TransactionCategoryService
 	tags                    *services.TransactionTagService
+	splits                  *services.TransactionSplitService
 	users                   *services.UserService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
 	tokens                  *services.TokenService
 	forgetPasswords         *services.ForgetPasswordService
+	stocks                  *services.StockService
+	cryptocurrencies        *services.CryptocurrencyService
 }
 
 // Initialize a user data cli singleton instance
@@ -42,10 +45,13 @@ var (
 		transactions:            services.Transactions,
 		categories:              services.TransactionCategories,
 		tags:                    services.TransactionTags,
+		splits:                  services.TransactionSplits,
 		users:                   services.Users,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
 		tokens:                  services.Tokens,
 		forgetPasswords:         services.ForgetPasswords,
+		stocks:                  services.Stocks,
+		cryptocurrencies:        services.Cryptocurrencies,
 	}
 )
 
@@ -101,6 +107,18 @@ func (l *UserDataCli) AddNewUser(c *core.CliContext, username string, email stri
 
 	log.CliInfof(c, "[user_data.AddNewUser] user \"%s\" has add successfully, uid is %d", user.Username, user.Uid)
 
+	err = l.stocks.InitUserStocks(c, user.Uid)
+
+	if err != nil {
+		log.CliWarnf(c, "[user_data.AddNewUser] failed to initialize stock watchlist for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
+	err = l.cryptocurrencies.InitUserCryptocurrencies(c, user.Uid)
+
+	if err != nil {
+		log.CliWarnf(c, "[user_data.AddNewUser] failed to initialize cryptocurrency watchlist for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
 	return user, nil
 }
 
@@ -777,13 +795,20 @@ func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fil
 		return nil, err
 	}
 
+	allSplits, err := l.splits.GetAllSplitsMapOfAllTransactions(c, uid)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get transaction splits for user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
 	dataExporter := converters.GetTransactionDataExporter(fileType)
 
 	if dataExporter == nil {
 		return nil, errs.ErrNotImplemented
 	}
 
-	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexesMap)
+	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexesMap, allSplits)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get csv format exported data for \"%s\", because %s", username, err.Error())
@@ -869,7 +894,7 @@ func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fil
 		return errs.ErrOperationFailed
 	}
 
-	err = l.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, nil)
+	err = l.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, nil, nil)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ImportTransaction] failed to create transaction, because %s", err.Error())
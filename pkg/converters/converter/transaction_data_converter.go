@@ -10,7 +10,7 @@ import (
 // TransactionDataExporter defines the structure of transaction data exporter
 type TransactionDataExporter interface {
 	// ToExportedContent returns the exported data
-	ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error)
+	ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64][]*models.TransactionSplit) ([]byte, error)
 }
 
 // TransactionDataImporter defines the structure of transaction data importer
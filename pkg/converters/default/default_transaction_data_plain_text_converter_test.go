@@ -121,12 +121,121 @@ func TestDefaultTransactionDataCSVFileConverterToExportedContent(t *testing.T) {
 		"2024-09-01 12:34:56,+08:00,Income,Test Category,Test Sub Category,Test Account,CNY,123.45,,,,123.450000 45.670000,Test Tag;Test Tag2,Hello World\n" +
 		"2024-09-01 12:34:56,+00:00,Expense,Test Category2,Test Sub Category2,Test Account,CNY,-0.10,,,,,Test Tag,Foo#Bar\n" +
 		"2024-09-01 12:34:56,-05:00,Transfer,Test Category3,Test Sub Category3,Test Account,CNY,123.45,Test Account2,USD,17.35,,Test Tag2,T\te s t test\n"
-	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes)
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
 
 	assert.Nil(t, err)
 	assert.Equal(t, expectedContent, string(actualContent))
 }
 
+func TestDefaultTransactionDataCSVFileConverterToExportedContent_SplitTransaction(t *testing.T) {
+	exporter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	transactions := make([]*models.Transaction, 1)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        2,
+		AccountId:         1,
+		Amount:            3000,
+		Comment:           "Supermarket",
+	}
+
+	accountMap := make(map[int64]*models.Account, 1)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "Test Account",
+		Currency:  "CNY",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 3)
+	categoryMap[1] = &models.TransactionCategory{
+		CategoryId: 1,
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+		Name:       "Test Category",
+	}
+	categoryMap[2] = &models.TransactionCategory{
+		CategoryId:       2,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 1,
+		Name:             "Food",
+	}
+	categoryMap[3] = &models.TransactionCategory{
+		CategoryId:       3,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 1,
+		Name:             "Pharmacy",
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, 2)
+	tagMap[1] = &models.TransactionTag{
+		TagId: 1,
+		Name:  "Test Tag",
+	}
+	tagMap[2] = &models.TransactionTag{
+		TagId: 2,
+		Name:  "Test Tag2",
+	}
+
+	allTagIndexes := make(map[int64][]int64, 1)
+	allTagIndexes[1] = []int64{1}
+
+	allSplits := make(map[int64][]*models.TransactionSplit, 1)
+	allSplits[1] = []*models.TransactionSplit{
+		{
+			TransactionId: 1,
+			CategoryId:    2,
+			Amount:        1800,
+			TagIds:        "1",
+		},
+		{
+			TransactionId: 1,
+			CategoryId:    3,
+			Amount:        1200,
+			TagIds:        "1,2",
+			Comment:       "Medicine",
+		},
+	}
+
+	expectedContent := "Time,Timezone,Type,Category,Sub Category,Account,Account Currency,Amount,Account2,Account2 Currency,Account2 Amount,Geographic Location,Tags,Description\n" +
+		"2024-09-01 12:34:56,+00:00,Expense,Test Category,Food,Test Account,CNY,18.00,,,,,Test Tag,Supermarket\n" +
+		"2024-09-01 12:34:56,+00:00,Expense,Test Category,Pharmacy,Test Account,CNY,12.00,,,,,Test Tag;Test Tag2,Medicine\n"
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, allSplits)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestDefaultTransactionDataCSVFileConverterParseImportedData_ExportedSplitTransaction(t *testing.T) {
+	importer := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	// the split lines are exported as separate rows without the parent transaction, so they are imported as separate transactions
+	allNewTransactions, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte("Time,Timezone,Type,Category,Sub Category,Account,Account Currency,Amount,Account2,Account2 Currency,Account2 Amount,Geographic Location,Tags,Description\n"+
+		"2024-09-01 12:34:56,+00:00,Expense,Test Category,Food,Test Account,CNY,18.00,,,,,Test Tag,Supermarket\n"+
+		"2024-09-01 12:34:56,+00:00,Expense,Test Category,Pharmacy,Test Account,CNY,12.00,,,,,Test Tag;Test Tag2,Medicine\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allNewTransactions))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1800), allNewTransactions[0].Amount)
+	assert.Equal(t, "Food", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, "Supermarket", allNewTransactions[0].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1200), allNewTransactions[1].Amount)
+	assert.Equal(t, "Pharmacy", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Medicine", allNewTransactions[1].Comment)
+}
+
 func TestDefaultTransactionDataCSVFileConverterParseImportedData_MinimumValidData(t *testing.T) {
 	importer := DefaultTransactionDataCSVFileConverter
 	context := core.NewNullContext()
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 )
@@ -58,6 +59,27 @@ func (f FiscalYearStart) GetMonthDay() (uint8, uint8, error) {
 	return month, day, nil
 }
 
+// GetFiscalYearTimeRange returns the start unix time (inclusive) and the end unix time (exclusive) of the specified fiscal year,
+// the fiscal year is named by the calendar year in which it ends
+func (f FiscalYearStart) GetFiscalYearTimeRange(fiscalYear int32, timezone *time.Location) (int64, int64, error) {
+	month, day, err := f.GetMonthDay()
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	startYear := int(fiscalYear)
+
+	if month != 1 || day != 1 {
+		startYear = startYear - 1
+	}
+
+	startTime := time.Date(startYear, time.Month(month), int(day), 0, 0, 0, 0, timezone)
+	endTime := startTime.AddDate(1, 0, 0)
+
+	return startTime.Unix(), endTime.Unix(), nil
+}
+
 // String returns a string representation of FiscalYearStart in MM/DD format
 func (f FiscalYearStart) String() string {
 	month, day, err := f.GetMonthDay()
@@ -2,6 +2,7 @@ package core
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -120,6 +121,29 @@ func TestFiscalYearStart_String(t *testing.T) {
 	}
 }
 
+func TestFiscalYearStart_GetFiscalYearTimeRange(t *testing.T) {
+	testCases := []struct {
+		fiscalYearStart FiscalYearStart
+		fiscalYear      int32
+		expectedStart   string
+		expectedEnd     string
+	}{
+		{0x0101, 2024, "2024-01-01T00:00:00Z", "2025-01-01T00:00:00Z"}, // January 1st
+		{0x0401, 2025, "2024-04-01T00:00:00Z", "2025-04-01T00:00:00Z"}, // April 1st
+		{0x0706, 2025, "2024-07-06T00:00:00Z", "2025-07-06T00:00:00Z"}, // July 6th
+	}
+
+	for _, tc := range testCases {
+		startTime, endTime, err := tc.fiscalYearStart.GetFiscalYearTimeRange(tc.fiscalYear, time.UTC)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expectedStart, time.Unix(startTime, 0).UTC().Format(time.RFC3339))
+		assert.Equal(t, tc.expectedEnd, time.Unix(endTime, 0).UTC().Format(time.RFC3339))
+	}
+
+	_, _, err := FISCAL_YEAR_START_INVALID.GetFiscalYearTimeRange(2025, time.UTC)
+	assert.Equal(t, errs.ErrFormatInvalid, err)
+}
+
 func TestFiscalYearStartConstants(t *testing.T) {
 	assert.Equal(t, FiscalYearStart(0xFFFF), FISCAL_YEAR_START_INVALID)
 	assert.Equal(t, FiscalYearStart(0x0101), FISCAL_YEAR_START_DEFAULT)
@@ -96,6 +96,10 @@ func (c *CronJobSchedulerContainer) registerAllJobs(ctx core.Context, config *se
 	if config.EnableAutoUpdateExchangeRates {
 		Container.registerIntervalJob(ctx, UpdateExchangeRatesJob)
 	}
+
+	if config.EnableSyncWalletBalances && config.WalletBalanceReader != "" {
+		Container.registerIntervalJob(ctx, SyncWalletBalancesJob)
+	}
 }
 
 func (c *CronJobSchedulerContainer) registerIntervalJob(ctx core.Context, job *CronJob) {
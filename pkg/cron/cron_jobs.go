@@ -10,6 +10,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 	"github.com/mayswind/ezbookkeeping/pkg/stocks"
+	"github.com/mayswind/ezbookkeeping/pkg/wallets"
 )
 
 // RemoveExpiredTokensJob represents the cron job which periodically remove expired user tokens from the database
@@ -39,7 +40,7 @@ var CreateScheduledTransactionJob = &CronJob{
 // UpdateCryptocurrencyPricesJob represents the cron job which periodically update cryptocurrency prices
 var UpdateCryptocurrencyPricesJob = &CronJob{
 	Name:        "UpdateCryptocurrencyPrices",
-	Description: "Periodically update cryptocurrency prices.",
+	Description: "Periodically update cryptocurrency prices, save the daily price history and evaluate price alerts.",
 	Period: CronJobIntervalPeriod{
 		Interval: 5 * time.Minute,
 	},
@@ -50,7 +51,7 @@ var UpdateCryptocurrencyPricesJob = &CronJob{
 			return err
 		}
 
-		cryptos, err := services.Cryptocurrencies.GetAllVisibleCryptocurrencies(c)
+		cryptos, err := services.Cryptocurrencies.GetAllWatchedCryptocurrencies(c, true)
 
 		if err != nil {
 			return err
@@ -61,26 +62,43 @@ var UpdateCryptocurrencyPricesJob = &CronJob{
 			symbols[i] = crypto.Symbol
 		}
 
-		_, err = cryptocurrency.Container.GetLatestCryptocurrencyPrices(c, 0, config, symbols)
-		return err
+		priceResponse, err := cryptocurrency.Container.GetLatestCryptocurrencyPrices(c, 0, config, symbols)
+
+		if err != nil {
+			return err
+		}
+
+		err = services.CryptocurrencyPriceHistories.SaveLatestCryptocurrencyPrices(c, config.DataSource, priceResponse)
+
+		if err != nil {
+			return err
+		}
+
+		return services.PriceAlerts.EvaluateCryptocurrencyPriceAlerts(c, config.DataSource, priceResponse)
 	},
 }
 
 // UpdateStockPricesJob represents the cron job which periodically update stock prices
 var UpdateStockPricesJob = &CronJob{
 	Name:        "UpdateStockPrices",
-	Description: "Periodically update stock prices.",
+	Description: "Periodically update stock prices, save the daily price history and evaluate price alerts.",
 	Period: CronJobIntervalPeriod{
 		Interval: 5 * time.Minute,
 	},
 	Run: func(c *core.CronContext) error {
-		config, err := services.ExternalDataSourceConfigs.GetConfig(c, models.EXTERNAL_DATA_SOURCE_TYPE_STOCK)
+		_, err := services.StockDataSourceRoutes.RefreshSymbolMarkets(c)
 
 		if err != nil {
 			return err
 		}
 
-		stockList, err := services.Stocks.GetAllVisibleStocks(c)
+		routing, err := services.StockDataSourceRoutes.GetRouting(c)
+
+		if err != nil {
+			return err
+		}
+
+		stockList, err := services.Stocks.GetAllWatchedStocks(c, true)
 
 		if err != nil {
 			return err
@@ -91,20 +109,57 @@ var UpdateStockPricesJob = &CronJob{
 			symbols[i] = stock.Symbol
 		}
 
-		_, err = stocks.Container.GetLatestStockPrices(c, 0, config, symbols)
-		return err
+		priceResponse, err := stocks.Container.GetLatestStockPrices(c, 0, routing, symbols)
+
+		if err != nil {
+			return err
+		}
+
+		err = services.StockPriceHistories.SaveLatestStockPrices(c, priceResponse.DataSource, priceResponse)
+
+		if err != nil {
+			return err
+		}
+
+		return services.PriceAlerts.EvaluateStockPriceAlerts(c, priceResponse.DataSource, priceResponse)
 	},
 }
 
 // UpdateExchangeRatesJob represents the cron job which periodically update exchange rates
 var UpdateExchangeRatesJob = &CronJob{
 	Name:        "UpdateExchangeRates",
-	Description: "Periodically update exchange rates.",
+	Description: "Periodically update exchange rates and save the daily exchange rate history.",
 	Period: CronJobIntervalPeriod{
 		Interval: 5 * time.Minute,
 	},
 	Run: func(c *core.CronContext) error {
-		_, err := exchangerates.Container.GetLatestExchangeRates(c, 0, settings.Container.GetCurrentConfig())
-		return err
+		// user custom exchange rates are maintained by each user and are not updated periodically
+		if exchangerates.Container.IsUserCustomDataSource() {
+			return nil
+		}
+
+		exchangeRateResponse, err := exchangerates.Container.GetLatestExchangeRates(c, 0, settings.Container.GetCurrentConfig())
+
+		if err != nil {
+			return err
+		}
+
+		return services.ExchangeRateHistories.SaveLatestExchangeRates(c, exchangeRateResponse)
+	},
+}
+
+// SyncWalletBalancesJob represents the cron job which periodically synchronize the balances of cryptocurrency accounts from their wallet addresses
+var SyncWalletBalancesJob = &CronJob{
+	Name:        "SyncWalletBalances",
+	Description: "Periodically read the balances of wallet addresses and record the differences as income or expense adjustments.",
+	Period: CronJobIntervalPeriod{
+		Interval: 1 * time.Hour,
+	},
+	Run: func(c *core.CronContext) error {
+		if !wallets.Container.IsEnabled() {
+			return nil
+		}
+
+		return wallets.Container.SyncAllWalletAccountBalances(c)
 	},
 }
@@ -0,0 +1,134 @@
+package cryptocurrency
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const (
+	binanceTickerPriceApiUrl = "https://api.binance.com/api/v3/ticker/price"
+	binanceQuoteCurrency     = "USDT"
+)
+
+// BinanceDataSource defines the structure of Binance public ticker data source
+type BinanceDataSource struct {
+}
+
+// BinanceTickerPrice represents the ticker price of one trading pair returned by Binance API
+type BinanceTickerPrice struct {
+	Symbol string `json:"symbol"`
+	Price  string `json:"price"`
+}
+
+// BinanceTickerPriceResponse represents the response from Binance ticker price API
+type BinanceTickerPriceResponse []*BinanceTickerPrice
+
+// ToLatestCryptocurrencyPriceResponse converts Binance response to LatestCryptocurrencyPriceResponse
+func (r BinanceTickerPriceResponse) ToLatestCryptocurrencyPriceResponse(c core.Context) *models.LatestCryptocurrencyPriceResponse {
+	prices := make(models.LatestCryptocurrencyPriceSlice, 0, len(r))
+
+	for i := 0; i < len(r); i++ {
+		tickerPrice := r[i]
+
+		if tickerPrice == nil || !strings.HasSuffix(tickerPrice.Symbol, binanceQuoteCurrency) {
+			continue
+		}
+
+		symbol := strings.TrimSuffix(tickerPrice.Symbol, binanceQuoteCurrency)
+
+		if symbol == "" {
+			continue
+		}
+
+		if _, err := strconv.ParseFloat(tickerPrice.Price, 64); err != nil {
+			log.Warnf(c, "[binance_datasource.ToLatestCryptocurrencyPriceResponse] failed to parse price of \"%s\", because %s", tickerPrice.Symbol, err.Error())
+			continue
+		}
+
+		prices = append(prices, &models.LatestCryptocurrencyPrice{
+			Symbol: symbol,
+			Price:  tickerPrice.Price,
+		})
+	}
+
+	return &models.LatestCryptocurrencyPriceResponse{
+		DataSource:   "Binance",
+		ReferenceUrl: "https://www.binance.com/en/markets/overview",
+		UpdateTime:   time.Now().Unix(),
+		BaseCurrency: binanceQuoteCurrency,
+		Prices:       prices,
+	}
+}
+
+// BuildRequests builds the http requests, each symbol is requested separately because Binance rejects the whole request if any symbol is invalid
+func (s *BinanceDataSource) BuildRequests(symbols []string, apiKey string) ([]*http.Request, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	requests := make([]*http.Request, 0, len(symbols))
+
+	for i := 0; i < len(symbols); i++ {
+		symbol := strings.ToUpper(strings.TrimSpace(symbols[i]))
+
+		if symbol == "" || symbol == binanceQuoteCurrency {
+			continue
+		}
+
+		req, err := http.NewRequest("GET", binanceTickerPriceApiUrl+"?symbol="+url.QueryEscape(symbol+binanceQuoteCurrency), nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, req)
+	}
+
+	if len(requests) < 1 {
+		return nil, errs.ErrInvalidCryptocurrencySymbol
+	}
+
+	return requests, nil
+}
+
+// Parse parses the response content
+func (s *BinanceDataSource) Parse(c core.Context, content []byte) (*models.LatestCryptocurrencyPriceResponse, error) {
+	var response BinanceTickerPriceResponse
+	trimmedContent := bytes.TrimSpace(content)
+
+	// the ticker price of one trading pair is returned as an object when the symbol is specified
+	if len(trimmedContent) > 0 && trimmedContent[0] == '{' {
+		tickerPrice := &BinanceTickerPrice{}
+		err := json.Unmarshal(trimmedContent, tickerPrice)
+
+		if err != nil {
+			return nil, err
+		} else if tickerPrice.Symbol == "" {
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		response = BinanceTickerPriceResponse{tickerPrice}
+	} else {
+		err := json.Unmarshal(trimmedContent, &response)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(response) < 1 {
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return response.ToLatestCryptocurrencyPriceResponse(c), nil
+}
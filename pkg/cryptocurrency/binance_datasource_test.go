@@ -0,0 +1,111 @@
+package cryptocurrency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const binanceMinimumRequiredContent = "[" +
+	"{\"symbol\":\"ETHBTC\",\"price\":\"0.05123000\"}," +
+	"{\"symbol\":\"BTCUSDT\",\"price\":\"65432.10000000\"}," +
+	"{\"symbol\":\"ETHUSDT\",\"price\":\"3456.78000000\"}," +
+	"{\"symbol\":\"BNBBTC\",\"price\":\"0.00890000\"}," +
+	"{\"symbol\":\"SOLUSDT\",\"price\":\"145.67000000\"}" +
+	"]"
+
+func TestBinanceDataSource_BuildRequests(t *testing.T) {
+	dataSource := &BinanceDataSource{}
+
+	requests, err := dataSource.BuildRequests([]string{"btc", "ETH", "USDT", " "}, "")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "https://api.binance.com/api/v3/ticker/price?symbol=BTCUSDT", requests[0].URL.String())
+	assert.Equal(t, "https://api.binance.com/api/v3/ticker/price?symbol=ETHUSDT", requests[1].URL.String())
+
+	_, err = dataSource.BuildRequests([]string{" "}, "")
+	assert.NotEqual(t, nil, err)
+
+	requests, err = dataSource.BuildRequests([]string{}, "")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(requests))
+}
+
+func TestBinanceDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &BinanceDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestCryptocurrencyPriceResponse, err := dataSource.Parse(context, []byte(binanceMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "USDT", actualLatestCryptocurrencyPriceResponse.BaseCurrency)
+	assert.Equal(t, "Binance", actualLatestCryptocurrencyPriceResponse.DataSource)
+}
+
+func TestBinanceDataSource_StandardDataExtractPrices(t *testing.T) {
+	dataSource := &BinanceDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestCryptocurrencyPriceResponse, err := dataSource.Parse(context, []byte(binanceMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(actualLatestCryptocurrencyPriceResponse.Prices))
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "BTC",
+		Price:  "65432.10000000",
+	})
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "ETH",
+		Price:  "3456.78000000",
+	})
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "SOL",
+		Price:  "145.67000000",
+	})
+}
+
+func TestBinanceDataSource_SingleTickerPriceContent(t *testing.T) {
+	dataSource := &BinanceDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestCryptocurrencyPriceResponse, err := dataSource.Parse(context, []byte("{\"symbol\":\"BTCUSDT\",\"price\":\"65432.10000000\"}"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(actualLatestCryptocurrencyPriceResponse.Prices))
+	assert.Equal(t, "BTC", actualLatestCryptocurrencyPriceResponse.Prices[0].Symbol)
+	assert.Equal(t, "65432.10000000", actualLatestCryptocurrencyPriceResponse.Prices[0].Price)
+}
+
+func TestBinanceDataSource_InvalidPrice(t *testing.T) {
+	dataSource := &BinanceDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestCryptocurrencyPriceResponse, err := dataSource.Parse(context, []byte("[{\"symbol\":\"BTCUSDT\",\"price\":\"null\"},{\"symbol\":\"ETHUSDT\",\"price\":\"3456.78\"}]"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(actualLatestCryptocurrencyPriceResponse.Prices))
+	assert.Equal(t, "ETH", actualLatestCryptocurrencyPriceResponse.Prices[0].Symbol)
+}
+
+func TestBinanceDataSource_BlankContent(t *testing.T) {
+	dataSource := &BinanceDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBinanceDataSource_EmptyArrayContent(t *testing.T) {
+	dataSource := &BinanceDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("[]"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBinanceDataSource_ErrorContent(t *testing.T) {
+	dataSource := &BinanceDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"code\":-1121,\"msg\":\"Invalid symbol.\"}"))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,100 @@
+package cryptocurrency
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const (
+	coinbaseSpotPriceApiUrlFormat = "https://api.coinbase.com/v2/prices/%s-%s/spot"
+	coinbaseQuoteCurrency         = "USD"
+)
+
+// CoinbaseDataSource defines the structure of Coinbase data source
+type CoinbaseDataSource struct {
+}
+
+// CoinbaseSpotPriceResponse represents the response from Coinbase spot price API
+type CoinbaseSpotPriceResponse struct {
+	Data *struct {
+		Amount   string `json:"amount"`
+		Base     string `json:"base"`
+		Currency string `json:"currency"`
+	} `json:"data"`
+}
+
+// ToLatestCryptocurrencyPriceResponse converts Coinbase response to LatestCryptocurrencyPriceResponse
+func (r *CoinbaseSpotPriceResponse) ToLatestCryptocurrencyPriceResponse() *models.LatestCryptocurrencyPriceResponse {
+	return &models.LatestCryptocurrencyPriceResponse{
+		DataSource:   "Coinbase",
+		ReferenceUrl: "https://www.coinbase.com/explore",
+		UpdateTime:   time.Now().Unix(),
+		BaseCurrency: strings.ToUpper(r.Data.Currency),
+		Prices: models.LatestCryptocurrencyPriceSlice{
+			{
+				Symbol: strings.ToUpper(r.Data.Base),
+				Price:  r.Data.Amount,
+			},
+		},
+	}
+}
+
+// BuildRequests builds the http requests, Coinbase spot price API only supports one currency pair per request
+func (s *CoinbaseDataSource) BuildRequests(symbols []string, apiKey string) ([]*http.Request, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	requests := make([]*http.Request, 0, len(symbols))
+
+	for i := 0; i < len(symbols); i++ {
+		symbol := strings.ToUpper(strings.TrimSpace(symbols[i]))
+
+		if symbol == "" {
+			continue
+		}
+
+		req, err := http.NewRequest("GET", fmt.Sprintf(coinbaseSpotPriceApiUrlFormat, url.PathEscape(symbol), coinbaseQuoteCurrency), nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, req)
+	}
+
+	if len(requests) < 1 {
+		return nil, errs.ErrInvalidCryptocurrencySymbol
+	}
+
+	return requests, nil
+}
+
+// Parse parses the response content
+func (s *CoinbaseDataSource) Parse(c core.Context, content []byte) (*models.LatestCryptocurrencyPriceResponse, error) {
+	response := &CoinbaseSpotPriceResponse{}
+	err := json.Unmarshal(content, response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Data == nil || response.Data.Base == "" || response.Data.Currency == "" {
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	if _, err := strconv.ParseFloat(response.Data.Amount, 64); err != nil {
+		return nil, err
+	}
+
+	return response.ToLatestCryptocurrencyPriceResponse(), nil
+}
@@ -0,0 +1,73 @@
+package cryptocurrency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const coinbaseMinimumRequiredContent = "{\"data\":{\"amount\":\"65432.105\",\"base\":\"BTC\",\"currency\":\"USD\"}}"
+
+func TestCoinbaseDataSource_BuildRequests(t *testing.T) {
+	dataSource := &CoinbaseDataSource{}
+
+	requests, err := dataSource.BuildRequests([]string{"btc", "ETH", " "}, "")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "https://api.coinbase.com/v2/prices/BTC-USD/spot", requests[0].URL.String())
+	assert.Equal(t, "https://api.coinbase.com/v2/prices/ETH-USD/spot", requests[1].URL.String())
+
+	_, err = dataSource.BuildRequests([]string{" "}, "")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCoinbaseDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &CoinbaseDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestCryptocurrencyPriceResponse, err := dataSource.Parse(context, []byte(coinbaseMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "USD", actualLatestCryptocurrencyPriceResponse.BaseCurrency)
+	assert.Equal(t, "Coinbase", actualLatestCryptocurrencyPriceResponse.DataSource)
+}
+
+func TestCoinbaseDataSource_StandardDataExtractPrices(t *testing.T) {
+	dataSource := &CoinbaseDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestCryptocurrencyPriceResponse, err := dataSource.Parse(context, []byte(coinbaseMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.LatestCryptocurrencyPriceSlice{
+		{
+			Symbol: "BTC",
+			Price:  "65432.105",
+		},
+	}, actualLatestCryptocurrencyPriceResponse.Prices)
+}
+
+func TestCoinbaseDataSource_BlankContent(t *testing.T) {
+	dataSource := &CoinbaseDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCoinbaseDataSource_ErrorContent(t *testing.T) {
+	dataSource := &CoinbaseDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"errors\":[{\"id\":\"not_found\",\"message\":\"Invalid currency\"}]}"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCoinbaseDataSource_InvalidPrice(t *testing.T) {
+	dataSource := &CoinbaseDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"data\":{\"amount\":\"abc\",\"base\":\"BTC\",\"currency\":\"USD\"}}"))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,181 @@
+package cryptocurrency
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const (
+	coinMarketCapQuotesLatestApiUrl = "https://pro-api.coinmarketcap.com/v2/cryptocurrency/quotes/latest"
+	coinMarketCapQuoteCurrency      = "USD"
+	coinMarketCapApiKeyHeaderName   = "X-CMC_PRO_API_KEY"
+)
+
+// CoinMarketCapDataSource defines the structure of CoinMarketCap data source
+type CoinMarketCapDataSource struct {
+}
+
+// CoinMarketCapQuote represents the quote of one cryptocurrency in one currency returned by CoinMarketCap API
+type CoinMarketCapQuote struct {
+	Price       float64 `json:"price"`
+	LastUpdated string  `json:"last_updated"`
+}
+
+// CoinMarketCapCryptocurrency represents one cryptocurrency returned by CoinMarketCap API
+type CoinMarketCapCryptocurrency struct {
+	Id       int64                          `json:"id"`
+	Symbol   string                         `json:"symbol"`
+	IsActive int                            `json:"is_active"`
+	Quote    map[string]*CoinMarketCapQuote `json:"quote"`
+}
+
+// CoinMarketCapQuotesLatestResponse represents the response from CoinMarketCap latest quotes API
+type CoinMarketCapQuotesLatestResponse struct {
+	Status *struct {
+		Timestamp    string `json:"timestamp"`
+		ErrorCode    int    `json:"error_code"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"status"`
+	Data map[string][]*CoinMarketCapCryptocurrency `json:"data"`
+}
+
+// ToLatestCryptocurrencyPriceResponse converts CoinMarketCap response to LatestCryptocurrencyPriceResponse
+func (r *CoinMarketCapQuotesLatestResponse) ToLatestCryptocurrencyPriceResponse() *models.LatestCryptocurrencyPriceResponse {
+	prices := make(models.LatestCryptocurrencyPriceSlice, 0, len(r.Data))
+	updateTime := int64(0)
+
+	for symbol, cryptocurrencies := range r.Data {
+		// multiple cryptocurrencies may have the same symbol, the first active one is the one with the highest market cap
+		for i := 0; i < len(cryptocurrencies); i++ {
+			cryptocurrency := cryptocurrencies[i]
+
+			if cryptocurrency == nil || cryptocurrency.IsActive != 1 {
+				continue
+			}
+
+			quote, exists := cryptocurrency.Quote[coinMarketCapQuoteCurrency]
+
+			if !exists || quote == nil {
+				continue
+			}
+
+			if lastUpdated := parseCoinMarketCapTime(quote.LastUpdated); lastUpdated > updateTime {
+				updateTime = lastUpdated
+			}
+
+			prices = append(prices, &models.LatestCryptocurrencyPrice{
+				Symbol: strings.ToUpper(symbol),
+				Price:  strconv.FormatFloat(quote.Price, 'f', -1, 64),
+			})
+
+			break
+		}
+	}
+
+	if updateTime <= 0 && r.Status != nil {
+		updateTime = parseCoinMarketCapTime(r.Status.Timestamp)
+	}
+
+	if updateTime <= 0 {
+		updateTime = time.Now().Unix()
+	}
+
+	return &models.LatestCryptocurrencyPriceResponse{
+		DataSource:   "CoinMarketCap",
+		ReferenceUrl: "https://coinmarketcap.com/",
+		UpdateTime:   updateTime,
+		BaseCurrency: coinMarketCapQuoteCurrency,
+		Prices:       prices,
+	}
+}
+
+// BuildRequests builds the http requests
+func (s *CoinMarketCapDataSource) BuildRequests(symbols []string, apiKey string) ([]*http.Request, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	if apiKey == "" {
+		return nil, errs.ErrCryptocurrencyApiKeyRequired
+	}
+
+	finalSymbols := make([]string, 0, len(symbols))
+
+	for i := 0; i < len(symbols); i++ {
+		symbol := strings.ToUpper(strings.TrimSpace(symbols[i]))
+
+		if symbol != "" {
+			finalSymbols = append(finalSymbols, symbol)
+		}
+	}
+
+	if len(finalSymbols) < 1 {
+		return nil, errs.ErrInvalidCryptocurrencySymbol
+	}
+
+	u, err := url.Parse(coinMarketCapQuotesLatestApiUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Set("symbol", strings.Join(finalSymbols, ","))
+	q.Set("convert", coinMarketCapQuoteCurrency)
+	q.Set("skip_invalid", "true")
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(coinMarketCapApiKeyHeaderName, apiKey)
+
+	return []*http.Request{req}, nil
+}
+
+// Parse parses the response content
+func (s *CoinMarketCapDataSource) Parse(c core.Context, content []byte) (*models.LatestCryptocurrencyPriceResponse, error) {
+	response := &CoinMarketCapQuotesLatestResponse{}
+	err := json.Unmarshal(content, response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Status == nil {
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	if response.Status.ErrorCode != 0 {
+		return nil, fmt.Errorf("coinmarketcap returns error code %d, message \"%s\"", response.Status.ErrorCode, response.Status.ErrorMessage)
+	}
+
+	return response.ToLatestCryptocurrencyPriceResponse(), nil
+}
+
+func parseCoinMarketCapTime(value string) int64 {
+	if value == "" {
+		return 0
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		return 0
+	}
+
+	return t.Unix()
+}
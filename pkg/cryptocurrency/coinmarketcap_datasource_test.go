@@ -0,0 +1,95 @@
+package cryptocurrency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const coinMarketCapMinimumRequiredContent = "{" +
+	"\"status\":{\"timestamp\":\"2024-05-01T08:00:30.000Z\",\"error_code\":0,\"error_message\":null,\"elapsed\":25,\"credit_count\":1}," +
+	"\"data\":{" +
+	"\"BTC\":[{\"id\":1,\"name\":\"Bitcoin\",\"symbol\":\"BTC\",\"is_active\":1,\"quote\":{\"USD\":{\"price\":65432.1234,\"last_updated\":\"2024-05-01T08:00:00.000Z\"}}}]," +
+	"\"ETH\":[{\"id\":1027,\"name\":\"Ethereum\",\"symbol\":\"ETH\",\"is_active\":1,\"quote\":{\"USD\":{\"price\":3456.78,\"last_updated\":\"2024-05-01T07:59:00.000Z\"}}}]," +
+	"\"UNI\":[" +
+	"{\"id\":99999,\"name\":\"Inactive Uni\",\"symbol\":\"UNI\",\"is_active\":0,\"quote\":{\"USD\":{\"price\":0.01,\"last_updated\":\"2024-05-01T07:00:00.000Z\"}}}," +
+	"{\"id\":7083,\"name\":\"Uniswap\",\"symbol\":\"UNI\",\"is_active\":1,\"quote\":{\"USD\":{\"price\":7.89,\"last_updated\":\"2024-05-01T07:58:00.000Z\"}}}" +
+	"]" +
+	"}}"
+
+func TestCoinMarketCapDataSource_BuildRequests(t *testing.T) {
+	dataSource := &CoinMarketCapDataSource{}
+
+	requests, err := dataSource.BuildRequests([]string{"btc", "ETH"}, "test-key")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, "https://pro-api.coinmarketcap.com/v2/cryptocurrency/quotes/latest?convert=USD&skip_invalid=true&symbol=BTC%2CETH", requests[0].URL.String())
+	assert.Equal(t, "test-key", requests[0].Header.Get("X-CMC_PRO_API_KEY"))
+}
+
+func TestCoinMarketCapDataSource_BuildRequestsWithoutApiKey(t *testing.T) {
+	dataSource := &CoinMarketCapDataSource{}
+
+	_, err := dataSource.BuildRequests([]string{"BTC"}, "")
+	assert.Equal(t, errs.ErrCryptocurrencyApiKeyRequired, err)
+}
+
+func TestCoinMarketCapDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &CoinMarketCapDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestCryptocurrencyPriceResponse, err := dataSource.Parse(context, []byte(coinMarketCapMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "USD", actualLatestCryptocurrencyPriceResponse.BaseCurrency)
+	assert.Equal(t, "CoinMarketCap", actualLatestCryptocurrencyPriceResponse.DataSource)
+}
+
+func TestCoinMarketCapDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &CoinMarketCapDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestCryptocurrencyPriceResponse, err := dataSource.Parse(context, []byte(coinMarketCapMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1714550400), actualLatestCryptocurrencyPriceResponse.UpdateTime)
+}
+
+func TestCoinMarketCapDataSource_StandardDataExtractPrices(t *testing.T) {
+	dataSource := &CoinMarketCapDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestCryptocurrencyPriceResponse, err := dataSource.Parse(context, []byte(coinMarketCapMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(actualLatestCryptocurrencyPriceResponse.Prices))
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "BTC",
+		Price:  "65432.1234",
+	})
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "ETH",
+		Price:  "3456.78",
+	})
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "UNI",
+		Price:  "7.89",
+	})
+}
+
+func TestCoinMarketCapDataSource_BlankContent(t *testing.T) {
+	dataSource := &CoinMarketCapDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCoinMarketCapDataSource_ErrorContent(t *testing.T) {
+	dataSource := &CoinMarketCapDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"status\":{\"timestamp\":\"2024-05-01T08:00:30.000Z\",\"error_code\":1001,\"error_message\":\"This API Key is invalid.\"}}"))
+	assert.NotEqual(t, nil, err)
+}
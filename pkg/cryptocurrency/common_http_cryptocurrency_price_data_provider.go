@@ -46,20 +46,48 @@ func (p *CommonHttpCryptocurrencyPriceDataProvider) GetLatestCryptocurrencyPrice
 	}
 
 	client := utils.NewHttpClient(uint32(config.RequestTimeout), config.Proxy, false, settings.GetUserAgent())
-	
-	// Currently we only support single request for most data sources
-	// If we need to support multiple requests (e.g. batch limit), we need to merge results
+
 	if len(requests) == 1 {
 		return p.executeRequest(c, client, requests[0])
 	}
 
-	// For multiple requests, we need to handle them (implementation for future if needed)
-	// For now, return error or handle first one
-	if len(requests) > 0 {
-		return p.executeRequest(c, client, requests[0])
+	if len(requests) < 1 {
+		return nil, errs.ErrSystemError
+	}
+
+	// Some data sources (like Coinbase) require one request per symbol, the failed requests would be skipped
+	finalResult := &models.LatestCryptocurrencyPriceResponse{
+		Prices: make(models.LatestCryptocurrencyPriceSlice, 0, len(symbols)),
+	}
+
+	for i := 0; i < len(requests); i++ {
+		result, err := p.executeRequest(c, client, requests[i])
+
+		if err != nil {
+			log.Warnf(c, "[cryptocurrency.CommonHttpCryptocurrencyPriceDataProvider] failed to request cryptocurrency price data for %s, because %s", requests[i].URL.String(), err.Error())
+			continue
+		}
+
+		if result == nil {
+			continue
+		}
+
+		finalResult.DataSource = result.DataSource
+		finalResult.ReferenceUrl = result.ReferenceUrl
+		finalResult.BaseCurrency = result.BaseCurrency
+
+		if result.UpdateTime > finalResult.UpdateTime {
+			finalResult.UpdateTime = result.UpdateTime
+		}
+
+		finalResult.Prices = append(finalResult.Prices, result.Prices...)
+	}
+
+	if len(finalResult.Prices) < 1 {
+		return nil, errs.ErrFailedToRequestRemoteApi
 	}
 
-	return nil, errs.ErrSystemError
+	return finalResult, nil
 }
 
 func (p *CommonHttpCryptocurrencyPriceDataProvider) executeRequest(c core.Context, client *http.Client, req *http.Request) (*models.LatestCryptocurrencyPriceResponse, error) {
@@ -1,36 +1,36 @@
 package cryptocurrency
 
 import (
+	"strings"
 	"sync"
 	"time"
 
-	"golang.org/x/sync/singleflight"
-
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
-	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/pricecache"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 const (
-	cryptocurrencyPriceCacheTimeout = 5 * time.Minute
+	cryptocurrencyPriceCacheTimeout   = 5 * time.Minute
+	cryptocurrencyPriceFetchBatchSize = 100
 )
 
 // CryptocurrencyPriceDataProviderContainer contains the cryptocurrency price data provider
 type CryptocurrencyPriceDataProviderContainer struct {
-	Current      CryptocurrencyPriceDataProvider
-	CurrentType  string
-	IsEnabled    bool
-	lastResult   *models.LatestCryptocurrencyPriceResponse
-	lastTime     time.Time
-	mu           sync.RWMutex
-	requestGroup singleflight.Group
+	Current     CryptocurrencyPriceDataProvider
+	CurrentType string
+	IsEnabled   bool
+	cache       *pricecache.SymbolPriceCache
+	mu          sync.RWMutex
 }
 
 // Initialize a cryptocurrency price data provider container singleton instance
 var (
-	Container = &CryptocurrencyPriceDataProviderContainer{}
+	Container = &CryptocurrencyPriceDataProviderContainer{
+		cache: pricecache.NewSymbolPriceCache("cryptocurrency", cryptocurrencyPriceCacheTimeout, cryptocurrencyPriceFetchBatchSize),
+	}
 )
 
 // InitializeCryptocurrencyPriceDataProvider initializes the cryptocurrency price data provider
@@ -53,6 +53,16 @@ func (c *CryptocurrencyPriceDataProviderContainer) GetLatestCryptocurrencyPrices
 		switch config.DataSource {
 		case "coingecko":
 			provider = NewCommonHttpCryptocurrencyPriceDataProvider(&CoinGeckoDataSource{})
+		case settings.BinanceDataSource:
+			provider = NewCommonHttpCryptocurrencyPriceDataProvider(&BinanceDataSource{})
+		case settings.CoinbaseDataSource:
+			provider = NewCommonHttpCryptocurrencyPriceDataProvider(&CoinbaseDataSource{})
+		case settings.KrakenDataSource:
+			provider = NewCommonHttpCryptocurrencyPriceDataProvider(&KrakenDataSource{})
+		case settings.CoinMarketCapDataSource:
+			provider = NewCommonHttpCryptocurrencyPriceDataProvider(&CoinMarketCapDataSource{})
+		case settings.LocalFileCryptocurrencyDataSource:
+			provider = NewLocalFileCryptocurrencyPriceDataProvider(settings.Container)
 		default:
 			c.mu.Unlock()
 			return nil, errs.ErrInvalidCryptocurrencyDataSource
@@ -62,42 +72,69 @@ func (c *CryptocurrencyPriceDataProviderContainer) GetLatestCryptocurrencyPrices
 		c.CurrentType = config.DataSource
 		c.IsEnabled = true
 	}
-	
+
 	provider := c.Current
 	c.mu.Unlock()
 
-	c.mu.RLock()
-	if c.lastResult != nil && time.Since(c.lastTime) < cryptocurrencyPriceCacheTimeout {
-		result := c.lastResult
-		c.mu.RUnlock()
-		return result, nil
-	}
-	c.mu.RUnlock()
+	cachedPrices, err := c.cache.GetPrices(ctx, config.DataSource, config.BaseCurrency, symbols, func(missingSymbols []string) ([]*pricecache.CachedPrice, error) {
+		response, err := provider.GetLatestCryptocurrencyPrices(ctx, uid, config, missingSymbols)
 
-	result, err, _ := c.requestGroup.Do("GetLatestCryptocurrencyPrices", func() (interface{}, error) {
-		res, fetchErr := provider.GetLatestCryptocurrencyPrices(ctx, uid, config, symbols)
+		if err != nil {
+			return nil, err
+		}
 
-		if fetchErr == nil {
-			c.mu.Lock()
-			c.lastResult = res
-			c.lastTime = time.Now()
-			c.mu.Unlock()
+		if response == nil {
+			return nil, nil
 		}
 
-		return res, fetchErr
+		prices := make([]*pricecache.CachedPrice, 0, len(response.Prices))
+
+		for i := 0; i < len(response.Prices); i++ {
+			price := response.Prices[i]
+
+			if price == nil {
+				continue
+			}
+
+			prices = append(prices, &pricecache.CachedPrice{
+				Symbol:       strings.ToUpper(price.Symbol),
+				Price:        price.Price,
+				Currency:     response.BaseCurrency,
+				DataSource:   response.DataSource,
+				ReferenceUrl: response.ReferenceUrl,
+				UpdateTime:   response.UpdateTime,
+			})
+		}
+
+		return prices, nil
 	})
 
-	if err == nil {
-		return result.(*models.LatestCryptocurrencyPriceResponse), nil
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.LatestCryptocurrencyPriceResponse{
+		Prices: make(models.LatestCryptocurrencyPriceSlice, 0, len(cachedPrices)),
 	}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	for i := 0; i < len(cachedPrices); i++ {
+		cachedPrice := cachedPrices[i]
+
+		if result.DataSource == "" {
+			result.DataSource = cachedPrice.DataSource
+			result.ReferenceUrl = cachedPrice.ReferenceUrl
+			result.BaseCurrency = cachedPrice.Currency
+		}
+
+		if cachedPrice.UpdateTime > result.UpdateTime {
+			result.UpdateTime = cachedPrice.UpdateTime
+		}
 
-	if c.lastResult != nil {
-		log.Warnf(ctx, "[cryptocurrency.Container] failed to get latest prices, using stale cache from %s", c.lastTime.Format("2006-01-02 15:04:05"))
-		return c.lastResult, nil
+		result.Prices = append(result.Prices, &models.LatestCryptocurrencyPrice{
+			Symbol: cachedPrice.Symbol,
+			Price:  cachedPrice.Price,
+		})
 	}
 
-	return nil, err
+	return result, nil
 }
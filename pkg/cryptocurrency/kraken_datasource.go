@@ -0,0 +1,154 @@
+package cryptocurrency
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const (
+	krakenTickerApiUrl      = "https://api.kraken.com/0/public/Ticker"
+	krakenQuoteCurrency     = "USD"
+	krakenPairNameUsdSuffix = "USD"
+)
+
+// krakenLegacyPairNames maps the legacy Kraken pair names (with X / Z prefix) to common cryptocurrency symbols
+var krakenLegacyPairNames = map[string]string{
+	"XXBTZUSD": "BTC",
+	"XETHZUSD": "ETH",
+	"XLTCZUSD": "LTC",
+	"XXRPZUSD": "XRP",
+	"XXLMZUSD": "XLM",
+	"XXMRZUSD": "XMR",
+	"XETCZUSD": "ETC",
+	"XZECZUSD": "ZEC",
+	"XXDGZUSD": "DOGE",
+	"XREPZUSD": "REP",
+	"XMLNZUSD": "MLN",
+	"USDTZUSD": "USDT",
+}
+
+// KrakenDataSource defines the structure of Kraken data source
+type KrakenDataSource struct {
+}
+
+// KrakenTickerInfo represents the ticker information of one trading pair returned by Kraken API
+type KrakenTickerInfo struct {
+	LastTradeClosed []string `json:"c"`
+}
+
+// KrakenTickerResponse represents the response from Kraken ticker API
+type KrakenTickerResponse struct {
+	Error  []string                     `json:"error"`
+	Result map[string]*KrakenTickerInfo `json:"result"`
+}
+
+// ToLatestCryptocurrencyPriceResponse converts Kraken response to LatestCryptocurrencyPriceResponse
+func (r *KrakenTickerResponse) ToLatestCryptocurrencyPriceResponse(c core.Context) *models.LatestCryptocurrencyPriceResponse {
+	prices := make(models.LatestCryptocurrencyPriceSlice, 0, len(r.Result))
+
+	for pairName, tickerInfo := range r.Result {
+		symbol := getKrakenSymbolByPairName(pairName)
+
+		if symbol == "" || tickerInfo == nil || len(tickerInfo.LastTradeClosed) < 1 {
+			continue
+		}
+
+		price := tickerInfo.LastTradeClosed[0]
+
+		if _, err := strconv.ParseFloat(price, 64); err != nil {
+			log.Warnf(c, "[kraken_datasource.ToLatestCryptocurrencyPriceResponse] failed to parse price of \"%s\", because %s", pairName, err.Error())
+			continue
+		}
+
+		prices = append(prices, &models.LatestCryptocurrencyPrice{
+			Symbol: symbol,
+			Price:  price,
+		})
+	}
+
+	return &models.LatestCryptocurrencyPriceResponse{
+		DataSource:   "Kraken",
+		ReferenceUrl: "https://www.kraken.com/prices",
+		UpdateTime:   time.Now().Unix(),
+		BaseCurrency: krakenQuoteCurrency,
+		Prices:       prices,
+	}
+}
+
+// BuildRequests builds the http requests, each pair is requested separately because Kraken rejects the whole request if any pair is unknown
+func (s *KrakenDataSource) BuildRequests(symbols []string, apiKey string) ([]*http.Request, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	requests := make([]*http.Request, 0, len(symbols))
+
+	for i := 0; i < len(symbols); i++ {
+		symbol := strings.ToUpper(strings.TrimSpace(symbols[i]))
+
+		if symbol == "" {
+			continue
+		}
+
+		req, err := http.NewRequest("GET", krakenTickerApiUrl+"?pair="+url.QueryEscape(symbol+krakenPairNameUsdSuffix), nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, req)
+	}
+
+	if len(requests) < 1 {
+		return nil, errs.ErrInvalidCryptocurrencySymbol
+	}
+
+	return requests, nil
+}
+
+// Parse parses the response content
+func (s *KrakenDataSource) Parse(c core.Context, content []byte) (*models.LatestCryptocurrencyPriceResponse, error) {
+	response := &KrakenTickerResponse{}
+	err := json.Unmarshal(content, response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response.Error) > 0 {
+		return nil, fmt.Errorf("kraken returns error \"%s\"", strings.Join(response.Error, ","))
+	}
+
+	if len(response.Result) < 1 {
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return response.ToLatestCryptocurrencyPriceResponse(c), nil
+}
+
+func getKrakenSymbolByPairName(pairName string) string {
+	if symbol, exists := krakenLegacyPairNames[pairName]; exists {
+		return symbol
+	}
+
+	// the legacy pairs which are not in the map (e.g. fiat pairs) are ignored
+	if strings.HasSuffix(pairName, "Z"+krakenPairNameUsdSuffix) && len(pairName) == 8 && (pairName[0] == 'X' || pairName[0] == 'Z') {
+		return ""
+	}
+
+	if !strings.HasSuffix(pairName, krakenPairNameUsdSuffix) {
+		return ""
+	}
+
+	return strings.TrimSuffix(pairName, krakenPairNameUsdSuffix)
+}
@@ -0,0 +1,102 @@
+package cryptocurrency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const krakenMinimumRequiredContent = "{\"error\":[],\"result\":{" +
+	"\"XXBTZUSD\":{\"a\":[\"65432.20000\",\"1\",\"1.000\"],\"b\":[\"65432.10000\",\"2\",\"2.000\"],\"c\":[\"65432.10000\",\"0.00100000\"]}," +
+	"\"XETHZUSD\":{\"a\":[\"3456.79000\",\"5\",\"5.000\"],\"b\":[\"3456.78000\",\"3\",\"3.000\"],\"c\":[\"3456.78000\",\"0.05000000\"]}," +
+	"\"XXDGZUSD\":{\"c\":[\"0.1523400\",\"100.00000000\"]}," +
+	"\"XTZUSD\":{\"c\":[\"0.987600\",\"10.00000000\"]}," +
+	"\"SOLUSD\":{\"c\":[\"145.67000\",\"1.20000000\"]}," +
+	"\"USDTZUSD\":{\"c\":[\"1.00010000\",\"500.00000000\"]}," +
+	"\"ZEURZUSD\":{\"c\":[\"1.08500\",\"1000.00000000\"]}," +
+	"\"XBTUSDT\":{\"c\":[\"65430.00000\",\"0.01000000\"]}," +
+	"\"XETHXXBT\":{\"c\":[\"0.05280\",\"0.10000000\"]}" +
+	"}}"
+
+func TestKrakenDataSource_BuildRequests(t *testing.T) {
+	dataSource := &KrakenDataSource{}
+
+	requests, err := dataSource.BuildRequests([]string{"btc", "SOL", " "}, "")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "https://api.kraken.com/0/public/Ticker?pair=BTCUSD", requests[0].URL.String())
+	assert.Equal(t, "https://api.kraken.com/0/public/Ticker?pair=SOLUSD", requests[1].URL.String())
+
+	_, err = dataSource.BuildRequests([]string{" "}, "")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestKrakenDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &KrakenDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestCryptocurrencyPriceResponse, err := dataSource.Parse(context, []byte(krakenMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "USD", actualLatestCryptocurrencyPriceResponse.BaseCurrency)
+	assert.Equal(t, "Kraken", actualLatestCryptocurrencyPriceResponse.DataSource)
+}
+
+func TestKrakenDataSource_StandardDataExtractPrices(t *testing.T) {
+	dataSource := &KrakenDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestCryptocurrencyPriceResponse, err := dataSource.Parse(context, []byte(krakenMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 6, len(actualLatestCryptocurrencyPriceResponse.Prices))
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "BTC",
+		Price:  "65432.10000",
+	})
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "ETH",
+		Price:  "3456.78000",
+	})
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "DOGE",
+		Price:  "0.1523400",
+	})
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "XTZ",
+		Price:  "0.987600",
+	})
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "SOL",
+		Price:  "145.67000",
+	})
+	assert.Contains(t, actualLatestCryptocurrencyPriceResponse.Prices, &models.LatestCryptocurrencyPrice{
+		Symbol: "USDT",
+		Price:  "1.00010000",
+	})
+}
+
+func TestKrakenDataSource_BlankContent(t *testing.T) {
+	dataSource := &KrakenDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestKrakenDataSource_ErrorContent(t *testing.T) {
+	dataSource := &KrakenDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"error\":[\"EGeneral:Too many requests\"]}"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestKrakenDataSource_EmptyResultContent(t *testing.T) {
+	dataSource := &KrakenDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"error\":[],\"result\":{}}"))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,52 @@
+package cryptocurrency
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/pricefeed"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// LocalFileCryptocurrencyPriceDataProvider defines the structure of cryptocurrency price data provider which reads prices from local price feed
+type LocalFileCryptocurrencyPriceDataProvider struct {
+	container *settings.ConfigContainer
+}
+
+// NewLocalFileCryptocurrencyPriceDataProvider returns a new local file cryptocurrency price data provider
+func NewLocalFileCryptocurrencyPriceDataProvider(container *settings.ConfigContainer) *LocalFileCryptocurrencyPriceDataProvider {
+	return &LocalFileCryptocurrencyPriceDataProvider{
+		container: container,
+	}
+}
+
+// GetLatestCryptocurrencyPrices returns the latest cryptocurrency prices which are read from the price feed location in the configuration file,
+// the prices whose currency is different from the base currency would be ignored
+func (p *LocalFileCryptocurrencyPriceDataProvider) GetLatestCryptocurrencyPrices(c core.Context, uid int64, config *models.ExternalDataSourceConfig, symbols []string) (*models.LatestCryptocurrencyPriceResponse, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	currentConfig := p.container.GetCurrentConfig()
+	reader := pricefeed.NewPriceFeedReader(currentConfig.CryptocurrencyPriceFeedLocation, config, currentConfig.CryptocurrencyRequestTimeout, currentConfig.CryptocurrencyProxy, currentConfig.CryptocurrencySkipTLSVerify)
+	latestPrices, err := reader.ReadLatestPrices(c, config, symbols, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.LatestCryptocurrencyPriceResponse{
+		DataSource:   settings.LocalFileCryptocurrencyDataSource,
+		BaseCurrency: latestPrices.BaseCurrency,
+		UpdateTime:   latestPrices.UpdateTime,
+		Prices:       make(models.LatestCryptocurrencyPriceSlice, len(latestPrices.Prices)),
+	}
+
+	for i := 0; i < len(latestPrices.Prices); i++ {
+		response.Prices[i] = &models.LatestCryptocurrencyPrice{
+			Symbol: latestPrices.Prices[i].Symbol,
+			Price:  latestPrices.Prices[i].Price,
+		}
+	}
+
+	return response, nil
+}
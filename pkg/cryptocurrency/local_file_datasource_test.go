@@ -0,0 +1,37 @@
+package cryptocurrency
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func TestLocalFileCryptocurrencyPriceDataProviderGetLatestCryptocurrencyPrices(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "prices.csv")
+	assert.Nil(t, os.WriteFile(filePath, []byte("symbol,price,currency,update_time\nBTC,43000,,1700000000\nETH,2000,EUR,1700000100\nSOL,60,USD,1700000200\n"), 0644))
+
+	settings.SetCurrentConfig(&settings.Config{
+		CryptocurrencyPriceFeedLocation: filePath,
+	})
+
+	provider := NewLocalFileCryptocurrencyPriceDataProvider(settings.Container)
+	response, err := provider.GetLatestCryptocurrencyPrices(core.NewNullContext(), 0, &models.ExternalDataSourceConfig{DataSource: settings.LocalFileCryptocurrencyDataSource}, []string{"btc", "ETH", "SOL", "DOGE"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, settings.LocalFileCryptocurrencyDataSource, response.DataSource)
+	assert.Equal(t, "USD", response.BaseCurrency)
+	assert.Equal(t, int64(1700000200), response.UpdateTime)
+	assert.Equal(t, 2, len(response.Prices))
+
+	assert.Equal(t, "BTC", response.Prices[0].Symbol)
+	assert.Equal(t, "43000", response.Prices[0].Price)
+
+	assert.Equal(t, "SOL", response.Prices[1].Symbol)
+	assert.Equal(t, "60", response.Prices[1].Price)
+}
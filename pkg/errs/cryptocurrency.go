@@ -12,4 +12,6 @@ var (
 	ErrCryptocurrencyServiceNotEnabled = NewNormalError(NormalSubcategoryCryptocurrency, 0, http.StatusBadRequest, "cryptocurrency service not enabled")
 	ErrInvalidCryptocurrencySymbol     = NewNormalError(NormalSubcategoryCryptocurrency, 1, http.StatusBadRequest, "invalid cryptocurrency symbol")
 	ErrCryptocurrencyNotFound          = NewNormalError(NormalSubcategoryCryptocurrency, 2, http.StatusNotFound, "cryptocurrency not found")
+	ErrCryptocurrencyApiKeyRequired    = NewNormalError(NormalSubcategoryCryptocurrency, 3, http.StatusBadRequest, "cryptocurrency data source api key is required")
+	ErrCryptocurrencyAlreadyExists     = NewNormalError(NormalSubcategoryCryptocurrency, 4, http.StatusBadRequest, "cryptocurrency already exists")
 )
@@ -46,6 +46,14 @@ const (
 	NormalSubcategoryInsightsExplorer       = 18
 	NormalSubcategoryCryptocurrency         = 19
 	NormalSubcategoryStocks                 = 20
+	NormalSubcategoryInvestment             = 21
+	NormalSubcategoryPriceAlert             = 22
+	NormalSubcategoryExchangeRate           = 23
+	NormalSubcategoryWalletBalance          = 24
+	NormalSubcategoryBudget                 = 25
+	NormalSubcategoryGoal                   = 26
+	NormalSubcategoryReconciliation         = 27
+	NormalSubcategoryRefund                 = 28
 )
 
 // Error represents the specific error returned to user
@@ -0,0 +1,9 @@
+package errs
+
+import "net/http"
+
+// Error codes related to exchange rates
+var (
+	ErrHistoricalExchangeRatesNotSupported = NewNormalError(NormalSubcategoryExchangeRate, 0, http.StatusBadRequest, "current exchange rates data source does not support historical exchange rates")
+	ErrInvalidExchangeRatesDateRange       = NewNormalError(NormalSubcategoryExchangeRate, 1, http.StatusBadRequest, "invalid exchange rates date range")
+)
@@ -0,0 +1,12 @@
+package errs
+
+import "net/http"
+
+// Error codes related to savings goals
+var (
+	ErrGoalIdInvalid           = NewNormalError(NormalSubcategoryGoal, 0, http.StatusBadRequest, "goal id is invalid")
+	ErrGoalNotFound            = NewNormalError(NormalSubcategoryGoal, 1, http.StatusBadRequest, "goal not found")
+	ErrGoalAccountInvalid      = NewNormalError(NormalSubcategoryGoal, 2, http.StatusBadRequest, "goal account is invalid")
+	ErrGoalTargetAmountInvalid = NewNormalError(NormalSubcategoryGoal, 3, http.StatusBadRequest, "goal target amount is invalid")
+	ErrTooManyGoalAccounts     = NewNormalError(NormalSubcategoryGoal, 4, http.StatusBadRequest, "too many goal accounts")
+)
@@ -0,0 +1,20 @@
+package errs
+
+import "net/http"
+
+// Error codes related to investments
+var (
+	ErrInvestmentTradeIdInvalid                = NewNormalError(NormalSubcategoryInvestment, 0, http.StatusBadRequest, "investment trade id is invalid")
+	ErrInvestmentTradeNotFound                 = NewNormalError(NormalSubcategoryInvestment, 1, http.StatusBadRequest, "investment trade not found")
+	ErrInvestmentTradeTypeInvalid              = NewNormalError(NormalSubcategoryInvestment, 2, http.StatusBadRequest, "investment trade type is invalid")
+	ErrInvestmentAccountTypeInvalid            = NewNormalError(NormalSubcategoryInvestment, 3, http.StatusBadRequest, "account is not an investment account")
+	ErrInvestmentTradeQuantityInvalid          = NewNormalError(NormalSubcategoryInvestment, 4, http.StatusBadRequest, "investment trade quantity is invalid")
+	ErrInvestmentTradeSplitRatioInvalid        = NewNormalError(NormalSubcategoryInvestment, 5, http.StatusBadRequest, "investment trade split ratio is invalid")
+	ErrInvestmentSellQuantityExceedsHolding    = NewNormalError(NormalSubcategoryInvestment, 6, http.StatusBadRequest, "sell quantity exceeds holding quantity")
+	ErrInvestmentLotMatchingMethodInvalid      = NewNormalError(NormalSubcategoryInvestment, 7, http.StatusBadRequest, "lot matching method is invalid")
+	ErrInvestmentTradeCurrencyMismatch         = NewNormalError(NormalSubcategoryInvestment, 8, http.StatusBadRequest, "investment trade currency does not match the currency of previous trades")
+	ErrInvestmentTradeCurrencyInvalid          = NewNormalError(NormalSubcategoryInvestment, 9, http.StatusBadRequest, "investment trade currency is invalid")
+	ErrInvestmentTradeTransactionMismatch      = NewNormalError(NormalSubcategoryInvestment, 10, http.StatusBadRequest, "linked transaction does not match the investment trade")
+	ErrInvestmentSplitLotQuantityTooSmall      = NewNormalError(NormalSubcategoryInvestment, 11, http.StatusBadRequest, "split would reduce the quantity of an open lot to zero")
+	ErrInvestmentTradeTransactionAlreadyLinked = NewNormalError(NormalSubcategoryInvestment, 12, http.StatusBadRequest, "linked transaction has already been linked to another investment trade")
+)
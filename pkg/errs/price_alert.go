@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to price alerts
+var (
+	ErrPriceAlertIdInvalid        = NewNormalError(NormalSubcategoryPriceAlert, 0, http.StatusBadRequest, "price alert id is invalid")
+	ErrPriceAlertNotFound         = NewNormalError(NormalSubcategoryPriceAlert, 1, http.StatusBadRequest, "price alert not found")
+	ErrPriceAlertTypeInvalid      = NewNormalError(NormalSubcategoryPriceAlert, 2, http.StatusBadRequest, "price alert type is invalid")
+	ErrPriceAlertAssetTypeInvalid = NewNormalError(NormalSubcategoryPriceAlert, 3, http.StatusBadRequest, "price alert asset type is invalid")
+	ErrPriceAlertThresholdInvalid = NewNormalError(NormalSubcategoryPriceAlert, 4, http.StatusBadRequest, "price alert threshold is invalid")
+	ErrPriceAlertPeriodInvalid    = NewNormalError(NormalSubcategoryPriceAlert, 5, http.StatusBadRequest, "price alert period is invalid")
+	ErrPriceAlertSymbolNotWatched = NewNormalError(NormalSubcategoryPriceAlert, 6, http.StatusBadRequest, "symbol is not in the watchlist")
+	ErrPriceAlertCurrencyInvalid  = NewNormalError(NormalSubcategoryPriceAlert, 7, http.StatusBadRequest, "price alert currency is invalid")
+)
@@ -0,0 +1,10 @@
+package errs
+
+import "net/http"
+
+// Error codes related to local price feed
+var (
+	ErrPriceFeedLocationNotSet = NewSystemError(SystemSubcategorySetting, 28, http.StatusInternalServerError, "price feed location is not set")
+	ErrFailedToReadPriceFeed   = NewSystemError(SystemSubcategoryDefault, 7, http.StatusInternalServerError, "failed to read price feed")
+	ErrInvalidPriceFeedContent = NewSystemError(SystemSubcategoryDefault, 8, http.StatusInternalServerError, "invalid price feed content")
+)
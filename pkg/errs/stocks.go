@@ -8,8 +8,18 @@ import (
 
 // Error codes related to stocks
 var (
-	ErrInvalidStockDataSource = NewSystemError(SystemSubcategorySetting, 27, http.StatusInternalServerError, "invalid stock data source")
-	ErrStockServiceNotEnabled = NewNormalError(NormalSubcategoryStocks, 0, http.StatusBadRequest, "stock service not enabled")
-	ErrInvalidStockSymbol     = NewNormalError(NormalSubcategoryStocks, 1, http.StatusBadRequest, "invalid stock symbol")
-	ErrStockNotFound          = NewNormalError(NormalSubcategoryStocks, 2, http.StatusNotFound, "stock not found")
+	ErrInvalidStockDataSource               = NewSystemError(SystemSubcategorySetting, 27, http.StatusInternalServerError, "invalid stock data source")
+	ErrStockServiceNotEnabled               = NewNormalError(NormalSubcategoryStocks, 0, http.StatusBadRequest, "stock service not enabled")
+	ErrInvalidStockSymbol                   = NewNormalError(NormalSubcategoryStocks, 1, http.StatusBadRequest, "invalid stock symbol")
+	ErrStockNotFound                        = NewNormalError(NormalSubcategoryStocks, 2, http.StatusNotFound, "stock not found")
+	ErrStockDataSourceConfigNotFound        = NewNormalError(NormalSubcategoryStocks, 3, http.StatusBadRequest, "stock data source config not found")
+	ErrStockDataSourceNotConfigured         = NewNormalError(NormalSubcategoryStocks, 4, http.StatusBadRequest, "stock data source is not configured")
+	ErrStockDataSourceRouteNotFound         = NewNormalError(NormalSubcategoryStocks, 5, http.StatusBadRequest, "stock data source route not found")
+	ErrStockCorporateActionNotFound         = NewNormalError(NormalSubcategoryStocks, 6, http.StatusBadRequest, "stock corporate action not found")
+	ErrStockCorporateActionTypeInvalid      = NewNormalError(NormalSubcategoryStocks, 7, http.StatusBadRequest, "stock corporate action type is invalid")
+	ErrStockCorporateActionRatioInvalid     = NewNormalError(NormalSubcategoryStocks, 8, http.StatusBadRequest, "stock corporate action ratio is invalid")
+	ErrStockCorporateActionNewSymbolInvalid = NewNormalError(NormalSubcategoryStocks, 9, http.StatusBadRequest, "new stock symbol is invalid")
+	ErrStockCorporateActionNewSymbolExists  = NewNormalError(NormalSubcategoryStocks, 10, http.StatusBadRequest, "new stock symbol already exists")
+	ErrStockCorporateActionNotLatest        = NewNormalError(NormalSubcategoryStocks, 11, http.StatusBadRequest, "only the latest corporate action of a stock can be deleted")
+	ErrStockAlreadyExists                   = NewNormalError(NormalSubcategoryStocks, 12, http.StatusBadRequest, "stock already exists")
 )
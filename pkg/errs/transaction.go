@@ -47,4 +47,16 @@ var (
 	ErrCannotMoveTransactionBetweenAccountsWithDifferentCurrencies = NewNormalError(NormalSubcategoryTransaction, 40, http.StatusBadRequest, "cannot move transaction between accounts with different currencies")
 	ErrCannotTransferBetweenDifferentAccountAssetTypes             = NewNormalError(NormalSubcategoryTransaction, 41, http.StatusBadRequest, "cannot transfer between different account asset types")
 	ErrCannotTransferBetweenDifferentCurrencies                    = NewNormalError(NormalSubcategoryTransaction, 42, http.StatusBadRequest, "cannot transfer between different currencies/symbols")
+	ErrOnlyIncomeTransactionCanSetHolding                          = NewNormalError(NormalSubcategoryTransaction, 43, http.StatusBadRequest, "only income transaction can set holding")
+	ErrTransactionHoldingAccountNotFound                           = NewNormalError(NormalSubcategoryTransaction, 44, http.StatusBadRequest, "transaction holding account not found")
+	ErrTransactionHoldingAccountInvalid                            = NewNormalError(NormalSubcategoryTransaction, 45, http.StatusBadRequest, "transaction holding account must be a stock, cryptocurrency or certificate of deposit account")
+	ErrOnlyIncomeOrExpenseTransactionCanBeSplit                    = NewNormalError(NormalSubcategoryTransaction, 46, http.StatusBadRequest, "only income or expense transaction can be split")
+	ErrTransactionSplitsCountInvalid                               = NewNormalError(NormalSubcategoryTransaction, 47, http.StatusBadRequest, "transaction must be split into at least two lines and cannot exceed the maximum count")
+	ErrTransactionSplitsAmountNotEqualToTransactionAmount          = NewNormalError(NormalSubcategoryTransaction, 48, http.StatusBadRequest, "sum of split amounts does not equal to transaction amount")
+	ErrTransactionSplitAmountInvalid                               = NewNormalError(NormalSubcategoryTransaction, 49, http.StatusBadRequest, "transaction split amount cannot be zero")
+	ErrTransactionSplitCategoryTypeInvalid                         = NewNormalError(NormalSubcategoryTransaction, 50, http.StatusBadRequest, "transaction split category type is not equal to transaction type")
+	ErrCannotModifyReconciledTransaction                           = NewNormalError(NormalSubcategoryTransaction, 51, http.StatusBadRequest, "cannot modify reconciled transaction")
+	ErrCannotDeleteReconciledTransaction                           = NewNormalError(NormalSubcategoryTransaction, 52, http.StatusBadRequest, "cannot delete reconciled transaction")
+	ErrCannotMoveReconciledTransaction                             = NewNormalError(NormalSubcategoryTransaction, 53, http.StatusBadRequest, "cannot move reconciled transaction")
+	ErrReimbursableTransactionTypeInvalid                          = NewNormalError(NormalSubcategoryTransaction, 54, http.StatusBadRequest, "only expense transaction can be reimbursable")
 )
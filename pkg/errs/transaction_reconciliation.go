@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction reconciliations
+var (
+	ErrTransactionReconciliationIdInvalid                  = NewNormalError(NormalSubcategoryReconciliation, 0, http.StatusBadRequest, "transaction reconciliation id is invalid")
+	ErrTransactionReconciliationNotFound                   = NewNormalError(NormalSubcategoryReconciliation, 1, http.StatusBadRequest, "transaction reconciliation not found")
+	ErrTransactionReconciliationStatusInvalid              = NewNormalError(NormalSubcategoryReconciliation, 2, http.StatusBadRequest, "transaction reconciliation status is invalid")
+	ErrReconciledTransactionStatusCannotBeModified         = NewNormalError(NormalSubcategoryReconciliation, 3, http.StatusBadRequest, "status of reconciled transaction cannot be modified")
+	ErrTransactionReconciliationStatementBalanceNotMatched = NewNormalError(NormalSubcategoryReconciliation, 4, http.StatusBadRequest, "cleared balance does not match statement balance")
+	ErrTransactionReconciliationStatementTimeInvalid       = NewNormalError(NormalSubcategoryReconciliation, 5, http.StatusBadRequest, "statement time cannot be earlier than the last reconciliation")
+	ErrOnlyLatestTransactionReconciliationCanBeDeleted     = NewNormalError(NormalSubcategoryReconciliation, 6, http.StatusBadRequest, "only the latest reconciliation of account can be deleted")
+	ErrTransactionReconciliationAccountTypeInvalid         = NewNormalError(NormalSubcategoryReconciliation, 7, http.StatusBadRequest, "only single account can be reconciled")
+)
@@ -0,0 +1,17 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction refunds
+var (
+	ErrTransactionRefundIdInvalid                      = NewNormalError(NormalSubcategoryRefund, 0, http.StatusBadRequest, "transaction refund id is invalid")
+	ErrTransactionRefundNotFound                       = NewNormalError(NormalSubcategoryRefund, 1, http.StatusBadRequest, "transaction refund not found")
+	ErrRefundedTransactionTypeInvalid                  = NewNormalError(NormalSubcategoryRefund, 2, http.StatusBadRequest, "refunded transaction must be expense transaction")
+	ErrRefundTransactionTypeInvalid                    = NewNormalError(NormalSubcategoryRefund, 3, http.StatusBadRequest, "refund transaction must be income transaction")
+	ErrTransactionRefundAmountInvalid                  = NewNormalError(NormalSubcategoryRefund, 4, http.StatusBadRequest, "transaction refund amount is invalid")
+	ErrTransactionRefundAmountExceedsExpenseAmount     = NewNormalError(NormalSubcategoryRefund, 5, http.StatusBadRequest, "total refund amount exceeds refunded transaction amount")
+	ErrTransactionRefundAmountExceedsIncomeAmount      = NewNormalError(NormalSubcategoryRefund, 6, http.StatusBadRequest, "refund amount exceeds refund transaction amount")
+	ErrTransactionRefundCurrencyNotMatched             = NewNormalError(NormalSubcategoryRefund, 7, http.StatusBadRequest, "refund transaction and refunded transaction must be in the same currency")
+	ErrRefundTransactionAlreadyLinked                  = NewNormalError(NormalSubcategoryRefund, 8, http.StatusBadRequest, "refund transaction has already been linked")
+	ErrRefundTransactionEarlierThanRefundedTransaction = NewNormalError(NormalSubcategoryRefund, 9, http.StatusBadRequest, "refund transaction cannot be earlier than refunded transaction")
+)
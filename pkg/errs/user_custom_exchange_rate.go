@@ -4,7 +4,12 @@ import "net/http"
 
 // Error codes related to user custom exchange rates
 var (
-	ErrUserCustomExchangeRateNotFound             = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 0, http.StatusBadRequest, "user custom exchange rate data not found")
-	ErrCannotUpdateExchangeRateForDefaultCurrency = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 1, http.StatusBadRequest, "cannot update exchange rate data for base currency")
-	ErrCannotDeleteExchangeRateForDefaultCurrency = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 2, http.StatusBadRequest, "cannot delete exchange rate data for base currency")
+	ErrUserCustomExchangeRateNotFound              = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 0, http.StatusBadRequest, "user custom exchange rate data not found")
+	ErrCannotUpdateExchangeRateForDefaultCurrency  = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 1, http.StatusBadRequest, "cannot update exchange rate data for base currency")
+	ErrCannotDeleteExchangeRateForDefaultCurrency  = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 2, http.StatusBadRequest, "cannot delete exchange rate data for base currency")
+	ErrUserCustomDatedExchangeRateIdInvalid        = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 3, http.StatusBadRequest, "user custom dated exchange rate id is invalid")
+	ErrUserCustomDatedExchangeRateNotFound         = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 4, http.StatusBadRequest, "user custom dated exchange rate not found")
+	ErrUserCustomDatedExchangeRateInvalidRate      = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 5, http.StatusBadRequest, "user custom dated exchange rate is invalid")
+	ErrUserCustomDatedExchangeRateInvalidTimeRange = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 6, http.StatusBadRequest, "effective end time must be later than effective start time")
+	ErrUserCustomDatedExchangeRateOverlapped       = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 7, http.StatusBadRequest, "effective time range overlaps with another user custom dated exchange rate")
 )
@@ -0,0 +1,14 @@
+package errs
+
+import (
+	"net/http"
+)
+
+// Error codes related to wallet balance
+var (
+	ErrInvalidWalletBalanceReader            = NewSystemError(SystemSubcategorySetting, 29, http.StatusInternalServerError, "invalid wallet balance reader")
+	ErrWalletBalanceReaderNotEnabled         = NewNormalError(NormalSubcategoryWalletBalance, 0, http.StatusBadRequest, "wallet balance reader not enabled")
+	ErrWalletBalanceReaderNotSupportCurrency = NewNormalError(NormalSubcategoryWalletBalance, 1, http.StatusBadRequest, "wallet balance reader does not support this cryptocurrency")
+	ErrFailedToRequestWalletBalance          = NewNormalError(NormalSubcategoryWalletBalance, 2, http.StatusBadRequest, "failed to request wallet balance")
+	ErrInvalidWalletBalanceResponse          = NewNormalError(NormalSubcategoryWalletBalance, 3, http.StatusBadRequest, "invalid wallet balance response")
+)
@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
 	"net/http"
 	"strings"
@@ -16,11 +17,13 @@ import (
 )
 
 const bankOfCanadaExchangeRateUrl = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?recent=1"
+const bankOfCanadaHistoricalExchangeRateUrlFormat = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?start_date=%s&end_date=%s"
 const bankOfCanadaExchangeRateReferenceUrl = "https://www.bankofcanada.ca/rates/exchange/daily-exchange-rates/"
 const bankOfCanadaDataSource = "Bank of Canada"
 const bankOfCanadaBaseCurrency = "CAD"
 
 const bankOfCanadaDataUpdateDateFormat = "2006-01-02 15:04"
+const bankOfCanadaRequestDateFormat = "2006-01-02"
 const bankOfCanadaDataUpdateDateTimezone = "America/Toronto"
 
 // BankOfCanadaDataSource defines the structure of exchange rates data source of bank of Canada
@@ -43,11 +46,88 @@ func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponse(c core.Conte
 		return nil
 	}
 
+	return toBankOfCanadaExchangeRateResponse(c, e.Observations)
+}
+
+// ToHistoricalExchangeRateResponses returns the view-objects of every day according to original data from bank of Canada
+func (e *BankOfCanadaExchangeRateData) ToHistoricalExchangeRateResponses(c core.Context) []*models.LatestExchangeRateResponse {
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(e.Observations))
+
+	for i := 0; i < len(e.Observations); i++ {
+		exchangeRateResp := toBankOfCanadaExchangeRateResponse(c, e.Observations[i:i+1])
+
+		if exchangeRateResp == nil || len(exchangeRateResp.ExchangeRates) < 1 {
+			continue
+		}
+
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	}
+
+	return exchangeRateResps
+}
+
+// BuildRequests returns the bank of Canada exchange rates http requests
+func (e *BankOfCanadaDataSource) BuildRequests() ([]*http.Request, error) {
+	req, err := http.NewRequest("GET", bankOfCanadaExchangeRateUrl, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// BuildHistoricalRequests returns the bank of Canada historical exchange rates http requests
+func (e *BankOfCanadaDataSource) BuildHistoricalRequests(startTime time.Time, endTime time.Time) ([]*http.Request, error) {
+	url := fmt.Sprintf(bankOfCanadaHistoricalExchangeRateUrlFormat, startTime.UTC().Format(bankOfCanadaRequestDateFormat), endTime.UTC().Format(bankOfCanadaRequestDateFormat))
+	req, err := http.NewRequest("GET", url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// Parse returns the common response entity according to the bank of Canada data source raw response
+func (e *BankOfCanadaDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	bankOfCanadaData := &BankOfCanadaExchangeRateData{}
+	err := json.Unmarshal(content, bankOfCanadaData)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_canada_datasource.Parse] failed to parse json data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResponse := bankOfCanadaData.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.Errorf(c, "[bank_of_canada_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
+
+// ParseHistorical returns the common response entities of every day according to the bank of Canada data source raw response
+func (e *BankOfCanadaDataSource) ParseHistorical(c core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	bankOfCanadaData := &BankOfCanadaExchangeRateData{}
+	err := json.Unmarshal(content, bankOfCanadaData)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_canada_datasource.ParseHistorical] failed to parse json data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return bankOfCanadaData.ToHistoricalExchangeRateResponses(c), nil
+}
+
+func toBankOfCanadaExchangeRateResponse(c core.Context, observations []BankOfCanadaObservationData) *models.LatestExchangeRateResponse {
 	exchangeRateMap := make(map[string]string)
 	latestUpdateDate := ""
 
-	for i := 0; i < len(e.Observations); i++ {
-		observation := e.Observations[i]
+	for i := 0; i < len(observations); i++ {
+		observation := observations[i]
 		updateDateData := observation["d"]
 
 		if updateDate, ok := updateDateData.(string); ok {
@@ -83,12 +163,12 @@ func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponse(c core.Conte
 		rate, err := utils.StringToFloat64(exchangeRate)
 
 		if err != nil {
-			log.Warnf(c, "[bank_of_canada_datasource.ToLatestExchangeRateResponse] failed to parse rate, rate is %s", exchangeRate)
+			log.Warnf(c, "[bank_of_canada_datasource.toBankOfCanadaExchangeRateResponse] failed to parse rate, rate is %s", exchangeRate)
 			continue
 		}
 
 		if rate <= 0 {
-			log.Warnf(c, "[bank_of_canada_datasource.ToLatestExchangeRateResponse] rate is invalid, rate is %s", exchangeRate)
+			log.Warnf(c, "[bank_of_canada_datasource.toBankOfCanadaExchangeRateResponse] rate is invalid, rate is %s", exchangeRate)
 			continue
 		}
 
@@ -107,7 +187,7 @@ func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponse(c core.Conte
 	timezone, err := time.LoadLocation(bankOfCanadaDataUpdateDateTimezone)
 
 	if err != nil {
-		log.Errorf(c, "[bank_of_canada_datasource.ToLatestExchangeRateResponse] failed to get timezone, timezone name is %s", bankOfCanadaDataUpdateDateTimezone)
+		log.Errorf(c, "[bank_of_canada_datasource.toBankOfCanadaExchangeRateResponse] failed to get timezone, timezone name is %s", bankOfCanadaDataUpdateDateTimezone)
 		return nil
 	}
 
@@ -115,7 +195,7 @@ func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponse(c core.Conte
 	updateTime, err := time.ParseInLocation(bankOfCanadaDataUpdateDateFormat, updateDateTime, timezone)
 
 	if err != nil {
-		log.Errorf(c, "[bank_of_canada_datasource.ToLatestExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
+		log.Errorf(c, "[bank_of_canada_datasource.toBankOfCanadaExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
 		return nil
 	}
 
@@ -129,34 +209,3 @@ func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponse(c core.Conte
 
 	return latestExchangeRateResp
 }
-
-// BuildRequests returns the bank of Canada exchange rates http requests
-func (e *BankOfCanadaDataSource) BuildRequests() ([]*http.Request, error) {
-	req, err := http.NewRequest("GET", bankOfCanadaExchangeRateUrl, nil)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return []*http.Request{req}, nil
-}
-
-// Parse returns the common response entity according to the bank of Canada data source raw response
-func (e *BankOfCanadaDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
-	bankOfCanadaData := &BankOfCanadaExchangeRateData{}
-	err := json.Unmarshal(content, bankOfCanadaData)
-
-	if err != nil {
-		log.Errorf(c, "[bank_of_canada_datasource.Parse] failed to parse json data, content is %s, because %s", string(content), err.Error())
-		return nil, errs.ErrFailedToRequestRemoteApi
-	}
-
-	latestExchangeRateResponse := bankOfCanadaData.ToLatestExchangeRateResponse(c)
-
-	if latestExchangeRateResponse == nil {
-		log.Errorf(c, "[bank_of_canada_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
-		return nil, errs.ErrFailedToRequestRemoteApi
-	}
-
-	return latestExchangeRateResponse, nil
-}
@@ -195,3 +195,41 @@ func TestBankOfCanadaDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestBankOfCanadaDataSource_HistoricalDataExtractEveryDay(t *testing.T) {
+	dataSource := &BankOfCanadaDataSource{}
+	context := core.NewNullContext()
+
+	actualExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(bankOfCanadaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualExchangeRateResponses))
+
+	assert.Equal(t, "CAD", actualExchangeRateResponses[0].BaseCurrency)
+	assert.Equal(t, int64(1577827800), actualExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, 1, len(actualExchangeRateResponses[0].ExchangeRates))
+	assert.Equal(t, "VND", actualExchangeRateResponses[0].ExchangeRates[0].Currency)
+
+	assert.Equal(t, "CAD", actualExchangeRateResponses[1].BaseCurrency)
+	assert.Equal(t, int64(1617309000), actualExchangeRateResponses[1].UpdateTime)
+	assert.Contains(t, actualExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.7958615200955034",
+	})
+}
+
+func TestBankOfCanadaDataSource_HistoricalDataEmptyObservations(t *testing.T) {
+	dataSource := &BankOfCanadaDataSource{}
+	context := core.NewNullContext()
+
+	actualExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte("{\"observations\":[]}"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(actualExchangeRateResponses))
+}
+
+func TestBankOfCanadaDataSource_HistoricalDataBlankContent(t *testing.T) {
+	dataSource := &BankOfCanadaDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
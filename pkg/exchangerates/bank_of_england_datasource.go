@@ -0,0 +1,198 @@
+package exchangerates
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const bankOfEnglandExchangeRateUrlFormat = "https://www.bankofengland.co.uk/boeapps/database/_iadb-fromshowcolumns.asp?csv.x=yes&Datefrom=%s&Dateto=now&SeriesCodes=%s&CSVF=TN&UsingCodes=Y&VPD=Y&VFD=N"
+const bankOfEnglandExchangeRateReferenceUrl = "https://www.bankofengland.co.uk/boeapps/database/Rates.asp?into=GBP"
+const bankOfEnglandDataSource = "Bank of England"
+const bankOfEnglandBaseCurrency = "GBP"
+
+const bankOfEnglandRequestDateFormat = "02/Jan/2006"
+const bankOfEnglandDataDateFormat = "02 Jan 2006"
+const bankOfEnglandDataUpdateDateFormat = "02 Jan 2006 15:04"
+const bankOfEnglandDataUpdateDateTimezone = "Europe/London"
+
+const bankOfEnglandRequestRecentDays = 14
+
+var bankOfEnglandSeriesCodes = []string{
+	"XUDLADS", "XUDLCDS", "XUDLBK89", "XUDLBK25", "XUDLDKS", "XUDLERS", "XUDLHDS", "XUDLBK33",
+	"XUDLBK97", "XUDLBK78", "XUDLJYS", "XUDLBK83", "XUDLNDS", "XUDLNKS", "XUDLBK47", "XUDLSRS",
+	"XUDLSGS", "XUDLZRS", "XUDLBK93", "XUDLSKS", "XUDLSFS", "XUDLTWS", "XUDLBK87", "XUDLBK95", "XUDLUSS",
+}
+
+var bankOfEnglandSeriesCodeCurrencyMap = map[string]string{
+	"XUDLADS":  "AUD",
+	"XUDLCDS":  "CAD",
+	"XUDLBK89": "CNY",
+	"XUDLBK25": "CZK",
+	"XUDLDKS":  "DKK",
+	"XUDLERS":  "EUR",
+	"XUDLHDS":  "HKD",
+	"XUDLBK33": "HUF",
+	"XUDLBK97": "INR",
+	"XUDLBK78": "ILS",
+	"XUDLJYS":  "JPY",
+	"XUDLBK83": "MYR",
+	"XUDLNDS":  "NZD",
+	"XUDLNKS":  "NOK",
+	"XUDLBK47": "PLN",
+	"XUDLSRS":  "SAR",
+	"XUDLSGS":  "SGD",
+	"XUDLZRS":  "ZAR",
+	"XUDLBK93": "KRW",
+	"XUDLSKS":  "SEK",
+	"XUDLSFS":  "CHF",
+	"XUDLTWS":  "TWD",
+	"XUDLBK87": "THB",
+	"XUDLBK95": "TRY",
+	"XUDLUSS":  "USD",
+}
+
+// BankOfEnglandDataSource defines the structure of exchange rates data source of Bank of England
+type BankOfEnglandDataSource struct {
+	HttpExchangeRatesDataSource
+}
+
+// BuildRequests returns the Bank of England exchange rates http requests
+func (e *BankOfEnglandDataSource) BuildRequests() ([]*http.Request, error) {
+	startDate := time.Now().AddDate(0, 0, -bankOfEnglandRequestRecentDays).Format(bankOfEnglandRequestDateFormat)
+	url := fmt.Sprintf(bankOfEnglandExchangeRateUrlFormat, startDate, strings.Join(bankOfEnglandSeriesCodes, ","))
+	req, err := http.NewRequest("GET", url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// Parse returns the common response entity according to the Bank of England data source raw response
+func (e *BankOfEnglandDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	csvReader := csv.NewReader(bytes.NewReader(content))
+	csvReader.FieldsPerRecord = -1
+	allLines, err := csvReader.ReadAll()
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_england_datasource.Parse] failed to parse csv data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	if len(allLines) < 2 {
+		log.Errorf(c, "[bank_of_england_datasource.Parse] content is invalid, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	titleLine := allLines[0]
+
+	if len(titleLine) < 2 || strings.TrimSpace(titleLine[0]) != "DATE" {
+		log.Errorf(c, "[bank_of_england_datasource.Parse] title line is invalid, title line is %s", strings.Join(titleLine, ","))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	var latestDate time.Time
+	var latestExchangeRates models.LatestExchangeRateSlice
+
+	for i := 1; i < len(allLines); i++ {
+		line := allLines[i]
+
+		if len(line) < 2 {
+			continue
+		}
+
+		date, err := time.Parse(bankOfEnglandDataDateFormat, strings.TrimSpace(line[0]))
+
+		if err != nil {
+			log.Warnf(c, "[bank_of_england_datasource.Parse] failed to parse date, line is %s", strings.Join(line, ","))
+			continue
+		}
+
+		if !latestDate.IsZero() && !date.After(latestDate) {
+			continue
+		}
+
+		exchangeRates := make(models.LatestExchangeRateSlice, 0, len(line)-1)
+
+		for j := 1; j < len(line) && j < len(titleLine); j++ {
+			exchangeRate := e.parseExchangeRate(c, strings.TrimSpace(titleLine[j]), strings.TrimSpace(line[j]))
+
+			if exchangeRate != nil {
+				exchangeRates = append(exchangeRates, exchangeRate)
+			}
+		}
+
+		if len(exchangeRates) < 1 {
+			continue
+		}
+
+		latestDate = date
+		latestExchangeRates = exchangeRates
+	}
+
+	if len(latestExchangeRates) < 1 {
+		log.Errorf(c, "[bank_of_england_datasource.Parse] there is no exchange rate data in content, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	timezone, err := time.LoadLocation(bankOfEnglandDataUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_england_datasource.Parse] failed to get timezone, timezone name is %s", bankOfEnglandDataUpdateDateTimezone)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	updateDateTime := latestDate.Format(bankOfEnglandDataDateFormat) + " 16:00" // The spot exchange rates are observed at 4pm London time
+	updateTime, err := time.ParseInLocation(bankOfEnglandDataUpdateDateFormat, updateDateTime, timezone)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_england_datasource.Parse] failed to parse update date, datetime is %s", updateDateTime)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    bankOfEnglandDataSource,
+		ReferenceUrl:  bankOfEnglandExchangeRateReferenceUrl,
+		UpdateTime:    updateTime.Unix(),
+		BaseCurrency:  bankOfEnglandBaseCurrency,
+		ExchangeRates: latestExchangeRates,
+	}
+
+	return latestExchangeRateResp, nil
+}
+
+func (e *BankOfEnglandDataSource) parseExchangeRate(c core.Context, seriesCode string, value string) *models.LatestExchangeRate {
+	currency, exists := bankOfEnglandSeriesCodeCurrencyMap[seriesCode]
+
+	if !exists || value == "" {
+		return nil
+	}
+
+	rate, err := utils.StringToFloat64(value)
+
+	if err != nil {
+		log.Warnf(c, "[bank_of_england_datasource.parseExchangeRate] failed to parse rate, currency is %s, rate is %s", currency, value)
+		return nil
+	}
+
+	if rate <= 0 {
+		log.Warnf(c, "[bank_of_england_datasource.parseExchangeRate] rate is invalid, currency is %s, rate is %s", currency, value)
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: currency,
+		Rate:     value,
+	}
+}
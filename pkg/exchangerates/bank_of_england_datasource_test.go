@@ -0,0 +1,94 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const bankOfEnglandMinimumRequiredContent = "DATE,XUDLUSS,XUDLERS\n" +
+	"02 Jan 2024,1.2627,1.1542\n" +
+	"03 Jan 2024,1.2652,1.1571\n"
+
+func TestBankOfEnglandDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfEnglandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "GBP", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestBankOfEnglandDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfEnglandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1704297600), actualLatestExchangeRateResponse.UpdateTime)
+}
+
+func TestBankOfEnglandDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfEnglandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 2)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.2652",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "1.1571",
+	})
+}
+
+func TestBankOfEnglandDataSource_InvalidContent(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"blank content", ""},
+		{"only title line", "DATE,XUDLUSS\n"},
+		{"invalid title line", "SERIES,XUDLUSS\n02 Jan 2024,1.2627\n"},
+		{"invalid date", "DATE,XUDLUSS\n2024-01-02,1.2627\n"},
+		{"no available rate", "DATE,XUDLUSS\n02 Jan 2024,\n"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &BankOfEnglandDataSource{}
+		context := core.NewNullContext()
+
+		_, err := dataSource.Parse(context, []byte(tc.content))
+		assert.NotEqual(t, nil, err, tc.name)
+	}
+}
+
+func TestBankOfEnglandDataSource_InvalidRates(t *testing.T) {
+	testCases := []struct {
+		name       string
+		seriesCode string
+		rate       string
+	}{
+		{"unknown series code", "XUDLXXX", "1.2627"},
+		{"empty rate", "XUDLUSS", ""},
+		{"invalid rate", "XUDLUSS", "null"},
+		{"zero rate", "XUDLUSS", "0"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &BankOfEnglandDataSource{}
+		context := core.NewNullContext()
+
+		actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("DATE,"+tc.seriesCode+",XUDLERS\n"+
+			"02 Jan 2024,"+tc.rate+",1.1542\n"))
+		assert.Equal(t, nil, err, tc.name)
+		assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1, tc.name)
+		assert.Equal(t, "EUR", actualLatestExchangeRateResponse.ExchangeRates[0].Currency, tc.name)
+	}
+}
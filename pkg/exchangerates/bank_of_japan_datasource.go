@@ -0,0 +1,196 @@
+package exchangerates
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const bankOfJapanExchangeRateUrlFormat = "https://www.stat-search.boj.or.jp/api/v1/getDataCode?format=json&lang=en&db=FM08&startDate=%s&code=FXERD04,FXERD34"
+const bankOfJapanExchangeRateReferenceUrl = "https://www.boj.or.jp/en/statistics/market/forex/fxdaily/index.htm"
+const bankOfJapanDataSource = "Bank of Japan"
+const bankOfJapanBaseCurrency = "JPY"
+
+const bankOfJapanRequestDateFormat = "200601"
+const bankOfJapanDataUpdateDateFormat = "20060102 15:04"
+const bankOfJapanDataUpdateDateTimezone = "Asia/Tokyo"
+
+var bankOfJapanSeriesCodeCurrencyMap = map[string]string{
+	"FXERD04": "USD", // US.Dollar/Japanese Yen Spot Rate at 17:00 in JST, Central Rate in the Tokyo Market
+	"FXERD34": "EUR", // Euro/Japanese Yen Spot Rate at 17:00 in JST, Central Rate in the Tokyo Market
+}
+
+// BankOfJapanDataSource defines the structure of exchange rates data source of Bank of Japan
+type BankOfJapanDataSource struct {
+	HttpExchangeRatesDataSource
+}
+
+// BankOfJapanExchangeRateData represents the whole data from Bank of Japan
+type BankOfJapanExchangeRateData struct {
+	Status    int                          `json:"STATUS"`
+	ResultSet []*BankOfJapanTimeSeriesData `json:"RESULTSET"`
+}
+
+// BankOfJapanTimeSeriesData represents the time series data from Bank of Japan
+type BankOfJapanTimeSeriesData struct {
+	SeriesCode string                           `json:"SERIES_CODE"`
+	Values     *BankOfJapanTimeSeriesValuesData `json:"VALUES"`
+}
+
+// BankOfJapanTimeSeriesValuesData represents the values of time series data from Bank of Japan
+type BankOfJapanTimeSeriesValuesData struct {
+	SurveyDates []int32    `json:"SURVEY_DATES"`
+	Values      []*float64 `json:"VALUES"`
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to original data from Bank of Japan
+func (e *BankOfJapanExchangeRateData) ToLatestExchangeRateResponse(c core.Context) *models.LatestExchangeRateResponse {
+	if len(e.ResultSet) < 1 {
+		log.Errorf(c, "[bank_of_japan_datasource.ToLatestExchangeRateResponse] result set is empty")
+		return nil
+	}
+
+	latestDate := int32(0)
+
+	for i := 0; i < len(e.ResultSet); i++ {
+		timeSeries := e.ResultSet[i]
+
+		if timeSeries == nil || timeSeries.Values == nil {
+			continue
+		}
+
+		if _, exists := bankOfJapanSeriesCodeCurrencyMap[timeSeries.SeriesCode]; !exists {
+			continue
+		}
+
+		for j := 0; j < len(timeSeries.Values.SurveyDates) && j < len(timeSeries.Values.Values); j++ {
+			if timeSeries.Values.Values[j] != nil && timeSeries.Values.SurveyDates[j] > latestDate {
+				latestDate = timeSeries.Values.SurveyDates[j]
+			}
+		}
+	}
+
+	if latestDate <= 0 {
+		log.Errorf(c, "[bank_of_japan_datasource.ToLatestExchangeRateResponse] there is no exchange rate data in result set")
+		return nil
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(e.ResultSet))
+
+	for i := 0; i < len(e.ResultSet); i++ {
+		exchangeRate := e.ResultSet[i].ToLatestExchangeRate(c, latestDate)
+
+		if exchangeRate != nil {
+			exchangeRates = append(exchangeRates, exchangeRate)
+		}
+	}
+
+	timezone, err := time.LoadLocation(bankOfJapanDataUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_japan_datasource.ToLatestExchangeRateResponse] failed to get timezone, timezone name is %s", bankOfJapanDataUpdateDateTimezone)
+		return nil
+	}
+
+	updateDateTime := utils.Int64ToString(int64(latestDate)) + " 17:00" // The central rates are observed at 17:00 in JST
+	updateTime, err := time.ParseInLocation(bankOfJapanDataUpdateDateFormat, updateDateTime, timezone)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_japan_datasource.ToLatestExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
+		return nil
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    bankOfJapanDataSource,
+		ReferenceUrl:  bankOfJapanExchangeRateReferenceUrl,
+		UpdateTime:    updateTime.Unix(),
+		BaseCurrency:  bankOfJapanBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp
+}
+
+// ToLatestExchangeRate returns a data pair of the specified date according to original data from Bank of Japan
+func (e *BankOfJapanTimeSeriesData) ToLatestExchangeRate(c core.Context, date int32) *models.LatestExchangeRate {
+	if e == nil || e.Values == nil {
+		return nil
+	}
+
+	currency, exists := bankOfJapanSeriesCodeCurrencyMap[e.SeriesCode]
+
+	if !exists {
+		return nil
+	}
+
+	for i := 0; i < len(e.Values.SurveyDates) && i < len(e.Values.Values); i++ {
+		if e.Values.SurveyDates[i] != date || e.Values.Values[i] == nil {
+			continue
+		}
+
+		rate := *e.Values.Values[i]
+
+		if rate <= 0 {
+			log.Warnf(c, "[bank_of_japan_datasource.ToLatestExchangeRate] rate is invalid, currency is %s, rate is %f", currency, rate)
+			return nil
+		}
+
+		finalRate := 1 / rate
+
+		if math.IsInf(finalRate, 0) {
+			return nil
+		}
+
+		return &models.LatestExchangeRate{
+			Currency: currency,
+			Rate:     utils.Float64ToString(finalRate),
+		}
+	}
+
+	return nil
+}
+
+// BuildRequests returns the Bank of Japan exchange rates http requests
+func (e *BankOfJapanDataSource) BuildRequests() ([]*http.Request, error) {
+	startDate := time.Now().AddDate(0, -1, 0).Format(bankOfJapanRequestDateFormat)
+	req, err := http.NewRequest("GET", fmt.Sprintf(bankOfJapanExchangeRateUrlFormat, startDate), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// Parse returns the common response entity according to the Bank of Japan data source raw response
+func (e *BankOfJapanDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	bankOfJapanData := &BankOfJapanExchangeRateData{}
+	err := json.Unmarshal(content, &bankOfJapanData)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_japan_datasource.Parse] failed to parse json data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	if bankOfJapanData.Status != http.StatusOK {
+		log.Errorf(c, "[bank_of_japan_datasource.Parse] response status is %d, content is %s", bankOfJapanData.Status, string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResponse := bankOfJapanData.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.Errorf(c, "[bank_of_japan_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
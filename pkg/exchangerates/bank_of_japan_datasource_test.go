@@ -0,0 +1,95 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const bankOfJapanMinimumRequiredContent = "{\"STATUS\":200,\"MESSAGEID\":\"M181000I\",\"RESULTSET\":[" +
+	"{\"SERIES_CODE\":\"FXERD04\",\"UNIT\":\"Yen per U.S.Dollar\",\"VALUES\":{\"SURVEY_DATES\":[20240104,20240105],\"VALUES\":[144.5,null]}}," +
+	"{\"SERIES_CODE\":\"FXERD34\",\"UNIT\":\"Yen per Euro\",\"VALUES\":{\"SURVEY_DATES\":[20240104,20240105],\"VALUES\":[158.06,null]}}" +
+	"]}"
+
+func TestBankOfJapanDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfJapanMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "JPY", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestBankOfJapanDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfJapanMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1704355200), actualLatestExchangeRateResponse.UpdateTime)
+}
+
+func TestBankOfJapanDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfJapanMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 2)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.006920415224913495",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "0.006326711375427053",
+	})
+}
+
+func TestBankOfJapanDataSource_InvalidContent(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"blank content", ""},
+		{"empty json object", "{}"},
+		{"error status", "{\"STATUS\":400,\"MESSAGEID\":\"M181005E\",\"RESULTSET\":[]}"},
+		{"empty result set", "{\"STATUS\":200,\"RESULTSET\":[]}"},
+		{"unknown series code", "{\"STATUS\":200,\"RESULTSET\":[{\"SERIES_CODE\":\"FXERD99\",\"VALUES\":{\"SURVEY_DATES\":[20240104],\"VALUES\":[144.5]}}]}"},
+		{"no available rate", "{\"STATUS\":200,\"RESULTSET\":[{\"SERIES_CODE\":\"FXERD04\",\"VALUES\":{\"SURVEY_DATES\":[20240104],\"VALUES\":[null]}}]}"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &BankOfJapanDataSource{}
+		context := core.NewNullContext()
+
+		_, err := dataSource.Parse(context, []byte(tc.content))
+		assert.NotEqual(t, nil, err, tc.name)
+	}
+}
+
+func TestBankOfJapanDataSource_InvalidRates(t *testing.T) {
+	testCases := []struct {
+		name string
+		rate string
+	}{
+		{"zero rate", "0"},
+		{"negative rate", "-144.5"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &BankOfJapanDataSource{}
+		context := core.NewNullContext()
+
+		actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("{\"STATUS\":200,\"RESULTSET\":["+
+			"{\"SERIES_CODE\":\"FXERD04\",\"VALUES\":{\"SURVEY_DATES\":[20240104],\"VALUES\":["+tc.rate+"]}},"+
+			"{\"SERIES_CODE\":\"FXERD34\",\"VALUES\":{\"SURVEY_DATES\":[20240104],\"VALUES\":[158.06]}}"+
+			"]}"))
+		assert.Equal(t, nil, err, tc.name)
+		assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1, tc.name)
+		assert.Equal(t, "EUR", actualLatestExchangeRateResponse.ExchangeRates[0].Currency, tc.name)
+	}
+}
@@ -0,0 +1,194 @@
+package exchangerates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+const bankOfThailandExchangeRateUrlFormat = "https://gateway.api.bot.or.th/Stat-ExchangeRate/v2/DAILY_AVG_EXG_RATE/?start_period=%s&end_period=%s"
+const bankOfThailandExchangeRateReferenceUrl = "https://www.bot.or.th/en/statistics/exchange-rate.html"
+const bankOfThailandDataSource = "Bank of Thailand"
+const bankOfThailandBaseCurrency = "THB"
+
+const bankOfThailandRequestDateFormat = "2006-01-02"
+const bankOfThailandDataUpdateDateFormat = "2006-01-02 15:04"
+const bankOfThailandDataUpdateDateTimezone = "Asia/Bangkok"
+
+const bankOfThailandRequestRecentDays = 10
+
+// The rates of some currencies are quoted per 100 or 1000 units
+var bankOfThailandCurrencyUnits = map[string]float64{
+	"JPY": 100,
+	"IDR": 1000,
+}
+
+// BankOfThailandDataSource defines the structure of exchange rates data source of Bank of Thailand
+type BankOfThailandDataSource struct {
+	HttpExchangeRatesDataSource
+	apiKey string
+}
+
+// BankOfThailandExchangeRateData represents the whole data from Bank of Thailand
+type BankOfThailandExchangeRateData struct {
+	Result *BankOfThailandExchangeRateResult `json:"result"`
+}
+
+// BankOfThailandExchangeRateResult represents the result data from Bank of Thailand
+type BankOfThailandExchangeRateResult struct {
+	Data *BankOfThailandExchangeRateResultData `json:"data"`
+}
+
+// BankOfThailandExchangeRateResultData represents the data of result from Bank of Thailand
+type BankOfThailandExchangeRateResultData struct {
+	DataDetail []*BankOfThailandExchangeRate `json:"data_detail"`
+}
+
+// BankOfThailandExchangeRate represents the exchange rate data from Bank of Thailand
+type BankOfThailandExchangeRate struct {
+	Period     string `json:"period"`
+	CurrencyId string `json:"currency_id"`
+	MidRate    string `json:"mid_rate"`
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to original data from Bank of Thailand
+func (e *BankOfThailandExchangeRateData) ToLatestExchangeRateResponse(c core.Context) *models.LatestExchangeRateResponse {
+	if e.Result == nil || e.Result.Data == nil || len(e.Result.Data.DataDetail) < 1 {
+		log.Errorf(c, "[bank_of_thailand_datasource.ToLatestExchangeRateResponse] data detail is empty")
+		return nil
+	}
+
+	dataDetail := e.Result.Data.DataDetail
+	latestDate := ""
+
+	for i := 0; i < len(dataDetail); i++ {
+		if dataDetail[i] != nil && dataDetail[i].MidRate != "" && dataDetail[i].Period > latestDate {
+			latestDate = dataDetail[i].Period
+		}
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(dataDetail))
+
+	for i := 0; i < len(dataDetail); i++ {
+		if dataDetail[i] == nil || dataDetail[i].Period != latestDate {
+			continue
+		}
+
+		exchangeRate := dataDetail[i].ToLatestExchangeRate(c)
+
+		if exchangeRate != nil {
+			exchangeRates = append(exchangeRates, exchangeRate)
+		}
+	}
+
+	timezone, err := time.LoadLocation(bankOfThailandDataUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_thailand_datasource.ToLatestExchangeRateResponse] failed to get timezone, timezone name is %s", bankOfThailandDataUpdateDateTimezone)
+		return nil
+	}
+
+	updateDateTime := latestDate + " 18:00" // The average exchange rates are published at around 6 PM every working day
+	updateTime, err := time.ParseInLocation(bankOfThailandDataUpdateDateFormat, updateDateTime, timezone)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_thailand_datasource.ToLatestExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
+		return nil
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    bankOfThailandDataSource,
+		ReferenceUrl:  bankOfThailandExchangeRateReferenceUrl,
+		UpdateTime:    updateTime.Unix(),
+		BaseCurrency:  bankOfThailandBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp
+}
+
+// ToLatestExchangeRate returns a data pair according to original data from Bank of Thailand
+func (e *BankOfThailandExchangeRate) ToLatestExchangeRate(c core.Context) *models.LatestExchangeRate {
+	if _, exists := validators.AllCurrencyNames[e.CurrencyId]; !exists {
+		return nil
+	}
+
+	rate, err := utils.StringToFloat64(e.MidRate)
+
+	if err != nil {
+		log.Warnf(c, "[bank_of_thailand_datasource.ToLatestExchangeRate] failed to parse rate, currency is %s, rate is %s", e.CurrencyId, e.MidRate)
+		return nil
+	}
+
+	if rate <= 0 {
+		log.Warnf(c, "[bank_of_thailand_datasource.ToLatestExchangeRate] rate is invalid, currency is %s, rate is %s", e.CurrencyId, e.MidRate)
+		return nil
+	}
+
+	unit, exists := bankOfThailandCurrencyUnits[e.CurrencyId]
+
+	if !exists {
+		unit = 1
+	}
+
+	finalRate := unit / rate
+
+	if math.IsInf(finalRate, 0) {
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: e.CurrencyId,
+		Rate:     utils.Float64ToString(finalRate),
+	}
+}
+
+// BuildRequests returns the Bank of Thailand exchange rates http requests
+func (e *BankOfThailandDataSource) BuildRequests() ([]*http.Request, error) {
+	if e.apiKey == "" {
+		return nil, errors.New("api key of bank of thailand is not set")
+	}
+
+	now := time.Now()
+	startDate := now.AddDate(0, 0, -bankOfThailandRequestRecentDays).Format(bankOfThailandRequestDateFormat)
+	endDate := now.Format(bankOfThailandRequestDateFormat)
+	req, err := http.NewRequest("GET", fmt.Sprintf(bankOfThailandExchangeRateUrlFormat, startDate, endDate), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", e.apiKey)
+
+	return []*http.Request{req}, nil
+}
+
+// Parse returns the common response entity according to the Bank of Thailand data source raw response
+func (e *BankOfThailandDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	bankOfThailandData := &BankOfThailandExchangeRateData{}
+	err := json.Unmarshal(content, &bankOfThailandData)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_thailand_datasource.Parse] failed to parse json data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResponse := bankOfThailandData.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.Errorf(c, "[bank_of_thailand_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
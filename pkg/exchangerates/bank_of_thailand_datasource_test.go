@@ -0,0 +1,115 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const bankOfThailandMinimumRequiredContent = "{\"result\":{\"timestamp\":\"2024-01-05 18:16:40\",\"data\":{\"data_detail\":[" +
+	"{\"period\":\"2024-01-05\",\"currency_id\":\"USD\",\"mid_rate\":\"34.4000\"}," +
+	"{\"period\":\"2024-01-05\",\"currency_id\":\"JPY\",\"mid_rate\":\"23.8000\"}," +
+	"{\"period\":\"2024-01-04\",\"currency_id\":\"USD\",\"mid_rate\":\"34.2000\"}" +
+	"]}}}"
+
+func TestBankOfThailandDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &BankOfThailandDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfThailandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "THB", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestBankOfThailandDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &BankOfThailandDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfThailandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1704452400), actualLatestExchangeRateResponse.UpdateTime)
+}
+
+func TestBankOfThailandDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &BankOfThailandDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfThailandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 2)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.029069767441860465",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "4.201680672268908",
+	})
+}
+
+func TestBankOfThailandDataSource_BuildRequestsWithoutApiKey(t *testing.T) {
+	dataSource := &BankOfThailandDataSource{}
+
+	_, err := dataSource.BuildRequests()
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfThailandDataSource_BuildRequestsWithApiKey(t *testing.T) {
+	dataSource := &BankOfThailandDataSource{
+		apiKey: "test-api-key",
+	}
+
+	requests, err := dataSource.BuildRequests()
+	assert.Equal(t, nil, err)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "test-api-key", requests[0].Header.Get("Authorization"))
+}
+
+func TestBankOfThailandDataSource_InvalidContent(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"blank content", ""},
+		{"empty json object", "{}"},
+		{"empty result", "{\"result\":{}}"},
+		{"empty data detail", "{\"result\":{\"data\":{\"data_detail\":[]}}}"},
+		{"invalid period", "{\"result\":{\"data\":{\"data_detail\":[{\"period\":\"05/01/2024\",\"currency_id\":\"USD\",\"mid_rate\":\"34.4\"}]}}}"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &BankOfThailandDataSource{}
+		context := core.NewNullContext()
+
+		_, err := dataSource.Parse(context, []byte(tc.content))
+		assert.NotEqual(t, nil, err, tc.name)
+	}
+}
+
+func TestBankOfThailandDataSource_InvalidRates(t *testing.T) {
+	testCases := []struct {
+		name     string
+		currency string
+		rate     string
+	}{
+		{"invalid currency", "XXX", "34.4"},
+		{"invalid rate", "USD", "null"},
+		{"zero rate", "USD", "0"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &BankOfThailandDataSource{}
+		context := core.NewNullContext()
+
+		actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("{\"result\":{\"data\":{\"data_detail\":["+
+			"{\"period\":\"2024-01-05\",\"currency_id\":\""+tc.currency+"\",\"mid_rate\":\""+tc.rate+"\"},"+
+			"{\"period\":\"2024-01-05\",\"currency_id\":\"EUR\",\"mid_rate\":\"37.6\"}"+
+			"]}}}"))
+		assert.Equal(t, nil, err, tc.name)
+		assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1, tc.name)
+		assert.Equal(t, "EUR", actualLatestExchangeRateResponse.ExchangeRates[0].Currency, tc.name)
+	}
+}
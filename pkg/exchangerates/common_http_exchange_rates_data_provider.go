@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -22,6 +23,17 @@ type HttpExchangeRatesDataSource interface {
 	Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error)
 }
 
+// HttpHistoricalExchangeRatesDataSource defines the structure of http exchange rates data source which supports historical exchange rates
+type HttpHistoricalExchangeRatesDataSource interface {
+	HttpExchangeRatesDataSource
+
+	// BuildHistoricalRequests returns the http requests of the exchange rates between the start time and the end time
+	BuildHistoricalRequests(startTime time.Time, endTime time.Time) ([]*http.Request, error)
+
+	// ParseHistorical returns the common response entities of every day according to the data source raw response
+	ParseHistorical(c core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error)
+}
+
 // CommonHttpExchangeRatesDataProvider defines the structure of common http exchange rates data provider
 type CommonHttpExchangeRatesDataProvider struct {
 	ExchangeRatesDataProvider
@@ -103,6 +115,84 @@ func (e *CommonHttpExchangeRatesDataProvider) GetLatestExchangeRates(c core.Cont
 	return finalExchangeRateResponse, nil
 }
 
+// GetHistoricalExchangeRates returns the exchange rates of every day between the start time and the end time (both dates are inclusive and in UTC) from the data source
+func (e *CommonHttpExchangeRatesDataProvider) GetHistoricalExchangeRates(c core.Context, startTime time.Time, endTime time.Time) ([]*models.LatestExchangeRateResponse, error) {
+	dataSource, ok := e.dataSource.(HttpHistoricalExchangeRatesDataSource)
+
+	if !ok {
+		return nil, errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	requests, err := dataSource.BuildHistoricalRequests(startTime, endTime)
+
+	if err != nil {
+		log.Errorf(c, "[common_http_exchange_rates_data_provider.GetHistoricalExchangeRates] failed to build requests, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	startDate := utils.FormatUnixTimeToNumericYearMonthDay(startTime.Unix(), time.UTC)
+	endDate := utils.FormatUnixTimeToNumericYearMonthDay(endTime.Unix(), time.UTC)
+	allExchangeRateResps := make([]*models.LatestExchangeRateResponse, 0)
+
+	for i := 0; i < len(requests); i++ {
+		exchangeRateResps, err := e.requestHistoricalExchangeRates(c, dataSource, requests[i], i)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < len(exchangeRateResps); j++ {
+			rateDate := utils.FormatUnixTimeToNumericYearMonthDay(exchangeRateResps[j].UpdateTime, time.UTC)
+
+			if rateDate < startDate || rateDate > endDate {
+				continue
+			}
+
+			allExchangeRateResps = append(allExchangeRateResps, exchangeRateResps[j])
+		}
+	}
+
+	sort.SliceStable(allExchangeRateResps, func(i, j int) bool {
+		return allExchangeRateResps[i].UpdateTime < allExchangeRateResps[j].UpdateTime
+	})
+
+	return allExchangeRateResps, nil
+}
+
+// requestHistoricalExchangeRates sends one historical exchange rates request and closes its response body before returning
+func (e *CommonHttpExchangeRatesDataProvider) requestHistoricalExchangeRates(c core.Context, dataSource HttpHistoricalExchangeRatesDataSource, req *http.Request, index int) ([]*models.LatestExchangeRateResponse, error) {
+	resp, err := e.httpClient.Do(req)
+
+	if err != nil {
+		log.Errorf(c, "[common_http_exchange_rates_data_provider.requestHistoricalExchangeRates] failed to request historical exchange rate data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		log.Errorf(c, "[common_http_exchange_rates_data_provider.requestHistoricalExchangeRates] failed to read response#%d, because %s", index, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	log.Debugf(c, "[common_http_exchange_rates_data_provider.requestHistoricalExchangeRates] response#%d is %s", index, body)
+
+	if resp.StatusCode != 200 {
+		log.Errorf(c, "[common_http_exchange_rates_data_provider.requestHistoricalExchangeRates] failed to get historical exchange rate data response, because response code is %d", resp.StatusCode)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	exchangeRateResps, err := dataSource.ParseHistorical(c, body)
+
+	if err != nil {
+		log.Errorf(c, "[common_http_exchange_rates_data_provider.requestHistoricalExchangeRates] failed to parse response, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
+	}
+
+	return exchangeRateResps, nil
+}
+
 func newCommonHttpExchangeRatesDataProvider(config *settings.Config, dataSource HttpExchangeRatesDataSource) *CommonHttpExchangeRatesDataProvider {
 	return &CommonHttpExchangeRatesDataProvider{
 		dataSource: dataSource,
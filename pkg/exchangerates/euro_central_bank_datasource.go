@@ -17,6 +17,8 @@ import (
 )
 
 const euroCentralBankExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+const euroCentralBankRecentHistoricalExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+const euroCentralBankAllHistoricalExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
 const euroCentralBankExchangeRateReferenceUrl = "https://www.ecb.europa.eu/stats/policy_and_exchange_rates/euro_reference_exchange_rates/html/index.en.html"
 const euroCentralBankDataSource = "European Central Bank"
 const euroCentralBankBaseCurrency = "EUR"
@@ -24,6 +26,8 @@ const euroCentralBankBaseCurrency = "EUR"
 const euroCentralBankDataUpdateDateFormat = "2006-01-02 15"
 const euroCentralBankDataUpdateDateTimezone = "Europe/Berlin"
 
+const euroCentralBankRecentHistoricalExchangeRateDays = 90
+
 // EuroCentralBankDataSource defines the structure of exchange rates data source of euro central bank
 type EuroCentralBankDataSource struct {
 	HttpExchangeRatesDataSource
@@ -54,17 +58,37 @@ func (e *EuroCentralBankExchangeRateData) ToLatestExchangeRateResponse(c core.Co
 		return nil
 	}
 
-	latestEuroCentralBankExchangeRate := e.AllExchangeRates[0]
+	return e.AllExchangeRates[0].ToLatestExchangeRateResponse(c)
+}
+
+// ToHistoricalExchangeRateResponses returns the view-objects of every day according to original data from euro central bank
+func (e *EuroCentralBankExchangeRateData) ToHistoricalExchangeRateResponses(c core.Context) []*models.LatestExchangeRateResponse {
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(e.AllExchangeRates))
+
+	for i := 0; i < len(e.AllExchangeRates); i++ {
+		exchangeRateResp := e.AllExchangeRates[i].ToLatestExchangeRateResponse(c)
+
+		if exchangeRateResp == nil {
+			continue
+		}
+
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	}
 
-	if len(latestEuroCentralBankExchangeRate.ExchangeRates) < 1 {
+	return exchangeRateResps
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to original data of one day from euro central bank
+func (e *EuroCentralBankExchangeRates) ToLatestExchangeRateResponse(c core.Context) *models.LatestExchangeRateResponse {
+	if len(e.ExchangeRates) < 1 {
 		log.Errorf(c, "[euro_central_bank_datasource.ToLatestExchangeRateResponse] exchange rates is empty")
 		return nil
 	}
 
-	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(latestEuroCentralBankExchangeRate.ExchangeRates))
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(e.ExchangeRates))
 
-	for i := 0; i < len(latestEuroCentralBankExchangeRate.ExchangeRates); i++ {
-		exchangeRate := latestEuroCentralBankExchangeRate.ExchangeRates[i]
+	for i := 0; i < len(e.ExchangeRates); i++ {
+		exchangeRate := e.ExchangeRates[i]
 
 		if _, exists := validators.AllCurrencyNames[exchangeRate.Currency]; !exists {
 			continue
@@ -84,7 +108,7 @@ func (e *EuroCentralBankExchangeRateData) ToLatestExchangeRateResponse(c core.Co
 		return nil
 	}
 
-	updateDateTime := latestEuroCentralBankExchangeRate.Date + " 16" // The reference rates are usually updated around 16:00 CET on every working day
+	updateDateTime := e.Date + " 16" // The reference rates are usually updated around 16:00 CET on every working day
 	updateTime, err := time.ParseInLocation(euroCentralBankDataUpdateDateFormat, updateDateTime, timezone)
 
 	if err != nil {
@@ -122,6 +146,40 @@ func (e *EuroCentralBankDataSource) BuildRequests() ([]*http.Request, error) {
 	return []*http.Request{req}, nil
 }
 
+// BuildHistoricalRequests returns the euro central bank historical exchange rates http requests,
+// the data of the last 90 days would be requested if the start time is within the period, otherwise the whole historical data would be requested
+func (e *EuroCentralBankDataSource) BuildHistoricalRequests(startTime time.Time, endTime time.Time) ([]*http.Request, error) {
+	url := euroCentralBankAllHistoricalExchangeRateUrl
+
+	if time.Since(startTime) < euroCentralBankRecentHistoricalExchangeRateDays*24*time.Hour {
+		url = euroCentralBankRecentHistoricalExchangeRateUrl
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// ParseHistorical returns the common response entities of every day according to the euro central bank data source raw response
+func (e *EuroCentralBankDataSource) ParseHistorical(c core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
+	xmlDecoder.CharsetReader = charset.NewReaderLabel
+
+	euroCentralBankData := &EuroCentralBankExchangeRateData{}
+	err := xmlDecoder.Decode(euroCentralBankData)
+
+	if err != nil {
+		log.Errorf(c, "[euro_central_bank_datasource.ParseHistorical] failed to parse xml data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return euroCentralBankData.ToHistoricalExchangeRateResponses(c), nil
+}
+
 // Parse returns the common response entity according to the euro central bank data source raw response
 func (e *EuroCentralBankDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
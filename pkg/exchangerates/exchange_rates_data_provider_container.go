@@ -34,81 +34,43 @@ var (
 
 // InitializeExchangeRatesDataSource initializes the current exchange rates data source according to the config
 func InitializeExchangeRatesDataSource(config *settings.Config) error {
-	if config.ExchangeRatesDataSource == settings.ReserveBankOfAustraliaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &ReserveBankOfAustraliaDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfCanadaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &BankOfCanadaDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CzechNationalBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CzechNationalBankDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.DanmarksNationalbankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &DanmarksNationalbankDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.EuroCentralBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &EuroCentralBankDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfGeorgiaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfGeorgiaDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfHungaryDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfHungaryDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfIsraelDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &BankOfIsraelDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfMyanmarDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfMyanmarDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NorgesBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NorgesBankDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfPolandDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfPolandDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfRomaniaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfRomaniaDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfRussiaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &BankOfRussiaDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.SwissNationalBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &SwissNationalBankDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfUkraineDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfUkraineDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfUzbekistanDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfUzbekistanDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.InternationalMonetaryFundDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &InternationalMonetaryFundDataSource{})
-		Container.isCustom = false
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.UserCustomExchangeRatesDataSource {
+	dataSources := config.ExchangeRatesDataSources
+
+	if len(dataSources) < 1 {
+		dataSources = []string{config.ExchangeRatesDataSource}
+	}
+
+	if len(dataSources) == 1 && dataSources[0] == settings.UserCustomExchangeRatesDataSource {
 		Container.current = newUserCustomExchangeRatesDataProvider()
 		Container.isCustom = true
 		return nil
 	}
 
-	return errs.ErrInvalidExchangeRatesDataSource
+	providers := make([]ExchangeRatesDataProvider, 0, len(dataSources))
+
+	for i := 0; i < len(dataSources); i++ {
+		provider, err := newExchangeRatesDataProvider(config, dataSources[i])
+
+		if err != nil {
+			return err
+		}
+
+		providers = append(providers, provider)
+	}
+
+	if len(providers) == 1 {
+		Container.current = providers[0]
+	} else {
+		Container.current = newFallbackExchangeRatesDataProvider(providers)
+	}
+
+	Container.isCustom = false
+	return nil
+}
+
+// IsUserCustomDataSource returns whether the current exchange rates data source is the user custom exchange rates
+func (e *ExchangeRatesDataProviderContainer) IsUserCustomDataSource() bool {
+	return e.isCustom
 }
 
 // GetLatestExchangeRates returns the latest exchange rates data from the current exchange rates data source
@@ -156,3 +118,98 @@ func (e *ExchangeRatesDataProviderContainer) GetLatestExchangeRates(c core.Conte
 
 	return e.current.GetLatestExchangeRates(c, uid, currentConfig)
 }
+
+// GetHistoricalExchangeRates returns the exchange rates data of every day between the start time and the end time from the current exchange rates data source
+func (e *ExchangeRatesDataProviderContainer) GetHistoricalExchangeRates(c core.Context, startTime time.Time, endTime time.Time) ([]*models.LatestExchangeRateResponse, error) {
+	if e.current == nil {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if startTime.After(endTime) {
+		return nil, errs.ErrInvalidExchangeRatesDateRange
+	}
+
+	provider, ok := e.current.(HistoricalExchangeRatesDataProvider)
+
+	if !ok {
+		return nil, errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	return provider.GetHistoricalExchangeRates(c, startTime, endTime)
+}
+
+// GetUserCustomExchangeRatesAtTime returns the user custom exchange rates effective at the specified unix time,
+// the latest exchange rates would be returned if the current exchange rates data source is not user custom data source
+func (e *ExchangeRatesDataProviderContainer) GetUserCustomExchangeRatesAtTime(c core.Context, uid int64, unixTime int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	provider, ok := e.current.(*UserCustomExchangeRatesDataProvider)
+
+	if !ok {
+		return e.GetLatestExchangeRates(c, uid, currentConfig)
+	}
+
+	return provider.GetExchangeRatesAtTime(c, uid, unixTime, currentConfig)
+}
+
+// GetUserCustomExchangeRateHistories returns the daily exchange rate histories according to the user custom dated exchange rates
+func (e *ExchangeRatesDataProviderContainer) GetUserCustomExchangeRateHistories(c core.Context, uid int64, baseCurrency string, latestExchangeRates map[string]float64) (map[string][]*models.HistoricalExchangeRate, error) {
+	provider, ok := e.current.(*UserCustomExchangeRatesDataProvider)
+
+	if !ok {
+		return nil, errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	return provider.GetExchangeRateHistories(c, uid, baseCurrency, latestExchangeRates)
+}
+
+// newExchangeRatesDataProvider returns the exchange rates data provider of the specified data source
+func newExchangeRatesDataProvider(config *settings.Config, dataSource string) (ExchangeRatesDataProvider, error) {
+	if dataSource == settings.ReserveBankOfAustraliaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &ReserveBankOfAustraliaDataSource{}), nil
+	} else if dataSource == settings.BankOfCanadaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfCanadaDataSource{}), nil
+	} else if dataSource == settings.CzechNationalBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CzechNationalBankDataSource{}), nil
+	} else if dataSource == settings.DanmarksNationalbankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &DanmarksNationalbankDataSource{}), nil
+	} else if dataSource == settings.EuroCentralBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &EuroCentralBankDataSource{}), nil
+	} else if dataSource == settings.NationalBankOfGeorgiaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfGeorgiaDataSource{}), nil
+	} else if dataSource == settings.CentralBankOfHungaryDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfHungaryDataSource{}), nil
+	} else if dataSource == settings.BankOfIsraelDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfIsraelDataSource{}), nil
+	} else if dataSource == settings.CentralBankOfMyanmarDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfMyanmarDataSource{}), nil
+	} else if dataSource == settings.NorgesBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NorgesBankDataSource{}), nil
+	} else if dataSource == settings.NationalBankOfPolandDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfPolandDataSource{}), nil
+	} else if dataSource == settings.NationalBankOfRomaniaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfRomaniaDataSource{}), nil
+	} else if dataSource == settings.BankOfRussiaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfRussiaDataSource{}), nil
+	} else if dataSource == settings.SwissNationalBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &SwissNationalBankDataSource{}), nil
+	} else if dataSource == settings.NationalBankOfUkraineDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfUkraineDataSource{}), nil
+	} else if dataSource == settings.CentralBankOfUzbekistanDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfUzbekistanDataSource{}), nil
+	} else if dataSource == settings.InternationalMonetaryFundDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &InternationalMonetaryFundDataSource{}), nil
+	} else if dataSource == settings.FederalReserveDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &FederalReserveDataSource{}), nil
+	} else if dataSource == settings.BankOfEnglandDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfEnglandDataSource{}), nil
+	} else if dataSource == settings.BankOfJapanDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfJapanDataSource{}), nil
+	} else if dataSource == settings.ReserveBankOfIndiaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &ReserveBankOfIndiaDataSource{}), nil
+	} else if dataSource == settings.PeoplesBankOfChinaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &PeoplesBankOfChinaDataSource{}), nil
+	} else if dataSource == settings.BankOfThailandDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfThailandDataSource{apiKey: config.ExchangeRatesBankOfThailandAPIKey}), nil
+	}
+
+	return nil, errs.ErrInvalidExchangeRatesDataSource
+}
@@ -0,0 +1,115 @@
+package exchangerates
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+const fallbackExchangeRatesDataSource = "Exchange Rates Fallback Chain"
+
+// FallbackExchangeRatesDataProvider defines the structure of exchange rates data provider which requests an ordered list of data providers,
+// the exchange rates of the first available data provider are returned under the same data source name and relative to the base currency of that data provider,
+// and the actual data source of each exchange rate is returned in the exchange rate itself
+type FallbackExchangeRatesDataProvider struct {
+	ExchangeRatesDataProvider
+	providers []ExchangeRatesDataProvider
+}
+
+// GetLatestExchangeRates returns the latest exchange rates of the first available data provider, the following data providers are only requested when the previous ones fail
+func (e *FallbackExchangeRatesDataProvider) GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	var lastErr error = errs.ErrFailedToRequestRemoteApi
+
+	for i := 0; i < len(e.providers); i++ {
+		exchangeRateResponse, err := e.providers[i].GetLatestExchangeRates(c, uid, currentConfig)
+
+		if err != nil {
+			log.Warnf(c, "[fallback_exchange_rates_data_provider.GetLatestExchangeRates] failed to get latest exchange rates from data provider#%d for user \"uid:%d\", because %s", i, uid, err.Error())
+			lastErr = err
+			continue
+		}
+
+		if exchangeRateResponse == nil || exchangeRateResponse.BaseCurrency == "" || len(exchangeRateResponse.ExchangeRates) < 1 {
+			log.Warnf(c, "[fallback_exchange_rates_data_provider.GetLatestExchangeRates] data provider#%d returns no exchange rates for user \"uid:%d\"", i, uid)
+			continue
+		}
+
+		return e.getFallbackExchangeRateResponse(exchangeRateResponse), nil
+	}
+
+	return nil, lastErr
+}
+
+// GetHistoricalExchangeRates returns the historical exchange rates of the first data provider which supports historical exchange rates and is available
+func (e *FallbackExchangeRatesDataProvider) GetHistoricalExchangeRates(c core.Context, startTime time.Time, endTime time.Time) ([]*models.LatestExchangeRateResponse, error) {
+	var lastErr error = errs.ErrHistoricalExchangeRatesNotSupported
+
+	for i := 0; i < len(e.providers); i++ {
+		provider, ok := e.providers[i].(HistoricalExchangeRatesDataProvider)
+
+		if !ok {
+			continue
+		}
+
+		exchangeRateResps, err := provider.GetHistoricalExchangeRates(c, startTime, endTime)
+
+		if err != nil {
+			log.Warnf(c, "[fallback_exchange_rates_data_provider.GetHistoricalExchangeRates] failed to get historical exchange rates from data provider#%d, because %s", i, err.Error())
+			lastErr = err
+			continue
+		}
+
+		finalExchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(exchangeRateResps))
+
+		for j := 0; j < len(exchangeRateResps); j++ {
+			if exchangeRateResps[j] == nil || exchangeRateResps[j].BaseCurrency == "" {
+				continue
+			}
+
+			finalExchangeRateResps = append(finalExchangeRateResps, e.getFallbackExchangeRateResponse(exchangeRateResps[j]))
+		}
+
+		return finalExchangeRateResps, nil
+	}
+
+	return nil, lastErr
+}
+
+// getFallbackExchangeRateResponse returns a copy of the exchange rate response under the fixed data source name and relative to its own base currency,
+// and the actual data source is kept in each exchange rate
+func (e *FallbackExchangeRatesDataProvider) getFallbackExchangeRateResponse(exchangeRateResponse *models.LatestExchangeRateResponse) *models.LatestExchangeRateResponse {
+	exchangeRates := make(models.LatestExchangeRateSlice, len(exchangeRateResponse.ExchangeRates))
+
+	for i := 0; i < len(exchangeRateResponse.ExchangeRates); i++ {
+		exchangeRate := exchangeRateResponse.ExchangeRates[i]
+		dataSource := exchangeRate.DataSource
+
+		if dataSource == "" {
+			dataSource = exchangeRateResponse.DataSource
+		}
+
+		exchangeRates[i] = &models.LatestExchangeRate{
+			Currency:   exchangeRate.Currency,
+			Rate:       exchangeRate.Rate,
+			DataSource: dataSource,
+		}
+	}
+
+	return &models.LatestExchangeRateResponse{
+		DataSource:    fallbackExchangeRatesDataSource,
+		ReferenceUrl:  exchangeRateResponse.ReferenceUrl,
+		UpdateTime:    exchangeRateResponse.UpdateTime,
+		BaseCurrency:  exchangeRateResponse.BaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+}
+
+func newFallbackExchangeRatesDataProvider(providers []ExchangeRatesDataProvider) *FallbackExchangeRatesDataProvider {
+	return &FallbackExchangeRatesDataProvider{
+		providers: providers,
+	}
+}
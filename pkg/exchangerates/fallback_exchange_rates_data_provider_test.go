@@ -0,0 +1,168 @@
+package exchangerates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type staticExchangeRatesDataProvider struct {
+	ExchangeRatesDataProvider
+	response *models.LatestExchangeRateResponse
+	err      error
+}
+
+func (p *staticExchangeRatesDataProvider) GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	return p.response, p.err
+}
+
+var euroCentralBankStaticExchangeRateResponse = &models.LatestExchangeRateResponse{
+	DataSource:   "European Central Bank",
+	ReferenceUrl: "https://www.ecb.europa.eu/",
+	UpdateTime:   1617285600,
+	BaseCurrency: "EUR",
+	ExchangeRates: models.LatestExchangeRateSlice{
+		{Currency: "CNY", Rate: "7.7195"},
+		{Currency: "EUR", Rate: "1"},
+		{Currency: "USD", Rate: "1.25"},
+	},
+}
+
+var internationalMonetaryFundStaticExchangeRateResponse = &models.LatestExchangeRateResponse{
+	DataSource:   "International Monetary Fund",
+	ReferenceUrl: "https://www.imf.org/",
+	UpdateTime:   1617199200,
+	BaseCurrency: "USD",
+	ExchangeRates: models.LatestExchangeRateSlice{
+		{Currency: "CNY", Rate: "6.5"},
+		{Currency: "EUR", Rate: "0.8"},
+		{Currency: "ISK", Rate: "125"},
+		{Currency: "USD", Rate: "1"},
+	},
+}
+
+type staticHistoricalExchangeRatesDataProvider struct {
+	staticExchangeRatesDataProvider
+	historicalResponses []*models.LatestExchangeRateResponse
+}
+
+func (p *staticHistoricalExchangeRatesDataProvider) GetHistoricalExchangeRates(c core.Context, startTime time.Time, endTime time.Time) ([]*models.LatestExchangeRateResponse, error) {
+	return p.historicalResponses, p.err
+}
+
+func TestFallbackExchangeRatesDataProvider_FirstProviderAvailable(t *testing.T) {
+	provider := newFallbackExchangeRatesDataProvider([]ExchangeRatesDataProvider{
+		&staticExchangeRatesDataProvider{response: euroCentralBankStaticExchangeRateResponse},
+	})
+
+	actualLatestExchangeRateResponse, err := provider.GetLatestExchangeRates(core.NewNullContext(), 0, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, fallbackExchangeRatesDataSource, actualLatestExchangeRateResponse.DataSource)
+	assert.Equal(t, "EUR", actualLatestExchangeRateResponse.BaseCurrency)
+	assert.Equal(t, int64(1617285600), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 3)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency:   "USD",
+		Rate:       "1.25",
+		DataSource: "European Central Bank",
+	})
+}
+
+func TestFallbackExchangeRatesDataProvider_FirstProviderUnavailable(t *testing.T) {
+	provider := newFallbackExchangeRatesDataProvider([]ExchangeRatesDataProvider{
+		&staticExchangeRatesDataProvider{err: errs.ErrFailedToRequestRemoteApi},
+		&staticExchangeRatesDataProvider{response: internationalMonetaryFundStaticExchangeRateResponse},
+	})
+
+	actualLatestExchangeRateResponse, err := provider.GetLatestExchangeRates(core.NewNullContext(), 0, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, fallbackExchangeRatesDataSource, actualLatestExchangeRateResponse.DataSource)
+	assert.Equal(t, "USD", actualLatestExchangeRateResponse.BaseCurrency)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 4)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency:   "ISK",
+		Rate:       "125",
+		DataSource: "International Monetary Fund",
+	})
+}
+
+func TestFallbackExchangeRatesDataProvider_StopAtFirstAvailableProvider(t *testing.T) {
+	provider := newFallbackExchangeRatesDataProvider([]ExchangeRatesDataProvider{
+		&staticExchangeRatesDataProvider{response: euroCentralBankStaticExchangeRateResponse},
+		&staticExchangeRatesDataProvider{response: internationalMonetaryFundStaticExchangeRateResponse},
+	})
+
+	actualLatestExchangeRateResponse, err := provider.GetLatestExchangeRates(core.NewNullContext(), 0, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "EUR", actualLatestExchangeRateResponse.BaseCurrency)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 3)
+	assert.NotContains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency:   "ISK",
+		Rate:       "125",
+		DataSource: "International Monetary Fund",
+	})
+}
+
+func TestFallbackExchangeRatesDataProvider_SkipProviderWithoutExchangeRates(t *testing.T) {
+	provider := newFallbackExchangeRatesDataProvider([]ExchangeRatesDataProvider{
+		&staticExchangeRatesDataProvider{response: &models.LatestExchangeRateResponse{
+			DataSource:    "Central Bank of Uzbekistan",
+			BaseCurrency:  "UZS",
+			ExchangeRates: models.LatestExchangeRateSlice{},
+		}},
+		&staticExchangeRatesDataProvider{response: internationalMonetaryFundStaticExchangeRateResponse},
+	})
+
+	actualLatestExchangeRateResponse, err := provider.GetLatestExchangeRates(core.NewNullContext(), 0, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "USD", actualLatestExchangeRateResponse.BaseCurrency)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 4)
+}
+
+func TestFallbackExchangeRatesDataProvider_AllProvidersUnavailable(t *testing.T) {
+	provider := newFallbackExchangeRatesDataProvider([]ExchangeRatesDataProvider{
+		&staticExchangeRatesDataProvider{err: errs.ErrFailedToRequestRemoteApi},
+		&staticExchangeRatesDataProvider{err: errs.ErrFailedToRequestRemoteApi},
+	})
+
+	_, err := provider.GetLatestExchangeRates(core.NewNullContext(), 0, nil)
+	assert.Equal(t, errs.ErrFailedToRequestRemoteApi, err)
+}
+
+func TestFallbackExchangeRatesDataProvider_HistoricalExchangeRatesNotSupported(t *testing.T) {
+	provider := newFallbackExchangeRatesDataProvider([]ExchangeRatesDataProvider{
+		&staticExchangeRatesDataProvider{response: euroCentralBankStaticExchangeRateResponse},
+	})
+
+	_, err := provider.GetHistoricalExchangeRates(core.NewNullContext(), time.Unix(1617199200, 0), time.Unix(1617285600, 0))
+	assert.Equal(t, errs.ErrHistoricalExchangeRatesNotSupported, err)
+}
+
+func TestFallbackExchangeRatesDataProvider_HistoricalExchangeRatesInOwnBaseCurrency(t *testing.T) {
+	provider := newFallbackExchangeRatesDataProvider([]ExchangeRatesDataProvider{
+		&staticExchangeRatesDataProvider{response: internationalMonetaryFundStaticExchangeRateResponse},
+		&staticHistoricalExchangeRatesDataProvider{
+			historicalResponses: []*models.LatestExchangeRateResponse{
+				euroCentralBankStaticExchangeRateResponse,
+			},
+		},
+	})
+
+	actualExchangeRateResponses, err := provider.GetHistoricalExchangeRates(core.NewNullContext(), time.Unix(1617199200, 0), time.Unix(1617285600, 0))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualExchangeRateResponses, 1)
+	assert.Equal(t, fallbackExchangeRatesDataSource, actualExchangeRateResponses[0].DataSource)
+	assert.Equal(t, "EUR", actualExchangeRateResponses[0].BaseCurrency)
+	assert.Len(t, actualExchangeRateResponses[0].ExchangeRates, 3)
+	assert.Contains(t, actualExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency:   "CNY",
+		Rate:       "7.7195",
+		DataSource: "European Central Bank",
+	})
+}
@@ -0,0 +1,237 @@
+package exchangerates
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+const federalReserveExchangeRateUrl = "https://www.federalreserve.gov/datadownload/Output.aspx?rel=H10&series=60f32914ab61dfab590e0e470153e3ae&lastobs=10&from=&to=&filetype=csv&label=include&layout=seriescolumn"
+const federalReserveExchangeRateReferenceUrl = "https://www.federalreserve.gov/releases/h10/current/"
+const federalReserveDataSource = "Federal Reserve Board"
+const federalReserveBaseCurrency = "USD"
+
+const federalReserveDataUpdateDateFormat = "2006-01-02 15:04"
+const federalReserveDataUpdateDateTimezone = "America/New_York"
+
+const federalReserveUnitRowTitle = "Unit:"
+const federalReserveMultiplierRowTitle = "Multiplier:"
+const federalReserveCurrencyRowTitle = "Currency:"
+const federalReserveTimePeriodRowTitle = "Time Period"
+const federalReserveCurrencyUnitPrefix = "Currency:_Per_"
+
+// FederalReserveDataSource defines the structure of exchange rates data source of the Federal Reserve H.10 release
+type FederalReserveDataSource struct {
+	HttpExchangeRatesDataSource
+}
+
+// federalReserveSeriesColumn represents the metadata of a series column in the Federal Reserve data
+type federalReserveSeriesColumn struct {
+	columnIndex    int
+	targetCurrency string
+	multiplier     float64
+	isInverse      bool
+}
+
+// BuildRequests returns the Federal Reserve exchange rates http requests
+func (e *FederalReserveDataSource) BuildRequests() ([]*http.Request, error) {
+	req, err := http.NewRequest("GET", federalReserveExchangeRateUrl, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// Parse returns the common response entity according to the Federal Reserve data source raw response
+func (e *FederalReserveDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	csvReader := csv.NewReader(bytes.NewReader(content))
+	csvReader.FieldsPerRecord = -1
+	allLines, err := csvReader.ReadAll()
+
+	if err != nil {
+		log.Errorf(c, "[federal_reserve_datasource.Parse] failed to parse csv data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	metadataRows := make(map[string][]string)
+	dataStartRowIndex := -1
+
+	for i := 0; i < len(allLines); i++ {
+		if len(allLines[i]) < 1 {
+			continue
+		}
+
+		title := strings.TrimSpace(allLines[i][0])
+
+		if title == federalReserveTimePeriodRowTitle {
+			dataStartRowIndex = i + 1
+			break
+		}
+
+		metadataRows[title] = allLines[i]
+	}
+
+	if dataStartRowIndex < 0 || metadataRows[federalReserveUnitRowTitle] == nil || metadataRows[federalReserveCurrencyRowTitle] == nil {
+		log.Errorf(c, "[federal_reserve_datasource.Parse] missing metadata rows, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	seriesColumns := e.parseSeriesColumns(c, metadataRows)
+
+	if len(seriesColumns) < 1 {
+		log.Errorf(c, "[federal_reserve_datasource.Parse] there is no exchange rate series in content, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	for i := len(allLines) - 1; i >= dataStartRowIndex; i-- {
+		line := allLines[i]
+
+		if len(line) < 2 {
+			continue
+		}
+
+		exchangeRates := make(models.LatestExchangeRateSlice, 0, len(seriesColumns))
+
+		for j := 0; j < len(seriesColumns); j++ {
+			exchangeRate := seriesColumns[j].parseExchangeRate(c, line)
+
+			if exchangeRate != nil {
+				exchangeRates = append(exchangeRates, exchangeRate)
+			}
+		}
+
+		if len(exchangeRates) < 1 {
+			continue
+		}
+
+		timezone, err := time.LoadLocation(federalReserveDataUpdateDateTimezone)
+
+		if err != nil {
+			log.Errorf(c, "[federal_reserve_datasource.Parse] failed to get timezone, timezone name is %s", federalReserveDataUpdateDateTimezone)
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		updateDateTime := strings.TrimSpace(line[0]) + " 12:00" // The rates are noon buying rates in New York
+		updateTime, err := time.ParseInLocation(federalReserveDataUpdateDateFormat, updateDateTime, timezone)
+
+		if err != nil {
+			log.Errorf(c, "[federal_reserve_datasource.Parse] failed to parse update date, datetime is %s", updateDateTime)
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		latestExchangeRateResp := &models.LatestExchangeRateResponse{
+			DataSource:    federalReserveDataSource,
+			ReferenceUrl:  federalReserveExchangeRateReferenceUrl,
+			UpdateTime:    updateTime.Unix(),
+			BaseCurrency:  federalReserveBaseCurrency,
+			ExchangeRates: exchangeRates,
+		}
+
+		return latestExchangeRateResp, nil
+	}
+
+	log.Errorf(c, "[federal_reserve_datasource.Parse] there is no exchange rate data in content, content is %s", string(content))
+	return nil, errs.ErrFailedToRequestRemoteApi
+}
+
+func (e *FederalReserveDataSource) parseSeriesColumns(c core.Context, metadataRows map[string][]string) []*federalReserveSeriesColumn {
+	unitRow := metadataRows[federalReserveUnitRowTitle]
+	currencyRow := metadataRows[federalReserveCurrencyRowTitle]
+	multiplierRow := metadataRows[federalReserveMultiplierRowTitle]
+	seriesColumns := make([]*federalReserveSeriesColumn, 0, len(unitRow))
+
+	for i := 1; i < len(unitRow) && i < len(currencyRow); i++ {
+		unit := strings.TrimSpace(unitRow[i])
+
+		if !strings.HasPrefix(unit, federalReserveCurrencyUnitPrefix) {
+			continue
+		}
+
+		perCurrency := strings.TrimPrefix(unit, federalReserveCurrencyUnitPrefix)
+		currency := strings.TrimSpace(currencyRow[i])
+		seriesColumn := &federalReserveSeriesColumn{
+			columnIndex: i,
+			multiplier:  1,
+		}
+
+		if perCurrency == federalReserveBaseCurrency {
+			seriesColumn.targetCurrency = currency
+			seriesColumn.isInverse = false
+		} else if currency == federalReserveBaseCurrency {
+			seriesColumn.targetCurrency = perCurrency
+			seriesColumn.isInverse = true
+		} else {
+			continue
+		}
+
+		if _, exists := validators.AllCurrencyNames[seriesColumn.targetCurrency]; !exists {
+			continue
+		}
+
+		if i < len(multiplierRow) {
+			multiplier, err := utils.StringToFloat64(strings.TrimSpace(multiplierRow[i]))
+
+			if err != nil || multiplier <= 0 {
+				log.Warnf(c, "[federal_reserve_datasource.parseSeriesColumns] multiplier is invalid, currency is %s, multiplier is %s", seriesColumn.targetCurrency, multiplierRow[i])
+				continue
+			}
+
+			seriesColumn.multiplier = multiplier
+		}
+
+		seriesColumns = append(seriesColumns, seriesColumn)
+	}
+
+	return seriesColumns
+}
+
+func (s *federalReserveSeriesColumn) parseExchangeRate(c core.Context, line []string) *models.LatestExchangeRate {
+	if s.columnIndex >= len(line) {
+		return nil
+	}
+
+	value := strings.TrimSpace(line[s.columnIndex])
+
+	if value == "" || value == "ND" {
+		return nil
+	}
+
+	rate, err := utils.StringToFloat64(value)
+
+	if err != nil {
+		log.Warnf(c, "[federal_reserve_datasource.parseExchangeRate] failed to parse rate, currency is %s, rate is %s", s.targetCurrency, value)
+		return nil
+	}
+
+	if rate <= 0 {
+		log.Warnf(c, "[federal_reserve_datasource.parseExchangeRate] rate is invalid, currency is %s, rate is %s", s.targetCurrency, value)
+		return nil
+	}
+
+	finalRate := rate * s.multiplier
+
+	if s.isInverse {
+		finalRate = 1 / finalRate
+	}
+
+	if math.IsInf(finalRate, 0) {
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: s.targetCurrency,
+		Rate:     utils.Float64ToString(finalRate),
+	}
+}
@@ -0,0 +1,116 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const federalReserveMinimumRequiredContent = "\"Series Description\",\"SPOT EXCHANGE RATE - EURO AREA \",\"SPOT EXCHANGE RATE - CANADA \",\"NOMINAL BROAD DOLLAR INDEX \"\n" +
+	"\"Unit:\",\"Currency:_Per_EUR\",\"Currency:_Per_USD\",\"Index:_January_2006_=_100\"\n" +
+	"\"Multiplier:\",\"1\",\"1\",\"1\"\n" +
+	"\"Currency:\",\"USD\",\"CAD\",\"NA\"\n" +
+	"\"Unique Identifier: \",\"H10/H10/RXI$US_N.B.EU\",\"H10/H10/RXI_N.B.CA\",\"H10/H10/JRXWTFB_N.B\"\n" +
+	"\"Time Period\",\"RXI$US_N.B.EU\",\"RXI_N.B.CA\",\"JRXWTFB_N.B\"\n" +
+	"2024-01-01,ND,ND,ND\n" +
+	"2024-01-02,1.0956,1.3316,121.0025\n" +
+	"2024-01-03,ND,ND,ND\n"
+
+func TestFederalReserveDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &FederalReserveDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(federalReserveMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "USD", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestFederalReserveDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &FederalReserveDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(federalReserveMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1704214800), actualLatestExchangeRateResponse.UpdateTime)
+}
+
+func TestFederalReserveDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &FederalReserveDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(federalReserveMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 2)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "0.9127418765972983",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "CAD",
+		Rate:     "1.3316",
+	})
+}
+
+func TestFederalReserveDataSource_InvalidContent(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"blank content", ""},
+		{"only title row", "\"Series Description\",\"SPOT EXCHANGE RATE - CANADA \"\n"},
+		{"missing time period row", "\"Unit:\",\"Currency:_Per_USD\"\n" +
+			"\"Currency:\",\"CAD\"\n" +
+			"2024-01-02,1.3316\n"},
+		{"no currency series", "\"Unit:\",\"Index:_January_2006_=_100\"\n" +
+			"\"Currency:\",\"NA\"\n" +
+			"\"Time Period\",\"JRXWTFB_N.B\"\n" +
+			"2024-01-02,121.0025\n"},
+		{"no available rate", "\"Unit:\",\"Currency:_Per_USD\"\n" +
+			"\"Currency:\",\"CAD\"\n" +
+			"\"Time Period\",\"RXI_N.B.CA\"\n" +
+			"2024-01-02,ND\n"},
+		{"invalid date", "\"Unit:\",\"Currency:_Per_USD\"\n" +
+			"\"Currency:\",\"CAD\"\n" +
+			"\"Time Period\",\"RXI_N.B.CA\"\n" +
+			"2024/01/02,1.3316\n"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &FederalReserveDataSource{}
+		context := core.NewNullContext()
+
+		_, err := dataSource.Parse(context, []byte(tc.content))
+		assert.NotEqual(t, nil, err, tc.name)
+	}
+}
+
+func TestFederalReserveDataSource_InvalidRates(t *testing.T) {
+	testCases := []struct {
+		name     string
+		currency string
+		perUnit  string
+		rate     string
+	}{
+		{"invalid currency", "XXX", "Currency:_Per_USD", "1.3316"},
+		{"invalid rate", "CAD", "Currency:_Per_USD", "null"},
+		{"zero rate", "CAD", "Currency:_Per_USD", "0"},
+		{"cross currency rate", "CAD", "Currency:_Per_EUR", "1.4589"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &FederalReserveDataSource{}
+		context := core.NewNullContext()
+
+		actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("\"Unit:\",\""+tc.perUnit+"\",\"Currency:_Per_EUR\"\n"+
+			"\"Multiplier:\",\"1\",\"1\"\n"+
+			"\"Currency:\",\""+tc.currency+"\",\"USD\"\n"+
+			"\"Time Period\",\"SERIES1\",\"RXI$US_N.B.EU\"\n"+
+			"2024-01-02,"+tc.rate+",1.0956\n"))
+		assert.Equal(t, nil, err, tc.name)
+		assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1, tc.name)
+		assert.Equal(t, "EUR", actualLatestExchangeRateResponse.ExchangeRates[0].Currency, tc.name)
+	}
+}
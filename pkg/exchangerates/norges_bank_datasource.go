@@ -3,8 +3,10 @@ package exchangerates
 import (
 	"bytes"
 	"encoding/xml"
+	"fmt"
 	"math"
 	"net/http"
+	"sort"
 	"time"
 
 	"golang.org/x/net/html/charset"
@@ -18,12 +20,14 @@ import (
 )
 
 const norgesBankExchangeRateUrl = "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=sdmx-compact-2.1&lastNObservations=1"
+const norgesBankHistoricalExchangeRateUrlFormat = "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=sdmx-compact-2.1&startPeriod=%s&endPeriod=%s"
 const norgesBankExchangeRateReferenceUrl = "https://www.norges-bank.no/en/topics/Statistics/exchange_rates/"
 const norgesBankDataSource = "Norges Bank"
 const norgesBankBaseCurrency = "NOK"
 
 const norgesBankUpdateDateFormat = "2006-01-02 15"
 const norgesBankUpdateDateTimezone = "Europe/Oslo"
+const norgesBankRequestDateFormat = "2006-01-02"
 
 // NorgesBankDataSource defines the structure of exchange rates data source of Norges Bank
 type NorgesBankDataSource struct {
@@ -119,6 +123,71 @@ func (e *NorgesBankExchangeRateData) ToLatestExchangeRateResponse(c core.Context
 	return latestExchangeRateResp
 }
 
+// ToHistoricalExchangeRateResponses returns the view-objects of every day according to original data from Norges Bank
+func (e *NorgesBankExchangeRateData) ToHistoricalExchangeRateResponses(c core.Context) []*models.LatestExchangeRateResponse {
+	if e.DataSet == nil || len(e.DataSet.ExchangeRates) < 1 {
+		return []*models.LatestExchangeRateResponse{}
+	}
+
+	timezone, err := time.LoadLocation(norgesBankUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[norges_bank_datasource.ToHistoricalExchangeRateResponses] failed to get timezone, timezone name is %s", norgesBankUpdateDateTimezone)
+		return nil
+	}
+
+	dailyExchangeRates := make(map[string]models.LatestExchangeRateSlice)
+
+	for i := 0; i < len(e.DataSet.ExchangeRates); i++ {
+		exchangeRate := e.DataSet.ExchangeRates[i]
+
+		if _, exists := validators.AllCurrencyNames[exchangeRate.BaseCurrency]; !exists {
+			continue
+		}
+
+		if exchangeRate.TargetCurrency != norgesBankBaseCurrency {
+			continue
+		}
+
+		for j := 0; j < len(exchangeRate.Observations); j++ {
+			observation := exchangeRate.Observations[j]
+			finalExchangeRate := exchangeRate.ToLatestExchangeRate(c, observation.Rate)
+
+			if finalExchangeRate == nil {
+				continue
+			}
+
+			dailyExchangeRates[observation.Date] = append(dailyExchangeRates[observation.Date], finalExchangeRate)
+		}
+	}
+
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(dailyExchangeRates))
+
+	for date, exchangeRates := range dailyExchangeRates {
+		updateDateTime := date + " 16" // Publication time of daily exchange rates is approximately 16:00 CET.
+		updateTime, err := time.ParseInLocation(norgesBankUpdateDateFormat, updateDateTime, timezone)
+
+		if err != nil {
+			log.Warnf(c, "[norges_bank_datasource.ToHistoricalExchangeRateResponses] failed to parse update date, datetime is %s", date)
+			continue
+		}
+
+		exchangeRateResps = append(exchangeRateResps, &models.LatestExchangeRateResponse{
+			DataSource:    norgesBankDataSource,
+			ReferenceUrl:  norgesBankExchangeRateReferenceUrl,
+			UpdateTime:    updateTime.Unix(),
+			BaseCurrency:  norgesBankBaseCurrency,
+			ExchangeRates: exchangeRates,
+		})
+	}
+
+	sort.Slice(exchangeRateResps, func(i, j int) bool {
+		return exchangeRateResps[i].UpdateTime < exchangeRateResps[j].UpdateTime
+	})
+
+	return exchangeRateResps
+}
+
 // ToLatestExchangeRate returns a data pair according to original data from Norges Bank
 func (e *NorgesBankExchangeRate) ToLatestExchangeRate(c core.Context, exchangeRate string) *models.LatestExchangeRate {
 	rate, err := utils.StringToFloat64(exchangeRate)
@@ -170,6 +239,40 @@ func (e *NorgesBankDataSource) BuildRequests() ([]*http.Request, error) {
 	return []*http.Request{req}, nil
 }
 
+// BuildHistoricalRequests returns the Norges Bank historical exchange rates http requests
+func (e *NorgesBankDataSource) BuildHistoricalRequests(startTime time.Time, endTime time.Time) ([]*http.Request, error) {
+	url := fmt.Sprintf(norgesBankHistoricalExchangeRateUrlFormat, startTime.UTC().Format(norgesBankRequestDateFormat), endTime.UTC().Format(norgesBankRequestDateFormat))
+	req, err := http.NewRequest("GET", url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// ParseHistorical returns the common response entities of every day according to the Norges Bank data source raw response
+func (e *NorgesBankDataSource) ParseHistorical(c core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
+	xmlDecoder.CharsetReader = charset.NewReaderLabel
+
+	norgesBankData := &NorgesBankExchangeRateData{}
+	err := xmlDecoder.Decode(norgesBankData)
+
+	if err != nil {
+		log.Errorf(c, "[norges_bank_datasource.ParseHistorical] failed to parse xml data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	exchangeRateResps := norgesBankData.ToHistoricalExchangeRateResponses(c)
+
+	if exchangeRateResps == nil {
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return exchangeRateResps, nil
+}
+
 // Parse returns the common response entity according to the Norges Bank data source raw response
 func (e *NorgesBankDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
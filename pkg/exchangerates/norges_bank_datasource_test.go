@@ -220,3 +220,47 @@ func TestNorgesBankDataSource_InvalidUnit(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestNorgesBankDataSource_HistoricalDataExtractEveryDay(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := core.NewNullContext()
+
+	actualExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<message:StructureSpecificData xmlns:message=\"http://www.sdmx.org/resources/sdmxml/schemas/v2_1/message\">\n"+
+		"  <message:DataSet>\n"+
+		"    <Series BASE_CUR=\"JPY\" QUOTE_CUR=\"NOK\" UNIT_MULT=\"2\">\n"+
+		"      <Obs TIME_PERIOD=\"2024-11-14\" OBS_VALUE=\"7.1025\" />\n"+
+		"      <Obs TIME_PERIOD=\"2024-11-15\" OBS_VALUE=\"7.1179\" />\n"+
+		"    </Series>\n"+
+		"    <Series BASE_CUR=\"USD\" QUOTE_CUR=\"NOK\" UNIT_MULT=\"0\">\n"+
+		"      <Obs TIME_PERIOD=\"2024-11-15\" OBS_VALUE=\"11.0545\" />\n"+
+		"    </Series>\n"+
+		"  </message:DataSet>\n"+
+		"</message:StructureSpecificData>"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualExchangeRateResponses))
+
+	assert.Equal(t, "NOK", actualExchangeRateResponses[0].BaseCurrency)
+	assert.Equal(t, int64(1731596400), actualExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, 1, len(actualExchangeRateResponses[0].ExchangeRates))
+	assert.Equal(t, "JPY", actualExchangeRateResponses[0].ExchangeRates[0].Currency)
+
+	assert.Equal(t, "NOK", actualExchangeRateResponses[1].BaseCurrency)
+	assert.Equal(t, int64(1731682800), actualExchangeRateResponses[1].UpdateTime)
+	assert.Contains(t, actualExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "14.049087511766112",
+	})
+	assert.Contains(t, actualExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.09046089827671988",
+	})
+}
+
+func TestNorgesBankDataSource_HistoricalDataBlankContent(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.ParseHistorical(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
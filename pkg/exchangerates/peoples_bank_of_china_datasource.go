@@ -0,0 +1,190 @@
+package exchangerates
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+// The central parity rates of the People's Bank of China are published by China Foreign Exchange Trade System (CFETS)
+const peoplesBankOfChinaExchangeRateUrl = "https://www.chinamoney.com.cn/r/cms/www/chinamoney/data/fx/ccpr.json"
+const peoplesBankOfChinaExchangeRateReferenceUrl = "https://www.chinamoney.com.cn/english/bmkcpr/"
+const peoplesBankOfChinaDataSource = "People's Bank of China"
+const peoplesBankOfChinaBaseCurrency = "CNY"
+
+const peoplesBankOfChinaDataUpdateDateFormat = "2006-01-02 15:04"
+const peoplesBankOfChinaDataUpdateDateTimezone = "Asia/Shanghai"
+
+// PeoplesBankOfChinaDataSource defines the structure of exchange rates data source of the People's Bank of China
+type PeoplesBankOfChinaDataSource struct {
+	HttpExchangeRatesDataSource
+}
+
+// PeoplesBankOfChinaExchangeRateData represents the whole data from the People's Bank of China
+type PeoplesBankOfChinaExchangeRateData struct {
+	Data    *PeoplesBankOfChinaExchangeRateMetadata `json:"data"`
+	Records []*PeoplesBankOfChinaExchangeRate       `json:"records"`
+}
+
+// PeoplesBankOfChinaExchangeRateMetadata represents the metadata of exchange rates from the People's Bank of China
+type PeoplesBankOfChinaExchangeRateMetadata struct {
+	LastDate string `json:"lastDate"`
+}
+
+// PeoplesBankOfChinaExchangeRate represents the exchange rate data from the People's Bank of China
+type PeoplesBankOfChinaExchangeRate struct {
+	CurrencyPair string `json:"vrtEName"`
+	Price        string `json:"price"`
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to original data from the People's Bank of China
+func (e *PeoplesBankOfChinaExchangeRateData) ToLatestExchangeRateResponse(c core.Context) *models.LatestExchangeRateResponse {
+	if e.Data == nil || e.Data.LastDate == "" {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRateResponse] last date does not exist")
+		return nil
+	}
+
+	if len(e.Records) < 1 {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRateResponse] records is empty")
+		return nil
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(e.Records))
+
+	for i := 0; i < len(e.Records); i++ {
+		if e.Records[i] == nil {
+			continue
+		}
+
+		exchangeRate := e.Records[i].ToLatestExchangeRate(c)
+
+		if exchangeRate != nil {
+			exchangeRates = append(exchangeRates, exchangeRate)
+		}
+	}
+
+	timezone, err := time.LoadLocation(peoplesBankOfChinaDataUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRateResponse] failed to get timezone, timezone name is %s", peoplesBankOfChinaDataUpdateDateTimezone)
+		return nil
+	}
+
+	updateTime, err := time.ParseInLocation(peoplesBankOfChinaDataUpdateDateFormat, strings.TrimSpace(e.Data.LastDate), timezone)
+
+	if err != nil {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRateResponse] failed to parse update date, datetime is %s", e.Data.LastDate)
+		return nil
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    peoplesBankOfChinaDataSource,
+		ReferenceUrl:  peoplesBankOfChinaExchangeRateReferenceUrl,
+		UpdateTime:    updateTime.Unix(),
+		BaseCurrency:  peoplesBankOfChinaBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp
+}
+
+// ToLatestExchangeRate returns a data pair according to original data from the People's Bank of China
+func (e *PeoplesBankOfChinaExchangeRate) ToLatestExchangeRate(c core.Context) *models.LatestExchangeRate {
+	// The currency pair is like "USD/CNY", "100JPY/CNY" or "CNY/MYR"
+	currencies := strings.Split(e.CurrencyPair, "/")
+
+	if len(currencies) != 2 {
+		log.Warnf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRate] currency pair is invalid, currency pair is %s", e.CurrencyPair)
+		return nil
+	}
+
+	price, err := utils.StringToFloat64(e.Price)
+
+	if err != nil {
+		log.Warnf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRate] failed to parse rate, currency pair is %s, rate is %s", e.CurrencyPair, e.Price)
+		return nil
+	}
+
+	if price <= 0 {
+		log.Warnf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRate] rate is invalid, currency pair is %s, rate is %s", e.CurrencyPair, e.Price)
+		return nil
+	}
+
+	var currency string
+	var finalRate float64
+
+	if currencies[1] == peoplesBankOfChinaBaseCurrency {
+		unit := float64(1)
+		unitText := strings.TrimRight(currencies[0], "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+		currency = currencies[0][len(unitText):]
+
+		if unitText != "" {
+			unit, err = utils.StringToFloat64(unitText)
+
+			if err != nil || unit <= 0 {
+				log.Warnf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRate] failed to parse unit, currency pair is %s", e.CurrencyPair)
+				return nil
+			}
+		}
+
+		finalRate = unit / price
+	} else if currencies[0] == peoplesBankOfChinaBaseCurrency {
+		currency = currencies[1]
+		finalRate = price
+	} else {
+		return nil
+	}
+
+	if _, exists := validators.AllCurrencyNames[currency]; !exists {
+		return nil
+	}
+
+	if math.IsInf(finalRate, 0) {
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: currency,
+		Rate:     utils.Float64ToString(finalRate),
+	}
+}
+
+// BuildRequests returns the People's Bank of China exchange rates http requests
+func (e *PeoplesBankOfChinaDataSource) BuildRequests() ([]*http.Request, error) {
+	req, err := http.NewRequest("GET", peoplesBankOfChinaExchangeRateUrl, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// Parse returns the common response entity according to the People's Bank of China data source raw response
+func (e *PeoplesBankOfChinaDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	peoplesBankOfChinaData := &PeoplesBankOfChinaExchangeRateData{}
+	err := json.Unmarshal(content, &peoplesBankOfChinaData)
+
+	if err != nil {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.Parse] failed to parse json data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResponse := peoplesBankOfChinaData.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
@@ -0,0 +1,106 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const peoplesBankOfChinaMinimumRequiredContent = "{\"head\":{\"rep_code\":\"200\"}," +
+	"\"data\":{\"lastDate\":\"2024-01-02 9:15\"}," +
+	"\"records\":[" +
+	"{\"vrtCode\":\"USD/CNY\",\"vrtEName\":\"USD/CNY\",\"price\":\"7.0920\"}," +
+	"{\"vrtCode\":\"100JPY/CNY\",\"vrtEName\":\"100JPY/CNY\",\"price\":\"5.0213\"}," +
+	"{\"vrtCode\":\"CNY/MYR\",\"vrtEName\":\"CNY/MYR\",\"price\":\"0.65025\"}" +
+	"]}"
+
+func TestPeoplesBankOfChinaDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(peoplesBankOfChinaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "CNY", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestPeoplesBankOfChinaDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(peoplesBankOfChinaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1704158100), actualLatestExchangeRateResponse.UpdateTime)
+}
+
+func TestPeoplesBankOfChinaDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(peoplesBankOfChinaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 3)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.1410039481105471",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "19.915161412383245",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "MYR",
+		Rate:     "0.65025",
+	})
+}
+
+func TestPeoplesBankOfChinaDataSource_InvalidContent(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"blank content", ""},
+		{"empty json object", "{}"},
+		{"missing last date", "{\"records\":[{\"vrtEName\":\"USD/CNY\",\"price\":\"7.0920\"}]}"},
+		{"empty records", "{\"data\":{\"lastDate\":\"2024-01-02 9:15\"},\"records\":[]}"},
+		{"invalid last date", "{\"data\":{\"lastDate\":\"2024/01/02\"},\"records\":[{\"vrtEName\":\"USD/CNY\",\"price\":\"7.0920\"}]}"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &PeoplesBankOfChinaDataSource{}
+		context := core.NewNullContext()
+
+		_, err := dataSource.Parse(context, []byte(tc.content))
+		assert.NotEqual(t, nil, err, tc.name)
+	}
+}
+
+func TestPeoplesBankOfChinaDataSource_InvalidRates(t *testing.T) {
+	testCases := []struct {
+		name         string
+		currencyPair string
+		price        string
+	}{
+		{"invalid currency", "XXX/CNY", "7.0920"},
+		{"cross currency pair", "USD/EUR", "0.9127"},
+		{"invalid currency pair", "USDCNY", "7.0920"},
+		{"empty price", "USD/CNY", ""},
+		{"invalid price", "USD/CNY", "null"},
+		{"zero price", "USD/CNY", "0"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &PeoplesBankOfChinaDataSource{}
+		context := core.NewNullContext()
+
+		actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("{\"data\":{\"lastDate\":\"2024-01-02 9:15\"},\"records\":["+
+			"{\"vrtEName\":\""+tc.currencyPair+"\",\"price\":\""+tc.price+"\"},"+
+			"{\"vrtEName\":\"EUR/CNY\",\"price\":\"7.8288\"}"+
+			"]}"))
+		assert.Equal(t, nil, err, tc.name)
+		assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1, tc.name)
+		assert.Equal(t, "EUR", actualLatestExchangeRateResponse.ExchangeRates[0].Currency, tc.name)
+	}
+}
@@ -0,0 +1,180 @@
+package exchangerates
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+// The reference rates published by Reserve Bank of India are administered and computed by Financial Benchmarks India (FBIL)
+const reserveBankOfIndiaExchangeRateUrlFormat = "https://www.fbil.org.in/wasdm/refrates/fetchfiltered?fromDate=%s&toDate=%s&authenticated=false"
+const reserveBankOfIndiaExchangeRateReferenceUrl = "https://www.rbi.org.in/scripts/ReferenceRateArchive.aspx"
+const reserveBankOfIndiaDataSource = "Reserve Bank of India"
+const reserveBankOfIndiaBaseCurrency = "INR"
+
+const reserveBankOfIndiaRequestDateFormat = "2006-01-02"
+const reserveBankOfIndiaDataUpdateDateFormat = "2006-01-02 15:04"
+const reserveBankOfIndiaDataUpdateDateTimezone = "Asia/Kolkata"
+
+const reserveBankOfIndiaRequestRecentDays = 10
+
+// ReserveBankOfIndiaDataSource defines the structure of exchange rates data source of Reserve Bank of India
+type ReserveBankOfIndiaDataSource struct {
+	HttpExchangeRatesDataSource
+}
+
+// ReserveBankOfIndiaReferenceRate represents the reference rate data from Reserve Bank of India
+type ReserveBankOfIndiaReferenceRate struct {
+	ProcessRunDate string  `json:"processRunDate"`
+	SubProductName string  `json:"subProdName"`
+	Rate           float64 `json:"rate"`
+}
+
+// ReserveBankOfIndiaReferenceRates represents the whole data from Reserve Bank of India
+type ReserveBankOfIndiaReferenceRates []*ReserveBankOfIndiaReferenceRate
+
+// ToLatestExchangeRateResponse returns a view-object according to original data from Reserve Bank of India
+func (e ReserveBankOfIndiaReferenceRates) ToLatestExchangeRateResponse(c core.Context) *models.LatestExchangeRateResponse {
+	if len(e) < 1 {
+		log.Errorf(c, "[reserve_bank_of_india_datasource.ToLatestExchangeRateResponse] reference rates is empty")
+		return nil
+	}
+
+	latestDate := ""
+
+	for i := 0; i < len(e); i++ {
+		if e[i] != nil && e[i].ProcessRunDate > latestDate {
+			latestDate = e[i].ProcessRunDate
+		}
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(e))
+
+	for i := 0; i < len(e); i++ {
+		if e[i] == nil || e[i].ProcessRunDate != latestDate {
+			continue
+		}
+
+		exchangeRate := e[i].ToLatestExchangeRate(c)
+
+		if exchangeRate != nil {
+			exchangeRates = append(exchangeRates, exchangeRate)
+		}
+	}
+
+	timezone, err := time.LoadLocation(reserveBankOfIndiaDataUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[reserve_bank_of_india_datasource.ToLatestExchangeRateResponse] failed to get timezone, timezone name is %s", reserveBankOfIndiaDataUpdateDateTimezone)
+		return nil
+	}
+
+	updateDateTime := latestDate + " 13:30" // The reference rates are published at around 1:30 PM IST every working day
+	updateTime, err := time.ParseInLocation(reserveBankOfIndiaDataUpdateDateFormat, updateDateTime, timezone)
+
+	if err != nil {
+		log.Errorf(c, "[reserve_bank_of_india_datasource.ToLatestExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
+		return nil
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    reserveBankOfIndiaDataSource,
+		ReferenceUrl:  reserveBankOfIndiaExchangeRateReferenceUrl,
+		UpdateTime:    updateTime.Unix(),
+		BaseCurrency:  reserveBankOfIndiaBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp
+}
+
+// ToLatestExchangeRate returns a data pair according to original data from Reserve Bank of India
+func (e *ReserveBankOfIndiaReferenceRate) ToLatestExchangeRate(c core.Context) *models.LatestExchangeRate {
+	// The product name is like "INR / 1 USD" or "INR / 100 JPY"
+	items := strings.Split(e.SubProductName, "/")
+
+	if len(items) != 2 || strings.TrimSpace(items[0]) != reserveBankOfIndiaBaseCurrency {
+		log.Warnf(c, "[reserve_bank_of_india_datasource.ToLatestExchangeRate] product name is invalid, product name is %s", e.SubProductName)
+		return nil
+	}
+
+	unitItems := strings.Fields(items[1])
+
+	if len(unitItems) != 2 {
+		log.Warnf(c, "[reserve_bank_of_india_datasource.ToLatestExchangeRate] product name is invalid, product name is %s", e.SubProductName)
+		return nil
+	}
+
+	currency := unitItems[1]
+
+	if _, exists := validators.AllCurrencyNames[currency]; !exists {
+		return nil
+	}
+
+	unit, err := utils.StringToFloat64(unitItems[0])
+
+	if err != nil || unit <= 0 {
+		log.Warnf(c, "[reserve_bank_of_india_datasource.ToLatestExchangeRate] failed to parse unit, product name is %s", e.SubProductName)
+		return nil
+	}
+
+	if e.Rate <= 0 {
+		log.Warnf(c, "[reserve_bank_of_india_datasource.ToLatestExchangeRate] rate is invalid, currency is %s, rate is %f", currency, e.Rate)
+		return nil
+	}
+
+	finalRate := unit / e.Rate
+
+	if math.IsInf(finalRate, 0) {
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: currency,
+		Rate:     utils.Float64ToString(finalRate),
+	}
+}
+
+// BuildRequests returns the Reserve Bank of India exchange rates http requests
+func (e *ReserveBankOfIndiaDataSource) BuildRequests() ([]*http.Request, error) {
+	now := time.Now()
+	startDate := now.AddDate(0, 0, -reserveBankOfIndiaRequestRecentDays).Format(reserveBankOfIndiaRequestDateFormat)
+	endDate := now.Format(reserveBankOfIndiaRequestDateFormat)
+	req, err := http.NewRequest("GET", fmt.Sprintf(reserveBankOfIndiaExchangeRateUrlFormat, startDate, endDate), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// Parse returns the common response entity according to the Reserve Bank of India data source raw response
+func (e *ReserveBankOfIndiaDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	var referenceRates ReserveBankOfIndiaReferenceRates
+	err := json.Unmarshal(content, &referenceRates)
+
+	if err != nil {
+		log.Errorf(c, "[reserve_bank_of_india_datasource.Parse] failed to parse json data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResponse := referenceRates.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.Errorf(c, "[reserve_bank_of_india_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
@@ -0,0 +1,98 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const reserveBankOfIndiaMinimumRequiredContent = "[" +
+	"{\"processRunDate\":\"2024-01-01\",\"subProdName\":\"INR / 1 USD\",\"rate\":83.2}," +
+	"{\"processRunDate\":\"2024-01-02\",\"subProdName\":\"INR / 1 USD\",\"rate\":83.32}," +
+	"{\"processRunDate\":\"2024-01-02\",\"subProdName\":\"INR / 100 JPY\",\"rate\":58.81}" +
+	"]"
+
+func TestReserveBankOfIndiaDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &ReserveBankOfIndiaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(reserveBankOfIndiaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "INR", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestReserveBankOfIndiaDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &ReserveBankOfIndiaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(reserveBankOfIndiaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1704182400), actualLatestExchangeRateResponse.UpdateTime)
+}
+
+func TestReserveBankOfIndiaDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &ReserveBankOfIndiaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(reserveBankOfIndiaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 2)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.012001920307249161",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "1.7003910899506887",
+	})
+}
+
+func TestReserveBankOfIndiaDataSource_InvalidContent(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"blank content", ""},
+		{"json object", "{}"},
+		{"empty array", "[]"},
+		{"invalid date", "[{\"processRunDate\":\"02/01/2024\",\"subProdName\":\"INR / 1 USD\",\"rate\":83.32}]"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &ReserveBankOfIndiaDataSource{}
+		context := core.NewNullContext()
+
+		_, err := dataSource.Parse(context, []byte(tc.content))
+		assert.NotEqual(t, nil, err, tc.name)
+	}
+}
+
+func TestReserveBankOfIndiaDataSource_InvalidRates(t *testing.T) {
+	testCases := []struct {
+		name        string
+		productName string
+		rate        string
+	}{
+		{"invalid currency", "INR / 1 XXX", "83.32"},
+		{"invalid base currency", "USD / 1 INR", "0.012"},
+		{"missing unit", "INR / USD", "83.32"},
+		{"invalid unit", "INR / one USD", "83.32"},
+		{"zero rate", "INR / 1 USD", "0"},
+	}
+
+	for _, tc := range testCases {
+		dataSource := &ReserveBankOfIndiaDataSource{}
+		context := core.NewNullContext()
+
+		actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("["+
+			"{\"processRunDate\":\"2024-01-02\",\"subProdName\":\""+tc.productName+"\",\"rate\":"+tc.rate+"},"+
+			"{\"processRunDate\":\"2024-01-02\",\"subProdName\":\"INR / 1 EUR\",\"rate\":91.78}"+
+			"]"))
+		assert.Equal(t, nil, err, tc.name)
+		assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1, tc.name)
+		assert.Equal(t, "EUR", actualLatestExchangeRateResponse.ExchangeRates[0].Currency, tc.name)
+	}
+}
@@ -93,6 +93,94 @@ func (e *UserCustomExchangeRatesDataProvider) GetLatestExchangeRates(c core.Cont
 	return finalExchangeRateResponse, nil
 }
 
+// GetExchangeRatesAtTime returns the exchange rates effective at the specified unix time, the latest user custom exchange rates would be overridden by the dated exchange rates effective at that time
+func (e *UserCustomExchangeRatesDataProvider) GetExchangeRatesAtTime(c core.Context, uid int64, unixTime int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	latestExchangeRateResponse, err := e.GetLatestExchangeRates(c, uid, currentConfig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	datedExchangeRates, err := e.userCustomExchangeRates.GetEffectiveDatedExchangeRates(c, uid, unixTime)
+
+	if err != nil {
+		log.Errorf(c, "[user_custom_data_provider.GetExchangeRatesAtTime] failed to get user custom dated exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	effectiveExchangeRates := make(map[string]*models.LatestExchangeRate, len(datedExchangeRates))
+
+	for i := 0; i < len(datedExchangeRates); i++ {
+		datedExchangeRate := datedExchangeRates[i]
+
+		if datedExchangeRate.BaseCurrency != latestExchangeRateResponse.BaseCurrency || datedExchangeRate.Currency == latestExchangeRateResponse.BaseCurrency {
+			continue
+		}
+
+		effectiveExchangeRates[datedExchangeRate.Currency] = datedExchangeRate.ToLatestExchangeRate()
+	}
+
+	allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(latestExchangeRateResponse.ExchangeRates)+len(effectiveExchangeRates))
+
+	for i := 0; i < len(latestExchangeRateResponse.ExchangeRates); i++ {
+		exchangeRate := latestExchangeRateResponse.ExchangeRates[i]
+
+		if effectiveExchangeRate, exists := effectiveExchangeRates[exchangeRate.Currency]; exists {
+			allExchangeRates = append(allExchangeRates, effectiveExchangeRate)
+			delete(effectiveExchangeRates, exchangeRate.Currency)
+		} else {
+			allExchangeRates = append(allExchangeRates, exchangeRate)
+		}
+	}
+
+	for _, effectiveExchangeRate := range effectiveExchangeRates {
+		allExchangeRates = append(allExchangeRates, effectiveExchangeRate)
+	}
+
+	sort.Sort(allExchangeRates)
+
+	return &models.LatestExchangeRateResponse{
+		DataSource:    latestExchangeRateResponse.DataSource,
+		ReferenceUrl:  latestExchangeRateResponse.ReferenceUrl,
+		UpdateTime:    unixTime,
+		BaseCurrency:  latestExchangeRateResponse.BaseCurrency,
+		ExchangeRates: allExchangeRates,
+	}, nil
+}
+
+// GetExchangeRateHistories returns the daily exchange rate histories of the specified currencies according to the user custom dated exchange rates,
+// the rates are relative to the specified base currency, and the specified latest exchange rates are restored after the dated exchange rates end
+func (e *UserCustomExchangeRatesDataProvider) GetExchangeRateHistories(c core.Context, uid int64, baseCurrency string, latestExchangeRates map[string]float64) (map[string][]*models.HistoricalExchangeRate, error) {
+	datedExchangeRates, err := e.userCustomExchangeRates.GetAllDatedExchangeRatesByUid(c, uid, "")
+
+	if err != nil {
+		log.Errorf(c, "[user_custom_data_provider.GetExchangeRateHistories] failed to get user custom dated exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	currencies := make(map[string]bool)
+
+	for i := 0; i < len(datedExchangeRates); i++ {
+		currencies[datedExchangeRates[i].Currency] = true
+	}
+
+	histories := make(map[string][]*models.HistoricalExchangeRate, len(currencies))
+
+	for currency := range currencies {
+		if currency == baseCurrency {
+			continue
+		}
+
+		currencyHistories := models.GetUserCustomDatedExchangeRateHistories(datedExchangeRates, currency, baseCurrency, latestExchangeRates[currency])
+
+		if len(currencyHistories) > 0 {
+			histories[currency] = currencyHistories
+		}
+	}
+
+	return histories, nil
+}
+
 func newUserCustomExchangeRatesDataProvider() *UserCustomExchangeRatesDataProvider {
 	return &UserCustomExchangeRatesDataProvider{
 		users:                   services.Users,
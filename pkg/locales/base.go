@@ -11,6 +11,7 @@ type LocaleTextItems struct {
 	DataConverterTextItems      *DataConverterTextItems
 	VerifyEmailTextItems        *VerifyEmailTextItems
 	ForgetPasswordMailTextItems *ForgetPasswordMailTextItems
+	PriceAlertMailTextItems     *PriceAlertMailTextItems
 }
 
 // GlobalTextItems represents global text items need to be translated
@@ -47,3 +48,14 @@ type ForgetPasswordMailTextItems struct {
 	ResetPassword             string
 	DescriptionBelowBtnFormat string
 }
+
+// PriceAlertMailTextItems represents text items need to be translated in price alert mail
+type PriceAlertMailTextItems struct {
+	Title                      string
+	SalutationFormat           string
+	DescriptionAboveList       string
+	AboveThresholdFormat       string
+	BelowThresholdFormat       string
+	PercentageChangeFormat     string
+	DescriptionBelowListFormat string
+}
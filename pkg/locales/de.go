@@ -30,4 +30,13 @@ var de = &LocaleTextItems{
 		ResetPassword:             "Passwort zurücksetzen",
 		DescriptionBelowBtnFormat: "Wenn Sie nicht angefordert haben, Ihr Passwort zurückzusetzen, ignorieren Sie bitte diese E-Mail. Wenn Sie den obigen Link nicht anklicken können, kopieren Sie bitte die obige URL und fügen Sie sie in Ihren Browser ein. Der Link zum Zurücksetzen des Passworts wird nach %v Minuten ablaufen.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Preisalarm",
+		SalutationFormat:           "Hallo %s,",
+		DescriptionAboveList:       "Wir möchten Sie darüber informieren, dass die folgenden Preisalarme ausgelöst wurden.",
+		AboveThresholdFormat:       "%s: Der Preis %s %s ist über %s gestiegen",
+		BelowThresholdFormat:       "%s: Der Preis %s %s ist unter %s gefallen",
+		PercentageChangeFormat:     "%s: Der Preis %s %s hat sich in den letzten %[5]d Tagen um %[4]s%% verändert",
+		DescriptionBelowListFormat: "Sie erhalten diese E-Mail, weil Sie in %s Preisalarme erstellt haben. Die Alarme werden erneut gemeldet, nachdem ihre Bedingungen nicht mehr erfüllt sind.",
+	},
 }
@@ -30,4 +30,13 @@ var en = &LocaleTextItems{
 		ResetPassword:             "Reset Password",
 		DescriptionBelowBtnFormat: "If you did not request to reset your password, please simply disregard this email. If you cannot click the link above, please copy the above url and paste it into your browser. The password reset link will be expired after %v minutes.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Price Alert",
+		SalutationFormat:           "Hi %s,",
+		DescriptionAboveList:       "We would like to let you know that the following price alerts have been triggered.",
+		AboveThresholdFormat:       "%s: the price %s %s has risen above %s",
+		BelowThresholdFormat:       "%s: the price %s %s has fallen below %s",
+		PercentageChangeFormat:     "%s: the price %s %s has changed by %s%% in the last %d days",
+		DescriptionBelowListFormat: "You are receiving this email because you created price alerts in %s. The alerts would be notified again after their conditions are no longer met.",
+	},
 }
@@ -30,4 +30,13 @@ var es = &LocaleTextItems{
 		ResetPassword:             "Restablecer Contraseña",
 		DescriptionBelowBtnFormat: "Si no solicitó un restablecimiento de contraseña, simplemente descarte este correo. Si no puede hacer click en el link anterior, copie la url arriba mostrada y péguela en su navegadror. El enlace de restablecimiento de contraseña expira pasados %v minutos.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Alerta de Precio",
+		SalutationFormat:           "Hola %s,",
+		DescriptionAboveList:       "Le informamos que se han activado las siguientes alertas de precio.",
+		AboveThresholdFormat:       "%s: el precio %s %s ha subido por encima de %s",
+		BelowThresholdFormat:       "%s: el precio %s %s ha bajado por debajo de %s",
+		PercentageChangeFormat:     "%s: el precio %s %s ha cambiado un %s%% en los últimos %d días",
+		DescriptionBelowListFormat: "Recibe este correo porque creó alertas de precio en %s. Las alertas se notificarán de nuevo cuando sus condiciones dejen de cumplirse.",
+	},
 }
@@ -30,4 +30,13 @@ var fr = &LocaleTextItems{
 		ResetPassword:             "Réinitialiser le mot de passe",
 		DescriptionBelowBtnFormat: "Si vous n'avez pas demandé la réinitialisation de votre mot de passe, vous pouvez ignorer cet e-mail. Si vous ne pouvez pas cliquer sur le lien ci-dessus, copiez l'URL ci-dessus et collez-la dans votre navigateur. Le lien de réinitialisation du mot de passe expire après %v minutes.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Alerte de prix",
+		SalutationFormat:           "Bonjour %s,",
+		DescriptionAboveList:       "Nous vous informons que les alertes de prix suivantes ont été déclenchées.",
+		AboveThresholdFormat:       "%s : le prix %s %s est passé au-dessus de %s",
+		BelowThresholdFormat:       "%s : le prix %s %s est passé en dessous de %s",
+		PercentageChangeFormat:     "%s : le prix %s %s a varié de %s%% au cours des %d derniers jours",
+		DescriptionBelowListFormat: "Vous recevez cet e-mail car vous avez créé des alertes de prix dans %s. Les alertes seront de nouveau notifiées lorsque leurs conditions ne seront plus remplies.",
+	},
 }
@@ -30,4 +30,13 @@ var it = &LocaleTextItems{
 		ResetPassword:             "Reimposta password",
 		DescriptionBelowBtnFormat: "Se non hai chiesto alcun cambio della password, puoi ignorare questa mail. Se non riesci a cliccare il link, copia l'indirizzo URL qui sopra e incollalo nel tuo browser preferito. Il link di verifica scadrà tra %v minuti.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Avviso di prezzo",
+		SalutationFormat:           "Ciao %s,",
+		DescriptionAboveList:       "Ti informiamo che i seguenti avvisi di prezzo sono stati attivati.",
+		AboveThresholdFormat:       "%s: il prezzo %s %s è salito sopra %s",
+		BelowThresholdFormat:       "%s: il prezzo %s %s è sceso sotto %s",
+		PercentageChangeFormat:     "%s: il prezzo %s %s è variato del %s%% negli ultimi %d giorni",
+		DescriptionBelowListFormat: "Ricevi questa mail perché hai creato degli avvisi di prezzo in %s. Gli avvisi verranno notificati di nuovo quando le loro condizioni non saranno più soddisfatte.",
+	},
 }
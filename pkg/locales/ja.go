@@ -30,4 +30,13 @@ var ja = &LocaleTextItems{
 		ResetPassword:             "パスワードをリセット",
 		DescriptionBelowBtnFormat: "パスワードのリセットをリクエストしていない場合はこのメールを無視してください。上記のリンクをクリックできない場合は、上記のURLをコピーしてブラウザに貼り付けてください。パスワードリセットのリンクは%v分後に期限切れになります。",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "価格アラート",
+		SalutationFormat:           "こんにちは%s,",
+		DescriptionAboveList:       "次の価格アラートが発動されましたのでお知らせします。",
+		AboveThresholdFormat:       "%s: 価格 %s %s が %s を上回りました",
+		BelowThresholdFormat:       "%s: 価格 %s %s が %s を下回りました",
+		PercentageChangeFormat:     "%s: 価格 %s %s は過去%[5]d日間で %[4]s%% 変動しました",
+		DescriptionBelowListFormat: "このメールは、%s で価格アラートを作成したため送信されています。アラートは条件を満たさなくなった後、再び通知されます。",
+	},
 }
@@ -30,4 +30,13 @@ var ko = &LocaleTextItems{
 		ResetPassword:             "비밀번호 재설정",
 		DescriptionBelowBtnFormat: "비밀번호 재설정을 요청하지 않으셨다면 이 이메일을 무시해주세요. 위 링크를 클릭할 수 없는 경우, 위 URL을 복사하여 브라우저에 붙여넣어 주세요. 비밀번호 재설정 링크는 %v분 후에 만료됩니다.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "가격 알림",
+		SalutationFormat:           "안녕하세요 %s님,",
+		DescriptionAboveList:       "다음 가격 알림이 발생했음을 알려드립니다.",
+		AboveThresholdFormat:       "%s: 가격 %s %s이(가) %s을(를) 넘었습니다",
+		BelowThresholdFormat:       "%s: 가격 %s %s이(가) %s 아래로 떨어졌습니다",
+		PercentageChangeFormat:     "%s: 가격 %s %s이(가) 지난 %[5]d일 동안 %[4]s%% 변동했습니다",
+		DescriptionBelowListFormat: "%s에서 가격 알림을 생성하셨기 때문에 이 이메일을 받으셨습니다. 알림은 조건이 더 이상 충족되지 않은 후에 다시 전송됩니다.",
+	},
 }
@@ -30,4 +30,13 @@ var nl = &LocaleTextItems{
 		ResetPassword:             "Wachtwoord opnieuw instellen",
 		DescriptionBelowBtnFormat: "Als je geen verzoek hebt gedaan om je wachtwoord te resetten, kun je deze e-mail negeren. Als je niet op de bovenstaande link kunt klikken, kopieer dan de URL hierboven en plak deze in je browser. De link voor het opnieuw instellen van het wachtwoord verloopt na  %v minuten.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Prijsalarm",
+		SalutationFormat:           "Hallo %s,",
+		DescriptionAboveList:       "We laten je weten dat de volgende prijsalarmen zijn geactiveerd.",
+		AboveThresholdFormat:       "%s: de prijs %s %s is gestegen boven %s",
+		BelowThresholdFormat:       "%s: de prijs %s %s is gedaald onder %s",
+		PercentageChangeFormat:     "%s: de prijs %s %s is in de afgelopen %[5]d dagen met %[4]s%% veranderd",
+		DescriptionBelowListFormat: "Je ontvangt deze e-mail omdat je prijsalarmen hebt aangemaakt in %s. De alarmen worden opnieuw gemeld nadat niet meer aan hun voorwaarden wordt voldaan.",
+	},
 }
@@ -30,4 +30,13 @@ var ptBR = &LocaleTextItems{
 		ResetPassword:             "Redefinir Senha",
 		DescriptionBelowBtnFormat: "Se você não solicitou a redefinição de senha, basta ignorar este e-mail. Se não conseguir clicar no link acima, copie a URL acima e cole no seu navegador. O link de redefinição de senha expirará após %v minutos.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Alerta de Preço",
+		SalutationFormat:           "Olá %s,",
+		DescriptionAboveList:       "Gostaríamos de informar que os seguintes alertas de preço foram acionados.",
+		AboveThresholdFormat:       "%s: o preço %s %s subiu acima de %s",
+		BelowThresholdFormat:       "%s: o preço %s %s caiu abaixo de %s",
+		PercentageChangeFormat:     "%s: o preço %s %s variou %s%% nos últimos %d dias",
+		DescriptionBelowListFormat: "Você está recebendo este e-mail porque criou alertas de preço no %s. Os alertas serão notificados novamente depois que suas condições deixarem de ser atendidas.",
+	},
 }
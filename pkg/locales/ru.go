@@ -30,4 +30,13 @@ var ru = &LocaleTextItems{
 		ResetPassword:             "Сбросить пароль",
 		DescriptionBelowBtnFormat: "Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо. Если вы не можете нажать на ссылку выше, скопируйте указанный выше URL и вставьте его в браузер. Ссылка для сброса пароля истечет через %v минут.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Ценовое оповещение",
+		SalutationFormat:           "Здравствуйте %s,",
+		DescriptionAboveList:       "Сообщаем вам, что сработали следующие ценовые оповещения.",
+		AboveThresholdFormat:       "%s: цена %s %s поднялась выше %s",
+		BelowThresholdFormat:       "%s: цена %s %s опустилась ниже %s",
+		PercentageChangeFormat:     "%s: цена %s %s изменилась на %s%% за последние %d дн.",
+		DescriptionBelowListFormat: "Вы получили это письмо, потому что создали ценовые оповещения в %s. Оповещения сработают снова после того, как их условия перестанут выполняться.",
+	},
 }
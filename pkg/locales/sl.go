@@ -30,4 +30,13 @@ var sl = &LocaleTextItems{
 		ResetPassword:             "Ponastavi geslo",
 		DescriptionBelowBtnFormat: "Če niste zahtevali ponastavitve gesla, prosimo, da to e-poštno sporočilo preprosto prezrete. Če ne morete klikniti zgornje povezave, kopirajte zgornji URL in ga prilepite v brskalnik. Povezava za ponastavitev gesla bo potekla po %v minutah.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Cenovno opozorilo",
+		SalutationFormat:           "Zdravo %s,",
+		DescriptionAboveList:       "Obveščamo vas, da so se sprožila naslednja cenovna opozorila.",
+		AboveThresholdFormat:       "%s: cena %s %s se je dvignila nad %s",
+		BelowThresholdFormat:       "%s: cena %s %s je padla pod %s",
+		PercentageChangeFormat:     "%s: cena %s %s se je v zadnjih %[5]d dneh spremenila za %[4]s%%",
+		DescriptionBelowListFormat: "To e-poštno sporočilo ste prejeli, ker ste v %s ustvarili cenovna opozorila. Opozorila bodo znova poslana, ko njihovi pogoji ne bodo več izpolnjeni.",
+	},
 }
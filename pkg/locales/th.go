@@ -30,4 +30,13 @@ var th = &LocaleTextItems{
 		ResetPassword:             "ตั้งรหัสผ่านใหม่",
 		DescriptionBelowBtnFormat: "หากคุณไม่ได้ร้องขอให้รีเซ็ตรหัสผ่าน โปรดละเว้นอีเมลนี้ หากคุณไม่สามารถคลิกลิงก์ด้านบน โปรดคัดลอก URL ด้านบนและวางลงในเบราว์เซอร์ของคุณ ลิงก์รีเซ็ตรหัสผ่านจะหมดอายุหลังจาก %v นาที",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "การแจ้งเตือนราคา",
+		SalutationFormat:           "สวัสดี %s,",
+		DescriptionAboveList:       "เราขอแจ้งให้คุณทราบว่าการแจ้งเตือนราคาต่อไปนี้ได้ถูกทริกเกอร์แล้ว",
+		AboveThresholdFormat:       "%s: ราคา %s %s สูงกว่า %s แล้ว",
+		BelowThresholdFormat:       "%s: ราคา %s %s ต่ำกว่า %s แล้ว",
+		PercentageChangeFormat:     "%s: ราคา %s %s เปลี่ยนแปลง %s%% ในช่วง %d วันที่ผ่านมา",
+		DescriptionBelowListFormat: "คุณได้รับอีเมลนี้เนื่องจากคุณได้สร้างการแจ้งเตือนราคาใน %s การแจ้งเตือนจะถูกส่งอีกครั้งหลังจากเงื่อนไขไม่เป็นจริงแล้ว",
+	},
 }
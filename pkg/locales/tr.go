@@ -30,4 +30,13 @@ var tr = &LocaleTextItems{
 		ResetPassword:             "Şifreyi Sıfırla",
 		DescriptionBelowBtnFormat: "Eğer şifre sıfırlama talebinde bulunmadıysanız, lütfen bu e-postayı dikkate almayın. Eğer yukarıdaki bağlantıya tıklayamıyorsanız, lütfen adresi kopyalayıp tarayıcınıza yapıştırın. Şifre sıfırlama bağlantısının süresi %v dakika sonra dolacaktır.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Fiyat Alarmı",
+		SalutationFormat:           "Merhaba %s,",
+		DescriptionAboveList:       "Aşağıdaki fiyat alarmlarının tetiklendiğini bildirmek isteriz.",
+		AboveThresholdFormat:       "%s: fiyat %s %s, %s üzerine çıktı",
+		BelowThresholdFormat:       "%s: fiyat %s %s, %s altına düştü",
+		PercentageChangeFormat:     "%s: fiyat %s %s son %[5]d günde %%%[4]s değişti",
+		DescriptionBelowListFormat: "Bu e-postayı %s üzerinde fiyat alarmları oluşturduğunuz için alıyorsunuz. Alarmlar, koşulları artık karşılanmadığında yeniden bildirilecektir.",
+	},
 }
@@ -30,4 +30,13 @@ var uk = &LocaleTextItems{
 		ResetPassword:             "Скинути пароль",
 		DescriptionBelowBtnFormat: "Якщо ви не надсилали запит на скидання пароля, просто проігноруйте цей лист. Якщо ви не можете натиснути на посилання вище, скопіюйте вказану URL-адресу та вставте її у свій браузер. Посилання для скидання пароля буде дійсне протягом %v хвилин.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Цінове сповіщення",
+		SalutationFormat:           "Вітаємо, %s!",
+		DescriptionAboveList:       "Повідомляємо, що спрацювали такі цінові сповіщення.",
+		AboveThresholdFormat:       "%s: ціна %s %s піднялася вище %s",
+		BelowThresholdFormat:       "%s: ціна %s %s опустилася нижче %s",
+		PercentageChangeFormat:     "%s: ціна %s %s змінилася на %s%% за останні %d дн.",
+		DescriptionBelowListFormat: "Ви отримали цей лист, тому що створили цінові сповіщення в %s. Сповіщення спрацюють знову після того, як їхні умови перестануть виконуватися.",
+	},
 }
@@ -30,4 +30,13 @@ var vi = &LocaleTextItems{
 		ResetPassword:             "Đặt lại Mật khẩu",
 		DescriptionBelowBtnFormat: "Nếu bạn không yêu cầu đặt lại mật khẩu, vui lòng bỏ qua email này. Nếu bạn không thể nhấp vào liên kết trên, hãy sao chép và dán liên kết vào trình duyệt của bạn. Liên kết đặt lại mật khẩu sẽ hết hạn sau %v phút.",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "Cảnh báo Giá",
+		SalutationFormat:           "Chào %s,",
+		DescriptionAboveList:       "Chúng tôi xin thông báo rằng các cảnh báo giá sau đã được kích hoạt.",
+		AboveThresholdFormat:       "%s: giá %s %s đã tăng lên trên %s",
+		BelowThresholdFormat:       "%s: giá %s %s đã giảm xuống dưới %s",
+		PercentageChangeFormat:     "%s: giá %s %s đã thay đổi %s%% trong %d ngày qua",
+		DescriptionBelowListFormat: "Bạn nhận được email này vì bạn đã tạo cảnh báo giá trong %s. Các cảnh báo sẽ được thông báo lại sau khi điều kiện của chúng không còn được đáp ứng.",
+	},
 }
@@ -30,4 +30,13 @@ var zhHans = &LocaleTextItems{
 		ResetPassword:             "重置密码",
 		DescriptionBelowBtnFormat: "如果您没有请求重置密码，请直接忽略本邮件。如果您无法点击上述链接，请复制下方的地址然后在您的浏览器中粘贴。重置密码链接将在 %v 分钟后过期。",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "价格提醒",
+		SalutationFormat:           "%s 您好，",
+		DescriptionAboveList:       "以下价格提醒已被触发。",
+		AboveThresholdFormat:       "%s：价格 %s %s 已高于 %s",
+		BelowThresholdFormat:       "%s：价格 %s %s 已低于 %s",
+		PercentageChangeFormat:     "%s：价格 %s %s 在最近 %[5]d 天内变动了 %[4]s%%",
+		DescriptionBelowListFormat: "您收到此邮件是因为您在 %s 中创建了价格提醒。在提醒条件不再满足后，提醒将会再次通知。",
+	},
 }
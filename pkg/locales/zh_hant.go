@@ -30,4 +30,13 @@ var zhHant = &LocaleTextItems{
 		ResetPassword:             "重設密碼",
 		DescriptionBelowBtnFormat: "如果您沒有請求重設密碼，請直接忽略本郵件。如果您無法點擊上述連結，請複製下方的地址然後在您的瀏覽器中貼上。重設密碼連結將在 %v 分鐘後過期。",
 	},
+	PriceAlertMailTextItems: &PriceAlertMailTextItems{
+		Title:                      "價格提醒",
+		SalutationFormat:           "%s 您好，",
+		DescriptionAboveList:       "以下價格提醒已被觸發。",
+		AboveThresholdFormat:       "%s：價格 %s %s 已高於 %s",
+		BelowThresholdFormat:       "%s：價格 %s %s 已低於 %s",
+		PercentageChangeFormat:     "%s：價格 %s %s 在最近 %[5]d 天內變動了 %[4]s%%",
+		DescriptionBelowListFormat: "您收到此郵件是因為您在 %s 中建立了價格提醒。在提醒條件不再滿足後，提醒將會再次通知。",
+	},
 }
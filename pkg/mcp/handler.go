@@ -14,8 +14,11 @@ type MCPAvailableServices interface {
 	GetTransactionService() *services.TransactionService
 	GetTransactionCategoryService() *services.TransactionCategoryService
 	GetTransactionTagService() *services.TransactionTagService
+	GetTransactionSplitService() *services.TransactionSplitService
 	GetAccountService() *services.AccountService
 	GetUserService() *services.UserService
+	GetGoalService() *services.GoalService
+	GetAssetValuator(c *core.WebContext, uid int64, targetCurrency string, accounts []*models.Account, historyStartTime int64, historyEndTime int64) *models.AssetValuator
 }
 
 // MCPToolHandler defines the MCP tool handler
@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPQuerySavingsGoalsResponse represents the response structure for querying savings goals
+type MCPQuerySavingsGoalsResponse struct {
+	Goals []*MCPSavingsGoalInfo `json:"goals" jsonschema_description:"List of savings goals"`
+}
+
+// MCPSavingsGoalInfo defines the structure of savings goal progress information
+type MCPSavingsGoalInfo struct {
+	Name                        string   `json:"name" jsonschema_description:"Savings goal name"`
+	Accounts                    []string `json:"accounts" jsonschema_description:"Names of the accounts linked to the savings goal"`
+	Currency                    string   `json:"currency" jsonschema_description:"Currency code of the savings goal (e.g. USD, EUR)"`
+	TargetAmount                string   `json:"targetAmount" jsonschema_description:"Target amount of the savings goal"`
+	TargetDate                  string   `json:"targetDate,omitempty" jsonschema:"format=date" jsonschema_description:"Target date of the savings goal (YYYY-MM-DD format)"`
+	CurrentAmount               string   `json:"currentAmount" jsonschema_description:"Current total balance of the linked accounts"`
+	RemainingAmount             string   `json:"remainingAmount" jsonschema_description:"Remaining amount to reach the target amount"`
+	Percentage                  float64  `json:"percentage" jsonschema_description:"Percentage of the current amount to the target amount"`
+	Completed                   bool     `json:"completed" jsonschema_description:"Whether the savings goal has been reached"`
+	AverageMonthlyContribution  string   `json:"averageMonthlyContribution" jsonschema_description:"Average monthly contribution in the recent months"`
+	RequiredMonthlyContribution string   `json:"requiredMonthlyContribution,omitempty" jsonschema_description:"Monthly contribution required to reach the target amount before the target date"`
+	ProjectedCompletionDate     string   `json:"projectedCompletionDate,omitempty" jsonschema:"format=date" jsonschema_description:"Projected date to reach the target amount at the average monthly contribution (YYYY-MM-DD format)"`
+}
+
+type mcpQuerySavingsGoalsToolHandler struct{}
+
+var MCPQuerySavingsGoalsToolHandler = &mcpQuerySavingsGoalsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQuerySavingsGoalsToolHandler) Name() string {
+	return "query_savings_goals"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQuerySavingsGoalsToolHandler) Description() string {
+	return "Query all savings goals with their progress, required monthly contribution and projected completion date for the current user in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQuerySavingsGoalsToolHandler) InputType() reflect.Type {
+	return nil
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQuerySavingsGoalsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQuerySavingsGoalsResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQuerySavingsGoalsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	uid := user.Uid
+	goals, err := services.GetGoalService().GetAllGoalsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[query_savings_goals_tool_handler.Handle] failed to get all savings goals for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	accounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[query_savings_goals_tool_handler.Handle] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		clientTimezone = time.Local
+	}
+
+	accountMap := services.GetAccountService().GetAccountMapByList(accounts)
+	now := time.Now()
+	historyStartTime := models.GetGoalBalanceHistoryStartTime(now, clientTimezone)
+	monthEndBalances, err := services.GetGoalService().GetGoalMonthEndBalances(c, uid, accounts, now, clientTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[query_savings_goals_tool_handler.Handle] failed to get month-end account balances for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	valuators := make(map[string]*models.AssetValuator)
+	response := &MCPQuerySavingsGoalsResponse{
+		Goals: make([]*MCPSavingsGoalInfo, 0, len(goals)),
+	}
+
+	for i := 0; i < len(goals); i++ {
+		goal := goals[i]
+		valuator, exists := valuators[goal.Currency]
+
+		if !exists {
+			valuator = services.GetAssetValuator(c, uid, goal.Currency, accounts, historyStartTime.Unix(), now.Unix())
+			valuators[goal.Currency] = valuator
+		}
+
+		progress, err := services.GetGoalService().GetGoalProgress(c, goal, accounts, monthEndBalances, valuator, now, clientTimezone)
+
+		if err != nil {
+			log.Errorf(c, "[query_savings_goals_tool_handler.Handle] failed to get progress of savings goal \"id:%d\" for user \"uid:%d\", because %s", goal.GoalId, uid, err.Error())
+			return nil, nil, err
+		}
+
+		response.Goals = append(response.Goals, h.createNewMCPSavingsGoalInfo(goal, progress, accountMap, clientTimezone))
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func (h *mcpQuerySavingsGoalsToolHandler) createNewMCPSavingsGoalInfo(goal *models.Goal, progress *models.GoalProgressResponse, accountMap map[int64]*models.Account, clientTimezone *time.Location) *MCPSavingsGoalInfo {
+	accountIds := goal.GetAccountIds()
+	accountNames := make([]string, 0, len(accountIds))
+
+	for i := 0; i < len(accountIds); i++ {
+		if account, exists := accountMap[accountIds[i]]; exists {
+			accountNames = append(accountNames, account.Name)
+		}
+	}
+
+	goalInfo := &MCPSavingsGoalInfo{
+		Name:                       goal.Name,
+		Accounts:                   accountNames,
+		Currency:                   progress.Currency,
+		TargetAmount:               utils.FormatAmount(progress.TargetAmount),
+		CurrentAmount:              utils.FormatAmount(progress.CurrentAmount),
+		RemainingAmount:            utils.FormatAmount(progress.RemainingAmount),
+		Percentage:                 progress.Percentage,
+		Completed:                  progress.Completed,
+		AverageMonthlyContribution: utils.FormatAmount(progress.AverageMonthlyContribution),
+	}
+
+	if progress.TargetTime > 0 {
+		goalInfo.TargetDate = utils.FormatUnixTimeToLongDate(progress.TargetTime, clientTimezone)
+	}
+
+	if progress.RequiredMonthlyContribution > 0 {
+		goalInfo.RequiredMonthlyContribution = utils.FormatAmount(progress.RequiredMonthlyContribution)
+	}
+
+	if progress.ProjectedCompletionTime > 0 {
+		goalInfo.ProjectedCompletionDate = utils.FormatUnixTimeToLongDate(progress.ProjectedCompletionTime, clientTimezone)
+	}
+
+	return goalInfo
+}
@@ -37,16 +37,24 @@ type MCPQueryTransactionsResponse struct {
 
 // MCPTransactionInfo defines the structure of transaction information
 type MCPTransactionInfo struct {
-	Time                   string `json:"time,omitempty" jsonschema_description:"Time of the transaction in RFC 3339 format (e.g. 2023-01-01T12:00:00Z)"`
-	Type                   string `json:"type" jsonschema:"enum=income,enum=expense,enum=transfer" jsonschema_description:"Transaction type (income, expense, transfer)"`
-	Amount                 string `json:"amount" jsonschema_description:"Amount of the transaction in the specified currency"`
-	Currency               string `json:"currency,omitempty" jsonschema_description:"Currency code of the transaction (e.g. USD, EUR)"`
-	SecondaryCategoryName  string `json:"category_name,omitempty" jsonschema_description:"Secondary category name for the transaction"`
-	AccountName            string `json:"account_name,omitempty" jsonschema_description:"Account name for the transaction"`
-	DestinationAmount      string `json:"destination_amount,omitempty" jsonschema_description:"Destination amount for transfer transactions (optional)"`
-	DestinationCurrency    string `json:"destination_currency,omitempty" jsonschema_description:"Currency code of the destination amount for transfer transactions (optional)"`
-	DestinationAccountName string `json:"destination_account_name,omitempty" jsonschema_description:"Destination account name for transfer transactions (optional)"`
-	Comment                string `json:"comment,omitempty" jsonschema_description:"Description of the transaction"`
+	Time                   string                     `json:"time,omitempty" jsonschema_description:"Time of the transaction in RFC 3339 format (e.g. 2023-01-01T12:00:00Z)"`
+	Type                   string                     `json:"type" jsonschema:"enum=income,enum=expense,enum=transfer" jsonschema_description:"Transaction type (income, expense, transfer)"`
+	Amount                 string                     `json:"amount" jsonschema_description:"Amount of the transaction in the specified currency"`
+	Currency               string                     `json:"currency,omitempty" jsonschema_description:"Currency code of the transaction (e.g. USD, EUR)"`
+	SecondaryCategoryName  string                     `json:"category_name,omitempty" jsonschema_description:"Secondary category name for the transaction"`
+	AccountName            string                     `json:"account_name,omitempty" jsonschema_description:"Account name for the transaction"`
+	DestinationAmount      string                     `json:"destination_amount,omitempty" jsonschema_description:"Destination amount for transfer transactions (optional)"`
+	DestinationCurrency    string                     `json:"destination_currency,omitempty" jsonschema_description:"Currency code of the destination amount for transfer transactions (optional)"`
+	DestinationAccountName string                     `json:"destination_account_name,omitempty" jsonschema_description:"Destination account name for transfer transactions (optional)"`
+	Comment                string                     `json:"comment,omitempty" jsonschema_description:"Description of the transaction"`
+	Splits                 []*MCPTransactionSplitInfo `json:"splits,omitempty" jsonschema_description:"Split lines of the transaction, each line has its own category and amount (only for split transactions)"`
+}
+
+// MCPTransactionSplitInfo defines the structure of transaction split line information
+type MCPTransactionSplitInfo struct {
+	Amount                string `json:"amount" jsonschema_description:"Amount of the split line in the currency of the transaction"`
+	SecondaryCategoryName string `json:"category_name,omitempty" jsonschema_description:"Secondary category name for the split line"`
+	Comment               string `json:"comment,omitempty" jsonschema_description:"Description of the split line"`
 }
 
 type mcpQueryTransactionsToolHandler struct{}
@@ -161,7 +169,20 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 	}
 
 	transactions, err := services.GetTransactionService().GetTransactionsByMaxTime(c, uid, maxTransactionTime, minTransactionTime, transactionType, filterCategoryIds, filterAccountIds, nil, false, "", queryTransactionsRequest.Keyword, queryTransactionsRequest.Page, queryTransactionsRequest.Count, false, true)
-	structuredResponse, response, err := h.createNewMCPQueryTransactionsResponse(c, &queryTransactionsRequest, transactions, totalCount, services.GetAccountService().GetAccountMapByList(allAccounts), services.GetTransactionCategoryService().GetCategoryMapByList(allCategories))
+
+	if err != nil {
+		log.Errorf(c, "[query_transactions.Handle] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	allTransactionSplits, err := services.GetTransactionSplitService().GetAllSplitsOfTransactions(c, uid, services.GetTransactionService().GetTransactionIds(transactions))
+
+	if err != nil {
+		log.Errorf(c, "[query_transactions.Handle] failed to get transaction splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	structuredResponse, response, err := h.createNewMCPQueryTransactionsResponse(c, &queryTransactionsRequest, transactions, totalCount, services.GetAccountService().GetAccountMapByList(allAccounts), services.GetTransactionCategoryService().GetCategoryMapByList(allCategories), allTransactionSplits)
 
 	if err != nil {
 		return nil, nil, err
@@ -170,7 +191,7 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 	return structuredResponse, response, nil
 }
 
-func (h *mcpQueryTransactionsToolHandler) createNewMCPQueryTransactionsResponse(c *core.WebContext, queryTransactionsRequest *MCPQueryTransactionsRequest, transactions []*models.Transaction, totalCount int64, accountsMap map[int64]*models.Account, categoriesMap map[int64]*models.TransactionCategory) (any, []*MCPTextContent, error) {
+func (h *mcpQueryTransactionsToolHandler) createNewMCPQueryTransactionsResponse(c *core.WebContext, queryTransactionsRequest *MCPQueryTransactionsRequest, transactions []*models.Transaction, totalCount int64, accountsMap map[int64]*models.Account, categoriesMap map[int64]*models.TransactionCategory, allTransactionSplits map[int64][]*models.TransactionSplit) (any, []*MCPTextContent, error) {
 	response := MCPQueryTransactionsResponse{
 		TotalCount:   totalCount,
 		CurrentPage:  queryTransactionsRequest.Page,
@@ -244,6 +265,29 @@ func (h *mcpQueryTransactionsToolHandler) createNewMCPQueryTransactionsResponse(
 			transactionInfo.Comment = transaction.Comment
 		}
 
+		if splits := allTransactionSplits[transaction.TransactionId]; len(splits) > 0 {
+			transactionInfo.Splits = make([]*MCPTransactionSplitInfo, 0, len(splits))
+
+			for j := 0; j < len(splits); j++ {
+				split := splits[j]
+				splitInfo := &MCPTransactionSplitInfo{
+					Amount: utils.FormatAmount(split.Amount),
+				}
+
+				if _, exists := filteredFields["category_name"]; exists || len(filteredFields) == 0 {
+					if category, exists := categoriesMap[split.CategoryId]; exists && category != nil {
+						splitInfo.SecondaryCategoryName = category.Name
+					}
+				}
+
+				if _, exists := filteredFields["comment"]; exists || len(filteredFields) == 0 {
+					splitInfo.Comment = split.Comment
+				}
+
+				transactionInfo.Splits = append(transactionInfo.Splits, splitInfo)
+			}
+		}
+
 		response.Transactions = append(response.Transactions, &transactionInfo)
 	}
 
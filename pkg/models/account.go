@@ -104,6 +104,9 @@ type Account struct {
 type AccountExtend struct {
 	CreditCardStatementDate *int             `json:"creditCardStatementDate"`
 	AssetType               AccountAssetType `json:"assetType"`
+	WalletAddress           string           `json:"walletAddress,omitempty"`
+	WalletIncomeCategoryId  int64            `json:"walletIncomeCategoryId,omitempty"`
+	WalletExpenseCategoryId int64            `json:"walletExpenseCategoryId,omitempty"`
 }
 
 // AccountCreateRequest represents all parameters of account creation request
@@ -119,6 +122,9 @@ type AccountCreateRequest struct {
 	BalanceTime             int64                   `json:"balanceTime"`
 	Comment                 string                  `json:"comment" binding:"max=255"`
 	CreditCardStatementDate int                     `json:"creditCardStatementDate" binding:"min=0,max=28"`
+	WalletAddress           string                  `json:"walletAddress" binding:"max=128"`
+	WalletIncomeCategoryId  int64                   `json:"walletIncomeCategoryId,string" binding:"min=0"`
+	WalletExpenseCategoryId int64                   `json:"walletExpenseCategoryId,string" binding:"min=0"`
 	SubAccounts             []*AccountCreateRequest `json:"subAccounts" binding:"omitempty"`
 	ClientSessionId         string                  `json:"clientSessionId"`
 }
@@ -136,6 +142,9 @@ type AccountModifyRequest struct {
 	BalanceTime             *int64                  `json:"balanceTime" binding:"omitempty"`
 	Comment                 string                  `json:"comment" binding:"max=255"`
 	CreditCardStatementDate int                     `json:"creditCardStatementDate" binding:"min=0,max=28"`
+	WalletAddress           *string                 `json:"walletAddress" binding:"omitempty,max=128"`
+	WalletIncomeCategoryId  *int64                  `json:"walletIncomeCategoryId,string" binding:"omitempty,min=0"`
+	WalletExpenseCategoryId *int64                  `json:"walletExpenseCategoryId,string" binding:"omitempty,min=0"`
 	Hidden                  bool                    `json:"hidden"`
 	SubAccounts             []*AccountModifyRequest `json:"subAccounts" binding:"omitempty"`
 	ClientSessionId         string                  `json:"clientSessionId"`
@@ -188,6 +197,9 @@ type AccountInfoResponse struct {
 	TotalBalance            int64                    `json:"totalBalance"`
 	Comment                 string                   `json:"comment"`
 	CreditCardStatementDate *int                     `json:"creditCardStatementDate,omitempty"`
+	WalletAddress           string                   `json:"walletAddress,omitempty"`
+	WalletIncomeCategoryId  int64                    `json:"walletIncomeCategoryId,string,omitempty"`
+	WalletExpenseCategoryId int64                    `json:"walletExpenseCategoryId,string,omitempty"`
 	DisplayOrder            int32                    `json:"displayOrder"`
 	IsAsset                 bool                     `json:"isAsset,omitempty"`
 	IsLiability             bool                     `json:"isLiability,omitempty"`
@@ -195,31 +207,47 @@ type AccountInfoResponse struct {
 	SubAccounts             AccountInfoResponseSlice `json:"subAccounts,omitempty"`
 }
 
+// GetAssetType returns the asset type of the account, the asset type would be inferred from the currency if not set
+func (a *Account) GetAssetType() AccountAssetType {
+	if a.Extend != nil && a.Extend.AssetType != 0 {
+		return a.Extend.AssetType
+	}
+
+	if AllCryptocurrencySymbols[a.Currency] {
+		return ACCOUNT_ASSET_TYPE_CRYPTO
+	}
+
+	// unknown currencies are treated as fiat for backward compatibility
+	return ACCOUNT_ASSET_TYPE_FIAT
+}
+
+// GetWalletAddress returns the wallet address of the account, returns empty string if not set
+func (a *Account) GetWalletAddress() string {
+	if a.Extend == nil {
+		return ""
+	}
+
+	return a.Extend.WalletAddress
+}
+
 // ToAccountInfoResponse returns a view-object according to database model
 func (a *Account) ToAccountInfoResponse() *AccountInfoResponse {
 	var creditCardStatementDate *int
-	var assetType AccountAssetType
+	var walletAddress string
+	var walletIncomeCategoryId int64
+	var walletExpenseCategoryId int64
+	assetType := a.GetAssetType()
 
 	if a.Extend != nil {
-		assetType = a.Extend.AssetType
+		walletAddress = a.Extend.WalletAddress
+		walletIncomeCategoryId = a.Extend.WalletIncomeCategoryId
+		walletExpenseCategoryId = a.Extend.WalletExpenseCategoryId
 
 		if a.ParentAccountId == LevelOneAccountParentId && a.Category == ACCOUNT_CATEGORY_CREDIT_CARD {
 			creditCardStatementDate = a.Extend.CreditCardStatementDate
 		}
 	}
 
-	if assetType == 0 {
-		// Infer asset type from currency code if not set
-		if AllCryptocurrencySymbols[a.Currency] {
-			assetType = ACCOUNT_ASSET_TYPE_CRYPTO
-		} else if AllCurrencyNames[a.Currency] {
-			assetType = ACCOUNT_ASSET_TYPE_FIAT
-		} else {
-			// Default to fiat for backward compatibility (unknown currencies are treated as fiat)
-			assetType = ACCOUNT_ASSET_TYPE_FIAT
-		}
-	}
-
 	if creditCardStatementDate == nil && a.ParentAccountId == LevelOneAccountParentId && a.Category == ACCOUNT_CATEGORY_CREDIT_CARD {
 		creditCardStatementDate = &defaultCreditCardAccountStatementDate
 	}
@@ -243,6 +271,9 @@ func (a *Account) ToAccountInfoResponse() *AccountInfoResponse {
 		TotalBalance:            totalBalance,
 		Comment:                 a.Comment,
 		CreditCardStatementDate: creditCardStatementDate,
+		WalletAddress:           walletAddress,
+		WalletIncomeCategoryId:  walletIncomeCategoryId,
+		WalletExpenseCategoryId: walletExpenseCategoryId,
 		DisplayOrder:            a.DisplayOrder,
 		IsAsset:                 assetAccountCategory[a.Category],
 		IsLiability:             liabilityAccountCategory[a.Category],
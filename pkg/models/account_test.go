@@ -49,3 +49,17 @@ func TestAccountInfoResponseSliceLess(t *testing.T) {
 	assert.Equal(t, int64(5), accountRespSlice[4].Id)
 	assert.Equal(t, int64(3), accountRespSlice[5].Id)
 }
+
+func TestAccountGetAssetType(t *testing.T) {
+	account := &Account{Currency: "USD"}
+	assert.Equal(t, ACCOUNT_ASSET_TYPE_FIAT, account.GetAssetType())
+
+	account = &Account{Currency: "BTC"}
+	assert.Equal(t, ACCOUNT_ASSET_TYPE_CRYPTO, account.GetAssetType())
+
+	account = &Account{Currency: "AAPL", Extend: &AccountExtend{AssetType: ACCOUNT_ASSET_TYPE_STOCK}}
+	assert.Equal(t, ACCOUNT_ASSET_TYPE_STOCK, account.GetAssetType())
+
+	account = &Account{Currency: "UNKNOWN", Extend: &AccountExtend{}}
+	assert.Equal(t, ACCOUNT_ASSET_TYPE_FIAT, account.GetAssetType())
+}
@@ -0,0 +1,13 @@
+package models
+
+// AssetPriceHistory represents the daily price of a stock or a cryptocurrency stored in database,
+// the price histories of each asset type are stored in their own table
+type AssetPriceHistory struct {
+	Symbol          string `xorm:"PK VARCHAR(20) NOT NULL"`
+	DataSource      string `xorm:"PK VARCHAR(50) NOT NULL"`
+	Currency        string `xorm:"PK VARCHAR(10) NOT NULL"`
+	PriceDate       int32  `xorm:"PK NOT NULL"`
+	Price           string `xorm:"VARCHAR(32) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
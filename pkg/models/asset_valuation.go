@@ -0,0 +1,283 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// AssetPrice represents the price of a stock or a cryptocurrency at a specified date
+type AssetPrice struct {
+	Price     float64
+	Currency  string
+	PriceDate int32
+}
+
+// HistoricalExchangeRate represents the exchange rate of a currency at a specified date
+type HistoricalExchangeRate struct {
+	Rate     float64
+	RateDate int32
+}
+
+// AssetValuator represents the valuator which values account balances in the target currency,
+// the balances of stock and cryptocurrency accounts are the quantities and would be valued by their prices
+type AssetValuator struct {
+	TargetCurrency         string
+	ExchangeRates          map[string]float64
+	latestExchangeRateDate int32
+	exchangeRateHistories  map[string][]*HistoricalExchangeRate
+	latestPrices           map[AccountAssetType]map[string]*AssetPrice
+	priceHistories         map[AccountAssetType]map[string][]*AssetPrice
+}
+
+// TransferFxGainLoss represents the foreign exchange gain or loss of a cross-currency transfer compared with the reference exchange rate
+type TransferFxGainLoss struct {
+	EffectiveRate    float64
+	ReferenceRate    float64
+	ReferenceAmount  int64
+	GainLoss         int64
+	HasReferenceRate bool
+	HasGainLoss      bool
+}
+
+// NetWorthAccountResponse represents a view-object of the valuation of an account
+type NetWorthAccountResponse struct {
+	AccountId     int64            `json:"accountId,string"`
+	Currency      string           `json:"currency"`
+	AssetType     AccountAssetType `json:"assetType"`
+	Balance       int64            `json:"balance"`
+	Value         int64            `json:"value"`
+	HasValue      bool             `json:"hasValue"`
+	Price         string           `json:"price,omitempty"`
+	PriceCurrency string           `json:"priceCurrency,omitempty"`
+}
+
+// NetWorthResponse represents a view-object of net worth
+type NetWorthResponse struct {
+	Currency         string                     `json:"currency"`
+	TotalAssets      int64                      `json:"totalAssets"`
+	TotalLiabilities int64                      `json:"totalLiabilities"`
+	NetWorth         int64                      `json:"netWorth"`
+	Accounts         []*NetWorthAccountResponse `json:"accounts"`
+}
+
+// NewAssetValuator returns a new asset valuator, the exchange rates must be relative to the same base currency
+func NewAssetValuator(targetCurrency string, exchangeRates map[string]float64) *AssetValuator {
+	if exchangeRates == nil {
+		exchangeRates = make(map[string]float64)
+	}
+
+	return &AssetValuator{
+		TargetCurrency:        targetCurrency,
+		ExchangeRates:         exchangeRates,
+		exchangeRateHistories: make(map[string][]*HistoricalExchangeRate),
+		latestPrices:          make(map[AccountAssetType]map[string]*AssetPrice),
+		priceHistories:        make(map[AccountAssetType]map[string][]*AssetPrice),
+	}
+}
+
+// SetLatestExchangeRateDate sets the date (in yyyymmdd format) of the latest exchange rates,
+// the latest exchange rates would be used for the dates on or after it
+func (v *AssetValuator) SetLatestExchangeRateDate(date int32) {
+	v.latestExchangeRateDate = date
+}
+
+// SetExchangeRateHistories sets the daily exchange rate histories of the specified currency,
+// the rates must be relative to the same base currency as the latest exchange rates
+func (v *AssetValuator) SetExchangeRateHistories(currency string, rates []*HistoricalExchangeRate) {
+	histories := make([]*HistoricalExchangeRate, 0, len(rates))
+
+	for i := 0; i < len(rates); i++ {
+		if rates[i] != nil && rates[i].Rate > 0 {
+			histories = append(histories, rates[i])
+		}
+	}
+
+	sort.SliceStable(histories, func(i, j int) bool {
+		return histories[i].RateDate < histories[j].RateDate
+	})
+
+	v.exchangeRateHistories[currency] = histories
+}
+
+// GetExchangeRate returns the exchange rate of the specified currency at the specified date (in yyyymmdd format, 0 means the latest exchange rate),
+// returns false if the exchange rate histories of the currency are set but there is no exchange rate history on or before the date,
+// and the latest exchange rate would be used only if the exchange rate histories of the currency are not set
+func (v *AssetValuator) GetExchangeRate(currency string, date int32) (float64, bool) {
+	if date > 0 && (v.latestExchangeRateDate <= 0 || date < v.latestExchangeRateDate) {
+		histories, exists := v.exchangeRateHistories[currency]
+		index := sort.Search(len(histories), func(i int) bool {
+			return histories[i].RateDate > date
+		})
+
+		if index > 0 {
+			return histories[index-1].Rate, true
+		} else if exists {
+			return 0, false
+		}
+	}
+
+	rate, exists := v.ExchangeRates[currency]
+
+	if !exists || rate <= 0 {
+		return 0, false
+	}
+
+	return rate, true
+}
+
+// GetCrossExchangeRate returns the amount of the target currency per one source currency at the specified date (in yyyymmdd format, 0 means the latest)
+func (v *AssetValuator) GetCrossExchangeRate(sourceCurrency string, targetCurrency string, date int32) (float64, bool) {
+	if sourceCurrency == targetCurrency {
+		return 1, true
+	}
+
+	sourceRate, sourceExists := v.GetExchangeRate(sourceCurrency, date)
+	targetRate, targetExists := v.GetExchangeRate(targetCurrency, date)
+
+	if !sourceExists || !targetExists {
+		return 0, false
+	}
+
+	return targetRate / sourceRate, true
+}
+
+// GetTransferFxGainLoss returns the effective exchange rate of a cross-currency transfer, and compares the received amount with the amount at the reference exchange rate of the transfer date,
+// the difference is valued in target currency, a positive value means gain and a negative value means loss (including the fees charged by the spread)
+func (v *AssetValuator) GetTransferFxGainLoss(sourceCurrency string, sourceAmount int64, destinationCurrency string, destinationAmount int64, date int32) *TransferFxGainLoss {
+	result := &TransferFxGainLoss{}
+
+	if sourceAmount == 0 {
+		return result
+	}
+
+	sourceValue := float64(sourceAmount) / utils.Pow10(GetCurrencyFraction(sourceCurrency))
+	destinationValue := float64(destinationAmount) / utils.Pow10(GetCurrencyFraction(destinationCurrency))
+	result.EffectiveRate = destinationValue / sourceValue
+
+	referenceRate, ok := v.GetCrossExchangeRate(sourceCurrency, destinationCurrency, date)
+
+	if !ok {
+		return result
+	}
+
+	result.ReferenceRate = referenceRate
+	result.ReferenceAmount = int64(math.Round(sourceValue * referenceRate * utils.Pow10(GetCurrencyFraction(destinationCurrency))))
+	result.HasReferenceRate = true
+	result.GainLoss, result.HasGainLoss = v.GetValue(ACCOUNT_ASSET_TYPE_FIAT, destinationCurrency, destinationAmount-result.ReferenceAmount, date)
+
+	return result
+}
+
+// SetLatestPrice sets the latest price of the specified symbol
+func (v *AssetValuator) SetLatestPrice(assetType AccountAssetType, symbol string, price *AssetPrice) {
+	if price == nil || price.Price <= 0 {
+		return
+	}
+
+	if _, exists := v.latestPrices[assetType]; !exists {
+		v.latestPrices[assetType] = make(map[string]*AssetPrice)
+	}
+
+	v.latestPrices[assetType][strings.ToUpper(symbol)] = price
+}
+
+// SetPriceHistories sets the daily price histories of the specified symbol
+func (v *AssetValuator) SetPriceHistories(assetType AccountAssetType, symbol string, prices []*AssetPrice) {
+	histories := make([]*AssetPrice, 0, len(prices))
+
+	for i := 0; i < len(prices); i++ {
+		if prices[i] != nil && prices[i].Price > 0 {
+			histories = append(histories, prices[i])
+		}
+	}
+
+	sort.SliceStable(histories, func(i, j int) bool {
+		return histories[i].PriceDate < histories[j].PriceDate
+	})
+
+	if _, exists := v.priceHistories[assetType]; !exists {
+		v.priceHistories[assetType] = make(map[string][]*AssetPrice)
+	}
+
+	v.priceHistories[assetType][strings.ToUpper(symbol)] = histories
+}
+
+// GetPrice returns the price of the specified symbol at the specified date (in yyyymmdd format, 0 means the latest price),
+// returns nil if the price histories of the symbol are set but there is no price history on or before the date,
+// and the latest price would be used only if the price histories of the symbol are not set
+func (v *AssetValuator) GetPrice(assetType AccountAssetType, symbol string, date int32) *AssetPrice {
+	symbol = strings.ToUpper(symbol)
+	latestPrice := v.latestPrices[assetType][symbol]
+
+	if date <= 0 || (latestPrice != nil && date >= latestPrice.PriceDate) {
+		return latestPrice
+	}
+
+	histories, exists := v.priceHistories[assetType][symbol]
+	index := sort.Search(len(histories), func(i int) bool {
+		return histories[i].PriceDate > date
+	})
+
+	if index > 0 {
+		return histories[index-1]
+	} else if exists {
+		return nil
+	}
+
+	return latestPrice
+}
+
+// GetValue returns the value in target currency of the balance of specified asset at the specified date (in yyyymmdd format, 0 means the latest),
+// the price and the exchange rate at the date would be used, and returns false if the price or the exchange rate is not available
+func (v *AssetValuator) GetValue(assetType AccountAssetType, currency string, balance int64, date int32) (int64, bool) {
+	if assetType != ACCOUNT_ASSET_TYPE_STOCK && assetType != ACCOUNT_ASSET_TYPE_CRYPTO {
+		if currency == v.TargetCurrency {
+			return balance, true
+		}
+
+		amount, ok := v.convert(float64(balance)/utils.Pow10(GetCurrencyFraction(currency)), currency, date)
+
+		if !ok {
+			return 0, false
+		}
+
+		return v.toTargetCurrencyAmount(amount), true
+	}
+
+	price := v.GetPrice(assetType, currency, date)
+
+	if price == nil {
+		return 0, false
+	}
+
+	quantity := float64(balance) / utils.Pow10(GetCurrencyFraction(currency))
+	amount, ok := v.convert(quantity*price.Price, price.Currency, date)
+
+	if !ok {
+		return 0, false
+	}
+
+	return v.toTargetCurrencyAmount(amount), true
+}
+
+func (v *AssetValuator) convert(amount float64, currency string, date int32) (float64, bool) {
+	if currency == v.TargetCurrency {
+		return amount, true
+	}
+
+	sourceRate, sourceExists := v.GetExchangeRate(currency, date)
+	targetRate, targetExists := v.GetExchangeRate(v.TargetCurrency, date)
+
+	if !sourceExists || !targetExists {
+		return 0, false
+	}
+
+	return amount / sourceRate * targetRate, true
+}
+
+func (v *AssetValuator) toTargetCurrencyAmount(amount float64) int64 {
+	return int64(math.Round(amount * utils.Pow10(GetCurrencyFraction(v.TargetCurrency))))
+}
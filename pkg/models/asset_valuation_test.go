@@ -0,0 +1,227 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestAssetValuator() *AssetValuator {
+	valuator := NewAssetValuator("CNY", map[string]float64{
+		"USD": 1,
+		"CNY": 7.2,
+		"JPY": 150,
+	})
+
+	valuator.SetLatestPrice(ACCOUNT_ASSET_TYPE_STOCK, "aapl", &AssetPrice{Price: 200, Currency: "USD", PriceDate: 20240110})
+	valuator.SetPriceHistories(ACCOUNT_ASSET_TYPE_STOCK, "AAPL", []*AssetPrice{
+		{Price: 190, Currency: "USD", PriceDate: 20240105},
+		{Price: 180, Currency: "USD", PriceDate: 20240101},
+		{Price: 185, Currency: "USD", PriceDate: 20240103},
+	})
+	valuator.SetLatestPrice(ACCOUNT_ASSET_TYPE_CRYPTO, "BTC", &AssetPrice{Price: 40000, Currency: "USD", PriceDate: 20240110})
+	valuator.SetLatestPrice(ACCOUNT_ASSET_TYPE_STOCK, "EUSTOCK", &AssetPrice{Price: 10, Currency: "EUR", PriceDate: 20240110})
+
+	return valuator
+}
+
+func TestAssetValuatorGetPrice(t *testing.T) {
+	valuator := getTestAssetValuator()
+
+	assert.Equal(t, float64(200), valuator.GetPrice(ACCOUNT_ASSET_TYPE_STOCK, "AAPL", 0).Price)
+	assert.Equal(t, float64(200), valuator.GetPrice(ACCOUNT_ASSET_TYPE_STOCK, "AAPL", 20240110).Price)
+	assert.Equal(t, float64(200), valuator.GetPrice(ACCOUNT_ASSET_TYPE_STOCK, "AAPL", 20240120).Price)
+	assert.Equal(t, float64(190), valuator.GetPrice(ACCOUNT_ASSET_TYPE_STOCK, "AAPL", 20240108).Price)
+	assert.Equal(t, float64(185), valuator.GetPrice(ACCOUNT_ASSET_TYPE_STOCK, "aapl", 20240104).Price)
+	assert.Equal(t, float64(180), valuator.GetPrice(ACCOUNT_ASSET_TYPE_STOCK, "AAPL", 20240101).Price)
+}
+
+func TestAssetValuatorGetPrice_NoPriceHistoryBeforeDate(t *testing.T) {
+	valuator := getTestAssetValuator()
+
+	assert.Nil(t, valuator.GetPrice(ACCOUNT_ASSET_TYPE_STOCK, "AAPL", 20231231))
+	assert.Equal(t, float64(40000), valuator.GetPrice(ACCOUNT_ASSET_TYPE_CRYPTO, "BTC", 20231231).Price)
+
+	_, ok := valuator.GetValue(ACCOUNT_ASSET_TYPE_STOCK, "AAPL", 1000, 20231231)
+	assert.False(t, ok)
+}
+
+func TestAssetValuatorGetPrice_NoPrice(t *testing.T) {
+	valuator := getTestAssetValuator()
+
+	assert.Nil(t, valuator.GetPrice(ACCOUNT_ASSET_TYPE_STOCK, "MSFT", 0))
+	assert.Nil(t, valuator.GetPrice(ACCOUNT_ASSET_TYPE_CRYPTO, "AAPL", 0))
+}
+
+func TestAssetValuatorGetValue_Fiat(t *testing.T) {
+	valuator := getTestAssetValuator()
+
+	value, ok := valuator.GetValue(ACCOUNT_ASSET_TYPE_FIAT, "CNY", 12345, 0)
+	assert.True(t, ok)
+	assert.Equal(t, int64(12345), value)
+
+	value, ok = valuator.GetValue(ACCOUNT_ASSET_TYPE_FIAT, "USD", 10000, 0)
+	assert.True(t, ok)
+	assert.Equal(t, int64(72000), value)
+
+	value, ok = valuator.GetValue(ACCOUNT_ASSET_TYPE_FIAT, "JPY", 15000, 0)
+	assert.True(t, ok)
+	assert.Equal(t, int64(72000), value)
+
+	_, ok = valuator.GetValue(ACCOUNT_ASSET_TYPE_FIAT, "EUR", 10000, 0)
+	assert.False(t, ok)
+}
+
+func TestAssetValuatorGetValue_Stock(t *testing.T) {
+	valuator := getTestAssetValuator()
+
+	value, ok := valuator.GetValue(ACCOUNT_ASSET_TYPE_STOCK, "AAPL", 1000, 0)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1440000), value)
+
+	value, ok = valuator.GetValue(ACCOUNT_ASSET_TYPE_STOCK, "AAPL", 1000, 20240102)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1296000), value)
+
+	_, ok = valuator.GetValue(ACCOUNT_ASSET_TYPE_STOCK, "MSFT", 1000, 0)
+	assert.False(t, ok)
+
+	_, ok = valuator.GetValue(ACCOUNT_ASSET_TYPE_STOCK, "EUSTOCK", 1000, 0)
+	assert.False(t, ok)
+}
+
+func TestAssetValuatorGetValue_Cryptocurrency(t *testing.T) {
+	valuator := getTestAssetValuator()
+
+	value, ok := valuator.GetValue(ACCOUNT_ASSET_TYPE_CRYPTO, "BTC", 50000000, 0)
+	assert.True(t, ok)
+	assert.Equal(t, int64(14400000), value)
+}
+
+func TestAssetValuatorGetExchangeRate(t *testing.T) {
+	valuator := getTestAssetValuator()
+	valuator.SetExchangeRateHistories("CNY", []*HistoricalExchangeRate{
+		{Rate: 7.1, RateDate: 20240105},
+		{Rate: 7.0, RateDate: 20240101},
+		{Rate: 0, RateDate: 20240103},
+	})
+
+	rate, ok := valuator.GetExchangeRate("CNY", 0)
+	assert.True(t, ok)
+	assert.Equal(t, 7.2, rate)
+
+	_, ok = valuator.GetExchangeRate("CNY", 20231231)
+	assert.False(t, ok)
+
+	rate, ok = valuator.GetExchangeRate("CNY", 20240101)
+	assert.True(t, ok)
+	assert.Equal(t, 7.0, rate)
+
+	rate, ok = valuator.GetExchangeRate("CNY", 20240104)
+	assert.True(t, ok)
+	assert.Equal(t, 7.0, rate)
+
+	rate, ok = valuator.GetExchangeRate("CNY", 20240120)
+	assert.True(t, ok)
+	assert.Equal(t, 7.1, rate)
+
+	rate, ok = valuator.GetExchangeRate("JPY", 20240104)
+	assert.True(t, ok)
+	assert.Equal(t, float64(150), rate)
+
+	_, ok = valuator.GetExchangeRate("EUR", 20240104)
+	assert.False(t, ok)
+}
+
+func TestAssetValuatorGetValue_HistoricalExchangeRates(t *testing.T) {
+	valuator := getTestAssetValuator()
+	valuator.SetExchangeRateHistories("CNY", []*HistoricalExchangeRate{
+		{Rate: 7.0, RateDate: 20240101},
+	})
+	valuator.SetExchangeRateHistories("EUR", []*HistoricalExchangeRate{
+		{Rate: 0.9, RateDate: 20240101},
+	})
+
+	value, ok := valuator.GetValue(ACCOUNT_ASSET_TYPE_FIAT, "USD", 10000, 0)
+	assert.True(t, ok)
+	assert.Equal(t, int64(72000), value)
+
+	value, ok = valuator.GetValue(ACCOUNT_ASSET_TYPE_FIAT, "USD", 10000, 20240102)
+	assert.True(t, ok)
+	assert.Equal(t, int64(70000), value)
+
+	value, ok = valuator.GetValue(ACCOUNT_ASSET_TYPE_FIAT, "EUR", 9000, 20240102)
+	assert.True(t, ok)
+	assert.Equal(t, int64(70000), value)
+
+	_, ok = valuator.GetValue(ACCOUNT_ASSET_TYPE_FIAT, "EUR", 9000, 0)
+	assert.False(t, ok)
+
+	value, ok = valuator.GetValue(ACCOUNT_ASSET_TYPE_STOCK, "AAPL", 1000, 20240102)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1260000), value)
+}
+
+func TestAssetValuatorGetCrossExchangeRate(t *testing.T) {
+	valuator := getTestAssetValuator()
+
+	rate, ok := valuator.GetCrossExchangeRate("USD", "CNY", 0)
+	assert.True(t, ok)
+	assert.Equal(t, 7.2, rate)
+
+	rate, ok = valuator.GetCrossExchangeRate("CNY", "CNY", 0)
+	assert.True(t, ok)
+	assert.Equal(t, float64(1), rate)
+
+	_, ok = valuator.GetCrossExchangeRate("USD", "EUR", 0)
+	assert.False(t, ok)
+}
+
+func TestAssetValuatorGetTransferFxGainLoss(t *testing.T) {
+	valuator := getTestAssetValuator()
+	valuator.SetExchangeRateHistories("CNY", []*HistoricalExchangeRate{
+		{Rate: 7.0, RateDate: 20240101},
+	})
+
+	fxGainLoss := valuator.GetTransferFxGainLoss("USD", 10000, "CNY", 70000, 0)
+	assert.Equal(t, 7.0, fxGainLoss.EffectiveRate)
+	assert.Equal(t, 7.2, fxGainLoss.ReferenceRate)
+	assert.Equal(t, int64(72000), fxGainLoss.ReferenceAmount)
+	assert.True(t, fxGainLoss.HasReferenceRate)
+	assert.Equal(t, int64(-2000), fxGainLoss.GainLoss)
+	assert.True(t, fxGainLoss.HasGainLoss)
+
+	fxGainLoss = valuator.GetTransferFxGainLoss("USD", 10000, "CNY", 70500, 20240102)
+	assert.Equal(t, int64(70000), fxGainLoss.ReferenceAmount)
+	assert.Equal(t, int64(500), fxGainLoss.GainLoss)
+	assert.True(t, fxGainLoss.HasGainLoss)
+
+	fxGainLoss = valuator.GetTransferFxGainLoss("JPY", 15000, "USD", 9900, 0)
+	assert.Equal(t, 0.0066, fxGainLoss.EffectiveRate)
+	assert.Equal(t, int64(10000), fxGainLoss.ReferenceAmount)
+	assert.Equal(t, int64(-720), fxGainLoss.GainLoss)
+
+	fxGainLoss = valuator.GetTransferFxGainLoss("USD", 10000, "EUR", 9000, 0)
+	assert.Equal(t, 0.9, fxGainLoss.EffectiveRate)
+	assert.False(t, fxGainLoss.HasReferenceRate)
+	assert.False(t, fxGainLoss.HasGainLoss)
+}
+
+func TestAssetValuatorGetExchangeRate_LatestExchangeRateDate(t *testing.T) {
+	valuator := getTestAssetValuator()
+	valuator.SetLatestExchangeRateDate(20240110)
+	valuator.SetExchangeRateHistories("CNY", []*HistoricalExchangeRate{
+		{Rate: 7.1, RateDate: 20240105},
+	})
+
+	rate, ok := valuator.GetExchangeRate("CNY", 20240108)
+	assert.True(t, ok)
+	assert.Equal(t, 7.1, rate)
+
+	rate, ok = valuator.GetExchangeRate("CNY", 20240110)
+	assert.True(t, ok)
+	assert.Equal(t, 7.2, rate)
+
+	_, ok = valuator.GetExchangeRate("CNY", 20240101)
+	assert.False(t, ok)
+}
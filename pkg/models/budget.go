@@ -0,0 +1,231 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MaximumCategoriesCountOfBudget is the maximum count of categories of one budget
+const MaximumCategoriesCountOfBudget = 10
+
+// MaximumBudgetRolloverPeriods is the maximum count of previous periods whose unspent amounts can be rolled over
+const MaximumBudgetRolloverPeriods = 36
+
+// BudgetPeriodType represents budget period type
+type BudgetPeriodType byte
+
+// Budget period types
+const (
+	BUDGET_PERIOD_TYPE_WEEKLY      BudgetPeriodType = 1
+	BUDGET_PERIOD_TYPE_MONTHLY     BudgetPeriodType = 2
+	BUDGET_PERIOD_TYPE_YEARLY      BudgetPeriodType = 3
+	BUDGET_PERIOD_TYPE_FISCAL_YEAR BudgetPeriodType = 4
+)
+
+// String returns a textual representation of the budget period type
+func (t BudgetPeriodType) String() string {
+	switch t {
+	case BUDGET_PERIOD_TYPE_WEEKLY:
+		return "Weekly"
+	case BUDGET_PERIOD_TYPE_MONTHLY:
+		return "Monthly"
+	case BUDGET_PERIOD_TYPE_YEARLY:
+		return "Yearly"
+	case BUDGET_PERIOD_TYPE_FISCAL_YEAR:
+		return "Fiscal Year"
+	default:
+		return "Invalid"
+	}
+}
+
+// Budget represents a spending budget of an expense category or a group of expense categories stored in database,
+// the amount is in the default currency of user, and the unspent amount of each period would be added to the next period if rollover is enabled
+type Budget struct {
+	BudgetId        int64            `xorm:"PK"`
+	Uid             int64            `xorm:"INDEX(IDX_budget_uid_deleted_order) NOT NULL"`
+	Deleted         bool             `xorm:"INDEX(IDX_budget_uid_deleted_order) NOT NULL"`
+	Name            string           `xorm:"VARCHAR(64) NOT NULL"`
+	PeriodType      BudgetPeriodType `xorm:"NOT NULL"`
+	CategoryIds     string           `xorm:"VARCHAR(255) NOT NULL"`
+	Amount          int64            `xorm:"NOT NULL"`
+	Rollover        bool             `xorm:"NOT NULL"`
+	StartUnixTime   int64            `xorm:"NOT NULL"`
+	DisplayOrder    int32            `xorm:"INDEX(IDX_budget_uid_deleted_order) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// BudgetGetRequest represents all parameters of budget getting request
+type BudgetGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// BudgetCreateRequest represents all parameters of budget creation request
+type BudgetCreateRequest struct {
+	Name        string           `json:"name" binding:"required,notBlank,max=64"`
+	PeriodType  BudgetPeriodType `json:"periodType" binding:"required"`
+	CategoryIds []string         `json:"categoryIds" binding:"required,min=1"`
+	Amount      int64            `json:"amount" binding:"min=1,max=99999999999"`
+	Rollover    bool             `json:"rollover"`
+	StartTime   int64            `json:"startTime" binding:"min=0"`
+}
+
+// BudgetModifyRequest represents all parameters of budget modification request
+type BudgetModifyRequest struct {
+	Id          int64            `json:"id,string" binding:"required,min=1"`
+	Name        string           `json:"name" binding:"required,notBlank,max=64"`
+	PeriodType  BudgetPeriodType `json:"periodType" binding:"required"`
+	CategoryIds []string         `json:"categoryIds" binding:"required,min=1"`
+	Amount      int64            `json:"amount" binding:"min=1,max=99999999999"`
+	Rollover    bool             `json:"rollover"`
+	StartTime   int64            `json:"startTime" binding:"min=0"`
+}
+
+// BudgetDeleteRequest represents all parameters of budget deleting request
+type BudgetDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// BudgetProgressRequest represents all parameters of budget progress request
+type BudgetProgressRequest struct {
+	Id                     int64  `form:"id,string" binding:"required,min=1"`
+	Time                   int64  `form:"time" binding:"min=0"`
+	TagFilter              string `form:"tag_filter" binding:"validTagFilter"`
+	Keyword                string `form:"keyword"`
+	UseTransactionTimezone bool   `form:"use_transaction_timezone"`
+}
+
+// BudgetInfoResponse represents a view-object of budget
+type BudgetInfoResponse struct {
+	Id          int64            `json:"id,string"`
+	Name        string           `json:"name"`
+	PeriodType  BudgetPeriodType `json:"periodType"`
+	CategoryIds []string         `json:"categoryIds"`
+	Amount      int64            `json:"amount"`
+	Rollover    bool             `json:"rollover"`
+	StartTime   int64            `json:"startTime"`
+}
+
+// BudgetProgressResponse represents a view-object of budget progress in a period
+type BudgetProgressResponse struct {
+	Id               int64  `json:"id,string"`
+	PeriodStartTime  int64  `json:"periodStartTime"`
+	PeriodEndTime    int64  `json:"periodEndTime"`
+	Currency         string `json:"currency"`
+	Amount           int64  `json:"amount"`
+	RolloverAmount   int64  `json:"rolloverAmount"`
+	TotalAmount      int64  `json:"totalAmount"`
+	SpentAmount      int64  `json:"spentAmount"`
+	RemainingAmount  int64  `json:"remainingAmount"`
+	IncompleteAmount bool   `json:"incompleteAmount,omitempty"`
+}
+
+// GetCategoryIds returns all category ids of the budget
+func (b *Budget) GetCategoryIds() []int64 {
+	categoryIds := make([]string, 0)
+
+	if b.CategoryIds != "" {
+		categoryIds = strings.Split(b.CategoryIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(categoryIds)
+
+	return result
+}
+
+// GetPeriodTimeRange returns the start unix time (inclusive) and the end unix time (exclusive) of the budget period which contains the specified unix time
+func (b *Budget) GetPeriodTimeRange(unixTime int64, firstDayOfWeek core.WeekDay, fiscalYearStart core.FiscalYearStart, timezone *time.Location) (int64, int64, error) {
+	t := time.Unix(unixTime, 0).In(timezone)
+	todayStartTime := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, timezone)
+
+	switch b.PeriodType {
+	case BUDGET_PERIOD_TYPE_WEEKLY:
+		dayOfWeek := int(t.Weekday()) - int(firstDayOfWeek)
+
+		if dayOfWeek < 0 {
+			dayOfWeek += 7
+		}
+
+		startTime := todayStartTime.AddDate(0, 0, -dayOfWeek)
+		return startTime.Unix(), startTime.AddDate(0, 0, 7).Unix(), nil
+	case BUDGET_PERIOD_TYPE_MONTHLY:
+		startTime := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, timezone)
+		return startTime.Unix(), startTime.AddDate(0, 1, 0).Unix(), nil
+	case BUDGET_PERIOD_TYPE_YEARLY:
+		startTime := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, timezone)
+		return startTime.Unix(), startTime.AddDate(1, 0, 0).Unix(), nil
+	case BUDGET_PERIOD_TYPE_FISCAL_YEAR:
+		if fiscalYearStart < core.FISCAL_YEAR_START_MIN || fiscalYearStart > core.FISCAL_YEAR_START_MAX {
+			fiscalYearStart = core.FISCAL_YEAR_START_DEFAULT
+		}
+
+		fiscalYear := int32(t.Year())
+		startUnixTime, endUnixTime, err := fiscalYearStart.GetFiscalYearTimeRange(fiscalYear, timezone)
+
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if unixTime < startUnixTime {
+			return fiscalYearStart.GetFiscalYearTimeRange(fiscalYear-1, timezone)
+		} else if unixTime >= endUnixTime {
+			return fiscalYearStart.GetFiscalYearTimeRange(fiscalYear+1, timezone)
+		}
+
+		return startUnixTime, endUnixTime, nil
+	default:
+		return 0, 0, errs.ErrBudgetPeriodTypeInvalid
+	}
+}
+
+// ToBudgetInfoResponse returns a view-object according to database model
+func (b *Budget) ToBudgetInfoResponse() *BudgetInfoResponse {
+	return &BudgetInfoResponse{
+		Id:          b.BudgetId,
+		Name:        b.Name,
+		PeriodType:  b.PeriodType,
+		CategoryIds: utils.Int64ArrayToStringArray(b.GetCategoryIds()),
+		Amount:      b.Amount,
+		Rollover:    b.Rollover,
+		StartTime:   b.StartUnixTime,
+	}
+}
+
+// GetBudgetSpentAmount returns the total converted expense amount of the specified categories,
+// and returns false if any of the matched amounts cannot be converted to the default currency
+func GetBudgetSpentAmount(totalAmounts []*TransactionWithConvertedAmount, categoryIds map[int64]bool) (int64, bool) {
+	spentAmount := int64(0)
+	complete := true
+
+	for i := 0; i < len(totalAmounts); i++ {
+		totalAmount := totalAmounts[i]
+
+		if totalAmount.Type != TRANSACTION_DB_TYPE_EXPENSE || !categoryIds[totalAmount.CategoryId] {
+			continue
+		}
+
+		if !totalAmount.HasConvertedAmount {
+			complete = false
+			continue
+		}
+
+		spentAmount += totalAmount.ConvertedAmount
+	}
+
+	return spentAmount, complete
+}
+
+// GetBudgetRemainingAmountToRollover returns the amount which would be rolled over to the next period,
+// only unspent amount would be rolled over and overspending would not reduce the amount of the next period
+func GetBudgetRemainingAmountToRollover(totalAmount int64, spentAmount int64) int64 {
+	if totalAmount <= spentAmount {
+		return 0
+	}
+
+	return totalAmount - spentAmount
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestBudgetGetCategoryIds(t *testing.T) {
+	budget := &Budget{
+		CategoryIds: "1,2,3",
+	}
+
+	expectedValue := []int64{1, 2, 3}
+	assert.EqualValues(t, expectedValue, budget.GetCategoryIds())
+}
+
+func TestBudgetGetPeriodTimeRange(t *testing.T) {
+	fiscalYearStart, _ := core.NewFiscalYearStart(4, 1)
+	unixTime := time.Date(2024, time.May, 15, 10, 30, 0, 0, time.UTC).Unix()
+
+	testCases := []struct {
+		name          string
+		periodType    BudgetPeriodType
+		unixTime      int64
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{"weekly", BUDGET_PERIOD_TYPE_WEEKLY, unixTime, time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC), time.Date(2024, time.May, 20, 0, 0, 0, 0, time.UTC)},
+		{"monthly", BUDGET_PERIOD_TYPE_MONTHLY, unixTime, time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"yearly", BUDGET_PERIOD_TYPE_YEARLY, unixTime, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"fiscal year", BUDGET_PERIOD_TYPE_FISCAL_YEAR, unixTime, time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"fiscal year before start day", BUDGET_PERIOD_TYPE_FISCAL_YEAR, time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC).Unix(), time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		budget := &Budget{PeriodType: tc.periodType}
+		startUnixTime, endUnixTime, err := budget.GetPeriodTimeRange(tc.unixTime, core.WEEKDAY_MONDAY, fiscalYearStart, time.UTC)
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.expectedStart.Unix(), startUnixTime, tc.name)
+		assert.Equal(t, tc.expectedEnd.Unix(), endUnixTime, tc.name)
+	}
+}
+
+func TestBudgetGetPeriodTimeRange_InvalidPeriodType(t *testing.T) {
+	budget := &Budget{PeriodType: 0}
+	_, _, err := budget.GetPeriodTimeRange(time.Now().Unix(), core.WEEKDAY_SUNDAY, core.FISCAL_YEAR_START_DEFAULT, time.UTC)
+	assert.Equal(t, errs.ErrBudgetPeriodTypeInvalid, err)
+}
+
+func TestGetBudgetSpentAmount(t *testing.T) {
+	totalAmounts := []*TransactionWithConvertedAmount{
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1}, ConvertedAmount: 1000, HasConvertedAmount: true},
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 2}, ConvertedAmount: 500, HasConvertedAmount: true},
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 3}, ConvertedAmount: 700, HasConvertedAmount: true},
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_INCOME, CategoryId: 1}, ConvertedAmount: 300, HasConvertedAmount: true},
+	}
+
+	spentAmount, complete := GetBudgetSpentAmount(totalAmounts, map[int64]bool{1: true, 2: true})
+	assert.Equal(t, int64(1500), spentAmount)
+	assert.True(t, complete)
+
+	totalAmounts = append(totalAmounts, &TransactionWithConvertedAmount{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 2}, HasConvertedAmount: false})
+
+	spentAmount, complete = GetBudgetSpentAmount(totalAmounts, map[int64]bool{1: true, 2: true})
+	assert.Equal(t, int64(1500), spentAmount)
+	assert.False(t, complete)
+}
+
+func TestGetBudgetRemainingAmountToRollover(t *testing.T) {
+	assert.Equal(t, int64(300), GetBudgetRemainingAmountToRollover(1000, 700))
+	assert.Equal(t, int64(0), GetBudgetRemainingAmountToRollover(1000, 1000))
+	assert.Equal(t, int64(0), GetBudgetRemainingAmountToRollover(1000, 1200))
+}
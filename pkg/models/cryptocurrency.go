@@ -1,14 +1,18 @@
 package models
 
-// Cryptocurrency represents cryptocurrency data stored in database
+// Cryptocurrency represents cryptocurrency data in the watchlist of a user stored in database,
+// the cryptocurrencies whose uid is 0 are the global default watchlist managed by administrators
 type Cryptocurrency struct {
 	CryptocurrencyId int64  `xorm:"PK AUTOINCR"`
-	Symbol           string `xorm:"VARCHAR(20) NOT NULL UNIQUE"`
+	Uid              int64  `xorm:"INDEX(IDX_cryptocurrency_uid_deleted_symbol) NOT NULL DEFAULT 0"`
+	Deleted          bool   `xorm:"INDEX(IDX_cryptocurrency_uid_deleted_symbol) NOT NULL DEFAULT 0"`
+	Symbol           string `xorm:"INDEX(IDX_cryptocurrency_uid_deleted_symbol) VARCHAR(20) NOT NULL"`
 	Name             string `xorm:"VARCHAR(100) NOT NULL"`
 	IsHidden         bool   `xorm:"NOT NULL DEFAULT 0"`
 	DisplayOrder     int    `xorm:"NOT NULL DEFAULT 0"`
 	CreatedUnixTime  int64
 	UpdatedUnixTime  int64
+	DeletedUnixTime  int64
 }
 
 // CryptocurrencyInfoResponse represents a view-object of cryptocurrency
@@ -2,10 +2,10 @@ package models
 
 // LatestCryptocurrencyPriceResponse represents the response of latest cryptocurrency price
 type LatestCryptocurrencyPriceResponse struct {
-	DataSource   string                        `json:"dataSource"`
-	ReferenceUrl string                        `json:"referenceUrl"`
-	UpdateTime   int64                         `json:"updateTime"`
-	BaseCurrency string                        `json:"baseCurrency"`
+	DataSource   string                         `json:"dataSource"`
+	ReferenceUrl string                         `json:"referenceUrl"`
+	UpdateTime   int64                          `json:"updateTime"`
+	BaseCurrency string                         `json:"baseCurrency"`
 	Prices       LatestCryptocurrencyPriceSlice `json:"prices"`
 }
 
@@ -32,3 +32,61 @@ func (s LatestCryptocurrencyPriceSlice) Swap(i, j int) {
 func (s LatestCryptocurrencyPriceSlice) Less(i, j int) bool {
 	return s[i].Symbol < s[j].Symbol
 }
+
+// CryptocurrencyPriceHistory represents the daily price of a cryptocurrency stored in database
+type CryptocurrencyPriceHistory AssetPriceHistory
+
+// CryptocurrencyPriceHistoryRequest represents all parameters of cryptocurrency price history request
+type CryptocurrencyPriceHistoryRequest struct {
+	Symbol     string `form:"symbol" binding:"required,notBlank,max=20"`
+	Currency   string `form:"currency" binding:"max=10"`
+	DataSource string `form:"data_source" binding:"max=50"`
+	StartTime  int64  `form:"start_time" binding:"min=0"`
+	EndTime    int64  `form:"end_time" binding:"min=0"`
+}
+
+// CryptocurrencyPriceHistoryResponse represents a view-object of cryptocurrency price history
+type CryptocurrencyPriceHistoryResponse struct {
+	Symbol     string `json:"symbol"`
+	DataSource string `json:"dataSource"`
+	Currency   string `json:"currency"`
+	Date       int32  `json:"date"`
+	Price      string `json:"price"`
+}
+
+// ToCryptocurrencyPriceHistoryResponse returns a view-object according to database model
+func (h *CryptocurrencyPriceHistory) ToCryptocurrencyPriceHistoryResponse() *CryptocurrencyPriceHistoryResponse {
+	return &CryptocurrencyPriceHistoryResponse{
+		Symbol:     h.Symbol,
+		DataSource: h.DataSource,
+		Currency:   h.Currency,
+		Date:       h.PriceDate,
+		Price:      h.Price,
+	}
+}
+
+// CryptocurrencyPriceHistoryResponseSlice represents the slice of cryptocurrency price history
+type CryptocurrencyPriceHistoryResponseSlice []*CryptocurrencyPriceHistoryResponse
+
+// Len returns the length of the slice
+func (s CryptocurrencyPriceHistoryResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps the elements with indexes i and j
+func (s CryptocurrencyPriceHistoryResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less returns true if the element with index i should be sorted before the element with index j
+func (s CryptocurrencyPriceHistoryResponseSlice) Less(i, j int) bool {
+	if s[i].Date != s[j].Date {
+		return s[i].Date < s[j].Date
+	}
+
+	if s[i].DataSource != s[j].DataSource {
+		return s[i].DataSource < s[j].DataSource
+	}
+
+	return s[i].Currency < s[j].Currency
+}
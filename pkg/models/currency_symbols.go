@@ -187,3 +187,26 @@ var AllCryptocurrencySymbols = map[string]bool{
 	"XMR":   true, //Monero
 	"ETC":   true, //Ethereum Classic
 }
+
+// maxCurrencyFraction is the max fraction of amounts stored in database, larger fractions would cause int64 overflow
+// (with fraction 8, the max amount is about 92,233,720,368 units)
+const maxCurrencyFraction = 8
+
+var currencyFractions = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"BTC": 8, "ETH": 5, "BNB": 5, "SOL": 5, "ADA": 4, "XRP": 4, "DOT": 3, "DOGE": 2, "MATIC": 4, "USDT": 2, "USDC": 2, "DAI": 2, "LTC": 4, "BCH": 4, "LINK": 4, "XLM": 4, "UNI": 4, "ATOM": 4, "XMR": 4, "ETC": 4,
+}
+
+// GetCurrencyFraction returns the fraction digits of amounts in the specified currency, cryptocurrency or stock
+func GetCurrencyFraction(currency string) int {
+	if fraction, exists := currencyFractions[currency]; exists {
+		if fraction > maxCurrencyFraction {
+			return maxCurrencyFraction
+		}
+
+		return fraction
+	}
+
+	return 2
+}
@@ -18,6 +18,12 @@ type UserCustomExchangeRate struct {
 	UpdatedUnixTime int64
 }
 
+// LatestExchangeRateRequest represents all parameters of latest exchange rate request,
+// the user custom exchange rates effective at the specified time would be returned if the time is specified
+type LatestExchangeRateRequest struct {
+	Time int64 `form:"time" binding:"min=0"`
+}
+
 // UserCustomExchangeRateUpdateRequest represents all parameters of user custom exchange rate data updating request
 type UserCustomExchangeRateUpdateRequest struct {
 	Currency string `json:"currency" binding:"required,min=1,max=10,validCurrency"`
@@ -46,8 +52,9 @@ type LatestExchangeRateResponse struct {
 
 // LatestExchangeRate represents a data pair of currency and exchange rate
 type LatestExchangeRate struct {
-	Currency string `json:"currency"`
-	Rate     string `json:"rate"`
+	Currency   string `json:"currency"`
+	Rate       string `json:"rate"`
+	DataSource string `json:"dataSource,omitempty"`
 }
 
 // ToLatestExchangeRate returns a data pair of currency and exchange rate according to database model
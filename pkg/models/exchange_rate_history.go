@@ -0,0 +1,122 @@
+package models
+
+import "github.com/mayswind/ezbookkeeping/pkg/utils"
+
+// ExchangeRateHistory represents the daily exchange rate of a currency relative to the base currency of the data source stored in database,
+// the origin data source is the data source which actually provided the rate when the rates of multiple data sources are merged
+type ExchangeRateHistory struct {
+	DataSource       string `xorm:"PK VARCHAR(50) NOT NULL"`
+	Currency         string `xorm:"PK VARCHAR(10) NOT NULL"`
+	RateDate         int32  `xorm:"PK NOT NULL"`
+	OriginDataSource string `xorm:"VARCHAR(50)"`
+	BaseCurrency     string `xorm:"VARCHAR(10) NOT NULL"`
+	Rate             string `xorm:"VARCHAR(32) NOT NULL"`
+	CreatedUnixTime  int64
+	UpdatedUnixTime  int64
+}
+
+// ExchangeRateHistoryRequest represents all parameters of exchange rate history request
+type ExchangeRateHistoryRequest struct {
+	Currency   string `form:"currency" binding:"max=10"`
+	DataSource string `form:"data_source" binding:"max=50"`
+	StartTime  int64  `form:"start_time" binding:"min=0"`
+	EndTime    int64  `form:"end_time" binding:"min=0"`
+}
+
+// ExchangeRateHistoryResponse represents a view-object of exchange rate history
+type ExchangeRateHistoryResponse struct {
+	DataSource       string `json:"dataSource"`
+	OriginDataSource string `json:"originDataSource,omitempty"`
+	BaseCurrency     string `json:"baseCurrency"`
+	Currency         string `json:"currency"`
+	Date             int32  `json:"date"`
+	Rate             string `json:"rate"`
+}
+
+// ToExchangeRateHistoryResponse returns a view-object according to database model
+func (h *ExchangeRateHistory) ToExchangeRateHistoryResponse() *ExchangeRateHistoryResponse {
+	return &ExchangeRateHistoryResponse{
+		DataSource:       h.DataSource,
+		OriginDataSource: h.OriginDataSource,
+		BaseCurrency:     h.BaseCurrency,
+		Currency:         h.Currency,
+		Date:             h.RateDate,
+		Rate:             h.Rate,
+	}
+}
+
+// GetHistoricalExchangeRatesInBaseCurrency returns the historical exchange rates of the histories relative to the specified base currency,
+// the rate whose stored base currency is different is divided by the rate of the specified base currency at the same date relative to the same stored base currency,
+// and the rate which cannot be converted is skipped
+func GetHistoricalExchangeRatesInBaseCurrency(histories []*ExchangeRateHistory, baseCurrency string, baseCurrencyHistories []*ExchangeRateHistory) []*HistoricalExchangeRate {
+	baseCurrencyRates := make(map[string]map[int32]float64)
+
+	for i := 0; i < len(baseCurrencyHistories); i++ {
+		history := baseCurrencyHistories[i]
+		rate, err := utils.StringToFloat64(history.Rate)
+
+		if err != nil || rate <= 0 {
+			continue
+		}
+
+		if _, exists := baseCurrencyRates[history.BaseCurrency]; !exists {
+			baseCurrencyRates[history.BaseCurrency] = make(map[int32]float64)
+		}
+
+		baseCurrencyRates[history.BaseCurrency][history.RateDate] = rate
+	}
+
+	rates := make([]*HistoricalExchangeRate, 0, len(histories))
+
+	for i := 0; i < len(histories); i++ {
+		history := histories[i]
+		rate, err := utils.StringToFloat64(history.Rate)
+
+		if err != nil || rate <= 0 {
+			continue
+		}
+
+		if history.BaseCurrency != baseCurrency {
+			baseCurrencyRate, exists := baseCurrencyRates[history.BaseCurrency][history.RateDate]
+
+			if !exists {
+				continue
+			}
+
+			rate = rate / baseCurrencyRate
+		}
+
+		rates = append(rates, &HistoricalExchangeRate{
+			Rate:     rate,
+			RateDate: history.RateDate,
+		})
+	}
+
+	return rates
+}
+
+// ExchangeRateHistoryResponseSlice represents the slice of exchange rate history
+type ExchangeRateHistoryResponseSlice []*ExchangeRateHistoryResponse
+
+// Len returns the length of the slice
+func (s ExchangeRateHistoryResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps the elements with indexes i and j
+func (s ExchangeRateHistoryResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less returns true if the element with index i should be sorted before the element with index j
+func (s ExchangeRateHistoryResponseSlice) Less(i, j int) bool {
+	if s[i].Date != s[j].Date {
+		return s[i].Date < s[j].Date
+	}
+
+	if s[i].DataSource != s[j].DataSource {
+		return s[i].DataSource < s[j].DataSource
+	}
+
+	return s[i].Currency < s[j].Currency
+}
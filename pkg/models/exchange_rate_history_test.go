@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHistoricalExchangeRatesInBaseCurrency(t *testing.T) {
+	histories := []*ExchangeRateHistory{
+		{Currency: "CNY", RateDate: 20210401, BaseCurrency: "EUR", Rate: "7.7195"},
+		{Currency: "CNY", RateDate: 20210402, BaseCurrency: "USD", Rate: "6.5"},
+		{Currency: "CNY", RateDate: 20210403, BaseCurrency: "EUR", Rate: "7.8"},
+	}
+	baseCurrencyHistories := []*ExchangeRateHistory{
+		{Currency: "USD", RateDate: 20210401, BaseCurrency: "EUR", Rate: "1.25"},
+		{Currency: "USD", RateDate: 20210402, BaseCurrency: "USD", Rate: "1"},
+	}
+
+	actualRates := GetHistoricalExchangeRatesInBaseCurrency(histories, "USD", baseCurrencyHistories)
+	assert.Len(t, actualRates, 2)
+	assert.Equal(t, int32(20210401), actualRates[0].RateDate)
+	assert.InDelta(t, 6.1756, actualRates[0].Rate, 0.000001)
+	assert.Equal(t, int32(20210402), actualRates[1].RateDate)
+	assert.Equal(t, 6.5, actualRates[1].Rate)
+}
@@ -12,8 +12,8 @@ const (
 // ExternalDataSourceConfig represents external data source configuration stored in database
 type ExternalDataSourceConfig struct {
 	ConfigId        int64                  `xorm:"PK AUTOINCR"`
-	Type            ExternalDataSourceType `xorm:"NOT NULL UNIQUE"`
-	DataSource      string                 `xorm:"VARCHAR(50) NOT NULL"`
+	Type            ExternalDataSourceType `xorm:"UNIQUE(UQE_external_data_source_config_type_data_source) NOT NULL"`
+	DataSource      string                 `xorm:"VARCHAR(50) UNIQUE(UQE_external_data_source_config_type_data_source) NOT NULL"`
 	BaseCurrency    string                 `xorm:"VARCHAR(10)"`
 	ApiKey          string                 `xorm:"VARCHAR(255)"`
 	RequestTimeout  int                    `xorm:"INT"`
@@ -47,6 +47,30 @@ func (c *ExternalDataSourceConfig) ToExternalDataSourceConfigResponse() *Externa
 	}
 }
 
+// ExternalDataSourceConfigSummaryResponse represents a view-object of external data source configuration without the api key
+type ExternalDataSourceConfigSummaryResponse struct {
+	Type            ExternalDataSourceType `json:"type"`
+	DataSource      string                 `json:"dataSource"`
+	BaseCurrency    string                 `json:"baseCurrency"`
+	ApiKeySet       bool                   `json:"apiKeySet"`
+	RequestTimeout  int                    `json:"requestTimeout"`
+	Proxy           string                 `json:"proxy"`
+	UpdateFrequency string                 `json:"updateFrequency"`
+}
+
+// ToExternalDataSourceConfigSummaryResponse returns a view-object which only contains whether the api key is set according to database model
+func (c *ExternalDataSourceConfig) ToExternalDataSourceConfigSummaryResponse() *ExternalDataSourceConfigSummaryResponse {
+	return &ExternalDataSourceConfigSummaryResponse{
+		Type:            c.Type,
+		DataSource:      c.DataSource,
+		BaseCurrency:    c.BaseCurrency,
+		ApiKeySet:       c.ApiKey != "",
+		RequestTimeout:  c.RequestTimeout,
+		Proxy:           c.Proxy,
+		UpdateFrequency: c.UpdateFrequency,
+	}
+}
+
 // ExternalDataSourceConfigSaveRequest represents all parameters of external data source config saving request
 type ExternalDataSourceConfigSaveRequest struct {
 	Type            ExternalDataSourceType `json:"type" binding:"required"`
@@ -62,3 +86,8 @@ type ExternalDataSourceConfigSaveRequest struct {
 type ExternalDataSourceConfigGetRequest struct {
 	Type ExternalDataSourceType `form:"type" binding:"required"`
 }
+
+// ExternalDataSourceConfigDeleteRequest represents all parameters of external data source config deleting request
+type ExternalDataSourceConfigDeleteRequest struct {
+	DataSource string `json:"dataSource" binding:"required,notBlank,max=50"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalDataSourceConfigToExternalDataSourceConfigSummaryResponse(t *testing.T) {
+	config := &ExternalDataSourceConfig{
+		Type:       EXTERNAL_DATA_SOURCE_TYPE_STOCK,
+		DataSource: "alpha_vantage",
+		ApiKey:     "secret",
+	}
+
+	response := config.ToExternalDataSourceConfigSummaryResponse()
+	assert.Equal(t, "alpha_vantage", response.DataSource)
+	assert.True(t, response.ApiKeySet)
+
+	config.ApiKey = ""
+	response = config.ToExternalDataSourceConfigSummaryResponse()
+	assert.False(t, response.ApiKeySet)
+}
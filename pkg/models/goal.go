@@ -0,0 +1,264 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MaximumAccountsCountOfGoal is the maximum count of linked accounts of one savings goal
+const MaximumAccountsCountOfGoal = 10
+
+// GoalBalanceHistoryMonths is the count of recent months whose month-end balances are used to compute the contribution of savings goal
+const GoalBalanceHistoryMonths = 12
+
+// Goal represents a savings goal stored in database, the progress of the goal is the total balance of the linked accounts in the goal currency
+type Goal struct {
+	GoalId          int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_goal_uid_deleted_order) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_goal_uid_deleted_order) NOT NULL"`
+	Name            string `xorm:"VARCHAR(64) NOT NULL"`
+	AccountIds      string `xorm:"VARCHAR(255) NOT NULL"`
+	Currency        string `xorm:"VARCHAR(10) NOT NULL"`
+	TargetAmount    int64  `xorm:"NOT NULL"`
+	TargetUnixTime  int64  `xorm:"NOT NULL"`
+	DisplayOrder    int32  `xorm:"INDEX(IDX_goal_uid_deleted_order) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// GoalGetRequest represents all parameters of savings goal getting request
+type GoalGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// GoalCreateRequest represents all parameters of savings goal creation request
+type GoalCreateRequest struct {
+	Name         string   `json:"name" binding:"required,notBlank,max=64"`
+	AccountIds   []string `json:"accountIds" binding:"required,min=1"`
+	Currency     string   `json:"currency" binding:"required,min=1,max=10,validCurrency"`
+	TargetAmount int64    `json:"targetAmount" binding:"min=1,max=99999999999"`
+	TargetTime   int64    `json:"targetTime" binding:"min=0"`
+}
+
+// GoalModifyRequest represents all parameters of savings goal modification request
+type GoalModifyRequest struct {
+	Id           int64    `json:"id,string" binding:"required,min=1"`
+	Name         string   `json:"name" binding:"required,notBlank,max=64"`
+	AccountIds   []string `json:"accountIds" binding:"required,min=1"`
+	Currency     string   `json:"currency" binding:"required,min=1,max=10,validCurrency"`
+	TargetAmount int64    `json:"targetAmount" binding:"min=1,max=99999999999"`
+	TargetTime   int64    `json:"targetTime" binding:"min=0"`
+}
+
+// GoalDeleteRequest represents all parameters of savings goal deleting request
+type GoalDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// GoalInfoResponse represents a view-object of savings goal
+type GoalInfoResponse struct {
+	Id           int64    `json:"id,string"`
+	Name         string   `json:"name"`
+	AccountIds   []string `json:"accountIds"`
+	Currency     string   `json:"currency"`
+	TargetAmount int64    `json:"targetAmount"`
+	TargetTime   int64    `json:"targetTime,omitempty"`
+}
+
+// GoalBalanceHistoryItem represents the total balance of the linked accounts of savings goal at the end of a month
+type GoalBalanceHistoryItem struct {
+	Time   int64 `json:"time"`
+	Amount int64 `json:"amount"`
+}
+
+// GoalProgressResponse represents a view-object of savings goal progress
+type GoalProgressResponse struct {
+	Id                          int64                     `json:"id,string"`
+	Currency                    string                    `json:"currency"`
+	TargetAmount                int64                     `json:"targetAmount"`
+	TargetTime                  int64                     `json:"targetTime,omitempty"`
+	CurrentAmount               int64                     `json:"currentAmount"`
+	RemainingAmount             int64                     `json:"remainingAmount"`
+	Percentage                  float64                   `json:"percentage"`
+	Completed                   bool                      `json:"completed"`
+	AverageMonthlyContribution  int64                     `json:"averageMonthlyContribution"`
+	RequiredMonthlyContribution int64                     `json:"requiredMonthlyContribution,omitempty"`
+	ProjectedCompletionTime     int64                     `json:"projectedCompletionTime,omitempty"`
+	IncompleteAmount            bool                      `json:"incompleteAmount,omitempty"`
+	BalanceHistory              []*GoalBalanceHistoryItem `json:"balanceHistory"`
+}
+
+// GetAccountIds returns all linked account ids of the savings goal
+func (g *Goal) GetAccountIds() []int64 {
+	accountIds := make([]string, 0)
+
+	if g.AccountIds != "" {
+		accountIds = strings.Split(g.AccountIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(accountIds)
+
+	return result
+}
+
+// ToGoalInfoResponse returns a view-object according to database model
+func (g *Goal) ToGoalInfoResponse() *GoalInfoResponse {
+	return &GoalInfoResponse{
+		Id:           g.GoalId,
+		Name:         g.Name,
+		AccountIds:   utils.Int64ArrayToStringArray(g.GetAccountIds()),
+		Currency:     g.Currency,
+		TargetAmount: g.TargetAmount,
+		TargetTime:   g.TargetUnixTime,
+	}
+}
+
+// ToGoalProgressResponse returns a view-object of the savings goal progress according to the current amount and the month-end amounts of recent months,
+// the average monthly contribution is computed from the oldest month-end amount to the current amount
+func (g *Goal) ToGoalProgressResponse(currentAmount int64, balanceHistory []*GoalBalanceHistoryItem, nowUnixTime int64, timezone *time.Location) *GoalProgressResponse {
+	remainingAmount := g.TargetAmount - currentAmount
+
+	if remainingAmount < 0 {
+		remainingAmount = 0
+	}
+
+	progress := &GoalProgressResponse{
+		Id:              g.GoalId,
+		Currency:        g.Currency,
+		TargetAmount:    g.TargetAmount,
+		TargetTime:      g.TargetUnixTime,
+		CurrentAmount:   currentAmount,
+		RemainingAmount: remainingAmount,
+		Percentage:      math.Round(float64(currentAmount)/float64(g.TargetAmount)*10000) / 100,
+		Completed:       remainingAmount == 0,
+		BalanceHistory:  balanceHistory,
+	}
+
+	if len(balanceHistory) > 0 {
+		progress.AverageMonthlyContribution = (currentAmount - balanceHistory[0].Amount) / int64(len(balanceHistory))
+	}
+
+	if progress.Completed {
+		return progress
+	}
+
+	if g.TargetUnixTime > 0 {
+		progress.RequiredMonthlyContribution = GetGoalRequiredMonthlyContribution(remainingAmount, nowUnixTime, g.TargetUnixTime, timezone)
+	}
+
+	progress.ProjectedCompletionTime = GetGoalProjectedCompletionUnixTime(remainingAmount, progress.AverageMonthlyContribution, nowUnixTime, timezone)
+
+	return progress
+}
+
+// GetGoalBalanceHistoryStartTime returns the start time of the first month whose month-end balance is used to compute the contribution of savings goal
+func GetGoalBalanceHistoryStartTime(now time.Time, timezone *time.Location) time.Time {
+	now = now.In(timezone)
+	currentMonthStartTime := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, timezone)
+
+	return currentMonthStartTime.AddDate(0, -GoalBalanceHistoryMonths, 0)
+}
+
+// GetGoalRequiredMonthlyContribution returns the monthly contribution which is required to save the remaining amount before the target time,
+// the whole remaining amount is required in the current month if the target time is in the current month or has passed
+func GetGoalRequiredMonthlyContribution(remainingAmount int64, nowUnixTime int64, targetUnixTime int64, timezone *time.Location) int64 {
+	if remainingAmount <= 0 {
+		return 0
+	}
+
+	now := time.Unix(nowUnixTime, 0).In(timezone)
+	target := time.Unix(targetUnixTime, 0).In(timezone)
+	months := int64((target.Year()-now.Year())*12 + int(target.Month()) - int(now.Month()))
+
+	if months < 1 {
+		return remainingAmount
+	}
+
+	return (remainingAmount + months - 1) / months
+}
+
+// GetGoalProjectedCompletionUnixTime returns the projected unix time when the remaining amount would be saved at the average monthly contribution,
+// and returns 0 if the average monthly contribution is not positive
+func GetGoalProjectedCompletionUnixTime(remainingAmount int64, averageMonthlyContribution int64, nowUnixTime int64, timezone *time.Location) int64 {
+	if remainingAmount <= 0 {
+		return nowUnixTime
+	}
+
+	if averageMonthlyContribution <= 0 {
+		return 0
+	}
+
+	months := (remainingAmount + averageMonthlyContribution - 1) / averageMonthlyContribution
+
+	if months > math.MaxInt32 {
+		return 0
+	}
+
+	return time.Unix(nowUnixTime, 0).In(timezone).AddDate(0, int(months), 0).Unix()
+}
+
+// GetAccountBalancesAtDates returns the closing balances of the specified accounts at the end of each specified date (in yyyymmdd format),
+// the daily balances are grouped by date and only contain the dates which have transactions
+func GetAccountBalancesAtDates(dailyBalances map[int32][]*TransactionWithAccountBalance, accountIds map[int64]bool, dates []int32) []map[int64]int64 {
+	type accountDailyBalance struct {
+		yearMonthDay   int32
+		openingBalance int64
+		closingBalance int64
+	}
+
+	allAccountDailyBalances := make(map[int64][]*accountDailyBalance, len(accountIds))
+
+	for yearMonthDay, balances := range dailyBalances {
+		for i := 0; i < len(balances); i++ {
+			balance := balances[i]
+
+			if !accountIds[balance.AccountId] {
+				continue
+			}
+
+			allAccountDailyBalances[balance.AccountId] = append(allAccountDailyBalances[balance.AccountId], &accountDailyBalance{
+				yearMonthDay:   yearMonthDay,
+				openingBalance: balance.AccountOpeningBalance,
+				closingBalance: balance.AccountClosingBalance,
+			})
+		}
+	}
+
+	for _, accountDailyBalances := range allAccountDailyBalances {
+		sort.Slice(accountDailyBalances, func(i, j int) bool {
+			return accountDailyBalances[i].yearMonthDay < accountDailyBalances[j].yearMonthDay
+		})
+	}
+
+	result := make([]map[int64]int64, len(dates))
+
+	for i := 0; i < len(dates); i++ {
+		result[i] = make(map[int64]int64, len(accountIds))
+
+		for accountId := range accountIds {
+			accountDailyBalances := allAccountDailyBalances[accountId]
+
+			if len(accountDailyBalances) < 1 {
+				result[i][accountId] = 0
+				continue
+			}
+
+			index := sort.Search(len(accountDailyBalances), func(j int) bool {
+				return accountDailyBalances[j].yearMonthDay > dates[i]
+			})
+
+			if index > 0 {
+				result[i][accountId] = accountDailyBalances[index-1].closingBalance
+			} else {
+				result[i][accountId] = accountDailyBalances[0].openingBalance
+			}
+		}
+	}
+
+	return result
+}
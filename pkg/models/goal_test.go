@@ -0,0 +1,88 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoalGetAccountIds(t *testing.T) {
+	goal := &Goal{
+		AccountIds: "1,2,3",
+	}
+
+	expectedValue := []int64{1, 2, 3}
+	assert.EqualValues(t, expectedValue, goal.GetAccountIds())
+}
+
+func TestGetGoalRequiredMonthlyContribution(t *testing.T) {
+	now := time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC).Unix()
+
+	assert.Equal(t, int64(1000), GetGoalRequiredMonthlyContribution(12000, now, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC).Unix(), time.UTC))
+	assert.Equal(t, int64(3334), GetGoalRequiredMonthlyContribution(10000, now, time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC).Unix(), time.UTC))
+	assert.Equal(t, int64(5000), GetGoalRequiredMonthlyContribution(5000, now, time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC).Unix(), time.UTC))
+	assert.Equal(t, int64(5000), GetGoalRequiredMonthlyContribution(5000, now, time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC).Unix(), time.UTC))
+	assert.Equal(t, int64(0), GetGoalRequiredMonthlyContribution(0, now, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC).Unix(), time.UTC))
+}
+
+func TestGetGoalProjectedCompletionUnixTime(t *testing.T) {
+	now := time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC).Unix()
+
+	assert.Equal(t, time.Date(2025, time.June, 15, 0, 0, 0, 0, time.UTC).Unix(), GetGoalProjectedCompletionUnixTime(2500, 1000, now, time.UTC))
+	assert.Equal(t, int64(0), GetGoalProjectedCompletionUnixTime(2500, 0, now, time.UTC))
+	assert.Equal(t, int64(0), GetGoalProjectedCompletionUnixTime(2500, -100, now, time.UTC))
+	assert.Equal(t, now, GetGoalProjectedCompletionUnixTime(0, 1000, now, time.UTC))
+}
+
+func TestGoalToGoalProgressResponse(t *testing.T) {
+	now := time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC).Unix()
+	goal := &Goal{
+		GoalId:         1,
+		Currency:       "EUR",
+		TargetAmount:   1000000,
+		TargetUnixTime: time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+	balanceHistory := []*GoalBalanceHistoryItem{
+		{Amount: 200000},
+		{Amount: 250000},
+		{Amount: 300000},
+		{Amount: 350000},
+	}
+
+	progress := goal.ToGoalProgressResponse(400000, balanceHistory, now, time.UTC)
+	assert.Equal(t, int64(400000), progress.CurrentAmount)
+	assert.Equal(t, int64(600000), progress.RemainingAmount)
+	assert.Equal(t, 40.0, progress.Percentage)
+	assert.False(t, progress.Completed)
+	assert.Equal(t, int64(50000), progress.AverageMonthlyContribution)
+	assert.Equal(t, int64(22223), progress.RequiredMonthlyContribution)
+	assert.Equal(t, time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC).Unix(), progress.ProjectedCompletionTime)
+
+	progress = goal.ToGoalProgressResponse(1200000, balanceHistory, now, time.UTC)
+	assert.Equal(t, int64(0), progress.RemainingAmount)
+	assert.True(t, progress.Completed)
+	assert.Equal(t, int64(0), progress.RequiredMonthlyContribution)
+	assert.Equal(t, int64(0), progress.ProjectedCompletionTime)
+}
+
+func TestGetAccountBalancesAtDates(t *testing.T) {
+	dailyBalances := map[int32][]*TransactionWithAccountBalance{
+		20250110: {
+			{Transaction: &Transaction{AccountId: 1}, AccountOpeningBalance: 1000, AccountClosingBalance: 1500},
+			{Transaction: &Transaction{AccountId: 3}, AccountOpeningBalance: 0, AccountClosingBalance: 900},
+		},
+		20250205: {
+			{Transaction: &Transaction{AccountId: 1}, AccountOpeningBalance: 1500, AccountClosingBalance: 1800},
+		},
+		20250220: {
+			{Transaction: &Transaction{AccountId: 2}, AccountOpeningBalance: 0, AccountClosingBalance: 300},
+		},
+	}
+
+	balances := GetAccountBalancesAtDates(dailyBalances, map[int64]bool{1: true, 2: true, 4: true}, []int32{20241231, 20250131, 20250228})
+	assert.Equal(t, 3, len(balances))
+	assert.Equal(t, map[int64]int64{1: 1000, 2: 0, 4: 0}, balances[0])
+	assert.Equal(t, map[int64]int64{1: 1500, 2: 0, 4: 0}, balances[1])
+	assert.Equal(t, map[int64]int64{1: 1800, 2: 300, 4: 0}, balances[2])
+}
@@ -0,0 +1,51 @@
+package models
+
+// InvestmentValuationPoint represents the value of investment accounts at the end of a day (in yyyymmdd format)
+// and the net external cash flow into the accounts on that day, both are in the same currency
+type InvestmentValuationPoint struct {
+	Date     int32
+	Value    int64
+	CashFlow int64
+}
+
+// InvestmentPerformance represents the performance of investment accounts over a time range,
+// the returns are ratios (e.g. 0.05 means 5%) and the money-weighted return is annualized
+type InvestmentPerformance struct {
+	StartValue             int64
+	EndValue               int64
+	NetCashFlow            int64
+	TimeWeightedReturn     float64
+	HasTimeWeightedReturn  bool
+	MoneyWeightedReturn    float64
+	HasMoneyWeightedReturn bool
+}
+
+// InvestmentPerformanceRequest represents all parameters of investment performance request
+type InvestmentPerformanceRequest struct {
+	StartTime  int64  `form:"start_time" binding:"required,min=1"`
+	EndTime    int64  `form:"end_time" binding:"min=0"`
+	AccountIds string `form:"account_ids"`
+}
+
+// InvestmentPerformanceInfoResponse represents a view-object of investment performance
+type InvestmentPerformanceInfoResponse struct {
+	AccountId           int64  `json:"accountId,string,omitempty"`
+	StartValue          int64  `json:"startValue"`
+	EndValue            int64  `json:"endValue"`
+	NetCashFlow         int64  `json:"netCashFlow"`
+	Gain                int64  `json:"gain"`
+	TimeWeightedReturn  string `json:"timeWeightedReturn,omitempty"`
+	MoneyWeightedReturn string `json:"moneyWeightedReturn,omitempty"`
+	HasValue            bool   `json:"hasValue"`
+	IncompleteValue     bool   `json:"incompleteValue,omitempty"`
+}
+
+// InvestmentPerformanceResponse represents a view-object of the performances of investment accounts, account groups and the whole portfolio
+type InvestmentPerformanceResponse struct {
+	Currency  string                               `json:"currency"`
+	StartTime int64                                `json:"startTime"`
+	EndTime   int64                                `json:"endTime"`
+	Portfolio *InvestmentPerformanceInfoResponse   `json:"portfolio"`
+	Groups    []*InvestmentPerformanceInfoResponse `json:"groups"`
+	Accounts  []*InvestmentPerformanceInfoResponse `json:"accounts"`
+}
@@ -0,0 +1,341 @@
+package models
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+// InvestmentTradeType represents investment trade type
+type InvestmentTradeType byte
+
+// Investment trade types
+const (
+	INVESTMENT_TRADE_TYPE_BUY      InvestmentTradeType = 1
+	INVESTMENT_TRADE_TYPE_SELL     InvestmentTradeType = 2
+	INVESTMENT_TRADE_TYPE_DIVIDEND InvestmentTradeType = 3
+	INVESTMENT_TRADE_TYPE_SPLIT    InvestmentTradeType = 4
+)
+
+// String returns a textual representation of the investment trade type
+func (t InvestmentTradeType) String() string {
+	switch t {
+	case INVESTMENT_TRADE_TYPE_BUY:
+		return "Buy"
+	case INVESTMENT_TRADE_TYPE_SELL:
+		return "Sell"
+	case INVESTMENT_TRADE_TYPE_DIVIDEND:
+		return "Dividend"
+	case INVESTMENT_TRADE_TYPE_SPLIT:
+		return "Split"
+	default:
+		return "Invalid"
+	}
+}
+
+// InvestmentLotMatchingMethod represents the method of matching sells against open lots
+type InvestmentLotMatchingMethod byte
+
+// Investment lot matching methods
+const (
+	INVESTMENT_LOT_MATCHING_METHOD_FIFO         InvestmentLotMatchingMethod = 1
+	INVESTMENT_LOT_MATCHING_METHOD_LIFO         InvestmentLotMatchingMethod = 2
+	INVESTMENT_LOT_MATCHING_METHOD_AVERAGE_COST InvestmentLotMatchingMethod = 3
+)
+
+// AllInvestmentLotMatchingMethods represents all the supported lot matching methods
+var AllInvestmentLotMatchingMethods = []InvestmentLotMatchingMethod{
+	INVESTMENT_LOT_MATCHING_METHOD_FIFO,
+	INVESTMENT_LOT_MATCHING_METHOD_LIFO,
+	INVESTMENT_LOT_MATCHING_METHOD_AVERAGE_COST,
+}
+
+// ParseInvestmentLotMatchingMethod returns the lot matching method according to the textual representation
+func ParseInvestmentLotMatchingMethod(method string) (InvestmentLotMatchingMethod, error) {
+	switch method {
+	case "", "fifo":
+		return INVESTMENT_LOT_MATCHING_METHOD_FIFO, nil
+	case "lifo":
+		return INVESTMENT_LOT_MATCHING_METHOD_LIFO, nil
+	case "average":
+		return INVESTMENT_LOT_MATCHING_METHOD_AVERAGE_COST, nil
+	default:
+		return 0, errs.ErrInvestmentLotMatchingMethodInvalid
+	}
+}
+
+// InvestmentHoldingPeriod represents the holding period of a realized gain
+type InvestmentHoldingPeriod byte
+
+// Investment holding periods
+const (
+	INVESTMENT_HOLDING_PERIOD_SHORT_TERM InvestmentHoldingPeriod = 1
+	INVESTMENT_HOLDING_PERIOD_LONG_TERM  InvestmentHoldingPeriod = 2
+)
+
+// String returns a textual representation of the investment holding period
+func (p InvestmentHoldingPeriod) String() string {
+	switch p {
+	case INVESTMENT_HOLDING_PERIOD_SHORT_TERM:
+		return "Short-term"
+	case INVESTMENT_HOLDING_PERIOD_LONG_TERM:
+		return "Long-term"
+	default:
+		return "Invalid"
+	}
+}
+
+// InvestmentTrade represents an investment trade stored in database
+// The quantity uses the same unit as the account balance, and the amount and fee use the minor unit of the trade currency
+type InvestmentTrade struct {
+	TradeId           int64               `xorm:"PK"`
+	Uid               int64               `xorm:"INDEX(IDX_investment_trade_uid_deleted_account_id_time) NOT NULL"`
+	Deleted           bool                `xorm:"INDEX(IDX_investment_trade_uid_deleted_account_id_time) NOT NULL"`
+	AccountId         int64               `xorm:"INDEX(IDX_investment_trade_uid_deleted_account_id_time) NOT NULL"`
+	TradeTime         int64               `xorm:"INDEX(IDX_investment_trade_uid_deleted_account_id_time) NOT NULL"`
+	Type              InvestmentTradeType `xorm:"NOT NULL"`
+	Symbol            string              `xorm:"VARCHAR(20) NOT NULL"`
+	Quantity          int64               `xorm:"NOT NULL"`
+	Amount            int64               `xorm:"NOT NULL"`
+	Fee               int64               `xorm:"NOT NULL"`
+	Currency          string              `xorm:"VARCHAR(10) NOT NULL"`
+	SplitNumerator    int32               `xorm:"NOT NULL"`
+	SplitDenominator  int32               `xorm:"NOT NULL"`
+	TransactionId     int64               `xorm:"NOT NULL"`
+	CorporateActionId int64               `xorm:"NOT NULL DEFAULT 0"`
+	Comment           string              `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime   int64
+	UpdatedUnixTime   int64
+	DeletedUnixTime   int64
+}
+
+// InvestmentLot represents an open lot of an investment account
+type InvestmentLot struct {
+	TradeId      int64
+	AccountId    int64
+	Symbol       string
+	Currency     string
+	AcquiredTime int64
+	Quantity     int64
+	Cost         int64
+}
+
+// InvestmentLotMatch represents a part of a sell matched against an open lot
+type InvestmentLotMatch struct {
+	SellTradeId  int64
+	BuyTradeId   int64
+	AccountId    int64
+	Symbol       string
+	Currency     string
+	AcquiredTime int64
+	SoldTime     int64
+	Quantity     int64
+	CostBasis    int64
+	Proceeds     int64
+}
+
+// InvestmentRealizedGain represents a realized gain of a lot match with its holding period
+type InvestmentRealizedGain struct {
+	*InvestmentLotMatch
+	HoldingPeriod InvestmentHoldingPeriod
+}
+
+// InvestmentTradeListRequest represents all parameters of investment trade listing request
+type InvestmentTradeListRequest struct {
+	AccountId int64 `form:"account_id,string" binding:"required,min=1"`
+}
+
+// InvestmentTradeCreateRequest represents all parameters of investment trade creation request
+type InvestmentTradeCreateRequest struct {
+	AccountId        int64               `json:"accountId,string" binding:"required,min=1"`
+	Type             InvestmentTradeType `json:"type" binding:"required"`
+	Time             int64               `json:"time" binding:"required,min=1"`
+	Quantity         int64               `json:"quantity" binding:"min=0"`
+	Amount           int64               `json:"amount" binding:"min=0"`
+	Fee              int64               `json:"fee" binding:"min=0"`
+	Currency         string              `json:"currency" binding:"omitempty,max=10,validCurrency"`
+	SplitNumerator   int32               `json:"splitNumerator" binding:"min=0"`
+	SplitDenominator int32               `json:"splitDenominator" binding:"min=0"`
+	TransactionId    int64               `json:"transactionId,string" binding:"min=0"`
+	Comment          string              `json:"comment" binding:"max=255"`
+}
+
+// InvestmentTradeDeleteRequest represents all parameters of investment trade deleting request
+type InvestmentTradeDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// InvestmentHoldingListRequest represents all parameters of investment holding listing request
+type InvestmentHoldingListRequest struct {
+	AccountIds  string `form:"account_ids"`
+	LotMatching string `form:"lot_matching"`
+}
+
+// InvestmentRealizedGainListRequest represents all parameters of investment realized gain listing request
+type InvestmentRealizedGainListRequest struct {
+	FiscalYear  int32  `form:"fiscal_year" binding:"required,min=1900,max=9999"`
+	AccountIds  string `form:"account_ids"`
+	LotMatching string `form:"lot_matching"`
+}
+
+// InvestmentTradeInfoResponse represents a view-object of investment trade
+type InvestmentTradeInfoResponse struct {
+	Id                int64               `json:"id,string"`
+	AccountId         int64               `json:"accountId,string"`
+	Type              InvestmentTradeType `json:"type"`
+	Time              int64               `json:"time"`
+	Symbol            string              `json:"symbol"`
+	Quantity          int64               `json:"quantity"`
+	Amount            int64               `json:"amount"`
+	Fee               int64               `json:"fee"`
+	Currency          string              `json:"currency"`
+	SplitNumerator    int32               `json:"splitNumerator,omitempty"`
+	SplitDenominator  int32               `json:"splitDenominator,omitempty"`
+	TransactionId     int64               `json:"transactionId,string,omitempty"`
+	CorporateActionId int64               `json:"corporateActionId,string,omitempty"`
+	Comment           string              `json:"comment"`
+}
+
+// InvestmentLotInfoResponse represents a view-object of investment open lot
+type InvestmentLotInfoResponse struct {
+	TradeId      int64  `json:"tradeId,string"`
+	AcquiredTime int64  `json:"acquiredTime"`
+	Quantity     int64  `json:"quantity"`
+	Cost         int64  `json:"cost"`
+	Currency     string `json:"currency"`
+}
+
+// InvestmentHoldingInfoResponse represents a view-object of investment holding
+type InvestmentHoldingInfoResponse struct {
+	AccountId               int64                        `json:"accountId,string"`
+	Symbol                  string                       `json:"symbol"`
+	Quantity                int64                        `json:"quantity"`
+	CostCurrency            string                       `json:"costCurrency"`
+	TotalCost               int64                        `json:"totalCost"`
+	AverageCost             string                       `json:"averageCost"`
+	MarketPrice             string                       `json:"marketPrice,omitempty"`
+	MarketPriceCurrency     string                       `json:"marketPriceCurrency,omitempty"`
+	MarketValue             int64                        `json:"marketValue"`
+	UnrealizedProfitAndLoss int64                        `json:"unrealizedProfitAndLoss"`
+	RealizedProfitAndLoss   int64                        `json:"realizedProfitAndLoss"`
+	TotalDividends          int64                        `json:"totalDividends"`
+	HasMarketPrice          bool                         `json:"hasMarketPrice"`
+	Lots                    []*InvestmentLotInfoResponse `json:"lots"`
+}
+
+// InvestmentRealizedGainInfoResponse represents a view-object of investment realized gain
+type InvestmentRealizedGainInfoResponse struct {
+	AccountId     int64                   `json:"accountId,string"`
+	Symbol        string                  `json:"symbol"`
+	Currency      string                  `json:"currency"`
+	BuyTradeId    int64                   `json:"buyTradeId,string"`
+	SellTradeId   int64                   `json:"sellTradeId,string"`
+	AcquiredTime  int64                   `json:"acquiredTime"`
+	SoldTime      int64                   `json:"soldTime"`
+	Quantity      int64                   `json:"quantity"`
+	CostBasis     int64                   `json:"costBasis"`
+	Proceeds      int64                   `json:"proceeds"`
+	Gain          int64                   `json:"gain"`
+	HoldingPeriod InvestmentHoldingPeriod `json:"holdingPeriod"`
+}
+
+// InvestmentRealizedGainSummaryResponse represents a view-object of investment realized gain summary in one currency
+type InvestmentRealizedGainSummaryResponse struct {
+	Currency       string `json:"currency"`
+	ShortTermGain  int64  `json:"shortTermGain"`
+	LongTermGain   int64  `json:"longTermGain"`
+	TotalProceeds  int64  `json:"totalProceeds"`
+	TotalCostBasis int64  `json:"totalCostBasis"`
+	TotalGain      int64  `json:"totalGain"`
+}
+
+// InvestmentRealizedGainReportResponse represents a view-object of investment realized gain report of a fiscal year
+type InvestmentRealizedGainReportResponse struct {
+	FiscalYear int32                                    `json:"fiscalYear"`
+	StartTime  int64                                    `json:"startTime"`
+	EndTime    int64                                    `json:"endTime"`
+	Gains      []*InvestmentRealizedGainInfoResponse    `json:"gains"`
+	Summaries  []*InvestmentRealizedGainSummaryResponse `json:"summaries"`
+}
+
+// ToInvestmentTradeInfoResponse returns a view-object according to database model
+func (t *InvestmentTrade) ToInvestmentTradeInfoResponse() *InvestmentTradeInfoResponse {
+	return &InvestmentTradeInfoResponse{
+		Id:                t.TradeId,
+		AccountId:         t.AccountId,
+		Type:              t.Type,
+		Time:              t.TradeTime,
+		Symbol:            t.Symbol,
+		Quantity:          t.Quantity,
+		Amount:            t.Amount,
+		Fee:               t.Fee,
+		Currency:          t.Currency,
+		SplitNumerator:    t.SplitNumerator,
+		SplitDenominator:  t.SplitDenominator,
+		TransactionId:     t.TransactionId,
+		CorporateActionId: t.CorporateActionId,
+		Comment:           t.Comment,
+	}
+}
+
+// ToInvestmentLotInfoResponse returns a view-object according to open lot
+func (l *InvestmentLot) ToInvestmentLotInfoResponse() *InvestmentLotInfoResponse {
+	return &InvestmentLotInfoResponse{
+		TradeId:      l.TradeId,
+		AcquiredTime: l.AcquiredTime,
+		Quantity:     l.Quantity,
+		Cost:         l.Cost,
+		Currency:     l.Currency,
+	}
+}
+
+// InvestmentTradeInfoResponseSlice represents the slice data structure of InvestmentTradeInfoResponse
+type InvestmentTradeInfoResponseSlice []*InvestmentTradeInfoResponse
+
+// Len returns the count of items
+func (s InvestmentTradeInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s InvestmentTradeInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s InvestmentTradeInfoResponseSlice) Less(i, j int) bool {
+	if s[i].Time != s[j].Time {
+		return s[i].Time > s[j].Time
+	}
+
+	return s[i].Id > s[j].Id
+}
+
+// InvestmentPosition represents the calculated position of an investment account
+type InvestmentPosition struct {
+	AccountId             int64
+	Symbol                string
+	Currency              string
+	Quantity              int64
+	TotalCost             int64
+	TotalDividends        int64
+	RealizedProfitAndLoss int64
+	OpenLots              []*InvestmentLot
+	Matches               []*InvestmentLotMatch
+}
+
+// ToInvestmentRealizedGainInfoResponse returns a view-object according to realized gain
+func (g *InvestmentRealizedGain) ToInvestmentRealizedGainInfoResponse() *InvestmentRealizedGainInfoResponse {
+	return &InvestmentRealizedGainInfoResponse{
+		AccountId:     g.AccountId,
+		Symbol:        g.Symbol,
+		Currency:      g.Currency,
+		BuyTradeId:    g.BuyTradeId,
+		SellTradeId:   g.SellTradeId,
+		AcquiredTime:  g.AcquiredTime,
+		SoldTime:      g.SoldTime,
+		Quantity:      g.Quantity,
+		CostBasis:     g.CostBasis,
+		Proceeds:      g.Proceeds,
+		Gain:          g.Proceeds - g.CostBasis,
+		HoldingPeriod: g.HoldingPeriod,
+	}
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"math"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// PriceAlertType represents price alert type
+type PriceAlertType byte
+
+// Price alert types
+const (
+	PRICE_ALERT_TYPE_ABOVE             PriceAlertType = 1
+	PRICE_ALERT_TYPE_BELOW             PriceAlertType = 2
+	PRICE_ALERT_TYPE_PERCENTAGE_CHANGE PriceAlertType = 3
+)
+
+// String returns a textual representation of the price alert type
+func (t PriceAlertType) String() string {
+	switch t {
+	case PRICE_ALERT_TYPE_ABOVE:
+		return "Above"
+	case PRICE_ALERT_TYPE_BELOW:
+		return "Below"
+	case PRICE_ALERT_TYPE_PERCENTAGE_CHANGE:
+		return "Percentage Change"
+	default:
+		return "Invalid"
+	}
+}
+
+// PriceAlert represents a price alert rule of a stock or a cryptocurrency stored in database,
+// the threshold is a price in the currency of the alert for above / below alerts, or a percentage of the price change in the period for percentage change alerts.
+// The alert would be notified once when its condition becomes true, and would be rearmed after the condition becomes false
+type PriceAlert struct {
+	AlertId               int64            `xorm:"PK"`
+	Uid                   int64            `xorm:"INDEX(IDX_price_alert_uid_deleted) NOT NULL"`
+	Deleted               bool             `xorm:"INDEX(IDX_price_alert_uid_deleted) INDEX(IDX_price_alert_deleted_asset_type_symbol) NOT NULL"`
+	AssetType             AccountAssetType `xorm:"INDEX(IDX_price_alert_deleted_asset_type_symbol) NOT NULL"`
+	Symbol                string           `xorm:"INDEX(IDX_price_alert_deleted_asset_type_symbol) VARCHAR(20) NOT NULL"`
+	Currency              string           `xorm:"VARCHAR(10) NOT NULL"`
+	Type                  PriceAlertType   `xorm:"NOT NULL"`
+	Threshold             string           `xorm:"VARCHAR(32) NOT NULL"`
+	PeriodDays            int32            `xorm:"NOT NULL"`
+	Triggered             bool             `xorm:"NOT NULL"`
+	LastTriggeredPrice    string           `xorm:"VARCHAR(32)"`
+	LastTriggeredUnixTime int64
+	CreatedUnixTime       int64
+	UpdatedUnixTime       int64
+	DeletedUnixTime       int64
+}
+
+// PriceAlertCreateRequest represents all parameters of price alert creation request
+type PriceAlertCreateRequest struct {
+	AssetType  AccountAssetType `json:"assetType" binding:"required"`
+	Symbol     string           `json:"symbol" binding:"required,notBlank,max=20"`
+	Currency   string           `json:"currency" binding:"required,max=10,validCurrency"`
+	Type       PriceAlertType   `json:"type" binding:"required"`
+	Threshold  string           `json:"threshold" binding:"required,notBlank,max=32"`
+	PeriodDays int32            `json:"periodDays" binding:"min=0,max=3650"`
+}
+
+// PriceAlertDeleteRequest represents all parameters of price alert deleting request
+type PriceAlertDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// PriceAlertInfoResponse represents a view-object of price alert
+type PriceAlertInfoResponse struct {
+	Id                    int64            `json:"id,string"`
+	AssetType             AccountAssetType `json:"assetType"`
+	Symbol                string           `json:"symbol"`
+	Currency              string           `json:"currency"`
+	Type                  PriceAlertType   `json:"type"`
+	Threshold             string           `json:"threshold"`
+	PeriodDays            int32            `json:"periodDays,omitempty"`
+	Triggered             bool             `json:"triggered"`
+	LastTriggeredPrice    string           `json:"lastTriggeredPrice,omitempty"`
+	LastTriggeredUnixTime int64            `json:"lastTriggeredTime,omitempty"`
+}
+
+// IsConditionMet returns whether the condition of the price alert is met by the specified price,
+// the base price is the price at the beginning of the period and is only used by percentage change alerts
+func (a *PriceAlert) IsConditionMet(price float64, basePrice float64) bool {
+	threshold, err := utils.StringToFloat64(a.Threshold)
+
+	if err != nil || price <= 0 {
+		return false
+	}
+
+	switch a.Type {
+	case PRICE_ALERT_TYPE_ABOVE:
+		return price >= threshold
+	case PRICE_ALERT_TYPE_BELOW:
+		return price <= threshold
+	case PRICE_ALERT_TYPE_PERCENTAGE_CHANGE:
+		if basePrice <= 0 {
+			return false
+		}
+
+		return math.Abs(GetPriceChangePercentage(price, basePrice)) >= threshold
+	default:
+		return false
+	}
+}
+
+// GetPriceChangePercentage returns the percentage of price change relative to the base price
+func GetPriceChangePercentage(price float64, basePrice float64) float64 {
+	return (price - basePrice) / basePrice * 100
+}
+
+// ToPriceAlertInfoResponse returns a view-object according to database model
+func (a *PriceAlert) ToPriceAlertInfoResponse() *PriceAlertInfoResponse {
+	return &PriceAlertInfoResponse{
+		Id:                    a.AlertId,
+		AssetType:             a.AssetType,
+		Symbol:                a.Symbol,
+		Currency:              a.Currency,
+		Type:                  a.Type,
+		Threshold:             a.Threshold,
+		PeriodDays:            a.PeriodDays,
+		Triggered:             a.Triggered,
+		LastTriggeredPrice:    a.LastTriggeredPrice,
+		LastTriggeredUnixTime: a.LastTriggeredUnixTime,
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceAlertIsConditionMet_Above(t *testing.T) {
+	alert := &PriceAlert{
+		Type:      PRICE_ALERT_TYPE_ABOVE,
+		Threshold: "200.5",
+	}
+
+	assert.True(t, alert.IsConditionMet(200.5, 0))
+	assert.True(t, alert.IsConditionMet(210, 0))
+	assert.False(t, alert.IsConditionMet(200.49, 0))
+	assert.False(t, alert.IsConditionMet(0, 0))
+}
+
+func TestPriceAlertIsConditionMet_Below(t *testing.T) {
+	alert := &PriceAlert{
+		Type:      PRICE_ALERT_TYPE_BELOW,
+		Threshold: "100",
+	}
+
+	assert.True(t, alert.IsConditionMet(100, 0))
+	assert.True(t, alert.IsConditionMet(99.9, 0))
+	assert.False(t, alert.IsConditionMet(100.1, 0))
+	assert.False(t, alert.IsConditionMet(0, 0))
+}
+
+func TestPriceAlertIsConditionMet_PercentageChange(t *testing.T) {
+	alert := &PriceAlert{
+		Type:       PRICE_ALERT_TYPE_PERCENTAGE_CHANGE,
+		Threshold:  "10",
+		PeriodDays: 7,
+	}
+
+	assert.True(t, alert.IsConditionMet(110, 100))
+	assert.True(t, alert.IsConditionMet(90, 100))
+	assert.False(t, alert.IsConditionMet(109, 100))
+	assert.False(t, alert.IsConditionMet(91, 100))
+	assert.False(t, alert.IsConditionMet(200, 0))
+}
+
+func TestPriceAlertIsConditionMet_InvalidThreshold(t *testing.T) {
+	alert := &PriceAlert{
+		Type:      PRICE_ALERT_TYPE_ABOVE,
+		Threshold: "abc",
+	}
+
+	assert.False(t, alert.IsConditionMet(100, 0))
+}
+
+func TestGetPriceChangePercentage(t *testing.T) {
+	assert.InDelta(t, 12.5, GetPriceChangePercentage(112.5, 100), 1e-9)
+	assert.InDelta(t, -20, GetPriceChangePercentage(40, 50), 1e-9)
+}
@@ -1,15 +1,19 @@
 package models
 
-// Stock represents stock data stored in database
+// Stock represents stock data in the watchlist of a user stored in database,
+// the stocks whose uid is 0 are the global default watchlist managed by administrators
 type Stock struct {
 	StockId         int64  `xorm:"PK AUTOINCR"`
-	Symbol          string `xorm:"VARCHAR(20) NOT NULL UNIQUE"`
+	Uid             int64  `xorm:"INDEX(IDX_stock_uid_deleted_symbol) NOT NULL DEFAULT 0"`
+	Deleted         bool   `xorm:"INDEX(IDX_stock_uid_deleted_symbol) NOT NULL DEFAULT 0"`
+	Symbol          string `xorm:"INDEX(IDX_stock_uid_deleted_symbol) VARCHAR(20) NOT NULL"`
 	Name            string `xorm:"VARCHAR(100) NOT NULL"`
 	Market          string `xorm:"VARCHAR(20)"`
 	IsHidden        bool   `xorm:"NOT NULL DEFAULT 0"`
 	DisplayOrder    int    `xorm:"NOT NULL DEFAULT 0"`
 	CreatedUnixTime int64
 	UpdatedUnixTime int64
+	DeletedUnixTime int64
 }
 
 // StockInfoResponse represents a view-object of stock
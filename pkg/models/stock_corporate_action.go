@@ -0,0 +1,166 @@
+package models
+
+import (
+	"math/big"
+	"strings"
+)
+
+const stockAdjustedPricePrecision = 8
+
+// StockCorporateActionType represents stock corporate action type
+type StockCorporateActionType byte
+
+// Stock corporate action types
+const (
+	STOCK_CORPORATE_ACTION_TYPE_SPLIT         StockCorporateActionType = 1
+	STOCK_CORPORATE_ACTION_TYPE_REVERSE_SPLIT StockCorporateActionType = 2
+	STOCK_CORPORATE_ACTION_TYPE_SYMBOL_CHANGE StockCorporateActionType = 3
+)
+
+// String returns a textual representation of the stock corporate action type
+func (t StockCorporateActionType) String() string {
+	switch t {
+	case STOCK_CORPORATE_ACTION_TYPE_SPLIT:
+		return "Split"
+	case STOCK_CORPORATE_ACTION_TYPE_REVERSE_SPLIT:
+		return "Reverse Split"
+	case STOCK_CORPORATE_ACTION_TYPE_SYMBOL_CHANGE:
+		return "Symbol Change"
+	default:
+		return "Invalid"
+	}
+}
+
+// StockCorporateAction represents a corporate action of a stock stored in database,
+// the ratio means the holders would get ratio numerator shares for every ratio denominator shares they hold
+type StockCorporateAction struct {
+	ActionId         int64                    `xorm:"PK AUTOINCR"`
+	Symbol           string                   `xorm:"VARCHAR(20) INDEX NOT NULL"`
+	Type             StockCorporateActionType `xorm:"NOT NULL"`
+	NewSymbol        string                   `xorm:"VARCHAR(20) NOT NULL"`
+	RatioNumerator   int32                    `xorm:"NOT NULL"`
+	RatioDenominator int32                    `xorm:"NOT NULL"`
+	EffectiveTime    int64                    `xorm:"NOT NULL"`
+	EffectiveDate    int32                    `xorm:"NOT NULL"`
+	Comment          string                   `xorm:"VARCHAR(255) NOT NULL"`
+	Applied          bool                     `xorm:"NOT NULL"`
+	CreatedUnixTime  int64
+	UpdatedUnixTime  int64
+}
+
+// StockCorporateActionAppliedRecord represents the marker of a stock corporate action which has been applied to the user data in one database
+type StockCorporateActionAppliedRecord struct {
+	ActionId        int64 `xorm:"PK"`
+	CreatedUnixTime int64
+}
+
+// StockCorporateActionListRequest represents all parameters of stock corporate action listing request
+type StockCorporateActionListRequest struct {
+	Symbol string `form:"symbol" binding:"max=20"`
+}
+
+// StockCorporateActionCreateRequest represents all parameters of stock corporate action creation request
+type StockCorporateActionCreateRequest struct {
+	Symbol           string                   `json:"symbol" binding:"required,notBlank,max=20"`
+	Type             StockCorporateActionType `json:"type" binding:"required"`
+	NewSymbol        string                   `json:"newSymbol" binding:"max=20"`
+	RatioNumerator   int32                    `json:"ratioNumerator" binding:"min=0"`
+	RatioDenominator int32                    `json:"ratioDenominator" binding:"min=0"`
+	EffectiveTime    int64                    `json:"effectiveTime" binding:"required,min=1"`
+	Comment          string                   `json:"comment" binding:"max=255"`
+}
+
+// StockCorporateActionApplyRequest represents all parameters of stock corporate action applying request
+type StockCorporateActionApplyRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// StockCorporateActionDeleteRequest represents all parameters of stock corporate action deleting request
+type StockCorporateActionDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// StockCorporateActionInfoResponse represents a view-object of stock corporate action
+type StockCorporateActionInfoResponse struct {
+	Id               int64                    `json:"id,string"`
+	Symbol           string                   `json:"symbol"`
+	Type             StockCorporateActionType `json:"type"`
+	NewSymbol        string                   `json:"newSymbol,omitempty"`
+	RatioNumerator   int32                    `json:"ratioNumerator,omitempty"`
+	RatioDenominator int32                    `json:"ratioDenominator,omitempty"`
+	EffectiveTime    int64                    `json:"effectiveTime"`
+	Comment          string                   `json:"comment"`
+	Applied          bool                     `json:"applied"`
+}
+
+// IsSplit returns whether the corporate action changes the quantity of shares
+func (a *StockCorporateAction) IsSplit() bool {
+	return a.Type == STOCK_CORPORATE_ACTION_TYPE_SPLIT || a.Type == STOCK_CORPORATE_ACTION_TYPE_REVERSE_SPLIT
+}
+
+// GetAdjustedPrice returns the price before the effective date adjusted by the split ratio,
+// or returns the reverted price if revert is true
+func (a *StockCorporateAction) GetAdjustedPrice(price string, revert bool) (string, bool) {
+	if !a.IsSplit() || a.RatioNumerator <= 0 || a.RatioDenominator <= 0 {
+		return price, false
+	}
+
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(price))
+
+	if !ok {
+		return price, false
+	}
+
+	ratio := big.NewRat(int64(a.RatioDenominator), int64(a.RatioNumerator))
+
+	if revert {
+		ratio.Inv(ratio)
+	}
+
+	value.Mul(value, ratio)
+
+	adjustedPrice := value.FloatString(stockAdjustedPricePrecision)
+
+	if strings.Contains(adjustedPrice, ".") {
+		adjustedPrice = strings.TrimRight(strings.TrimRight(adjustedPrice, "0"), ".")
+	}
+
+	return adjustedPrice, true
+}
+
+// ToStockCorporateActionInfoResponse returns a view-object according to database model
+func (a *StockCorporateAction) ToStockCorporateActionInfoResponse() *StockCorporateActionInfoResponse {
+	return &StockCorporateActionInfoResponse{
+		Id:               a.ActionId,
+		Symbol:           a.Symbol,
+		Type:             a.Type,
+		NewSymbol:        a.NewSymbol,
+		RatioNumerator:   a.RatioNumerator,
+		RatioDenominator: a.RatioDenominator,
+		EffectiveTime:    a.EffectiveTime,
+		Comment:          a.Comment,
+		Applied:          a.Applied,
+	}
+}
+
+// StockCorporateActionInfoResponseSlice represents the slice data structure of StockCorporateActionInfoResponse
+type StockCorporateActionInfoResponseSlice []*StockCorporateActionInfoResponse
+
+// Len returns the count of items
+func (s StockCorporateActionInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s StockCorporateActionInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s StockCorporateActionInfoResponseSlice) Less(i, j int) bool {
+	if s[i].EffectiveTime != s[j].EffectiveTime {
+		return s[i].EffectiveTime > s[j].EffectiveTime
+	}
+
+	return s[i].Id > s[j].Id
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockCorporateActionGetAdjustedPrice_Split(t *testing.T) {
+	action := &StockCorporateAction{
+		Type:             STOCK_CORPORATE_ACTION_TYPE_SPLIT,
+		RatioNumerator:   3,
+		RatioDenominator: 1,
+	}
+
+	price, ok := action.GetAdjustedPrice("900.30", false)
+	assert.True(t, ok)
+	assert.Equal(t, "300.1", price)
+
+	price, ok = action.GetAdjustedPrice("100", false)
+	assert.True(t, ok)
+	assert.Equal(t, "33.33333333", price)
+
+	price, ok = action.GetAdjustedPrice("300.1", true)
+	assert.True(t, ok)
+	assert.Equal(t, "900.3", price)
+}
+
+func TestStockCorporateActionGetAdjustedPrice_ReverseSplit(t *testing.T) {
+	action := &StockCorporateAction{
+		Type:             STOCK_CORPORATE_ACTION_TYPE_REVERSE_SPLIT,
+		RatioNumerator:   1,
+		RatioDenominator: 10,
+	}
+
+	price, ok := action.GetAdjustedPrice("1.25", false)
+	assert.True(t, ok)
+	assert.Equal(t, "12.5", price)
+
+	price, ok = action.GetAdjustedPrice("12.5", true)
+	assert.True(t, ok)
+	assert.Equal(t, "1.25", price)
+}
+
+func TestStockCorporateActionGetAdjustedPrice_NotAdjusted(t *testing.T) {
+	action := &StockCorporateAction{
+		Type:      STOCK_CORPORATE_ACTION_TYPE_SYMBOL_CHANGE,
+		NewSymbol: "META",
+	}
+
+	price, ok := action.GetAdjustedPrice("100", false)
+	assert.False(t, ok)
+	assert.Equal(t, "100", price)
+
+	action = &StockCorporateAction{
+		Type:             STOCK_CORPORATE_ACTION_TYPE_SPLIT,
+		RatioNumerator:   2,
+		RatioDenominator: 1,
+	}
+
+	price, ok = action.GetAdjustedPrice("abc", false)
+	assert.False(t, ok)
+	assert.Equal(t, "abc", price)
+}
+
+func TestStockCorporateActionInfoResponseSliceLess(t *testing.T) {
+	var actionSlice StockCorporateActionInfoResponseSlice
+	actionSlice = append(actionSlice, &StockCorporateActionInfoResponse{Id: 1, EffectiveTime: 1000})
+	actionSlice = append(actionSlice, &StockCorporateActionInfoResponse{Id: 2, EffectiveTime: 3000})
+	actionSlice = append(actionSlice, &StockCorporateActionInfoResponse{Id: 3, EffectiveTime: 1000})
+	actionSlice = append(actionSlice, &StockCorporateActionInfoResponse{Id: 4, EffectiveTime: 2000})
+
+	sort.Sort(actionSlice)
+
+	assert.Equal(t, int64(2), actionSlice[0].Id)
+	assert.Equal(t, int64(4), actionSlice[1].Id)
+	assert.Equal(t, int64(3), actionSlice[2].Id)
+	assert.Equal(t, int64(1), actionSlice[3].Id)
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"strings"
+)
+
+// StockDataSourceRoute represents the ordered data sources used for the stocks of a market stored in database
+type StockDataSourceRoute struct {
+	Market          string `xorm:"VARCHAR(20) PK"`
+	DataSources     string `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// StockDataSourceRouting represents all the information which is required to route stock symbols to data sources
+type StockDataSourceRouting struct {
+	Configs           []*ExternalDataSourceConfig
+	MarketDataSources map[string][]string
+	SymbolMarkets     map[string][]string
+}
+
+// StockDataSourceRouteSaveRequest represents all parameters of stock data source route saving request
+type StockDataSourceRouteSaveRequest struct {
+	Market      string   `json:"market" binding:"max=20"`
+	DataSources []string `json:"dataSources" binding:"required,min=1,dive,notBlank,max=50"`
+}
+
+// StockDataSourceRouteDeleteRequest represents all parameters of stock data source route deleting request
+type StockDataSourceRouteDeleteRequest struct {
+	Market string `json:"market" binding:"max=20"`
+}
+
+// StockDataSourceRouteInfoResponse represents a view-object of stock data source route
+type StockDataSourceRouteInfoResponse struct {
+	Market      string   `json:"market"`
+	DataSources []string `json:"dataSources"`
+}
+
+// GetDataSources returns the ordered data source list of the route
+func (r *StockDataSourceRoute) GetDataSources() []string {
+	if r.DataSources == "" {
+		return []string{}
+	}
+
+	return strings.Split(r.DataSources, ",")
+}
+
+// ToStockDataSourceRouteInfoResponse returns a view-object according to database model
+func (r *StockDataSourceRoute) ToStockDataSourceRouteInfoResponse() *StockDataSourceRouteInfoResponse {
+	return &StockDataSourceRouteInfoResponse{
+		Market:      r.Market,
+		DataSources: r.GetDataSources(),
+	}
+}
+
+// GetDataSourceConfigs returns the data source configs which are used to get the price of the specified symbol in fallback order,
+// the symbol watched in several markets would use the data sources of all these markets in order,
+// and the symbols in markets without route would use all the configured data sources
+func (r *StockDataSourceRouting) GetDataSourceConfigs(symbol string) []*ExternalDataSourceConfig {
+	markets, exists := r.SymbolMarkets[strings.ToUpper(symbol)]
+
+	if !exists || len(markets) < 1 {
+		return r.Configs
+	}
+
+	if len(markets) == 1 {
+		return r.getMarketDataSourceConfigs(markets[0])
+	}
+
+	configs := make([]*ExternalDataSourceConfig, 0, len(r.Configs))
+	addedDataSources := make(map[string]bool, len(r.Configs))
+
+	for i := 0; i < len(markets); i++ {
+		marketConfigs := r.getMarketDataSourceConfigs(markets[i])
+
+		for j := 0; j < len(marketConfigs); j++ {
+			if addedDataSources[marketConfigs[j].DataSource] {
+				continue
+			}
+
+			addedDataSources[marketConfigs[j].DataSource] = true
+			configs = append(configs, marketConfigs[j])
+		}
+	}
+
+	return configs
+}
+
+func (r *StockDataSourceRouting) getMarketDataSourceConfigs(market string) []*ExternalDataSourceConfig {
+	dataSources, exists := r.MarketDataSources[market]
+
+	if !exists || len(dataSources) < 1 {
+		return r.Configs
+	}
+
+	configs := make([]*ExternalDataSourceConfig, 0, len(dataSources))
+
+	for i := 0; i < len(dataSources); i++ {
+		for j := 0; j < len(r.Configs); j++ {
+			if r.Configs[j].DataSource == dataSources[i] {
+				configs = append(configs, r.Configs[j])
+				break
+			}
+		}
+	}
+
+	if len(configs) < 1 {
+		return r.Configs
+	}
+
+	return configs
+}
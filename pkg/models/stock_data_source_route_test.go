@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestStockDataSourceRouting() *StockDataSourceRouting {
+	return &StockDataSourceRouting{
+		Configs: []*ExternalDataSourceConfig{
+			{DataSource: "yahoo_finance"},
+			{DataSource: "alpha_vantage"},
+			{DataSource: "sina_finance"},
+		},
+		MarketDataSources: map[string][]string{
+			"CN": {"sina_finance", "yahoo_finance"},
+			"JP": {"not_configured"},
+		},
+		SymbolMarkets: map[string][]string{
+			"600519": {"CN"},
+			"7203":   {"JP"},
+			"AAPL":   {"US"},
+			"ABC":    {"CN", "US"},
+		},
+	}
+}
+
+func TestStockDataSourceRoutingGetDataSourceConfigs_MarketWithRoute(t *testing.T) {
+	routing := getTestStockDataSourceRouting()
+	configs := routing.GetDataSourceConfigs("600519")
+
+	assert.Equal(t, 2, len(configs))
+	assert.Equal(t, "sina_finance", configs[0].DataSource)
+	assert.Equal(t, "yahoo_finance", configs[1].DataSource)
+}
+
+func TestStockDataSourceRoutingGetDataSourceConfigs_MarketWithoutRoute(t *testing.T) {
+	routing := getTestStockDataSourceRouting()
+
+	assert.Equal(t, routing.Configs, routing.GetDataSourceConfigs("aapl"))
+	assert.Equal(t, routing.Configs, routing.GetDataSourceConfigs("UNKNOWN"))
+}
+
+func TestStockDataSourceRoutingGetDataSourceConfigs_RouteWithoutConfiguredDataSource(t *testing.T) {
+	routing := getTestStockDataSourceRouting()
+
+	assert.Equal(t, routing.Configs, routing.GetDataSourceConfigs("7203"))
+}
+
+func TestStockDataSourceRoutingGetDataSourceConfigs_SymbolInMultipleMarkets(t *testing.T) {
+	routing := getTestStockDataSourceRouting()
+	configs := routing.GetDataSourceConfigs("ABC")
+
+	assert.Equal(t, 3, len(configs))
+	assert.Equal(t, "sina_finance", configs[0].DataSource)
+	assert.Equal(t, "yahoo_finance", configs[1].DataSource)
+	assert.Equal(t, "alpha_vantage", configs[2].DataSource)
+}
+
+func TestStockDataSourceRouteGetDataSources(t *testing.T) {
+	assert.Equal(t, []string{"sina_finance", "yahoo_finance"}, (&StockDataSourceRoute{DataSources: "sina_finance,yahoo_finance"}).GetDataSources())
+	assert.Equal(t, []string{}, (&StockDataSourceRoute{}).GetDataSources())
+}
@@ -2,18 +2,19 @@ package models
 
 // LatestStockPriceResponse represents the response of latest stock price
 type LatestStockPriceResponse struct {
-	DataSource   string               `json:"dataSource"`
-	ReferenceUrl string               `json:"referenceUrl"`
-	UpdateTime   int64                `json:"updateTime"`
-	BaseCurrency string               `json:"baseCurrency"`
+	DataSource   string                `json:"dataSource"`
+	ReferenceUrl string                `json:"referenceUrl"`
+	UpdateTime   int64                 `json:"updateTime"`
+	BaseCurrency string                `json:"baseCurrency"`
 	Prices       LatestStockPriceSlice `json:"prices"`
 }
 
 // LatestStockPrice represents the latest stock price
 type LatestStockPrice struct {
-	Symbol   string `json:"symbol"`
-	Price    string `json:"price"`
-	Currency string `json:"currency"`
+	Symbol     string `json:"symbol"`
+	Price      string `json:"price"`
+	Currency   string `json:"currency"`
+	DataSource string `json:"dataSource,omitempty"`
 }
 
 // LatestStockPriceSlice represents the slice of latest stock price
@@ -33,3 +34,61 @@ func (s LatestStockPriceSlice) Swap(i, j int) {
 func (s LatestStockPriceSlice) Less(i, j int) bool {
 	return s[i].Symbol < s[j].Symbol
 }
+
+// StockPriceHistory represents the daily price of a stock stored in database
+type StockPriceHistory AssetPriceHistory
+
+// StockPriceHistoryRequest represents all parameters of stock price history request
+type StockPriceHistoryRequest struct {
+	Symbol     string `form:"symbol" binding:"required,notBlank,max=20"`
+	Currency   string `form:"currency" binding:"max=10"`
+	DataSource string `form:"data_source" binding:"max=50"`
+	StartTime  int64  `form:"start_time" binding:"min=0"`
+	EndTime    int64  `form:"end_time" binding:"min=0"`
+}
+
+// StockPriceHistoryResponse represents a view-object of stock price history
+type StockPriceHistoryResponse struct {
+	Symbol     string `json:"symbol"`
+	DataSource string `json:"dataSource"`
+	Currency   string `json:"currency"`
+	Date       int32  `json:"date"`
+	Price      string `json:"price"`
+}
+
+// ToStockPriceHistoryResponse returns a view-object according to database model
+func (h *StockPriceHistory) ToStockPriceHistoryResponse() *StockPriceHistoryResponse {
+	return &StockPriceHistoryResponse{
+		Symbol:     h.Symbol,
+		DataSource: h.DataSource,
+		Currency:   h.Currency,
+		Date:       h.PriceDate,
+		Price:      h.Price,
+	}
+}
+
+// StockPriceHistoryResponseSlice represents the slice of stock price history
+type StockPriceHistoryResponseSlice []*StockPriceHistoryResponse
+
+// Len returns the length of the slice
+func (s StockPriceHistoryResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps the elements with indexes i and j
+func (s StockPriceHistoryResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less returns true if the element with index i should be sorted before the element with index j
+func (s StockPriceHistoryResponseSlice) Less(i, j int) bool {
+	if s[i].Date != s[j].Date {
+		return s[i].Date < s[j].Date
+	}
+
+	if s[i].DataSource != s[j].DataSource {
+		return s[i].DataSource < s[j].DataSource
+	}
+
+	return s[i].Currency < s[j].Currency
+}
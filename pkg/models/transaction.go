@@ -130,23 +130,27 @@ const (
 
 // Transaction represents transaction data stored in database
 type Transaction struct {
-	TransactionId        int64             `xorm:"PK"`
-	Uid                  int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
-	Deleted              bool              `xorm:"INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
-	Type                 TransactionDbType `xorm:"INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
-	CategoryId           int64             `xorm:"INDEX(IDX_transaction_uid_deleted_category_id_time) NOT NULL"`
-	AccountId            int64             `xorm:"INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
-	TransactionTime      int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) NOT NULL"`
-	TimezoneUtcOffset    int16             `xorm:"NOT NULL"`
-	Amount               int64             `xorm:"NOT NULL"`
-	RelatedId            int64             `xorm:"NOT NULL"`
-	RelatedAccountId     int64             `xorm:"NOT NULL"`
-	RelatedAccountAmount int64             `xorm:"NOT NULL"`
-	HideAmount           bool              `xorm:"NOT NULL"`
-	Comment              string            `xorm:"VARCHAR(255) NOT NULL"`
-	GeoLongitude         float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
-	GeoLatitude          float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
-	CreatedIp            string            `xorm:"VARCHAR(39)"`
+	TransactionId        int64                           `xorm:"PK"`
+	Uid                  int64                           `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
+	Deleted              bool                            `xorm:"INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
+	Type                 TransactionDbType               `xorm:"INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
+	CategoryId           int64                           `xorm:"INDEX(IDX_transaction_uid_deleted_category_id_time) NOT NULL"`
+	AccountId            int64                           `xorm:"INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
+	TransactionTime      int64                           `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) NOT NULL"`
+	TimezoneUtcOffset    int16                           `xorm:"NOT NULL"`
+	Amount               int64                           `xorm:"NOT NULL"`
+	RelatedId            int64                           `xorm:"NOT NULL"`
+	RelatedAccountId     int64                           `xorm:"NOT NULL"`
+	RelatedAccountAmount int64                           `xorm:"NOT NULL"`
+	HoldingAccountId     int64                           `xorm:"NOT NULL DEFAULT 0"`
+	ReconciliationStatus TransactionReconciliationStatus `xorm:"NOT NULL DEFAULT 0"`
+	ReconciliationId     int64                           `xorm:"NOT NULL DEFAULT 0"`
+	Reimbursable         bool                            `xorm:"NOT NULL DEFAULT false"`
+	HideAmount           bool                            `xorm:"NOT NULL"`
+	Comment              string                          `xorm:"VARCHAR(255) NOT NULL"`
+	GeoLongitude         float64                         `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
+	GeoLatitude          float64                         `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
+	CreatedIp            string                          `xorm:"VARCHAR(39)"`
 	ScheduledCreated     bool
 	CreatedUnixTime      int64
 	UpdatedUnixTime      int64
@@ -160,6 +164,17 @@ type TransactionWithAccountBalance struct {
 	AccountClosingBalance int64
 }
 
+// TransactionWithConvertedAmount represents a transaction item with the amount converted to another currency
+type TransactionWithConvertedAmount struct {
+	*Transaction
+	ConvertedAmount    int64
+	HasConvertedAmount bool
+}
+
+// TransactionAmountConverter converts the amount of the specified account at the specified date (in yyyymmdd format) to another currency,
+// and returns false if the amount cannot be converted
+type TransactionAmountConverter func(accountId int64, amount int64, yearMonthDay int32) (int64, bool)
+
 // TransactionGeoLocationRequest represents all parameters of transaction geographic location info update request
 type TransactionGeoLocationRequest struct {
 	Latitude  float64 `json:"latitude" binding:"required"`
@@ -168,37 +183,43 @@ type TransactionGeoLocationRequest struct {
 
 // TransactionCreateRequest represents all parameters of transaction creation request
 type TransactionCreateRequest struct {
-	Type                 TransactionType                `json:"type" binding:"required"`
-	CategoryId           int64                          `json:"categoryId,string"`
-	Time                 int64                          `json:"time" binding:"required,min=1"`
-	UtcOffset            int16                          `json:"utcOffset" binding:"min=-720,max=840"`
-	SourceAccountId      int64                          `json:"sourceAccountId,string" binding:"required,min=1"`
-	DestinationAccountId int64                          `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-9223372036854775808,max=9223372036854775807"`
-	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-9223372036854775808,max=9223372036854775807"`
-	HideAmount           bool                           `json:"hideAmount"`
-	TagIds               []string                       `json:"tagIds"`
-	PictureIds           []string                       `json:"pictureIds"`
-	Comment              string                         `json:"comment" binding:"max=255"`
-	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
-	ClientSessionId      string                         `json:"clientSessionId"`
+	Type                 TransactionType                  `json:"type" binding:"required"`
+	CategoryId           int64                            `json:"categoryId,string"`
+	Time                 int64                            `json:"time" binding:"required,min=1"`
+	UtcOffset            int16                            `json:"utcOffset" binding:"min=-720,max=840"`
+	SourceAccountId      int64                            `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId int64                            `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount         int64                            `json:"sourceAmount" binding:"min=-9223372036854775808,max=9223372036854775807"`
+	DestinationAmount    int64                            `json:"destinationAmount" binding:"min=-9223372036854775808,max=9223372036854775807"`
+	HoldingAccountId     int64                            `json:"holdingAccountId,string" binding:"min=0"`
+	HideAmount           bool                             `json:"hideAmount"`
+	Reimbursable         bool                             `json:"reimbursable"`
+	TagIds               []string                         `json:"tagIds"`
+	PictureIds           []string                         `json:"pictureIds"`
+	Comment              string                           `json:"comment" binding:"max=255"`
+	GeoLocation          *TransactionGeoLocationRequest   `json:"geoLocation" binding:"omitempty"`
+	Splits               []*TransactionSplitCreateRequest `json:"splits" binding:"omitempty,dive"`
+	ClientSessionId      string                           `json:"clientSessionId"`
 }
 
 // TransactionModifyRequest represents all parameters of transaction modification request
 type TransactionModifyRequest struct {
-	Id                   int64                          `json:"id,string" binding:"required,min=1"`
-	CategoryId           int64                          `json:"categoryId,string"`
-	Time                 int64                          `json:"time" binding:"required,min=1"`
-	UtcOffset            int16                          `json:"utcOffset" binding:"min=-720,max=840"`
-	SourceAccountId      int64                          `json:"sourceAccountId,string" binding:"required,min=1"`
-	DestinationAccountId int64                          `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-9223372036854775808,max=9223372036854775807"`
-	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-9223372036854775808,max=9223372036854775807"`
-	HideAmount           bool                           `json:"hideAmount"`
-	TagIds               []string                       `json:"tagIds"`
-	PictureIds           []string                       `json:"pictureIds"`
-	Comment              string                         `json:"comment" binding:"max=255"`
-	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Id                   int64                            `json:"id,string" binding:"required,min=1"`
+	CategoryId           int64                            `json:"categoryId,string"`
+	Time                 int64                            `json:"time" binding:"required,min=1"`
+	UtcOffset            int16                            `json:"utcOffset" binding:"min=-720,max=840"`
+	SourceAccountId      int64                            `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId int64                            `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount         int64                            `json:"sourceAmount" binding:"min=-9223372036854775808,max=9223372036854775807"`
+	DestinationAmount    int64                            `json:"destinationAmount" binding:"min=-9223372036854775808,max=9223372036854775807"`
+	HoldingAccountId     int64                            `json:"holdingAccountId,string" binding:"min=0"`
+	HideAmount           bool                             `json:"hideAmount"`
+	Reimbursable         bool                             `json:"reimbursable"`
+	TagIds               []string                         `json:"tagIds"`
+	PictureIds           []string                         `json:"pictureIds"`
+	Comment              string                           `json:"comment" binding:"max=255"`
+	GeoLocation          *TransactionGeoLocationRequest   `json:"geoLocation" binding:"omitempty"`
+	Splits               []*TransactionSplitCreateRequest `json:"splits" binding:"omitempty,dive"`
 }
 
 // TransactionImportRequest represents all parameters of transaction import request
@@ -289,19 +310,21 @@ type TransactionReconciliationStatementRequest struct {
 
 // TransactionStatisticRequest represents all parameters of transaction statistic request
 type TransactionStatisticRequest struct {
-	StartTime              int64  `form:"start_time" binding:"min=0"`
-	EndTime                int64  `form:"end_time" binding:"min=0"`
-	TagFilter              string `form:"tag_filter" binding:"validTagFilter"`
-	Keyword                string `form:"keyword"`
-	UseTransactionTimezone bool   `form:"use_transaction_timezone"`
+	StartTime                  int64  `form:"start_time" binding:"min=0"`
+	EndTime                    int64  `form:"end_time" binding:"min=0"`
+	TagFilter                  string `form:"tag_filter" binding:"validTagFilter"`
+	Keyword                    string `form:"keyword"`
+	UseTransactionTimezone     bool   `form:"use_transaction_timezone"`
+	UseHistoricalExchangeRates bool   `form:"use_historical_exchange_rates"`
 }
 
 // TransactionStatisticTrendsRequest represents all parameters of transaction statistic trends request
 type TransactionStatisticTrendsRequest struct {
 	YearMonthRangeRequest
-	TagFilter              string `form:"tag_filter" binding:"validTagFilter"`
-	Keyword                string `form:"keyword"`
-	UseTransactionTimezone bool   `form:"use_transaction_timezone"`
+	TagFilter                  string `form:"tag_filter" binding:"validTagFilter"`
+	Keyword                    string `form:"keyword"`
+	UseTransactionTimezone     bool   `form:"use_transaction_timezone"`
+	UseHistoricalExchangeRates bool   `form:"use_historical_exchange_rates"`
 }
 
 // TransactionStatisticAssetTrendsRequest represents all parameters of transaction statistic asset trends request
@@ -310,6 +333,19 @@ type TransactionStatisticAssetTrendsRequest struct {
 	EndTime   int64 `form:"end_time"`
 }
 
+// TransactionStatisticHoldingIncomeRequest represents all parameters of transaction statistic holding income request
+type TransactionStatisticHoldingIncomeRequest struct {
+	StartYear int32 `form:"start_year" binding:"min=0"`
+	EndYear   int32 `form:"end_year" binding:"min=0"`
+}
+
+// TransactionStatisticFxGainLossRequest represents all parameters of transaction statistic foreign exchange gain and loss request
+type TransactionStatisticFxGainLossRequest struct {
+	StartTime              int64 `form:"start_time" binding:"min=0"`
+	EndTime                int64 `form:"end_time" binding:"min=0"`
+	UseTransactionTimezone bool  `form:"use_transaction_timezone"`
+}
+
 // TransactionAmountsRequest represents all parameters of transaction amounts request
 type TransactionAmountsRequest struct {
 	Query                  string `form:"query"`
@@ -372,12 +408,15 @@ type TransactionInfoResponse struct {
 	DestinationAccount   *AccountInfoResponse                     `json:"destinationAccount,omitempty"`
 	SourceAmount         int64                                    `json:"sourceAmount"`
 	DestinationAmount    int64                                    `json:"destinationAmount,omitempty"`
+	HoldingAccountId     int64                                    `json:"holdingAccountId,string,omitempty"`
 	HideAmount           bool                                     `json:"hideAmount"`
+	Reimbursable         bool                                     `json:"reimbursable,omitempty"`
 	TagIds               []string                                 `json:"tagIds"`
 	Tags                 []*TransactionTagInfoResponse            `json:"tags,omitempty"`
 	Pictures             TransactionPictureInfoBasicResponseSlice `json:"pictures,omitempty"`
 	Comment              string                                   `json:"comment"`
 	GeoLocation          *TransactionGeoLocationResponse          `json:"geoLocation,omitempty"`
+	Splits               []*TransactionSplitInfoResponse          `json:"splits,omitempty"`
 	Editable             bool                                     `json:"editable"`
 }
 
@@ -402,8 +441,9 @@ type TransactionInfoPageWrapperResponse2 struct {
 // TransactionReconciliationStatementResponseItem represents a transaction reconciliation statement response
 type TransactionReconciliationStatementResponseItem struct {
 	*TransactionInfoResponse
-	AccountOpeningBalance int64 `json:"accountOpeningBalance"`
-	AccountClosingBalance int64 `json:"accountClosingBalance"`
+	AccountOpeningBalance int64                           `json:"accountOpeningBalance"`
+	AccountClosingBalance int64                           `json:"accountClosingBalance"`
+	ReconciliationStatus  TransactionReconciliationStatus `json:"reconciliationStatus"`
 }
 
 // TransactionReconciliationStatementResponse represents the response of all transaction reconciliation statement response
@@ -424,11 +464,13 @@ type TransactionStatisticResponse struct {
 
 // TransactionStatisticResponseItem represents total amount item for a response
 type TransactionStatisticResponseItem struct {
-	CategoryId         int64                         `json:"categoryId,string"`
-	AccountId          int64                         `json:"accountId,string"`
-	RelatedAccountId   int64                         `json:"relatedAccountId,string,omitempty"`
-	RelatedAccountType TransactionRelatedAccountType `json:"relatedAccountType,omitempty"`
-	TotalAmount        int64                         `json:"amount"`
+	CategoryId               int64                         `json:"categoryId,string"`
+	AccountId                int64                         `json:"accountId,string"`
+	RelatedAccountId         int64                         `json:"relatedAccountId,string,omitempty"`
+	RelatedAccountType       TransactionRelatedAccountType `json:"relatedAccountType,omitempty"`
+	TotalAmount              int64                         `json:"amount"`
+	DefaultCurrencyAmount    int64                         `json:"defaultCurrencyAmount,omitempty"`
+	HasDefaultCurrencyAmount bool                          `json:"hasDefaultCurrencyAmount,omitempty"`
 }
 
 // TransactionStatisticTrendsResponseItem represents the data within each statistic interval
@@ -451,6 +493,72 @@ type TransactionStatisticAssetTrendsResponseDataItem struct {
 	AccountId             int64 `json:"accountId,string"`
 	AccountOpeningBalance int64 `json:"accountOpeningBalance"`
 	AccountClosingBalance int64 `json:"accountClosingBalance"`
+	AccountOpeningValue   int64 `json:"accountOpeningValue"`
+	AccountClosingValue   int64 `json:"accountClosingValue"`
+	HasValue              bool  `json:"hasValue"`
+}
+
+// TransactionStatisticHoldingIncomeResponse represents the income of all holdings in the default currency of user
+type TransactionStatisticHoldingIncomeResponse struct {
+	Currency string                                                    `json:"currency"`
+	Holdings TransactionStatisticHoldingIncomeResponseHoldingItemSlice `json:"holdings"`
+}
+
+// TransactionStatisticHoldingIncomeResponseHoldingItem represents the income of a holding
+type TransactionStatisticHoldingIncomeResponseHoldingItem struct {
+	HoldingAccountId     int64                                                `json:"holdingAccountId,string"`
+	Symbol               string                                               `json:"symbol"`
+	AssetType            AccountAssetType                                     `json:"assetType"`
+	TotalIncome          int64                                                `json:"totalIncome"`
+	HasUnconvertedIncome bool                                                 `json:"hasUnconvertedIncome"`
+	Years                []*TransactionStatisticHoldingIncomeResponseYearItem `json:"years"`
+}
+
+// TransactionStatisticHoldingIncomeResponseYearItem represents the income of a holding in a year
+type TransactionStatisticHoldingIncomeResponseYearItem struct {
+	Year             int32  `json:"year"`
+	Income           int64  `json:"income"`
+	CumulativeIncome int64  `json:"cumulativeIncome"`
+	HoldingValue     int64  `json:"holdingValue"`
+	DividendYield    string `json:"dividendYield,omitempty"`
+}
+
+// TransactionStatisticFxGainLossResponse represents the foreign exchange gain and loss of all cross-currency transfers in the default currency of user
+type TransactionStatisticFxGainLossResponse struct {
+	Currency             string                                                `json:"currency"`
+	TotalFees            int64                                                 `json:"totalFees"`
+	TotalGains           int64                                                 `json:"totalGains"`
+	NetGainLoss          int64                                                 `json:"netGainLoss"`
+	HasUnconvertedAmount bool                                                  `json:"hasUnconvertedAmount"`
+	Periods              []*TransactionStatisticFxGainLossResponsePeriodItem   `json:"periods"`
+	Transfers            []*TransactionStatisticFxGainLossResponseTransferItem `json:"transfers"`
+}
+
+// TransactionStatisticFxGainLossResponsePeriodItem represents the total fees and foreign exchange gain and loss in a month
+type TransactionStatisticFxGainLossResponsePeriodItem struct {
+	Year          int32 `json:"year"`
+	Month         int32 `json:"month"`
+	TransferCount int32 `json:"transferCount"`
+	Fees          int64 `json:"fees"`
+	Gains         int64 `json:"gains"`
+	NetGainLoss   int64 `json:"netGainLoss"`
+}
+
+// TransactionStatisticFxGainLossResponseTransferItem represents the effective exchange rate and the foreign exchange gain and loss of a cross-currency transfer
+type TransactionStatisticFxGainLossResponseTransferItem struct {
+	TransactionId        int64  `json:"transactionId,string"`
+	Time                 int64  `json:"time"`
+	SourceAccountId      int64  `json:"sourceAccountId,string"`
+	SourceCurrency       string `json:"sourceCurrency"`
+	SourceAmount         int64  `json:"sourceAmount"`
+	DestinationAccountId int64  `json:"destinationAccountId,string"`
+	DestinationCurrency  string `json:"destinationCurrency"`
+	DestinationAmount    int64  `json:"destinationAmount"`
+	EffectiveRate        string `json:"effectiveRate"`
+	ReferenceRate        string `json:"referenceRate,omitempty"`
+	ReferenceAmount      int64  `json:"referenceAmount,omitempty"`
+	GainLoss             int64  `json:"gainLoss"`
+	HasGainLoss          bool   `json:"hasGainLoss"`
 }
 
 // TransactionAmountsResponseItem represents an item of transaction amounts
@@ -530,6 +638,10 @@ func (t *Transaction) IsEditable(currentUser *User, clientTimezone *time.Locatio
 		return false
 	}
 
+	if t.ReconciliationStatus == TRANSACTION_RECONCILIATION_STATUS_RECONCILED {
+		return false
+	}
+
 	if t.Type == TRANSACTION_DB_TYPE_TRANSFER_OUT {
 		if relatedAccount == nil || relatedAccount.Hidden {
 			return false
@@ -584,7 +696,9 @@ func (t *Transaction) ToTransactionInfoResponse(tagIds []int64, editable bool) *
 		DestinationAccountId: destinationAccountId,
 		SourceAmount:         sourceAmount,
 		DestinationAmount:    destinationAmount,
+		HoldingAccountId:     t.HoldingAccountId,
 		HideAmount:           t.HideAmount,
+		Reimbursable:         t.Reimbursable,
 		TagIds:               utils.Int64ArrayToStringArray(tagIds),
 		Comment:              t.Comment,
 		GeoLocation:          geoLocation,
@@ -696,6 +810,28 @@ func (s TransactionStatisticTrendsResponseItemSlice) Less(i, j int) bool {
 	return s[i].Month < s[j].Month
 }
 
+// TransactionStatisticHoldingIncomeResponseHoldingItemSlice represents the slice data structure of TransactionStatisticHoldingIncomeResponseHoldingItem
+type TransactionStatisticHoldingIncomeResponseHoldingItemSlice []*TransactionStatisticHoldingIncomeResponseHoldingItem
+
+// Len returns the count of items
+func (s TransactionStatisticHoldingIncomeResponseHoldingItemSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionStatisticHoldingIncomeResponseHoldingItemSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionStatisticHoldingIncomeResponseHoldingItemSlice) Less(i, j int) bool {
+	if s[i].TotalIncome != s[j].TotalIncome {
+		return s[i].TotalIncome > s[j].TotalIncome
+	}
+
+	return s[i].HoldingAccountId < s[j].HoldingAccountId
+}
+
 // TransactionStatisticAssetTrendsResponseItemSlice represents the slice data structure of TransactionStatisticAssetTrendsResponseItem
 type TransactionStatisticAssetTrendsResponseItemSlice []*TransactionStatisticAssetTrendsResponseItem
 
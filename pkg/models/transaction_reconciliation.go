@@ -0,0 +1,124 @@
+package models
+
+// TransactionReconciliationStatus represents the reconciliation status of transaction in its account,
+// the status of transfer transaction is stored separately in the transfer out and transfer in rows
+type TransactionReconciliationStatus byte
+
+// Transaction reconciliation statuses
+const (
+	TRANSACTION_RECONCILIATION_STATUS_UNCLEARED  TransactionReconciliationStatus = 0
+	TRANSACTION_RECONCILIATION_STATUS_CLEARED    TransactionReconciliationStatus = 1
+	TRANSACTION_RECONCILIATION_STATUS_RECONCILED TransactionReconciliationStatus = 2
+)
+
+// String returns a textual representation of the transaction reconciliation status
+func (s TransactionReconciliationStatus) String() string {
+	switch s {
+	case TRANSACTION_RECONCILIATION_STATUS_UNCLEARED:
+		return "Uncleared"
+	case TRANSACTION_RECONCILIATION_STATUS_CLEARED:
+		return "Cleared"
+	case TRANSACTION_RECONCILIATION_STATUS_RECONCILED:
+		return "Reconciled"
+	default:
+		return "Invalid"
+	}
+}
+
+// TransactionReconciliation represents a finished reconciliation of an account against a bank statement stored in database,
+// all the cleared transactions before the statement time are reconciled and locked against edits by the reconciliation
+type TransactionReconciliation struct {
+	ReconciliationId  int64 `xorm:"PK"`
+	Uid               int64 `xorm:"INDEX(IDX_transaction_reconciliation_uid_deleted_account_id_time) NOT NULL"`
+	Deleted           bool  `xorm:"INDEX(IDX_transaction_reconciliation_uid_deleted_account_id_time) NOT NULL"`
+	AccountId         int64 `xorm:"INDEX(IDX_transaction_reconciliation_uid_deleted_account_id_time) NOT NULL"`
+	StatementUnixTime int64 `xorm:"INDEX(IDX_transaction_reconciliation_uid_deleted_account_id_time) NOT NULL"`
+	StatementBalance  int64 `xorm:"NOT NULL"`
+	TransactionCount  int32 `xorm:"NOT NULL"`
+	CreatedUnixTime   int64
+	UpdatedUnixTime   int64
+	DeletedUnixTime   int64
+}
+
+// TransactionReconciliationStatusModifyRequest represents all parameters of transaction reconciliation status modification request,
+// the ids are the transaction ids shown in the reconciliation statement of the account
+type TransactionReconciliationStatusModifyRequest struct {
+	AccountId int64                           `json:"accountId,string" binding:"required,min=1"`
+	Ids       []string                        `json:"ids" binding:"required,min=1"`
+	Status    TransactionReconciliationStatus `json:"status" binding:"min=0,max=1"`
+}
+
+// TransactionReconciliationListRequest represents all parameters of transaction reconciliation listing request
+type TransactionReconciliationListRequest struct {
+	AccountId int64 `form:"account_id,string" binding:"required,min=1"`
+}
+
+// TransactionReconciliationPreviewRequest represents all parameters of transaction reconciliation preview request
+type TransactionReconciliationPreviewRequest struct {
+	AccountId     int64 `form:"account_id,string" binding:"required,min=1"`
+	StatementTime int64 `form:"statement_time" binding:"required,min=1"`
+}
+
+// TransactionReconciliationCreateRequest represents all parameters of transaction reconciliation creation request
+type TransactionReconciliationCreateRequest struct {
+	AccountId        int64 `json:"accountId,string" binding:"required,min=1"`
+	StatementTime    int64 `json:"statementTime" binding:"required,min=1"`
+	StatementBalance int64 `json:"statementBalance"`
+}
+
+// TransactionReconciliationDeleteRequest represents all parameters of transaction reconciliation deleting request
+type TransactionReconciliationDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionReconciliationInfoResponse represents a view-object of transaction reconciliation
+type TransactionReconciliationInfoResponse struct {
+	Id               int64 `json:"id,string"`
+	AccountId        int64 `json:"accountId,string"`
+	StatementTime    int64 `json:"statementTime"`
+	StatementBalance int64 `json:"statementBalance"`
+	TransactionCount int32 `json:"transactionCount"`
+	CreatedTime      int64 `json:"createdTime"`
+}
+
+// TransactionReconciliationPreviewResponse represents a view-object of the cleared balance of account before the statement time
+type TransactionReconciliationPreviewResponse struct {
+	AccountId          int64                                  `json:"accountId,string"`
+	StatementTime      int64                                  `json:"statementTime"`
+	ClearedBalance     int64                                  `json:"clearedBalance"`
+	ClearedCount       int32                                  `json:"clearedCount"`
+	UnclearedCount     int32                                  `json:"unclearedCount"`
+	LastReconciliation *TransactionReconciliationInfoResponse `json:"lastReconciliation,omitempty"`
+}
+
+// ToTransactionReconciliationInfoResponse returns a view-object according to database model
+func (r *TransactionReconciliation) ToTransactionReconciliationInfoResponse() *TransactionReconciliationInfoResponse {
+	return &TransactionReconciliationInfoResponse{
+		Id:               r.ReconciliationId,
+		AccountId:        r.AccountId,
+		StatementTime:    r.StatementUnixTime,
+		StatementBalance: r.StatementBalance,
+		TransactionCount: r.TransactionCount,
+		CreatedTime:      r.CreatedUnixTime,
+	}
+}
+
+// GetTransactionsAccountBalanceChange returns the total balance change of the account of the specified transactions
+func GetTransactionsAccountBalanceChange(transactions []*Transaction) int64 {
+	balanceChange := int64(0)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		switch transaction.Type {
+		case TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+			balanceChange += transaction.RelatedAccountAmount
+		case TRANSACTION_DB_TYPE_INCOME, TRANSACTION_DB_TYPE_TRANSFER_IN:
+			balanceChange += transaction.Amount
+		case TRANSACTION_DB_TYPE_EXPENSE, TRANSACTION_DB_TYPE_TRANSFER_OUT:
+			balanceChange -= transaction.Amount
+		}
+	}
+
+	return balanceChange
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionReconciliationStatusString(t *testing.T) {
+	assert.Equal(t, "Uncleared", TRANSACTION_RECONCILIATION_STATUS_UNCLEARED.String())
+	assert.Equal(t, "Cleared", TRANSACTION_RECONCILIATION_STATUS_CLEARED.String())
+	assert.Equal(t, "Reconciled", TRANSACTION_RECONCILIATION_STATUS_RECONCILED.String())
+	assert.Equal(t, "Invalid", TransactionReconciliationStatus(3).String())
+}
+
+func TestGetTransactionsAccountBalanceChange(t *testing.T) {
+	transactions := []*Transaction{
+		{Type: TRANSACTION_DB_TYPE_MODIFY_BALANCE, Amount: 10000, RelatedAccountAmount: 10000},
+		{Type: TRANSACTION_DB_TYPE_INCOME, Amount: 5000},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, Amount: 1200},
+		{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, Amount: 3000, RelatedAccountAmount: 2000},
+		{Type: TRANSACTION_DB_TYPE_TRANSFER_IN, Amount: 800, RelatedAccountAmount: 900},
+	}
+
+	assert.Equal(t, int64(11600), GetTransactionsAccountBalanceChange(transactions))
+	assert.Equal(t, int64(0), GetTransactionsAccountBalanceChange(nil))
+}
+
+func TestTransactionIsEditable_ReconciledTransaction(t *testing.T) {
+	user := &User{
+		TransactionEditScope: TRANSACTION_EDIT_SCOPE_ALL,
+	}
+
+	transaction := &Transaction{
+		Type:                 TRANSACTION_DB_TYPE_EXPENSE,
+		ReconciliationStatus: TRANSACTION_RECONCILIATION_STATUS_CLEARED,
+	}
+
+	assert.True(t, transaction.IsEditable(user, nil, &Account{}, nil))
+
+	transaction.ReconciliationStatus = TRANSACTION_RECONCILIATION_STATUS_RECONCILED
+	assert.False(t, transaction.IsEditable(user, nil, &Account{}, nil))
+}
@@ -0,0 +1,181 @@
+package models
+
+import "math"
+
+// TransactionRefund represents a link between an expense transaction and an income transaction which refunds or reimburses it stored in database,
+// the refund amount is in the currency of both transactions and can be less than the income amount (partial refund)
+type TransactionRefund struct {
+	RefundId             int64 `xorm:"PK"`
+	Uid                  int64 `xorm:"INDEX(IDX_transaction_refund_uid_deleted_expense_id) INDEX(IDX_transaction_refund_uid_deleted_income_id) NOT NULL"`
+	Deleted              bool  `xorm:"INDEX(IDX_transaction_refund_uid_deleted_expense_id) INDEX(IDX_transaction_refund_uid_deleted_income_id) NOT NULL"`
+	ExpenseTransactionId int64 `xorm:"INDEX(IDX_transaction_refund_uid_deleted_expense_id) NOT NULL"`
+	IncomeTransactionId  int64 `xorm:"INDEX(IDX_transaction_refund_uid_deleted_income_id) NOT NULL"`
+	Amount               int64 `xorm:"NOT NULL"`
+	CreatedUnixTime      int64
+	UpdatedUnixTime      int64
+	DeletedUnixTime      int64
+}
+
+// TransactionRefundListRequest represents all parameters of transaction refund listing request
+type TransactionRefundListRequest struct {
+	TransactionId int64 `form:"transaction_id,string" binding:"required,min=1"`
+}
+
+// TransactionRefundCreateRequest represents all parameters of transaction refund creation request
+type TransactionRefundCreateRequest struct {
+	ExpenseTransactionId int64 `json:"expenseTransactionId,string" binding:"required,min=1"`
+	IncomeTransactionId  int64 `json:"incomeTransactionId,string" binding:"required,min=1"`
+	Amount               int64 `json:"amount" binding:"min=0,max=99999999999"`
+}
+
+// TransactionRefundDeleteRequest represents all parameters of transaction refund deleting request
+type TransactionRefundDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRefundInfoResponse represents a view-object of transaction refund
+type TransactionRefundInfoResponse struct {
+	Id                   int64 `json:"id,string"`
+	ExpenseTransactionId int64 `json:"expenseTransactionId,string"`
+	IncomeTransactionId  int64 `json:"incomeTransactionId,string"`
+	Amount               int64 `json:"amount"`
+	CreatedTime          int64 `json:"createdTime"`
+}
+
+// TransactionOutstandingReimbursementResponse represents a view-object of reimbursable expense transaction which has not been fully reimbursed
+type TransactionOutstandingReimbursementResponse struct {
+	Id                int64  `json:"id,string"`
+	Time              int64  `json:"time"`
+	UtcOffset         int16  `json:"utcOffset"`
+	CategoryId        int64  `json:"categoryId,string"`
+	AccountId         int64  `json:"accountId,string"`
+	Amount            int64  `json:"amount"`
+	ReimbursedAmount  int64  `json:"reimbursedAmount"`
+	OutstandingAmount int64  `json:"outstandingAmount"`
+	Comment           string `json:"comment"`
+}
+
+// ToTransactionRefundInfoResponse returns a view-object according to database model
+func (r *TransactionRefund) ToTransactionRefundInfoResponse() *TransactionRefundInfoResponse {
+	return &TransactionRefundInfoResponse{
+		Id:                   r.RefundId,
+		ExpenseTransactionId: r.ExpenseTransactionId,
+		IncomeTransactionId:  r.IncomeTransactionId,
+		Amount:               r.Amount,
+		CreatedTime:          r.CreatedUnixTime,
+	}
+}
+
+// GetTransactionRefundedAmounts returns the total refund amounts of each expense transaction
+func GetTransactionRefundedAmounts(refunds []*TransactionRefund) map[int64]int64 {
+	refundedAmounts := make(map[int64]int64)
+
+	for i := 0; i < len(refunds); i++ {
+		refundedAmounts[refunds[i].ExpenseTransactionId] += refunds[i].Amount
+	}
+
+	return refundedAmounts
+}
+
+// GetTransactionRefundCategoryAmounts returns the refund amount of each category of the refunded expense transaction,
+// the refund amount of split transaction is allocated to the split lines in proportion to their amounts and the rounding difference is allocated to the last line
+func GetTransactionRefundCategoryAmounts(refundAmount int64, refundedTransaction *Transaction, refundedTransactionSplits []*TransactionSplit) []*TransactionSplit {
+	if len(refundedTransactionSplits) < 1 || refundedTransaction.Amount == 0 {
+		return []*TransactionSplit{
+			{
+				CategoryId: refundedTransaction.CategoryId,
+				Amount:     refundAmount,
+			},
+		}
+	}
+
+	categoryAmounts := make([]*TransactionSplit, len(refundedTransactionSplits))
+	allocatedAmount := int64(0)
+
+	for i := 0; i < len(refundedTransactionSplits); i++ {
+		amount := refundAmount - allocatedAmount
+
+		if i < len(refundedTransactionSplits)-1 {
+			amount = int64(math.Round(float64(refundAmount) * float64(refundedTransactionSplits[i].Amount) / float64(refundedTransaction.Amount)))
+		}
+
+		categoryAmounts[i] = &TransactionSplit{
+			CategoryId: refundedTransactionSplits[i].CategoryId,
+			Amount:     amount,
+		}
+
+		allocatedAmount += amount
+	}
+
+	return categoryAmounts
+}
+
+// GetTransactionsWithNettedRefunds returns the transactions whose refund amounts are moved from the refund income transactions to the categories of the refunded expense transactions,
+// each linked refund amount is deducted from the income transaction (or its splits in order) and is added as negative expenses of the refunded expense categories (or its split line categories) in the income account
+func GetTransactionsWithNettedRefunds(transactions []*Transaction, refunds []*TransactionRefund, refundedTransactions map[int64]*Transaction, refundedTransactionSplits map[int64][]*TransactionSplit) []*Transaction {
+	if len(refunds) < 1 {
+		return transactions
+	}
+
+	incomeRefunds := make(map[int64][]*TransactionRefund)
+	remainingRefundAmounts := make(map[int64]int64)
+
+	for i := 0; i < len(refunds); i++ {
+		refund := refunds[i]
+
+		if _, exists := refundedTransactions[refund.ExpenseTransactionId]; !exists {
+			continue
+		}
+
+		incomeRefunds[refund.IncomeTransactionId] = append(incomeRefunds[refund.IncomeTransactionId], refund)
+		remainingRefundAmounts[refund.IncomeTransactionId] += refund.Amount
+	}
+
+	nettedTransactions := make([]*Transaction, 0, len(transactions)+len(refunds))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		currentIncomeRefunds, exists := incomeRefunds[transaction.TransactionId]
+
+		if transaction.Type != TRANSACTION_DB_TYPE_INCOME || !exists {
+			nettedTransactions = append(nettedTransactions, transaction)
+			continue
+		}
+
+		deductedAmount := remainingRefundAmounts[transaction.TransactionId]
+
+		if deductedAmount > transaction.Amount {
+			deductedAmount = transaction.Amount
+		}
+
+		remainingRefundAmounts[transaction.TransactionId] -= deductedAmount
+
+		if transaction.Amount != deductedAmount {
+			nettedTransaction := *transaction
+			nettedTransaction.Amount = transaction.Amount - deductedAmount
+			nettedTransactions = append(nettedTransactions, &nettedTransaction)
+		}
+
+		if currentIncomeRefunds == nil {
+			continue
+		}
+
+		for j := 0; j < len(currentIncomeRefunds); j++ {
+			refund := currentIncomeRefunds[j]
+			categoryAmounts := GetTransactionRefundCategoryAmounts(refund.Amount, refundedTransactions[refund.ExpenseTransactionId], refundedTransactionSplits[refund.ExpenseTransactionId])
+
+			for k := 0; k < len(categoryAmounts); k++ {
+				refundTransaction := *transaction
+				refundTransaction.Type = TRANSACTION_DB_TYPE_EXPENSE
+				refundTransaction.CategoryId = categoryAmounts[k].CategoryId
+				refundTransaction.Amount = -categoryAmounts[k].Amount
+				nettedTransactions = append(nettedTransactions, &refundTransaction)
+			}
+		}
+
+		// the refund expenses of the income transaction which is expanded to multiple splits are only added once
+		incomeRefunds[transaction.TransactionId] = nil
+	}
+
+	return nettedTransactions
+}
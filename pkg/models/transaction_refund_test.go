@@ -0,0 +1,155 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTransactionRefundedAmounts(t *testing.T) {
+	refunds := []*TransactionRefund{
+		{ExpenseTransactionId: 1, IncomeTransactionId: 11, Amount: 300},
+		{ExpenseTransactionId: 1, IncomeTransactionId: 12, Amount: 200},
+		{ExpenseTransactionId: 2, IncomeTransactionId: 13, Amount: 1000},
+	}
+
+	actualValue := GetTransactionRefundedAmounts(refunds)
+	assert.Equal(t, 2, len(actualValue))
+	assert.Equal(t, int64(500), actualValue[1])
+	assert.Equal(t, int64(1000), actualValue[2])
+}
+
+func TestGetTransactionsWithNettedRefunds_PartialRefund(t *testing.T) {
+	transactions := []*Transaction{
+		{TransactionId: 1, Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 100, AccountId: 1000, Amount: 5000},
+		{TransactionId: 2, Type: TRANSACTION_DB_TYPE_INCOME, CategoryId: 200, AccountId: 2000, Amount: 3000},
+		{TransactionId: 3, Type: TRANSACTION_DB_TYPE_INCOME, CategoryId: 200, AccountId: 2000, Amount: 800},
+	}
+
+	refunds := []*TransactionRefund{
+		{ExpenseTransactionId: 1, IncomeTransactionId: 2, Amount: 2000},
+	}
+
+	refundedTransactions := map[int64]*Transaction{
+		1: {TransactionId: 1, CategoryId: 100},
+	}
+
+	actualValue := GetTransactionsWithNettedRefunds(transactions, refunds, refundedTransactions, nil)
+	assert.Equal(t, 4, len(actualValue))
+
+	assert.Equal(t, TRANSACTION_DB_TYPE_EXPENSE, actualValue[0].Type)
+	assert.Equal(t, int64(5000), actualValue[0].Amount)
+
+	assert.Equal(t, TRANSACTION_DB_TYPE_INCOME, actualValue[1].Type)
+	assert.Equal(t, int64(200), actualValue[1].CategoryId)
+	assert.Equal(t, int64(1000), actualValue[1].Amount)
+
+	assert.Equal(t, TRANSACTION_DB_TYPE_EXPENSE, actualValue[2].Type)
+	assert.Equal(t, int64(100), actualValue[2].CategoryId)
+	assert.Equal(t, int64(2000), actualValue[2].AccountId)
+	assert.Equal(t, int64(-2000), actualValue[2].Amount)
+
+	assert.Equal(t, int64(3), actualValue[3].TransactionId)
+	assert.Equal(t, int64(800), actualValue[3].Amount)
+
+	assert.Equal(t, int64(3000), transactions[1].Amount)
+}
+
+func TestGetTransactionsWithNettedRefunds_FullRefundOfSplitIncome(t *testing.T) {
+	transactions := []*Transaction{
+		{TransactionId: 2, Type: TRANSACTION_DB_TYPE_INCOME, CategoryId: 201, AccountId: 2000, Amount: 600},
+		{TransactionId: 2, Type: TRANSACTION_DB_TYPE_INCOME, CategoryId: 202, AccountId: 2000, Amount: 400},
+	}
+
+	refunds := []*TransactionRefund{
+		{ExpenseTransactionId: 1, IncomeTransactionId: 2, Amount: 800},
+	}
+
+	refundedTransactions := map[int64]*Transaction{
+		1: {TransactionId: 1, CategoryId: 100},
+	}
+
+	actualValue := GetTransactionsWithNettedRefunds(transactions, refunds, refundedTransactions, nil)
+	assert.Equal(t, 2, len(actualValue))
+
+	assert.Equal(t, TRANSACTION_DB_TYPE_EXPENSE, actualValue[0].Type)
+	assert.Equal(t, int64(100), actualValue[0].CategoryId)
+	assert.Equal(t, int64(-800), actualValue[0].Amount)
+
+	assert.Equal(t, TRANSACTION_DB_TYPE_INCOME, actualValue[1].Type)
+	assert.Equal(t, int64(202), actualValue[1].CategoryId)
+	assert.Equal(t, int64(200), actualValue[1].Amount)
+}
+
+func TestGetTransactionsWithNettedRefunds_RefundedTransactionNotExists(t *testing.T) {
+	transactions := []*Transaction{
+		{TransactionId: 2, Type: TRANSACTION_DB_TYPE_INCOME, CategoryId: 200, AccountId: 2000, Amount: 3000},
+	}
+
+	refunds := []*TransactionRefund{
+		{ExpenseTransactionId: 1, IncomeTransactionId: 2, Amount: 2000},
+	}
+
+	actualValue := GetTransactionsWithNettedRefunds(transactions, refunds, map[int64]*Transaction{}, nil)
+	assert.Equal(t, 1, len(actualValue))
+	assert.Equal(t, int64(3000), actualValue[0].Amount)
+}
+
+func TestGetTransactionRefundCategoryAmounts_NotSplit(t *testing.T) {
+	refundedTransaction := &Transaction{TransactionId: 1, CategoryId: 100, Amount: 5000}
+
+	actualValue := GetTransactionRefundCategoryAmounts(2000, refundedTransaction, nil)
+	assert.Equal(t, 1, len(actualValue))
+	assert.Equal(t, int64(100), actualValue[0].CategoryId)
+	assert.Equal(t, int64(2000), actualValue[0].Amount)
+}
+
+func TestGetTransactionRefundCategoryAmounts_Split(t *testing.T) {
+	refundedTransaction := &Transaction{TransactionId: 1, CategoryId: 101, Amount: 3000}
+	splits := []*TransactionSplit{
+		{TransactionId: 1, CategoryId: 101, Amount: 1000},
+		{TransactionId: 1, CategoryId: 102, Amount: 1000},
+		{TransactionId: 1, CategoryId: 103, Amount: 1000},
+	}
+
+	actualValue := GetTransactionRefundCategoryAmounts(1000, refundedTransaction, splits)
+	assert.Equal(t, 3, len(actualValue))
+	assert.Equal(t, int64(101), actualValue[0].CategoryId)
+	assert.Equal(t, int64(333), actualValue[0].Amount)
+	assert.Equal(t, int64(102), actualValue[1].CategoryId)
+	assert.Equal(t, int64(333), actualValue[1].Amount)
+	assert.Equal(t, int64(103), actualValue[2].CategoryId)
+	assert.Equal(t, int64(334), actualValue[2].Amount)
+}
+
+func TestGetTransactionsWithNettedRefunds_SplitRefundedTransaction(t *testing.T) {
+	transactions := []*Transaction{
+		{TransactionId: 2, Type: TRANSACTION_DB_TYPE_INCOME, CategoryId: 200, AccountId: 2000, Amount: 500},
+	}
+
+	refunds := []*TransactionRefund{
+		{ExpenseTransactionId: 1, IncomeTransactionId: 2, Amount: 500},
+	}
+
+	refundedTransactions := map[int64]*Transaction{
+		1: {TransactionId: 1, CategoryId: 101, Amount: 1000},
+	}
+
+	refundedTransactionSplits := map[int64][]*TransactionSplit{
+		1: {
+			{TransactionId: 1, CategoryId: 101, Amount: 600},
+			{TransactionId: 1, CategoryId: 102, Amount: 400},
+		},
+	}
+
+	actualValue := GetTransactionsWithNettedRefunds(transactions, refunds, refundedTransactions, refundedTransactionSplits)
+	assert.Equal(t, 2, len(actualValue))
+
+	assert.Equal(t, TRANSACTION_DB_TYPE_EXPENSE, actualValue[0].Type)
+	assert.Equal(t, int64(101), actualValue[0].CategoryId)
+	assert.Equal(t, int64(-300), actualValue[0].Amount)
+
+	assert.Equal(t, TRANSACTION_DB_TYPE_EXPENSE, actualValue[1].Type)
+	assert.Equal(t, int64(102), actualValue[1].CategoryId)
+	assert.Equal(t, int64(-200), actualValue[1].Amount)
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MaximumSplitsCountOfTransaction is the maximum count of split lines of one transaction
+const MaximumSplitsCountOfTransaction = 50
+
+// TransactionSplit represents a split line of an income or expense transaction stored in database,
+// each split line carries its own category, amount, tags and comment, and the sum of all split line amounts equals the transaction amount
+type TransactionSplit struct {
+	SplitId         int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) INDEX(IDX_transaction_split_uid_deleted_transaction_time) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) INDEX(IDX_transaction_split_uid_deleted_transaction_time) NOT NULL"`
+	TransactionId   int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) NOT NULL"`
+	TransactionTime int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_time) NOT NULL"`
+	CategoryId      int64  `xorm:"NOT NULL"`
+	Amount          int64  `xorm:"NOT NULL"`
+	TagIds          string `xorm:"VARCHAR(255) NOT NULL"`
+	Comment         string `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder    int32  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// TransactionSplitCreateRequest represents all parameters of a split line in transaction creation or modification request
+type TransactionSplitCreateRequest struct {
+	CategoryId int64    `json:"categoryId,string" binding:"required,min=1"`
+	Amount     int64    `json:"amount" binding:"min=-9223372036854775808,max=9223372036854775807"`
+	TagIds     []string `json:"tagIds"`
+	Comment    string   `json:"comment" binding:"max=255"`
+}
+
+// TransactionSplitInfoResponse represents a view-object of transaction split line
+type TransactionSplitInfoResponse struct {
+	Id         int64    `json:"id,string"`
+	CategoryId int64    `json:"categoryId,string"`
+	Amount     int64    `json:"amount"`
+	TagIds     []string `json:"tagIds"`
+	Comment    string   `json:"comment"`
+}
+
+// GetTagIds returns all tag ids of the transaction split line
+func (s *TransactionSplit) GetTagIds() []int64 {
+	tagIds := make([]string, 0)
+
+	if s.TagIds != "" {
+		tagIds = strings.Split(s.TagIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(tagIds)
+
+	return result
+}
+
+// ToTransactionSplitInfoResponse returns a view-object according to database model
+func (s *TransactionSplit) ToTransactionSplitInfoResponse() *TransactionSplitInfoResponse {
+	return &TransactionSplitInfoResponse{
+		Id:         s.SplitId,
+		CategoryId: s.CategoryId,
+		Amount:     s.Amount,
+		TagIds:     utils.Int64ArrayToStringArray(s.GetTagIds()),
+		Comment:    s.Comment,
+	}
+}
+
+// GetTransactionSplitsTotalAmount returns the sum of the amounts of all the split lines
+func GetTransactionSplitsTotalAmount(splits []*TransactionSplit) int64 {
+	totalAmount := int64(0)
+
+	for i := 0; i < len(splits); i++ {
+		totalAmount += splits[i].Amount
+	}
+
+	return totalAmount
+}
+
+// GetTransactionSplitInfoResponses returns the view-objects of the split lines
+func GetTransactionSplitInfoResponses(splits []*TransactionSplit) []*TransactionSplitInfoResponse {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	responses := make([]*TransactionSplitInfoResponse, len(splits))
+
+	for i := 0; i < len(splits); i++ {
+		responses[i] = splits[i].ToTransactionSplitInfoResponse()
+	}
+
+	return responses
+}
@@ -0,0 +1,15 @@
+package models
+
+// TransactionSplitTagIndex represents transaction split line and transaction tag relation stored in database
+type TransactionSplitTagIndex struct {
+	TagIndexId      int64 `xorm:"PK"`
+	Uid             int64 `xorm:"INDEX(IDX_transaction_split_tag_index_uid_deleted_tag_id_transaction_id) INDEX(IDX_transaction_split_tag_index_uid_deleted_transaction_time_tag_id) INDEX(IDX_transaction_split_tag_index_uid_deleted_transaction_id)"`
+	Deleted         bool  `xorm:"INDEX(IDX_transaction_split_tag_index_uid_deleted_tag_id_transaction_id) INDEX(IDX_transaction_split_tag_index_uid_deleted_transaction_time_tag_id) INDEX(IDX_transaction_split_tag_index_uid_deleted_transaction_id) NOT NULL"`
+	TransactionTime int64 `xorm:"INDEX(IDX_transaction_split_tag_index_uid_deleted_transaction_time_tag_id) NOT NULL"`
+	TagId           int64 `xorm:"INDEX(IDX_transaction_split_tag_index_uid_deleted_tag_id_transaction_id) INDEX(IDX_transaction_split_tag_index_uid_deleted_transaction_time_tag_id)"`
+	TransactionId   int64 `xorm:"INDEX(IDX_transaction_split_tag_index_uid_deleted_tag_id_transaction_id) INDEX(IDX_transaction_split_tag_index_uid_deleted_transaction_id)"`
+	SplitId         int64 `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionSplitGetTagIds(t *testing.T) {
+	split := &TransactionSplit{
+		TagIds: "1,2,3",
+	}
+
+	expectedValue := []int64{1, 2, 3}
+	assert.EqualValues(t, expectedValue, split.GetTagIds())
+}
+
+func TestTransactionSplitGetTagIds_EmptyTagIds(t *testing.T) {
+	split := &TransactionSplit{
+		TagIds: "",
+	}
+
+	assert.Equal(t, 0, len(split.GetTagIds()))
+}
+
+func TestGetTransactionSplitsTotalAmount(t *testing.T) {
+	splits := []*TransactionSplit{
+		{Amount: 1800},
+		{Amount: 1200},
+		{Amount: -100},
+	}
+
+	assert.Equal(t, int64(2900), GetTransactionSplitsTotalAmount(splits))
+	assert.Equal(t, int64(0), GetTransactionSplitsTotalAmount(nil))
+}
+
+func TestGetTransactionSplitInfoResponses(t *testing.T) {
+	splits := []*TransactionSplit{
+		{SplitId: 1, CategoryId: 2, Amount: 1800, TagIds: "3,4", Comment: "Food"},
+		{SplitId: 5, CategoryId: 6, Amount: 1200},
+	}
+
+	responses := GetTransactionSplitInfoResponses(splits)
+	assert.Equal(t, 2, len(responses))
+	assert.Equal(t, int64(1), responses[0].Id)
+	assert.Equal(t, int64(2), responses[0].CategoryId)
+	assert.Equal(t, int64(1800), responses[0].Amount)
+	assert.EqualValues(t, []string{"3", "4"}, responses[0].TagIds)
+	assert.Equal(t, "Food", responses[0].Comment)
+	assert.Equal(t, int64(5), responses[1].Id)
+	assert.Equal(t, 0, len(responses[1].TagIds))
+
+	assert.Nil(t, GetTransactionSplitInfoResponses(nil))
+}
@@ -314,6 +314,33 @@ func TestTransactionStatisticAssetTrendsResponseItemSliceLess(t *testing.T) {
 	assert.Equal(t, int32(1), transactionTrendsSlice[5].Day)
 }
 
+func TestTransactionStatisticHoldingIncomeResponseHoldingItemSliceLess(t *testing.T) {
+	var holdingItemSlice TransactionStatisticHoldingIncomeResponseHoldingItemSlice
+	holdingItemSlice = append(holdingItemSlice, &TransactionStatisticHoldingIncomeResponseHoldingItem{
+		HoldingAccountId: 1,
+		TotalIncome:      100,
+	})
+	holdingItemSlice = append(holdingItemSlice, &TransactionStatisticHoldingIncomeResponseHoldingItem{
+		HoldingAccountId: 2,
+		TotalIncome:      300,
+	})
+	holdingItemSlice = append(holdingItemSlice, &TransactionStatisticHoldingIncomeResponseHoldingItem{
+		HoldingAccountId: 4,
+		TotalIncome:      200,
+	})
+	holdingItemSlice = append(holdingItemSlice, &TransactionStatisticHoldingIncomeResponseHoldingItem{
+		HoldingAccountId: 3,
+		TotalIncome:      200,
+	})
+
+	sort.Sort(holdingItemSlice)
+
+	assert.Equal(t, int64(2), holdingItemSlice[0].HoldingAccountId)
+	assert.Equal(t, int64(3), holdingItemSlice[1].HoldingAccountId)
+	assert.Equal(t, int64(4), holdingItemSlice[2].HoldingAccountId)
+	assert.Equal(t, int64(1), holdingItemSlice[3].HoldingAccountId)
+}
+
 func TestTransactionAmountsResponseItemAmountInfoSliceLess(t *testing.T) {
 	var amountInfoSlice TransactionAmountsResponseItemAmountInfoSlice
 	amountInfoSlice = append(amountInfoSlice, &TransactionAmountsResponseItemAmountInfo{
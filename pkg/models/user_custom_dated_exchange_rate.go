@@ -0,0 +1,131 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// UserCustomDatedExchangeRate represents a user custom exchange rate which is only effective in the specified time range stored in database,
+// the rate is the amount of the currency per one base currency, and the effective end time is inclusive (zero means open-ended)
+type UserCustomDatedExchangeRate struct {
+	RateId             int64  `xorm:"PK"`
+	Uid                int64  `xorm:"INDEX(IDX_user_custom_dated_exchange_rate_uid_deleted_currency) NOT NULL"`
+	Deleted            bool   `xorm:"INDEX(IDX_user_custom_dated_exchange_rate_uid_deleted_currency) NOT NULL"`
+	Currency           string `xorm:"INDEX(IDX_user_custom_dated_exchange_rate_uid_deleted_currency) VARCHAR(10) NOT NULL"`
+	BaseCurrency       string `xorm:"VARCHAR(10) NOT NULL"`
+	Rate               string `xorm:"VARCHAR(32) NOT NULL"`
+	EffectiveStartTime int64  `xorm:"NOT NULL"`
+	EffectiveEndTime   int64  `xorm:"NOT NULL"`
+	CreatedUnixTime    int64
+	UpdatedUnixTime    int64
+	DeletedUnixTime    int64
+}
+
+// UserCustomDatedExchangeRateListRequest represents all parameters of user custom dated exchange rate listing request
+type UserCustomDatedExchangeRateListRequest struct {
+	Currency string `form:"currency" binding:"max=10"`
+}
+
+// UserCustomDatedExchangeRateCreateRequest represents all parameters of user custom dated exchange rate creation request
+type UserCustomDatedExchangeRateCreateRequest struct {
+	Currency           string `json:"currency" binding:"required,min=1,max=10,validCurrency"`
+	Rate               string `json:"rate" binding:"required,notBlank,max=32"`
+	EffectiveStartTime int64  `json:"effectiveStartTime" binding:"required,min=1"`
+	EffectiveEndTime   int64  `json:"effectiveEndTime" binding:"min=0"`
+}
+
+// UserCustomDatedExchangeRateDeleteRequest represents all parameters of user custom dated exchange rate deleting request
+type UserCustomDatedExchangeRateDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// UserCustomDatedExchangeRateInfoResponse represents a view-object of user custom dated exchange rate
+type UserCustomDatedExchangeRateInfoResponse struct {
+	Id                 int64  `json:"id,string"`
+	Currency           string `json:"currency"`
+	BaseCurrency       string `json:"baseCurrency"`
+	Rate               string `json:"rate"`
+	EffectiveStartTime int64  `json:"effectiveStartTime"`
+	EffectiveEndTime   int64  `json:"effectiveEndTime,omitempty"`
+	UpdateTime         int64  `json:"updateTime"`
+}
+
+// IsEffectiveAt returns whether the exchange rate is effective at the specified unix time
+func (r *UserCustomDatedExchangeRate) IsEffectiveAt(unixTime int64) bool {
+	return r.EffectiveStartTime <= unixTime && (r.EffectiveEndTime == 0 || unixTime <= r.EffectiveEndTime)
+}
+
+// ToLatestExchangeRate returns a data pair of currency and exchange rate according to database model
+func (r *UserCustomDatedExchangeRate) ToLatestExchangeRate() *LatestExchangeRate {
+	return &LatestExchangeRate{
+		Currency: r.Currency,
+		Rate:     r.Rate,
+	}
+}
+
+// ToUserCustomDatedExchangeRateInfoResponse returns a view-object according to database model
+func (r *UserCustomDatedExchangeRate) ToUserCustomDatedExchangeRateInfoResponse() *UserCustomDatedExchangeRateInfoResponse {
+	return &UserCustomDatedExchangeRateInfoResponse{
+		Id:                 r.RateId,
+		Currency:           r.Currency,
+		BaseCurrency:       r.BaseCurrency,
+		Rate:               r.Rate,
+		EffectiveStartTime: r.EffectiveStartTime,
+		EffectiveEndTime:   r.EffectiveEndTime,
+		UpdateTime:         r.UpdatedUnixTime,
+	}
+}
+
+// GetUserCustomDatedExchangeRateHistories returns the daily exchange rate histories of the specified currency according to the dated exchange rates,
+// the rate of each dated exchange rate is effective from the date of its start time, and the latest rate is restored after its end time
+// and is also effective before the first dated exchange rate (with the rate date zero).
+// The dated exchange rates must not overlap, and the dated exchange rates of other currencies or other base currencies are ignored
+func GetUserCustomDatedExchangeRateHistories(datedExchangeRates []*UserCustomDatedExchangeRate, currency string, baseCurrency string, latestRate float64) []*HistoricalExchangeRate {
+	rates := make([]*UserCustomDatedExchangeRate, 0, len(datedExchangeRates))
+
+	for i := 0; i < len(datedExchangeRates); i++ {
+		if datedExchangeRates[i].Currency == currency && datedExchangeRates[i].BaseCurrency == baseCurrency {
+			rates = append(rates, datedExchangeRates[i])
+		}
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].EffectiveStartTime < rates[j].EffectiveStartTime
+	})
+
+	histories := make([]*HistoricalExchangeRate, 0, len(rates)*2)
+
+	for i := 0; i < len(rates); i++ {
+		rate, err := utils.StringToFloat64(rates[i].Rate)
+
+		if err != nil || rate <= 0 {
+			continue
+		}
+
+		startDate := utils.FormatUnixTimeToNumericYearMonthDay(rates[i].EffectiveStartTime, time.UTC)
+
+		if len(histories) > 0 && histories[len(histories)-1].RateDate == startDate {
+			histories[len(histories)-1].Rate = rate
+		} else {
+			histories = append(histories, &HistoricalExchangeRate{
+				Rate:     rate,
+				RateDate: startDate,
+			})
+		}
+
+		if rates[i].EffectiveEndTime > 0 && latestRate > 0 {
+			histories = append(histories, &HistoricalExchangeRate{
+				Rate:     latestRate,
+				RateDate: utils.FormatUnixTimeToNumericYearMonthDay(rates[i].EffectiveEndTime+1, time.UTC),
+			})
+		}
+	}
+
+	if len(histories) > 0 && latestRate > 0 {
+		histories = append([]*HistoricalExchangeRate{{Rate: latestRate, RateDate: 0}}, histories...)
+	}
+
+	return histories
+}
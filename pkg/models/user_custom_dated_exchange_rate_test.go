@@ -0,0 +1,87 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserCustomDatedExchangeRateIsEffectiveAt(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rate     *UserCustomDatedExchangeRate
+		unixTime int64
+		expected bool
+	}{
+		{"before start time", &UserCustomDatedExchangeRate{EffectiveStartTime: 1704067200, EffectiveEndTime: 1704153599}, 1704067199, false},
+		{"at start time", &UserCustomDatedExchangeRate{EffectiveStartTime: 1704067200, EffectiveEndTime: 1704153599}, 1704067200, true},
+		{"at end time", &UserCustomDatedExchangeRate{EffectiveStartTime: 1704067200, EffectiveEndTime: 1704153599}, 1704153599, true},
+		{"after end time", &UserCustomDatedExchangeRate{EffectiveStartTime: 1704067200, EffectiveEndTime: 1704153599}, 1704153600, false},
+		{"open-ended", &UserCustomDatedExchangeRate{EffectiveStartTime: 1704067200}, 1893456000, true},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tc.rate.IsEffectiveAt(tc.unixTime), tc.name)
+	}
+}
+
+func TestGetUserCustomDatedExchangeRateHistories(t *testing.T) {
+	datedExchangeRates := []*UserCustomDatedExchangeRate{
+		{Currency: "USD", BaseCurrency: "CNY", Rate: "0.14", EffectiveStartTime: 1704844800},                               // 2024-01-10
+		{Currency: "USD", BaseCurrency: "CNY", Rate: "0.13", EffectiveStartTime: 1704067200, EffectiveEndTime: 1704326399}, // 2024-01-01 ~ 2024-01-03
+		{Currency: "EUR", BaseCurrency: "CNY", Rate: "0.12", EffectiveStartTime: 1704067200},
+		{Currency: "USD", BaseCurrency: "EUR", Rate: "1.1", EffectiveStartTime: 1704067200},
+	}
+
+	histories := GetUserCustomDatedExchangeRateHistories(datedExchangeRates, "USD", "CNY", 0.15)
+	assert.Equal(t, []*HistoricalExchangeRate{
+		{Rate: 0.15, RateDate: 0},
+		{Rate: 0.13, RateDate: 20240101},
+		{Rate: 0.15, RateDate: 20240104},
+		{Rate: 0.14, RateDate: 20240110},
+	}, histories)
+
+	valuator := NewAssetValuator("CNY", map[string]float64{"CNY": 1, "USD": 0.15})
+	valuator.SetExchangeRateHistories("USD", histories)
+
+	testCases := []struct {
+		date     int32
+		expected float64
+	}{
+		{20231231, 0.15},
+		{20240102, 0.13},
+		{20240105, 0.15},
+		{20240201, 0.14},
+	}
+
+	for _, tc := range testCases {
+		rate, exists := valuator.GetExchangeRate("USD", tc.date)
+		assert.True(t, exists)
+		assert.Equal(t, tc.expected, rate, tc.date)
+	}
+}
+
+func TestGetUserCustomDatedExchangeRateHistories_AdjacentRanges(t *testing.T) {
+	datedExchangeRates := []*UserCustomDatedExchangeRate{
+		{Currency: "USD", BaseCurrency: "CNY", Rate: "0.13", EffectiveStartTime: 1704067200, EffectiveEndTime: 1704153599}, // 2024-01-01
+		{Currency: "USD", BaseCurrency: "CNY", Rate: "0.14", EffectiveStartTime: 1704153600, EffectiveEndTime: 1704239999}, // 2024-01-02
+	}
+
+	histories := GetUserCustomDatedExchangeRateHistories(datedExchangeRates, "USD", "CNY", 0.15)
+	assert.Equal(t, []*HistoricalExchangeRate{
+		{Rate: 0.15, RateDate: 0},
+		{Rate: 0.13, RateDate: 20240101},
+		{Rate: 0.14, RateDate: 20240102},
+		{Rate: 0.15, RateDate: 20240103},
+	}, histories)
+}
+
+func TestGetUserCustomDatedExchangeRateHistories_InvalidRate(t *testing.T) {
+	datedExchangeRates := []*UserCustomDatedExchangeRate{
+		{Currency: "USD", BaseCurrency: "CNY", Rate: "null", EffectiveStartTime: 1704067200},
+		{Currency: "USD", BaseCurrency: "CNY", Rate: "0", EffectiveStartTime: 1704153600},
+	}
+
+	histories := GetUserCustomDatedExchangeRateHistories(datedExchangeRates, "USD", "CNY", 0.15)
+	assert.Len(t, histories, 0)
+}
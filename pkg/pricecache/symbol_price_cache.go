@@ -0,0 +1,204 @@
+package pricecache
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+)
+
+const defaultFetchBatchSize = 50
+
+// staleEntryTimeoutMultiple is the multiple of cache timeout after which the stale entries would be no longer returned and be removed
+const staleEntryTimeoutMultiple = 12
+
+// CachedPrice represents the latest price of one symbol returned by a price data source
+type CachedPrice struct {
+	Symbol       string
+	Price        string
+	Currency     string
+	DataSource   string
+	ReferenceUrl string
+	UpdateTime   int64
+}
+
+// PriceFetchFunc fetches the latest prices of given symbols, the symbols which are not returned are treated as not found
+type PriceFetchFunc func(symbols []string) ([]*CachedPrice, error)
+
+type cacheEntry struct {
+	price       *CachedPrice
+	fetchedTime time.Time
+}
+
+// SymbolPriceCache represents a price cache whose entries are stored and expired per symbol
+type SymbolPriceCache struct {
+	name         string
+	timeout      time.Duration
+	staleTimeout time.Duration
+	batchSize    int
+	entries      map[string]*cacheEntry
+	mu           sync.RWMutex
+	requestGroup singleflight.Group
+}
+
+// NewSymbolPriceCache returns a new symbol price cache
+func NewSymbolPriceCache(name string, timeout time.Duration, batchSize int) *SymbolPriceCache {
+	if batchSize <= 0 {
+		batchSize = defaultFetchBatchSize
+	}
+
+	return &SymbolPriceCache{
+		name:         name,
+		timeout:      timeout,
+		staleTimeout: timeout * staleEntryTimeoutMultiple,
+		batchSize:    batchSize,
+		entries:      make(map[string]*cacheEntry),
+	}
+}
+
+// GetPrices returns the prices of given symbols, only the symbols which are not cached or expired would be fetched in batches,
+// and the stale prices which are not too old would be returned when fetching failed.
+// The prices are cached separately for each data source and quote currency
+func (c *SymbolPriceCache) GetPrices(ctx core.Context, dataSource string, quoteCurrency string, symbols []string, fetch PriceFetchFunc) ([]*CachedPrice, error) {
+	normalizedSymbols := make([]string, 0, len(symbols))
+	symbolExists := make(map[string]bool, len(symbols))
+
+	for i := 0; i < len(symbols); i++ {
+		symbol := strings.ToUpper(strings.TrimSpace(symbols[i]))
+
+		if symbol == "" || symbolExists[symbol] {
+			continue
+		}
+
+		symbolExists[symbol] = true
+		normalizedSymbols = append(normalizedSymbols, symbol)
+	}
+
+	missingSymbols := make([]string, 0, len(normalizedSymbols))
+	now := time.Now()
+
+	c.mu.RLock()
+
+	for i := 0; i < len(normalizedSymbols); i++ {
+		entry, exists := c.entries[c.getCacheKey(dataSource, quoteCurrency, normalizedSymbols[i])]
+
+		if !exists || now.Sub(entry.fetchedTime) >= c.timeout {
+			missingSymbols = append(missingSymbols, normalizedSymbols[i])
+		}
+	}
+
+	c.mu.RUnlock()
+
+	var lastErr error
+	failedBatchCount := 0
+	batchCount := 0
+
+	for start := 0; start < len(missingSymbols); start += c.batchSize {
+		end := start + c.batchSize
+
+		if end > len(missingSymbols) {
+			end = len(missingSymbols)
+		}
+
+		batch := missingSymbols[start:end]
+		batchCount++
+
+		_, err, _ := c.requestGroup.Do(c.getCacheKey(dataSource, quoteCurrency, strings.Join(batch, ",")), func() (any, error) {
+			prices, err := fetch(batch)
+
+			if err != nil {
+				return nil, err
+			}
+
+			c.savePrices(dataSource, quoteCurrency, batch, prices)
+			return nil, nil
+		})
+
+		if err != nil {
+			log.Warnf(ctx, "[pricecache.GetPrices] failed to get latest %s prices of \"%s\" from \"%s\", because %s", c.name, strings.Join(batch, ","), dataSource, err.Error())
+			lastErr = err
+			failedBatchCount++
+		}
+	}
+
+	result := make([]*CachedPrice, 0, len(normalizedSymbols))
+	hasStaleEntry := false
+	now = time.Now()
+
+	c.mu.RLock()
+
+	for i := 0; i < len(normalizedSymbols); i++ {
+		entry, exists := c.entries[c.getCacheKey(dataSource, quoteCurrency, normalizedSymbols[i])]
+
+		if !exists || now.Sub(entry.fetchedTime) >= c.staleTimeout {
+			continue
+		}
+
+		if now.Sub(entry.fetchedTime) >= c.timeout {
+			hasStaleEntry = true
+		}
+
+		if entry.price != nil {
+			result = append(result, entry.price)
+		}
+	}
+
+	c.mu.RUnlock()
+
+	if batchCount > 0 && failedBatchCount == batchCount && len(result) < 1 {
+		return nil, lastErr
+	}
+
+	if hasStaleEntry {
+		log.Warnf(ctx, "[pricecache.GetPrices] some latest %s prices from \"%s\" are stale", c.name, dataSource)
+	}
+
+	return result, nil
+}
+
+// Clear removes all cached prices
+func (c *SymbolPriceCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*cacheEntry)
+}
+
+func (c *SymbolPriceCache) savePrices(dataSource string, quoteCurrency string, requestedSymbols []string, prices []*CachedPrice) {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if now.Sub(entry.fetchedTime) >= c.staleTimeout {
+			delete(c.entries, key)
+		}
+	}
+
+	// cache the symbols not returned by data source as well, to avoid requesting them again before expired
+	for i := 0; i < len(requestedSymbols); i++ {
+		c.entries[c.getCacheKey(dataSource, quoteCurrency, requestedSymbols[i])] = &cacheEntry{
+			fetchedTime: now,
+		}
+	}
+
+	for i := 0; i < len(prices); i++ {
+		if prices[i] == nil || prices[i].Symbol == "" {
+			continue
+		}
+
+		c.entries[c.getCacheKey(dataSource, quoteCurrency, prices[i].Symbol)] = &cacheEntry{
+			price:       prices[i],
+			fetchedTime: now,
+		}
+	}
+}
+
+func (c *SymbolPriceCache) getCacheKey(dataSource string, quoteCurrency string, symbol string) string {
+	return dataSource + ":" + strings.ToUpper(quoteCurrency) + ":" + strings.ToUpper(symbol)
+}
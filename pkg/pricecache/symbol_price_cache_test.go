@@ -0,0 +1,143 @@
+package pricecache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func getTestPriceFetchFunc(requestedSymbols *[][]string, err error) PriceFetchFunc {
+	return func(symbols []string) ([]*CachedPrice, error) {
+		*requestedSymbols = append(*requestedSymbols, symbols)
+
+		if err != nil {
+			return nil, err
+		}
+
+		prices := make([]*CachedPrice, 0, len(symbols))
+
+		for i := 0; i < len(symbols); i++ {
+			if symbols[i] == "UNKNOWN" {
+				continue
+			}
+
+			prices = append(prices, &CachedPrice{
+				Symbol:   symbols[i],
+				Price:    "1.23",
+				Currency: "USD",
+			})
+		}
+
+		return prices, nil
+	}
+}
+
+func TestSymbolPriceCacheGetPrices_FetchOnlyMissingSymbols(t *testing.T) {
+	context := core.NewNullContext()
+	cache := NewSymbolPriceCache("test", time.Minute, 10)
+	var requestedSymbols [][]string
+
+	prices, err := cache.GetPrices(context, "source", "USD", []string{"aapl", "MSFT", "AAPL"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(prices))
+	assert.Equal(t, [][]string{{"AAPL", "MSFT"}}, requestedSymbols)
+
+	prices, err = cache.GetPrices(context, "source", "USD", []string{"AAPL", "GOOG", "UNKNOWN"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(prices))
+	assert.Equal(t, [][]string{{"AAPL", "MSFT"}, {"GOOG", "UNKNOWN"}}, requestedSymbols)
+
+	prices, err = cache.GetPrices(context, "source", "USD", []string{"UNKNOWN", "MSFT"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(prices))
+	assert.Equal(t, "MSFT", prices[0].Symbol)
+	assert.Equal(t, 2, len(requestedSymbols))
+
+	_, err = cache.GetPrices(context, "another_source", "USD", []string{"MSFT"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"MSFT"}, requestedSymbols[2])
+}
+
+func TestSymbolPriceCacheGetPrices_FetchInBatches(t *testing.T) {
+	context := core.NewNullContext()
+	cache := NewSymbolPriceCache("test", time.Minute, 2)
+	var requestedSymbols [][]string
+
+	prices, err := cache.GetPrices(context, "source", "USD", []string{"A", "B", "C", "D", "E"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(prices))
+	assert.Equal(t, [][]string{{"A", "B"}, {"C", "D"}, {"E"}}, requestedSymbols)
+}
+
+func TestSymbolPriceCacheGetPrices_RefetchExpiredSymbols(t *testing.T) {
+	context := core.NewNullContext()
+	cache := NewSymbolPriceCache("test", time.Minute, 10)
+	var requestedSymbols [][]string
+
+	_, err := cache.GetPrices(context, "source", "USD", []string{"AAPL", "MSFT"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+
+	cache.entries[cache.getCacheKey("source", "USD", "AAPL")].fetchedTime = time.Now().Add(-2 * time.Minute)
+
+	_, err = cache.GetPrices(context, "source", "USD", []string{"AAPL", "MSFT"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"AAPL", "MSFT"}, {"AAPL"}}, requestedSymbols)
+}
+
+func TestSymbolPriceCacheGetPrices_ReturnStalePricesWhenFetchFailed(t *testing.T) {
+	context := core.NewNullContext()
+	cache := NewSymbolPriceCache("test", time.Minute, 10)
+	var requestedSymbols [][]string
+
+	_, err := cache.GetPrices(context, "source", "USD", []string{"AAPL"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+
+	cache.entries[cache.getCacheKey("source", "USD", "AAPL")].fetchedTime = time.Now().Add(-2 * time.Minute)
+
+	prices, err := cache.GetPrices(context, "source", "USD", []string{"AAPL"}, getTestPriceFetchFunc(&requestedSymbols, errs.ErrFailedToRequestRemoteApi))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(prices))
+	assert.Equal(t, "AAPL", prices[0].Symbol)
+
+	_, err = cache.GetPrices(context, "source", "USD", []string{"MSFT"}, getTestPriceFetchFunc(&requestedSymbols, errs.ErrFailedToRequestRemoteApi))
+	assert.Equal(t, errs.ErrFailedToRequestRemoteApi, err)
+}
+
+func TestSymbolPriceCacheGetPrices_CachePricesPerQuoteCurrency(t *testing.T) {
+	context := core.NewNullContext()
+	cache := NewSymbolPriceCache("test", time.Minute, 10)
+	var requestedSymbols [][]string
+
+	_, err := cache.GetPrices(context, "source", "USD", []string{"AAPL"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+
+	_, err = cache.GetPrices(context, "source", "EUR", []string{"AAPL"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+
+	_, err = cache.GetPrices(context, "source", "usd", []string{"AAPL"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"AAPL"}, {"AAPL"}}, requestedSymbols)
+}
+
+func TestSymbolPriceCacheGetPrices_NotReturnExpiredStalePrices(t *testing.T) {
+	context := core.NewNullContext()
+	cache := NewSymbolPriceCache("test", time.Minute, 10)
+	var requestedSymbols [][]string
+
+	_, err := cache.GetPrices(context, "source", "USD", []string{"AAPL", "MSFT"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+
+	cache.entries[cache.getCacheKey("source", "USD", "AAPL")].fetchedTime = time.Now().Add(-staleEntryTimeoutMultiple * time.Minute)
+	cache.entries[cache.getCacheKey("source", "USD", "MSFT")].fetchedTime = time.Now().Add(-staleEntryTimeoutMultiple * time.Minute)
+
+	_, err = cache.GetPrices(context, "source", "USD", []string{"AAPL", "MSFT"}, getTestPriceFetchFunc(&requestedSymbols, errs.ErrFailedToRequestRemoteApi))
+	assert.Equal(t, errs.ErrFailedToRequestRemoteApi, err)
+
+	_, err = cache.GetPrices(context, "source", "USD", []string{"GOOG"}, getTestPriceFetchFunc(&requestedSymbols, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(cache.entries))
+}
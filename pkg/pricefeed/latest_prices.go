@@ -0,0 +1,96 @@
+package pricefeed
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const defaultPriceFeedBaseCurrency = "USD"
+
+// PriceFeedLatestPrices represents the latest prices of the requested symbols in the price feed
+type PriceFeedLatestPrices struct {
+	BaseCurrency string
+	UpdateTime   int64
+	Prices       []*PriceFeedItem
+}
+
+// NewPriceFeedReader returns a new price feed reader of the location for the data source,
+// the request timeout and the proxy of the data source config would be used if they are set
+func NewPriceFeedReader(location string, config *models.ExternalDataSourceConfig, defaultRequestTimeout uint32, defaultProxy string, skipTLSVerify bool) *PriceFeedReader {
+	reader := &PriceFeedReader{
+		Location:       location,
+		RequestTimeout: uint32(config.RequestTimeout),
+		Proxy:          config.Proxy,
+		SkipTLSVerify:  skipTLSVerify,
+	}
+
+	if reader.RequestTimeout == 0 {
+		reader.RequestTimeout = defaultRequestTimeout
+	}
+
+	if reader.Proxy == "" {
+		reader.Proxy = defaultProxy
+	}
+
+	return reader
+}
+
+// ReadLatestPrices reads the prices of the requested symbols in the price feed, the prices without currency would be in the base currency of the data source config (or USD if not set),
+// and the prices whose currency is different from the base currency would be ignored if baseCurrencyOnly is true
+func (r *PriceFeedReader) ReadLatestPrices(c core.Context, config *models.ExternalDataSourceConfig, symbols []string, baseCurrencyOnly bool) (*PriceFeedLatestPrices, error) {
+	items, err := r.ReadPrices(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	baseCurrency := strings.ToUpper(config.BaseCurrency)
+
+	if baseCurrency == "" {
+		baseCurrency = defaultPriceFeedBaseCurrency
+	}
+
+	requestedSymbols := make(map[string]bool, len(symbols))
+
+	for i := 0; i < len(symbols); i++ {
+		requestedSymbols[strings.ToUpper(symbols[i])] = true
+	}
+
+	latestPrices := &PriceFeedLatestPrices{
+		BaseCurrency: baseCurrency,
+		Prices:       make([]*PriceFeedItem, 0, len(symbols)),
+	}
+
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+
+		if !requestedSymbols[item.Symbol] {
+			continue
+		}
+
+		currency := item.Currency
+
+		if currency == "" {
+			currency = baseCurrency
+		} else if baseCurrencyOnly && currency != baseCurrency {
+			log.Warnf(c, "[pricefeed.ReadLatestPrices] ignore price of \"%s\", because its currency \"%s\" is not base currency \"%s\"", item.Symbol, currency, baseCurrency)
+			continue
+		}
+
+		if item.UpdateTime > latestPrices.UpdateTime {
+			latestPrices.UpdateTime = item.UpdateTime
+		}
+
+		latestPrices.Prices = append(latestPrices.Prices, &PriceFeedItem{
+			Symbol:     item.Symbol,
+			Price:      item.Price,
+			Currency:   currency,
+			UpdateTime: item.UpdateTime,
+		})
+	}
+
+	return latestPrices, nil
+}
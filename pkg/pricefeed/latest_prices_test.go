@@ -0,0 +1,47 @@
+package pricefeed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestNewPriceFeedReader(t *testing.T) {
+	reader := NewPriceFeedReader("prices.csv", &models.ExternalDataSourceConfig{}, 10000, "system", true)
+	assert.Equal(t, "prices.csv", reader.Location)
+	assert.Equal(t, uint32(10000), reader.RequestTimeout)
+	assert.Equal(t, "system", reader.Proxy)
+	assert.True(t, reader.SkipTLSVerify)
+
+	reader = NewPriceFeedReader("prices.csv", &models.ExternalDataSourceConfig{RequestTimeout: 5000, Proxy: "none"}, 10000, "system", false)
+	assert.Equal(t, uint32(5000), reader.RequestTimeout)
+	assert.Equal(t, "none", reader.Proxy)
+	assert.False(t, reader.SkipTLSVerify)
+}
+
+func TestPriceFeedReaderReadLatestPrices_BaseCurrencyOnly(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "prices.csv")
+	assert.Nil(t, os.WriteFile(filePath, []byte("symbol,price,currency,update_time\nBTC,40000,,1700000000\nETH,2000,USD,1700000100\nSOL,60,EUR,1700000200\n"), 0644))
+
+	reader := &PriceFeedReader{Location: filePath}
+	latestPrices, err := reader.ReadLatestPrices(core.NewNullContext(), &models.ExternalDataSourceConfig{BaseCurrency: "eur"}, []string{"btc", "ETH", "SOL"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR", latestPrices.BaseCurrency)
+	assert.Equal(t, int64(1700000200), latestPrices.UpdateTime)
+	assert.Equal(t, 3, len(latestPrices.Prices))
+	assert.Equal(t, "EUR", latestPrices.Prices[0].Currency)
+	assert.Equal(t, "USD", latestPrices.Prices[1].Currency)
+	assert.Equal(t, "EUR", latestPrices.Prices[2].Currency)
+
+	latestPrices, err = reader.ReadLatestPrices(core.NewNullContext(), &models.ExternalDataSourceConfig{BaseCurrency: "eur"}, []string{"btc", "ETH", "SOL"}, true)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1700000200), latestPrices.UpdateTime)
+	assert.Equal(t, 2, len(latestPrices.Prices))
+	assert.Equal(t, "BTC", latestPrices.Prices[0].Symbol)
+	assert.Equal(t, "SOL", latestPrices.Prices[1].Symbol)
+}
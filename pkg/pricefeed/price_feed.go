@@ -0,0 +1,348 @@
+package pricefeed
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	csvColumnSymbol     = "symbol"
+	csvColumnPrice      = "price"
+	csvColumnCurrency   = "currency"
+	csvColumnUpdateTime = "update_time"
+)
+
+// PriceFeedItem represents the price of one symbol in the price feed
+type PriceFeedItem struct {
+	Symbol     string
+	Price      string
+	Currency   string
+	UpdateTime int64
+}
+
+// PriceFeedReader represents the reader which reads prices from a local file, a directory or an url
+type PriceFeedReader struct {
+	Location       string
+	RequestTimeout uint32
+	Proxy          string
+	SkipTLSVerify  bool
+}
+
+type jsonPriceFeedItem struct {
+	Symbol     string      `json:"symbol"`
+	Price      json.Number `json:"price"`
+	Currency   string      `json:"currency"`
+	UpdateTime int64       `json:"updateTime"`
+}
+
+type jsonPriceFeed struct {
+	Currency   string               `json:"currency"`
+	UpdateTime int64                `json:"updateTime"`
+	Prices     []*jsonPriceFeedItem `json:"prices"`
+}
+
+// ReadPrices reads all the prices in the price feed, the prices of the same symbol in later files would override the earlier ones
+func (r *PriceFeedReader) ReadPrices(c core.Context) ([]*PriceFeedItem, error) {
+	if r.Location == "" {
+		return nil, errs.ErrPriceFeedLocationNotSet
+	}
+
+	if strings.HasPrefix(r.Location, "http://") || strings.HasPrefix(r.Location, "https://") {
+		return r.readPricesFromUrl(c)
+	}
+
+	fileInfo, err := os.Stat(r.Location)
+
+	if err != nil {
+		log.Errorf(c, "[pricefeed.ReadPrices] failed to get file info of \"%s\", because %s", r.Location, err.Error())
+		return nil, errs.ErrFailedToReadPriceFeed
+	}
+
+	if !fileInfo.IsDir() {
+		return r.readPricesFromFile(c, r.Location, fileInfo)
+	}
+
+	entries, err := os.ReadDir(r.Location)
+
+	if err != nil {
+		log.Errorf(c, "[pricefeed.ReadPrices] failed to read directory \"%s\", because %s", r.Location, err.Error())
+		return nil, errs.ErrFailedToReadPriceFeed
+	}
+
+	fileNames := make([]string, 0, len(entries))
+
+	for i := 0; i < len(entries); i++ {
+		if entries[i].IsDir() {
+			continue
+		}
+
+		extension := strings.ToLower(filepath.Ext(entries[i].Name()))
+
+		if extension == ".csv" || extension == ".json" {
+			fileNames = append(fileNames, entries[i].Name())
+		}
+	}
+
+	sort.Strings(fileNames)
+
+	allItems := make([]*PriceFeedItem, 0)
+
+	for i := 0; i < len(fileNames); i++ {
+		filePath := filepath.Join(r.Location, fileNames[i])
+		fileInfo, err := os.Stat(filePath)
+
+		if err != nil {
+			log.Errorf(c, "[pricefeed.ReadPrices] failed to get file info of \"%s\", because %s", filePath, err.Error())
+			return nil, errs.ErrFailedToReadPriceFeed
+		}
+
+		items, err := r.readPricesFromFile(c, filePath, fileInfo)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allItems = append(allItems, items...)
+	}
+
+	return mergePriceFeedItems(allItems), nil
+}
+
+func (r *PriceFeedReader) readPricesFromFile(c core.Context, filePath string, fileInfo os.FileInfo) ([]*PriceFeedItem, error) {
+	content, err := os.ReadFile(filePath)
+
+	if err != nil {
+		log.Errorf(c, "[pricefeed.readPricesFromFile] failed to read file \"%s\", because %s", filePath, err.Error())
+		return nil, errs.ErrFailedToReadPriceFeed
+	}
+
+	items, err := ParsePrices(content, fileInfo.ModTime().Unix())
+
+	if err != nil {
+		log.Errorf(c, "[pricefeed.readPricesFromFile] failed to parse file \"%s\", because %s", filePath, err.Error())
+		return nil, errs.ErrInvalidPriceFeedContent
+	}
+
+	return items, nil
+}
+
+func (r *PriceFeedReader) readPricesFromUrl(c core.Context) ([]*PriceFeedItem, error) {
+	req, err := http.NewRequest("GET", r.Location, nil)
+
+	if err != nil {
+		log.Errorf(c, "[pricefeed.readPricesFromUrl] failed to create request, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	client := utils.NewHttpClient(r.RequestTimeout, r.Proxy, r.SkipTLSVerify, settings.GetUserAgent())
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Errorf(c, "[pricefeed.readPricesFromUrl] failed to request price feed from \"%s\", because %s", r.Location, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		log.Errorf(c, "[pricefeed.readPricesFromUrl] failed to read response body from \"%s\", because %s", r.Location, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	if resp.StatusCode != 200 {
+		log.Errorf(c, "[pricefeed.readPricesFromUrl] response status code is %d (expected 200) from \"%s\"", resp.StatusCode, r.Location)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	items, err := ParsePrices(content, time.Now().Unix())
+
+	if err != nil {
+		log.Errorf(c, "[pricefeed.readPricesFromUrl] failed to parse response from \"%s\", because %s", r.Location, err.Error())
+		return nil, errs.ErrInvalidPriceFeedContent
+	}
+
+	return items, nil
+}
+
+// ParsePrices parses the price feed content in json or csv format, the default update time would be used if the price does not have update time
+func ParsePrices(content []byte, defaultUpdateTime int64) ([]*PriceFeedItem, error) {
+	content = bytes.TrimPrefix(content, []byte{0xEF, 0xBB, 0xBF})
+	trimmedContent := bytes.TrimSpace(content)
+
+	if len(trimmedContent) > 0 && (trimmedContent[0] == '{' || trimmedContent[0] == '[') {
+		return parseJsonPrices(trimmedContent, defaultUpdateTime)
+	}
+
+	return parseCsvPrices(content, defaultUpdateTime)
+}
+
+func parseJsonPrices(content []byte, defaultUpdateTime int64) ([]*PriceFeedItem, error) {
+	feed := &jsonPriceFeed{}
+
+	if content[0] == '[' {
+		if err := json.Unmarshal(content, &feed.Prices); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(content, feed); err != nil {
+		return nil, err
+	}
+
+	if feed.UpdateTime > 0 {
+		defaultUpdateTime = feed.UpdateTime
+	}
+
+	items := make([]*PriceFeedItem, 0, len(feed.Prices))
+
+	for i := 0; i < len(feed.Prices); i++ {
+		price := feed.Prices[i]
+
+		if price == nil {
+			continue
+		}
+
+		currency := price.Currency
+
+		if currency == "" {
+			currency = feed.Currency
+		}
+
+		item, err := newPriceFeedItem(price.Symbol, price.Price.String(), currency, price.UpdateTime, defaultUpdateTime)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return mergePriceFeedItems(items), nil
+}
+
+func parseCsvPrices(content []byte, defaultUpdateTime int64) ([]*PriceFeedItem, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) < 1 {
+		return nil, errs.ErrInvalidPriceFeedContent
+	}
+
+	columnIndexes := make(map[string]int, len(rows[0]))
+
+	for i := 0; i < len(rows[0]); i++ {
+		columnIndexes[strings.ToLower(strings.TrimSpace(rows[0][i]))] = i
+	}
+
+	_, symbolExists := columnIndexes[csvColumnSymbol]
+	_, priceExists := columnIndexes[csvColumnPrice]
+
+	if !symbolExists || !priceExists {
+		return nil, errs.ErrInvalidPriceFeedContent
+	}
+
+	items := make([]*PriceFeedItem, 0, len(rows)-1)
+
+	for i := 1; i < len(rows); i++ {
+		row := rows[i]
+
+		if len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+
+		updateTime := int64(0)
+
+		if value := getCsvColumnValue(row, columnIndexes, csvColumnUpdateTime); value != "" {
+			updateTime, err = utils.StringToInt64(value)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		item, err := newPriceFeedItem(getCsvColumnValue(row, columnIndexes, csvColumnSymbol), getCsvColumnValue(row, columnIndexes, csvColumnPrice), getCsvColumnValue(row, columnIndexes, csvColumnCurrency), updateTime, defaultUpdateTime)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return mergePriceFeedItems(items), nil
+}
+
+func getCsvColumnValue(row []string, columnIndexes map[string]int, columnName string) string {
+	index, exists := columnIndexes[columnName]
+
+	if !exists || index >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[index])
+}
+
+func newPriceFeedItem(symbol string, price string, currency string, updateTime int64, defaultUpdateTime int64) (*PriceFeedItem, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	price = strings.TrimSpace(price)
+
+	if symbol == "" {
+		return nil, errs.ErrInvalidPriceFeedContent
+	}
+
+	priceValue, err := strconv.ParseFloat(price, 64)
+
+	if err != nil || priceValue < 0 {
+		return nil, errs.ErrInvalidPriceFeedContent
+	}
+
+	if updateTime <= 0 {
+		updateTime = defaultUpdateTime
+	}
+
+	return &PriceFeedItem{
+		Symbol:     symbol,
+		Price:      price,
+		Currency:   strings.ToUpper(strings.TrimSpace(currency)),
+		UpdateTime: updateTime,
+	}, nil
+}
+
+func mergePriceFeedItems(items []*PriceFeedItem) []*PriceFeedItem {
+	symbolIndexes := make(map[string]int, len(items))
+	mergedItems := make([]*PriceFeedItem, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		if index, exists := symbolIndexes[items[i].Symbol]; exists {
+			mergedItems[index] = items[i]
+			continue
+		}
+
+		symbolIndexes[items[i].Symbol] = len(mergedItems)
+		mergedItems = append(mergedItems, items[i])
+	}
+
+	return mergedItems
+}
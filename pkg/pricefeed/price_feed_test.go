@@ -0,0 +1,151 @@
+package pricefeed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestParsePrices_CsvContent(t *testing.T) {
+	content := "Symbol,Price,Currency,Update_Time\n" +
+		"aapl,189.5,usd,1700000000\n" +
+		"MSFT, 370.1 ,,\n" +
+		"\n" +
+		"AAPL,190.25,USD,\n"
+
+	items, err := ParsePrices([]byte(content), 1600000000)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(items))
+
+	assert.Equal(t, "AAPL", items[0].Symbol)
+	assert.Equal(t, "190.25", items[0].Price)
+	assert.Equal(t, "USD", items[0].Currency)
+	assert.Equal(t, int64(1600000000), items[0].UpdateTime)
+
+	assert.Equal(t, "MSFT", items[1].Symbol)
+	assert.Equal(t, "370.1", items[1].Price)
+	assert.Equal(t, "", items[1].Currency)
+	assert.Equal(t, int64(1600000000), items[1].UpdateTime)
+}
+
+func TestParsePrices_CsvContentWithByteOrderMark(t *testing.T) {
+	content := "\xEF\xBB\xBFsymbol,price\nBTC,65000\n"
+
+	items, err := ParsePrices([]byte(content), 1600000000)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, "BTC", items[0].Symbol)
+	assert.Equal(t, "65000", items[0].Price)
+}
+
+func TestParsePrices_JsonArrayContent(t *testing.T) {
+	content := `[{"symbol":"btc","price":65000.5,"currency":"usd","updateTime":1700000000},{"symbol":"ETH","price":"3500"}]`
+
+	items, err := ParsePrices([]byte(content), 1600000000)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(items))
+
+	assert.Equal(t, "BTC", items[0].Symbol)
+	assert.Equal(t, "65000.5", items[0].Price)
+	assert.Equal(t, "USD", items[0].Currency)
+	assert.Equal(t, int64(1700000000), items[0].UpdateTime)
+
+	assert.Equal(t, "ETH", items[1].Symbol)
+	assert.Equal(t, "3500", items[1].Price)
+	assert.Equal(t, int64(1600000000), items[1].UpdateTime)
+}
+
+func TestParsePrices_JsonObjectContent(t *testing.T) {
+	content := ` {"currency":"EUR","updateTime":1700000000,"prices":[{"symbol":"SAP","price":"150"},{"symbol":"AAPL","price":"180","currency":"USD"}]}`
+
+	items, err := ParsePrices([]byte(content), 1600000000)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(items))
+
+	assert.Equal(t, "SAP", items[0].Symbol)
+	assert.Equal(t, "EUR", items[0].Currency)
+	assert.Equal(t, int64(1700000000), items[0].UpdateTime)
+
+	assert.Equal(t, "AAPL", items[1].Symbol)
+	assert.Equal(t, "USD", items[1].Currency)
+}
+
+func TestParsePrices_InvalidContent(t *testing.T) {
+	_, err := ParsePrices([]byte("name,value\nAAPL,100\n"), 0)
+	assert.Equal(t, errs.ErrInvalidPriceFeedContent, err)
+
+	_, err = ParsePrices([]byte("symbol,price\nAAPL,abc\n"), 0)
+	assert.Equal(t, errs.ErrInvalidPriceFeedContent, err)
+
+	_, err = ParsePrices([]byte("symbol,price\nAAPL,-1\n"), 0)
+	assert.Equal(t, errs.ErrInvalidPriceFeedContent, err)
+
+	_, err = ParsePrices([]byte("symbol,price\n,100\n"), 0)
+	assert.Equal(t, errs.ErrInvalidPriceFeedContent, err)
+
+	_, err = ParsePrices([]byte(`[{"symbol":"AAPL","price":true}]`), 0)
+	assert.NotNil(t, err)
+
+	_, err = ParsePrices([]byte(""), 0)
+	assert.Equal(t, errs.ErrInvalidPriceFeedContent, err)
+}
+
+func TestPriceFeedReaderReadPrices_FromDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "01_stocks.csv"), []byte("symbol,price,currency\nAAPL,180,USD\nMSFT,370,USD\n"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "02_override.json"), []byte(`[{"symbol":"AAPL","price":"190","currency":"USD"}]`), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("not a price feed"), 0644))
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "sub.csv"), 0755))
+
+	reader := &PriceFeedReader{Location: dir}
+	items, err := reader.ReadPrices(core.NewNullContext())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(items))
+
+	assert.Equal(t, "AAPL", items[0].Symbol)
+	assert.Equal(t, "190", items[0].Price)
+	assert.Equal(t, "MSFT", items[1].Symbol)
+	assert.Equal(t, "370", items[1].Price)
+	assert.True(t, items[1].UpdateTime > 0)
+}
+
+func TestPriceFeedReaderReadPrices_FromUrl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/prices.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"currency":"USD","prices":[{"symbol":"BTC","price":"65000"}]}`))
+	}))
+	defer server.Close()
+
+	reader := &PriceFeedReader{Location: server.URL + "/prices.json", Proxy: "none"}
+	items, err := reader.ReadPrices(core.NewNullContext())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, "BTC", items[0].Symbol)
+	assert.Equal(t, "USD", items[0].Currency)
+
+	reader = &PriceFeedReader{Location: server.URL + "/not_found.json", Proxy: "none"}
+	_, err = reader.ReadPrices(core.NewNullContext())
+	assert.Equal(t, errs.ErrFailedToRequestRemoteApi, err)
+}
+
+func TestPriceFeedReaderReadPrices_InvalidLocation(t *testing.T) {
+	reader := &PriceFeedReader{}
+	_, err := reader.ReadPrices(core.NewNullContext())
+	assert.Equal(t, errs.ErrPriceFeedLocationNotSet, err)
+
+	reader = &PriceFeedReader{Location: filepath.Join(t.TempDir(), "not_exists.csv")}
+	_, err = reader.ReadPrices(core.NewNullContext())
+	assert.Equal(t, errs.ErrFailedToReadPriceFeed, err)
+}
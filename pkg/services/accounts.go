@@ -57,6 +57,28 @@ func (s *AccountService) GetAllAccountsByUid(c core.Context, uid int64) ([]*mode
 	return accounts, err
 }
 
+// GetAllWalletAddressAccounts returns all visible single accounts of all users which have wallet addresses
+func (s *AccountService) GetAllWalletAddressAccounts(c core.Context) ([]*models.Account, error) {
+	var allAccounts []*models.Account
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var accounts []*models.Account
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND hidden=? AND type=?", false, false, models.ACCOUNT_TYPE_SINGLE_ACCOUNT).Find(&accounts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < len(accounts); j++ {
+			if accounts[j].GetWalletAddress() != "" {
+				allAccounts = append(allAccounts, accounts[j])
+			}
+		}
+	}
+
+	return allAccounts, nil
+}
+
 // GetAccountByAccountId returns account model according to account id
 func (s *AccountService) GetAccountByAccountId(c core.Context, uid int64, accountId int64) (*models.Account, error) {
 	if uid <= 0 {
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// assetPriceHistoryTables represents the tables which store the daily prices of each asset type
+var assetPriceHistoryTables = map[models.AccountAssetType]any{
+	models.ACCOUNT_ASSET_TYPE_STOCK:  &models.StockPriceHistory{},
+	models.ACCOUNT_ASSET_TYPE_CRYPTO: &models.CryptocurrencyPriceHistory{},
+}
+
+// assetPriceHistoryStore represents the store of the daily prices of the specified asset type,
+// which is shared by the stock price history service and the cryptocurrency price history service
+type assetPriceHistoryStore struct {
+	ServiceUsingDB
+	assetType models.AccountAssetType
+}
+
+// getPriceHistories returns the daily prices of the given symbol between the start date and the end date (both inclusive, 0 means unlimited)
+func (s *assetPriceHistoryStore) getPriceHistories(c core.Context, symbol string, currency string, dataSource string, startDate int32, endDate int32) ([]*models.AssetPriceHistory, error) {
+	condition := "symbol=?"
+	conditionParams := []any{strings.ToUpper(symbol)}
+
+	if currency != "" {
+		condition = condition + " AND currency=?"
+		conditionParams = append(conditionParams, strings.ToUpper(currency))
+	}
+
+	if dataSource != "" {
+		condition = condition + " AND data_source=?"
+		conditionParams = append(conditionParams, dataSource)
+	}
+
+	if startDate > 0 {
+		condition = condition + " AND price_date>=?"
+		conditionParams = append(conditionParams, startDate)
+	}
+
+	if endDate > 0 {
+		condition = condition + " AND price_date<=?"
+		conditionParams = append(conditionParams, endDate)
+	}
+
+	var histories []*models.AssetPriceHistory
+	err := s.newSession(c).Where(condition, conditionParams...).OrderBy("price_date asc").Find(&histories)
+
+	return histories, err
+}
+
+// getPriceHistoriesInDateRange returns the daily prices of the given symbol, currency and data source between the start date and the end date (both inclusive, 0 means unlimited),
+// the last price before the start date is also returned so that the price at the start date can be determined
+func (s *assetPriceHistoryStore) getPriceHistoriesInDateRange(c core.Context, symbol string, currency string, dataSource string, startDate int32, endDate int32) ([]*models.AssetPriceHistory, error) {
+	if startDate > 0 {
+		previousHistory := &models.AssetPriceHistory{}
+		has, err := s.newSession(c).Where("symbol=? AND currency=? AND data_source=? AND price_date<?", strings.ToUpper(symbol), strings.ToUpper(currency), dataSource, startDate).OrderBy("price_date desc").Limit(1).Get(previousHistory)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if has {
+			startDate = previousHistory.PriceDate
+		}
+	}
+
+	return s.getPriceHistories(c, symbol, currency, dataSource, startDate, endDate)
+}
+
+// getLatestPriceHistory returns the latest saved daily price of the given symbol, returns nil if there is no price history
+func (s *assetPriceHistoryStore) getLatestPriceHistory(c core.Context, symbol string) (*models.AssetPriceHistory, error) {
+	history := &models.AssetPriceHistory{}
+	has, err := s.newSession(c).Where("symbol=?", strings.ToUpper(symbol)).OrderBy("price_date desc, updated_unix_time desc").Limit(1).Get(history)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+
+	return history, nil
+}
+
+// GetPriceHistoriesOfSymbolsInDateRange returns the daily prices of the given symbols of the same currency and data source between the start date and the end date (both inclusive, 0 means unlimited),
+// the last price of each symbol before the start date is also returned so that the price at the start date can be determined, and the prices are grouped by the symbols in upper case
+func (s *assetPriceHistoryStore) GetPriceHistoriesOfSymbolsInDateRange(c core.Context, symbols []string, currency string, dataSource string, startDate int32, endDate int32) (map[string][]*models.AssetPriceHistory, error) {
+	symbolPriceHistories := make(map[string][]*models.AssetPriceHistory, len(symbols))
+
+	if len(symbols) < 1 {
+		return symbolPriceHistories, nil
+	}
+
+	upperCaseSymbols := make([]string, len(symbols))
+	symbolStartDates := make(map[string]int32, len(symbols))
+
+	for i := 0; i < len(symbols); i++ {
+		upperCaseSymbols[i] = strings.ToUpper(symbols[i])
+		symbolStartDates[upperCaseSymbols[i]] = startDate
+	}
+
+	queryStartDate := startDate
+
+	if startDate > 0 {
+		var previousHistories []*models.AssetPriceHistory
+		err := s.newSession(c).Select("symbol, MAX(price_date) AS price_date").Where("currency=? AND data_source=? AND price_date<?", strings.ToUpper(currency), dataSource, startDate).In("symbol", upperCaseSymbols).GroupBy("symbol").Find(&previousHistories)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(previousHistories); i++ {
+			symbolStartDates[previousHistories[i].Symbol] = previousHistories[i].PriceDate
+
+			if previousHistories[i].PriceDate < queryStartDate {
+				queryStartDate = previousHistories[i].PriceDate
+			}
+		}
+	}
+
+	condition := "currency=? AND data_source=?"
+	conditionParams := []any{strings.ToUpper(currency), dataSource}
+
+	if queryStartDate > 0 {
+		condition = condition + " AND price_date>=?"
+		conditionParams = append(conditionParams, queryStartDate)
+	}
+
+	if endDate > 0 {
+		condition = condition + " AND price_date<=?"
+		conditionParams = append(conditionParams, endDate)
+	}
+
+	var histories []*models.AssetPriceHistory
+	err := s.newSession(c).Where(condition, conditionParams...).In("symbol", upperCaseSymbols).OrderBy("price_date asc").Find(&histories)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(histories); i++ {
+		history := histories[i]
+
+		if history.PriceDate >= symbolStartDates[history.Symbol] {
+			symbolPriceHistories[history.Symbol] = append(symbolPriceHistories[history.Symbol], history)
+		}
+	}
+
+	return symbolPriceHistories, nil
+}
+
+// GetLatestPriceHistories returns the latest saved daily prices of the given symbols grouped by the symbols in upper case,
+// the symbols which have no price history are not included
+func (s *assetPriceHistoryStore) GetLatestPriceHistories(c core.Context, symbols []string) (map[string]*models.AssetPriceHistory, error) {
+	latestPriceHistories := make(map[string]*models.AssetPriceHistory, len(symbols))
+
+	if len(symbols) < 1 {
+		return latestPriceHistories, nil
+	}
+
+	upperCaseSymbols := make([]string, len(symbols))
+
+	for i := 0; i < len(symbols); i++ {
+		upperCaseSymbols[i] = strings.ToUpper(symbols[i])
+	}
+
+	var latestDateHistories []*models.AssetPriceHistory
+	err := s.newSession(c).Select("symbol, MAX(price_date) AS price_date").In("symbol", upperCaseSymbols).GroupBy("symbol").Find(&latestDateHistories)
+
+	if err != nil {
+		return nil, err
+	} else if len(latestDateHistories) < 1 {
+		return latestPriceHistories, nil
+	}
+
+	latestDates := make(map[string]int32, len(latestDateHistories))
+	minLatestDate := latestDateHistories[0].PriceDate
+
+	for i := 0; i < len(latestDateHistories); i++ {
+		latestDates[latestDateHistories[i].Symbol] = latestDateHistories[i].PriceDate
+
+		if latestDateHistories[i].PriceDate < minLatestDate {
+			minLatestDate = latestDateHistories[i].PriceDate
+		}
+	}
+
+	var histories []*models.AssetPriceHistory
+	err = s.newSession(c).Where("price_date>=?", minLatestDate).In("symbol", upperCaseSymbols).OrderBy("price_date desc, updated_unix_time desc").Find(&histories)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(histories); i++ {
+		history := histories[i]
+
+		if _, exists := latestPriceHistories[history.Symbol]; !exists && history.PriceDate == latestDates[history.Symbol] {
+			latestPriceHistories[history.Symbol] = history
+		}
+	}
+
+	return latestPriceHistories, nil
+}
+
+// saveLatestPrices saves the given prices as the prices of the day when they were updated,
+// the price of the same symbol, data source, currency and day would be overwritten
+func (s *assetPriceHistoryStore) saveLatestPrices(c core.Context, updateTime int64, histories []*models.AssetPriceHistory) error {
+	if len(histories) < 1 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+
+	if updateTime <= 0 {
+		updateTime = now
+	}
+
+	priceDate := utils.FormatUnixTimeToNumericYearMonthDay(updateTime, time.UTC)
+	table := assetPriceHistoryTables[s.assetType]
+
+	return s.UserDataDB(0).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(histories); i++ {
+			history := histories[i]
+			history.Symbol = strings.ToUpper(history.Symbol)
+			history.Currency = strings.ToUpper(history.Currency)
+			history.PriceDate = priceDate
+			history.UpdatedUnixTime = now
+
+			exists, err := sess.Table(table).Where("symbol=? AND data_source=? AND currency=? AND price_date=?", history.Symbol, history.DataSource, history.Currency, history.PriceDate).Exist()
+
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				_, err = sess.Table(table).Cols("price", "updated_unix_time").Where("symbol=? AND data_source=? AND currency=? AND price_date=?", history.Symbol, history.DataSource, history.Currency, history.PriceDate).Update(history)
+			} else {
+				history.CreatedUnixTime = now
+				_, err = sess.Table(table).Insert(history)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *assetPriceHistoryStore) newSession(c core.Context) *xorm.Session {
+	return s.UserDataDB(0).NewSession(c).Table(assetPriceHistoryTables[s.assetType])
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+func initializeTestDataStore(t *testing.T, beans ...any) {
+	config := &settings.Config{
+		DatabaseConfig: &settings.DatabaseConfig{
+			DatabaseType: settings.Sqlite3DbType,
+			DatabasePath: filepath.Join(t.TempDir(), "ezbookkeeping.db"),
+		},
+		UuidGeneratorType: settings.InternalUuidGeneratorType,
+	}
+
+	err := datastore.InitializeDataStore(config)
+	assert.Nil(t, err)
+
+	err = uuid.InitializeUuidGenerator(config)
+	assert.Nil(t, err)
+
+	err = datastore.Container.UserDataStore.SyncStructs(beans...)
+	assert.Nil(t, err)
+}
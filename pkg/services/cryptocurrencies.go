@@ -1,8 +1,11 @@
 package services
 
 import (
+	"strings"
 	"time"
 
+	"xorm.io/xorm"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -23,24 +26,83 @@ var (
 	}
 )
 
-// GetAllCryptocurrencies returns all cryptocurrency models
-func (s *CryptocurrencyService) GetAllCryptocurrencies(c core.Context) ([]*models.Cryptocurrency, error) {
-	var cryptocurrencies []*models.Cryptocurrency
-	err := s.UserDataDB(0).NewSession(c).OrderBy("display_order asc").Find(&cryptocurrencies)
-	return cryptocurrencies, err
+// GetAllCryptocurrencies returns all cryptocurrency models in the watchlist of user, or in the global default watchlist if uid is 0
+func (s *CryptocurrencyService) GetAllCryptocurrencies(c core.Context, uid int64) ([]*models.Cryptocurrency, error) {
+	if uid < 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var cryptos []*models.Cryptocurrency
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&cryptos)
+	return cryptos, err
+}
+
+// GetAllVisibleCryptocurrencies returns all visible cryptocurrency models in the watchlist of user, or in the global default watchlist if uid is 0
+func (s *CryptocurrencyService) GetAllVisibleCryptocurrencies(c core.Context, uid int64) ([]*models.Cryptocurrency, error) {
+	if uid < 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var cryptos []*models.Cryptocurrency
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND is_hidden=?", uid, false, false).OrderBy("display_order asc").Find(&cryptos)
+	return cryptos, err
 }
 
-// GetAllVisibleCryptocurrencies returns all visible cryptocurrency models
-func (s *CryptocurrencyService) GetAllVisibleCryptocurrencies(c core.Context) ([]*models.Cryptocurrency, error) {
-	var cryptocurrencies []*models.Cryptocurrency
-	err := s.UserDataDB(0).NewSession(c).Where("is_hidden=?", false).OrderBy("display_order asc").Find(&cryptocurrencies)
-	return cryptocurrencies, err
+// GetAllWatchedCryptocurrencies returns the cryptocurrency models in the global default watchlist and the watchlists of all users, the cryptocurrencies with the same symbol are only returned once
+func (s *CryptocurrencyService) GetAllWatchedCryptocurrencies(c core.Context, visibleOnly bool) ([]*models.Cryptocurrency, error) {
+	var allCryptos []*models.Cryptocurrency
+	addedSymbols := make(map[string]bool)
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var cryptos []*models.Cryptocurrency
+		sess := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=?", false)
+
+		if visibleOnly {
+			sess = sess.And("is_hidden=?", false)
+		}
+
+		err := sess.OrderBy("uid asc, display_order asc").Find(&cryptos)
+		if err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < len(cryptos); j++ {
+			symbol := strings.ToUpper(cryptos[j].Symbol)
+
+			if addedSymbols[symbol] {
+				continue
+			}
+
+			addedSymbols[symbol] = true
+			allCryptos = append(allCryptos, cryptos[j])
+		}
+	}
+
+	return allCryptos, nil
 }
 
-// GetCryptocurrencyBySymbol returns cryptocurrency model by symbol
-func (s *CryptocurrencyService) GetCryptocurrencyBySymbol(c core.Context, symbol string) (*models.Cryptocurrency, error) {
+// IsSymbolWatched returns whether the cryptocurrency symbol is in the global default watchlist or the watchlist of any user
+func (s *CryptocurrencyService) IsSymbolWatched(c core.Context, symbol string) (bool, error) {
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		exists, err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND symbol=?", false, symbol).Exist(&models.Cryptocurrency{})
+		if err != nil {
+			return false, err
+		} else if exists {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetCryptocurrencyBySymbol returns cryptocurrency model by symbol in the watchlist of user, or in the global default watchlist if uid is 0
+func (s *CryptocurrencyService) GetCryptocurrencyBySymbol(c core.Context, uid int64, symbol string) (*models.Cryptocurrency, error) {
+	if uid < 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
 	crypto := &models.Cryptocurrency{}
-	has, err := s.UserDataDB(0).NewSession(c).Where("symbol=?", symbol).Get(crypto)
+	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND symbol=?", uid, false, symbol).Get(crypto)
 	if err != nil {
 		return nil, err
 	} else if !has {
@@ -49,22 +111,40 @@ func (s *CryptocurrencyService) GetCryptocurrencyBySymbol(c core.Context, symbol
 	return crypto, nil
 }
 
-// CreateCryptocurrency saves a new cryptocurrency model to database
+// CreateCryptocurrency saves a new cryptocurrency model to the watchlist of user, or to the global default watchlist if uid is 0
 func (s *CryptocurrencyService) CreateCryptocurrency(c core.Context, crypto *models.Cryptocurrency) error {
+	if crypto.Uid < 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	now := time.Now().Unix()
+	crypto.Deleted = false
 	crypto.CreatedUnixTime = now
 	crypto.UpdatedUnixTime = now
 
-	_, err := s.UserDataDB(0).NewSession(c).Insert(crypto)
-	return err
+	return s.UserDataDB(crypto.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Where("uid=? AND deleted=? AND symbol=?", crypto.Uid, false, crypto.Symbol).Exist(&models.Cryptocurrency{})
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrCryptocurrencyAlreadyExists
+		}
+
+		_, err = sess.Insert(crypto)
+		return err
+	})
 }
 
-// UpdateCryptocurrency updates an existing cryptocurrency model
+// UpdateCryptocurrency updates an existing cryptocurrency model in the watchlist of user, or in the global default watchlist if uid is 0
 func (s *CryptocurrencyService) UpdateCryptocurrency(c core.Context, crypto *models.Cryptocurrency) error {
+	if crypto.Uid < 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	now := time.Now().Unix()
 	crypto.UpdatedUnixTime = now
 
-	updatedRows, err := s.UserDataDB(0).NewSession(c).Where("symbol=?", crypto.Symbol).Update(crypto)
+	updatedRows, err := s.UserDataDB(crypto.Uid).NewSession(c).Cols("name", "is_hidden", "display_order", "updated_unix_time").Where("uid=? AND deleted=? AND symbol=?", crypto.Uid, false, crypto.Symbol).Update(crypto)
 	if err != nil {
 		return err
 	} else if updatedRows < 1 {
@@ -73,21 +153,40 @@ func (s *CryptocurrencyService) UpdateCryptocurrency(c core.Context, crypto *mod
 	return nil
 }
 
-// DeleteCryptocurrency deletes a cryptocurrency from database
-func (s *CryptocurrencyService) DeleteCryptocurrency(c core.Context, symbol string) error {
-	_, err := s.UserDataDB(0).NewSession(c).Where("symbol=?", symbol).Delete(&models.Cryptocurrency{})
-	return err
+// DeleteCryptocurrency deletes a cryptocurrency from the watchlist of user, or from the global default watchlist if uid is 0
+func (s *CryptocurrencyService) DeleteCryptocurrency(c core.Context, uid int64, symbol string) error {
+	if uid < 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+	updateModel := &models.Cryptocurrency{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	deletedRows, err := s.UserDataDB(uid).NewSession(c).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND symbol=?", uid, false, symbol).Update(updateModel)
+	if err != nil {
+		return err
+	} else if deletedRows < 1 {
+		return errs.ErrCryptocurrencyNotFound
+	}
+	return nil
 }
 
-// HideCryptocurrency updates the hidden status of a cryptocurrency
-func (s *CryptocurrencyService) HideCryptocurrency(c core.Context, symbol string, hidden bool) error {
+// HideCryptocurrency updates the hidden status of a cryptocurrency in the watchlist of user, or in the global default watchlist if uid is 0
+func (s *CryptocurrencyService) HideCryptocurrency(c core.Context, uid int64, symbol string, hidden bool) error {
+	if uid < 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	now := time.Now().Unix()
 	updateModel := &models.Cryptocurrency{
 		IsHidden:        hidden,
 		UpdatedUnixTime: now,
 	}
-	
-	updatedRows, err := s.UserDataDB(0).NewSession(c).Where("symbol=?", symbol).Cols("is_hidden", "updated_unix_time").Update(updateModel)
+
+	updatedRows, err := s.UserDataDB(uid).NewSession(c).Cols("is_hidden", "updated_unix_time").Where("uid=? AND deleted=? AND symbol=?", uid, false, symbol).Update(updateModel)
 	if err != nil {
 		return err
 	} else if updatedRows < 1 {
@@ -95,3 +194,58 @@ func (s *CryptocurrencyService) HideCryptocurrency(c core.Context, symbol string
 	}
 	return nil
 }
+
+// InitUserCryptocurrencies copies the global default watchlist to the watchlist of the user, it should be called when the user is created or the database is updated,
+// and does nothing if the user has already had any cryptocurrency in the watchlist
+func (s *CryptocurrencyService) InitUserCryptocurrencies(c core.Context, uid int64) error {
+	if uid == 0 {
+		return nil
+	}
+
+	exists, err := s.UserDataDB(uid).NewSession(c).Where("uid=?", uid).Exist(&models.Cryptocurrency{})
+
+	if err != nil {
+		return err
+	} else if exists {
+		return nil
+	}
+
+	var defaultCryptos []*models.Cryptocurrency
+	err = s.UserDataDB(0).NewSession(c).Where("uid=? AND deleted=?", 0, false).OrderBy("display_order asc").Find(&defaultCryptos)
+
+	if err != nil {
+		return err
+	} else if len(defaultCryptos) < 1 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Where("uid=?", uid).Exist(&models.Cryptocurrency{})
+
+		if err != nil || exists {
+			return err
+		}
+
+		for i := 0; i < len(defaultCryptos); i++ {
+			crypto := &models.Cryptocurrency{
+				Uid:             uid,
+				Symbol:          defaultCryptos[i].Symbol,
+				Name:            defaultCryptos[i].Name,
+				IsHidden:        defaultCryptos[i].IsHidden,
+				DisplayOrder:    defaultCryptos[i].DisplayOrder,
+				CreatedUnixTime: now,
+				UpdatedUnixTime: now,
+			}
+
+			_, err = sess.Insert(crypto)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// CryptocurrencyPriceHistoryService represents cryptocurrency price history service
+type CryptocurrencyPriceHistoryService struct {
+	assetPriceHistoryStore
+}
+
+// Initialize a cryptocurrency price history service singleton instance
+var (
+	CryptocurrencyPriceHistories = &CryptocurrencyPriceHistoryService{
+		assetPriceHistoryStore: assetPriceHistoryStore{
+			ServiceUsingDB: ServiceUsingDB{
+				container: datastore.Container,
+			},
+			assetType: models.ACCOUNT_ASSET_TYPE_CRYPTO,
+		},
+	}
+)
+
+// GetPriceHistories returns the daily cryptocurrency prices of the given symbol between the start date and the end date (both inclusive, 0 means unlimited)
+func (s *CryptocurrencyPriceHistoryService) GetPriceHistories(c core.Context, symbol string, currency string, dataSource string, startDate int32, endDate int32) ([]*models.CryptocurrencyPriceHistory, error) {
+	histories, err := s.getPriceHistories(c, symbol, currency, dataSource, startDate, endDate)
+	return toCryptocurrencyPriceHistories(histories), err
+}
+
+// GetPriceHistoriesInDateRange returns the daily cryptocurrency prices of the given symbol, currency and data source between the start date and the end date (both inclusive, 0 means unlimited),
+// the last price before the start date is also returned so that the price at the start date can be determined
+func (s *CryptocurrencyPriceHistoryService) GetPriceHistoriesInDateRange(c core.Context, symbol string, currency string, dataSource string, startDate int32, endDate int32) ([]*models.CryptocurrencyPriceHistory, error) {
+	histories, err := s.getPriceHistoriesInDateRange(c, symbol, currency, dataSource, startDate, endDate)
+	return toCryptocurrencyPriceHistories(histories), err
+}
+
+// GetLatestPriceHistory returns the latest saved daily cryptocurrency price of the given symbol, returns nil if there is no price history
+func (s *CryptocurrencyPriceHistoryService) GetLatestPriceHistory(c core.Context, symbol string) (*models.CryptocurrencyPriceHistory, error) {
+	history, err := s.getLatestPriceHistory(c, symbol)
+	return (*models.CryptocurrencyPriceHistory)(history), err
+}
+
+// SaveLatestCryptocurrencyPrices saves the latest cryptocurrency prices as the prices of the day when they were updated
+func (s *CryptocurrencyPriceHistoryService) SaveLatestCryptocurrencyPrices(c core.Context, dataSource string, priceResponse *models.LatestCryptocurrencyPriceResponse) error {
+	if priceResponse == nil || len(priceResponse.Prices) < 1 {
+		return nil
+	}
+
+	histories := make([]*models.AssetPriceHistory, 0, len(priceResponse.Prices))
+
+	for i := 0; i < len(priceResponse.Prices); i++ {
+		price := priceResponse.Prices[i]
+
+		if price == nil || price.Symbol == "" || price.Price == "" {
+			continue
+		}
+
+		histories = append(histories, &models.AssetPriceHistory{
+			Symbol:     price.Symbol,
+			DataSource: dataSource,
+			Currency:   priceResponse.BaseCurrency,
+			Price:      price.Price,
+		})
+	}
+
+	return s.saveLatestPrices(c, priceResponse.UpdateTime, histories)
+}
+
+func toCryptocurrencyPriceHistories(histories []*models.AssetPriceHistory) []*models.CryptocurrencyPriceHistory {
+	if histories == nil {
+		return nil
+	}
+
+	cryptocurrencyPriceHistories := make([]*models.CryptocurrencyPriceHistory, len(histories))
+
+	for i := 0; i < len(histories); i++ {
+		cryptocurrencyPriceHistories[i] = (*models.CryptocurrencyPriceHistory)(histories[i])
+	}
+
+	return cryptocurrencyPriceHistories
+}
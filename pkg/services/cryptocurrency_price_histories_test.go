@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestCryptocurrencyPriceHistoryGetPriceHistoriesInDateRange(t *testing.T) {
+	initializeTestDataStore(t, new(models.CryptocurrencyPriceHistory))
+	c := core.NewNullContext()
+
+	for _, history := range []*models.CryptocurrencyPriceHistory{
+		{Symbol: "BTC", DataSource: "coingecko", Currency: "USD", PriceDate: 20240101, Price: "42000"},
+		{Symbol: "BTC", DataSource: "coingecko", Currency: "USD", PriceDate: 20240110, Price: "46000"},
+		{Symbol: "BTC", DataSource: "coingecko", Currency: "EUR", PriceDate: 20240105, Price: "39000"},
+		{Symbol: "BTC", DataSource: "binance", Currency: "USDT", PriceDate: 20240105, Price: "44000"},
+	} {
+		_, err := CryptocurrencyPriceHistories.UserDataDB(0).NewSession(c).Insert(history)
+		assert.Nil(t, err)
+	}
+
+	histories, err := CryptocurrencyPriceHistories.GetPriceHistoriesInDateRange(c, "btc", "usd", "coingecko", 20240105, 20240131)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 2)
+	assert.Equal(t, "42000", histories[0].Price)
+	assert.Equal(t, "46000", histories[1].Price)
+
+	history, err := CryptocurrencyPriceHistories.GetLatestPriceHistory(c, "BTC")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(20240110), history.PriceDate)
+	assert.Equal(t, "coingecko", history.DataSource)
+
+	history, err = CryptocurrencyPriceHistories.GetLatestPriceHistory(c, "ETH")
+	assert.Nil(t, err)
+	assert.Nil(t, history)
+}
+
+func TestCryptocurrencyPriceHistorySaveLatestCryptocurrencyPrices_UpsertSameDay(t *testing.T) {
+	initializeTestDataStore(t, new(models.CryptocurrencyPriceHistory))
+	c := core.NewNullContext()
+
+	err := CryptocurrencyPriceHistories.SaveLatestCryptocurrencyPrices(c, "coingecko", &models.LatestCryptocurrencyPriceResponse{
+		BaseCurrency: "usd",
+		UpdateTime:   1704153600,
+		Prices: models.LatestCryptocurrencyPriceSlice{
+			{Symbol: "btc", Price: "45000"},
+			{Symbol: "ETH", Price: "2350"},
+		},
+	})
+	assert.Nil(t, err)
+
+	err = CryptocurrencyPriceHistories.SaveLatestCryptocurrencyPrices(c, "coingecko", &models.LatestCryptocurrencyPriceResponse{
+		BaseCurrency: "USD",
+		UpdateTime:   1704196800,
+		Prices: models.LatestCryptocurrencyPriceSlice{
+			{Symbol: "BTC", Price: "45500"},
+		},
+	})
+	assert.Nil(t, err)
+
+	histories, err := CryptocurrencyPriceHistories.GetPriceHistories(c, "BTC", "USD", "coingecko", 0, 0)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 1)
+	assert.Equal(t, int32(20240102), histories[0].PriceDate)
+	assert.Equal(t, "45500", histories[0].Price)
+
+	histories, err = CryptocurrencyPriceHistories.GetPriceHistories(c, "eth", "", "", 20240102, 20240102)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 1)
+	assert.Equal(t, "2350", histories[0].Price)
+}
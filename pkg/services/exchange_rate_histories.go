@@ -0,0 +1,259 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// ExchangeRateHistoryService represents exchange rate history service
+type ExchangeRateHistoryService struct {
+	ServiceUsingDB
+}
+
+// Initialize an exchange rate history service singleton instance
+var (
+	ExchangeRateHistories = &ExchangeRateHistoryService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetExchangeRateHistories returns the daily exchange rates of the given data source between the start date and the end date (both inclusive, 0 means unlimited),
+// the rates of all currencies would be returned if currency is empty
+func (s *ExchangeRateHistoryService) GetExchangeRateHistories(c core.Context, dataSource string, currency string, startDate int32, endDate int32) ([]*models.ExchangeRateHistory, error) {
+	condition := "data_source=?"
+	conditionParams := []any{dataSource}
+
+	if currency != "" {
+		condition = condition + " AND currency=?"
+		conditionParams = append(conditionParams, strings.ToUpper(currency))
+	}
+
+	if startDate > 0 {
+		condition = condition + " AND rate_date>=?"
+		conditionParams = append(conditionParams, startDate)
+	}
+
+	if endDate > 0 {
+		condition = condition + " AND rate_date<=?"
+		conditionParams = append(conditionParams, endDate)
+	}
+
+	var histories []*models.ExchangeRateHistory
+	err := s.UserDataDB(0).NewSession(c).Where(condition, conditionParams...).OrderBy("rate_date asc").Find(&histories)
+
+	return histories, err
+}
+
+// GetExchangeRateHistoriesInDateRange returns the daily exchange rates of the given data source and currency between the start date and the end date (both inclusive, 0 means unlimited),
+// the last rate before the start date is also returned so that the rate at the start date can be determined
+func (s *ExchangeRateHistoryService) GetExchangeRateHistoriesInDateRange(c core.Context, dataSource string, currency string, startDate int32, endDate int32) ([]*models.ExchangeRateHistory, error) {
+	if startDate > 0 {
+		previousHistory := &models.ExchangeRateHistory{}
+		has, err := s.UserDataDB(0).NewSession(c).Where("data_source=? AND currency=? AND rate_date<?", dataSource, strings.ToUpper(currency), startDate).OrderBy("rate_date desc").Limit(1).Get(previousHistory)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if has {
+			startDate = previousHistory.RateDate
+		}
+	}
+
+	return s.GetExchangeRateHistories(c, dataSource, currency, startDate, endDate)
+}
+
+// GetExchangeRateHistoriesOfCurrenciesInDateRange returns the daily exchange rates of the given data source and currencies between the start date and the end date (both inclusive, 0 means unlimited),
+// the last rate of each currency before the start date is also returned so that the rate at the start date can be determined, and the rates are grouped by the currencies in upper case
+func (s *ExchangeRateHistoryService) GetExchangeRateHistoriesOfCurrenciesInDateRange(c core.Context, dataSource string, currencies []string, startDate int32, endDate int32) (map[string][]*models.ExchangeRateHistory, error) {
+	currencyRateHistories := make(map[string][]*models.ExchangeRateHistory, len(currencies))
+
+	if len(currencies) < 1 {
+		return currencyRateHistories, nil
+	}
+
+	upperCaseCurrencies := make([]string, len(currencies))
+	currencyStartDates := make(map[string]int32, len(currencies))
+
+	for i := 0; i < len(currencies); i++ {
+		upperCaseCurrencies[i] = strings.ToUpper(currencies[i])
+		currencyStartDates[upperCaseCurrencies[i]] = startDate
+	}
+
+	queryStartDate := startDate
+
+	if startDate > 0 {
+		var previousHistories []*models.ExchangeRateHistory
+		err := s.UserDataDB(0).NewSession(c).Select("currency, MAX(rate_date) AS rate_date").Where("data_source=? AND rate_date<?", dataSource, startDate).In("currency", upperCaseCurrencies).GroupBy("currency").Find(&previousHistories)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(previousHistories); i++ {
+			currencyStartDates[previousHistories[i].Currency] = previousHistories[i].RateDate
+
+			if previousHistories[i].RateDate < queryStartDate {
+				queryStartDate = previousHistories[i].RateDate
+			}
+		}
+	}
+
+	condition := "data_source=?"
+	conditionParams := []any{dataSource}
+
+	if queryStartDate > 0 {
+		condition = condition + " AND rate_date>=?"
+		conditionParams = append(conditionParams, queryStartDate)
+	}
+
+	if endDate > 0 {
+		condition = condition + " AND rate_date<=?"
+		conditionParams = append(conditionParams, endDate)
+	}
+
+	var histories []*models.ExchangeRateHistory
+	err := s.UserDataDB(0).NewSession(c).Where(condition, conditionParams...).In("currency", upperCaseCurrencies).OrderBy("rate_date asc").Find(&histories)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(histories); i++ {
+		history := histories[i]
+
+		if history.RateDate >= currencyStartDates[history.Currency] {
+			currencyRateHistories[history.Currency] = append(currencyRateHistories[history.Currency], history)
+		}
+	}
+
+	return currencyRateHistories, nil
+}
+
+// SaveLatestExchangeRates saves the latest exchange rates as the rates of the day when they were updated under the data source of the response,
+// the rate of the base currency is also saved so that any two currencies of the same day can be converted,
+// and the actual data source of each rate (if it is different from the data source of the response) is saved as the origin data source
+func (s *ExchangeRateHistoryService) SaveLatestExchangeRates(c core.Context, exchangeRateResponse *models.LatestExchangeRateResponse) error {
+	if exchangeRateResponse == nil || exchangeRateResponse.DataSource == "" || exchangeRateResponse.BaseCurrency == "" || len(exchangeRateResponse.ExchangeRates) < 1 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+	updateTime := exchangeRateResponse.UpdateTime
+
+	if updateTime <= 0 {
+		updateTime = now
+	}
+
+	rateDate := utils.FormatUnixTimeToNumericYearMonthDay(updateTime, time.UTC)
+	baseCurrency := strings.ToUpper(exchangeRateResponse.BaseCurrency)
+
+	exchangeRates := make([]*models.LatestExchangeRate, 0, len(exchangeRateResponse.ExchangeRates)+1)
+	exchangeRates = append(exchangeRates, &models.LatestExchangeRate{
+		Currency:   baseCurrency,
+		Rate:       "1",
+		DataSource: s.getBaseCurrencyOriginDataSource(exchangeRateResponse, baseCurrency),
+	})
+	exchangeRates = append(exchangeRates, exchangeRateResponse.ExchangeRates...)
+
+	histories := make([]*models.ExchangeRateHistory, 0, len(exchangeRates))
+	historyIndexes := make(map[string]int, len(exchangeRates))
+
+	for i := 0; i < len(exchangeRates); i++ {
+		exchangeRate := exchangeRates[i]
+
+		if exchangeRate == nil || exchangeRate.Currency == "" || exchangeRate.Rate == "" {
+			continue
+		}
+
+		currency := strings.ToUpper(exchangeRate.Currency)
+
+		if i > 0 && currency == baseCurrency {
+			continue
+		}
+
+		originDataSource := exchangeRate.DataSource
+
+		if originDataSource == "" {
+			originDataSource = exchangeRateResponse.DataSource
+		}
+
+		history := &models.ExchangeRateHistory{
+			DataSource:       exchangeRateResponse.DataSource,
+			Currency:         currency,
+			RateDate:         rateDate,
+			OriginDataSource: originDataSource,
+			BaseCurrency:     baseCurrency,
+			Rate:             exchangeRate.Rate,
+			CreatedUnixTime:  now,
+			UpdatedUnixTime:  now,
+		}
+
+		if index, exists := historyIndexes[currency]; exists {
+			histories[index] = history
+		} else {
+			historyIndexes[currency] = len(histories)
+			histories = append(histories, history)
+		}
+	}
+
+	currencies := make([]string, 0, len(histories))
+
+	for i := 0; i < len(histories); i++ {
+		currencies = append(currencies, histories[i].Currency)
+	}
+
+	// the rates of the same day are upserted by replacing the existed rows in batch, and their created time is retained
+	return s.UserDataDB(0).DoTransaction(c, func(sess *xorm.Session) error {
+		var existedHistories []*models.ExchangeRateHistory
+		err := sess.Cols("data_source", "currency", "rate_date", "created_unix_time").Where("data_source=? AND rate_date=?", exchangeRateResponse.DataSource, rateDate).In("currency", currencies).Find(&existedHistories)
+
+		if err != nil {
+			return err
+		}
+
+		if len(existedHistories) > 0 {
+			existedCurrencies := make([]string, 0, len(existedHistories))
+
+			for i := 0; i < len(existedHistories); i++ {
+				existedHistory := existedHistories[i]
+				existedCurrencies = append(existedCurrencies, existedHistory.Currency)
+
+				if index, exists := historyIndexes[existedHistory.Currency]; exists {
+					histories[index].CreatedUnixTime = existedHistory.CreatedUnixTime
+				}
+			}
+
+			_, err = sess.Where("data_source=? AND rate_date=?", exchangeRateResponse.DataSource, rateDate).In("currency", existedCurrencies).Delete(&models.ExchangeRateHistory{})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = sess.Insert(&histories)
+
+		return err
+	})
+}
+
+func (s *ExchangeRateHistoryService) getBaseCurrencyOriginDataSource(exchangeRateResponse *models.LatestExchangeRateResponse, baseCurrency string) string {
+	for i := 0; i < len(exchangeRateResponse.ExchangeRates); i++ {
+		exchangeRate := exchangeRateResponse.ExchangeRates[i]
+
+		if exchangeRate != nil && exchangeRate.DataSource != "" && strings.ToUpper(exchangeRate.Currency) == baseCurrency {
+			return exchangeRate.DataSource
+		}
+	}
+
+	return exchangeRateResponse.DataSource
+}
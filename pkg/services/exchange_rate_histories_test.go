@@ -0,0 +1,110 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestExchangeRateHistoryGetExchangeRateHistoriesInDateRange(t *testing.T) {
+	initializeTestDataStore(t, new(models.ExchangeRateHistory))
+	c := core.NewNullContext()
+
+	for _, history := range []*models.ExchangeRateHistory{
+		{DataSource: "euro_central_bank", Currency: "USD", RateDate: 20240101, BaseCurrency: "EUR", Rate: "1.10"},
+		{DataSource: "euro_central_bank", Currency: "USD", RateDate: 20240105, BaseCurrency: "EUR", Rate: "1.11"},
+		{DataSource: "euro_central_bank", Currency: "USD", RateDate: 20240110, BaseCurrency: "EUR", Rate: "1.12"},
+		{DataSource: "euro_central_bank", Currency: "USD", RateDate: 20240120, BaseCurrency: "EUR", Rate: "1.13"},
+		{DataSource: "euro_central_bank", Currency: "CNY", RateDate: 20240108, BaseCurrency: "EUR", Rate: "7.8"},
+		{DataSource: "international_monetary_fund", Currency: "USD", RateDate: 20240108, BaseCurrency: "USD", Rate: "1"},
+	} {
+		_, err := ExchangeRateHistories.UserDataDB(0).NewSession(c).Insert(history)
+		assert.Nil(t, err)
+	}
+
+	histories, err := ExchangeRateHistories.GetExchangeRateHistoriesInDateRange(c, "euro_central_bank", "usd", 20240108, 20240115)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 2)
+	assert.Equal(t, int32(20240105), histories[0].RateDate)
+	assert.Equal(t, "1.11", histories[0].Rate)
+	assert.Equal(t, int32(20240110), histories[1].RateDate)
+
+	histories, err = ExchangeRateHistories.GetExchangeRateHistoriesInDateRange(c, "euro_central_bank", "USD", 20231201, 20240101)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 1)
+	assert.Equal(t, int32(20240101), histories[0].RateDate)
+
+	histories, err = ExchangeRateHistories.GetExchangeRateHistoriesInDateRange(c, "euro_central_bank", "USD", 0, 0)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 4)
+}
+
+func TestExchangeRateHistorySaveLatestExchangeRates_UpsertSameDay(t *testing.T) {
+	initializeTestDataStore(t, new(models.ExchangeRateHistory))
+	c := core.NewNullContext()
+
+	err := ExchangeRateHistories.SaveLatestExchangeRates(c, &models.LatestExchangeRateResponse{
+		DataSource:   "euro_central_bank",
+		BaseCurrency: "EUR",
+		UpdateTime:   1704153600,
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.10"},
+			{Currency: "CNY", Rate: "7.8"},
+		},
+	})
+	assert.Nil(t, err)
+
+	err = ExchangeRateHistories.SaveLatestExchangeRates(c, &models.LatestExchangeRateResponse{
+		DataSource:   "euro_central_bank",
+		BaseCurrency: "EUR",
+		UpdateTime:   1704157200,
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.11"},
+			{Currency: "JPY", Rate: "160"},
+		},
+	})
+	assert.Nil(t, err)
+
+	var histories []*models.ExchangeRateHistory
+	err = ExchangeRateHistories.UserDataDB(0).NewSession(c).Where("data_source=?", "euro_central_bank").OrderBy("currency asc").Find(&histories)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 4)
+	assert.Equal(t, "CNY", histories[0].Currency)
+	assert.Equal(t, "7.8", histories[0].Rate)
+	assert.Equal(t, "EUR", histories[1].Currency)
+	assert.Equal(t, "1", histories[1].Rate)
+	assert.Equal(t, "JPY", histories[2].Currency)
+	assert.Equal(t, "USD", histories[3].Currency)
+	assert.Equal(t, "1.11", histories[3].Rate)
+	assert.Equal(t, int32(20240102), histories[3].RateDate)
+}
+
+func TestExchangeRateHistoryGetExchangeRateHistoriesOfCurrenciesInDateRange(t *testing.T) {
+	initializeTestDataStore(t, new(models.ExchangeRateHistory))
+	c := core.NewNullContext()
+
+	for _, history := range []*models.ExchangeRateHistory{
+		{DataSource: "euro_central_bank", Currency: "USD", RateDate: 20231230, BaseCurrency: "EUR", Rate: "1.09"},
+		{DataSource: "euro_central_bank", Currency: "USD", RateDate: 20240105, BaseCurrency: "EUR", Rate: "1.11"},
+		{DataSource: "euro_central_bank", Currency: "USD", RateDate: 20240110, BaseCurrency: "EUR", Rate: "1.12"},
+		{DataSource: "euro_central_bank", Currency: "CNY", RateDate: 20231225, BaseCurrency: "EUR", Rate: "7.7"},
+		{DataSource: "euro_central_bank", Currency: "CNY", RateDate: 20240108, BaseCurrency: "EUR", Rate: "7.8"},
+		{DataSource: "international_monetary_fund", Currency: "USD", RateDate: 20240108, BaseCurrency: "USD", Rate: "1"},
+	} {
+		_, err := ExchangeRateHistories.UserDataDB(0).NewSession(c).Insert(history)
+		assert.Nil(t, err)
+	}
+
+	currencyHistories, err := ExchangeRateHistories.GetExchangeRateHistoriesOfCurrenciesInDateRange(c, "euro_central_bank", []string{"usd", "CNY", "JPY"}, 20240108, 20240115)
+	assert.Nil(t, err)
+	assert.Len(t, currencyHistories, 2)
+	assert.Len(t, currencyHistories["USD"], 2)
+	assert.Equal(t, int32(20240105), currencyHistories["USD"][0].RateDate)
+	assert.Equal(t, int32(20240110), currencyHistories["USD"][1].RateDate)
+	assert.Len(t, currencyHistories["CNY"], 2)
+	assert.Equal(t, int32(20231225), currencyHistories["CNY"][0].RateDate)
+	assert.Equal(t, int32(20240108), currencyHistories["CNY"][1].RateDate)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 )
 
@@ -22,10 +23,10 @@ var (
 	}
 )
 
-// GetConfig returns the config for a specific type
+// GetConfig returns the config for a specific type, the earliest saved config would be returned if there are multiple configs
 func (s *ExternalDataSourceConfigService) GetConfig(c core.Context, configType models.ExternalDataSourceType) (*models.ExternalDataSourceConfig, error) {
 	config := &models.ExternalDataSourceConfig{}
-	has, err := s.UserDataDB(0).NewSession(c).Where("type=?", configType).Get(config)
+	has, err := s.UserDataDB(0).NewSession(c).Where("type=?", configType).OrderBy("config_id asc").Limit(1).Get(config)
 	if err != nil {
 		return nil, err
 	} else if !has {
@@ -34,16 +35,31 @@ func (s *ExternalDataSourceConfigService) GetConfig(c core.Context, configType m
 	return config, nil
 }
 
-// SaveConfig saves or updates the config
+// GetConfigs returns all the configs for a specific type in saved order
+func (s *ExternalDataSourceConfigService) GetConfigs(c core.Context, configType models.ExternalDataSourceType) ([]*models.ExternalDataSourceConfig, error) {
+	var configs []*models.ExternalDataSourceConfig
+	err := s.UserDataDB(0).NewSession(c).Where("type=?", configType).OrderBy("config_id asc").Find(&configs)
+
+	return configs, err
+}
+
+// SaveConfig saves or updates the config, stock type allows one config for each data source and other types allow only one config
 func (s *ExternalDataSourceConfigService) SaveConfig(c core.Context, config *models.ExternalDataSourceConfig) error {
 	now := time.Now().Unix()
-	
-	existingConfig, err := s.GetConfig(c, config.Type)
+
+	existingConfig := &models.ExternalDataSourceConfig{}
+	sess := s.UserDataDB(0).NewSession(c).Where("type=?", config.Type)
+
+	if config.Type == models.EXTERNAL_DATA_SOURCE_TYPE_STOCK {
+		sess = sess.And("data_source=?", config.DataSource)
+	}
+
+	has, err := sess.OrderBy("config_id asc").Limit(1).Get(existingConfig)
 	if err != nil {
 		return err
 	}
 
-	if existingConfig == nil {
+	if !has {
 		config.CreatedUnixTime = now
 		config.UpdatedUnixTime = now
 		_, err = s.UserDataDB(0).NewSession(c).Insert(config)
@@ -55,3 +71,16 @@ func (s *ExternalDataSourceConfigService) SaveConfig(c core.Context, config *mod
 
 	return err
 }
+
+// DeleteStockConfig deletes the stock config of the specified data source
+func (s *ExternalDataSourceConfigService) DeleteStockConfig(c core.Context, dataSource string) error {
+	deletedRows, err := s.UserDataDB(0).NewSession(c).Where("type=? AND data_source=?", models.EXTERNAL_DATA_SOURCE_TYPE_STOCK, dataSource).Delete(&models.ExternalDataSourceConfig{})
+
+	if err != nil {
+		return err
+	} else if deletedRows < 1 {
+		return errs.ErrStockDataSourceConfigNotFound
+	}
+
+	return nil
+}
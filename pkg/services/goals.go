@@ -0,0 +1,330 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// GoalService represents savings goal service
+type GoalService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+	accounts     *AccountService
+	transactions *TransactionService
+}
+
+// Initialize a savings goal service singleton instance
+var (
+	Goals = &GoalService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		accounts:     Accounts,
+		transactions: Transactions,
+	}
+)
+
+// GetAllGoalsByUid returns all savings goal models of user
+func (s *GoalService) GetAllGoalsByUid(c core.Context, uid int64) ([]*models.Goal, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var goals []*models.Goal
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc, goal_id asc").Find(&goals)
+
+	return goals, err
+}
+
+// GetGoalByGoalId returns a savings goal model according to savings goal id
+func (s *GoalService) GetGoalByGoalId(c core.Context, uid int64, goalId int64) (*models.Goal, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if goalId <= 0 {
+		return nil, errs.ErrGoalIdInvalid
+	}
+
+	goal := &models.Goal{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(goalId).Where("uid=? AND deleted=?", uid, false).Get(goal)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrGoalNotFound
+	}
+
+	return goal, nil
+}
+
+// GetMaxDisplayOrder returns the max display order of savings goals
+func (s *GoalService) GetMaxDisplayOrder(c core.Context, uid int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	goal := &models.Goal{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(goal)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return goal.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreateGoal saves a new savings goal model to database
+func (s *GoalService) CreateGoal(c core.Context, goal *models.Goal) error {
+	if goal.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.isGoalValid(c, goal)
+
+	if err != nil {
+		return err
+	}
+
+	goal.GoalId = s.GenerateUuid(uuid.UUID_TYPE_GOAL)
+
+	if goal.GoalId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	goal.Deleted = false
+	goal.CreatedUnixTime = time.Now().Unix()
+	goal.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(goal.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(goal)
+		return err
+	})
+}
+
+// ModifyGoal saves an existed savings goal model to database
+func (s *GoalService) ModifyGoal(c core.Context, goal *models.Goal) error {
+	if goal.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if goal.GoalId <= 0 {
+		return errs.ErrGoalIdInvalid
+	}
+
+	err := s.isGoalValid(c, goal)
+
+	if err != nil {
+		return err
+	}
+
+	goal.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(goal.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(goal.GoalId).Cols("name", "account_ids", "currency", "target_amount", "target_unix_time", "updated_unix_time").Where("uid=? AND deleted=?", goal.Uid, false).Update(goal)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrGoalNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteGoal deletes an existed savings goal from database
+func (s *GoalService) DeleteGoal(c core.Context, uid int64, goalId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if goalId <= 0 {
+		return errs.ErrGoalIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Goal{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(goalId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrGoalNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteAllGoals deletes all existed savings goals from database
+func (s *GoalService) DeleteAllGoals(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Goal{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+// GetGoalMonthEndBalances returns the month-end balances of all the specified accounts in recent months which are used to compute the progress of savings goals,
+// the returned balances can be shared by the progresses of all savings goals of the user
+func (s *GoalService) GetGoalMonthEndBalances(c core.Context, uid int64, allAccounts []*models.Account, now time.Time, clientTimezone *time.Location) ([]map[int64]int64, error) {
+	historyStartTime := models.GetGoalBalanceHistoryStartTime(now, clientTimezone)
+	_, monthEndDates := s.getGoalMonthEndTimes(historyStartTime, clientTimezone)
+	dailyBalances, err := s.transactions.GetAllAccountsDailyOpeningAndClosingBalance(c, uid, 0, utils.GetMinTransactionTimeFromUnixTime(historyStartTime.Unix()), clientTimezone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accountIds := make(map[int64]bool, len(allAccounts))
+
+	for i := 0; i < len(allAccounts); i++ {
+		accountIds[allAccounts[i].AccountId] = true
+	}
+
+	return models.GetAccountBalancesAtDates(dailyBalances, accountIds, monthEndDates), nil
+}
+
+// GetGoalProgress returns the progress of the savings goal according to the current balances and the specified month-end balances of recent months of the linked accounts,
+// all the balances are valued in the goal currency by the specified valuator, and the sub-accounts of the linked accounts are also included
+func (s *GoalService) GetGoalProgress(c core.Context, goal *models.Goal, allAccounts []*models.Account, monthEndBalances []map[int64]int64, valuator *models.AssetValuator, now time.Time, clientTimezone *time.Location) (*models.GoalProgressResponse, error) {
+	if len(monthEndBalances) != models.GoalBalanceHistoryMonths {
+		return nil, errs.ErrOperationFailed
+	}
+
+	goalAccountIds := make(map[int64]bool)
+
+	for _, accountId := range goal.GetAccountIds() {
+		goalAccountIds[accountId] = true
+	}
+
+	accounts := make([]*models.Account, 0, len(goalAccountIds))
+
+	for i := 0; i < len(allAccounts); i++ {
+		account := allAccounts[i]
+
+		if goalAccountIds[account.AccountId] || goalAccountIds[account.ParentAccountId] {
+			accounts = append(accounts, account)
+		}
+	}
+
+	historyStartTime := models.GetGoalBalanceHistoryStartTime(now, clientTimezone)
+	monthEndUnixTimes, monthEndDates := s.getGoalMonthEndTimes(historyStartTime, clientTimezone)
+
+	complete := true
+	balanceHistory := make([]*models.GoalBalanceHistoryItem, models.GoalBalanceHistoryMonths)
+
+	for i := 0; i < models.GoalBalanceHistoryMonths; i++ {
+		amount := int64(0)
+
+		for j := 0; j < len(accounts); j++ {
+			account := accounts[j]
+			value, ok := valuator.GetValue(account.GetAssetType(), account.Currency, monthEndBalances[i][account.AccountId], monthEndDates[i])
+
+			if !ok {
+				complete = false
+				continue
+			}
+
+			amount += value
+		}
+
+		balanceHistory[i] = &models.GoalBalanceHistoryItem{
+			Time:   monthEndUnixTimes[i],
+			Amount: amount,
+		}
+	}
+
+	currentAmount := int64(0)
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		value, ok := valuator.GetValue(account.GetAssetType(), account.Currency, account.Balance, 0)
+
+		if !ok {
+			complete = false
+			continue
+		}
+
+		currentAmount += value
+	}
+
+	progress := goal.ToGoalProgressResponse(currentAmount, balanceHistory, now.Unix(), clientTimezone)
+	progress.IncompleteAmount = !complete
+
+	return progress, nil
+}
+
+func (s *GoalService) isGoalValid(c core.Context, goal *models.Goal) error {
+	if goal.TargetAmount <= 0 {
+		return errs.ErrGoalTargetAmountInvalid
+	}
+
+	accountIds := goal.GetAccountIds()
+
+	if len(accountIds) < 1 {
+		return errs.ErrGoalAccountInvalid
+	}
+
+	if len(accountIds) > models.MaximumAccountsCountOfGoal {
+		return errs.ErrTooManyGoalAccounts
+	}
+
+	accountMap, err := s.accounts.GetAccountsByAccountIds(c, goal.Uid, accountIds)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(accountIds); i++ {
+		if _, exists := accountMap[accountIds[i]]; !exists {
+			return errs.ErrGoalAccountInvalid
+		}
+	}
+
+	return nil
+}
+
+func (s *GoalService) getGoalMonthEndTimes(historyStartTime time.Time, clientTimezone *time.Location) ([]int64, []int32) {
+	monthEndUnixTimes := make([]int64, models.GoalBalanceHistoryMonths)
+	monthEndDates := make([]int32, models.GoalBalanceHistoryMonths)
+
+	for i := 0; i < models.GoalBalanceHistoryMonths; i++ {
+		monthEndUnixTimes[i] = historyStartTime.AddDate(0, i+1, 0).Unix() - 1
+		monthEndDates[i] = utils.FormatUnixTimeToNumericYearMonthDay(monthEndUnixTimes[i], clientTimezone)
+	}
+
+	return monthEndUnixTimes, monthEndDates
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestGetGoalProgress_SharedMonthEndBalances(t *testing.T) {
+	initializeTestDataStore(t, new(models.Account), new(models.Transaction))
+
+	c := core.NewNullContext()
+	sess := Transactions.UserDataDB(1).NewSession(c)
+	now := time.Now()
+	historyStartTime := models.GetGoalBalanceHistoryStartTime(now, time.UTC)
+	beforeHistoryTime := utils.GetMinTransactionTimeFromUnixTime(historyStartTime.AddDate(0, 0, -10).Unix())
+
+	_, err := sess.Insert(&models.Account{AccountId: 1001, Uid: 1, Name: "Savings Account", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 2000})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Account{AccountId: 1002, Uid: 1, Name: "Deposit Account", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 3000})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{TransactionId: 2001, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1001, TransactionTime: beforeHistoryTime, Amount: 2000})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{TransactionId: 2002, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1002, TransactionTime: beforeHistoryTime + 1, Amount: 3000})
+	assert.Nil(t, err)
+
+	accounts, err := Accounts.GetAllAccountsByUid(c, 1)
+	assert.Nil(t, err)
+
+	monthEndBalances, err := Goals.GetGoalMonthEndBalances(c, 1, accounts, now, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, models.GoalBalanceHistoryMonths, len(monthEndBalances))
+
+	valuator := models.NewAssetValuator("USD", map[string]float64{"USD": 1})
+
+	progress, err := Goals.GetGoalProgress(c, &models.Goal{GoalId: 1, Uid: 1, AccountIds: "1001", Currency: "USD", TargetAmount: 10000}, accounts, monthEndBalances, valuator, now, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2000), progress.CurrentAmount)
+	assert.Equal(t, int64(2000), progress.BalanceHistory[0].Amount)
+	assert.Equal(t, int64(2000), progress.BalanceHistory[models.GoalBalanceHistoryMonths-1].Amount)
+
+	progress, err = Goals.GetGoalProgress(c, &models.Goal{GoalId: 2, Uid: 1, AccountIds: "1002", Currency: "USD", TargetAmount: 10000}, accounts, monthEndBalances, valuator, now, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3000), progress.CurrentAmount)
+	assert.Equal(t, int64(3000), progress.BalanceHistory[0].Amount)
+	assert.Equal(t, int64(3000), progress.BalanceHistory[models.GoalBalanceHistoryMonths-1].Amount)
+	assert.False(t, progress.IncompleteAmount)
+}
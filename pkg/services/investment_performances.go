@@ -0,0 +1,373 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	xirrMaxIterations  = 200
+	xirrTolerance      = 1e-9
+	xirrMinRate        = -0.999999
+	xirrMaxRate        = 1e6
+	daysPerYearForXirr = 365
+)
+
+// InvestmentPerformanceService represents investment performance service
+type InvestmentPerformanceService struct {
+	transactions     *TransactionService
+	investmentTrades *InvestmentTradeService
+}
+
+// Initialize an investment performance service singleton instance
+var (
+	InvestmentPerformances = &InvestmentPerformanceService{
+		transactions:     Transactions,
+		investmentTrades: InvestmentTrades,
+	}
+)
+
+// GetAccountsValuationPoints returns the valuation points of given accounts between the start time and the end time,
+// the first point of each account is the opening value on the start date, and the following points are the closing values of the days
+// when any of the accounts has transactions (the start date and the end date are always included), so the points of all accounts can be merged.
+// The transfers into or out of the accounts, the balance modifications and the income or expense transactions (e.g. the buys and sells
+// of stocks and cryptocurrencies) are treated as external cash flows, except the income of holdings (e.g. dividends and interests)
+// and the shares increased by stock splits, and the points of an account would be nil if the account cannot be valued
+func (s *InvestmentPerformanceService) GetAccountsValuationPoints(c core.Context, uid int64, accounts []*models.Account, startTime int64, endTime int64, valuator *models.AssetValuator, clientTimezone *time.Location) (map[int64][]*models.InvestmentValuationPoint, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	accountPoints := make(map[int64][]*models.InvestmentValuationPoint, len(accounts))
+
+	if len(accounts) < 1 {
+		return accountPoints, nil
+	}
+
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(endTime)
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(startTime)
+	startDate := utils.FormatUnixTimeToNumericYearMonthDay(startTime, clientTimezone)
+	endDate := utils.FormatUnixTimeToNumericYearMonthDay(endTime, clientTimezone)
+
+	accountMap := make(map[int64]*models.Account, len(accounts))
+	accountIds := make([]int64, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		accountMap[accounts[i].AccountId] = accounts[i]
+		accountIds[i] = accounts[i].AccountId
+	}
+
+	accountDailyBalances, err := s.transactions.GetAllAccountsDailyOpeningAndClosingBalance(c, uid, maxTransactionTime, minTransactionTime, clientTimezone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.transactions.GetAllSpecifiedTransactions(c, uid, maxTransactionTime, minTransactionTime, 0, nil, accountIds, nil, false, "", "", pageCountForLoadTransactionAmounts, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	trades, err := s.investmentTrades.GetAllTradesByAccountIds(c, uid, accountIds)
+
+	if err != nil {
+		return nil, err
+	}
+
+	splitTransactionIds := make(map[int64]bool)
+
+	for i := 0; i < len(trades); i++ {
+		if trades[i].Type == models.INVESTMENT_TRADE_TYPE_SPLIT && trades[i].TransactionId > 0 {
+			splitTransactionIds[trades[i].TransactionId] = true
+		}
+	}
+
+	allDates := map[int32]bool{
+		startDate: true,
+		endDate:   true,
+	}
+	openingBalances := make(map[int64]int64, len(accounts))
+	firstBalanceDates := make(map[int64]int32, len(accounts))
+	closingBalances := make(map[int64]map[int32]int64, len(accounts))
+	cashFlows := make(map[int64]map[int32]int64, len(accounts))
+
+	for yearMonthDay, dailyAccountBalances := range accountDailyBalances {
+		for i := 0; i < len(dailyAccountBalances); i++ {
+			accountBalance := dailyAccountBalances[i]
+
+			if _, exists := accountMap[accountBalance.AccountId]; !exists {
+				continue
+			}
+
+			if firstDate, exists := firstBalanceDates[accountBalance.AccountId]; !exists || yearMonthDay < firstDate {
+				firstBalanceDates[accountBalance.AccountId] = yearMonthDay
+				openingBalances[accountBalance.AccountId] = accountBalance.AccountOpeningBalance
+			}
+
+			if _, exists := closingBalances[accountBalance.AccountId]; !exists {
+				closingBalances[accountBalance.AccountId] = make(map[int32]int64)
+			}
+
+			closingBalances[accountBalance.AccountId][yearMonthDay] = accountBalance.AccountClosingBalance
+			allDates[yearMonthDay] = true
+		}
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if _, exists := accountMap[transaction.AccountId]; !exists {
+			continue
+		}
+
+		var cashFlow int64
+
+		switch transaction.Type {
+		case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+			cashFlow = transaction.RelatedAccountAmount
+		case models.TRANSACTION_DB_TYPE_INCOME:
+			// the income of holdings is the return of the investment rather than the external cash flow,
+			// and the shares increased by stock splits do not change the value of the investment
+			if transaction.HoldingAccountId > 0 || splitTransactionIds[transaction.TransactionId] {
+				continue
+			}
+
+			cashFlow = transaction.Amount
+		case models.TRANSACTION_DB_TYPE_EXPENSE:
+			cashFlow = -transaction.Amount
+		case models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+			cashFlow = transaction.Amount
+		case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+			cashFlow = -transaction.Amount
+		default:
+			continue
+		}
+
+		yearMonthDay := utils.FormatUnixTimeToNumericYearMonthDay(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), clientTimezone)
+
+		if _, exists := cashFlows[transaction.AccountId]; !exists {
+			cashFlows[transaction.AccountId] = make(map[int32]int64)
+		}
+
+		cashFlows[transaction.AccountId][yearMonthDay] += cashFlow
+		allDates[yearMonthDay] = true
+	}
+
+	dates := make([]int32, 0, len(allDates))
+
+	for yearMonthDay := range allDates {
+		if yearMonthDay >= startDate && yearMonthDay <= endDate {
+			dates = append(dates, yearMonthDay)
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i] < dates[j]
+	})
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		assetType := account.GetAssetType()
+		balance := openingBalances[account.AccountId]
+		openingValue, ok := valuator.GetValue(assetType, account.Currency, balance, startDate)
+
+		if !ok {
+			accountPoints[account.AccountId] = nil
+			continue
+		}
+
+		points := make([]*models.InvestmentValuationPoint, 0, len(dates)+1)
+		points = append(points, &models.InvestmentValuationPoint{
+			Date:  startDate,
+			Value: openingValue,
+		})
+
+		for j := 0; j < len(dates); j++ {
+			if closingBalance, exists := closingBalances[account.AccountId][dates[j]]; exists {
+				balance = closingBalance
+			}
+
+			value, hasValue := valuator.GetValue(assetType, account.Currency, balance, dates[j])
+			cashFlow, hasCashFlow := int64(0), true
+
+			if flow := cashFlows[account.AccountId][dates[j]]; flow != 0 {
+				cashFlow, hasCashFlow = valuator.GetValue(assetType, account.Currency, flow, dates[j])
+			}
+
+			if !hasValue || !hasCashFlow {
+				points = nil
+				break
+			}
+
+			points = append(points, &models.InvestmentValuationPoint{
+				Date:     dates[j],
+				Value:    value,
+				CashFlow: cashFlow,
+			})
+		}
+
+		accountPoints[account.AccountId] = points
+	}
+
+	return accountPoints, nil
+}
+
+// MergeValuationPoints returns the sum of the valuation points of multiple accounts which have the same dates,
+// the accounts which cannot be valued are skipped and the merged points are reported as incomplete,
+// and returns nil if none of the accounts can be valued
+func (s *InvestmentPerformanceService) MergeValuationPoints(allPoints [][]*models.InvestmentValuationPoint) ([]*models.InvestmentValuationPoint, bool) {
+	var mergedPoints []*models.InvestmentValuationPoint
+	incomplete := false
+
+	for i := 0; i < len(allPoints); i++ {
+		points := allPoints[i]
+
+		if points == nil {
+			incomplete = true
+			continue
+		}
+
+		if mergedPoints == nil {
+			mergedPoints = make([]*models.InvestmentValuationPoint, len(points))
+		} else if len(points) != len(mergedPoints) {
+			incomplete = true
+			continue
+		}
+
+		for j := 0; j < len(points); j++ {
+			if mergedPoints[j] == nil {
+				mergedPoints[j] = &models.InvestmentValuationPoint{
+					Date: points[j].Date,
+				}
+			}
+
+			mergedPoints[j].Value += points[j].Value
+			mergedPoints[j].CashFlow += points[j].CashFlow
+		}
+	}
+
+	return mergedPoints, incomplete
+}
+
+// CalculatePerformance returns the time-weighted return and the annualized money-weighted return (XIRR) according to the valuation points,
+// the first point is treated as the opening value and the cash flows of the other points are assumed to happen at the end of the day
+func (s *InvestmentPerformanceService) CalculatePerformance(points []*models.InvestmentValuationPoint) *models.InvestmentPerformance {
+	performance := &models.InvestmentPerformance{}
+
+	if len(points) < 1 {
+		return performance
+	}
+
+	performance.StartValue = points[0].Value
+	performance.EndValue = points[len(points)-1].Value
+
+	growth := 1.0
+	startDate := points[0].Date
+	amounts := []float64{-float64(points[0].Value)}
+	years := []float64{0}
+
+	for i := 1; i < len(points); i++ {
+		point := points[i]
+		performance.NetCashFlow += point.CashFlow
+
+		// the period without any capital at the beginning does not affect the time-weighted return
+		if points[i-1].Value > 0 {
+			growth *= float64(point.Value-point.CashFlow) / float64(points[i-1].Value)
+			performance.HasTimeWeightedReturn = true
+		}
+
+		if point.CashFlow != 0 {
+			amounts = append(amounts, -float64(point.CashFlow))
+			years = append(years, getYearsBetweenDates(startDate, point.Date))
+		}
+	}
+
+	if performance.HasTimeWeightedReturn {
+		performance.TimeWeightedReturn = growth - 1
+	}
+
+	amounts = append(amounts, float64(performance.EndValue))
+	years = append(years, getYearsBetweenDates(startDate, points[len(points)-1].Date))
+
+	performance.MoneyWeightedReturn, performance.HasMoneyWeightedReturn = calculateXirr(amounts, years)
+
+	return performance
+}
+
+func calculateXirr(amounts []float64, years []float64) (float64, bool) {
+	hasPositive := false
+	hasNegative := false
+	hasFutureAmount := false
+
+	for i := 0; i < len(amounts); i++ {
+		if amounts[i] > 0 {
+			hasPositive = true
+		} else if amounts[i] < 0 {
+			hasNegative = true
+		}
+
+		if amounts[i] != 0 && years[i] > 0 {
+			hasFutureAmount = true
+		}
+	}
+
+	if !hasPositive || !hasNegative || !hasFutureAmount {
+		return 0, false
+	}
+
+	netPresentValue := func(rate float64) float64 {
+		value := 0.0
+
+		for i := 0; i < len(amounts); i++ {
+			value += amounts[i] / math.Pow(1+rate, years[i])
+		}
+
+		return value
+	}
+
+	low := xirrMinRate
+	high := 1.0
+	lowValue := netPresentValue(low)
+	highValue := netPresentValue(high)
+
+	for (lowValue > 0) == (highValue > 0) {
+		if high >= xirrMaxRate {
+			return 0, false
+		}
+
+		high *= 2
+		highValue = netPresentValue(high)
+	}
+
+	for i := 0; i < xirrMaxIterations; i++ {
+		middle := (low + high) / 2
+		middleValue := netPresentValue(middle)
+
+		if math.Abs(middleValue) < xirrTolerance || (high-low)/2 < xirrTolerance {
+			return middle, true
+		}
+
+		if (middleValue > 0) == (lowValue > 0) {
+			low = middle
+			lowValue = middleValue
+		} else {
+			high = middle
+		}
+	}
+
+	return (low + high) / 2, true
+}
+
+func getYearsBetweenDates(startDate int32, endDate int32) float64 {
+	startTime := time.Date(int(startDate/10000), time.Month(startDate%10000/100), int(startDate%100), 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(int(endDate/10000), time.Month(endDate%10000/100), int(endDate%100), 0, 0, 0, 0, time.UTC)
+
+	return endTime.Sub(startTime).Hours() / 24 / daysPerYearForXirr
+}
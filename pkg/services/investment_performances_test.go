@@ -0,0 +1,138 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestCalculatePerformance_NoCashFlow(t *testing.T) {
+	performance := InvestmentPerformances.CalculatePerformance([]*models.InvestmentValuationPoint{
+		{Date: 20230101, Value: 100000},
+		{Date: 20230101, Value: 100000},
+		{Date: 20240101, Value: 110000},
+	})
+
+	assert.Equal(t, int64(100000), performance.StartValue)
+	assert.Equal(t, int64(110000), performance.EndValue)
+	assert.Equal(t, int64(0), performance.NetCashFlow)
+	assert.True(t, performance.HasTimeWeightedReturn)
+	assert.InDelta(t, 0.1, performance.TimeWeightedReturn, 1e-9)
+	assert.True(t, performance.HasMoneyWeightedReturn)
+	assert.InDelta(t, 0.1, performance.MoneyWeightedReturn, 1e-6)
+}
+
+func TestCalculatePerformance_WithCashFlow(t *testing.T) {
+	performance := InvestmentPerformances.CalculatePerformance([]*models.InvestmentValuationPoint{
+		{Date: 20230101, Value: 100000},
+		{Date: 20230702, Value: 220000, CashFlow: 100000},
+		{Date: 20231231, Value: 242000},
+	})
+
+	assert.Equal(t, int64(100000), performance.NetCashFlow)
+	assert.True(t, performance.HasTimeWeightedReturn)
+	assert.InDelta(t, 0.32, performance.TimeWeightedReturn, 1e-9)
+	assert.True(t, performance.HasMoneyWeightedReturn)
+	assert.InDelta(t, 0.286, performance.MoneyWeightedReturn, 0.01)
+}
+
+func TestCalculatePerformance_StartWithoutValue(t *testing.T) {
+	performance := InvestmentPerformances.CalculatePerformance([]*models.InvestmentValuationPoint{
+		{Date: 20230101, Value: 0},
+		{Date: 20230101, Value: 100000, CashFlow: 100000},
+		{Date: 20240101, Value: 90000},
+	})
+
+	assert.True(t, performance.HasTimeWeightedReturn)
+	assert.InDelta(t, -0.1, performance.TimeWeightedReturn, 1e-9)
+	assert.True(t, performance.HasMoneyWeightedReturn)
+	assert.InDelta(t, -0.1, performance.MoneyWeightedReturn, 1e-6)
+}
+
+func TestCalculatePerformance_NoValue(t *testing.T) {
+	performance := InvestmentPerformances.CalculatePerformance([]*models.InvestmentValuationPoint{
+		{Date: 20230101, Value: 0},
+		{Date: 20240101, Value: 0},
+	})
+
+	assert.False(t, performance.HasTimeWeightedReturn)
+	assert.False(t, performance.HasMoneyWeightedReturn)
+}
+
+func TestMergeValuationPoints(t *testing.T) {
+	points, incomplete := InvestmentPerformances.MergeValuationPoints([][]*models.InvestmentValuationPoint{
+		{{Date: 20230101, Value: 100}, {Date: 20230201, Value: 200, CashFlow: 50}},
+		{{Date: 20230101, Value: 300}, {Date: 20230201, Value: 100, CashFlow: -50}},
+	})
+
+	assert.False(t, incomplete)
+	assert.Equal(t, 2, len(points))
+	assert.Equal(t, int32(20230101), points[0].Date)
+	assert.Equal(t, int64(400), points[0].Value)
+	assert.Equal(t, int64(300), points[1].Value)
+	assert.Equal(t, int64(0), points[1].CashFlow)
+}
+
+func TestMergeValuationPoints_AccountWithoutValue(t *testing.T) {
+	points, incomplete := InvestmentPerformances.MergeValuationPoints([][]*models.InvestmentValuationPoint{
+		nil,
+		{{Date: 20230101, Value: 100}, {Date: 20230201, Value: 120, CashFlow: 10}},
+	})
+
+	assert.True(t, incomplete)
+	assert.Equal(t, 2, len(points))
+	assert.Equal(t, int64(100), points[0].Value)
+	assert.Equal(t, int64(120), points[1].Value)
+	assert.Equal(t, int64(10), points[1].CashFlow)
+}
+
+func TestMergeValuationPoints_NoAccountWithValue(t *testing.T) {
+	points, incomplete := InvestmentPerformances.MergeValuationPoints([][]*models.InvestmentValuationPoint{
+		nil,
+		nil,
+	})
+
+	assert.True(t, incomplete)
+	assert.Nil(t, points)
+}
+
+func TestGetAccountsValuationPoints_BuyOnlyPeriod(t *testing.T) {
+	initializeTestDataStore(t, new(models.Account), new(models.Transaction), new(models.InvestmentTrade))
+
+	c := core.NewNullContext()
+	sess := InvestmentPerformances.transactions.UserDataDB(1).NewSession(c)
+	account := &models.Account{AccountId: 1001, Uid: 1, Name: "Brokerage", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "AAPL", Extend: &models.AccountExtend{AssetType: models.ACCOUNT_ASSET_TYPE_STOCK}}
+
+	_, err := sess.Insert(account)
+	assert.Nil(t, err)
+
+	// buy 10 shares before the period and 5 shares in the period, both are recorded as income transactions of the quantity
+	_, err = sess.Insert(&models.Transaction{TransactionId: 2001, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 201, AccountId: 1001, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC).Unix()), Amount: 1000})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{TransactionId: 2002, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 201, AccountId: 1001, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC).Unix()), Amount: 500})
+	assert.Nil(t, err)
+
+	valuator := models.NewAssetValuator("USD", map[string]float64{"USD": 1})
+	valuator.SetLatestPrice(models.ACCOUNT_ASSET_TYPE_STOCK, "AAPL", &models.AssetPrice{Price: 100, Currency: "USD", PriceDate: 20220101})
+
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	endTime := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC).Unix()
+	accountPoints, err := InvestmentPerformances.GetAccountsValuationPoints(c, 1, []*models.Account{account}, startTime, endTime, valuator, time.UTC)
+	assert.Nil(t, err)
+
+	performance := InvestmentPerformances.CalculatePerformance(accountPoints[1001])
+
+	assert.Equal(t, int64(100000), performance.StartValue)
+	assert.Equal(t, int64(150000), performance.EndValue)
+	assert.Equal(t, int64(50000), performance.NetCashFlow)
+	assert.True(t, performance.HasTimeWeightedReturn)
+	assert.InDelta(t, 0, performance.TimeWeightedReturn, 1e-9)
+	assert.True(t, performance.HasMoneyWeightedReturn)
+	assert.InDelta(t, 0, performance.MoneyWeightedReturn, 1e-6)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// InvestmentRealizedGainService represents investment realized gain service
+type InvestmentRealizedGainService struct {
+	trades *InvestmentTradeService
+}
+
+// Initialize an investment realized gain service singleton instance
+var (
+	InvestmentRealizedGains = &InvestmentRealizedGainService{
+		trades: InvestmentTrades,
+	}
+)
+
+// GetRealizedGains returns the realized gains of given accounts whose sell time is in the specified range (start time inclusive, end time exclusive)
+func (s *InvestmentRealizedGainService) GetRealizedGains(c core.Context, uid int64, accountIds []int64, startTime int64, endTime int64, method models.InvestmentLotMatchingMethod) ([]*models.InvestmentRealizedGain, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if len(accountIds) < 1 {
+		return make([]*models.InvestmentRealizedGain, 0), nil
+	}
+
+	trades, err := s.trades.GetAllTradesByAccountIds(c, uid, accountIds)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accountTrades := make(map[int64][]*models.InvestmentTrade)
+
+	for i := 0; i < len(trades); i++ {
+		accountTrades[trades[i].AccountId] = append(accountTrades[trades[i].AccountId], trades[i])
+	}
+
+	positions := make([]*models.InvestmentPosition, 0, len(accountTrades))
+
+	for i := 0; i < len(accountIds); i++ {
+		if len(accountTrades[accountIds[i]]) < 1 {
+			continue
+		}
+
+		position, err := s.trades.CalculatePosition(accountTrades[accountIds[i]], method)
+
+		if err != nil {
+			return nil, err
+		}
+
+		positions = append(positions, position)
+	}
+
+	return s.GetRealizedGainsFromPositions(positions, startTime, endTime), nil
+}
+
+// GetRealizedGainsFromPositions returns the realized gains of given positions whose sell time is in the specified range (start time inclusive, end time exclusive)
+func (s *InvestmentRealizedGainService) GetRealizedGainsFromPositions(positions []*models.InvestmentPosition, startTime int64, endTime int64) []*models.InvestmentRealizedGain {
+	gains := make([]*models.InvestmentRealizedGain, 0)
+
+	for i := 0; i < len(positions); i++ {
+		for j := 0; j < len(positions[i].Matches); j++ {
+			match := positions[i].Matches[j]
+
+			if match.SoldTime < startTime || match.SoldTime >= endTime {
+				continue
+			}
+
+			gains = append(gains, &models.InvestmentRealizedGain{
+				InvestmentLotMatch: match,
+				HoldingPeriod:      s.GetHoldingPeriod(match.AcquiredTime, match.SoldTime),
+			})
+		}
+	}
+
+	sort.SliceStable(gains, func(i, j int) bool {
+		if gains[i].SoldTime != gains[j].SoldTime {
+			return gains[i].SoldTime < gains[j].SoldTime
+		}
+
+		return gains[i].AcquiredTime < gains[j].AcquiredTime
+	})
+
+	return gains
+}
+
+// GetHoldingPeriod returns long-term when the asset is held for more than one year, otherwise returns short-term
+func (s *InvestmentRealizedGainService) GetHoldingPeriod(acquiredTime int64, soldTime int64) models.InvestmentHoldingPeriod {
+	oneYearAfterAcquired := time.Unix(acquiredTime, 0).UTC().AddDate(1, 0, 0).Unix()
+
+	if soldTime > oneYearAfterAcquired {
+		return models.INVESTMENT_HOLDING_PERIOD_LONG_TERM
+	}
+
+	return models.INVESTMENT_HOLDING_PERIOD_SHORT_TERM
+}
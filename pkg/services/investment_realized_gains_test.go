@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestGetHoldingPeriod(t *testing.T) {
+	acquiredTime := int64(1704067200) // 2024-01-01 00:00:00 UTC
+
+	assert.Equal(t, models.INVESTMENT_HOLDING_PERIOD_SHORT_TERM, InvestmentRealizedGains.GetHoldingPeriod(acquiredTime, acquiredTime+86400))
+	assert.Equal(t, models.INVESTMENT_HOLDING_PERIOD_SHORT_TERM, InvestmentRealizedGains.GetHoldingPeriod(acquiredTime, 1735689600)) // 2025-01-01 00:00:00 UTC
+	assert.Equal(t, models.INVESTMENT_HOLDING_PERIOD_LONG_TERM, InvestmentRealizedGains.GetHoldingPeriod(acquiredTime, 1735689601))
+}
+
+func TestGetRealizedGainsFromPositions(t *testing.T) {
+	trades := []*models.InvestmentTrade{
+		{TradeId: 1, AccountId: 100, Symbol: "BTC", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1704067200, Quantity: 100000000, Amount: 4000000},
+		{TradeId: 2, AccountId: 100, Symbol: "BTC", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1735689600, Quantity: 100000000, Amount: 9000000},
+		{TradeId: 3, AccountId: 100, Symbol: "BTC", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_SELL, TradeTime: 1740787200, Quantity: 150000000, Amount: 15000000},
+		{TradeId: 4, AccountId: 100, Symbol: "BTC", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_SELL, TradeTime: 1767225600, Quantity: 50000000, Amount: 5000000},
+	}
+
+	position, err := InvestmentTrades.CalculatePosition(trades, models.INVESTMENT_LOT_MATCHING_METHOD_FIFO)
+	assert.Nil(t, err)
+
+	gains := InvestmentRealizedGains.GetRealizedGainsFromPositions([]*models.InvestmentPosition{position}, 1735689600, 1767225600)
+
+	assert.Equal(t, 2, len(gains))
+	assert.Equal(t, int64(1), gains[0].BuyTradeId)
+	assert.Equal(t, models.INVESTMENT_HOLDING_PERIOD_LONG_TERM, gains[0].HoldingPeriod)
+	assert.Equal(t, int64(10000000-4000000), gains[0].Proceeds-gains[0].CostBasis)
+	assert.Equal(t, int64(2), gains[1].BuyTradeId)
+	assert.Equal(t, models.INVESTMENT_HOLDING_PERIOD_SHORT_TERM, gains[1].HoldingPeriod)
+	assert.Equal(t, int64(5000000-4500000), gains[1].Proceeds-gains[1].CostBasis)
+}
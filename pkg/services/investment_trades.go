@@ -0,0 +1,548 @@
+package services
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// InvestmentTradeService represents investment trade service
+type InvestmentTradeService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize an investment trade service singleton instance
+var (
+	InvestmentTrades = &InvestmentTradeService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllTradesByAccountIds returns all investment trade models of given accounts ordered by trade time
+func (s *InvestmentTradeService) GetAllTradesByAccountIds(c core.Context, uid int64, accountIds []int64) ([]*models.InvestmentTrade, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if len(accountIds) < 1 {
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	var trades []*models.InvestmentTrade
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("account_id", accountIds).OrderBy("trade_time asc, trade_id asc").Find(&trades)
+
+	return trades, err
+}
+
+// GetAllTradesByUid returns all investment trade models of user ordered by trade time
+func (s *InvestmentTradeService) GetAllTradesByUid(c core.Context, uid int64) ([]*models.InvestmentTrade, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var trades []*models.InvestmentTrade
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("trade_time asc, trade_id asc").Find(&trades)
+
+	return trades, err
+}
+
+// GetTradeByTradeId returns an investment trade model according to trade id
+func (s *InvestmentTradeService) GetTradeByTradeId(c core.Context, uid int64, tradeId int64) (*models.InvestmentTrade, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if tradeId <= 0 {
+		return nil, errs.ErrInvestmentTradeIdInvalid
+	}
+
+	trade := &models.InvestmentTrade{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(tradeId).Where("uid=? AND deleted=?", uid, false).Get(trade)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrInvestmentTradeNotFound
+	}
+
+	return trade, nil
+}
+
+// CreateTrade saves a new investment trade model to database
+func (s *InvestmentTradeService) CreateTrade(c core.Context, trade *models.InvestmentTrade) error {
+	if trade.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.isTradeValid(trade)
+
+	if err != nil {
+		return err
+	}
+
+	trade.TradeId = s.GenerateUuid(uuid.UUID_TYPE_INVESTMENT_TRADE)
+
+	if trade.TradeId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	trade.Deleted = false
+	trade.CreatedUnixTime = time.Now().Unix()
+	trade.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(trade.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		account := &models.Account{}
+		has, err := sess.ID(trade.AccountId).Where("uid=? AND deleted=?", trade.Uid, false).Get(account)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		}
+
+		if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			return errs.ErrCannotAddTransactionToParentAccount
+		}
+
+		if account.Extend == nil || (account.Extend.AssetType != models.ACCOUNT_ASSET_TYPE_STOCK && account.Extend.AssetType != models.ACCOUNT_ASSET_TYPE_CRYPTO) {
+			return errs.ErrInvestmentAccountTypeInvalid
+		}
+
+		if trade.TransactionId > 0 {
+			transaction := &models.Transaction{}
+			has, err := sess.ID(trade.TransactionId).Where("uid=? AND deleted=?", trade.Uid, false).Get(transaction)
+
+			if err != nil {
+				return err
+			} else if !has {
+				return errs.ErrTransactionNotFound
+			}
+
+			err = s.isLinkedTransactionValid(trade, transaction)
+
+			if err != nil {
+				return err
+			}
+
+			exists, err := sess.Where("uid=? AND deleted=? AND transaction_id=?", trade.Uid, false, trade.TransactionId).Exist(&models.InvestmentTrade{})
+
+			if err != nil {
+				return err
+			} else if exists {
+				return errs.ErrInvestmentTradeTransactionAlreadyLinked
+			}
+		}
+
+		trade.Symbol = account.Currency
+
+		var trades []*models.InvestmentTrade
+		err = sess.Where("uid=? AND deleted=? AND account_id=?", trade.Uid, false, trade.AccountId).Find(&trades)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(trades); i++ {
+			if trades[i].Type == models.INVESTMENT_TRADE_TYPE_SPLIT || trades[i].Currency == "" {
+				continue
+			}
+
+			if trade.Type == models.INVESTMENT_TRADE_TYPE_SPLIT {
+				trade.Currency = trades[i].Currency
+			} else if trades[i].Currency != trade.Currency {
+				return errs.ErrInvestmentTradeCurrencyMismatch
+			}
+
+			break
+		}
+
+		err = s.isPositionValid(append(trades, trade))
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(trade)
+		return err
+	})
+}
+
+// DeleteTrade deletes an existed investment trade from database
+func (s *InvestmentTradeService) DeleteTrade(c core.Context, uid int64, tradeId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.InvestmentTrade{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		trade := &models.InvestmentTrade{}
+		has, err := sess.ID(tradeId).Where("uid=? AND deleted=?", uid, false).Get(trade)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrInvestmentTradeNotFound
+		}
+
+		var remainTrades []*models.InvestmentTrade
+		err = sess.Where("uid=? AND deleted=? AND account_id=? AND trade_id<>?", uid, false, trade.AccountId, tradeId).Find(&remainTrades)
+
+		if err != nil {
+			return err
+		}
+
+		err = s.isPositionValid(remainTrades)
+
+		if err != nil {
+			return err
+		}
+
+		deletedRows, err := sess.ID(tradeId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrInvestmentTradeNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllTrades deletes all existed investment trades from database
+func (s *InvestmentTradeService) DeleteAllTrades(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.InvestmentTrade{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// CalculatePosition replays the trades of one investment account and returns the open lots and the realized lot matches
+func (s *InvestmentTradeService) CalculatePosition(trades []*models.InvestmentTrade, method models.InvestmentLotMatchingMethod) (*models.InvestmentPosition, error) {
+	sortedTrades := make([]*models.InvestmentTrade, len(trades))
+	copy(sortedTrades, trades)
+
+	sort.SliceStable(sortedTrades, func(i, j int) bool {
+		if sortedTrades[i].TradeTime != sortedTrades[j].TradeTime {
+			return sortedTrades[i].TradeTime < sortedTrades[j].TradeTime
+		}
+
+		if sortedTrades[i].Type != sortedTrades[j].Type {
+			return sortedTrades[i].Type < sortedTrades[j].Type
+		}
+
+		return sortedTrades[i].TradeId < sortedTrades[j].TradeId
+	})
+
+	position := &models.InvestmentPosition{
+		OpenLots: make([]*models.InvestmentLot, 0),
+		Matches:  make([]*models.InvestmentLotMatch, 0),
+	}
+
+	for i := 0; i < len(sortedTrades); i++ {
+		trade := sortedTrades[i]
+
+		if position.AccountId == 0 {
+			position.AccountId = trade.AccountId
+			position.Symbol = trade.Symbol
+		}
+
+		if position.Currency == "" && trade.Type != models.INVESTMENT_TRADE_TYPE_SPLIT {
+			position.Currency = trade.Currency
+		}
+
+		switch trade.Type {
+		case models.INVESTMENT_TRADE_TYPE_BUY:
+			position.OpenLots = append(position.OpenLots, &models.InvestmentLot{
+				TradeId:      trade.TradeId,
+				AccountId:    trade.AccountId,
+				Symbol:       trade.Symbol,
+				Currency:     trade.Currency,
+				AcquiredTime: trade.TradeTime,
+				Quantity:     trade.Quantity,
+				Cost:         trade.Amount + trade.Fee,
+			})
+		case models.INVESTMENT_TRADE_TYPE_SELL:
+			matches, err := s.matchSell(position, trade, method)
+
+			if err != nil {
+				return nil, err
+			}
+
+			position.Matches = append(position.Matches, matches...)
+		case models.INVESTMENT_TRADE_TYPE_DIVIDEND:
+			position.TotalDividends += trade.Amount - trade.Fee
+		case models.INVESTMENT_TRADE_TYPE_SPLIT:
+			if trade.SplitNumerator <= 0 || trade.SplitDenominator <= 0 {
+				return nil, errs.ErrInvestmentTradeSplitRatioInvalid
+			}
+
+			for j := 0; j < len(position.OpenLots); j++ {
+				lot := position.OpenLots[j]
+
+				if lot.Quantity <= 0 {
+					continue
+				}
+
+				lot.Quantity = mulDiv(lot.Quantity, int64(trade.SplitNumerator), int64(trade.SplitDenominator))
+
+				// the cost of the lot would be lost if the lot is rounded down to zero quantity
+				if lot.Quantity <= 0 {
+					return nil, errs.ErrInvestmentSplitLotQuantityTooSmall
+				}
+			}
+		default:
+			return nil, errs.ErrInvestmentTradeTypeInvalid
+		}
+	}
+
+	openLots := make([]*models.InvestmentLot, 0, len(position.OpenLots))
+
+	for i := 0; i < len(position.OpenLots); i++ {
+		lot := position.OpenLots[i]
+
+		if lot.Quantity <= 0 {
+			continue
+		}
+
+		position.Quantity += lot.Quantity
+		position.TotalCost += lot.Cost
+		openLots = append(openLots, lot)
+	}
+
+	position.OpenLots = openLots
+
+	for i := 0; i < len(position.Matches); i++ {
+		position.RealizedProfitAndLoss += position.Matches[i].Proceeds - position.Matches[i].CostBasis
+	}
+
+	return position, nil
+}
+
+// isPositionValid checks whether the trades can be replayed by every lot matching method,
+// because the method is chosen by user when querying holdings and realized gains instead of being stored in account
+func (s *InvestmentTradeService) isPositionValid(trades []*models.InvestmentTrade) error {
+	for i := 0; i < len(models.AllInvestmentLotMatchingMethods); i++ {
+		_, err := s.CalculatePosition(trades, models.AllInvestmentLotMatchingMethods[i])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *InvestmentTradeService) matchSell(position *models.InvestmentPosition, trade *models.InvestmentTrade, method models.InvestmentLotMatchingMethod) ([]*models.InvestmentLotMatch, error) {
+	totalQuantity := int64(0)
+	totalCost := int64(0)
+
+	for i := 0; i < len(position.OpenLots); i++ {
+		totalQuantity += position.OpenLots[i].Quantity
+		totalCost += position.OpenLots[i].Cost
+	}
+
+	if trade.Quantity <= 0 {
+		return nil, errs.ErrInvestmentTradeQuantityInvalid
+	}
+
+	if trade.Quantity > totalQuantity {
+		return nil, errs.ErrInvestmentSellQuantityExceedsHolding
+	}
+
+	if method == models.INVESTMENT_LOT_MATCHING_METHOD_AVERAGE_COST {
+		lastOpenLotIndex := -1
+
+		for i := 0; i < len(position.OpenLots); i++ {
+			if position.OpenLots[i].Quantity > 0 {
+				lastOpenLotIndex = i
+			}
+		}
+
+		// the total cost is redistributed to the lots which still have quantity, and the rounding remainder is carried over to the last one
+		remainCost := totalCost
+
+		for i := 0; i < len(position.OpenLots); i++ {
+			lot := position.OpenLots[i]
+
+			if lot.Quantity <= 0 {
+				lot.Cost = 0
+			} else if i == lastOpenLotIndex {
+				lot.Cost = remainCost
+			} else {
+				lot.Cost = mulDiv(totalCost, lot.Quantity, totalQuantity)
+				remainCost -= lot.Cost
+			}
+		}
+	}
+
+	totalProceeds := trade.Amount - trade.Fee
+	remainQuantity := trade.Quantity
+	remainProceeds := totalProceeds
+	matches := make([]*models.InvestmentLotMatch, 0)
+
+	for remainQuantity > 0 {
+		var lot *models.InvestmentLot
+
+		if method == models.INVESTMENT_LOT_MATCHING_METHOD_LIFO {
+			for i := len(position.OpenLots) - 1; i >= 0; i-- {
+				if position.OpenLots[i].Quantity > 0 {
+					lot = position.OpenLots[i]
+					break
+				}
+			}
+		} else {
+			for i := 0; i < len(position.OpenLots); i++ {
+				if position.OpenLots[i].Quantity > 0 {
+					lot = position.OpenLots[i]
+					break
+				}
+			}
+		}
+
+		if lot == nil {
+			return nil, errs.ErrInvestmentSellQuantityExceedsHolding
+		}
+
+		quantity := lot.Quantity
+
+		if quantity > remainQuantity {
+			quantity = remainQuantity
+		}
+
+		costBasis := lot.Cost
+
+		if quantity < lot.Quantity {
+			costBasis = mulDiv(lot.Cost, quantity, lot.Quantity)
+		}
+
+		proceeds := remainProceeds
+
+		if quantity < remainQuantity {
+			proceeds = mulDiv(totalProceeds, quantity, trade.Quantity)
+		}
+
+		lot.Quantity -= quantity
+		lot.Cost -= costBasis
+		remainQuantity -= quantity
+		remainProceeds -= proceeds
+
+		matches = append(matches, &models.InvestmentLotMatch{
+			SellTradeId:  trade.TradeId,
+			BuyTradeId:   lot.TradeId,
+			AccountId:    trade.AccountId,
+			Symbol:       trade.Symbol,
+			Currency:     trade.Currency,
+			AcquiredTime: lot.AcquiredTime,
+			SoldTime:     trade.TradeTime,
+			Quantity:     quantity,
+			CostBasis:    costBasis,
+			Proceeds:     proceeds,
+		})
+	}
+
+	return matches, nil
+}
+
+func (s *InvestmentTradeService) isTradeValid(trade *models.InvestmentTrade) error {
+	switch trade.Type {
+	case models.INVESTMENT_TRADE_TYPE_BUY, models.INVESTMENT_TRADE_TYPE_SELL:
+		if trade.Quantity <= 0 {
+			return errs.ErrInvestmentTradeQuantityInvalid
+		}
+	case models.INVESTMENT_TRADE_TYPE_DIVIDEND:
+		trade.Quantity = 0
+	case models.INVESTMENT_TRADE_TYPE_SPLIT:
+		if trade.SplitNumerator <= 0 || trade.SplitDenominator <= 0 {
+			return errs.ErrInvestmentTradeSplitRatioInvalid
+		}
+
+		trade.Quantity = 0
+		trade.Amount = 0
+		trade.Fee = 0
+		trade.Currency = ""
+	default:
+		return errs.ErrInvestmentTradeTypeInvalid
+	}
+
+	if trade.Type != models.INVESTMENT_TRADE_TYPE_SPLIT {
+		if trade.Currency == "" {
+			return errs.ErrInvestmentTradeCurrencyInvalid
+		}
+
+		trade.SplitNumerator = 0
+		trade.SplitDenominator = 0
+	}
+
+	return nil
+}
+
+// isLinkedTransactionValid returns whether the linked transaction is in the trade account and changes the account balance by the trade quantity,
+// the buy trade must be linked to an income or transfer in transaction and the sell trade must be linked to an expense or transfer out transaction
+func (s *InvestmentTradeService) isLinkedTransactionValid(trade *models.InvestmentTrade, transaction *models.Transaction) error {
+	if transaction.AccountId != trade.AccountId {
+		return errs.ErrInvestmentTradeTransactionMismatch
+	}
+
+	switch trade.Type {
+	case models.INVESTMENT_TRADE_TYPE_BUY:
+		if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			return errs.ErrInvestmentTradeTransactionMismatch
+		}
+	case models.INVESTMENT_TRADE_TYPE_SELL:
+		if transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE && transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			return errs.ErrInvestmentTradeTransactionMismatch
+		}
+	default:
+		return errs.ErrInvestmentTradeTransactionMismatch
+	}
+
+	if transaction.Amount != trade.Quantity {
+		return errs.ErrInvestmentTradeTransactionMismatch
+	}
+
+	return nil
+}
+
+func mulDiv(value int64, multiplier int64, divisor int64) int64 {
+	result := new(big.Int).Mul(big.NewInt(value), big.NewInt(multiplier))
+	result.Quo(result, big.NewInt(divisor))
+
+	return result.Int64()
+}
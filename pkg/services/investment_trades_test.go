@@ -0,0 +1,223 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func getTestInvestmentTrades() []*models.InvestmentTrade {
+	return []*models.InvestmentTrade{
+		{TradeId: 1, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 1000, Amount: 100000, Fee: 100},
+		{TradeId: 2, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 2000, Quantity: 1000, Amount: 200000, Fee: 100},
+		{TradeId: 3, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_SELL, TradeTime: 3000, Quantity: 1500, Amount: 450000, Fee: 300},
+	}
+}
+
+func TestCalculatePosition_Fifo(t *testing.T) {
+	position, err := InvestmentTrades.CalculatePosition(getTestInvestmentTrades(), models.INVESTMENT_LOT_MATCHING_METHOD_FIFO)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(500), position.Quantity)
+	assert.Equal(t, int64(100050), position.TotalCost)
+	assert.Equal(t, 1, len(position.OpenLots))
+	assert.Equal(t, int64(2), position.OpenLots[0].TradeId)
+
+	assert.Equal(t, 2, len(position.Matches))
+	assert.Equal(t, int64(1), position.Matches[0].BuyTradeId)
+	assert.Equal(t, int64(1000), position.Matches[0].Quantity)
+	assert.Equal(t, int64(100100), position.Matches[0].CostBasis)
+	assert.Equal(t, int64(299800), position.Matches[0].Proceeds)
+	assert.Equal(t, int64(2), position.Matches[1].BuyTradeId)
+	assert.Equal(t, int64(500), position.Matches[1].Quantity)
+	assert.Equal(t, int64(100050), position.Matches[1].CostBasis)
+	assert.Equal(t, int64(149900), position.Matches[1].Proceeds)
+	assert.Equal(t, int64(449700-200150), position.RealizedProfitAndLoss)
+}
+
+func TestCalculatePosition_Lifo(t *testing.T) {
+	position, err := InvestmentTrades.CalculatePosition(getTestInvestmentTrades(), models.INVESTMENT_LOT_MATCHING_METHOD_LIFO)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(500), position.Quantity)
+	assert.Equal(t, int64(50050), position.TotalCost)
+	assert.Equal(t, 1, len(position.OpenLots))
+	assert.Equal(t, int64(1), position.OpenLots[0].TradeId)
+
+	assert.Equal(t, 2, len(position.Matches))
+	assert.Equal(t, int64(2), position.Matches[0].BuyTradeId)
+	assert.Equal(t, int64(200100), position.Matches[0].CostBasis)
+	assert.Equal(t, int64(1), position.Matches[1].BuyTradeId)
+	assert.Equal(t, int64(50050), position.Matches[1].CostBasis)
+}
+
+func TestCalculatePosition_AverageCost(t *testing.T) {
+	position, err := InvestmentTrades.CalculatePosition(getTestInvestmentTrades(), models.INVESTMENT_LOT_MATCHING_METHOD_AVERAGE_COST)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(500), position.Quantity)
+	assert.Equal(t, int64(75050), position.TotalCost)
+
+	totalCostBasis := int64(0)
+
+	for i := 0; i < len(position.Matches); i++ {
+		totalCostBasis += position.Matches[i].CostBasis
+	}
+
+	assert.Equal(t, int64(225150), totalCostBasis)
+}
+
+func TestCalculatePosition_SplitAndDividend(t *testing.T) {
+	trades := []*models.InvestmentTrade{
+		{TradeId: 1, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 1000, Amount: 100000},
+		{TradeId: 2, AccountId: 100, Symbol: "AAPL", Type: models.INVESTMENT_TRADE_TYPE_SPLIT, TradeTime: 2000, SplitNumerator: 4, SplitDenominator: 1},
+		{TradeId: 3, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_DIVIDEND, TradeTime: 3000, Amount: 1200, Fee: 200},
+	}
+
+	position, err := InvestmentTrades.CalculatePosition(trades, models.INVESTMENT_LOT_MATCHING_METHOD_FIFO)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "USD", position.Currency)
+	assert.Equal(t, int64(4000), position.Quantity)
+	assert.Equal(t, int64(100000), position.TotalCost)
+	assert.Equal(t, int64(1000), position.TotalDividends)
+}
+
+func TestCalculatePosition_SellExceedsHolding(t *testing.T) {
+	trades := []*models.InvestmentTrade{
+		{TradeId: 1, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 2000, Quantity: 1000, Amount: 100000},
+		{TradeId: 2, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_SELL, TradeTime: 1000, Quantity: 500, Amount: 60000},
+	}
+
+	_, err := InvestmentTrades.CalculatePosition(trades, models.INVESTMENT_LOT_MATCHING_METHOD_FIFO)
+
+	assert.Equal(t, errs.ErrInvestmentSellQuantityExceedsHolding, err)
+}
+
+func TestCalculatePosition_AverageCostCarriesOverRemainingCost(t *testing.T) {
+	trades := []*models.InvestmentTrade{
+		{TradeId: 1, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 1, Amount: 100},
+		{TradeId: 2, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 2000, Quantity: 1, Amount: 101},
+		{TradeId: 3, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 3000, Quantity: 1, Amount: 102},
+		{TradeId: 4, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_SELL, TradeTime: 4000, Quantity: 2, Amount: 300},
+		{TradeId: 5, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_SELL, TradeTime: 5000, Quantity: 1, Amount: 150},
+	}
+
+	position, err := InvestmentTrades.CalculatePosition(trades, models.INVESTMENT_LOT_MATCHING_METHOD_AVERAGE_COST)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), position.Quantity)
+	assert.Equal(t, int64(0), position.TotalCost)
+
+	totalCostBasis := int64(0)
+
+	for i := 0; i < len(position.Matches); i++ {
+		totalCostBasis += position.Matches[i].CostBasis
+	}
+
+	assert.Equal(t, int64(303), totalCostBasis)
+}
+
+func TestCalculatePosition_SplitReducesLotToZero(t *testing.T) {
+	trades := []*models.InvestmentTrade{
+		{TradeId: 1, AccountId: 100, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 5, Amount: 500},
+		{TradeId: 2, AccountId: 100, Symbol: "AAPL", Type: models.INVESTMENT_TRADE_TYPE_SPLIT, TradeTime: 2000, SplitNumerator: 1, SplitDenominator: 10},
+	}
+
+	_, err := InvestmentTrades.CalculatePosition(trades, models.INVESTMENT_LOT_MATCHING_METHOD_AVERAGE_COST)
+
+	assert.Equal(t, errs.ErrInvestmentSplitLotQuantityTooSmall, err)
+}
+
+func TestCreateTrade_LinkedTransactionMismatch(t *testing.T) {
+	initializeTestDataStore(t, new(models.Account), new(models.Transaction), new(models.InvestmentTrade))
+
+	c := core.NewNullContext()
+	sess := InvestmentTrades.UserDataDB(1).NewSession(c)
+
+	_, err := sess.Insert(&models.Account{AccountId: 1001, Uid: 1, Name: "Brokerage", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "AAPL", Extend: &models.AccountExtend{AssetType: models.ACCOUNT_ASSET_TYPE_STOCK}})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Account{AccountId: 1002, Uid: 1, Name: "Checking Account", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{TransactionId: 2001, Uid: 1, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1002, TransactionTime: 1000, Amount: 100000, RelatedId: 2002, RelatedAccountId: 1001, RelatedAccountAmount: 1000})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{TransactionId: 2002, Uid: 1, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 1001, TransactionTime: 1001, Amount: 1000, RelatedId: 2001, RelatedAccountId: 1002, RelatedAccountAmount: 100000})
+	assert.Nil(t, err)
+
+	err = InvestmentTrades.CreateTrade(c, &models.InvestmentTrade{Uid: 1, AccountId: 1001, Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 1000, Amount: 100000, Currency: "USD", TransactionId: 2001})
+	assert.Equal(t, errs.ErrInvestmentTradeTransactionMismatch, err)
+
+	err = InvestmentTrades.CreateTrade(c, &models.InvestmentTrade{Uid: 1, AccountId: 1001, Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 500, Amount: 50000, Currency: "USD", TransactionId: 2002})
+	assert.Equal(t, errs.ErrInvestmentTradeTransactionMismatch, err)
+
+	err = InvestmentTrades.CreateTrade(c, &models.InvestmentTrade{Uid: 1, AccountId: 1001, Type: models.INVESTMENT_TRADE_TYPE_SELL, TradeTime: 1000, Quantity: 1000, Amount: 100000, Currency: "USD", TransactionId: 2002})
+	assert.Equal(t, errs.ErrInvestmentTradeTransactionMismatch, err)
+
+	err = InvestmentTrades.CreateTrade(c, &models.InvestmentTrade{Uid: 1, AccountId: 1001, Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 1000, Amount: 100000, Currency: "USD", TransactionId: 2002})
+	assert.Nil(t, err)
+}
+
+func TestCreateTrade_LinkedTransactionAlreadyLinked(t *testing.T) {
+	initializeTestDataStore(t, new(models.Account), new(models.Transaction), new(models.InvestmentTrade))
+
+	c := core.NewNullContext()
+	sess := InvestmentTrades.UserDataDB(1).NewSession(c)
+
+	_, err := sess.Insert(&models.Account{AccountId: 1001, Uid: 1, Name: "Brokerage", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "AAPL", Extend: &models.AccountExtend{AssetType: models.ACCOUNT_ASSET_TYPE_STOCK}})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{TransactionId: 2001, Uid: 1, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 1001, TransactionTime: 1000, Amount: 1000, RelatedAccountAmount: 100000})
+	assert.Nil(t, err)
+
+	err = InvestmentTrades.CreateTrade(c, &models.InvestmentTrade{Uid: 1, AccountId: 1001, Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 1000, Amount: 100000, Currency: "USD", TransactionId: 2001})
+	assert.Nil(t, err)
+
+	err = InvestmentTrades.CreateTrade(c, &models.InvestmentTrade{Uid: 1, AccountId: 1001, Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 1000, Amount: 100000, Currency: "USD", TransactionId: 2001})
+	assert.Equal(t, errs.ErrInvestmentTradeTransactionAlreadyLinked, err)
+
+	var trades []*models.InvestmentTrade
+	err = sess.Where("uid=? AND deleted=?", 1, false).Find(&trades)
+	assert.Nil(t, err)
+	assert.Len(t, trades, 1)
+
+	err = InvestmentTrades.DeleteTrade(c, 1, trades[0].TradeId)
+	assert.Nil(t, err)
+
+	err = InvestmentTrades.CreateTrade(c, &models.InvestmentTrade{Uid: 1, AccountId: 1001, Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 1000, Amount: 100000, Currency: "USD", TransactionId: 2001})
+	assert.Nil(t, err)
+}
+
+func TestDeleteTrade_InvalidPositionByAnyLotMatchingMethod(t *testing.T) {
+	initializeTestDataStore(t, new(models.Account), new(models.Transaction), new(models.InvestmentTrade))
+
+	c := core.NewNullContext()
+	sess := InvestmentTrades.UserDataDB(1).NewSession(c)
+
+	trades := []*models.InvestmentTrade{
+		{TradeId: 1, Uid: 1, AccountId: 1001, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 5, Amount: 500},
+		{TradeId: 2, Uid: 1, AccountId: 1001, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_SELL, TradeTime: 1500, Quantity: 5, Amount: 600},
+		{TradeId: 3, Uid: 1, AccountId: 1001, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 2000, Quantity: 100, Amount: 10000},
+		{TradeId: 4, Uid: 1, AccountId: 1001, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_SELL, TradeTime: 3000, Quantity: 5, Amount: 600},
+		{TradeId: 5, Uid: 1, AccountId: 1001, Symbol: "AAPL", Type: models.INVESTMENT_TRADE_TYPE_SPLIT, TradeTime: 4000, SplitNumerator: 1, SplitDenominator: 10},
+	}
+
+	for i := 0; i < len(trades); i++ {
+		_, err := sess.Insert(trades[i])
+		assert.Nil(t, err)
+	}
+
+	// the remaining trades are valid by fifo, but the split rounds the first lot down to zero by lifo
+	err := InvestmentTrades.DeleteTrade(c, 1, 2)
+	assert.Equal(t, errs.ErrInvestmentSplitLotQuantityTooSmall, err)
+
+	trade, err := InvestmentTrades.GetTradeByTradeId(c, 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), trade.TradeId)
+}
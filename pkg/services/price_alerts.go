@@ -0,0 +1,492 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/locales"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mail"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/templates"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+type priceAlertLatestPrice struct {
+	symbol     string
+	price      string
+	currency   string
+	dataSource string
+}
+
+type triggeredPriceAlert struct {
+	alert       *models.PriceAlert
+	price       string
+	currency    string
+	basePrice   float64
+	priceNumber float64
+}
+
+// PriceAlertService represents price alert service
+type PriceAlertService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingMailer
+	ServiceUsingUuid
+	users                        *UserService
+	stocks                       *StockService
+	cryptocurrencies             *CryptocurrencyService
+	stockPriceHistories          *StockPriceHistoryService
+	cryptocurrencyPriceHistories *CryptocurrencyPriceHistoryService
+}
+
+// Initialize a price alert service singleton instance
+var (
+	PriceAlerts = &PriceAlertService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingMailer: ServiceUsingMailer{
+			container: mail.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		users:                        Users,
+		stocks:                       Stocks,
+		cryptocurrencies:             Cryptocurrencies,
+		stockPriceHistories:          StockPriceHistories,
+		cryptocurrencyPriceHistories: CryptocurrencyPriceHistories,
+	}
+)
+
+// GetAllAlertsByUid returns all price alert models of user
+func (s *PriceAlertService) GetAllAlertsByUid(c core.Context, uid int64) ([]*models.PriceAlert, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var alerts []*models.PriceAlert
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("asset_type asc, symbol asc, alert_id asc").Find(&alerts)
+
+	return alerts, err
+}
+
+// CreateAlert saves a new price alert model to database
+func (s *PriceAlertService) CreateAlert(c core.Context, alert *models.PriceAlert) error {
+	if alert.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.isAlertValid(alert)
+
+	if err != nil {
+		return err
+	}
+
+	if alert.AssetType == models.ACCOUNT_ASSET_TYPE_STOCK {
+		_, err = s.stocks.GetStockBySymbol(c, alert.Uid, alert.Symbol)
+	} else {
+		_, err = s.cryptocurrencies.GetCryptocurrencyBySymbol(c, alert.Uid, alert.Symbol)
+	}
+
+	if err == errs.ErrStockNotFound || err == errs.ErrCryptocurrencyNotFound {
+		return errs.ErrPriceAlertSymbolNotWatched
+	} else if err != nil {
+		return err
+	}
+
+	alert.AlertId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+
+	if alert.AlertId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	alert.Deleted = false
+	alert.Triggered = false
+	alert.CreatedUnixTime = time.Now().Unix()
+	alert.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(alert.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(alert)
+		return err
+	})
+}
+
+// DeleteAlert deletes an existed price alert from database
+func (s *PriceAlertService) DeleteAlert(c core.Context, uid int64, alertId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if alertId <= 0 {
+		return errs.ErrPriceAlertIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.PriceAlert{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(alertId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrPriceAlertNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteAllAlerts deletes all existed price alerts from database
+func (s *PriceAlertService) DeleteAllAlerts(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.PriceAlert{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+// EvaluateStockPriceAlerts evaluates the price alerts of stocks according to the latest stock prices and notifies the users by email,
+// the data source of each price would be used if present, otherwise the given data source would be used
+func (s *PriceAlertService) EvaluateStockPriceAlerts(c core.Context, dataSource string, priceResponse *models.LatestStockPriceResponse) error {
+	if priceResponse == nil {
+		return nil
+	}
+
+	prices := make(map[string]*priceAlertLatestPrice, len(priceResponse.Prices))
+
+	for i := 0; i < len(priceResponse.Prices); i++ {
+		price := priceResponse.Prices[i]
+		currency := price.Currency
+
+		if currency == "" {
+			currency = priceResponse.BaseCurrency
+		}
+
+		priceDataSource := price.DataSource
+
+		if priceDataSource == "" {
+			priceDataSource = dataSource
+		}
+
+		prices[getPriceAlertLatestPriceKey(models.ACCOUNT_ASSET_TYPE_STOCK, price.Symbol, currency)] = &priceAlertLatestPrice{
+			symbol:     strings.ToUpper(price.Symbol),
+			price:      price.Price,
+			currency:   strings.ToUpper(currency),
+			dataSource: priceDataSource,
+		}
+	}
+
+	return s.evaluateAlerts(c, models.ACCOUNT_ASSET_TYPE_STOCK, prices)
+}
+
+// EvaluateCryptocurrencyPriceAlerts evaluates the price alerts of cryptocurrencies according to the latest cryptocurrency prices of the given data source and notifies the users by email
+func (s *PriceAlertService) EvaluateCryptocurrencyPriceAlerts(c core.Context, dataSource string, priceResponse *models.LatestCryptocurrencyPriceResponse) error {
+	if priceResponse == nil {
+		return nil
+	}
+
+	prices := make(map[string]*priceAlertLatestPrice, len(priceResponse.Prices))
+
+	for i := 0; i < len(priceResponse.Prices); i++ {
+		prices[getPriceAlertLatestPriceKey(models.ACCOUNT_ASSET_TYPE_CRYPTO, priceResponse.Prices[i].Symbol, priceResponse.BaseCurrency)] = &priceAlertLatestPrice{
+			symbol:     strings.ToUpper(priceResponse.Prices[i].Symbol),
+			price:      priceResponse.Prices[i].Price,
+			currency:   strings.ToUpper(priceResponse.BaseCurrency),
+			dataSource: dataSource,
+		}
+	}
+
+	return s.evaluateAlerts(c, models.ACCOUNT_ASSET_TYPE_CRYPTO, prices)
+}
+
+func (s *PriceAlertService) evaluateAlerts(c core.Context, assetType models.AccountAssetType, prices map[string]*priceAlertLatestPrice) error {
+	if len(prices) < 1 {
+		return nil
+	}
+
+	symbols := make([]string, 0, len(prices))
+	addedSymbols := make(map[string]bool, len(prices))
+
+	for _, latestPrice := range prices {
+		if addedSymbols[latestPrice.symbol] {
+			continue
+		}
+
+		addedSymbols[latestPrice.symbol] = true
+		symbols = append(symbols, latestPrice.symbol)
+	}
+
+	now := time.Now().Unix()
+	basePrices := make(map[string]float64)
+	userTriggeredAlerts := make(map[int64][]*triggeredPriceAlert)
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var alerts []*models.PriceAlert
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND asset_type=?", false, assetType).In("symbol", symbols).Find(&alerts)
+
+		if err != nil {
+			return err
+		}
+
+		for j := 0; j < len(alerts); j++ {
+			alert := alerts[j]
+			latestPrice, exists := prices[getPriceAlertLatestPriceKey(alert.AssetType, alert.Symbol, alert.Currency)]
+
+			// the threshold of the alert is in its own currency, so the price in other currencies cannot be compared with it
+			if !exists {
+				continue
+			}
+
+			price, err := utils.StringToFloat64(latestPrice.price)
+
+			if err != nil {
+				continue
+			}
+
+			basePrice := float64(0)
+
+			if alert.Type == models.PRICE_ALERT_TYPE_PERCENTAGE_CHANGE {
+				basePriceKey := fmt.Sprintf("%s_%s_%s_%d", alert.Symbol, latestPrice.currency, latestPrice.dataSource, alert.PeriodDays)
+				cachedBasePrice, exists := basePrices[basePriceKey]
+
+				if !exists {
+					cachedBasePrice = s.getBasePrice(c, assetType, alert.Symbol, latestPrice.currency, latestPrice.dataSource, alert.PeriodDays)
+					basePrices[basePriceKey] = cachedBasePrice
+				}
+
+				basePrice = cachedBasePrice
+			}
+
+			conditionMet := alert.IsConditionMet(price, basePrice)
+
+			if conditionMet == alert.Triggered {
+				continue
+			}
+
+			// the triggered alerts would be saved after the notification email has been sent, so that they can be retried next time when sending failed,
+			// and the alerts would keep untriggered until the SMTP server is enabled
+			if conditionMet {
+				if !s.CurrentConfig().EnableSMTP {
+					continue
+				}
+
+				userTriggeredAlerts[alert.Uid] = append(userTriggeredAlerts[alert.Uid], &triggeredPriceAlert{
+					alert:       alert,
+					price:       latestPrice.price,
+					currency:    latestPrice.currency,
+					basePrice:   basePrice,
+					priceNumber: price,
+				})
+				continue
+			}
+
+			alert.Triggered = false
+			alert.UpdatedUnixTime = now
+
+			_, err = s.UserDataDBByIndex(i).NewSession(c).ID(alert.AlertId).Cols("triggered", "updated_unix_time").Where("uid=? AND deleted=? AND triggered=?", alert.Uid, false, true).Update(alert)
+
+			if err != nil {
+				log.Errorf(c, "[price_alerts.evaluateAlerts] failed to reset price alert \"id:%d\" of user \"uid:%d\", because %s", alert.AlertId, alert.Uid, err.Error())
+			}
+		}
+	}
+
+	for uid, triggeredAlerts := range userTriggeredAlerts {
+		user, err := s.users.GetUserById(c, uid)
+
+		if err != nil {
+			log.Warnf(c, "[price_alerts.evaluateAlerts] failed to get user \"uid:%d\", because %s", uid, err.Error())
+			continue
+		}
+
+		// the alerts would not be saved as triggered if the notification email cannot be sent to the user
+		if user.Disabled || user.Email == "" {
+			continue
+		}
+
+		err = s.sendPriceAlertEmail(user, triggeredAlerts)
+
+		if err != nil {
+			log.Errorf(c, "[price_alerts.evaluateAlerts] failed to send price alert email to user \"uid:%d\", because %s", uid, err.Error())
+			continue
+		}
+
+		log.Infof(c, "[price_alerts.evaluateAlerts] %d price alerts have been sent to user \"uid:%d\"", len(triggeredAlerts), uid)
+
+		for i := 0; i < len(triggeredAlerts); i++ {
+			alert := triggeredAlerts[i].alert
+			alert.Triggered = true
+			alert.LastTriggeredPrice = triggeredAlerts[i].price
+			alert.LastTriggeredUnixTime = now
+			alert.UpdatedUnixTime = now
+
+			_, err = s.UserDataDB(uid).NewSession(c).ID(alert.AlertId).Cols("triggered", "last_triggered_price", "last_triggered_unix_time", "updated_unix_time").Where("uid=? AND deleted=? AND triggered=?", uid, false, false).Update(alert)
+
+			if err != nil {
+				log.Errorf(c, "[price_alerts.evaluateAlerts] failed to update price alert \"id:%d\" of user \"uid:%d\", because %s", alert.AlertId, uid, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// getBasePrice returns the last saved price of the symbol on or before the start of the period from the same data source as the latest price
+func (s *PriceAlertService) getBasePrice(c core.Context, assetType models.AccountAssetType, symbol string, currency string, dataSource string, periodDays int32) float64 {
+	baseDate := utils.FormatUnixTimeToNumericYearMonthDay(time.Now().Unix()-int64(periodDays)*24*60*60, time.UTC)
+	price := ""
+
+	if assetType == models.ACCOUNT_ASSET_TYPE_STOCK {
+		histories, err := s.stockPriceHistories.GetPriceHistories(c, symbol, currency, dataSource, 0, baseDate)
+
+		if err != nil {
+			log.Warnf(c, "[price_alerts.getBasePrice] failed to get price histories of stock \"%s\", because %s", symbol, err.Error())
+		} else if len(histories) > 0 {
+			price = histories[len(histories)-1].Price
+		}
+	} else {
+		histories, err := s.cryptocurrencyPriceHistories.GetPriceHistories(c, symbol, currency, dataSource, 0, baseDate)
+
+		if err != nil {
+			log.Warnf(c, "[price_alerts.getBasePrice] failed to get price histories of cryptocurrency \"%s\", because %s", symbol, err.Error())
+		} else if len(histories) > 0 {
+			price = histories[len(histories)-1].Price
+		}
+	}
+
+	basePrice, err := utils.StringToFloat64(price)
+
+	if err != nil {
+		return 0
+	}
+
+	return basePrice
+}
+
+func (s *PriceAlertService) sendPriceAlertEmail(user *models.User, triggeredAlerts []*triggeredPriceAlert) error {
+	localeTextItems := locales.GetLocaleTextItems(user.Language)
+	priceAlertTextItems := localeTextItems.PriceAlertMailTextItems
+
+	tmpl, err := templates.GetTemplate(templates.TEMPLATE_PRICE_ALERT)
+
+	if err != nil {
+		return err
+	}
+
+	alertDescriptions := make([]string, len(triggeredAlerts))
+
+	for i := 0; i < len(triggeredAlerts); i++ {
+		triggeredAlert := triggeredAlerts[i]
+		alert := triggeredAlert.alert
+
+		switch alert.Type {
+		case models.PRICE_ALERT_TYPE_ABOVE:
+			alertDescriptions[i] = fmt.Sprintf(priceAlertTextItems.AboveThresholdFormat, alert.Symbol, triggeredAlert.price, triggeredAlert.currency, alert.Threshold)
+		case models.PRICE_ALERT_TYPE_BELOW:
+			alertDescriptions[i] = fmt.Sprintf(priceAlertTextItems.BelowThresholdFormat, alert.Symbol, triggeredAlert.price, triggeredAlert.currency, alert.Threshold)
+		case models.PRICE_ALERT_TYPE_PERCENTAGE_CHANGE:
+			change := models.GetPriceChangePercentage(triggeredAlert.priceNumber, triggeredAlert.basePrice)
+			changeText := strconv.FormatFloat(change, 'f', 2, 64)
+
+			if change > 0 {
+				changeText = "+" + changeText
+			}
+
+			alertDescriptions[i] = fmt.Sprintf(priceAlertTextItems.PercentageChangeFormat, alert.Symbol, triggeredAlert.price, triggeredAlert.currency, changeText, alert.PeriodDays)
+		}
+	}
+
+	templateParams := map[string]any{
+		"AppName": localeTextItems.GlobalTextItems.AppName,
+		"PriceAlertMail": map[string]any{
+			"Title":                priceAlertTextItems.Title,
+			"Salutation":           fmt.Sprintf(priceAlertTextItems.SalutationFormat, user.Nickname),
+			"DescriptionAboveList": priceAlertTextItems.DescriptionAboveList,
+			"Alerts":               alertDescriptions,
+			"DescriptionBelowList": fmt.Sprintf(priceAlertTextItems.DescriptionBelowListFormat, localeTextItems.GlobalTextItems.AppName),
+		},
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = tmpl.Execute(&bodyBuffer, templateParams)
+
+	if err != nil {
+		return err
+	}
+
+	message := &mail.MailMessage{
+		To:      user.Email,
+		Subject: priceAlertTextItems.Title,
+		Body:    bodyBuffer.String(),
+	}
+
+	return s.SendMail(message)
+}
+
+// getPriceAlertLatestPriceKey returns the key of the latest price by the asset type, the symbol and the currency of the price
+func getPriceAlertLatestPriceKey(assetType models.AccountAssetType, symbol string, currency string) string {
+	return fmt.Sprintf("%d_%s_%s", assetType, strings.ToUpper(symbol), strings.ToUpper(currency))
+}
+
+func (s *PriceAlertService) isAlertValid(alert *models.PriceAlert) error {
+	alert.Symbol = strings.ToUpper(strings.TrimSpace(alert.Symbol))
+	alert.Currency = strings.ToUpper(strings.TrimSpace(alert.Currency))
+	alert.Threshold = strings.TrimSpace(alert.Threshold)
+
+	if alert.AssetType != models.ACCOUNT_ASSET_TYPE_STOCK && alert.AssetType != models.ACCOUNT_ASSET_TYPE_CRYPTO {
+		return errs.ErrPriceAlertAssetTypeInvalid
+	}
+
+	if alert.Currency == "" {
+		return errs.ErrPriceAlertCurrencyInvalid
+	}
+
+	threshold, err := utils.StringToFloat64(alert.Threshold)
+
+	if err != nil || threshold <= 0 {
+		return errs.ErrPriceAlertThresholdInvalid
+	}
+
+	switch alert.Type {
+	case models.PRICE_ALERT_TYPE_ABOVE, models.PRICE_ALERT_TYPE_BELOW:
+		alert.PeriodDays = 0
+	case models.PRICE_ALERT_TYPE_PERCENTAGE_CHANGE:
+		if alert.PeriodDays <= 0 {
+			return errs.ErrPriceAlertPeriodInvalid
+		}
+	default:
+		return errs.ErrPriceAlertTypeInvalid
+	}
+
+	return nil
+}
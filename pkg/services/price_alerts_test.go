@@ -0,0 +1,147 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func TestPriceAlertIsAlertValid_Threshold(t *testing.T) {
+	alert := &models.PriceAlert{AssetType: models.ACCOUNT_ASSET_TYPE_STOCK, Symbol: " aapl ", Currency: " usd ", Type: models.PRICE_ALERT_TYPE_ABOVE, Threshold: " 200 ", PeriodDays: 30}
+	assert.Nil(t, PriceAlerts.isAlertValid(alert))
+	assert.Equal(t, "AAPL", alert.Symbol)
+	assert.Equal(t, "USD", alert.Currency)
+	assert.Equal(t, "200", alert.Threshold)
+	assert.Equal(t, int32(0), alert.PeriodDays)
+
+	alert = &models.PriceAlert{AssetType: models.ACCOUNT_ASSET_TYPE_CRYPTO, Symbol: "BTC", Currency: "USD", Type: models.PRICE_ALERT_TYPE_BELOW, Threshold: "0"}
+	assert.Equal(t, errs.ErrPriceAlertThresholdInvalid, PriceAlerts.isAlertValid(alert))
+
+	alert = &models.PriceAlert{AssetType: models.ACCOUNT_ASSET_TYPE_CRYPTO, Symbol: "BTC", Currency: "USD", Type: models.PRICE_ALERT_TYPE_BELOW, Threshold: "abc"}
+	assert.Equal(t, errs.ErrPriceAlertThresholdInvalid, PriceAlerts.isAlertValid(alert))
+}
+
+func TestPriceAlertIsAlertValid_PercentageChange(t *testing.T) {
+	alert := &models.PriceAlert{AssetType: models.ACCOUNT_ASSET_TYPE_CRYPTO, Symbol: "ETH", Currency: "USD", Type: models.PRICE_ALERT_TYPE_PERCENTAGE_CHANGE, Threshold: "5", PeriodDays: 7}
+	assert.Nil(t, PriceAlerts.isAlertValid(alert))
+	assert.Equal(t, int32(7), alert.PeriodDays)
+
+	alert = &models.PriceAlert{AssetType: models.ACCOUNT_ASSET_TYPE_CRYPTO, Symbol: "ETH", Currency: "USD", Type: models.PRICE_ALERT_TYPE_PERCENTAGE_CHANGE, Threshold: "5"}
+	assert.Equal(t, errs.ErrPriceAlertPeriodInvalid, PriceAlerts.isAlertValid(alert))
+}
+
+func TestPriceAlertIsAlertValid_InvalidType(t *testing.T) {
+	alert := &models.PriceAlert{AssetType: models.ACCOUNT_ASSET_TYPE_STOCK, Symbol: "AAPL", Currency: "USD", Type: 0, Threshold: "1"}
+	assert.Equal(t, errs.ErrPriceAlertTypeInvalid, PriceAlerts.isAlertValid(alert))
+
+	alert = &models.PriceAlert{AssetType: models.ACCOUNT_ASSET_TYPE_FIAT, Symbol: "AAPL", Currency: "USD", Type: models.PRICE_ALERT_TYPE_ABOVE, Threshold: "1"}
+	assert.Equal(t, errs.ErrPriceAlertAssetTypeInvalid, PriceAlerts.isAlertValid(alert))
+}
+
+func TestPriceAlertIsAlertValid_InvalidCurrency(t *testing.T) {
+	alert := &models.PriceAlert{AssetType: models.ACCOUNT_ASSET_TYPE_STOCK, Symbol: "AAPL", Currency: " ", Type: models.PRICE_ALERT_TYPE_ABOVE, Threshold: "1"}
+	assert.Equal(t, errs.ErrPriceAlertCurrencyInvalid, PriceAlerts.isAlertValid(alert))
+}
+
+func TestEvaluateStockPriceAlerts_UserCannotBeNotified(t *testing.T) {
+	initializeTestDataStore(t, new(models.User), new(models.PriceAlert))
+	settings.SetCurrentConfig(&settings.Config{EnableSMTP: true})
+
+	c := core.NewNullContext()
+
+	_, err := PriceAlerts.UserDB().NewSession(c).Insert(&models.User{Uid: 1, Username: "test", Email: "test@example.com", Disabled: true})
+	assert.Nil(t, err)
+
+	_, err = PriceAlerts.UserDataDB(1).NewSession(c).Insert(&models.PriceAlert{AlertId: 1001, Uid: 1, AssetType: models.ACCOUNT_ASSET_TYPE_STOCK, Symbol: "AAPL", Currency: "USD", Type: models.PRICE_ALERT_TYPE_ABOVE, Threshold: "200"})
+	assert.Nil(t, err)
+
+	err = PriceAlerts.EvaluateStockPriceAlerts(c, "test", &models.LatestStockPriceResponse{
+		BaseCurrency: "USD",
+		Prices:       []*models.LatestStockPrice{{Symbol: "AAPL", Price: "210"}},
+	})
+	assert.Nil(t, err)
+
+	alerts, err := PriceAlerts.GetAllAlertsByUid(c, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(alerts))
+	assert.False(t, alerts[0].Triggered)
+	assert.Equal(t, "", alerts[0].LastTriggeredPrice)
+}
+
+func TestEvaluateStockPriceAlerts_ResetWithoutSMTP(t *testing.T) {
+	initializeTestDataStore(t, new(models.User), new(models.PriceAlert))
+	settings.SetCurrentConfig(&settings.Config{EnableSMTP: false})
+
+	c := core.NewNullContext()
+
+	_, err := PriceAlerts.UserDataDB(1).NewSession(c).Insert(&models.PriceAlert{AlertId: 1001, Uid: 1, AssetType: models.ACCOUNT_ASSET_TYPE_STOCK, Symbol: "AAPL", Currency: "USD", Type: models.PRICE_ALERT_TYPE_ABOVE, Threshold: "200", Triggered: true, LastTriggeredPrice: "210"})
+	assert.Nil(t, err)
+
+	_, err = PriceAlerts.UserDataDB(1).NewSession(c).Insert(&models.PriceAlert{AlertId: 1002, Uid: 1, AssetType: models.ACCOUNT_ASSET_TYPE_STOCK, Symbol: "AAPL", Currency: "USD", Type: models.PRICE_ALERT_TYPE_BELOW, Threshold: "200"})
+	assert.Nil(t, err)
+
+	err = PriceAlerts.EvaluateStockPriceAlerts(c, "test", &models.LatestStockPriceResponse{
+		BaseCurrency: "USD",
+		Prices:       []*models.LatestStockPrice{{Symbol: "AAPL", Price: "190"}},
+	})
+	assert.Nil(t, err)
+
+	alerts, err := PriceAlerts.GetAllAlertsByUid(c, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(alerts))
+	assert.Equal(t, int64(1001), alerts[0].AlertId)
+	assert.False(t, alerts[0].Triggered)
+	assert.Equal(t, int64(1002), alerts[1].AlertId)
+	assert.False(t, alerts[1].Triggered)
+}
+
+func TestEvaluateStockPriceAlerts_CurrencyMismatch(t *testing.T) {
+	initializeTestDataStore(t, new(models.User), new(models.PriceAlert))
+	settings.SetCurrentConfig(&settings.Config{EnableSMTP: false})
+
+	c := core.NewNullContext()
+
+	_, err := PriceAlerts.UserDataDB(1).NewSession(c).Insert(&models.PriceAlert{AlertId: 1001, Uid: 1, AssetType: models.ACCOUNT_ASSET_TYPE_STOCK, Symbol: "AAPL", Currency: "USD", Type: models.PRICE_ALERT_TYPE_ABOVE, Threshold: "200", Triggered: true, LastTriggeredPrice: "210"})
+	assert.Nil(t, err)
+
+	_, err = PriceAlerts.UserDataDB(1).NewSession(c).Insert(&models.PriceAlert{AlertId: 1002, Uid: 1, AssetType: models.ACCOUNT_ASSET_TYPE_CRYPTO, Symbol: "AAPL", Currency: "EUR", Type: models.PRICE_ALERT_TYPE_ABOVE, Threshold: "200", Triggered: true, LastTriggeredPrice: "210"})
+	assert.Nil(t, err)
+
+	_, err = PriceAlerts.UserDataDB(1).NewSession(c).Insert(&models.PriceAlert{AlertId: 1003, Uid: 1, AssetType: models.ACCOUNT_ASSET_TYPE_STOCK, Symbol: "AAPL", Currency: "EUR", Type: models.PRICE_ALERT_TYPE_ABOVE, Threshold: "200", Triggered: true, LastTriggeredPrice: "210"})
+	assert.Nil(t, err)
+
+	// the price in euro is below the threshold of the alert in euro, but cannot be compared with the alert in us dollar or the alert of cryptocurrency
+	err = PriceAlerts.EvaluateStockPriceAlerts(c, "test", &models.LatestStockPriceResponse{
+		BaseCurrency: "USD",
+		Prices:       []*models.LatestStockPrice{{Symbol: "AAPL", Price: "190", Currency: "EUR"}},
+	})
+	assert.Nil(t, err)
+
+	alerts, err := PriceAlerts.GetAllAlertsByUid(c, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(alerts))
+
+	for i := 0; i < len(alerts); i++ {
+		assert.Equal(t, alerts[i].AlertId != 1003, alerts[i].Triggered)
+	}
+}
+
+func TestPriceAlertGetBasePrice_SameDataSource(t *testing.T) {
+	initializeTestDataStore(t, new(models.StockPriceHistory))
+
+	c := core.NewNullContext()
+
+	_, err := PriceAlerts.UserDataDB(0).NewSession(c).Insert(&models.StockPriceHistory{Symbol: "AAPL", DataSource: "source_a", Currency: "USD", PriceDate: 20200101, Price: "100"})
+	assert.Nil(t, err)
+
+	_, err = PriceAlerts.UserDataDB(0).NewSession(c).Insert(&models.StockPriceHistory{Symbol: "AAPL", DataSource: "source_b", Currency: "USD", PriceDate: 20200102, Price: "200"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, float64(100), PriceAlerts.getBasePrice(c, models.ACCOUNT_ASSET_TYPE_STOCK, "AAPL", "USD", "source_a", 7))
+	assert.Equal(t, float64(200), PriceAlerts.getBasePrice(c, models.ACCOUNT_ASSET_TYPE_STOCK, "AAPL", "USD", "source_b", 7))
+}
@@ -0,0 +1,611 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// StockCorporateActionService represents stock corporate action service
+type StockCorporateActionService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+	stocks *StockService
+}
+
+// Initialize a stock corporate action service singleton instance
+var (
+	StockCorporateActions = &StockCorporateActionService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		stocks: Stocks,
+	}
+)
+
+// GetAllActions returns all stock corporate action models, or the actions related to the specified symbol if it is not empty
+func (s *StockCorporateActionService) GetAllActions(c core.Context, symbol string) ([]*models.StockCorporateAction, error) {
+	var actions []*models.StockCorporateAction
+	sess := s.UserDataDB(0).NewSession(c)
+
+	if symbol != "" {
+		symbol = strings.ToUpper(symbol)
+		sess = sess.Where("symbol=? OR new_symbol=?", symbol, symbol)
+	}
+
+	err := sess.OrderBy("effective_time desc, action_id desc").Find(&actions)
+
+	return actions, err
+}
+
+// GetActionByActionId returns a stock corporate action model according to action id
+func (s *StockCorporateActionService) GetActionByActionId(c core.Context, actionId int64) (*models.StockCorporateAction, error) {
+	action := &models.StockCorporateAction{}
+	has, err := s.UserDataDB(0).NewSession(c).ID(actionId).Get(action)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrStockCorporateActionNotFound
+	}
+
+	return action, nil
+}
+
+// CreateAction saves a new stock corporate action model to database,
+// and adjusts the price histories of the stock, the watchlists and the trades of all the stock accounts holding it,
+// the action is kept unapplied if applying to the user data fails partway and it can be resumed by ApplyAction
+func (s *StockCorporateActionService) CreateAction(c core.Context, action *models.StockCorporateAction) error {
+	err := s.isActionValid(action)
+
+	if err != nil {
+		return err
+	}
+
+	exists, err := s.stocks.IsSymbolWatched(c, action.Symbol)
+
+	if err != nil {
+		return err
+	} else if !exists {
+		return errs.ErrStockNotFound
+	}
+
+	if action.Type == models.STOCK_CORPORATE_ACTION_TYPE_SYMBOL_CHANGE {
+		exists, err = s.stocks.IsSymbolWatched(c, action.NewSymbol)
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrStockCorporateActionNewSymbolExists
+		}
+	}
+
+	now := time.Now().Unix()
+	action.EffectiveDate = utils.FormatUnixTimeToNumericYearMonthDay(action.EffectiveTime, time.UTC)
+	action.Applied = false
+	action.CreatedUnixTime = now
+	action.UpdatedUnixTime = now
+
+	err = s.UserDataDB(0).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(action)
+
+		if err != nil {
+			return err
+		}
+
+		return s.applyToStockData(sess, action, false)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return s.applyAction(c, action)
+}
+
+// ApplyAction applies an existed stock corporate action to the user data in all the databases which it has not been applied to,
+// it is used to resume the corporate action whose previous applying failed partway
+func (s *StockCorporateActionService) ApplyAction(c core.Context, actionId int64) (*models.StockCorporateAction, error) {
+	action, err := s.GetActionByActionId(c, actionId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if action.Applied {
+		return action, nil
+	}
+
+	err = s.applyAction(c, action)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return action, nil
+}
+
+// DeleteAction deletes an existed stock corporate action from database and reverts all the adjustments it made,
+// only the latest corporate action of a stock can be deleted
+func (s *StockCorporateActionService) DeleteAction(c core.Context, actionId int64) error {
+	action, err := s.GetActionByActionId(c, actionId)
+
+	if err != nil {
+		return err
+	}
+
+	newSymbol := action.NewSymbol
+
+	if newSymbol == "" {
+		newSymbol = action.Symbol
+	}
+
+	exists, err := s.UserDataDB(0).NewSession(c).Where("action_id>? AND (symbol=? OR symbol=? OR new_symbol=? OR new_symbol=?)", actionId, action.Symbol, newSymbol, action.Symbol, newSymbol).Exist(&models.StockCorporateAction{})
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrStockCorporateActionNotLatest
+	}
+
+	err = s.applyToAllUserData(c, action, true)
+
+	if err != nil {
+		return err
+	}
+
+	return s.UserDataDB(0).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(actionId).Delete(&models.StockCorporateAction{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrStockCorporateActionNotFound
+		}
+
+		return s.applyToStockData(sess, action, true)
+	})
+}
+
+func (s *StockCorporateActionService) applyToStockData(sess *xorm.Session, action *models.StockCorporateAction, revert bool) error {
+	now := time.Now().Unix()
+
+	if action.IsSplit() {
+		var histories []*models.StockPriceHistory
+		err := sess.Where("symbol=? AND price_date<?", action.Symbol, action.EffectiveDate).Find(&histories)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(histories); i++ {
+			history := histories[i]
+			adjustedPrice, ok := action.GetAdjustedPrice(history.Price, revert)
+
+			if !ok {
+				continue
+			}
+
+			history.Price = adjustedPrice
+			history.UpdatedUnixTime = now
+
+			_, err = sess.Cols("price", "updated_unix_time").Where("symbol=? AND data_source=? AND currency=? AND price_date=?", history.Symbol, history.DataSource, history.Currency, history.PriceDate).Update(history)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	oldSymbol := action.Symbol
+	newSymbol := action.NewSymbol
+
+	if revert {
+		oldSymbol, newSymbol = newSymbol, oldSymbol
+	}
+
+	_, err := sess.Cols("symbol", "updated_unix_time").Where("uid=? AND symbol=?", 0, oldSymbol).Update(&models.Stock{
+		Symbol:          newSymbol,
+		UpdatedUnixTime: now,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	var histories []*models.StockPriceHistory
+	err = sess.Where("symbol=?", oldSymbol).Find(&histories)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(histories); i++ {
+		history := histories[i]
+		condition := "symbol=? AND data_source=? AND currency=? AND price_date=?"
+
+		exists, err := sess.Where(condition, newSymbol, history.DataSource, history.Currency, history.PriceDate).Exist(&models.StockPriceHistory{})
+
+		if err != nil {
+			return err
+		}
+
+		// the price already saved with the new symbol takes precedence
+		if exists {
+			_, err = sess.Where(condition, history.Symbol, history.DataSource, history.Currency, history.PriceDate).Delete(&models.StockPriceHistory{})
+		} else {
+			_, err = sess.Cols("symbol", "updated_unix_time").Where(condition, history.Symbol, history.DataSource, history.Currency, history.PriceDate).Update(&models.StockPriceHistory{
+				Symbol:          newSymbol,
+				UpdatedUnixTime: now,
+			})
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *StockCorporateActionService) applyAction(c core.Context, action *models.StockCorporateAction) error {
+	err := s.applyToAllUserData(c, action, false)
+
+	if err != nil {
+		return err
+	}
+
+	action.Applied = true
+	action.UpdatedUnixTime = time.Now().Unix()
+
+	_, err = s.UserDataDB(0).NewSession(c).ID(action.ActionId).Cols("applied", "updated_unix_time").Update(action)
+
+	return err
+}
+
+func (s *StockCorporateActionService) applyToAllUserData(c core.Context, action *models.StockCorporateAction, revert bool) error {
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		err := s.UserDataDBByIndex(i).DoTransaction(c, func(sess *xorm.Session) error {
+			applied, err := sess.ID(action.ActionId).Exist(&models.StockCorporateActionAppliedRecord{})
+
+			if err != nil {
+				return err
+			}
+
+			// the databases which the action has already been applied to (or reverted from) are skipped, so applying or reverting again is safe
+			if applied != revert {
+				return nil
+			}
+
+			err = s.applyToUserData(sess, action, revert)
+
+			if err != nil {
+				return err
+			}
+
+			if revert {
+				_, err = sess.ID(action.ActionId).Delete(&models.StockCorporateActionAppliedRecord{})
+			} else {
+				_, err = sess.Insert(&models.StockCorporateActionAppliedRecord{
+					ActionId:        action.ActionId,
+					CreatedUnixTime: time.Now().Unix(),
+				})
+			}
+
+			return err
+		})
+
+		if err != nil {
+			log.Errorf(c, "[stock_corporate_actions.applyToAllUserData] failed to apply corporate action \"id:%d\" to user data in database #%d, because %s", action.ActionId, i, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *StockCorporateActionService) applyToUserData(sess *xorm.Session, action *models.StockCorporateAction, revert bool) error {
+	now := time.Now().Unix()
+
+	if action.IsSplit() && revert {
+		var trades []*models.InvestmentTrade
+		err := sess.Where("corporate_action_id=? AND deleted=?", action.ActionId, false).Find(&trades)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(trades); i++ {
+			if trades[i].TransactionId <= 0 {
+				continue
+			}
+
+			err = s.deleteSplitQuantityAdjustmentTransaction(sess, trades[i], now)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("corporate_action_id=? AND deleted=?", action.ActionId, false).Update(&models.InvestmentTrade{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		})
+
+		return err
+	}
+
+	symbol := action.Symbol
+
+	if revert {
+		symbol = action.NewSymbol
+	}
+
+	if !action.IsSplit() {
+		newSymbol := action.NewSymbol
+
+		if revert {
+			newSymbol = action.Symbol
+		}
+
+		_, err := sess.Cols("symbol", "updated_unix_time").Where("uid>? AND symbol=?", 0, symbol).Update(&models.Stock{
+			Symbol:          newSymbol,
+			UpdatedUnixTime: now,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	var accounts []*models.Account
+	err := sess.Where("deleted=? AND type=? AND currency=?", false, models.ACCOUNT_TYPE_SINGLE_ACCOUNT, symbol).Find(&accounts)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.GetAssetType() != models.ACCOUNT_ASSET_TYPE_STOCK {
+			continue
+		}
+
+		if action.IsSplit() {
+			transactionId, err := s.createSplitQuantityAdjustmentTransaction(sess, action, account, now)
+
+			if err != nil {
+				return err
+			}
+
+			trade := &models.InvestmentTrade{
+				TradeId:           s.GenerateUuid(uuid.UUID_TYPE_INVESTMENT_TRADE),
+				Uid:               account.Uid,
+				Deleted:           false,
+				AccountId:         account.AccountId,
+				TradeTime:         action.EffectiveTime,
+				Type:              models.INVESTMENT_TRADE_TYPE_SPLIT,
+				Symbol:            account.Currency,
+				SplitNumerator:    action.RatioNumerator,
+				SplitDenominator:  action.RatioDenominator,
+				TransactionId:     transactionId,
+				CorporateActionId: action.ActionId,
+				Comment:           action.Comment,
+				CreatedUnixTime:   now,
+				UpdatedUnixTime:   now,
+			}
+
+			if trade.TradeId < 1 {
+				return errs.ErrSystemIsBusy
+			}
+
+			_, err = sess.Insert(trade)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		newSymbol := action.NewSymbol
+
+		if revert {
+			newSymbol = action.Symbol
+		}
+
+		_, err = sess.ID(account.AccountId).Cols("currency", "updated_unix_time").Where("uid=? AND deleted=?", account.Uid, false).Update(&models.Account{
+			Currency:        newSymbol,
+			UpdatedUnixTime: now,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("symbol", "updated_unix_time").Where("uid=? AND account_id=? AND symbol=?", account.Uid, account.AccountId, symbol).Update(&models.InvestmentTrade{
+			Symbol:          newSymbol,
+			UpdatedUnixTime: now,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createSplitQuantityAdjustmentTransaction multiplies the share count of the stock account at the effective time by the split ratio,
+// and records the increased shares as an income transaction at the effective time (a balance modification transaction can only be the first transaction of an account),
+// returns the id of the transaction, or zero if the share count is not changed
+func (s *StockCorporateActionService) createSplitQuantityAdjustmentTransaction(sess *xorm.Session, action *models.StockCorporateAction, account *models.Account, now int64) (int64, error) {
+	transactionTime := utils.GetMinTransactionTimeFromUnixTime(action.EffectiveTime)
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(action.EffectiveTime)
+
+	sameSecondLatestTransaction := &models.Transaction{}
+	has, err := sess.Where("uid=? AND transaction_time>=? AND transaction_time<=?", account.Uid, transactionTime, maxTransactionTime).OrderBy("transaction_time desc").Limit(1).Get(sameSecondLatestTransaction)
+
+	if err != nil {
+		return 0, err
+	} else if has {
+		if sameSecondLatestTransaction.TransactionTime == maxTransactionTime-1 {
+			return 0, errs.ErrTooMuchTransactionInOneSecond
+		}
+
+		transactionTime = sameSecondLatestTransaction.TransactionTime + 1
+	}
+
+	// the share count at the effective time is the current balance excluding the changes of the transactions after the effective time
+	var laterTransactions []*models.Transaction
+	err = sess.Where("uid=? AND deleted=? AND account_id=? AND transaction_time>=?", account.Uid, false, account.AccountId, transactionTime).Find(&laterTransactions)
+
+	if err != nil {
+		return 0, err
+	}
+
+	balance := account.Balance
+
+	for i := 0; i < len(laterTransactions); i++ {
+		balance -= getTransactionAccountBalanceChange(laterTransactions[i])
+	}
+
+	increasedShares := balance*int64(action.RatioNumerator)/int64(action.RatioDenominator) - balance
+
+	if increasedShares <= 0 {
+		return 0, nil
+	}
+
+	category := &models.TransactionCategory{}
+	has, err = sess.Where("uid=? AND deleted=? AND type=? AND parent_category_id<>? AND hidden=?", account.Uid, false, models.CATEGORY_TYPE_INCOME, models.LevelOneTransactionCategoryParentId, false).OrderBy("display_order asc, category_id asc").Limit(1).Get(category)
+
+	if err != nil {
+		return 0, err
+	} else if !has {
+		return 0, errs.ErrTransactionCategoryNotFound
+	}
+
+	transactionId := s.GenerateUuid(uuid.UUID_TYPE_TRANSACTION)
+
+	if transactionId < 1 {
+		return 0, errs.ErrSystemIsBusy
+	}
+
+	transaction := &models.Transaction{
+		TransactionId:     transactionId,
+		Uid:               account.Uid,
+		Deleted:           false,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		CategoryId:        category.CategoryId,
+		TransactionTime:   transactionTime,
+		TimezoneUtcOffset: utils.GetServerTimezoneOffsetMinutes(),
+		AccountId:         account.AccountId,
+		Amount:            increasedShares,
+		Comment:           action.Comment,
+		CreatedIp:         "127.0.0.1",
+		CreatedUnixTime:   now,
+		UpdatedUnixTime:   now,
+	}
+
+	_, err = sess.Insert(transaction)
+
+	if err != nil {
+		return 0, err
+	}
+
+	updatedRows, err := sess.ID(account.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", account.Uid, false).Update(&models.Account{UpdatedUnixTime: now})
+
+	if err != nil {
+		return 0, err
+	} else if updatedRows < 1 {
+		return 0, errs.ErrDatabaseOperationFailed
+	}
+
+	return transactionId, nil
+}
+
+// deleteSplitQuantityAdjustmentTransaction deletes the income transaction linked to the split trade and restores the account balance,
+// the transaction which has already been deleted by user is skipped
+func (s *StockCorporateActionService) deleteSplitQuantityAdjustmentTransaction(sess *xorm.Session, trade *models.InvestmentTrade, now int64) error {
+	transaction := &models.Transaction{}
+	has, err := sess.ID(trade.TransactionId).Where("uid=? AND deleted=? AND type=?", trade.Uid, false, models.TRANSACTION_DB_TYPE_INCOME).Get(transaction)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return nil
+	}
+
+	_, err = sess.ID(transaction.TransactionId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", transaction.Uid, false).Update(&models.Transaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", transaction.Uid, false).Update(&models.Account{UpdatedUnixTime: now})
+
+	return err
+}
+
+func (s *StockCorporateActionService) isActionValid(action *models.StockCorporateAction) error {
+	action.Symbol = strings.ToUpper(strings.TrimSpace(action.Symbol))
+	action.NewSymbol = strings.ToUpper(strings.TrimSpace(action.NewSymbol))
+
+	switch action.Type {
+	case models.STOCK_CORPORATE_ACTION_TYPE_SPLIT:
+		if action.RatioNumerator <= 0 || action.RatioDenominator <= 0 || action.RatioNumerator <= action.RatioDenominator {
+			return errs.ErrStockCorporateActionRatioInvalid
+		}
+
+		action.NewSymbol = ""
+	case models.STOCK_CORPORATE_ACTION_TYPE_REVERSE_SPLIT:
+		if action.RatioNumerator <= 0 || action.RatioDenominator <= 0 || action.RatioNumerator >= action.RatioDenominator {
+			return errs.ErrStockCorporateActionRatioInvalid
+		}
+
+		action.NewSymbol = ""
+	case models.STOCK_CORPORATE_ACTION_TYPE_SYMBOL_CHANGE:
+		if action.NewSymbol == "" || action.NewSymbol == action.Symbol {
+			return errs.ErrStockCorporateActionNewSymbolInvalid
+		}
+
+		action.RatioNumerator = 0
+		action.RatioDenominator = 0
+	default:
+		return errs.ErrStockCorporateActionTypeInvalid
+	}
+
+	return nil
+}
+
+func getTransactionAccountBalanceChange(transaction *models.Transaction) int64 {
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		return transaction.RelatedAccountAmount
+	case models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+		return transaction.Amount
+	case models.TRANSACTION_DB_TYPE_EXPENSE, models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		return -transaction.Amount
+	default:
+		return 0
+	}
+}
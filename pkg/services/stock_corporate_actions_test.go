@@ -0,0 +1,200 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestStockCorporateActionIsActionValid_Split(t *testing.T) {
+	action := &models.StockCorporateAction{Symbol: " tsla ", Type: models.STOCK_CORPORATE_ACTION_TYPE_SPLIT, NewSymbol: "TSLA2", RatioNumerator: 3, RatioDenominator: 1}
+	assert.Nil(t, StockCorporateActions.isActionValid(action))
+	assert.Equal(t, "TSLA", action.Symbol)
+	assert.Equal(t, "", action.NewSymbol)
+
+	action = &models.StockCorporateAction{Symbol: "TSLA", Type: models.STOCK_CORPORATE_ACTION_TYPE_SPLIT, RatioNumerator: 1, RatioDenominator: 3}
+	assert.Equal(t, errs.ErrStockCorporateActionRatioInvalid, StockCorporateActions.isActionValid(action))
+
+	action = &models.StockCorporateAction{Symbol: "TSLA", Type: models.STOCK_CORPORATE_ACTION_TYPE_SPLIT, RatioNumerator: 3}
+	assert.Equal(t, errs.ErrStockCorporateActionRatioInvalid, StockCorporateActions.isActionValid(action))
+}
+
+func TestStockCorporateActionIsActionValid_ReverseSplit(t *testing.T) {
+	action := &models.StockCorporateAction{Symbol: "GE", Type: models.STOCK_CORPORATE_ACTION_TYPE_REVERSE_SPLIT, RatioNumerator: 1, RatioDenominator: 8}
+	assert.Nil(t, StockCorporateActions.isActionValid(action))
+
+	action = &models.StockCorporateAction{Symbol: "GE", Type: models.STOCK_CORPORATE_ACTION_TYPE_REVERSE_SPLIT, RatioNumerator: 8, RatioDenominator: 1}
+	assert.Equal(t, errs.ErrStockCorporateActionRatioInvalid, StockCorporateActions.isActionValid(action))
+}
+
+func TestStockCorporateActionIsActionValid_SymbolChange(t *testing.T) {
+	action := &models.StockCorporateAction{Symbol: "FB", Type: models.STOCK_CORPORATE_ACTION_TYPE_SYMBOL_CHANGE, NewSymbol: "meta", RatioNumerator: 2, RatioDenominator: 1}
+	assert.Nil(t, StockCorporateActions.isActionValid(action))
+	assert.Equal(t, "META", action.NewSymbol)
+	assert.Equal(t, int32(0), action.RatioNumerator)
+	assert.Equal(t, int32(0), action.RatioDenominator)
+
+	action = &models.StockCorporateAction{Symbol: "FB", Type: models.STOCK_CORPORATE_ACTION_TYPE_SYMBOL_CHANGE, NewSymbol: "fb"}
+	assert.Equal(t, errs.ErrStockCorporateActionNewSymbolInvalid, StockCorporateActions.isActionValid(action))
+
+	action = &models.StockCorporateAction{Symbol: "FB", Type: models.STOCK_CORPORATE_ACTION_TYPE_SYMBOL_CHANGE}
+	assert.Equal(t, errs.ErrStockCorporateActionNewSymbolInvalid, StockCorporateActions.isActionValid(action))
+}
+
+func TestStockCorporateActionIsActionValid_InvalidType(t *testing.T) {
+	action := &models.StockCorporateAction{Symbol: "TSLA", Type: 4}
+	assert.Equal(t, errs.ErrStockCorporateActionTypeInvalid, StockCorporateActions.isActionValid(action))
+}
+
+func initializeStockCorporateActionTestData(t *testing.T) {
+	initializeTestDataStore(t, new(models.Account), new(models.Stock), new(models.StockPriceHistory), new(models.StockCorporateAction), new(models.StockCorporateActionAppliedRecord), new(models.InvestmentTrade), new(models.Transaction), new(models.TransactionCategory), new(models.TransactionTag), new(models.TransactionTagIndex), new(models.TransactionSplit), new(models.TransactionPictureInfo))
+
+	sess := StockCorporateActions.UserDataDB(0).NewSession(core.NewNullContext())
+
+	_, err := sess.Insert(&models.TransactionCategory{CategoryId: 100, Uid: 1, Type: models.CATEGORY_TYPE_INCOME, ParentCategoryId: models.LevelOneTransactionCategoryParentId, Name: "Investment Income"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.TransactionCategory{CategoryId: 101, Uid: 1, Type: models.CATEGORY_TYPE_INCOME, ParentCategoryId: 100, Name: "Stock Split"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Stock{Uid: 1, Symbol: "TSLA", Name: "Tesla"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Account{AccountId: 1001, Uid: 1, Name: "Tesla Shares", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "TSLA", Balance: 1000, Extend: &models.AccountExtend{AssetType: models.ACCOUNT_ASSET_TYPE_STOCK}})
+	assert.Nil(t, err)
+}
+
+func TestStockCorporateActionApplyAction_AppliedOnlyOnce(t *testing.T) {
+	initializeStockCorporateActionTestData(t)
+
+	c := core.NewNullContext()
+	action := &models.StockCorporateAction{Symbol: "TSLA", Type: models.STOCK_CORPORATE_ACTION_TYPE_SPLIT, RatioNumerator: 3, RatioDenominator: 1, EffectiveTime: 1700000000}
+	err := StockCorporateActions.CreateAction(c, action)
+	assert.Nil(t, err)
+	assert.True(t, action.Applied)
+
+	sess := StockCorporateActions.UserDataDB(0).NewSession(c)
+
+	// simulate the action whose previous applying failed after the user data was adjusted
+	_, err = sess.ID(action.ActionId).Cols("applied").Update(&models.StockCorporateAction{Applied: false})
+	assert.Nil(t, err)
+
+	appliedAction, err := StockCorporateActions.ApplyAction(c, action.ActionId)
+	assert.Nil(t, err)
+	assert.True(t, appliedAction.Applied)
+
+	count, err := sess.Where("corporate_action_id=? AND deleted=?", action.ActionId, false).Count(&models.InvestmentTrade{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestStockCorporateActionApplyAndDeleteAction_SplitAdjustsAccountBalance(t *testing.T) {
+	initializeStockCorporateActionTestData(t)
+
+	c := core.NewNullContext()
+	action := &models.StockCorporateAction{Symbol: "TSLA", Type: models.STOCK_CORPORATE_ACTION_TYPE_SPLIT, RatioNumerator: 3, RatioDenominator: 1, EffectiveTime: 1700000000}
+	err := StockCorporateActions.CreateAction(c, action)
+	assert.Nil(t, err)
+
+	sess := StockCorporateActions.UserDataDB(0).NewSession(c)
+
+	account := &models.Account{}
+	_, err = sess.ID(1001).Get(account)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3000), account.Balance)
+
+	trade := &models.InvestmentTrade{}
+	has, err := sess.Where("corporate_action_id=? AND deleted=?", action.ActionId, false).Get(trade)
+	assert.Nil(t, err)
+	assert.True(t, has)
+
+	transaction := &models.Transaction{}
+	has, err = sess.ID(trade.TransactionId).Where("deleted=?", false).Get(transaction)
+	assert.Nil(t, err)
+	assert.True(t, has)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, transaction.Type)
+	assert.Equal(t, int64(101), transaction.CategoryId)
+	assert.Equal(t, int64(2000), transaction.Amount)
+
+	err = StockCorporateActions.DeleteAction(c, action.ActionId)
+	assert.Nil(t, err)
+
+	account = &models.Account{}
+	_, err = sess.ID(1001).Get(account)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), account.Balance)
+
+	exists, err := sess.ID(trade.TransactionId).Where("deleted=?", false).Exist(&models.Transaction{})
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestStockCorporateActionApplyAction_SplitAdjustsAccountBalanceAtEffectiveTime(t *testing.T) {
+	initializeStockCorporateActionTestData(t)
+
+	c := core.NewNullContext()
+	sess := StockCorporateActions.UserDataDB(0).NewSession(c)
+
+	// 500 shares are bought after the split, so only the other 500 shares are split
+	_, err := sess.Insert(&models.Transaction{TransactionId: 2001, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 101, AccountId: 1001, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1710000000), Amount: 500})
+	assert.Nil(t, err)
+
+	action := &models.StockCorporateAction{Symbol: "TSLA", Type: models.STOCK_CORPORATE_ACTION_TYPE_SPLIT, RatioNumerator: 3, RatioDenominator: 1, EffectiveTime: 1700000000}
+	err = StockCorporateActions.CreateAction(c, action)
+	assert.Nil(t, err)
+
+	account := &models.Account{}
+	_, err = sess.ID(1001).Get(account)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2000), account.Balance)
+}
+
+func TestStockCorporateActionApplyAction_CreateTransactionBeforeSplit(t *testing.T) {
+	initializeStockCorporateActionTestData(t)
+
+	c := core.NewNullContext()
+	action := &models.StockCorporateAction{Symbol: "TSLA", Type: models.STOCK_CORPORATE_ACTION_TYPE_SPLIT, RatioNumerator: 3, RatioDenominator: 1, EffectiveTime: 1700000000}
+	err := StockCorporateActions.CreateAction(c, action)
+	assert.Nil(t, err)
+
+	transaction := &models.Transaction{Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 101, AccountId: 1001, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1690000000), Amount: 100}
+	err = Transactions.CreateTransaction(c, transaction, nil, nil, nil)
+	assert.Nil(t, err)
+}
+
+func TestStockCorporateActionDeleteAction_UserDataAlreadyReverted(t *testing.T) {
+	initializeStockCorporateActionTestData(t)
+
+	c := core.NewNullContext()
+	action := &models.StockCorporateAction{Symbol: "TSLA", Type: models.STOCK_CORPORATE_ACTION_TYPE_SYMBOL_CHANGE, NewSymbol: "TSLQ", EffectiveTime: 1700000000}
+	err := StockCorporateActions.CreateAction(c, action)
+	assert.Nil(t, err)
+
+	sess := StockCorporateActions.UserDataDB(0).NewSession(c)
+
+	account := &models.Account{}
+	_, err = sess.ID(1001).Get(account)
+	assert.Nil(t, err)
+	assert.Equal(t, "TSLQ", account.Currency)
+
+	// simulate the deletion whose previous attempt failed after the user data was reverted
+	err = StockCorporateActions.applyToAllUserData(c, action, true)
+	assert.Nil(t, err)
+
+	err = StockCorporateActions.DeleteAction(c, action.ActionId)
+	assert.Nil(t, err)
+
+	account = &models.Account{}
+	_, err = sess.ID(1001).Get(account)
+	assert.Nil(t, err)
+	assert.Equal(t, "TSLA", account.Currency)
+
+	exists, err := sess.Where("symbol=? AND deleted=?", "TSLA", false).Exist(&models.Stock{})
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
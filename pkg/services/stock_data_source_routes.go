@@ -0,0 +1,189 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const stockSymbolMarketsCacheExpiration = 10 * time.Minute
+
+// StockDataSourceRouteService represents stock data source route service
+type StockDataSourceRouteService struct {
+	ServiceUsingDB
+	externalDataSourceConfigs *ExternalDataSourceConfigService
+	stocks                    *StockService
+	symbolMarkets             map[string][]string
+	symbolMarketsUpdateTime   time.Time
+	symbolMarketsMutex        sync.RWMutex
+}
+
+// Initialize a stock data source route service singleton instance
+var (
+	StockDataSourceRoutes = &StockDataSourceRouteService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		externalDataSourceConfigs: ExternalDataSourceConfigs,
+		stocks:                    Stocks,
+	}
+)
+
+// GetAllRoutes returns all stock data source routes
+func (s *StockDataSourceRouteService) GetAllRoutes(c core.Context) ([]*models.StockDataSourceRoute, error) {
+	var routes []*models.StockDataSourceRoute
+	err := s.UserDataDB(0).NewSession(c).OrderBy("market asc").Find(&routes)
+
+	return routes, err
+}
+
+// SaveRoute saves or updates the ordered data sources of a market
+func (s *StockDataSourceRouteService) SaveRoute(c core.Context, market string, dataSources []string) (*models.StockDataSourceRoute, error) {
+	configs, err := s.externalDataSourceConfigs.GetConfigs(c, models.EXTERNAL_DATA_SOURCE_TYPE_STOCK)
+
+	if err != nil {
+		return nil, err
+	}
+
+	configuredDataSources := make(map[string]bool, len(configs))
+
+	for i := 0; i < len(configs); i++ {
+		configuredDataSources[configs[i].DataSource] = true
+	}
+
+	finalDataSources := make([]string, 0, len(dataSources))
+	addedDataSources := make(map[string]bool, len(dataSources))
+
+	for i := 0; i < len(dataSources); i++ {
+		dataSource := strings.TrimSpace(dataSources[i])
+
+		if !configuredDataSources[dataSource] {
+			return nil, errs.ErrStockDataSourceNotConfigured
+		}
+
+		if addedDataSources[dataSource] {
+			continue
+		}
+
+		addedDataSources[dataSource] = true
+		finalDataSources = append(finalDataSources, dataSource)
+	}
+
+	now := time.Now().Unix()
+
+	route := &models.StockDataSourceRoute{
+		Market:          market,
+		DataSources:     strings.Join(finalDataSources, ","),
+		CreatedUnixTime: now,
+		UpdatedUnixTime: now,
+	}
+
+	err = s.UserDataDB(0).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Where("market=?", route.Market).Exist(&models.StockDataSourceRoute{})
+
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			_, err = sess.Cols("data_sources", "updated_unix_time").Where("market=?", route.Market).Update(route)
+		} else {
+			_, err = sess.Insert(route)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return route, nil
+}
+
+// DeleteRoute deletes the route of a market
+func (s *StockDataSourceRouteService) DeleteRoute(c core.Context, market string) error {
+	deletedRows, err := s.UserDataDB(0).NewSession(c).Where("market=?", market).Delete(&models.StockDataSourceRoute{})
+
+	if err != nil {
+		return err
+	} else if deletedRows < 1 {
+		return errs.ErrStockDataSourceRouteNotFound
+	}
+
+	return nil
+}
+
+// RefreshSymbolMarkets reloads the markets of all the watched stocks and caches them for routing,
+// the same symbol may be watched in different markets by different users, so all of its markets are kept
+func (s *StockDataSourceRouteService) RefreshSymbolMarkets(c core.Context) (map[string][]string, error) {
+	symbolMarkets, err := s.stocks.GetAllWatchedStockMarkets(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.symbolMarketsMutex.Lock()
+	defer s.symbolMarketsMutex.Unlock()
+
+	s.symbolMarkets = symbolMarkets
+	s.symbolMarketsUpdateTime = time.Now()
+
+	return symbolMarkets, nil
+}
+
+// InvalidateSymbolMarkets clears the cached markets of stocks, it should be called after any stock in the watchlists is created, modified or deleted
+func (s *StockDataSourceRouteService) InvalidateSymbolMarkets() {
+	s.symbolMarketsMutex.Lock()
+	defer s.symbolMarketsMutex.Unlock()
+
+	s.symbolMarkets = nil
+}
+
+// GetRouting returns all the configured stock data sources, routes and the cached markets of stocks,
+// the markets of stocks are refreshed by the stock price update job and only reloaded here when the cache is missing or expired
+func (s *StockDataSourceRouteService) GetRouting(c core.Context) (*models.StockDataSourceRouting, error) {
+	configs, err := s.externalDataSourceConfigs.GetConfigs(c, models.EXTERNAL_DATA_SOURCE_TYPE_STOCK)
+
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := s.GetAllRoutes(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.symbolMarketsMutex.RLock()
+	symbolMarkets := s.symbolMarkets
+	symbolMarketsUpdateTime := s.symbolMarketsUpdateTime
+	s.symbolMarketsMutex.RUnlock()
+
+	if symbolMarkets == nil || time.Since(symbolMarketsUpdateTime) > stockSymbolMarketsCacheExpiration {
+		symbolMarkets, err = s.RefreshSymbolMarkets(c)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	routing := &models.StockDataSourceRouting{
+		Configs:           configs,
+		MarketDataSources: make(map[string][]string, len(routes)),
+		SymbolMarkets:     symbolMarkets,
+	}
+
+	for i := 0; i < len(routes); i++ {
+		routing.MarketDataSources[routes[i].Market] = routes[i].GetDataSources()
+	}
+
+	return routing, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// StockPriceHistoryService represents stock price history service
+type StockPriceHistoryService struct {
+	assetPriceHistoryStore
+}
+
+// Initialize a stock price history service singleton instance
+var (
+	StockPriceHistories = &StockPriceHistoryService{
+		assetPriceHistoryStore: assetPriceHistoryStore{
+			ServiceUsingDB: ServiceUsingDB{
+				container: datastore.Container,
+			},
+			assetType: models.ACCOUNT_ASSET_TYPE_STOCK,
+		},
+	}
+)
+
+// GetPriceHistories returns the daily stock prices of the given symbol between the start date and the end date (both inclusive, 0 means unlimited)
+func (s *StockPriceHistoryService) GetPriceHistories(c core.Context, symbol string, currency string, dataSource string, startDate int32, endDate int32) ([]*models.StockPriceHistory, error) {
+	histories, err := s.getPriceHistories(c, symbol, currency, dataSource, startDate, endDate)
+	return toStockPriceHistories(histories), err
+}
+
+// GetPriceHistoriesInDateRange returns the daily stock prices of the given symbol, currency and data source between the start date and the end date (both inclusive, 0 means unlimited),
+// the last price before the start date is also returned so that the price at the start date can be determined
+func (s *StockPriceHistoryService) GetPriceHistoriesInDateRange(c core.Context, symbol string, currency string, dataSource string, startDate int32, endDate int32) ([]*models.StockPriceHistory, error) {
+	histories, err := s.getPriceHistoriesInDateRange(c, symbol, currency, dataSource, startDate, endDate)
+	return toStockPriceHistories(histories), err
+}
+
+// GetLatestPriceHistory returns the latest saved daily stock price of the given symbol, returns nil if there is no price history
+func (s *StockPriceHistoryService) GetLatestPriceHistory(c core.Context, symbol string) (*models.StockPriceHistory, error) {
+	history, err := s.getLatestPriceHistory(c, symbol)
+	return (*models.StockPriceHistory)(history), err
+}
+
+// SaveLatestStockPrices saves the latest stock prices as the prices of the day when they were updated,
+// the data source of each price would be used if present, otherwise the given data source would be used
+func (s *StockPriceHistoryService) SaveLatestStockPrices(c core.Context, dataSource string, priceResponse *models.LatestStockPriceResponse) error {
+	if priceResponse == nil || len(priceResponse.Prices) < 1 {
+		return nil
+	}
+
+	histories := make([]*models.AssetPriceHistory, 0, len(priceResponse.Prices))
+
+	for i := 0; i < len(priceResponse.Prices); i++ {
+		price := priceResponse.Prices[i]
+
+		if price == nil || price.Symbol == "" || price.Price == "" {
+			continue
+		}
+
+		currency := price.Currency
+
+		if currency == "" {
+			currency = priceResponse.BaseCurrency
+		}
+
+		priceDataSource := price.DataSource
+
+		if priceDataSource == "" {
+			priceDataSource = dataSource
+		}
+
+		histories = append(histories, &models.AssetPriceHistory{
+			Symbol:     price.Symbol,
+			DataSource: priceDataSource,
+			Currency:   currency,
+			Price:      price.Price,
+		})
+	}
+
+	return s.saveLatestPrices(c, priceResponse.UpdateTime, histories)
+}
+
+func toStockPriceHistories(histories []*models.AssetPriceHistory) []*models.StockPriceHistory {
+	if histories == nil {
+		return nil
+	}
+
+	stockPriceHistories := make([]*models.StockPriceHistory, len(histories))
+
+	for i := 0; i < len(histories); i++ {
+		stockPriceHistories[i] = (*models.StockPriceHistory)(histories[i])
+	}
+
+	return stockPriceHistories
+}
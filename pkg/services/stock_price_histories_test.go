@@ -0,0 +1,134 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestStockPriceHistorySaveLatestStockPrices_UpsertSameDay(t *testing.T) {
+	initializeTestDataStore(t, new(models.StockPriceHistory))
+	c := core.NewNullContext()
+
+	err := StockPriceHistories.SaveLatestStockPrices(c, "alpha_vantage", &models.LatestStockPriceResponse{
+		BaseCurrency: "USD",
+		UpdateTime:   1704153600,
+		Prices: models.LatestStockPriceSlice{
+			{Symbol: "aapl", Price: "185.64"},
+			{Symbol: "0700.HK", Price: "285.2", Currency: "HKD", DataSource: "hkex"},
+		},
+	})
+	assert.Nil(t, err)
+
+	err = StockPriceHistories.SaveLatestStockPrices(c, "alpha_vantage", &models.LatestStockPriceResponse{
+		BaseCurrency: "USD",
+		UpdateTime:   1704196800,
+		Prices: models.LatestStockPriceSlice{
+			{Symbol: "AAPL", Price: "185.85"},
+		},
+	})
+	assert.Nil(t, err)
+
+	err = StockPriceHistories.SaveLatestStockPrices(c, "alpha_vantage", &models.LatestStockPriceResponse{
+		BaseCurrency: "USD",
+		UpdateTime:   1704240000,
+		Prices: models.LatestStockPriceSlice{
+			{Symbol: "AAPL", Price: "184.25"},
+		},
+	})
+	assert.Nil(t, err)
+
+	histories, err := StockPriceHistories.GetPriceHistories(c, "AAPL", "", "", 0, 0)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 2)
+	assert.Equal(t, int32(20240102), histories[0].PriceDate)
+	assert.Equal(t, "185.85", histories[0].Price)
+	assert.Equal(t, "alpha_vantage", histories[0].DataSource)
+	assert.Equal(t, "USD", histories[0].Currency)
+	assert.Equal(t, int32(20240103), histories[1].PriceDate)
+	assert.Equal(t, "184.25", histories[1].Price)
+
+	histories, err = StockPriceHistories.GetPriceHistories(c, "0700.hk", "hkd", "hkex", 0, 0)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 1)
+	assert.Equal(t, "285.2", histories[0].Price)
+}
+
+func TestStockPriceHistoryGetPriceHistoriesInDateRange(t *testing.T) {
+	initializeTestDataStore(t, new(models.StockPriceHistory))
+	c := core.NewNullContext()
+
+	for _, history := range []*models.StockPriceHistory{
+		{Symbol: "AAPL", DataSource: "alpha_vantage", Currency: "USD", PriceDate: 20240101, Price: "185"},
+		{Symbol: "AAPL", DataSource: "alpha_vantage", Currency: "USD", PriceDate: 20240103, Price: "184"},
+		{Symbol: "AAPL", DataSource: "alpha_vantage", Currency: "USD", PriceDate: 20240110, Price: "186"},
+		{Symbol: "AAPL", DataSource: "alpha_vantage", Currency: "USD", PriceDate: 20240120, Price: "191"},
+		{Symbol: "AAPL", DataSource: "alpha_vantage", Currency: "EUR", PriceDate: 20240105, Price: "169"},
+		{Symbol: "AAPL", DataSource: "yahoo_finance", Currency: "USD", PriceDate: 20240105, Price: "181"},
+		{Symbol: "MSFT", DataSource: "alpha_vantage", Currency: "USD", PriceDate: 20240125, Price: "404"},
+	} {
+		_, err := StockPriceHistories.UserDataDB(0).NewSession(c).Insert(history)
+		assert.Nil(t, err)
+	}
+
+	histories, err := StockPriceHistories.GetPriceHistoriesInDateRange(c, "aapl", "usd", "alpha_vantage", 20240105, 20240115)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 2)
+	assert.Equal(t, int32(20240103), histories[0].PriceDate)
+	assert.Equal(t, int32(20240110), histories[1].PriceDate)
+
+	histories, err = StockPriceHistories.GetPriceHistoriesInDateRange(c, "AAPL", "USD", "alpha_vantage", 20231201, 20231231)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 0)
+
+	histories, err = StockPriceHistories.GetPriceHistoriesInDateRange(c, "AAPL", "USD", "alpha_vantage", 20240121, 0)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 1)
+	assert.Equal(t, "191", histories[0].Price)
+
+	history, err := StockPriceHistories.GetLatestPriceHistory(c, "aapl")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(20240120), history.PriceDate)
+
+	history, err = StockPriceHistories.GetLatestPriceHistory(c, "GOOG")
+	assert.Nil(t, err)
+	assert.Nil(t, history)
+}
+
+func TestStockPriceHistoryGetPriceHistoriesOfSymbolsInDateRange(t *testing.T) {
+	initializeTestDataStore(t, new(models.StockPriceHistory))
+	c := core.NewNullContext()
+
+	for _, history := range []*models.StockPriceHistory{
+		{Symbol: "AAPL", DataSource: "alpha_vantage", Currency: "USD", PriceDate: 20231215, Price: "197"},
+		{Symbol: "AAPL", DataSource: "alpha_vantage", Currency: "USD", PriceDate: 20240103, Price: "184"},
+		{Symbol: "AAPL", DataSource: "alpha_vantage", Currency: "USD", PriceDate: 20240110, Price: "186"},
+		{Symbol: "AAPL", DataSource: "alpha_vantage", Currency: "USD", PriceDate: 20240120, Price: "191"},
+		{Symbol: "MSFT", DataSource: "alpha_vantage", Currency: "USD", PriceDate: 20231220, Price: "373"},
+		{Symbol: "MSFT", DataSource: "alpha_vantage", Currency: "USD", PriceDate: 20240108, Price: "388"},
+		{Symbol: "MSFT", DataSource: "yahoo_finance", Currency: "USD", PriceDate: 20240125, Price: "404"},
+	} {
+		_, err := StockPriceHistories.UserDataDB(0).NewSession(c).Insert(history)
+		assert.Nil(t, err)
+	}
+
+	symbolHistories, err := StockPriceHistories.GetPriceHistoriesOfSymbolsInDateRange(c, []string{"aapl", "MSFT", "GOOG"}, "usd", "alpha_vantage", 20240105, 20240115)
+	assert.Nil(t, err)
+	assert.Len(t, symbolHistories, 2)
+	assert.Len(t, symbolHistories["AAPL"], 2)
+	assert.Equal(t, int32(20240103), symbolHistories["AAPL"][0].PriceDate)
+	assert.Equal(t, int32(20240110), symbolHistories["AAPL"][1].PriceDate)
+	assert.Len(t, symbolHistories["MSFT"], 2)
+	assert.Equal(t, int32(20231220), symbolHistories["MSFT"][0].PriceDate)
+	assert.Equal(t, int32(20240108), symbolHistories["MSFT"][1].PriceDate)
+
+	latestHistories, err := StockPriceHistories.GetLatestPriceHistories(c, []string{"aapl", "MSFT", "GOOG"})
+	assert.Nil(t, err)
+	assert.Len(t, latestHistories, 2)
+	assert.Equal(t, int32(20240120), latestHistories["AAPL"].PriceDate)
+	assert.Equal(t, int32(20240125), latestHistories["MSFT"].PriceDate)
+	assert.Equal(t, "yahoo_finance", latestHistories["MSFT"].DataSource)
+}
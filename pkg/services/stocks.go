@@ -1,8 +1,11 @@
 package services
 
 import (
+	"strings"
 	"time"
 
+	"xorm.io/xorm"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -23,24 +26,113 @@ var (
 	}
 )
 
-// GetAllStocks returns all stock models
-func (s *StockService) GetAllStocks(c core.Context) ([]*models.Stock, error) {
+// GetAllStocks returns all stock models in the watchlist of user, or in the global default watchlist if uid is 0
+func (s *StockService) GetAllStocks(c core.Context, uid int64) ([]*models.Stock, error) {
+	if uid < 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
 	var stocks []*models.Stock
-	err := s.UserDataDB(0).NewSession(c).OrderBy("display_order asc").Find(&stocks)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&stocks)
 	return stocks, err
 }
 
-// GetAllVisibleStocks returns all visible stock models
-func (s *StockService) GetAllVisibleStocks(c core.Context) ([]*models.Stock, error) {
+// GetAllVisibleStocks returns all visible stock models in the watchlist of user, or in the global default watchlist if uid is 0
+func (s *StockService) GetAllVisibleStocks(c core.Context, uid int64) ([]*models.Stock, error) {
+	if uid < 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
 	var stocks []*models.Stock
-	err := s.UserDataDB(0).NewSession(c).Where("is_hidden=?", false).OrderBy("display_order asc").Find(&stocks)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND is_hidden=?", uid, false, false).OrderBy("display_order asc").Find(&stocks)
 	return stocks, err
 }
 
-// GetStockBySymbol returns stock model by symbol
-func (s *StockService) GetStockBySymbol(c core.Context, symbol string) (*models.Stock, error) {
+// GetAllWatchedStocks returns the stock models in the global default watchlist and the watchlists of all users, the stocks with the same symbol are only returned once
+func (s *StockService) GetAllWatchedStocks(c core.Context, visibleOnly bool) ([]*models.Stock, error) {
+	var allStocks []*models.Stock
+	addedSymbols := make(map[string]bool)
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var stocks []*models.Stock
+		sess := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=?", false)
+
+		if visibleOnly {
+			sess = sess.And("is_hidden=?", false)
+		}
+
+		err := sess.OrderBy("uid asc, display_order asc").Find(&stocks)
+		if err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < len(stocks); j++ {
+			symbol := strings.ToUpper(stocks[j].Symbol)
+
+			if addedSymbols[symbol] {
+				continue
+			}
+
+			addedSymbols[symbol] = true
+			allStocks = append(allStocks, stocks[j])
+		}
+	}
+
+	return allStocks, nil
+}
+
+// GetAllWatchedStockMarkets returns the markets of the stocks in the global default watchlist and the watchlists of all users grouped by the symbols in upper case,
+// the same symbol may be watched in different markets, and each market of a symbol is only returned once
+func (s *StockService) GetAllWatchedStockMarkets(c core.Context) (map[string][]string, error) {
+	symbolMarkets := make(map[string][]string)
+	addedSymbolMarkets := make(map[string]bool)
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var stocks []*models.Stock
+		err := s.UserDataDBByIndex(i).NewSession(c).Cols("symbol", "market").Where("deleted=?", false).OrderBy("uid asc, display_order asc").Find(&stocks)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < len(stocks); j++ {
+			symbol := strings.ToUpper(stocks[j].Symbol)
+			symbolMarket := symbol + "|" + stocks[j].Market
+
+			if addedSymbolMarkets[symbolMarket] {
+				continue
+			}
+
+			addedSymbolMarkets[symbolMarket] = true
+			symbolMarkets[symbol] = append(symbolMarkets[symbol], stocks[j].Market)
+		}
+	}
+
+	return symbolMarkets, nil
+}
+
+// IsSymbolWatched returns whether the stock symbol is in the global default watchlist or the watchlist of any user
+func (s *StockService) IsSymbolWatched(c core.Context, symbol string) (bool, error) {
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		exists, err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND symbol=?", false, symbol).Exist(&models.Stock{})
+		if err != nil {
+			return false, err
+		} else if exists {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetStockBySymbol returns stock model by symbol in the watchlist of user, or in the global default watchlist if uid is 0
+func (s *StockService) GetStockBySymbol(c core.Context, uid int64, symbol string) (*models.Stock, error) {
+	if uid < 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
 	stock := &models.Stock{}
-	has, err := s.UserDataDB(0).NewSession(c).Where("symbol=?", symbol).Get(stock)
+	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND symbol=?", uid, false, symbol).Get(stock)
 	if err != nil {
 		return nil, err
 	} else if !has {
@@ -49,22 +141,40 @@ func (s *StockService) GetStockBySymbol(c core.Context, symbol string) (*models.
 	return stock, nil
 }
 
-// CreateStock saves a new stock model to database
+// CreateStock saves a new stock model to the watchlist of user, or to the global default watchlist if uid is 0
 func (s *StockService) CreateStock(c core.Context, stock *models.Stock) error {
+	if stock.Uid < 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	now := time.Now().Unix()
+	stock.Deleted = false
 	stock.CreatedUnixTime = now
 	stock.UpdatedUnixTime = now
 
-	_, err := s.UserDataDB(0).NewSession(c).Insert(stock)
-	return err
+	return s.UserDataDB(stock.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Where("uid=? AND deleted=? AND symbol=?", stock.Uid, false, stock.Symbol).Exist(&models.Stock{})
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrStockAlreadyExists
+		}
+
+		_, err = sess.Insert(stock)
+		return err
+	})
 }
 
-// UpdateStock updates an existing stock model
+// UpdateStock updates an existing stock model in the watchlist of user, or in the global default watchlist if uid is 0
 func (s *StockService) UpdateStock(c core.Context, stock *models.Stock) error {
+	if stock.Uid < 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	now := time.Now().Unix()
 	stock.UpdatedUnixTime = now
 
-	updatedRows, err := s.UserDataDB(0).NewSession(c).Where("symbol=?", stock.Symbol).Update(stock)
+	updatedRows, err := s.UserDataDB(stock.Uid).NewSession(c).Cols("name", "market", "is_hidden", "display_order", "updated_unix_time").Where("uid=? AND deleted=? AND symbol=?", stock.Uid, false, stock.Symbol).Update(stock)
 	if err != nil {
 		return err
 	} else if updatedRows < 1 {
@@ -73,21 +183,40 @@ func (s *StockService) UpdateStock(c core.Context, stock *models.Stock) error {
 	return nil
 }
 
-// DeleteStock deletes a stock from database
-func (s *StockService) DeleteStock(c core.Context, symbol string) error {
-	_, err := s.UserDataDB(0).NewSession(c).Where("symbol=?", symbol).Delete(&models.Stock{})
-	return err
+// DeleteStock deletes a stock from the watchlist of user, or from the global default watchlist if uid is 0
+func (s *StockService) DeleteStock(c core.Context, uid int64, symbol string) error {
+	if uid < 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+	updateModel := &models.Stock{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	deletedRows, err := s.UserDataDB(uid).NewSession(c).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND symbol=?", uid, false, symbol).Update(updateModel)
+	if err != nil {
+		return err
+	} else if deletedRows < 1 {
+		return errs.ErrStockNotFound
+	}
+	return nil
 }
 
-// HideStock updates the hidden status of a stock
-func (s *StockService) HideStock(c core.Context, symbol string, hidden bool) error {
+// HideStock updates the hidden status of a stock in the watchlist of user, or in the global default watchlist if uid is 0
+func (s *StockService) HideStock(c core.Context, uid int64, symbol string, hidden bool) error {
+	if uid < 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	now := time.Now().Unix()
 	updateModel := &models.Stock{
 		IsHidden:        hidden,
 		UpdatedUnixTime: now,
 	}
-	
-	updatedRows, err := s.UserDataDB(0).NewSession(c).Where("symbol=?", symbol).Cols("is_hidden", "updated_unix_time").Update(updateModel)
+
+	updatedRows, err := s.UserDataDB(uid).NewSession(c).Cols("is_hidden", "updated_unix_time").Where("uid=? AND deleted=? AND symbol=?", uid, false, symbol).Update(updateModel)
 	if err != nil {
 		return err
 	} else if updatedRows < 1 {
@@ -95,3 +224,59 @@ func (s *StockService) HideStock(c core.Context, symbol string, hidden bool) err
 	}
 	return nil
 }
+
+// InitUserStocks copies the global default watchlist to the watchlist of the user, it should be called when the user is created or the database is updated,
+// and does nothing if the user has already had any stock in the watchlist
+func (s *StockService) InitUserStocks(c core.Context, uid int64) error {
+	if uid == 0 {
+		return nil
+	}
+
+	exists, err := s.UserDataDB(uid).NewSession(c).Where("uid=?", uid).Exist(&models.Stock{})
+
+	if err != nil {
+		return err
+	} else if exists {
+		return nil
+	}
+
+	var defaultStocks []*models.Stock
+	err = s.UserDataDB(0).NewSession(c).Where("uid=? AND deleted=?", 0, false).OrderBy("display_order asc").Find(&defaultStocks)
+
+	if err != nil {
+		return err
+	} else if len(defaultStocks) < 1 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Where("uid=?", uid).Exist(&models.Stock{})
+
+		if err != nil || exists {
+			return err
+		}
+
+		for i := 0; i < len(defaultStocks); i++ {
+			stock := &models.Stock{
+				Uid:             uid,
+				Symbol:          defaultStocks[i].Symbol,
+				Name:            defaultStocks[i].Name,
+				Market:          defaultStocks[i].Market,
+				IsHidden:        defaultStocks[i].IsHidden,
+				DisplayOrder:    defaultStocks[i].DisplayOrder,
+				CreatedUnixTime: now,
+				UpdatedUnixTime: now,
+			}
+
+			_, err = sess.Insert(stock)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TransactionReconciliationService represents transaction reconciliation service
+type TransactionReconciliationService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a transaction reconciliation service singleton instance
+var (
+	TransactionReconciliations = &TransactionReconciliationService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllReconciliationsByAccountId returns all transaction reconciliation models of the specified account
+func (s *TransactionReconciliationService) GetAllReconciliationsByAccountId(c core.Context, uid int64, accountId int64) ([]*models.TransactionReconciliation, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	var reconciliations []*models.TransactionReconciliation
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).OrderBy("statement_unix_time desc, reconciliation_id desc").Find(&reconciliations)
+
+	return reconciliations, err
+}
+
+// GetLatestReconciliationByAccountId returns the latest transaction reconciliation model of the specified account, or nil if the account has never been reconciled
+func (s *TransactionReconciliationService) GetLatestReconciliationByAccountId(c core.Context, uid int64, accountId int64) (*models.TransactionReconciliation, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	return s.getLatestReconciliation(s.UserDataDB(uid).NewSession(c), uid, accountId)
+}
+
+// GetReconciliationPreview returns the cleared balance and the count of cleared and uncleared transactions of the specified account before the statement time
+func (s *TransactionReconciliationService) GetReconciliationPreview(c core.Context, uid int64, accountId int64, statementUnixTime int64) (*models.TransactionReconciliationPreviewResponse, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(statementUnixTime)
+
+	var clearedTransactions []*models.Transaction
+	err := sess.Where("uid=? AND deleted=? AND account_id=? AND transaction_time<=? AND (reconciliation_status=? OR reconciliation_status=?)", uid, false, accountId, maxTransactionTime, models.TRANSACTION_RECONCILIATION_STATUS_CLEARED, models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED).Find(&clearedTransactions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	clearedCount := 0
+
+	for i := 0; i < len(clearedTransactions); i++ {
+		if clearedTransactions[i].ReconciliationStatus == models.TRANSACTION_RECONCILIATION_STATUS_CLEARED {
+			clearedCount++
+		}
+	}
+
+	unclearedCount, err := sess.Where("uid=? AND deleted=? AND account_id=? AND transaction_time<=? AND reconciliation_status=?", uid, false, accountId, maxTransactionTime, models.TRANSACTION_RECONCILIATION_STATUS_UNCLEARED).Count(&models.Transaction{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &models.TransactionReconciliationPreviewResponse{
+		AccountId:      accountId,
+		StatementTime:  statementUnixTime,
+		ClearedBalance: models.GetTransactionsAccountBalanceChange(clearedTransactions),
+		ClearedCount:   int32(clearedCount),
+		UnclearedCount: int32(unclearedCount),
+	}
+
+	lastReconciliation, err := s.getLatestReconciliation(sess, uid, accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if lastReconciliation != nil {
+		preview.LastReconciliation = lastReconciliation.ToTransactionReconciliationInfoResponse()
+	}
+
+	return preview, nil
+}
+
+// ModifyTransactionsReconciliationStatus marks the specified transactions of the account as cleared or uncleared,
+// the transaction ids are the ids shown in the reconciliation statement, so the transfer in transactions are matched by the related transfer out transaction ids
+func (s *TransactionReconciliationService) ModifyTransactionsReconciliationStatus(c core.Context, uid int64, accountId int64, transactionIds []int64, status models.TransactionReconciliationStatus) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return errs.ErrAccountIdInvalid
+	}
+
+	if len(transactionIds) < 1 {
+		return errs.ErrTransactionIdInvalid
+	}
+
+	if status != models.TRANSACTION_RECONCILIATION_STATUS_UNCLEARED && status != models.TRANSACTION_RECONCILIATION_STATUS_CLEARED {
+		return errs.ErrTransactionReconciliationStatusInvalid
+	}
+
+	cond := builder.Eq{"uid": uid}.
+		And(builder.Eq{"deleted": false}).
+		And(builder.Eq{"account_id": accountId}).
+		And(builder.Or(builder.In("transaction_id", transactionIds), builder.Eq{"type": models.TRANSACTION_DB_TYPE_TRANSFER_IN}.And(builder.In("related_id", transactionIds))))
+
+	updateModel := &models.Transaction{
+		ReconciliationStatus: status,
+		UpdatedUnixTime:      time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var transactions []*models.Transaction
+		err := sess.Cols("transaction_id", "uid", "deleted", "account_id", "type", "related_id", "reconciliation_status").Where(cond).Find(&transactions)
+
+		if err != nil {
+			return err
+		} else if len(transactions) != len(transactionIds) {
+			return errs.ErrTransactionNotFound
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			if transactions[i].ReconciliationStatus == models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED {
+				return errs.ErrReconciledTransactionStatusCannotBeModified
+			}
+		}
+
+		_, err = sess.Cols("reconciliation_status", "updated_unix_time").Where(cond).Update(updateModel)
+
+		return err
+	})
+}
+
+// CreateReconciliation reconciles all the cleared transactions of the account before the statement time,
+// the cleared balance must be equal to the statement balance, and the reconciled transactions cannot be modified or deleted until the reconciliation is deleted
+func (s *TransactionReconciliationService) CreateReconciliation(c core.Context, reconciliation *models.TransactionReconciliation) error {
+	if reconciliation.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if reconciliation.AccountId <= 0 {
+		return errs.ErrAccountIdInvalid
+	}
+
+	reconciliation.ReconciliationId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+
+	if reconciliation.ReconciliationId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	now := time.Now().Unix()
+
+	reconciliation.Deleted = false
+	reconciliation.CreatedUnixTime = now
+	reconciliation.UpdatedUnixTime = now
+
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(reconciliation.StatementUnixTime)
+
+	updateModel := &models.Transaction{
+		ReconciliationStatus: models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED,
+		ReconciliationId:     reconciliation.ReconciliationId,
+		UpdatedUnixTime:      now,
+	}
+
+	return s.UserDataDB(reconciliation.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		account := &models.Account{}
+		has, err := sess.ID(reconciliation.AccountId).Where("uid=? AND deleted=?", reconciliation.Uid, false).Get(account)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		}
+
+		if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+			return errs.ErrTransactionReconciliationAccountTypeInvalid
+		}
+
+		lastReconciliation, err := s.getLatestReconciliation(sess, reconciliation.Uid, reconciliation.AccountId)
+
+		if err != nil {
+			return err
+		}
+
+		if lastReconciliation != nil && reconciliation.StatementUnixTime < lastReconciliation.StatementUnixTime {
+			return errs.ErrTransactionReconciliationStatementTimeInvalid
+		}
+
+		var clearedTransactions []*models.Transaction
+		err = sess.Where("uid=? AND deleted=? AND account_id=? AND transaction_time<=? AND (reconciliation_status=? OR reconciliation_status=?)", reconciliation.Uid, false, reconciliation.AccountId, maxTransactionTime, models.TRANSACTION_RECONCILIATION_STATUS_CLEARED, models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED).Find(&clearedTransactions)
+
+		if err != nil {
+			return err
+		}
+
+		clearedBalance := models.GetTransactionsAccountBalanceChange(clearedTransactions)
+
+		if clearedBalance != reconciliation.StatementBalance {
+			log.Warnf(c, "[transaction_reconciliations.CreateReconciliation] cleared balance %d of account \"id:%d\" is not equal to statement balance %d for user \"uid:%d\"", clearedBalance, reconciliation.AccountId, reconciliation.StatementBalance, reconciliation.Uid)
+			return errs.ErrTransactionReconciliationStatementBalanceNotMatched
+		}
+
+		updatedRows, err := sess.Cols("reconciliation_status", "reconciliation_id", "updated_unix_time").Where("uid=? AND deleted=? AND account_id=? AND transaction_time<=? AND reconciliation_status=?", reconciliation.Uid, false, reconciliation.AccountId, maxTransactionTime, models.TRANSACTION_RECONCILIATION_STATUS_CLEARED).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		reconciliation.TransactionCount = int32(updatedRows)
+
+		_, err = sess.Insert(reconciliation)
+
+		return err
+	})
+}
+
+// DeleteReconciliation deletes the latest reconciliation of the account, and the transactions reconciled by it are reverted to cleared
+func (s *TransactionReconciliationService) DeleteReconciliation(c core.Context, uid int64, reconciliationId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if reconciliationId <= 0 {
+		return errs.ErrTransactionReconciliationIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionReconciliation{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	transactionUpdateModel := &models.Transaction{
+		ReconciliationStatus: models.TRANSACTION_RECONCILIATION_STATUS_CLEARED,
+		ReconciliationId:     0,
+		UpdatedUnixTime:      now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		reconciliation := &models.TransactionReconciliation{}
+		has, err := sess.ID(reconciliationId).Where("uid=? AND deleted=?", uid, false).Get(reconciliation)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionReconciliationNotFound
+		}
+
+		lastReconciliation, err := s.getLatestReconciliation(sess, uid, reconciliation.AccountId)
+
+		if err != nil {
+			return err
+		} else if lastReconciliation == nil || lastReconciliation.ReconciliationId != reconciliation.ReconciliationId {
+			return errs.ErrOnlyLatestTransactionReconciliationCanBeDeleted
+		}
+
+		deletedRows, err := sess.ID(reconciliationId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionReconciliationNotFound
+		}
+
+		_, err = sess.Cols("reconciliation_status", "reconciliation_id", "updated_unix_time").Where("uid=? AND deleted=? AND reconciliation_id=?", uid, false, reconciliationId).Update(transactionUpdateModel)
+
+		return err
+	})
+}
+
+// DeleteAllReconciliations deletes all existed transaction reconciliations from database
+func (s *TransactionReconciliationService) DeleteAllReconciliations(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionReconciliation{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+func (s *TransactionReconciliationService) getLatestReconciliation(sess *xorm.Session, uid int64, accountId int64) (*models.TransactionReconciliation, error) {
+	reconciliation := &models.TransactionReconciliation{}
+	has, err := sess.Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).OrderBy("statement_unix_time desc, created_unix_time desc").Limit(1).Get(reconciliation)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+
+	return reconciliation, nil
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TransactionRefundService represents transaction refund service
+type TransactionRefundService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a transaction refund service singleton instance
+var (
+	TransactionRefunds = &TransactionRefundService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllRefundsByTransactionId returns all transaction refund models which the specified transaction is the refunded expense or the refund income of
+func (s *TransactionRefundService) GetAllRefundsByTransactionId(c core.Context, uid int64, transactionId int64) ([]*models.TransactionRefund, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	var refunds []*models.TransactionRefund
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND (expense_transaction_id=? OR income_transaction_id=?)", uid, false, transactionId, transactionId).OrderBy("created_unix_time asc, refund_id asc").Find(&refunds)
+
+	return refunds, err
+}
+
+// GetOutstandingReimbursableTransactions returns all reimbursable expense transactions which have not been fully reimbursed and their reimbursed amounts
+func (s *TransactionRefundService) GetOutstandingReimbursableTransactions(c core.Context, uid int64) ([]*models.Transaction, map[int64]int64, error) {
+	if uid <= 0 {
+		return nil, nil, errs.ErrUserIdInvalid
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+
+	var reimbursableTransactions []*models.Transaction
+	err := sess.Where("uid=? AND deleted=? AND type=? AND reimbursable=?", uid, false, models.TRANSACTION_DB_TYPE_EXPENSE, true).OrderBy("transaction_time desc").Find(&reimbursableTransactions)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(reimbursableTransactions) < 1 {
+		return reimbursableTransactions, make(map[int64]int64), nil
+	}
+
+	var refunds []*models.TransactionRefund
+	err = sess.Where("uid=? AND deleted=?", uid, false).Find(&refunds)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reimbursedAmounts := models.GetTransactionRefundedAmounts(refunds)
+	outstandingTransactions := make([]*models.Transaction, 0, len(reimbursableTransactions))
+
+	for i := 0; i < len(reimbursableTransactions); i++ {
+		transaction := reimbursableTransactions[i]
+
+		if reimbursedAmounts[transaction.TransactionId] < transaction.Amount {
+			outstandingTransactions = append(outstandingTransactions, transaction)
+		}
+	}
+
+	return outstandingTransactions, reimbursedAmounts, nil
+}
+
+// CreateRefund saves a new transaction refund model to database,
+// the whole remaining amount of the expense transaction (but not more than the income amount) would be linked if the refund amount is zero
+func (s *TransactionRefundService) CreateRefund(c core.Context, refund *models.TransactionRefund) error {
+	if refund.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if refund.ExpenseTransactionId <= 0 || refund.IncomeTransactionId <= 0 {
+		return errs.ErrTransactionIdInvalid
+	}
+
+	if refund.Amount < 0 {
+		return errs.ErrTransactionRefundAmountInvalid
+	}
+
+	refund.RefundId = s.GenerateUuid(uuid.UUID_TYPE_TRANSACTION_REFUND)
+
+	if refund.RefundId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	refund.Deleted = false
+	refund.CreatedUnixTime = time.Now().Unix()
+	refund.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(refund.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		expenseTransaction := &models.Transaction{}
+		has, err := sess.ID(refund.ExpenseTransactionId).Where("uid=? AND deleted=?", refund.Uid, false).Get(expenseTransaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
+		} else if expenseTransaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+			return errs.ErrRefundedTransactionTypeInvalid
+		}
+
+		incomeTransaction := &models.Transaction{}
+		has, err = sess.ID(refund.IncomeTransactionId).Where("uid=? AND deleted=?", refund.Uid, false).Get(incomeTransaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
+		} else if incomeTransaction.Type != models.TRANSACTION_DB_TYPE_INCOME {
+			return errs.ErrRefundTransactionTypeInvalid
+		}
+
+		if incomeTransaction.TransactionTime < expenseTransaction.TransactionTime {
+			return errs.ErrRefundTransactionEarlierThanRefundedTransaction
+		}
+
+		expenseAccount := &models.Account{}
+		has, err = sess.ID(expenseTransaction.AccountId).Where("uid=?", refund.Uid).Get(expenseAccount)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		}
+
+		incomeAccount := &models.Account{}
+		has, err = sess.ID(incomeTransaction.AccountId).Where("uid=?", refund.Uid).Get(incomeAccount)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		}
+
+		if expenseAccount.Currency != incomeAccount.Currency {
+			return errs.ErrTransactionRefundCurrencyNotMatched
+		}
+
+		exists, err := sess.Where("uid=? AND deleted=? AND income_transaction_id=?", refund.Uid, false, refund.IncomeTransactionId).Exist(&models.TransactionRefund{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrRefundTransactionAlreadyLinked
+		}
+
+		var existedRefunds []*models.TransactionRefund
+		err = sess.Where("uid=? AND deleted=? AND expense_transaction_id=?", refund.Uid, false, refund.ExpenseTransactionId).Find(&existedRefunds)
+
+		if err != nil {
+			return err
+		}
+
+		remainingAmount := expenseTransaction.Amount - models.GetTransactionRefundedAmounts(existedRefunds)[refund.ExpenseTransactionId]
+
+		if refund.Amount == 0 {
+			refund.Amount = remainingAmount
+
+			if refund.Amount > incomeTransaction.Amount {
+				refund.Amount = incomeTransaction.Amount
+			}
+		}
+
+		if refund.Amount <= 0 {
+			return errs.ErrTransactionRefundAmountInvalid
+		} else if refund.Amount > remainingAmount {
+			return errs.ErrTransactionRefundAmountExceedsExpenseAmount
+		} else if refund.Amount > incomeTransaction.Amount {
+			return errs.ErrTransactionRefundAmountExceedsIncomeAmount
+		}
+
+		_, err = sess.Insert(refund)
+
+		return err
+	})
+}
+
+// DeleteRefund deletes an existed transaction refund from database
+func (s *TransactionRefundService) DeleteRefund(c core.Context, uid int64, refundId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if refundId <= 0 {
+		return errs.ErrTransactionRefundIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionRefund{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(refundId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionRefundNotFound
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func initializeTransactionRefundTestData(t *testing.T) {
+	initializeTestDataStore(t, new(models.Account), new(models.Transaction), new(models.TransactionSplit), new(models.TransactionRefund))
+
+	c := core.NewNullContext()
+	err := TransactionRefunds.UserDataDB(1).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(&models.Account{AccountId: 1001, Uid: 1, Name: "Credit Card", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(&models.Account{AccountId: 1002, Uid: 1, Name: "Checking Account", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(&models.Transaction{TransactionId: 2001, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1001, TransactionTime: 1000, Amount: 3000})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(&models.Transaction{TransactionId: 2002, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 201, AccountId: 1002, TransactionTime: 2000, Amount: 1000})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(&models.Transaction{TransactionId: 2003, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 201, AccountId: 1002, TransactionTime: 3000, Amount: 5000})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(&models.TransactionSplit{SplitId: 3001, Uid: 1, TransactionId: 2001, TransactionTime: 1000, CategoryId: 101, Amount: 2000, DisplayOrder: 1})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(&models.TransactionSplit{SplitId: 3002, Uid: 1, TransactionId: 2001, TransactionTime: 1000, CategoryId: 102, Amount: 1000, DisplayOrder: 2})
+
+		return err
+	})
+	assert.Nil(t, err)
+}
+
+func TestCreateRefund_WholeRemainingAmount(t *testing.T) {
+	initializeTransactionRefundTestData(t)
+
+	c := core.NewNullContext()
+	refund := &models.TransactionRefund{Uid: 1, ExpenseTransactionId: 2001, IncomeTransactionId: 2002}
+	err := TransactionRefunds.CreateRefund(c, refund)
+	assert.Nil(t, err)
+	assert.NotEqual(t, int64(0), refund.RefundId)
+	assert.Equal(t, int64(1000), refund.Amount)
+
+	refunds, err := TransactionRefunds.GetAllRefundsByTransactionId(c, 1, 2001)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(refunds))
+	assert.Equal(t, refund.RefundId, refunds[0].RefundId)
+	assert.Equal(t, int64(2002), refunds[0].IncomeTransactionId)
+	assert.Equal(t, int64(1000), refunds[0].Amount)
+}
+
+func TestCreateRefund_IncomeTransactionAlreadyLinked(t *testing.T) {
+	initializeTransactionRefundTestData(t)
+
+	c := core.NewNullContext()
+	err := TransactionRefunds.CreateRefund(c, &models.TransactionRefund{Uid: 1, ExpenseTransactionId: 2001, IncomeTransactionId: 2002, Amount: 500})
+	assert.Nil(t, err)
+
+	err = TransactionRefunds.CreateRefund(c, &models.TransactionRefund{Uid: 1, ExpenseTransactionId: 2001, IncomeTransactionId: 2002, Amount: 500})
+	assert.Equal(t, errs.ErrRefundTransactionAlreadyLinked, err)
+}
+
+func TestCreateRefund_AmountExceedsExpenseAmount(t *testing.T) {
+	initializeTransactionRefundTestData(t)
+
+	c := core.NewNullContext()
+	err := TransactionRefunds.CreateRefund(c, &models.TransactionRefund{Uid: 1, ExpenseTransactionId: 2001, IncomeTransactionId: 2003, Amount: 4000})
+	assert.Equal(t, errs.ErrTransactionRefundAmountExceedsExpenseAmount, err)
+}
+
+func TestCreateRefund_AmountExceedsIncomeAmount(t *testing.T) {
+	initializeTransactionRefundTestData(t)
+
+	c := core.NewNullContext()
+	err := TransactionRefunds.CreateRefund(c, &models.TransactionRefund{Uid: 1, ExpenseTransactionId: 2001, IncomeTransactionId: 2002, Amount: 2000})
+	assert.Equal(t, errs.ErrTransactionRefundAmountExceedsIncomeAmount, err)
+}
+
+func TestCreateRefund_RefundTransactionTypeInvalid(t *testing.T) {
+	initializeTransactionRefundTestData(t)
+
+	c := core.NewNullContext()
+	err := TransactionRefunds.CreateRefund(c, &models.TransactionRefund{Uid: 1, ExpenseTransactionId: 2002, IncomeTransactionId: 2003})
+	assert.Equal(t, errs.ErrRefundedTransactionTypeInvalid, err)
+}
+
+func TestNetRefundTransactions_SplitRefundedTransaction(t *testing.T) {
+	initializeTransactionRefundTestData(t)
+
+	c := core.NewNullContext()
+	err := TransactionRefunds.CreateRefund(c, &models.TransactionRefund{Uid: 1, ExpenseTransactionId: 2001, IncomeTransactionId: 2003, Amount: 1500})
+	assert.Nil(t, err)
+
+	transactions := []*models.Transaction{
+		{TransactionId: 2002, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 201, AccountId: 1002, Amount: 1000},
+		{TransactionId: 2003, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 201, AccountId: 1002, Amount: 5000},
+	}
+
+	actualValue, err := Transactions.netRefundTransactions(c, 1, transactions)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(actualValue))
+
+	assert.Equal(t, int64(2002), actualValue[0].TransactionId)
+	assert.Equal(t, int64(1000), actualValue[0].Amount)
+
+	assert.Equal(t, int64(2003), actualValue[1].TransactionId)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, actualValue[1].Type)
+	assert.Equal(t, int64(3500), actualValue[1].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, actualValue[2].Type)
+	assert.Equal(t, int64(101), actualValue[2].CategoryId)
+	assert.Equal(t, int64(-1000), actualValue[2].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, actualValue[3].Type)
+	assert.Equal(t, int64(102), actualValue[3].CategoryId)
+	assert.Equal(t, int64(-500), actualValue[3].Amount)
+}
@@ -396,6 +396,14 @@ func (s *TransactionTagService) DeleteTag(c core.Context, uid int64, tagId int64
 			return errs.ErrTransactionTagInUseCannotBeDeleted
 		}
 
+		exists, err = sess.Cols("uid", "tag_id").Where("uid=? AND deleted=? AND tag_id=?", uid, false, tagId).Limit(1).Exist(&models.TransactionSplitTagIndex{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrTransactionTagInUseCannotBeDeleted
+		}
+
 		var relatedTransactionTemplatesByTag []*models.TransactionTemplate
 		err = sess.Cols("uid", "deleted", "tag_ids", "template_type", "scheduled_frequency_type", "scheduled_end_time").Where("uid=? AND deleted=? AND (template_type=? OR (template_type=? AND scheduled_frequency_type<>? AND (scheduled_end_time IS NULL OR scheduled_end_time>=?))) AND tag_ids LIKE ?", uid, false, models.TRANSACTION_TEMPLATE_TYPE_NORMAL, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED, now, "%%"+utils.Int64ToString(tagId)+"%%").Find(&relatedTransactionTemplatesByTag)
 
@@ -452,6 +460,14 @@ func (s *TransactionTagService) DeleteAllTags(c core.Context, uid int64) error {
 			return errs.ErrTransactionTagInUseCannotBeDeleted
 		}
 
+		exists, err = sess.Cols("uid", "deleted").Where("uid=? AND deleted=?", uid, false).Limit(1).Exist(&models.TransactionSplitTagIndex{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrTransactionTagInUseCannotBeDeleted
+		}
+
 		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
 
 		if err != nil {
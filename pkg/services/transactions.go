@@ -3,6 +3,7 @@ package services
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,6 +20,8 @@ import (
 )
 
 const pageCountForLoadTransactionAmounts = 1000
+const pageCountForLoadTransactionRefunds = 500
+const pageCountForCheckReconciledTransactions = 500
 
 // TransactionService represents transaction service
 type TransactionService struct {
@@ -464,7 +467,7 @@ func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxT
 }
 
 // CreateTransaction saves a new transaction to database
-func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64) error {
+func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64, splits []*models.TransactionSplit) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -528,15 +531,96 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 		UpdatedUnixTime: now,
 	}
 
+	err = s.prepareTransactionSplits(transaction, splits, now)
+
+	if err != nil {
+		return err
+	}
+
 	userDataDb := s.UserDataDB(transaction.Uid)
 
 	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
-		return s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
+		return s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel, splits)
+	})
+}
+
+// CreateWalletBalanceAdjustmentTransaction saves a new income or expense transaction with the wallet balance adjustment category of the account to database,
+// the amount of which is the difference between the specified balance and the current account balance
+func (s *TransactionService) CreateWalletBalanceAdjustmentTransaction(c core.Context, account *models.Account, balance int64, unixTime int64) (*models.Transaction, error) {
+	if account.Uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if account.AccountId <= 0 {
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	transactionId := s.GenerateUuid(uuid.UUID_TYPE_TRANSACTION)
+
+	if transactionId < 1 {
+		return nil, errs.ErrSystemIsBusy
+	}
+
+	now := time.Now().Unix()
+
+	transaction := &models.Transaction{
+		TransactionId:     transactionId,
+		Uid:               account.Uid,
+		Deleted:           false,
+		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(unixTime),
+		TimezoneUtcOffset: utils.GetServerTimezoneOffsetMinutes(),
+		AccountId:         account.AccountId,
+		CreatedIp:         "127.0.0.1",
+		CreatedUnixTime:   now,
+		UpdatedUnixTime:   now,
+	}
+
+	userDataDb := s.UserDataDB(transaction.Uid)
+
+	err := userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
+		currentAccount := &models.Account{}
+		has, err := sess.ID(account.AccountId).Where("uid=? AND deleted=?", account.Uid, false).Get(currentAccount)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrSourceAccountNotFound
+		}
+
+		if currentAccount.Extend == nil {
+			return errs.ErrWalletBalanceCategoryRequired
+		}
+
+		difference := balance - currentAccount.Balance
+
+		if difference == 0 {
+			return errs.ErrNothingWillBeUpdated
+		} else if difference > 0 {
+			transaction.Type = models.TRANSACTION_DB_TYPE_INCOME
+			transaction.CategoryId = currentAccount.Extend.WalletIncomeCategoryId
+			transaction.Amount = difference
+		} else {
+			transaction.Type = models.TRANSACTION_DB_TYPE_EXPENSE
+			transaction.CategoryId = currentAccount.Extend.WalletExpenseCategoryId
+			transaction.Amount = -difference
+		}
+
+		if transaction.CategoryId <= 0 {
+			return errs.ErrWalletBalanceCategoryRequired
+		}
+
+		return s.doCreateTransaction(c, userDataDb, sess, transaction, nil, nil, nil, nil, nil)
 	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
 }
 
 // BatchCreateTransactions saves new transactions to database
-func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64, processHandler core.TaskProcessUpdateHandler) error {
+func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64, allSplits map[int][]*models.TransactionSplit, processHandler core.TaskProcessUpdateHandler) error {
 	now := time.Now().Unix()
 	currentProcess := float64(0)
 	processUpdateStep := int(math.Max(100.0, float64(len(transactions)/100.0)))
@@ -579,6 +663,12 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 		needTagIndexUuidCount += uint16(len(uniqueTagIds))
 	}
 
+	for index := range allSplits {
+		if index < 0 || index >= len(transactions) {
+			return errs.ErrOperationFailed
+		}
+	}
+
 	if needTransactionUuidCount > uint16(65535) || needTagIndexUuidCount > uint16(65535) {
 		return errs.ErrImportTooManyTransaction
 	}
@@ -600,6 +690,12 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 			transaction.RelatedId = transactionUuids[transactionUuidIndex]
 			transactionUuidIndex++
 		}
+
+		err := s.prepareTransactionSplits(transaction, allSplits[i], now)
+
+		if err != nil {
+			return err
+		}
 	}
 
 	tagIndexUuids := s.GenerateUuids(uuid.UUID_TYPE_TAG_INDEX, needTagIndexUuidCount)
@@ -643,7 +739,7 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 			transaction := transactions[i]
 			transactionTagIndexes := allTransactionTagIndexes[transaction.TransactionId]
 			transactionTagIds := allTransactionTagIds[transaction.TransactionId]
-			err := s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, transactionTagIds, nil, nil)
+			err := s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, transactionTagIds, nil, nil, allSplits[i])
 
 			currentProcess = float64(i) / float64(len(transactions)) * 100
 
@@ -788,7 +884,7 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 		}
 
 		tagIds := template.GetTagIds()
-		err = s.CreateTransaction(c, transaction, tagIds, nil)
+		err = s.CreateTransaction(c, transaction, tagIds, nil, nil)
 
 		if err == nil {
 			successCount++
@@ -805,7 +901,7 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 }
 
 // ModifyTransaction saves an existed transaction to database
-func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64) error {
+func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64, splits []*models.TransactionSplit) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -842,7 +938,13 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		}
 	}
 
-	err := s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+	err := s.prepareTransactionSplits(transaction, splits, now)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
 		has, err := sess.ID(transaction.TransactionId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(oldTransaction)
@@ -854,8 +956,40 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			return errs.ErrTransactionNotFound
 		}
 
+		reconciled, err := s.isTransactionReconciled(sess, oldTransaction)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to get reconciliation status of current transaction, because %s", err.Error())
+			return err
+		} else if reconciled {
+			return errs.ErrCannotModifyReconciledTransaction
+		}
+
 		transaction.Type = oldTransaction.Type
 
+		if transaction.Reimbursable && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+			return errs.ErrReimbursableTransactionTypeInvalid
+		}
+
+		oldSplitsCount, err := sess.Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Count(&models.TransactionSplit{})
+
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to get current transaction splits count, because %s", err.Error())
+			return err
+		}
+
+		// Keep current splits when splits are not specified, but the amount of split transaction cannot be modified without new splits,
+		// and the split transaction which is moved to another category is no longer split
+		if splits == nil && oldSplitsCount > 0 {
+			if transaction.Amount != oldTransaction.Amount {
+				return errs.ErrTransactionSplitsAmountNotEqualToTransactionAmount
+			}
+
+			if transaction.CategoryId != oldTransaction.CategoryId {
+				splits = make([]*models.TransactionSplit, 0)
+			}
+		}
+
 		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
 			transaction.RelatedId = oldTransaction.RelatedId
 		}
@@ -987,10 +1121,25 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			}
 		}
 
+		if transaction.HoldingAccountId != oldTransaction.HoldingAccountId {
+			// Get and verify holding account
+			err = s.isHoldingAccountValid(sess, transaction)
+
+			if err != nil {
+				return err
+			}
+
+			updateCols = append(updateCols, "holding_account_id")
+		}
+
 		if transaction.HideAmount != oldTransaction.HideAmount {
 			updateCols = append(updateCols, "hide_amount")
 		}
 
+		if transaction.Reimbursable != oldTransaction.Reimbursable {
+			updateCols = append(updateCols, "reimbursable")
+		}
+
 		if transaction.Comment != oldTransaction.Comment {
 			updateCols = append(updateCols, "comment")
 		}
@@ -1017,6 +1166,27 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			return err
 		}
 
+		// Get and verify splits
+		err = s.isSplitsValid(sess, transaction, splits)
+
+		if err != nil {
+			return err
+		}
+
+		// Verify linked refunds
+		err = s.isRefundsValid(sess, transaction, oldTransaction, sourceAccount)
+
+		if err != nil {
+			return err
+		}
+
+		// Verify linked investment trades
+		err = s.isInvestmentTradesValid(sess, transaction, oldTransaction)
+
+		if err != nil {
+			return err
+		}
+
 		// Not allow to add transaction before balance modification transaction
 		if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			otherTransactionExists := false
@@ -1106,6 +1276,76 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			}
 		}
 
+		// Update transaction splits
+		if splits != nil && oldSplitsCount > 0 {
+			splitUpdateModel := &models.TransactionSplit{
+				Deleted:         true,
+				DeletedUnixTime: now,
+			}
+
+			_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(splitUpdateModel)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to remove old transaction splits, because %s", err.Error())
+				return err
+			}
+
+			splitTagIndexUpdateModel := &models.TransactionSplitTagIndex{
+				Deleted:         true,
+				DeletedUnixTime: now,
+			}
+
+			_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(splitTagIndexUpdateModel)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to remove old transaction split tag index, because %s", err.Error())
+				return err
+			}
+		}
+
+		if len(splits) > 0 {
+			for i := 0; i < len(splits); i++ {
+				split := splits[i]
+				split.TransactionTime = transaction.TransactionTime
+
+				_, err := sess.Insert(split)
+
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to add new transaction split, because %s", err.Error())
+					return err
+				}
+			}
+
+			err = s.createTransactionSplitTagIndexes(sess, transaction, splits, now)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to add new transaction split tag index, because %s", err.Error())
+				return err
+			}
+		} else if splits == nil && oldSplitsCount > 0 && modifyTransactionTime {
+			splitUpdateModel := &models.TransactionSplit{
+				TransactionTime: transaction.TransactionTime,
+			}
+
+			_, err := sess.Cols("transaction_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(splitUpdateModel)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction splits, because %s", err.Error())
+				return err
+			}
+
+			splitTagIndexUpdateModel := &models.TransactionSplitTagIndex{
+				TransactionTime: transaction.TransactionTime,
+			}
+
+			_, err = sess.Cols("transaction_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(splitTagIndexUpdateModel)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction split tag index, because %s", err.Error())
+				return err
+			}
+		}
+
 		// Update transaction picture
 		if len(removePictureIds) > 0 {
 			pictureUpdateModel := &models.TransactionPictureInfo{
@@ -1354,6 +1594,15 @@ func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context,
 			return errs.ErrCannotMoveTransactionBetweenAccountsWithDifferentCurrencies
 		}
 
+		// reconciled transactions are locked in their account
+		reconciledTransactionExists, err := sess.Cols("uid", "deleted", "account_id", "reconciliation_status").Where("uid=? AND deleted=? AND account_id=? AND reconciliation_status=?", uid, false, fromAccountId, models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			return err
+		} else if reconciledTransactionExists {
+			return errs.ErrCannotMoveReconciledTransaction
+		}
+
 		// combine balance modification transaction
 		var balanceModificationTransactions []*models.Transaction
 		err = sess.Where("uid=? AND deleted=? AND type=? AND (account_id=? OR account_id=?)", uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, fromAccountId, toAccountId).Find(&balanceModificationTransactions)
@@ -1545,6 +1794,26 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 		DeletedUnixTime: now,
 	}
 
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	splitTagIndexUpdateModel := &models.TransactionSplitTagIndex{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	refundUpdateModel := &models.TransactionRefund{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	investmentTradeUpdateModel := &models.InvestmentTrade{
+		TransactionId:   0,
+		UpdatedUnixTime: now,
+	}
+
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
@@ -1556,6 +1825,14 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 			return errs.ErrTransactionNotFound
 		}
 
+		reconciled, err := s.isTransactionReconciled(sess, oldTransaction)
+
+		if err != nil {
+			return err
+		} else if reconciled {
+			return errs.ErrCannotDeleteReconciledTransaction
+		}
+
 		// Get and verify source and destination account
 		sourceAccount, destinationAccount, err := s.getAccountModels(sess, oldTransaction)
 
@@ -1604,6 +1881,40 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 			return err
 		}
 
+		// Update transaction splits
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update transaction split tag index
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(splitTagIndexUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update linked transaction refunds
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND (expense_transaction_id=? OR income_transaction_id=?)", uid, false, oldTransaction.TransactionId, oldTransaction.TransactionId).Update(refundUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Unlink investment trades from the deleted transaction
+		linkedTransactionIds := []int64{oldTransaction.TransactionId}
+
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			linkedTransactionIds = append(linkedTransactionIds, oldTransaction.RelatedId)
+		}
+
+		_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", linkedTransactionIds).Update(investmentTradeUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
 		// Update account table
 		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			if oldTransaction.RelatedAccountAmount != 0 {
@@ -1696,6 +2007,26 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 		DeletedUnixTime: now,
 	}
 
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	splitTagIndexUpdateModel := &models.TransactionSplitTagIndex{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	refundUpdateModel := &models.TransactionRefund{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	investmentTradeUpdateModel := &models.InvestmentTrade{
+		TransactionId:   0,
+		UpdatedUnixTime: now,
+	}
+
 	accountUpdateModel := &models.Account{
 		Balance:         0,
 		Deleted:         deleteAccount,
@@ -1724,6 +2055,34 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 			return err
 		}
 
+		// Update all transaction splits to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update all transaction split tag index to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(splitTagIndexUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update all transaction refunds to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(refundUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Unlink all investment trades from transactions
+		_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id<>?", uid, false, 0).Update(investmentTradeUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
 		// Update all accounts to deleted or set amount to zero
 		_, err = sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(accountUpdateModel)
 
@@ -1735,7 +2094,8 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 	})
 }
 
-// DeleteAllTransactionsOfAccount deletes all existed transactions of specific account from database
+// DeleteAllTransactionsOfAccount deletes all existed transactions of specific account from database,
+// nothing would be deleted if any transaction of the account (or the related transfer transaction in other account) is reconciled
 func (s *TransactionService) DeleteAllTransactionsOfAccount(c core.Context, uid int64, accountId int64, pageCount int32) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
@@ -1755,6 +2115,14 @@ func (s *TransactionService) DeleteAllTransactionsOfAccount(c core.Context, uid
 		return nil
 	}
 
+	reconciled, err := s.hasReconciledTransactions(c, uid, transactions)
+
+	if err != nil {
+		return err
+	} else if reconciled {
+		return errs.ErrCannotDeleteReconciledTransaction
+	}
+
 	for i := 0; i < len(transactions); i++ {
 		transaction := transactions[i]
 
@@ -1936,16 +2304,40 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 	return incomeAmounts, expenseAmounts, nil
 }
 
-// GetAccountsAndCategoriesTotalInflowAndOutflow returns the every accounts and categories total inflows and outflows amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool) ([]*models.Transaction, error) {
+// GetAccountsAndCategoriesTotalInflowAndOutflow returns the every accounts and categories total inflows and outflows amount by specific date range,
+// the amount of each transaction would also be converted by the amount converter at the transaction date if the converter is not nil
+func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool, amountConverter models.TransactionAmountConverter) ([]*models.TransactionWithConvertedAmount, error) {
+	periodTotalAmounts, err := s.GetAccountsAndCategoriesPeriodicTotalInflowAndOutflow(c, uid, []int64{startUnixTime}, endUnixTime, tagFilters, noTags, keyword, clientTimezone, useTransactionTimezone, amountConverter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return periodTotalAmounts[0], nil
+}
+
+// GetAccountsAndCategoriesPeriodicTotalInflowAndOutflow returns the every accounts and categories total inflows and outflows amount of each period,
+// the periods are specified by the ascending start unix times and the end unix time of the last period, and the transactions of all periods are loaded only once,
+// the amount of each transaction would also be converted by the amount converter at the transaction date if the converter is not nil
+func (s *TransactionService) GetAccountsAndCategoriesPeriodicTotalInflowAndOutflow(c core.Context, uid int64, periodStartUnixTimes []int64, endUnixTime int64, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool, amountConverter models.TransactionAmountConverter) ([][]*models.TransactionWithConvertedAmount, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
-	var startLocalDateTime, endLocalDateTime, startTransactionTime, endTransactionTime int64
+	if len(periodStartUnixTimes) < 1 {
+		return nil, errs.ErrOperationFailed
+	}
+
+	var endLocalDateTime, startTransactionTime, endTransactionTime int64
+	periodStartLocalDateTimes := make([]int64, len(periodStartUnixTimes))
 
-	if startUnixTime > 0 {
-		startLocalDateTime = utils.FormatUnixTimeToNumericLocalDateTime(startUnixTime, clientTimezone)
+	for i := 0; i < len(periodStartUnixTimes); i++ {
+		if periodStartUnixTimes[i] > 0 {
+			periodStartLocalDateTimes[i] = utils.FormatUnixTimeToNumericLocalDateTime(periodStartUnixTimes[i], clientTimezone)
+		}
+	}
+
+	if startUnixTime := periodStartUnixTimes[0]; startUnixTime > 0 {
 		startUnixTime = utils.GetMinUnixTimeWithSameLocalDateTime(startUnixTime, utils.GetTimezoneOffsetMinutes(startUnixTime, clientTimezone))
 		startTransactionTime = utils.GetMinTransactionTimeFromUnixTime(startUnixTime)
 	}
@@ -1991,8 +2383,8 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			finalConditionParams = append(finalConditionParams, "%%"+keyword+"%%")
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
-		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess = s.appendFilterTagIdsConditionToStatisticsQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
 
@@ -2010,19 +2402,44 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
 	}
 
-	transactionTotalAmountsMap := make(map[string]*models.Transaction)
+	allTransactions, err := s.expandSplitTransactions(c, uid, allTransactions, startTransactionTime, endTransactionTime, tagFilters)
 
-	for i := 0; i < len(allTransactions); i++ {
-		transaction := allTransactions[i]
-		timeZone := clientTimezone
+	if err != nil {
+		return nil, err
+	}
 
-		if useTransactionTimezone {
-			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
-		}
+	allTransactions, err = s.netRefundTransactions(c, uid, allTransactions)
 
-		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	periodTransactionTotalAmountsMaps := make([]map[string]*models.TransactionWithConvertedAmount, len(periodStartUnixTimes))
+
+	for i := 0; i < len(periodTransactionTotalAmountsMaps); i++ {
+		periodTransactionTotalAmountsMaps[i] = make(map[string]*models.TransactionWithConvertedAmount)
+	}
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		timeZone := clientTimezone
+
+		if useTransactionTimezone {
+			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		}
+
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(transactionUnixTime, timeZone)
+
+		if endLocalDateTime > 0 && localDateTime > endLocalDateTime {
+			continue
+		}
 
-		if (startLocalDateTime > 0 && localDateTime < startLocalDateTime) || (endLocalDateTime > 0 && localDateTime > endLocalDateTime) {
+		periodIndex := sort.Search(len(periodStartLocalDateTimes), func(j int) bool {
+			return periodStartLocalDateTimes[j] > localDateTime
+		}) - 1
+
+		if periodIndex < 0 {
 			continue
 		}
 
@@ -2032,34 +2449,46 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			groupKey = fmt.Sprintf("%d_%d_%d_%d", transaction.CategoryId, transaction.AccountId, transaction.RelatedAccountId, transaction.Type)
 		}
 
+		transactionTotalAmountsMap := periodTransactionTotalAmountsMaps[periodIndex]
 		totalAmounts, exists := transactionTotalAmountsMap[groupKey]
 
 		if !exists {
-			totalAmounts = &models.Transaction{
-				Type:             transaction.Type,
-				CategoryId:       transaction.CategoryId,
-				AccountId:        transaction.AccountId,
-				RelatedAccountId: transaction.RelatedAccountId,
-				Amount:           0,
+			totalAmounts = &models.TransactionWithConvertedAmount{
+				Transaction: &models.Transaction{
+					Type:             transaction.Type,
+					CategoryId:       transaction.CategoryId,
+					AccountId:        transaction.AccountId,
+					RelatedAccountId: transaction.RelatedAccountId,
+					Amount:           0,
+				},
+				HasConvertedAmount: amountConverter != nil,
 			}
 
 			transactionTotalAmountsMap[groupKey] = totalAmounts
 		}
 
 		totalAmounts.Amount += transaction.Amount
+		s.addConvertedAmount(totalAmounts, transaction, utils.FormatUnixTimeToNumericYearMonthDay(transactionUnixTime, timeZone), amountConverter)
 	}
 
-	transactionTotalAmounts := make([]*models.Transaction, 0, len(transactionTotalAmountsMap))
+	periodTransactionTotalAmounts := make([][]*models.TransactionWithConvertedAmount, len(periodTransactionTotalAmountsMaps))
+
+	for i := 0; i < len(periodTransactionTotalAmountsMaps); i++ {
+		transactionTotalAmounts := make([]*models.TransactionWithConvertedAmount, 0, len(periodTransactionTotalAmountsMaps[i]))
+
+		for _, totalAmounts := range periodTransactionTotalAmountsMaps[i] {
+			transactionTotalAmounts = append(transactionTotalAmounts, totalAmounts)
+		}
 
-	for _, totalAmounts := range transactionTotalAmountsMap {
-		transactionTotalAmounts = append(transactionTotalAmounts, totalAmounts)
+		periodTransactionTotalAmounts[i] = transactionTotalAmounts
 	}
 
-	return transactionTotalAmounts, nil
+	return periodTransactionTotalAmounts, nil
 }
 
-// GetAccountsAndCategoriesMonthlyInflowAndOutflow returns the every accounts monthly inflows and outflows amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool) (map[int32][]*models.Transaction, error) {
+// GetAccountsAndCategoriesMonthlyInflowAndOutflow returns the every accounts monthly inflows and outflows amount by specific date range,
+// the amount of each transaction would also be converted by the amount converter at the transaction date if the converter is not nil
+func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool, amountConverter models.TransactionAmountConverter) (map[int32][]*models.TransactionWithConvertedAmount, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -2118,8 +2547,8 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			finalConditionParams = append(finalConditionParams, "%%"+keyword+"%%")
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
-		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess = s.appendFilterTagIdsConditionToStatisticsQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
 
@@ -2137,10 +2566,22 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
 	}
 
+	allTransactions, err = s.expandSplitTransactions(c, uid, allTransactions, startTransactionTime, endTransactionTime, tagFilters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allTransactions, err = s.netRefundTransactions(c, uid, allTransactions)
+
+	if err != nil {
+		return nil, err
+	}
+
 	startYearMonth := startYear*100 + startMonth
 	endYearMonth := endYear*100 + endMonth
-	transactionsMonthlyAmountsMap := make(map[string]*models.Transaction)
-	transactionsMonthlyAmounts := make(map[int32][]*models.Transaction)
+	transactionsMonthlyAmountsMap := make(map[string]*models.TransactionWithConvertedAmount)
+	transactionsMonthlyAmounts := make(map[int32][]*models.TransactionWithConvertedAmount)
 
 	for i := 0; i < len(allTransactions); i++ {
 		transaction := allTransactions[i]
@@ -2150,7 +2591,8 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
 		}
 
-		yearMonth := utils.FormatUnixTimeToNumericYearMonth(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		yearMonth := utils.FormatUnixTimeToNumericYearMonth(transactionUnixTime, timeZone)
 
 		if (startYearMonth > 0 && yearMonth < startYearMonth) || (endYearMonth > 0 && yearMonth > endYearMonth) {
 			continue
@@ -2165,18 +2607,22 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 		transactionAmounts, exists := transactionsMonthlyAmountsMap[groupKey]
 
 		if !exists {
-			transactionAmounts = &models.Transaction{
-				Type:             transaction.Type,
-				CategoryId:       transaction.CategoryId,
-				AccountId:        transaction.AccountId,
-				RelatedAccountId: transaction.RelatedAccountId,
-				Amount:           0,
+			transactionAmounts = &models.TransactionWithConvertedAmount{
+				Transaction: &models.Transaction{
+					Type:             transaction.Type,
+					CategoryId:       transaction.CategoryId,
+					AccountId:        transaction.AccountId,
+					RelatedAccountId: transaction.RelatedAccountId,
+					Amount:           0,
+				},
+				HasConvertedAmount: amountConverter != nil,
 			}
 
 			transactionsMonthlyAmountsMap[groupKey] = transactionAmounts
 		}
 
 		transactionAmounts.Amount += transaction.Amount
+		s.addConvertedAmount(transactionAmounts, transaction, utils.FormatUnixTimeToNumericYearMonthDay(transactionUnixTime, timeZone), amountConverter)
 	}
 
 	for groupKey, transaction := range transactionsMonthlyAmountsMap {
@@ -2185,7 +2631,7 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 		monthlyAmounts, exists := transactionsMonthlyAmounts[yearMonth]
 
 		if !exists {
-			monthlyAmounts = make([]*models.Transaction, 0, 0)
+			monthlyAmounts = make([]*models.TransactionWithConvertedAmount, 0, 0)
 		}
 
 		monthlyAmounts = append(monthlyAmounts, transaction)
@@ -2195,6 +2641,146 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 	return transactionsMonthlyAmounts, nil
 }
 
+// GetHoldingsYearlyIncome returns the total amounts of income transactions which are linked to holdings grouped by year,
+// the amounts are summed by day, holding account and income account, and the transaction time of each amount is in that day
+func (s *TransactionService) GetHoldingsYearlyIncome(c core.Context, uid int64, startYear int32, endYear int32, clientTimezone *time.Location) (map[int32][]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND deleted=? AND type=? AND holding_account_id>?"
+	conditionParams := make([]any, 0, 4)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, false)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
+	conditionParams = append(conditionParams, 0)
+
+	maxTransactionTime := int64(0)
+	var allTransactions []*models.Transaction
+
+	for maxTransactionTime >= 0 {
+		var transactions []*models.Transaction
+
+		finalCondition := condition
+		finalConditionParams := make([]any, 0, 5)
+		finalConditionParams = append(finalConditionParams, conditionParams...)
+
+		if maxTransactionTime > 0 {
+			finalCondition = finalCondition + " AND transaction_time<=?"
+			finalConditionParams = append(finalConditionParams, maxTransactionTime)
+		}
+
+		err := s.UserDataDB(uid).NewSession(c).Select("account_id, holding_account_id, transaction_time, amount").Where(finalCondition, finalConditionParams...).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allTransactions = append(allTransactions, transactions...)
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			maxTransactionTime = -1
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	transactionsYearlyAmountsMap := make(map[string]*models.Transaction)
+	transactionsYearlyAmounts := make(map[int32][]*models.Transaction)
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		yearMonthDay := utils.FormatUnixTimeToNumericYearMonthDay(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), clientTimezone)
+		year := yearMonthDay / 10000
+
+		if (startYear > 0 && year < startYear) || (endYear > 0 && year > endYear) {
+			continue
+		}
+
+		groupKey := fmt.Sprintf("%d_%d_%d", yearMonthDay, transaction.HoldingAccountId, transaction.AccountId)
+		transactionAmounts, exists := transactionsYearlyAmountsMap[groupKey]
+
+		if !exists {
+			transactionAmounts = &models.Transaction{
+				Type:             models.TRANSACTION_DB_TYPE_INCOME,
+				TransactionTime:  transaction.TransactionTime,
+				AccountId:        transaction.AccountId,
+				HoldingAccountId: transaction.HoldingAccountId,
+				Amount:           0,
+			}
+
+			transactionsYearlyAmountsMap[groupKey] = transactionAmounts
+			transactionsYearlyAmounts[year] = append(transactionsYearlyAmounts[year], transactionAmounts)
+		}
+
+		transactionAmounts.Amount += transaction.Amount
+	}
+
+	return transactionsYearlyAmounts, nil
+}
+
+// GetAllTransferOutTransactions returns all transfer out transactions of user between the specified unix time range
+func (s *TransactionService) GetAllTransferOutTransactions(c core.Context, uid int64, startUnixTime int64, endUnixTime int64) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND deleted=? AND type=?"
+	conditionParams := make([]any, 0, 3)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, false)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
+
+	minTransactionTime := int64(0)
+	maxTransactionTime := int64(0)
+
+	if startUnixTime > 0 {
+		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(startUnixTime)
+	}
+
+	if endUnixTime > 0 {
+		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(endUnixTime)
+	}
+
+	var allTransactions []*models.Transaction
+
+	for maxTransactionTime >= 0 {
+		var transactions []*models.Transaction
+
+		finalCondition := condition
+		finalConditionParams := make([]any, 0, 5)
+		finalConditionParams = append(finalConditionParams, conditionParams...)
+
+		if minTransactionTime > 0 {
+			finalCondition = finalCondition + " AND transaction_time>=?"
+			finalConditionParams = append(finalConditionParams, minTransactionTime)
+		}
+
+		if maxTransactionTime > 0 {
+			finalCondition = finalCondition + " AND transaction_time<=?"
+			finalConditionParams = append(finalConditionParams, maxTransactionTime)
+		}
+
+		err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, account_id, related_id, related_account_id, transaction_time, timezone_utc_offset, amount, related_account_amount").Where(finalCondition, finalConditionParams...).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allTransactions = append(allTransactions, transactions...)
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			maxTransactionTime = -1
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	return allTransactions, nil
+}
+
 // GetTransactionMapByList returns a transaction map by a list
 func (s *TransactionService) GetTransactionMapByList(transactions []*models.Transaction) map[int64]*models.Transaction {
 	transactionMap := make(map[int64]*models.Transaction)
@@ -2218,7 +2804,11 @@ func (s *TransactionService) GetTransactionIds(transactions []*models.Transactio
 	return transactionIds
 }
 
-func (s *TransactionService) doCreateTransaction(c core.Context, database *datastore.Database, sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64, pictureIds []int64, pictureUpdateModel *models.TransactionPictureInfo) error {
+func (s *TransactionService) doCreateTransaction(c core.Context, database *datastore.Database, sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64, pictureIds []int64, pictureUpdateModel *models.TransactionPictureInfo, splits []*models.TransactionSplit) error {
+	if transaction.Reimbursable && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+		return errs.ErrReimbursableTransactionTypeInvalid
+	}
+
 	// Get and verify source and destination account
 	sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
 
@@ -2271,6 +2861,20 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		return err
 	}
 
+	// Get and verify splits
+	err = s.isSplitsValid(sess, transaction, splits)
+
+	if err != nil {
+		return err
+	}
+
+	// Get and verify holding account
+	err = s.isHoldingAccountValid(sess, transaction)
+
+	if err != nil {
+		return err
+	}
+
 	// Get and verify tags
 	err = s.isTagsValid(sess, transaction, transactionTagIndexes, tagIds)
 
@@ -2409,6 +3013,28 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		}
 	}
 
+	// Insert transaction splits
+	if len(splits) > 0 {
+		for i := 0; i < len(splits); i++ {
+			split := splits[i]
+			split.TransactionTime = transaction.TransactionTime
+
+			_, err := sess.Insert(split)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction split, because %s", err.Error())
+				return err
+			}
+		}
+
+		err = s.createTransactionSplitTagIndexes(sess, transaction, splits, transaction.CreatedUnixTime)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction split tag index, because %s", err.Error())
+			return err
+		}
+	}
+
 	// Update transaction picture
 	if len(pictureIds) > 0 {
 		_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, models.TransactionPictureNewPictureTransactionId).In("picture_id", pictureIds).Update(pictureUpdateModel)
@@ -2492,97 +3118,261 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 	return err
 }
 
-func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransactionTime int64, minTransactionTime int64, transactionDbType models.TransactionDbType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, amountFilter string, keyword string, noDuplicated bool) (string, []any) {
+// expandSplitTransactions replaces every split transaction in the list with the copies of the transaction for each split line,
+// and each copy has the category and amount of the split line
+func (s *TransactionService) expandSplitTransactions(c core.Context, uid int64, transactions []*models.Transaction, minTransactionTime int64, maxTransactionTime int64, tagFilters []*models.TransactionTagFilter) ([]*models.Transaction, error) {
+	if len(transactions) < 1 {
+		return transactions, nil
+	}
+
 	condition := "uid=? AND deleted=?"
-	conditionParams := make([]any, 0, 16)
+	conditionParams := make([]any, 0, 4)
 	conditionParams = append(conditionParams, uid)
 	conditionParams = append(conditionParams, false)
 
-	if maxTransactionTime > 0 {
-		condition = condition + " AND transaction_time<=?"
-		conditionParams = append(conditionParams, maxTransactionTime)
-	}
-
 	if minTransactionTime > 0 {
 		condition = condition + " AND transaction_time>=?"
 		conditionParams = append(conditionParams, minTransactionTime)
 	}
 
-	var accountIdsCondition strings.Builder
-	accountIdConditionParams := make([]any, 0, len(accountIds))
+	if maxTransactionTime > 0 {
+		condition = condition + " AND transaction_time<=?"
+		conditionParams = append(conditionParams, maxTransactionTime)
+	}
 
-	for i := 0; i < len(accountIds); i++ {
-		if i > 0 {
-			accountIdsCondition.WriteString(",")
-		}
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Select("split_id, transaction_id, category_id, amount, tag_ids, display_order").Where(condition, conditionParams...).OrderBy("transaction_id asc, display_order asc").Find(&splits)
 
-		accountIdsCondition.WriteString("?")
-		accountIdConditionParams = append(accountIdConditionParams, accountIds[i])
+	if err != nil {
+		return nil, err
 	}
 
-	if models.TRANSACTION_DB_TYPE_MODIFY_BALANCE <= transactionDbType && transactionDbType <= models.TRANSACTION_DB_TYPE_EXPENSE {
-		condition = condition + " AND type=?"
-		conditionParams = append(conditionParams, transactionDbType)
-	} else if transactionDbType == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transactionDbType == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-		if len(accountIds) == 0 {
-			condition = condition + " AND type=?"
-			conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
-		} else if len(accountIds) == 1 {
-			condition = condition + " AND (type=? OR type=?)"
-			conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
-			conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
-		} else { // len(accountsIds) > 1
-			condition = condition + " AND (type=? OR (type=? AND related_account_id NOT IN (" + accountIdsCondition.String() + ")))"
-			conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
-			conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
-			conditionParams = append(conditionParams, accountIdConditionParams...)
-		}
-	} else {
-		if noDuplicated {
-			if len(accountIds) == 0 {
-				condition = condition + " AND (type=? OR type=? OR type=? OR type=?)"
-				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE)
-				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
-				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
-				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
-			} else if len(accountIds) == 1 {
-				// Do Nothing
-			} else { // len(accountsIds) > 1
-				condition = condition + " AND (type=? OR type=? OR type=? OR type=? OR (type=? AND related_account_id NOT IN (" + accountIdsCondition.String() + ")))"
-				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE)
-				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
-				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
-				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
-				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
-				conditionParams = append(conditionParams, accountIdConditionParams...)
-			}
-		}
+	if len(splits) < 1 {
+		return transactions, nil
 	}
 
-	if len(categoryIds) > 0 {
-		var conditions strings.Builder
+	var matchedSplitIds map[int64]bool
 
-		for i := 0; i < len(categoryIds); i++ {
-			if i > 0 {
-				conditions.WriteString(",")
-			}
+	// the split lines which do not match the tag filters are excluded, and the tags of transaction are also treated as the tags of its split lines
+	if len(tagFilters) > 0 {
+		var matchedSplits []*models.TransactionSplit
+		err = s.UserDataDB(uid).NewSession(c).Select("split_id").Where(condition, conditionParams...).And(s.getSplitTagFiltersCondition(uid, maxTransactionTime, minTransactionTime, tagFilters)).Find(&matchedSplits)
 
-			conditions.WriteString("?")
-			conditionParams = append(conditionParams, categoryIds[i])
+		if err != nil {
+			return nil, err
 		}
 
-		if conditions.Len() > 1 {
-			condition = condition + " AND category_id IN (" + conditions.String() + ")"
-		} else {
-			condition = condition + " AND category_id = " + conditions.String()
+		matchedSplitIds = make(map[int64]bool, len(matchedSplits))
+
+		for i := 0; i < len(matchedSplits); i++ {
+			matchedSplitIds[matchedSplits[i].SplitId] = true
 		}
 	}
 
-	if len(accountIds) > 0 {
-		if accountIdsCondition.Len() > 1 {
-			condition = condition + " AND account_id IN (" + accountIdsCondition.String() + ")"
-		} else {
-			condition = condition + " AND account_id = " + accountIdsCondition.String()
+	allTransactionSplits := make(map[int64][]*models.TransactionSplit)
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		allTransactionSplits[split.TransactionId] = append(allTransactionSplits[split.TransactionId], split)
+	}
+
+	expandedTransactions := make([]*models.Transaction, 0, len(transactions)+len(splits))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		transactionSplits, exists := allTransactionSplits[transaction.TransactionId]
+
+		if !exists || (transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE) {
+			expandedTransactions = append(expandedTransactions, transaction)
+			continue
+		}
+
+		for j := 0; j < len(transactionSplits); j++ {
+			if len(tagFilters) > 0 && !matchedSplitIds[transactionSplits[j].SplitId] {
+				continue
+			}
+
+			splitTransaction := *transaction
+			splitTransaction.CategoryId = transactionSplits[j].CategoryId
+			splitTransaction.Amount = transactionSplits[j].Amount
+			expandedTransactions = append(expandedTransactions, &splitTransaction)
+		}
+	}
+
+	return expandedTransactions, nil
+}
+
+func (s *TransactionService) netRefundTransactions(c core.Context, uid int64, transactions []*models.Transaction) ([]*models.Transaction, error) {
+	incomeTransactionIds := make([]int64, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		if transactions[i].Type == models.TRANSACTION_DB_TYPE_INCOME {
+			incomeTransactionIds = append(incomeTransactionIds, transactions[i].TransactionId)
+		}
+	}
+
+	incomeTransactionIds = utils.ToUniqueInt64Slice(incomeTransactionIds)
+
+	if len(incomeTransactionIds) < 1 {
+		return transactions, nil
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+	refunds := make([]*models.TransactionRefund, 0)
+
+	for i := 0; i < len(incomeTransactionIds); i += pageCountForLoadTransactionRefunds {
+		var currentRefunds []*models.TransactionRefund
+		err := sess.Where("uid=? AND deleted=?", uid, false).In("income_transaction_id", incomeTransactionIds[i:min(i+pageCountForLoadTransactionRefunds, len(incomeTransactionIds))]).Find(&currentRefunds)
+
+		if err != nil {
+			return nil, err
+		}
+
+		refunds = append(refunds, currentRefunds...)
+	}
+
+	if len(refunds) < 1 {
+		return transactions, nil
+	}
+
+	expenseTransactionIds := make([]int64, len(refunds))
+
+	for i := 0; i < len(refunds); i++ {
+		expenseTransactionIds[i] = refunds[i].ExpenseTransactionId
+	}
+
+	expenseTransactionIds = utils.ToUniqueInt64Slice(expenseTransactionIds)
+	expenseTransactions := make([]*models.Transaction, 0, len(expenseTransactionIds))
+	expenseTransactionSplits := make(map[int64][]*models.TransactionSplit)
+
+	for i := 0; i < len(expenseTransactionIds); i += pageCountForLoadTransactionRefunds {
+		currentExpenseTransactionIds := expenseTransactionIds[i:min(i+pageCountForLoadTransactionRefunds, len(expenseTransactionIds))]
+
+		var currentExpenseTransactions []*models.Transaction
+		err := sess.Select("transaction_id, category_id, amount").Where("uid=? AND deleted=?", uid, false).In("transaction_id", currentExpenseTransactionIds).Find(&currentExpenseTransactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		expenseTransactions = append(expenseTransactions, currentExpenseTransactions...)
+
+		var currentSplits []*models.TransactionSplit
+		err = sess.Select("transaction_id, category_id, amount, display_order").Where("uid=? AND deleted=?", uid, false).In("transaction_id", currentExpenseTransactionIds).OrderBy("transaction_id asc, display_order asc").Find(&currentSplits)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < len(currentSplits); j++ {
+			split := currentSplits[j]
+			expenseTransactionSplits[split.TransactionId] = append(expenseTransactionSplits[split.TransactionId], split)
+		}
+	}
+
+	return models.GetTransactionsWithNettedRefunds(transactions, refunds, s.GetTransactionMapByList(expenseTransactions), expenseTransactionSplits), nil
+}
+
+func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransactionTime int64, minTransactionTime int64, transactionDbType models.TransactionDbType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, amountFilter string, keyword string, noDuplicated bool) (string, []any) {
+	condition := "uid=? AND deleted=?"
+	conditionParams := make([]any, 0, 16)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, false)
+
+	if maxTransactionTime > 0 {
+		condition = condition + " AND transaction_time<=?"
+		conditionParams = append(conditionParams, maxTransactionTime)
+	}
+
+	if minTransactionTime > 0 {
+		condition = condition + " AND transaction_time>=?"
+		conditionParams = append(conditionParams, minTransactionTime)
+	}
+
+	var accountIdsCondition strings.Builder
+	accountIdConditionParams := make([]any, 0, len(accountIds))
+
+	for i := 0; i < len(accountIds); i++ {
+		if i > 0 {
+			accountIdsCondition.WriteString(",")
+		}
+
+		accountIdsCondition.WriteString("?")
+		accountIdConditionParams = append(accountIdConditionParams, accountIds[i])
+	}
+
+	if models.TRANSACTION_DB_TYPE_MODIFY_BALANCE <= transactionDbType && transactionDbType <= models.TRANSACTION_DB_TYPE_EXPENSE {
+		condition = condition + " AND type=?"
+		conditionParams = append(conditionParams, transactionDbType)
+	} else if transactionDbType == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transactionDbType == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		if len(accountIds) == 0 {
+			condition = condition + " AND type=?"
+			conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
+		} else if len(accountIds) == 1 {
+			condition = condition + " AND (type=? OR type=?)"
+			conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
+			conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
+		} else { // len(accountsIds) > 1
+			condition = condition + " AND (type=? OR (type=? AND related_account_id NOT IN (" + accountIdsCondition.String() + ")))"
+			conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
+			conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
+			conditionParams = append(conditionParams, accountIdConditionParams...)
+		}
+	} else {
+		if noDuplicated {
+			if len(accountIds) == 0 {
+				condition = condition + " AND (type=? OR type=? OR type=? OR type=?)"
+				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE)
+				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
+				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
+				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
+			} else if len(accountIds) == 1 {
+				// Do Nothing
+			} else { // len(accountsIds) > 1
+				condition = condition + " AND (type=? OR type=? OR type=? OR type=? OR (type=? AND related_account_id NOT IN (" + accountIdsCondition.String() + ")))"
+				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE)
+				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
+				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
+				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
+				conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
+				conditionParams = append(conditionParams, accountIdConditionParams...)
+			}
+		}
+	}
+
+	if len(categoryIds) > 0 {
+		var conditions strings.Builder
+		categoryIdConditionParams := make([]any, 0, len(categoryIds))
+
+		for i := 0; i < len(categoryIds); i++ {
+			if i > 0 {
+				conditions.WriteString(",")
+			}
+
+			conditions.WriteString("?")
+			categoryIdConditionParams = append(categoryIdConditionParams, categoryIds[i])
+		}
+
+		// The split transaction also matches when any of its split lines has the specified categories
+		splitCondition := "transaction_id IN (SELECT transaction_id FROM transaction_split WHERE uid=? AND deleted=? AND category_id IN (" + conditions.String() + "))"
+
+		if conditions.Len() > 1 {
+			condition = condition + " AND (category_id IN (" + conditions.String() + ") OR " + splitCondition + ")"
+		} else {
+			condition = condition + " AND (category_id = " + conditions.String() + " OR " + splitCondition + ")"
+		}
+
+		conditionParams = append(conditionParams, categoryIdConditionParams...)
+		conditionParams = append(conditionParams, uid)
+		conditionParams = append(conditionParams, false)
+		conditionParams = append(conditionParams, categoryIdConditionParams...)
+	}
+
+	if len(accountIds) > 0 {
+		if accountIdsCondition.Len() > 1 {
+			condition = condition + " AND account_id IN (" + accountIdsCondition.String() + ")"
+		} else {
+			condition = condition + " AND account_id = " + accountIdsCondition.String()
 		}
 
 		conditionParams = append(conditionParams, accountIdConditionParams...)
@@ -2661,7 +3451,8 @@ func (s *TransactionService) appendFilterTagIdsConditionToQuery(sess *xorm.Sessi
 		}
 
 		subQuery := builder.Select("transaction_id").From("transaction_tag_index").Where(subQueryCondition)
-		sess.NotIn("transaction_id", subQuery).NotIn("related_id", subQuery)
+		splitSubQuery := builder.Select("transaction_id").From("transaction_split_tag_index").Where(subQueryCondition)
+		sess.NotIn("transaction_id", subQuery).NotIn("related_id", subQuery).NotIn("transaction_id", splitSubQuery)
 		return sess
 	}
 
@@ -2669,6 +3460,58 @@ func (s *TransactionService) appendFilterTagIdsConditionToQuery(sess *xorm.Sessi
 		return sess
 	}
 
+	sess.And(s.getTagFiltersCondition(uid, maxTransactionTime, minTransactionTime, tagFilters, func(tagIndexCondition builder.Cond) builder.Cond {
+		subQuery := builder.Select("transaction_id").From("transaction_tag_index").Where(tagIndexCondition)
+		splitSubQuery := builder.Select("transaction_id").From("transaction_split_tag_index").Where(tagIndexCondition)
+		return builder.Or(builder.In("transaction_id", subQuery), builder.In("related_id", subQuery), builder.In("transaction_id", splitSubQuery))
+	}))
+
+	return sess
+}
+
+// appendFilterTagIdsConditionToStatisticsQuery appends the tag filters condition to the query of statistics, the split lines of a transaction are counted separately,
+// so the transaction which has split lines is matched if any of its split lines matches the tag filters, and the other transactions are matched by their own tags
+func (s *TransactionService) appendFilterTagIdsConditionToStatisticsQuery(sess *xorm.Session, uid int64, maxTransactionTime int64, minTransactionTime int64, tagFilters []*models.TransactionTagFilter, noTags bool) *xorm.Session {
+	if noTags || len(tagFilters) < 1 {
+		return s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
+	}
+
+	splitQueryCondition := builder.And(builder.Eq{"uid": uid}, builder.Eq{"deleted": false})
+
+	if maxTransactionTime > 0 {
+		splitQueryCondition = splitQueryCondition.And(builder.Lte{"transaction_time": maxTransactionTime})
+	}
+
+	if minTransactionTime > 0 {
+		splitQueryCondition = splitQueryCondition.And(builder.Gte{"transaction_time": minTransactionTime})
+	}
+
+	splitTransactionsQuery := builder.Select("transaction_id").From("transaction_split").Where(splitQueryCondition)
+	matchedSplitTransactionsQuery := builder.Select("transaction_id").From("transaction_split").Where(splitQueryCondition.And(s.getSplitTagFiltersCondition(uid, maxTransactionTime, minTransactionTime, tagFilters)))
+	transactionTagFiltersCondition := s.getTagFiltersCondition(uid, maxTransactionTime, minTransactionTime, tagFilters, func(tagIndexCondition builder.Cond) builder.Cond {
+		subQuery := builder.Select("transaction_id").From("transaction_tag_index").Where(tagIndexCondition)
+		return builder.Or(builder.In("transaction_id", subQuery), builder.In("related_id", subQuery))
+	})
+
+	sess.And(builder.Or(builder.And(builder.NotIn("transaction_id", splitTransactionsQuery), transactionTagFiltersCondition), builder.In("transaction_id", matchedSplitTransactionsQuery)))
+
+	return sess
+}
+
+// getSplitTagFiltersCondition returns the query condition of all the tag filters for the split lines, the tags of transaction are also treated as the tags of its split lines
+func (s *TransactionService) getSplitTagFiltersCondition(uid int64, maxTransactionTime int64, minTransactionTime int64, tagFilters []*models.TransactionTagFilter) builder.Cond {
+	return s.getTagFiltersCondition(uid, maxTransactionTime, minTransactionTime, tagFilters, func(tagIndexCondition builder.Cond) builder.Cond {
+		subQuery := builder.Select("transaction_id").From("transaction_tag_index").Where(tagIndexCondition)
+		splitSubQuery := builder.Select("split_id").From("transaction_split_tag_index").Where(tagIndexCondition)
+		return builder.Or(builder.In("transaction_id", subQuery), builder.In("split_id", splitSubQuery))
+	})
+}
+
+// getTagFiltersCondition returns the query condition of all the tag filters, the hasTagsCondition returns the condition of whether the row has any tag in the tag indexes
+// which match the specified condition, so the transactions and the split lines share the same tag filter logic
+func (s *TransactionService) getTagFiltersCondition(uid int64, maxTransactionTime int64, minTransactionTime int64, tagFilters []*models.TransactionTagFilter, hasTagsCondition func(tagIndexCondition builder.Cond) builder.Cond) builder.Cond {
+	condition := builder.NewCond()
+
 	for i := 0; i < len(tagFilters); i++ {
 		tagFilter := tagFilters[i]
 		subQueryCondition := builder.And(builder.Eq{"uid": uid}, builder.Eq{"deleted": false})
@@ -2681,21 +3524,47 @@ func (s *TransactionService) appendFilterTagIdsConditionToQuery(sess *xorm.Sessi
 			subQueryCondition = subQueryCondition.And(builder.Gte{"transaction_time": minTransactionTime})
 		}
 
-		subQueryCondition = subQueryCondition.And(builder.In("tag_id", tagFilter.TagIds))
-		subQuery := builder.Select("transaction_id").From("transaction_tag_index").Where(subQueryCondition)
+		if tagFilter.Type == models.TRANSACTION_TAG_FILTER_HAS_ANY || tagFilter.Type == models.TRANSACTION_TAG_FILTER_NOT_HAS_ANY {
+			hasAnyTagsCondition := hasTagsCondition(subQueryCondition.And(builder.In("tag_id", tagFilter.TagIds)))
 
-		if tagFilter.Type == models.TRANSACTION_TAG_FILTER_HAS_ALL || tagFilter.Type == models.TRANSACTION_TAG_FILTER_NOT_HAS_ALL {
-			subQuery = subQuery.GroupBy("transaction_id").Having(fmt.Sprintf("COUNT(DISTINCT tag_id) >= %d", len(tagFilter.TagIds)))
-		}
+			if tagFilter.Type == models.TRANSACTION_TAG_FILTER_HAS_ANY {
+				condition = condition.And(hasAnyTagsCondition)
+			} else {
+				condition = condition.And(builder.Not{hasAnyTagsCondition})
+			}
+		} else if tagFilter.Type == models.TRANSACTION_TAG_FILTER_HAS_ALL || tagFilter.Type == models.TRANSACTION_TAG_FILTER_NOT_HAS_ALL {
+			// the tags of transaction and the tags of its split lines are counted together, so each tag is checked separately
+			hasAllTagsCondition := builder.NewCond()
+
+			for j := 0; j < len(tagFilter.TagIds); j++ {
+				hasAllTagsCondition = hasAllTagsCondition.And(hasTagsCondition(subQueryCondition.And(builder.Eq{"tag_id": tagFilter.TagIds[j]})))
+			}
 
-		if tagFilter.Type == models.TRANSACTION_TAG_FILTER_HAS_ANY || tagFilter.Type == models.TRANSACTION_TAG_FILTER_HAS_ALL {
-			sess.And(builder.Or(builder.In("transaction_id", subQuery), builder.In("related_id", subQuery)))
-		} else if tagFilter.Type == models.TRANSACTION_TAG_FILTER_NOT_HAS_ANY || tagFilter.Type == models.TRANSACTION_TAG_FILTER_NOT_HAS_ALL {
-			sess.NotIn("transaction_id", subQuery).NotIn("related_id", subQuery)
+			if tagFilter.Type == models.TRANSACTION_TAG_FILTER_HAS_ALL {
+				condition = condition.And(hasAllTagsCondition)
+			} else {
+				condition = condition.And(builder.Not{hasAllTagsCondition})
+			}
 		}
 	}
 
-	return sess
+	return condition
+}
+
+func (s *TransactionService) addConvertedAmount(totalAmounts *models.TransactionWithConvertedAmount, transaction *models.Transaction, yearMonthDay int32, amountConverter models.TransactionAmountConverter) {
+	if amountConverter == nil || !totalAmounts.HasConvertedAmount {
+		return
+	}
+
+	convertedAmount, ok := amountConverter(transaction.AccountId, transaction.Amount, yearMonthDay)
+
+	if !ok {
+		totalAmounts.ConvertedAmount = 0
+		totalAmounts.HasConvertedAmount = false
+		return
+	}
+
+	totalAmounts.ConvertedAmount += convertedAmount
 }
 
 func (s *TransactionService) isAccountIdValid(transaction *models.Transaction) error {
@@ -2843,6 +3712,178 @@ func (s *TransactionService) getOldAccountModels(sess *xorm.Session, transaction
 	return oldSourceAccount, oldDestinationAccount, nil
 }
 
+func (s *TransactionService) isTransactionReconciled(sess *xorm.Session, transaction *models.Transaction) (bool, error) {
+	if transaction.ReconciliationStatus == models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED {
+		return true, nil
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT && transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return false, nil
+	}
+
+	return sess.Cols("uid", "deleted", "transaction_id", "reconciliation_status").Where("uid=? AND deleted=? AND transaction_id=? AND reconciliation_status=?", transaction.Uid, false, transaction.RelatedId, models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED).Exist(&models.Transaction{})
+}
+
+func (s *TransactionService) hasReconciledTransactions(c core.Context, uid int64, transactions []*models.Transaction) (bool, error) {
+	relatedTransactionIds := make([]int64, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.ReconciliationStatus == models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED {
+			return true, nil
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			relatedTransactionIds = append(relatedTransactionIds, transaction.RelatedId)
+		}
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+
+	for i := 0; i < len(relatedTransactionIds); i += pageCountForCheckReconciledTransactions {
+		exists, err := sess.Cols("uid", "deleted", "transaction_id", "reconciliation_status").Where("uid=? AND deleted=? AND reconciliation_status=?", uid, false, models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED).In("transaction_id", relatedTransactionIds[i:min(i+pageCountForCheckReconciledTransactions, len(relatedTransactionIds))]).Exist(&models.Transaction{})
+
+		if err != nil {
+			return false, err
+		} else if exists {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *TransactionService) isInvestmentTradesValid(sess *xorm.Session, transaction *models.Transaction, oldTransaction *models.Transaction) error {
+	if transaction.Amount == oldTransaction.Amount && transaction.AccountId == oldTransaction.AccountId &&
+		transaction.RelatedAccountAmount == oldTransaction.RelatedAccountAmount && transaction.RelatedAccountId == oldTransaction.RelatedAccountId {
+		return nil
+	}
+
+	linkedTransactions := map[int64]*models.Transaction{
+		transaction.TransactionId: transaction,
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		relatedTransaction := s.GetRelatedTransferTransaction(transaction)
+		linkedTransactions[relatedTransaction.TransactionId] = relatedTransaction
+	}
+
+	linkedTransactionIds := make([]int64, 0, len(linkedTransactions))
+
+	for transactionId := range linkedTransactions {
+		linkedTransactionIds = append(linkedTransactionIds, transactionId)
+	}
+
+	var trades []*models.InvestmentTrade
+	err := sess.Where("uid=? AND deleted=?", transaction.Uid, false).In("transaction_id", linkedTransactionIds).Find(&trades)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(trades); i++ {
+		trade := trades[i]
+
+		// the transaction of split or corporate action adjusts the account balance by the calculated quantity, so it cannot be modified
+		if trade.Type == models.INVESTMENT_TRADE_TYPE_SPLIT || trade.CorporateActionId > 0 {
+			return errs.ErrInvestmentTradeTransactionMismatch
+		}
+
+		err = InvestmentTrades.isLinkedTransactionValid(trade, linkedTransactions[trade.TransactionId])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionService) isRefundsValid(sess *xorm.Session, transaction *models.Transaction, oldTransaction *models.Transaction, sourceAccount *models.Account) error {
+	if transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE && transaction.Type != models.TRANSACTION_DB_TYPE_INCOME {
+		return nil
+	}
+
+	if transaction.Amount == oldTransaction.Amount && transaction.AccountId == oldTransaction.AccountId {
+		return nil
+	}
+
+	var refunds []*models.TransactionRefund
+	var err error
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		err = sess.Where("uid=? AND deleted=? AND expense_transaction_id=?", transaction.Uid, false, transaction.TransactionId).Find(&refunds)
+	} else {
+		err = sess.Where("uid=? AND deleted=? AND income_transaction_id=?", transaction.Uid, false, transaction.TransactionId).Find(&refunds)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(refunds) < 1 {
+		return nil
+	}
+
+	totalRefundAmount := int64(0)
+	linkedTransactionIds := make([]int64, len(refunds))
+
+	for i := 0; i < len(refunds); i++ {
+		totalRefundAmount += refunds[i].Amount
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			linkedTransactionIds[i] = refunds[i].IncomeTransactionId
+		} else {
+			linkedTransactionIds[i] = refunds[i].ExpenseTransactionId
+		}
+	}
+
+	if totalRefundAmount > transaction.Amount {
+		if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			return errs.ErrTransactionRefundAmountExceedsExpenseAmount
+		} else {
+			return errs.ErrTransactionRefundAmountExceedsIncomeAmount
+		}
+	}
+
+	if transaction.AccountId == oldTransaction.AccountId {
+		return nil
+	}
+
+	var linkedTransactions []*models.Transaction
+	err = sess.Cols("transaction_id", "account_id").Where("uid=? AND deleted=?", transaction.Uid, false).In("transaction_id", linkedTransactionIds).Find(&linkedTransactions)
+
+	if err != nil {
+		return err
+	}
+
+	linkedAccountIds := make([]int64, len(linkedTransactions))
+
+	for i := 0; i < len(linkedTransactions); i++ {
+		linkedAccountIds[i] = linkedTransactions[i].AccountId
+	}
+
+	if len(linkedAccountIds) < 1 {
+		return nil
+	}
+
+	var linkedAccounts []*models.Account
+	err = sess.Cols("account_id", "currency").Where("uid=? AND deleted=?", transaction.Uid, false).In("account_id", utils.ToUniqueInt64Slice(linkedAccountIds)).Find(&linkedAccounts)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(linkedAccounts); i++ {
+		if linkedAccounts[i].Currency != sourceAccount.Currency {
+			return errs.ErrTransactionRefundCurrencyNotMatched
+		}
+	}
+
+	return nil
+}
+
 func (s *TransactionService) getRelatedUpdateColumns(updateCols []string) []string {
 	relatedUpdateCols := make([]string, len(updateCols))
 
@@ -2909,6 +3950,37 @@ func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *mo
 	return nil
 }
 
+func (s *TransactionService) isHoldingAccountValid(sess *xorm.Session, transaction *models.Transaction) error {
+	if transaction.HoldingAccountId == 0 {
+		return nil
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME {
+		return errs.ErrOnlyIncomeTransactionCanSetHolding
+	}
+
+	holdingAccount := &models.Account{}
+	has, err := sess.ID(transaction.HoldingAccountId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(holdingAccount)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrTransactionHoldingAccountNotFound
+	}
+
+	if holdingAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+		return errs.ErrTransactionHoldingAccountInvalid
+	}
+
+	assetType := holdingAccount.GetAssetType()
+
+	if assetType != models.ACCOUNT_ASSET_TYPE_STOCK && assetType != models.ACCOUNT_ASSET_TYPE_CRYPTO && holdingAccount.Category != models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT {
+		return errs.ErrTransactionHoldingAccountInvalid
+	}
+
+	return nil
+}
+
 func (s *TransactionService) isTagsValid(sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64) error {
 	if len(transactionTagIndexes) > 0 {
 		var tags []*models.TransactionTag
@@ -2938,6 +4010,158 @@ func (s *TransactionService) isTagsValid(sess *xorm.Session, transaction *models
 	return nil
 }
 
+func (s *TransactionService) prepareTransactionSplits(transaction *models.Transaction, splits []*models.TransactionSplit, now int64) error {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	if len(splits) > models.MaximumSplitsCountOfTransaction {
+		return errs.ErrTransactionSplitsCountInvalid
+	}
+
+	splitUuids := s.GenerateUuids(uuid.UUID_TYPE_TRANSACTION, uint16(len(splits)))
+
+	if len(splitUuids) < len(splits) {
+		return errs.ErrSystemIsBusy
+	}
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		split.SplitId = splitUuids[i]
+		split.Uid = transaction.Uid
+		split.Deleted = false
+		split.TransactionId = transaction.TransactionId
+		split.DisplayOrder = int32(i + 1)
+		split.CreatedUnixTime = now
+		split.UpdatedUnixTime = now
+	}
+
+	// The category of split transaction is the category of its first split line
+	transaction.CategoryId = splits[0].CategoryId
+
+	return nil
+}
+
+func (s *TransactionService) createTransactionSplitTagIndexes(sess *xorm.Session, transaction *models.Transaction, splits []*models.TransactionSplit, now int64) error {
+	splitTagIndexes := make([]*models.TransactionSplitTagIndex, 0)
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		tagIds := utils.ToUniqueInt64Slice(split.GetTagIds())
+
+		for j := 0; j < len(tagIds); j++ {
+			splitTagIndexes = append(splitTagIndexes, &models.TransactionSplitTagIndex{
+				Uid:             transaction.Uid,
+				Deleted:         false,
+				TransactionTime: transaction.TransactionTime,
+				TagId:           tagIds[j],
+				TransactionId:   transaction.TransactionId,
+				SplitId:         split.SplitId,
+				CreatedUnixTime: now,
+				UpdatedUnixTime: now,
+			})
+		}
+	}
+
+	if len(splitTagIndexes) < 1 {
+		return nil
+	}
+
+	tagIndexUuids := s.GenerateUuids(uuid.UUID_TYPE_TAG_INDEX, uint16(len(splitTagIndexes)))
+
+	if len(tagIndexUuids) < len(splitTagIndexes) {
+		return errs.ErrSystemIsBusy
+	}
+
+	for i := 0; i < len(splitTagIndexes); i++ {
+		splitTagIndexes[i].TagIndexId = tagIndexUuids[i]
+
+		_, err := sess.Insert(splitTagIndexes[i])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionService) isSplitsValid(sess *xorm.Session, transaction *models.Transaction, splits []*models.TransactionSplit) error {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+		return errs.ErrOnlyIncomeOrExpenseTransactionCanBeSplit
+	}
+
+	if len(splits) < 2 || len(splits) > models.MaximumSplitsCountOfTransaction {
+		return errs.ErrTransactionSplitsCountInvalid
+	}
+
+	verifiedCategoryIds := make(map[int64]bool)
+	allTagIds := make([]int64, 0)
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+
+		if split.Amount == 0 {
+			return errs.ErrTransactionSplitAmountInvalid
+		}
+
+		if !verifiedCategoryIds[split.CategoryId] {
+			err := s.isCategoryValid(sess, &models.Transaction{
+				Uid:        transaction.Uid,
+				Type:       transaction.Type,
+				CategoryId: split.CategoryId,
+			})
+
+			if err == errs.ErrTransactionCategoryTypeInvalid {
+				return errs.ErrTransactionSplitCategoryTypeInvalid
+			} else if err != nil {
+				return err
+			}
+
+			verifiedCategoryIds[split.CategoryId] = true
+		}
+
+		allTagIds = append(allTagIds, split.GetTagIds()...)
+	}
+
+	if models.GetTransactionSplitsTotalAmount(splits) != transaction.Amount {
+		return errs.ErrTransactionSplitsAmountNotEqualToTransactionAmount
+	}
+
+	allTagIds = utils.ToUniqueInt64Slice(allTagIds)
+
+	if len(allTagIds) > 0 {
+		var tags []*models.TransactionTag
+		err := sess.Where("uid=? AND deleted=?", transaction.Uid, false).In("tag_id", allTagIds).Find(&tags)
+
+		if err != nil {
+			return err
+		}
+
+		tagMap := make(map[int64]*models.TransactionTag)
+
+		for i := 0; i < len(tags); i++ {
+			if tags[i].Hidden {
+				return errs.ErrCannotUseHiddenTransactionTag
+			}
+
+			tagMap[tags[i].TagId] = tags[i]
+		}
+
+		for i := 0; i < len(allTagIds); i++ {
+			if _, exists := tagMap[allTagIds[i]]; !exists {
+				return errs.ErrTransactionTagNotFound
+			}
+		}
+	}
+
+	return nil
+}
+
 func (s *TransactionService) isPicturesValid(sess *xorm.Session, transaction *models.Transaction, pictureIds []int64) error {
 	if len(pictureIds) > 0 {
 		var pictureInfos []*models.TransactionPictureInfo
@@ -0,0 +1,403 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestDeleteAllTransactionsOfAccount_HasReconciledTransaction(t *testing.T) {
+	initializeTestDataStore(t, new(models.Account), new(models.Transaction))
+
+	c := core.NewNullContext()
+	sess := Transactions.UserDataDB(1).NewSession(c)
+
+	_, err := sess.Insert(&models.Account{AccountId: 1001, Uid: 1, Name: "Checking Account", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 2000})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{TransactionId: 2001, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 101, AccountId: 1001, TransactionTime: 1000, Amount: 3000, ReconciliationStatus: models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{TransactionId: 2002, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 201, AccountId: 1001, TransactionTime: 2000, Amount: 1000})
+	assert.Nil(t, err)
+
+	err = Transactions.DeleteAllTransactionsOfAccount(c, 1, 1001, 100)
+	assert.Equal(t, errs.ErrCannotDeleteReconciledTransaction, err)
+
+	count, err := sess.Where("uid=? AND deleted=?", 1, false).Count(&models.Transaction{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestDeleteAllTransactionsOfAccount_HasReconciledRelatedTransferTransaction(t *testing.T) {
+	initializeTestDataStore(t, new(models.Account), new(models.Transaction))
+
+	c := core.NewNullContext()
+	sess := Transactions.UserDataDB(1).NewSession(c)
+
+	_, err := sess.Insert(&models.Transaction{TransactionId: 2001, Uid: 1, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, CategoryId: 301, AccountId: 1001, TransactionTime: 1000, Amount: 1000, RelatedId: 2002, RelatedAccountId: 1002, RelatedAccountAmount: 1000})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{TransactionId: 2002, Uid: 1, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, CategoryId: 301, AccountId: 1002, TransactionTime: 1001, Amount: 1000, RelatedId: 2001, RelatedAccountId: 1001, RelatedAccountAmount: 1000, ReconciliationStatus: models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED})
+	assert.Nil(t, err)
+
+	err = Transactions.DeleteAllTransactionsOfAccount(c, 1, 1001, 100)
+	assert.Equal(t, errs.ErrCannotDeleteReconciledTransaction, err)
+
+	count, err := sess.Where("uid=? AND deleted=?", 1, false).Count(&models.Transaction{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func initializeTransactionSplitTestData(t *testing.T) {
+	initializeTestDataStore(t, new(models.Account), new(models.Transaction), new(models.TransactionCategory), new(models.TransactionTag), new(models.TransactionTagIndex), new(models.TransactionSplit), new(models.TransactionSplitTagIndex), new(models.TransactionPictureInfo), new(models.TransactionRefund), new(models.TransactionTemplate), new(models.InvestmentTrade))
+
+	sess := Transactions.UserDataDB(1).NewSession(core.NewNullContext())
+
+	_, err := sess.Insert(&models.Account{AccountId: 1001, Uid: 1, Name: "Checking Account", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.TransactionCategory{CategoryId: 100, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: models.LevelOneTransactionCategoryParentId, Name: "Daily Expense"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.TransactionCategory{CategoryId: 101, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 100, Name: "Food"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.TransactionCategory{CategoryId: 102, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 100, Name: "Household"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.TransactionTag{TagId: 201, Uid: 1, Name: "Work"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.TransactionTag{TagId: 202, Uid: 1, Name: "Family"})
+	assert.Nil(t, err)
+}
+
+func createTestSplitTransaction(t *testing.T) *models.Transaction {
+	transaction := &models.Transaction{Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1001, TransactionTime: 1700000000000, Amount: 3000}
+	splits := []*models.TransactionSplit{
+		{CategoryId: 101, Amount: 2000, TagIds: "201"},
+		{CategoryId: 102, Amount: 1000},
+	}
+
+	err := Transactions.CreateTransaction(core.NewNullContext(), transaction, nil, nil, splits)
+	assert.Nil(t, err)
+
+	return transaction
+}
+
+func TestCreateTransaction_WithSplits(t *testing.T) {
+	initializeTransactionSplitTestData(t)
+	transaction := createTestSplitTransaction(t)
+
+	c := core.NewNullContext()
+	splits, err := TransactionSplits.GetAllSplitsOfTransactions(c, 1, []int64{transaction.TransactionId})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(splits[transaction.TransactionId]))
+	assert.Equal(t, int64(101), splits[transaction.TransactionId][0].CategoryId)
+	assert.Equal(t, int64(2000), splits[transaction.TransactionId][0].Amount)
+	assert.Equal(t, int64(102), splits[transaction.TransactionId][1].CategoryId)
+	assert.Equal(t, int64(1000), splits[transaction.TransactionId][1].Amount)
+
+	account := &models.Account{}
+	_, err = Transactions.UserDataDB(1).NewSession(c).ID(1001).Get(account)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-3000), account.Balance)
+
+	transactions, err := Transactions.GetTransactionsByMaxTime(c, 1, 0, 0, 0, nil, nil, []*models.TransactionTagFilter{{TagIds: []int64{201}, Type: models.TRANSACTION_TAG_FILTER_HAS_ANY}}, false, "", "", 1, 10, false, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(transactions))
+
+	transactions, err = Transactions.GetTransactionsByMaxTime(c, 1, 0, 0, 0, nil, nil, []*models.TransactionTagFilter{{TagIds: []int64{201}, Type: models.TRANSACTION_TAG_FILTER_NOT_HAS_ANY}}, false, "", "", 1, 10, false, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(transactions))
+
+	transactions, err = Transactions.GetTransactionsByMaxTime(c, 1, 0, 0, 0, nil, nil, nil, true, "", "", 1, 10, false, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(transactions))
+}
+
+func TestGetAccountsAndCategoriesTotalInflowAndOutflow_SplitTagFilters(t *testing.T) {
+	initializeTransactionSplitTestData(t)
+	createTestSplitTransaction(t)
+
+	c := core.NewNullContext()
+	totalAmounts, err := Transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, 1, 1690000000, 1710000000, []*models.TransactionTagFilter{{TagIds: []int64{201}, Type: models.TRANSACTION_TAG_FILTER_HAS_ANY}}, false, "", time.UTC, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(totalAmounts))
+	assert.Equal(t, int64(101), totalAmounts[0].CategoryId)
+	assert.Equal(t, int64(2000), totalAmounts[0].Amount)
+
+	totalAmounts, err = Transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, 1, 1690000000, 1710000000, []*models.TransactionTagFilter{{TagIds: []int64{201, 202}, Type: models.TRANSACTION_TAG_FILTER_HAS_ALL}}, false, "", time.UTC, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(totalAmounts))
+
+	totalAmounts, err = Transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, 1, 1690000000, 1710000000, nil, false, "", time.UTC, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(totalAmounts))
+}
+
+func TestGetAccountsAndCategoriesTotalInflowAndOutflow_SplitAndTransactionTagFilters(t *testing.T) {
+	initializeTransactionSplitTestData(t)
+
+	c := core.NewNullContext()
+	transaction := &models.Transaction{Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1001, TransactionTime: 1700000000000, Amount: 3000}
+	splits := []*models.TransactionSplit{
+		{CategoryId: 101, Amount: 2000, TagIds: "201"},
+		{CategoryId: 102, Amount: 1000},
+	}
+
+	err := Transactions.CreateTransaction(c, transaction, []int64{202}, nil, splits)
+	assert.Nil(t, err)
+
+	totalAmounts, err := Transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, 1, 1690000000, 1710000000, []*models.TransactionTagFilter{{TagIds: []int64{201, 202}, Type: models.TRANSACTION_TAG_FILTER_HAS_ALL}}, false, "", time.UTC, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(totalAmounts))
+	assert.Equal(t, int64(101), totalAmounts[0].CategoryId)
+	assert.Equal(t, int64(2000), totalAmounts[0].Amount)
+
+	totalAmounts, err = Transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, 1, 1690000000, 1710000000, []*models.TransactionTagFilter{{TagIds: []int64{201}, Type: models.TRANSACTION_TAG_FILTER_NOT_HAS_ANY}}, false, "", time.UTC, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(totalAmounts))
+	assert.Equal(t, int64(102), totalAmounts[0].CategoryId)
+	assert.Equal(t, int64(1000), totalAmounts[0].Amount)
+
+	totalAmounts, err = Transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, 1, 1690000000, 1710000000, []*models.TransactionTagFilter{{TagIds: []int64{201, 202}, Type: models.TRANSACTION_TAG_FILTER_NOT_HAS_ALL}}, false, "", time.UTC, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(totalAmounts))
+	assert.Equal(t, int64(102), totalAmounts[0].CategoryId)
+
+	totalAmounts, err = Transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, 1, 1690000000, 1710000000, []*models.TransactionTagFilter{{TagIds: []int64{202}, Type: models.TRANSACTION_TAG_FILTER_HAS_ANY}}, false, "", time.UTC, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(totalAmounts))
+}
+
+func TestGetAccountsAndCategoriesPeriodicTotalInflowAndOutflow(t *testing.T) {
+	initializeTransactionSplitTestData(t)
+
+	c := core.NewNullContext()
+	transactionUnixTimes := []int64{1700000000, 1700100000, 1700200000}
+
+	for i := 0; i < len(transactionUnixTimes); i++ {
+		transaction := &models.Transaction{Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1001, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionUnixTimes[i]), Amount: int64(1000 * (i + 1))}
+		err := Transactions.CreateTransaction(c, transaction, nil, nil, nil)
+		assert.Nil(t, err)
+	}
+
+	periodTotalAmounts, err := Transactions.GetAccountsAndCategoriesPeriodicTotalInflowAndOutflow(c, 1, []int64{1700050000, 1700150000}, 1700300000, nil, false, "", time.UTC, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(periodTotalAmounts))
+	assert.Equal(t, 1, len(periodTotalAmounts[0]))
+	assert.Equal(t, int64(2000), periodTotalAmounts[0][0].Amount)
+	assert.Equal(t, 1, len(periodTotalAmounts[1]))
+	assert.Equal(t, int64(3000), periodTotalAmounts[1][0].Amount)
+
+	periodTotalAmounts, err = Transactions.GetAccountsAndCategoriesPeriodicTotalInflowAndOutflow(c, 1, []int64{1699990000, 1700050000, 1700150000}, 1700150000, nil, false, "", time.UTC, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(periodTotalAmounts))
+	assert.Equal(t, int64(1000), periodTotalAmounts[0][0].Amount)
+	assert.Equal(t, int64(2000), periodTotalAmounts[1][0].Amount)
+	assert.Equal(t, 0, len(periodTotalAmounts[2]))
+}
+
+func TestCreateTransaction_SplitCategoryAndTagCannotBeDeleted(t *testing.T) {
+	initializeTransactionSplitTestData(t)
+	createTestSplitTransaction(t)
+
+	c := core.NewNullContext()
+	err := TransactionCategories.DeleteCategory(c, 1, 102)
+	assert.Equal(t, errs.ErrTransactionCategoryInUseCannotBeDeleted, err)
+
+	err = TransactionTags.DeleteTag(c, 1, 201)
+	assert.Equal(t, errs.ErrTransactionTagInUseCannotBeDeleted, err)
+
+	err = TransactionTags.DeleteTag(c, 1, 202)
+	assert.Nil(t, err)
+}
+
+func TestModifyTransaction_ReplaceSplits(t *testing.T) {
+	initializeTransactionSplitTestData(t)
+	transaction := createTestSplitTransaction(t)
+
+	c := core.NewNullContext()
+	modifiedTransaction := &models.Transaction{TransactionId: transaction.TransactionId, Uid: 1, CategoryId: 101, AccountId: 1001, TransactionTime: transaction.TransactionTime, Amount: 4000}
+	splits := []*models.TransactionSplit{
+		{CategoryId: 102, Amount: 2500, TagIds: "202"},
+		{CategoryId: 101, Amount: 1500},
+	}
+
+	err := Transactions.ModifyTransaction(c, modifiedTransaction, 0, nil, nil, nil, nil, splits)
+	assert.Nil(t, err)
+
+	allSplits, err := TransactionSplits.GetAllSplitsOfTransactions(c, 1, []int64{transaction.TransactionId})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allSplits[transaction.TransactionId]))
+	assert.Equal(t, int64(102), allSplits[transaction.TransactionId][0].CategoryId)
+	assert.Equal(t, int64(2500), allSplits[transaction.TransactionId][0].Amount)
+
+	// the tag of the removed split line is no longer in use
+	err = TransactionTags.DeleteTag(c, 1, 201)
+	assert.Nil(t, err)
+
+	err = TransactionTags.DeleteTag(c, 1, 202)
+	assert.Equal(t, errs.ErrTransactionTagInUseCannotBeDeleted, err)
+
+	modifiedTransaction = &models.Transaction{TransactionId: transaction.TransactionId, Uid: 1, CategoryId: 101, AccountId: 1001, TransactionTime: transaction.TransactionTime, Amount: 5000}
+	err = Transactions.ModifyTransaction(c, modifiedTransaction, 0, nil, nil, nil, nil, nil)
+	assert.Equal(t, errs.ErrTransactionSplitsAmountNotEqualToTransactionAmount, err)
+}
+
+func TestModifyTransaction_LinkedInvestmentTradeMismatch(t *testing.T) {
+	initializeTransactionSplitTestData(t)
+
+	c := core.NewNullContext()
+	sess := Transactions.UserDataDB(1).NewSession(c)
+
+	_, err := sess.Insert(&models.Account{AccountId: 1002, Uid: 1, Name: "Brokerage", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "AAPL", Balance: 1000, Extend: &models.AccountExtend{AssetType: models.ACCOUNT_ASSET_TYPE_STOCK}})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Account{AccountId: 1003, Uid: 1, Name: "Another Brokerage", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "AAPL", Extend: &models.AccountExtend{AssetType: models.ACCOUNT_ASSET_TYPE_STOCK}})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{TransactionId: 2001, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 101, AccountId: 1002, TransactionTime: 1700000000000, Amount: 1000})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.InvestmentTrade{TradeId: 3001, Uid: 1, AccountId: 1002, Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1700000000, Symbol: "AAPL", Quantity: 1000, Amount: 100000, Currency: "USD", TransactionId: 2001})
+	assert.Nil(t, err)
+
+	// the quantity of the linked transaction no longer matches the trade
+	modifiedTransaction := &models.Transaction{TransactionId: 2001, Uid: 1, CategoryId: 101, AccountId: 1002, TransactionTime: 1700000000000, Amount: 500}
+	err = Transactions.ModifyTransaction(c, modifiedTransaction, 0, nil, nil, nil, nil, nil)
+	assert.Equal(t, errs.ErrInvestmentTradeTransactionMismatch, err)
+
+	// the linked transaction is moved out of the trade account
+	modifiedTransaction = &models.Transaction{TransactionId: 2001, Uid: 1, CategoryId: 101, AccountId: 1003, TransactionTime: 1700000000000, Amount: 1000}
+	err = Transactions.ModifyTransaction(c, modifiedTransaction, 0, nil, nil, nil, nil, nil)
+	assert.Equal(t, errs.ErrInvestmentTradeTransactionMismatch, err)
+
+	// the modification which does not change the account and the amount is still allowed
+	modifiedTransaction = &models.Transaction{TransactionId: 2001, Uid: 1, CategoryId: 101, AccountId: 1002, TransactionTime: 1700000000000, Amount: 1000, Comment: "Buy AAPL"}
+	err = Transactions.ModifyTransaction(c, modifiedTransaction, 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	transaction := &models.Transaction{}
+	_, err = sess.ID(2001).Get(transaction)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), transaction.Amount)
+	assert.Equal(t, "Buy AAPL", transaction.Comment)
+}
+
+func TestDeleteTransaction_WithSplits(t *testing.T) {
+	initializeTransactionSplitTestData(t)
+	transaction := createTestSplitTransaction(t)
+
+	c := core.NewNullContext()
+	err := Transactions.DeleteTransaction(c, 1, transaction.TransactionId)
+	assert.Nil(t, err)
+
+	allSplits, err := TransactionSplits.GetAllSplitsOfTransactions(c, 1, []int64{transaction.TransactionId})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(allSplits[transaction.TransactionId]))
+
+	err = TransactionCategories.DeleteCategory(c, 1, 102)
+	assert.Nil(t, err)
+
+	err = TransactionTags.DeleteTag(c, 1, 201)
+	assert.Nil(t, err)
+}
+
+func TestDeleteTransaction_UnlinkInvestmentTrades(t *testing.T) {
+	initializeTransactionSplitTestData(t)
+	transaction := createTestSplitTransaction(t)
+
+	c := core.NewNullContext()
+	sess := Transactions.UserDataDB(1).NewSession(c)
+
+	_, err := sess.Insert(&models.InvestmentTrade{TradeId: 3001, Uid: 1, AccountId: 1002, Symbol: "AAPL", Currency: "USD", Type: models.INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1000, Quantity: 1000, Amount: 3000, TransactionId: transaction.TransactionId})
+	assert.Nil(t, err)
+
+	err = Transactions.DeleteTransaction(c, 1, transaction.TransactionId)
+	assert.Nil(t, err)
+
+	trade, err := InvestmentTrades.GetTradeByTradeId(c, 1, 3001)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), trade.TransactionId)
+}
+
+func initializeWalletBalanceAdjustmentTestData(t *testing.T) {
+	initializeTestDataStore(t, new(models.Account), new(models.Transaction), new(models.TransactionCategory), new(models.TransactionTag), new(models.TransactionTagIndex), new(models.TransactionPictureInfo))
+
+	sess := Transactions.UserDataDB(1).NewSession(core.NewNullContext())
+
+	_, err := sess.Insert(&models.TransactionCategory{CategoryId: 100, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: models.LevelOneTransactionCategoryParentId, Name: "Daily Expense"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.TransactionCategory{CategoryId: 101, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 100, Name: "Food"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.TransactionCategory{CategoryId: 102, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 100, Name: "Household"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.TransactionCategory{CategoryId: 300, Uid: 1, Type: models.CATEGORY_TYPE_INCOME, ParentCategoryId: models.LevelOneTransactionCategoryParentId, Name: "Occupational Earnings"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.TransactionCategory{CategoryId: 301, Uid: 1, Type: models.CATEGORY_TYPE_INCOME, ParentCategoryId: 300, Name: "Investment Income"})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Account{AccountId: 1002, Uid: 1, Name: "Wallet", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "BTC", Extend: &models.AccountExtend{WalletAddress: "bc1qtest", WalletIncomeCategoryId: 301, WalletExpenseCategoryId: 102}})
+	assert.Nil(t, err)
+}
+
+func TestCreateWalletBalanceAdjustmentTransaction(t *testing.T) {
+	initializeWalletBalanceAdjustmentTestData(t)
+
+	c := core.NewNullContext()
+	account := &models.Account{}
+	_, err := Transactions.UserDataDB(1).NewSession(c).ID(1002).Get(account)
+	assert.Nil(t, err)
+
+	transaction, err := Transactions.CreateWalletBalanceAdjustmentTransaction(c, account, 5000, 1700000000)
+	assert.Nil(t, err)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, transaction.Type)
+	assert.Equal(t, int64(301), transaction.CategoryId)
+	assert.Equal(t, int64(5000), transaction.Amount)
+
+	transaction, err = Transactions.CreateWalletBalanceAdjustmentTransaction(c, account, 3000, 1700003600)
+	assert.Nil(t, err)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, transaction.Type)
+	assert.Equal(t, int64(102), transaction.CategoryId)
+	assert.Equal(t, int64(2000), transaction.Amount)
+
+	_, err = Transactions.UserDataDB(1).NewSession(c).ID(1002).Get(account)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3000), account.Balance)
+
+	_, err = Transactions.CreateWalletBalanceAdjustmentTransaction(c, account, 3000, 1700007200)
+	assert.Equal(t, errs.ErrNothingWillBeUpdated, err)
+
+	// backdated transactions can still be added after the wallet balance is adjusted
+	backdatedTransaction := &models.Transaction{Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1002, TransactionTime: 1600000000000, Amount: 1000}
+	err = Transactions.CreateTransaction(c, backdatedTransaction, nil, nil, nil)
+	assert.Nil(t, err)
+}
+
+func TestCreateWalletBalanceAdjustmentTransaction_CategoryNotSet(t *testing.T) {
+	initializeWalletBalanceAdjustmentTestData(t)
+
+	c := core.NewNullContext()
+	_, err := Transactions.UserDataDB(1).NewSession(c).ID(1002).Cols("extend").Update(&models.Account{Extend: &models.AccountExtend{WalletAddress: "bc1qtest", WalletExpenseCategoryId: 102}})
+	assert.Nil(t, err)
+
+	account := &models.Account{}
+	_, err = Transactions.UserDataDB(1).NewSession(c).ID(1002).Get(account)
+	assert.Nil(t, err)
+
+	_, err = Transactions.CreateWalletBalanceAdjustmentTransaction(c, account, 5000, 1700000000)
+	assert.Equal(t, errs.ErrWalletBalanceCategoryRequired, err)
+}
@@ -8,11 +8,14 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
 )
 
 // UserCustomExchangeRatesService represents user custom exchange rate data service
 type UserCustomExchangeRatesService struct {
 	ServiceUsingDB
+	ServiceUsingUuid
 }
 
 // Initialize a user custom exchange rate data service singleton instance
@@ -21,6 +24,9 @@ var (
 		ServiceUsingDB: ServiceUsingDB{
 			container: datastore.Container,
 		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
 	}
 )
 
@@ -141,6 +147,11 @@ func (s *UserCustomExchangeRatesService) DeleteAllCustomExchangeRates(c core.Con
 		DeletedUnixTime: now,
 	}
 
+	updateDatedModel := &models.UserCustomDatedExchangeRate{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		_, err := sess.Cols("deleted_unix_time").Where("uid=? AND deleted_unix_time=?", uid, 0).Update(updateModel)
 
@@ -148,6 +159,122 @@ func (s *UserCustomExchangeRatesService) DeleteAllCustomExchangeRates(c core.Con
 			return err
 		}
 
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateDatedModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// GetAllDatedExchangeRatesByUid returns all user custom dated exchange rate models of user, only the rates of the specified currency would be returned if the currency is not empty
+func (s *UserCustomExchangeRatesService) GetAllDatedExchangeRatesByUid(c core.Context, uid int64, currency string) ([]*models.UserCustomDatedExchangeRate, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND deleted=?"
+	conditionParams := []any{uid, false}
+
+	if currency != "" {
+		condition = condition + " AND currency=?"
+		conditionParams = append(conditionParams, currency)
+	}
+
+	var datedExchangeRates []*models.UserCustomDatedExchangeRate
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("currency asc, effective_start_time asc").Find(&datedExchangeRates)
+
+	return datedExchangeRates, err
+}
+
+// GetEffectiveDatedExchangeRates returns the user custom dated exchange rate models of user which are effective at the specified unix time
+func (s *UserCustomExchangeRatesService) GetEffectiveDatedExchangeRates(c core.Context, uid int64, unixTime int64) ([]*models.UserCustomDatedExchangeRate, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var datedExchangeRates []*models.UserCustomDatedExchangeRate
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND effective_start_time<=? AND (effective_end_time=? OR effective_end_time>=?)", uid, false, unixTime, 0, unixTime).Find(&datedExchangeRates)
+
+	return datedExchangeRates, err
+}
+
+// CreateDatedExchangeRate saves a new user custom dated exchange rate model to database
+func (s *UserCustomExchangeRatesService) CreateDatedExchangeRate(c core.Context, datedExchangeRate *models.UserCustomDatedExchangeRate) error {
+	if datedExchangeRate.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if datedExchangeRate.EffectiveEndTime > 0 && datedExchangeRate.EffectiveEndTime < datedExchangeRate.EffectiveStartTime {
+		return errs.ErrUserCustomDatedExchangeRateInvalidTimeRange
+	}
+
+	rate, err := utils.StringToFloat64(datedExchangeRate.Rate)
+
+	if err != nil || rate <= 0 {
+		return errs.ErrUserCustomDatedExchangeRateInvalidRate
+	}
+
+	datedExchangeRate.RateId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+
+	if datedExchangeRate.RateId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	datedExchangeRate.Deleted = false
+	datedExchangeRate.CreatedUnixTime = time.Now().Unix()
+	datedExchangeRate.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(datedExchangeRate.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		condition := "uid=? AND deleted=? AND currency=? AND (effective_end_time=? OR effective_end_time>=?)"
+		conditionParams := []any{datedExchangeRate.Uid, false, datedExchangeRate.Currency, 0, datedExchangeRate.EffectiveStartTime}
+
+		if datedExchangeRate.EffectiveEndTime > 0 {
+			condition = condition + " AND effective_start_time<=?"
+			conditionParams = append(conditionParams, datedExchangeRate.EffectiveEndTime)
+		}
+
+		overlappedCount, err := sess.Where(condition, conditionParams...).Count(&models.UserCustomDatedExchangeRate{})
+
+		if err != nil {
+			return err
+		} else if overlappedCount > 0 {
+			return errs.ErrUserCustomDatedExchangeRateOverlapped
+		}
+
+		_, err = sess.Insert(datedExchangeRate)
+		return err
+	})
+}
+
+// DeleteDatedExchangeRate deletes an existed user custom dated exchange rate from database
+func (s *UserCustomExchangeRatesService) DeleteDatedExchangeRate(c core.Context, uid int64, rateId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if rateId <= 0 {
+		return errs.ErrUserCustomDatedExchangeRateIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.UserCustomDatedExchangeRate{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(rateId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrUserCustomDatedExchangeRateNotFound
+		}
+
 		return nil
 	})
 }
@@ -141,12 +141,23 @@ const (
 	NationalBankOfUkraineDataSource     string = "national_bank_of_ukraine"
 	CentralBankOfUzbekistanDataSource   string = "central_bank_of_uzbekistan"
 	InternationalMonetaryFundDataSource string = "international_monetary_fund"
+	FederalReserveDataSource            string = "federal_reserve"
+	BankOfEnglandDataSource             string = "bank_of_england"
+	BankOfJapanDataSource               string = "bank_of_japan"
+	ReserveBankOfIndiaDataSource        string = "reserve_bank_of_india"
+	PeoplesBankOfChinaDataSource        string = "peoples_bank_of_china"
+	BankOfThailandDataSource            string = "bank_of_thailand"
 	UserCustomExchangeRatesDataSource   string = "user_custom"
 )
 
 // Cryptocurrency data source types
 const (
-	CoinGeckoDataSource string = "coingecko"
+	CoinGeckoDataSource               string = "coingecko"
+	BinanceDataSource                 string = "binance"
+	CoinbaseDataSource                string = "coinbase"
+	KrakenDataSource                  string = "kraken"
+	CoinMarketCapDataSource           string = "coinmarketcap"
+	LocalFileCryptocurrencyDataSource string = "local_file"
 )
 
 // Stock data source types
@@ -156,6 +167,12 @@ const (
 	FinancialModelingPrepDataSource string = "financial_modeling_prep"
 	TencentFinanceDataSource        string = "tencent_finance"
 	SinaFinanceDataSource           string = "sina_finance"
+	LocalFileStockDataSource        string = "local_file"
+)
+
+// Wallet balance reader types
+const (
+	JsonRpcWalletBalanceReader string = "json_rpc"
 )
 
 const (
@@ -358,6 +375,7 @@ type Config struct {
 	EnableAutoUpdateCryptocurrencyPrices bool
 	EnableAutoUpdateStockPrices          bool
 	EnableAutoUpdateExchangeRates        bool
+	EnableSyncWalletBalances             bool
 
 	// Secret
 	SecretKeyNoSet                        bool
@@ -408,6 +426,7 @@ type Config struct {
 	AvatarProvider                core.UserAvatarProviderType
 	MaxAvatarFileSize             uint32
 	DefaultFeatureRestrictions    core.UserFeatureRestrictions
+	Administrators                []string
 
 	// Data
 	EnableDataExport  bool
@@ -442,26 +461,41 @@ type Config struct {
 
 	// Exchange Rates
 	ExchangeRatesDataSource                       string
+	ExchangeRatesDataSources                      []string
 	ExchangeRatesRequestTimeout                   uint32
 	ExchangeRatesRequestTimeoutExceedDefaultValue bool
 	ExchangeRatesProxy                            string
 	ExchangeRatesSkipTLSVerify                    bool
+	ExchangeRatesBankOfThailandAPIKey             string
 
 	// Cryptocurrency
-	CryptocurrencyDataSource     string
-	CryptocurrencySymbols        []string
-	CryptocurrencyRequestTimeout uint32
-	CryptocurrencyProxy          string
-	CryptocurrencySkipTLSVerify  bool
-	CryptocurrencyAPIKey         string
+	CryptocurrencyDataSource        string
+	CryptocurrencySymbols           []string
+	CryptocurrencyRequestTimeout    uint32
+	CryptocurrencyProxy             string
+	CryptocurrencySkipTLSVerify     bool
+	CryptocurrencyAPIKey            string
+	CryptocurrencyPriceFeedLocation string
 
 	// Stocks
-	StockDataSource     string
-	StockSymbols        []string
-	StockRequestTimeout uint32
-	StockProxy          string
-	StockSkipTLSVerify  bool
-	StockAPIKey         string
+	StockDataSource        string
+	StockSymbols           []string
+	StockRequestTimeout    uint32
+	StockProxy             string
+	StockSkipTLSVerify     bool
+	StockAPIKey            string
+	StockPriceFeedLocation string
+
+	// Wallet Balance
+	WalletBalanceReader            string
+	WalletBalanceRequestTimeout    uint32
+	WalletBalanceProxy             string
+	WalletBalanceSkipTLSVerify     bool
+	WalletBalanceJsonRpcUrl        string
+	WalletBalanceJsonRpcMethod     string
+	WalletBalanceJsonRpcParams     []string
+	WalletBalanceJsonRpcDecimals   uint8
+	WalletBalanceJsonRpcCurrencies []string
 }
 
 // LoadConfiguration loads setting config from given config file path
@@ -615,6 +649,12 @@ func LoadConfiguration(configFilePath string) (*Config, error) {
 		return nil, err
 	}
 
+	err = loadWalletBalanceConfiguration(config, cfgFile, "wallet_balance")
+
+	if err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -975,6 +1015,7 @@ func loadCronConfiguration(config *Config, configFile *ini.File, sectionName str
 	config.EnableAutoUpdateCryptocurrencyPrices = getConfigItemBoolValue(configFile, sectionName, "enable_auto_update_cryptocurrency_prices", false)
 	config.EnableAutoUpdateStockPrices = getConfigItemBoolValue(configFile, sectionName, "enable_auto_update_stock_prices", false)
 	config.EnableAutoUpdateExchangeRates = getConfigItemBoolValue(configFile, sectionName, "enable_auto_update_exchange_rates", false)
+	config.EnableSyncWalletBalances = getConfigItemBoolValue(configFile, sectionName, "enable_sync_wallet_balances", false)
 
 	return nil
 }
@@ -1108,6 +1149,15 @@ func loadUserConfiguration(config *Config, configFile *ini.File, sectionName str
 
 	config.MaxAvatarFileSize = getConfigItemUint32Value(configFile, sectionName, "max_user_avatar_size", defaultUserAvatarFileMaxSize)
 	config.DefaultFeatureRestrictions = core.ParseUserFeatureRestrictions(getConfigItemStringValue(configFile, sectionName, "default_feature_restrictions", ""))
+	config.Administrators = nil
+
+	for _, username := range strings.Split(getConfigItemStringValue(configFile, sectionName, "administrators"), ",") {
+		username = strings.TrimSpace(username)
+
+		if username != "" {
+			config.Administrators = append(config.Administrators, username)
+		}
+	}
 
 	return nil
 }
@@ -1199,9 +1249,55 @@ func loadMapConfiguration(config *Config, configFile *ini.File, sectionName stri
 	return nil
 }
 func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectionName string) error {
-	dataSource := getConfigItemStringValue(configFile, sectionName, "data_source")
+	config.ExchangeRatesDataSource = ""
+	config.ExchangeRatesDataSources = nil
+	addedDataSources := make(map[string]bool)
+
+	for _, dataSource := range strings.Split(getConfigItemStringValue(configFile, sectionName, "data_source"), ",") {
+		dataSource = strings.TrimSpace(dataSource)
+
+		if dataSource == "" {
+			continue
+		}
+
+		if !isValidExchangeRatesDataSource(dataSource) {
+			return errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		if addedDataSources[dataSource] {
+			continue
+		}
+
+		addedDataSources[dataSource] = true
+		config.ExchangeRatesDataSources = append(config.ExchangeRatesDataSources, dataSource)
+	}
+
+	if len(config.ExchangeRatesDataSources) < 1 {
+		return errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	// user custom exchange rates cannot be used with other data sources in the fallback chain
+	if len(config.ExchangeRatesDataSources) > 1 && addedDataSources[UserCustomExchangeRatesDataSource] {
+		return errs.ErrInvalidExchangeRatesDataSource
+	}
 
-	if dataSource == ReserveBankOfAustraliaDataSource ||
+	config.ExchangeRatesDataSource = config.ExchangeRatesDataSources[0]
+
+	config.ExchangeRatesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
+	config.ExchangeRatesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout)
+
+	if config.ExchangeRatesRequestTimeout > defaultExchangeRatesDataRequestTimeout {
+		config.ExchangeRatesRequestTimeoutExceedDefaultValue = true
+	}
+
+	config.ExchangeRatesSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
+	config.ExchangeRatesBankOfThailandAPIKey = getConfigItemStringValue(configFile, sectionName, "bank_of_thailand_api_key")
+
+	return nil
+}
+
+func isValidExchangeRatesDataSource(dataSource string) bool {
+	return dataSource == ReserveBankOfAustraliaDataSource ||
 		dataSource == BankOfCanadaDataSource ||
 		dataSource == CzechNationalBankDataSource ||
 		dataSource == DanmarksNationalbankDataSource ||
@@ -1218,22 +1314,13 @@ func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectio
 		dataSource == NationalBankOfUkraineDataSource ||
 		dataSource == CentralBankOfUzbekistanDataSource ||
 		dataSource == InternationalMonetaryFundDataSource ||
-		dataSource == UserCustomExchangeRatesDataSource {
-		config.ExchangeRatesDataSource = dataSource
-	} else {
-		return errs.ErrInvalidExchangeRatesDataSource
-	}
-
-	config.ExchangeRatesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
-	config.ExchangeRatesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout)
-
-	if config.ExchangeRatesRequestTimeout > defaultExchangeRatesDataRequestTimeout {
-		config.ExchangeRatesRequestTimeoutExceedDefaultValue = true
-	}
-
-	config.ExchangeRatesSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
-
-	return nil
+		dataSource == FederalReserveDataSource ||
+		dataSource == BankOfEnglandDataSource ||
+		dataSource == BankOfJapanDataSource ||
+		dataSource == ReserveBankOfIndiaDataSource ||
+		dataSource == PeoplesBankOfChinaDataSource ||
+		dataSource == BankOfThailandDataSource ||
+		dataSource == UserCustomExchangeRatesDataSource
 }
 
 func getWorkingPath() (string, error) {
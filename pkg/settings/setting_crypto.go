@@ -13,7 +13,7 @@ func loadCryptocurrencyConfiguration(config *Config, configFile *ini.File, secti
 
 	if dataSource == "" {
 		config.CryptocurrencyDataSource = ""
-	} else if dataSource == CoinGeckoDataSource {
+	} else if dataSource == CoinGeckoDataSource || dataSource == BinanceDataSource || dataSource == CoinbaseDataSource || dataSource == KrakenDataSource || dataSource == CoinMarketCapDataSource || dataSource == LocalFileCryptocurrencyDataSource {
 		config.CryptocurrencyDataSource = dataSource
 	} else {
 		return errs.ErrInvalidCryptocurrencyDataSource
@@ -31,6 +31,7 @@ func loadCryptocurrencyConfiguration(config *Config, configFile *ini.File, secti
 	config.CryptocurrencyProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
 	config.CryptocurrencySkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
 	config.CryptocurrencyAPIKey = getConfigItemStringValue(configFile, sectionName, "api_key")
+	config.CryptocurrencyPriceFeedLocation = getConfigItemStringValue(configFile, sectionName, "price_feed_location")
 
 	return nil
 }
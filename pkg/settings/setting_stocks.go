@@ -13,7 +13,7 @@ func loadStockConfiguration(config *Config, configFile *ini.File, sectionName st
 
 	if dataSource == "" {
 		config.StockDataSource = ""
-	} else if dataSource == YahooFinanceDataSource || dataSource == AlphaVantageDataSource || dataSource == FinancialModelingPrepDataSource || dataSource == TencentFinanceDataSource || dataSource == SinaFinanceDataSource {
+	} else if dataSource == YahooFinanceDataSource || dataSource == AlphaVantageDataSource || dataSource == FinancialModelingPrepDataSource || dataSource == TencentFinanceDataSource || dataSource == SinaFinanceDataSource || dataSource == LocalFileStockDataSource {
 		config.StockDataSource = dataSource
 	} else {
 		return errs.ErrInvalidStockDataSource
@@ -31,6 +31,7 @@ func loadStockConfiguration(config *Config, configFile *ini.File, sectionName st
 	config.StockProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
 	config.StockSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
 	config.StockAPIKey = getConfigItemStringValue(configFile, sectionName, "api_key")
+	config.StockPriceFeedLocation = getConfigItemStringValue(configFile, sectionName, "price_feed_location")
 
 	return nil
 }
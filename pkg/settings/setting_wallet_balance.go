@@ -0,0 +1,65 @@
+package settings
+
+import (
+	"strings"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+const (
+	defaultWalletBalanceJsonRpcMethod     string = "eth_getBalance"
+	defaultWalletBalanceJsonRpcParams     string = "{address},latest"
+	defaultWalletBalanceJsonRpcDecimals   uint8  = 18
+	defaultWalletBalanceJsonRpcCurrencies string = "ETH"
+)
+
+func loadWalletBalanceConfiguration(config *Config, configFile *ini.File, sectionName string) error {
+	reader := getConfigItemStringValue(configFile, sectionName, "reader")
+
+	if reader == "" {
+		config.WalletBalanceReader = ""
+	} else if reader == JsonRpcWalletBalanceReader {
+		config.WalletBalanceReader = reader
+	} else {
+		return errs.ErrInvalidWalletBalanceReader
+	}
+
+	config.WalletBalanceRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout) // Reuse exchange rates default timeout
+	config.WalletBalanceProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
+	config.WalletBalanceSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
+
+	config.WalletBalanceJsonRpcUrl = getConfigItemStringValue(configFile, sectionName, "json_rpc_url")
+	config.WalletBalanceJsonRpcMethod = getConfigItemStringValue(configFile, sectionName, "json_rpc_method", defaultWalletBalanceJsonRpcMethod)
+	config.WalletBalanceJsonRpcParams = splitWalletBalanceConfigItemValues(getConfigItemStringValue(configFile, sectionName, "json_rpc_params", defaultWalletBalanceJsonRpcParams), false)
+	config.WalletBalanceJsonRpcDecimals = getConfigItemUint8Value(configFile, sectionName, "json_rpc_decimals", defaultWalletBalanceJsonRpcDecimals)
+	config.WalletBalanceJsonRpcCurrencies = splitWalletBalanceConfigItemValues(getConfigItemStringValue(configFile, sectionName, "json_rpc_currencies", defaultWalletBalanceJsonRpcCurrencies), true)
+
+	if config.WalletBalanceReader == JsonRpcWalletBalanceReader && config.WalletBalanceJsonRpcUrl == "" {
+		return errs.ErrInvalidWalletBalanceReader
+	}
+
+	return nil
+}
+
+func splitWalletBalanceConfigItemValues(value string, upperCase bool) []string {
+	items := strings.Split(value, ",")
+	values := make([]string, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		item := strings.TrimSpace(items[i])
+
+		if item == "" {
+			continue
+		}
+
+		if upperCase {
+			item = strings.ToUpper(item)
+		}
+
+		values = append(values, item)
+	}
+
+	return values
+}
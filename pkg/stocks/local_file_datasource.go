@@ -0,0 +1,52 @@
+package stocks
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/pricefeed"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// LocalFileStockPriceDataProvider defines the structure of stock price data provider which reads prices from local price feed
+type LocalFileStockPriceDataProvider struct {
+	container *settings.ConfigContainer
+}
+
+// NewLocalFileStockPriceDataProvider returns a new local file stock price data provider
+func NewLocalFileStockPriceDataProvider(container *settings.ConfigContainer) *LocalFileStockPriceDataProvider {
+	return &LocalFileStockPriceDataProvider{
+		container: container,
+	}
+}
+
+// GetLatestStockPrices returns the latest stock prices which are read from the price feed location in the configuration file
+func (p *LocalFileStockPriceDataProvider) GetLatestStockPrices(c core.Context, uid int64, config *models.ExternalDataSourceConfig, symbols []string) (*models.LatestStockPriceResponse, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	currentConfig := p.container.GetCurrentConfig()
+	reader := pricefeed.NewPriceFeedReader(currentConfig.StockPriceFeedLocation, config, currentConfig.StockRequestTimeout, currentConfig.StockProxy, currentConfig.StockSkipTLSVerify)
+	latestPrices, err := reader.ReadLatestPrices(c, config, symbols, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.LatestStockPriceResponse{
+		DataSource:   settings.LocalFileStockDataSource,
+		BaseCurrency: latestPrices.BaseCurrency,
+		UpdateTime:   latestPrices.UpdateTime,
+		Prices:       make(models.LatestStockPriceSlice, len(latestPrices.Prices)),
+	}
+
+	for i := 0; i < len(latestPrices.Prices); i++ {
+		response.Prices[i] = &models.LatestStockPrice{
+			Symbol:   latestPrices.Prices[i].Symbol,
+			Price:    latestPrices.Prices[i].Price,
+			Currency: latestPrices.Prices[i].Currency,
+		}
+	}
+
+	return response, nil
+}
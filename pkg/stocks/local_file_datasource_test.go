@@ -0,0 +1,39 @@
+package stocks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func TestLocalFileStockPriceDataProviderGetLatestStockPrices(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "prices.csv")
+	assert.Nil(t, os.WriteFile(filePath, []byte("symbol,price,currency,update_time\nAAPL,180,,1700000000\n7203,2800,JPY,1700000100\nMSFT,370,USD,1700000200\n"), 0644))
+
+	settings.SetCurrentConfig(&settings.Config{
+		StockPriceFeedLocation: filePath,
+	})
+
+	provider := NewLocalFileStockPriceDataProvider(settings.Container)
+	response, err := provider.GetLatestStockPrices(core.NewNullContext(), 0, &models.ExternalDataSourceConfig{DataSource: settings.LocalFileStockDataSource}, []string{"aapl", "7203", "GOOG"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, settings.LocalFileStockDataSource, response.DataSource)
+	assert.Equal(t, "USD", response.BaseCurrency)
+	assert.Equal(t, int64(1700000100), response.UpdateTime)
+	assert.Equal(t, 2, len(response.Prices))
+
+	assert.Equal(t, "AAPL", response.Prices[0].Symbol)
+	assert.Equal(t, "180", response.Prices[0].Price)
+	assert.Equal(t, "USD", response.Prices[0].Currency)
+
+	assert.Equal(t, "7203", response.Prices[1].Symbol)
+	assert.Equal(t, "2800", response.Prices[1].Price)
+	assert.Equal(t, "JPY", response.Prices[1].Currency)
+}
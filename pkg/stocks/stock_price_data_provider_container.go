@@ -1,36 +1,36 @@
 package stocks
 
 import (
+	"strings"
 	"sync"
 	"time"
 
-	"golang.org/x/sync/singleflight"
-
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/pricecache"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 const (
-	stockPriceCacheTimeout = 5 * time.Minute
+	stockPriceCacheTimeout   = 5 * time.Minute
+	stockPriceFetchBatchSize = 50
 )
 
-// StockPriceDataProviderContainer contains the stock price data provider
+// StockPriceDataProviderContainer contains the stock price data providers
 type StockPriceDataProviderContainer struct {
-	Current      StockPriceDataProvider
-	CurrentType  string
-	IsEnabled    bool
-	lastResult   *models.LatestStockPriceResponse
-	lastTime     time.Time
-	mu           sync.RWMutex
-	requestGroup singleflight.Group
+	providers map[string]StockPriceDataProvider
+	cache     *pricecache.SymbolPriceCache
+	mu        sync.Mutex
 }
 
 // Initialize a stock price data provider container singleton instance
 var (
-	Container = &StockPriceDataProviderContainer{}
+	Container = &StockPriceDataProviderContainer{
+		providers: make(map[string]StockPriceDataProvider),
+		cache:     pricecache.NewSymbolPriceCache("stock", stockPriceCacheTimeout, stockPriceFetchBatchSize),
+	}
 )
 
 // InitializeStockPriceDataProvider initializes the stock price data provider
@@ -39,72 +39,182 @@ func InitializeStockPriceDataProvider(config *settings.Config) error {
 	return nil
 }
 
-// GetLatestStockPrices returns the latest stock prices
-func (c *StockPriceDataProviderContainer) GetLatestStockPrices(ctx core.Context, uid int64, config *models.ExternalDataSourceConfig, symbols []string) (*models.LatestStockPriceResponse, error) {
-	if config == nil {
+// GetLatestStockPrices returns the latest stock prices, each symbol would be requested from the data sources of its market in fallback order
+// and the results of all data sources would be merged
+func (c *StockPriceDataProviderContainer) GetLatestStockPrices(ctx core.Context, uid int64, routing *models.StockDataSourceRouting, symbols []string) (*models.LatestStockPriceResponse, error) {
+	if routing == nil || len(routing.Configs) < 1 {
 		return nil, errs.ErrStockServiceNotEnabled
 	}
 
-	c.mu.Lock()
-	if c.CurrentType != config.DataSource {
-		var provider StockPriceDataProvider
-
-		switch config.DataSource {
-		case settings.YahooFinanceDataSource:
-			provider = NewCommonHttpStockPriceDataProvider(&YahooFinanceDataSource{})
-		case settings.AlphaVantageDataSource:
-			provider = NewCommonHttpStockPriceDataProvider(&AlphaVantageDataSource{})
-		case settings.FinancialModelingPrepDataSource:
-			provider = NewCommonHttpStockPriceDataProvider(&FMPDataSource{})
-		case settings.TencentFinanceDataSource:
-			provider = NewCommonHttpStockPriceDataProvider(&TencentFinanceDataSource{})
-		case settings.SinaFinanceDataSource:
-			provider = NewCommonHttpStockPriceDataProvider(&SinaFinanceDataSource{})
-		default:
-			c.mu.Unlock()
-			return nil, errs.ErrInvalidStockDataSource
+	pendingSymbols := make(map[string][]*models.ExternalDataSourceConfig, len(symbols))
+
+	for i := 0; i < len(symbols); i++ {
+		symbol := strings.ToUpper(strings.TrimSpace(symbols[i]))
+
+		if symbol == "" {
+			continue
 		}
 
-		c.Current = provider
-		c.CurrentType = config.DataSource
-		c.IsEnabled = true
+		pendingSymbols[symbol] = routing.GetDataSourceConfigs(symbol)
 	}
 
-	provider := c.Current
-	c.mu.Unlock()
+	allPrices := make([]*pricecache.CachedPrice, 0, len(pendingSymbols))
+	var lastErr error
+
+	for len(pendingSymbols) > 0 {
+		dataSourceSymbols := make(map[string][]string)
+		dataSourceConfigs := make(map[string]*models.ExternalDataSourceConfig)
+		dataSourceOrder := make([]string, 0)
+
+		for symbol, configs := range pendingSymbols {
+			if len(configs) < 1 {
+				delete(pendingSymbols, symbol)
+				continue
+			}
+
+			dataSource := configs[0].DataSource
+
+			if _, exists := dataSourceConfigs[dataSource]; !exists {
+				dataSourceConfigs[dataSource] = configs[0]
+				dataSourceOrder = append(dataSourceOrder, dataSource)
+			}
+
+			dataSourceSymbols[dataSource] = append(dataSourceSymbols[dataSource], symbol)
+		}
 
-	c.mu.RLock()
-	if c.lastResult != nil && time.Since(c.lastTime) < stockPriceCacheTimeout {
-		result := c.lastResult
-		c.mu.RUnlock()
-		return result, nil
+		for i := 0; i < len(dataSourceOrder); i++ {
+			dataSource := dataSourceOrder[i]
+			requestSymbols := dataSourceSymbols[dataSource]
+			prices, err := c.getLatestStockPricesFromDataSource(ctx, uid, dataSourceConfigs[dataSource], requestSymbols)
+
+			if err != nil {
+				log.Warnf(ctx, "[stocks.Container] failed to get latest prices from \"%s\", because %s", dataSource, err.Error())
+				lastErr = err
+			}
+
+			for j := 0; j < len(prices); j++ {
+				if _, exists := pendingSymbols[prices[j].Symbol]; exists {
+					allPrices = append(allPrices, prices[j])
+					delete(pendingSymbols, prices[j].Symbol)
+				}
+			}
+
+			// the symbols which are not returned would be requested from the next data source
+			for j := 0; j < len(requestSymbols); j++ {
+				if configs, exists := pendingSymbols[requestSymbols[j]]; exists {
+					pendingSymbols[requestSymbols[j]] = configs[1:]
+				}
+			}
+		}
+	}
+
+	if len(allPrices) < 1 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	result := &models.LatestStockPriceResponse{
+		Prices: make(models.LatestStockPriceSlice, 0, len(allPrices)),
 	}
-	c.mu.RUnlock()
 
-	result, err, _ := c.requestGroup.Do("GetLatestStockPrices", func() (interface{}, error) {
-		res, fetchErr := provider.GetLatestStockPrices(ctx, uid, config, symbols)
+	for i := 0; i < len(allPrices); i++ {
+		cachedPrice := allPrices[i]
 
-		if fetchErr == nil {
-			c.mu.Lock()
-			c.lastResult = res
-			c.lastTime = time.Now()
-			c.mu.Unlock()
+		if result.DataSource == "" {
+			result.DataSource = cachedPrice.DataSource
+			result.ReferenceUrl = cachedPrice.ReferenceUrl
+			result.BaseCurrency = cachedPrice.Currency
 		}
 
-		return res, fetchErr
-	})
+		if cachedPrice.UpdateTime > result.UpdateTime {
+			result.UpdateTime = cachedPrice.UpdateTime
+		}
+
+		result.Prices = append(result.Prices, &models.LatestStockPrice{
+			Symbol:     cachedPrice.Symbol,
+			Price:      cachedPrice.Price,
+			Currency:   cachedPrice.Currency,
+			DataSource: cachedPrice.DataSource,
+		})
+	}
+
+	return result, nil
+}
+
+func (c *StockPriceDataProviderContainer) getLatestStockPricesFromDataSource(ctx core.Context, uid int64, config *models.ExternalDataSourceConfig, symbols []string) ([]*pricecache.CachedPrice, error) {
+	provider, err := c.getProvider(config.DataSource)
 
-	if err == nil {
-		return result.(*models.LatestStockPriceResponse), nil
+	if err != nil {
+		return nil, err
 	}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	return c.cache.GetPrices(ctx, config.DataSource, config.BaseCurrency, symbols, func(missingSymbols []string) ([]*pricecache.CachedPrice, error) {
+		response, err := provider.GetLatestStockPrices(ctx, uid, config, missingSymbols)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if response == nil {
+			return nil, nil
+		}
+
+		prices := make([]*pricecache.CachedPrice, 0, len(response.Prices))
+
+		for i := 0; i < len(response.Prices); i++ {
+			price := response.Prices[i]
+
+			if price == nil {
+				continue
+			}
+
+			currency := price.Currency
+
+			if currency == "" {
+				currency = response.BaseCurrency
+			}
 
-	if c.lastResult != nil {
-		log.Warnf(ctx, "[stocks.Container] failed to get latest prices, using stale cache from %s", c.lastTime.Format("2006-01-02 15:04:05"))
-		return c.lastResult, nil
+			prices = append(prices, &pricecache.CachedPrice{
+				Symbol:       strings.ToUpper(price.Symbol),
+				Price:        price.Price,
+				Currency:     currency,
+				DataSource:   config.DataSource,
+				ReferenceUrl: response.ReferenceUrl,
+				UpdateTime:   response.UpdateTime,
+			})
+		}
+
+		return prices, nil
+	})
+}
+
+func (c *StockPriceDataProviderContainer) getProvider(dataSource string) (StockPriceDataProvider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if provider, exists := c.providers[dataSource]; exists {
+		return provider, nil
 	}
 
-	return nil, err
+	var provider StockPriceDataProvider
+
+	switch dataSource {
+	case settings.YahooFinanceDataSource:
+		provider = NewCommonHttpStockPriceDataProvider(&YahooFinanceDataSource{})
+	case settings.AlphaVantageDataSource:
+		provider = NewCommonHttpStockPriceDataProvider(&AlphaVantageDataSource{})
+	case settings.FinancialModelingPrepDataSource:
+		provider = NewCommonHttpStockPriceDataProvider(&FMPDataSource{})
+	case settings.TencentFinanceDataSource:
+		provider = NewCommonHttpStockPriceDataProvider(&TencentFinanceDataSource{})
+	case settings.SinaFinanceDataSource:
+		provider = NewCommonHttpStockPriceDataProvider(&SinaFinanceDataSource{})
+	case settings.LocalFileStockDataSource:
+		provider = NewLocalFileStockPriceDataProvider(settings.Container)
+	default:
+		return nil, errs.ErrInvalidStockDataSource
+	}
+
+	c.providers[dataSource] = provider
+
+	return provider, nil
 }
@@ -0,0 +1,146 @@
+package stocks
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/pricecache"
+)
+
+type testStockPriceDataProvider struct {
+	prices           map[string]string
+	err              error
+	requestedSymbols [][]string
+}
+
+func (p *testStockPriceDataProvider) GetLatestStockPrices(c core.Context, uid int64, config *models.ExternalDataSourceConfig, symbols []string) (*models.LatestStockPriceResponse, error) {
+	sortedSymbols := make([]string, len(symbols))
+	copy(sortedSymbols, symbols)
+	sort.Strings(sortedSymbols)
+	p.requestedSymbols = append(p.requestedSymbols, sortedSymbols)
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	response := &models.LatestStockPriceResponse{
+		DataSource:   config.DataSource,
+		BaseCurrency: "USD",
+		UpdateTime:   time.Now().Unix(),
+	}
+
+	for i := 0; i < len(symbols); i++ {
+		if price, exists := p.prices[symbols[i]]; exists {
+			response.Prices = append(response.Prices, &models.LatestStockPrice{
+				Symbol: symbols[i],
+				Price:  price,
+			})
+		}
+	}
+
+	return response, nil
+}
+
+func getTestStockPriceDataProviderContainer(providers map[string]StockPriceDataProvider) *StockPriceDataProviderContainer {
+	return &StockPriceDataProviderContainer{
+		providers: providers,
+		cache:     pricecache.NewSymbolPriceCache("stock", time.Minute, 10),
+	}
+}
+
+func getTestStockPriceMap(response *models.LatestStockPriceResponse) map[string]*models.LatestStockPrice {
+	prices := make(map[string]*models.LatestStockPrice, len(response.Prices))
+
+	for i := 0; i < len(response.Prices); i++ {
+		prices[response.Prices[i].Symbol] = response.Prices[i]
+	}
+
+	return prices
+}
+
+func TestStockPriceDataProviderContainerGetLatestStockPrices_RouteByMarket(t *testing.T) {
+	primary := &testStockPriceDataProvider{prices: map[string]string{"AAPL": "200"}}
+	secondary := &testStockPriceDataProvider{prices: map[string]string{"600519": "1500", "AAPL": "199"}}
+	container := getTestStockPriceDataProviderContainer(map[string]StockPriceDataProvider{
+		"primary":   primary,
+		"secondary": secondary,
+	})
+	routing := &models.StockDataSourceRouting{
+		Configs:           []*models.ExternalDataSourceConfig{{DataSource: "primary"}, {DataSource: "secondary"}},
+		MarketDataSources: map[string][]string{"CN": {"secondary"}},
+		SymbolMarkets:     map[string][]string{"600519": {"CN"}, "AAPL": {"US"}},
+	}
+
+	response, err := container.GetLatestStockPrices(core.NewNullContext(), 0, routing, []string{"AAPL", "600519"})
+	assert.Nil(t, err)
+
+	prices := getTestStockPriceMap(response)
+	assert.Equal(t, 2, len(prices))
+	assert.Equal(t, "200", prices["AAPL"].Price)
+	assert.Equal(t, "primary", prices["AAPL"].DataSource)
+	assert.Equal(t, "1500", prices["600519"].Price)
+	assert.Equal(t, "secondary", prices["600519"].DataSource)
+	assert.Equal(t, [][]string{{"AAPL"}}, primary.requestedSymbols)
+	assert.Equal(t, [][]string{{"600519"}}, secondary.requestedSymbols)
+}
+
+func TestStockPriceDataProviderContainerGetLatestStockPrices_FallbackToNextDataSource(t *testing.T) {
+	primary := &testStockPriceDataProvider{prices: map[string]string{"AAPL": "200"}}
+	secondary := &testStockPriceDataProvider{prices: map[string]string{"MSFT": "400", "AAPL": "199"}}
+	container := getTestStockPriceDataProviderContainer(map[string]StockPriceDataProvider{
+		"primary":   primary,
+		"secondary": secondary,
+	})
+	routing := &models.StockDataSourceRouting{
+		Configs: []*models.ExternalDataSourceConfig{{DataSource: "primary"}, {DataSource: "secondary"}},
+	}
+
+	response, err := container.GetLatestStockPrices(core.NewNullContext(), 0, routing, []string{"AAPL", "MSFT", "UNKNOWN"})
+	assert.Nil(t, err)
+
+	prices := getTestStockPriceMap(response)
+	assert.Equal(t, 2, len(prices))
+	assert.Equal(t, "primary", prices["AAPL"].DataSource)
+	assert.Equal(t, "secondary", prices["MSFT"].DataSource)
+	assert.Equal(t, [][]string{{"AAPL", "MSFT", "UNKNOWN"}}, primary.requestedSymbols)
+	assert.Equal(t, [][]string{{"MSFT", "UNKNOWN"}}, secondary.requestedSymbols)
+}
+
+func TestStockPriceDataProviderContainerGetLatestStockPrices_FallbackWhenDataSourceFailed(t *testing.T) {
+	primary := &testStockPriceDataProvider{err: errs.ErrFailedToRequestRemoteApi}
+	secondary := &testStockPriceDataProvider{prices: map[string]string{"AAPL": "199"}}
+	container := getTestStockPriceDataProviderContainer(map[string]StockPriceDataProvider{
+		"primary":   primary,
+		"secondary": secondary,
+	})
+	routing := &models.StockDataSourceRouting{
+		Configs: []*models.ExternalDataSourceConfig{{DataSource: "primary"}, {DataSource: "secondary"}},
+	}
+
+	response, err := container.GetLatestStockPrices(core.NewNullContext(), 0, routing, []string{"AAPL"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(response.Prices))
+	assert.Equal(t, "secondary", response.Prices[0].DataSource)
+
+	secondary.err = errs.ErrFailedToRequestRemoteApi
+	container.cache.Clear()
+
+	_, err = container.GetLatestStockPrices(core.NewNullContext(), 0, routing, []string{"AAPL"})
+	assert.Equal(t, errs.ErrFailedToRequestRemoteApi, err)
+}
+
+func TestStockPriceDataProviderContainerGetLatestStockPrices_NoDataSourceConfigured(t *testing.T) {
+	container := getTestStockPriceDataProviderContainer(map[string]StockPriceDataProvider{})
+
+	_, err := container.GetLatestStockPrices(core.NewNullContext(), 0, nil, []string{"AAPL"})
+	assert.Equal(t, errs.ErrStockServiceNotEnabled, err)
+
+	_, err = container.GetLatestStockPrices(core.NewNullContext(), 0, &models.StockDataSourceRouting{}, []string{"AAPL"})
+	assert.Equal(t, errs.ErrStockServiceNotEnabled, err)
+}
@@ -5,14 +5,18 @@ type UuidType uint8
 
 // Types of uuid
 const (
-	UUID_TYPE_DEFAULT     UuidType = 0
-	UUID_TYPE_USER        UuidType = 1
-	UUID_TYPE_ACCOUNT     UuidType = 2
-	UUID_TYPE_TRANSACTION UuidType = 3
-	UUID_TYPE_CATEGORY    UuidType = 4
-	UUID_TYPE_TAG         UuidType = 5
-	UUID_TYPE_TAG_INDEX   UuidType = 6
-	UUID_TYPE_TEMPLATE    UuidType = 7
-	UUID_TYPE_PICTURE     UuidType = 8
-	UUID_TYPE_EXPLORER    UuidType = 9
+	UUID_TYPE_DEFAULT            UuidType = 0
+	UUID_TYPE_USER               UuidType = 1
+	UUID_TYPE_ACCOUNT            UuidType = 2
+	UUID_TYPE_TRANSACTION        UuidType = 3
+	UUID_TYPE_CATEGORY           UuidType = 4
+	UUID_TYPE_TAG                UuidType = 5
+	UUID_TYPE_TAG_INDEX          UuidType = 6
+	UUID_TYPE_TEMPLATE           UuidType = 7
+	UUID_TYPE_PICTURE            UuidType = 8
+	UUID_TYPE_EXPLORER           UuidType = 9
+	UUID_TYPE_INVESTMENT_TRADE   UuidType = 10
+	UUID_TYPE_BUDGET             UuidType = 11
+	UUID_TYPE_GOAL               UuidType = 12
+	UUID_TYPE_TRANSACTION_REFUND UuidType = 13
 )
@@ -0,0 +1,214 @@
+package wallets
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const jsonRpcWalletAddressPlaceholder = "{address}"
+
+// JsonRpcWalletBalanceReader represents the wallet balance reader which requests any JSON-RPC 2.0 compatible endpoint,
+// e.g. a self-hosted node or a local stand-in service
+type JsonRpcWalletBalanceReader struct {
+	WalletBalanceReader
+	url        string
+	method     string
+	params     []string
+	decimals   int
+	currencies map[string]bool
+	client     *http.Client
+}
+
+// JsonRpcRequest represents the request of JSON-RPC 2.0
+type JsonRpcRequest struct {
+	JsonRpc string `json:"jsonrpc"`
+	Id      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+// JsonRpcResponse represents the response of JSON-RPC 2.0
+type JsonRpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *JsonRpcError   `json:"error"`
+}
+
+// JsonRpcError represents the error object of JSON-RPC 2.0
+type JsonRpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewJsonRpcWalletBalanceReader returns a new json-rpc wallet balance reader according to the config
+func NewJsonRpcWalletBalanceReader(config *settings.Config) *JsonRpcWalletBalanceReader {
+	currencies := make(map[string]bool, len(config.WalletBalanceJsonRpcCurrencies))
+
+	for i := 0; i < len(config.WalletBalanceJsonRpcCurrencies); i++ {
+		currencies[config.WalletBalanceJsonRpcCurrencies[i]] = true
+	}
+
+	return &JsonRpcWalletBalanceReader{
+		url:        config.WalletBalanceJsonRpcUrl,
+		method:     config.WalletBalanceJsonRpcMethod,
+		params:     config.WalletBalanceJsonRpcParams,
+		decimals:   int(config.WalletBalanceJsonRpcDecimals),
+		currencies: currencies,
+		client:     utils.NewHttpClient(config.WalletBalanceRequestTimeout, config.WalletBalanceProxy, config.WalletBalanceSkipTLSVerify, settings.GetUserAgent()),
+	}
+}
+
+// IsSupported returns whether the reader can read the balance of the specified cryptocurrency
+func (r *JsonRpcWalletBalanceReader) IsSupported(currency string) bool {
+	return r.currencies[currency]
+}
+
+// GetBalance returns the balance of the specified wallet address, the balance is in the minimum amount unit of the specified cryptocurrency stored in database
+func (r *JsonRpcWalletBalanceReader) GetBalance(c core.Context, currency string, address string) (int64, error) {
+	if !r.IsSupported(currency) {
+		return 0, errs.ErrWalletBalanceReaderNotSupportCurrency
+	}
+
+	requestBody, err := json.Marshal(r.buildRequest(address))
+
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest("POST", r.url, bytes.NewReader(requestBody))
+
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	log.Debugf(c, "[json_rpc_wallet_balance_reader.GetBalance] requesting method \"%s\" for currency \"%s\"", r.method, currency)
+
+	resp, err := r.client.Do(req)
+
+	if err != nil {
+		log.Errorf(c, "[json_rpc_wallet_balance_reader.GetBalance] failed to request wallet balance, because %s", err.Error())
+		return 0, errs.ErrFailedToRequestWalletBalance
+	}
+
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		log.Errorf(c, "[json_rpc_wallet_balance_reader.GetBalance] failed to read response body, because %s", err.Error())
+		return 0, errs.ErrFailedToRequestWalletBalance
+	}
+
+	if resp.StatusCode != 200 {
+		log.Errorf(c, "[json_rpc_wallet_balance_reader.GetBalance] response status code is %d (expected 200), response content is %s", resp.StatusCode, string(content))
+		return 0, errs.ErrFailedToRequestWalletBalance
+	}
+
+	return r.parseResponse(c, content, currency)
+}
+
+func (r *JsonRpcWalletBalanceReader) buildRequest(address string) *JsonRpcRequest {
+	params := make([]any, len(r.params))
+
+	for i := 0; i < len(r.params); i++ {
+		param := strings.ReplaceAll(r.params[i], jsonRpcWalletAddressPlaceholder, address)
+
+		if param != r.params[i] {
+			params[i] = param
+		} else if intValue, err := strconv.ParseInt(param, 10, 64); err == nil {
+			params[i] = intValue
+		} else {
+			params[i] = param
+		}
+	}
+
+	return &JsonRpcRequest{
+		JsonRpc: "2.0",
+		Id:      1,
+		Method:  r.method,
+		Params:  params,
+	}
+}
+
+func (r *JsonRpcWalletBalanceReader) parseResponse(c core.Context, content []byte, currency string) (int64, error) {
+	response := &JsonRpcResponse{}
+	err := json.Unmarshal(content, response)
+
+	if err != nil {
+		log.Errorf(c, "[json_rpc_wallet_balance_reader.parseResponse] failed to parse response, because %s", err.Error())
+		return 0, errs.ErrInvalidWalletBalanceResponse
+	}
+
+	if response.Error != nil {
+		log.Errorf(c, "[json_rpc_wallet_balance_reader.parseResponse] response contains error, code is %d, message is %s", response.Error.Code, response.Error.Message)
+		return 0, errs.ErrFailedToRequestWalletBalance
+	}
+
+	balance, err := parseJsonRpcBalanceResult(response.Result, r.decimals, models.GetCurrencyFraction(currency))
+
+	if err != nil {
+		log.Errorf(c, "[json_rpc_wallet_balance_reader.parseResponse] failed to parse balance \"%s\", because %s", string(response.Result), err.Error())
+		return 0, errs.ErrInvalidWalletBalanceResponse
+	}
+
+	return balance, nil
+}
+
+// parseJsonRpcBalanceResult converts the balance result which has the specified decimals to the amount in the specified fraction,
+// the result can be a hex string which starts with "0x", a decimal string or a number, and the extra fraction digits are truncated
+func parseJsonRpcBalanceResult(result json.RawMessage, decimals int, fraction int) (int64, error) {
+	var value string
+
+	if len(result) > 0 && result[0] == '"' {
+		if err := json.Unmarshal(result, &value); err != nil {
+			return 0, err
+		}
+	} else {
+		value = string(result)
+	}
+
+	value = strings.TrimSpace(value)
+	balance := new(big.Rat)
+
+	if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+		intValue, ok := new(big.Int).SetString(value[2:], 16)
+
+		if !ok {
+			return 0, errs.ErrInvalidWalletBalanceResponse
+		}
+
+		balance.SetInt(intValue)
+	} else if _, ok := balance.SetString(value); !ok || value == "" {
+		return 0, errs.ErrInvalidWalletBalanceResponse
+	}
+
+	if balance.Sign() < 0 {
+		return 0, errs.ErrInvalidWalletBalanceResponse
+	}
+
+	balance.Mul(balance, new(big.Rat).SetFrac(pow10(fraction), pow10(decimals)))
+	amount := new(big.Int).Quo(balance.Num(), balance.Denom())
+
+	if !amount.IsInt64() {
+		return 0, errs.ErrInvalidWalletBalanceResponse
+	}
+
+	return amount.Int64(), nil
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
@@ -0,0 +1,108 @@
+package wallets
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func TestParseJsonRpcBalanceResult(t *testing.T) {
+	testCases := []struct {
+		name     string
+		result   string
+		decimals int
+		fraction int
+		expected int64
+	}{
+		{"hex string in wei", "\"0xde0b6b3a7640000\"", 18, 5, 100000},
+		{"hex string with extra fraction digits", "\"0x1bc16d674ec80001\"", 18, 5, 200000},
+		{"zero hex string", "\"0x0\"", 18, 5, 0},
+		{"decimal string in satoshi", "\"150000000\"", 8, 8, 150000000},
+		{"decimal number in whole unit", "1.5", 0, 8, 150000000},
+		{"decimal string in whole unit", "\"0.123456789\"", 0, 8, 12345678},
+		{"less decimals than fraction", "\"15\"", 1, 3, 1500},
+	}
+
+	for _, tc := range testCases {
+		actual, err := parseJsonRpcBalanceResult(json.RawMessage(tc.result), tc.decimals, tc.fraction)
+		assert.Equal(t, nil, err, tc.name)
+		assert.Equal(t, tc.expected, actual, tc.name)
+	}
+}
+
+func TestParseJsonRpcBalanceResult_InvalidResult(t *testing.T) {
+	testCases := []struct {
+		name   string
+		result string
+	}{
+		{"empty result", ""},
+		{"null", "null"},
+		{"empty string", "\"\""},
+		{"invalid hex string", "\"0xzz\""},
+		{"invalid decimal string", "\"abc\""},
+		{"negative balance", "\"-1\""},
+		{"json object", "{\"balance\":\"1\"}"},
+		{"int64 overflow", "\"0xffffffffffffffffffffffffffffffff\""},
+	}
+
+	for _, tc := range testCases {
+		_, err := parseJsonRpcBalanceResult(json.RawMessage(tc.result), 0, 8)
+		assert.NotEqual(t, nil, err, tc.name)
+	}
+}
+
+func TestJsonRpcWalletBalanceReader_BuildRequest(t *testing.T) {
+	reader := NewJsonRpcWalletBalanceReader(&settings.Config{
+		WalletBalanceJsonRpcMethod: "getreceivedbyaddress",
+		WalletBalanceJsonRpcParams: []string{"{address}", "6"},
+	})
+
+	request := reader.buildRequest("bc1qtest")
+	assert.Equal(t, "2.0", request.JsonRpc)
+	assert.Equal(t, "getreceivedbyaddress", request.Method)
+	assert.Equal(t, []any{"bc1qtest", int64(6)}, request.Params)
+}
+
+func TestJsonRpcWalletBalanceReader_GetBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := io.ReadAll(r.Body)
+		request := &JsonRpcRequest{}
+		_ = json.Unmarshal(content, request)
+
+		if request.Method != "eth_getBalance" || len(request.Params) != 2 || request.Params[0] != "0x0000000000000000000000000000000000000001" || request.Params[1] != "latest" {
+			_, _ = w.Write([]byte("{\"jsonrpc\":\"2.0\",\"id\":1,\"error\":{\"code\":-32602,\"message\":\"invalid params\"}}"))
+			return
+		}
+
+		_, _ = w.Write([]byte("{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":\"0x29a2241af62c0000\"}"))
+	}))
+	defer server.Close()
+
+	reader := NewJsonRpcWalletBalanceReader(&settings.Config{
+		WalletBalanceProxy:             "none",
+		WalletBalanceJsonRpcUrl:        server.URL,
+		WalletBalanceJsonRpcMethod:     "eth_getBalance",
+		WalletBalanceJsonRpcParams:     []string{"{address}", "latest"},
+		WalletBalanceJsonRpcDecimals:   18,
+		WalletBalanceJsonRpcCurrencies: []string{"ETH"},
+	})
+	context := core.NewNullContext()
+
+	balance, err := reader.GetBalance(context, "ETH", "0x0000000000000000000000000000000000000001")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(300000), balance)
+
+	_, err = reader.GetBalance(context, "ETH", "0x0000000000000000000000000000000000000002")
+	assert.Equal(t, errs.ErrFailedToRequestWalletBalance, err)
+
+	_, err = reader.GetBalance(context, "BTC", "0x0000000000000000000000000000000000000001")
+	assert.Equal(t, errs.ErrWalletBalanceReaderNotSupportCurrency, err)
+}
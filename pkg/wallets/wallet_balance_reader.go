@@ -0,0 +1,14 @@
+package wallets
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+)
+
+// WalletBalanceReader defines the structure of wallet balance reader
+type WalletBalanceReader interface {
+	// IsSupported returns whether the reader can read the balance of the specified cryptocurrency
+	IsSupported(currency string) bool
+
+	// GetBalance returns the balance of the specified wallet address, the balance is in the minimum amount unit of the specified cryptocurrency stored in database
+	GetBalance(c core.Context, currency string, address string) (int64, error)
+}
@@ -0,0 +1,116 @@
+package wallets
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// WalletBalanceReaderContainer contains the current wallet balance reader
+type WalletBalanceReaderContainer struct {
+	current      WalletBalanceReader
+	accounts     *services.AccountService
+	transactions *services.TransactionService
+}
+
+// Initialize a wallet balance reader container singleton instance
+var (
+	Container = &WalletBalanceReaderContainer{
+		accounts:     services.Accounts,
+		transactions: services.Transactions,
+	}
+)
+
+// InitializeWalletBalanceReader initializes the current wallet balance reader according to the config
+func InitializeWalletBalanceReader(config *settings.Config) error {
+	if config.WalletBalanceReader == "" {
+		Container.current = nil
+		return nil
+	} else if config.WalletBalanceReader == settings.JsonRpcWalletBalanceReader {
+		Container.current = NewJsonRpcWalletBalanceReader(config)
+		return nil
+	}
+
+	return errs.ErrInvalidWalletBalanceReader
+}
+
+// IsEnabled returns whether the wallet balance reader is enabled
+func (w *WalletBalanceReaderContainer) IsEnabled() bool {
+	return w.current != nil
+}
+
+// GetWalletBalance returns the balance of the specified wallet address from the current wallet balance reader
+func (w *WalletBalanceReaderContainer) GetWalletBalance(c core.Context, currency string, address string) (int64, error) {
+	if w.current == nil {
+		return 0, errs.ErrWalletBalanceReaderNotEnabled
+	}
+
+	return w.current.GetBalance(c, currency, address)
+}
+
+// SyncAllWalletAccountBalances reads the balances of all cryptocurrency accounts which have wallet addresses,
+// and creates income or expense adjustment transactions for the accounts whose balances differ from their wallet balances
+func (w *WalletBalanceReaderContainer) SyncAllWalletAccountBalances(c core.Context) error {
+	if w.current == nil {
+		return errs.ErrWalletBalanceReaderNotEnabled
+	}
+
+	accounts, err := w.accounts.GetAllWalletAddressAccounts(c)
+
+	if err != nil {
+		return err
+	}
+
+	if len(accounts) < 1 {
+		return nil
+	}
+
+	log.Infof(c, "[wallet_balance_reader_container.SyncAllWalletAccountBalances] should sync %d wallet accounts now", len(accounts))
+
+	updatedCount := 0
+	skipCount := 0
+	failedCount := 0
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.GetAssetType() != models.ACCOUNT_ASSET_TYPE_CRYPTO || !w.current.IsSupported(account.Currency) {
+			skipCount++
+			log.Debugf(c, "[wallet_balance_reader_container.SyncAllWalletAccountBalances] account \"id:%d\" of user \"uid:%d\" is not supported by current wallet balance reader", account.AccountId, account.Uid)
+			continue
+		}
+
+		balance, err := w.current.GetBalance(c, account.Currency, account.GetWalletAddress())
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[wallet_balance_reader_container.SyncAllWalletAccountBalances] failed to get wallet balance of account \"id:%d\" of user \"uid:%d\", because %s", account.AccountId, account.Uid, err.Error())
+			continue
+		}
+
+		if balance == account.Balance {
+			skipCount++
+			continue
+		}
+
+		transaction, err := w.transactions.CreateWalletBalanceAdjustmentTransaction(c, account, balance, time.Now().Unix())
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[wallet_balance_reader_container.SyncAllWalletAccountBalances] failed to adjust balance of account \"id:%d\" of user \"uid:%d\", because %s", account.AccountId, account.Uid, err.Error())
+			continue
+		}
+
+		updatedCount++
+		log.Infof(c, "[wallet_balance_reader_container.SyncAllWalletAccountBalances] account \"id:%d\" of user \"uid:%d\" has been adjusted by transaction \"id:%d\", balance difference is %d", account.AccountId, account.Uid, transaction.TransactionId, balance-account.Balance)
+	}
+
+	log.Infof(c, "[wallet_balance_reader_container.SyncAllWalletAccountBalances] %d accounts has been adjusted successfully, %d accounts does not need to adjust and %d accounts failed to adjust", updatedCount, skipCount, failedCount)
+
+	return nil
+}
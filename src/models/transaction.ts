@@ -22,6 +22,7 @@ export class Transaction implements TransactionInfoResponse {
     public destinationAccountId: string;
     public sourceAmount: number;
     public destinationAmount: number;
+    public holdingAccountId: string = '0';
     public hideAmount: boolean;
     public tagIds: string[];
     public comment: string;
@@ -236,6 +237,7 @@ export class Transaction implements TransactionInfoResponse {
             destinationAccountId: this.type === TransactionType.Transfer ? this.destinationAccountId : '0',
             sourceAmount: this.sourceAmount,
             destinationAmount: this.type === TransactionType.Transfer ? this.destinationAmount : 0,
+            holdingAccountId: this.type === TransactionType.Income ? this.holdingAccountId : '0',
             hideAmount: this.hideAmount,
             tagIds: this.tagIds,
             pictureIds: this.getPictureIds(),
@@ -261,6 +263,7 @@ export class Transaction implements TransactionInfoResponse {
             destinationAccountId: this.type === TransactionType.Transfer ? this.destinationAccountId : '0',
             sourceAmount: this.sourceAmount,
             destinationAmount: this.type === TransactionType.Transfer ? this.destinationAmount : 0,
+            holdingAccountId: this.type === TransactionType.Income ? this.holdingAccountId : '0',
             hideAmount: this.hideAmount,
             tagIds: this.tagIds,
             pictureIds: this.getPictureIds(),
@@ -329,6 +332,10 @@ export class Transaction implements TransactionInfoResponse {
             transactionResponse.editable
         );
 
+        if (transactionResponse.holdingAccountId) {
+            transaction.holdingAccountId = transactionResponse.holdingAccountId;
+        }
+
         if (transactionResponse.category) {
             transaction.setCategory(TransactionCategory.of(transactionResponse.category));
         }
@@ -534,6 +541,7 @@ export interface TransactionCreateRequest {
     readonly destinationAccountId: string;
     readonly sourceAmount: number;
     readonly destinationAmount: number;
+    readonly holdingAccountId?: string;
     readonly hideAmount: boolean;
     readonly tagIds: string[];
     readonly pictureIds: string[];
@@ -551,6 +559,7 @@ export interface TransactionModifyRequest {
     readonly destinationAccountId: string;
     readonly sourceAmount: number;
     readonly destinationAmount: number;
+    readonly holdingAccountId?: string;
     readonly hideAmount: boolean;
     readonly tagIds: string[];
     readonly pictureIds: string[];
@@ -625,6 +634,7 @@ export interface TransactionInfoResponse {
     readonly destinationAccount?: AccountInfoResponse;
     readonly sourceAmount: number;
     readonly destinationAmount: number;
+    readonly holdingAccountId?: string;
     readonly hideAmount: boolean;
     readonly tagIds: string[];
     readonly tags?: TransactionTagInfoResponse[];
@@ -814,6 +824,9 @@ export interface TransactionStatisticAssetTrendsResponseDataItem {
     readonly accountId: string;
     readonly accountOpeningBalance: number;
     readonly accountClosingBalance: number;
+    readonly accountOpeningValue: number;
+    readonly accountClosingValue: number;
+    readonly hasValue: boolean;
 }
 
 export interface YearMonthDataItem extends Year1BasedMonth, Record<string, unknown> {}
@@ -779,6 +779,7 @@ export const useStatisticsStore = defineStore('statistics', () => {
 
         for (const assetTrendItem of assetTrendsData) {
             const statisticResponseItems: TransactionStatisticResponseItem[] = [];
+            const valuesInDefaultCurrency: Record<string, number> = {};
             const existedAccountIds: Record<string, boolean> = {};
             const missingDays: number = getDayDifference(lastAssetTrendItem, assetTrendItem) - 1;
             const lastAssetTrendItemDate: DateTime = getYearMonthDayDateTime(lastAssetTrendItem.year, lastAssetTrendItem.month, lastAssetTrendItem.day);
@@ -786,6 +787,7 @@ export const useStatisticsStore = defineStore('statistics', () => {
             // fill in missing days with last known balance
             for (let i = 1; i <= missingDays; i++) {
                 const missingStatisticResponseItems: TransactionStatisticResponseItem[] = [];
+                const missingValuesInDefaultCurrency: Record<string, number> = {};
                 const dateTime: DateTime = lastAssetTrendItemDate.add(i, 'days');
 
                 for (const item of values(lastAssetTrendItemMap)) {
@@ -795,6 +797,10 @@ export const useStatisticsStore = defineStore('statistics', () => {
                         amount: item.accountClosingBalance
                     };
 
+                    if (item.hasValue) {
+                        missingValuesInDefaultCurrency[item.accountId] = item.accountClosingValue;
+                    }
+
                     missingStatisticResponseItems.push(statisticResponseItem);
                 }
 
@@ -802,7 +808,7 @@ export const useStatisticsStore = defineStore('statistics', () => {
                     year: dateTime.getGregorianCalendarYear(),
                     month: dateTime.getGregorianCalendarMonth(),
                     day: dateTime.getGregorianCalendarDay(),
-                    items: assembleAccountAndCategoryInfo(missingStatisticResponseItems)
+                    items: assembleAccountAndCategoryInfo(missingStatisticResponseItems, missingValuesInDefaultCurrency)
                 };
 
                 lastAssetTrendItem = assetTrendItem;
@@ -817,6 +823,10 @@ export const useStatisticsStore = defineStore('statistics', () => {
                     amount: item.accountClosingBalance
                 };
 
+                if (item.hasValue) {
+                    valuesInDefaultCurrency[item.accountId] = item.accountClosingValue;
+                }
+
                 lastAssetTrendItemMap[item.accountId] = item;
                 existedAccountIds[item.accountId] = true;
                 statisticResponseItems.push(statisticResponseItem);
@@ -834,6 +844,10 @@ export const useStatisticsStore = defineStore('statistics', () => {
                     amount: item.accountClosingBalance
                 };
 
+                if (item.hasValue) {
+                    valuesInDefaultCurrency[item.accountId] = item.accountClosingValue;
+                }
+
                 existedAccountIds[item.accountId] = true;
                 statisticResponseItems.push(statisticResponseItem);
             }
@@ -842,7 +856,7 @@ export const useStatisticsStore = defineStore('statistics', () => {
                 year: assetTrendItem.year,
                 month: assetTrendItem.month,
                 day: assetTrendItem.day,
-                items: assembleAccountAndCategoryInfo(statisticResponseItems)
+                items: assembleAccountAndCategoryInfo(statisticResponseItems, valuesInDefaultCurrency)
             };
 
             lastAssetTrendItem = assetTrendItem;
@@ -1015,7 +1029,7 @@ export const useStatisticsStore = defineStore('statistics', () => {
         sortStatisticsItems(items, transactionStatisticsFilter.sortingType);
     }
 
-    function assembleAccountAndCategoryInfo(items: TransactionStatisticResponseItem[]): TransactionStatisticResponseItemWithInfo[] {
+    function assembleAccountAndCategoryInfo(items: TransactionStatisticResponseItem[], valuesInDefaultCurrency?: Record<string, number>): TransactionStatisticResponseItemWithInfo[] {
         const finalItems: TransactionStatisticResponseItemWithInfo[] = [];
         const defaultCurrency = userStore.currentUserDefaultCurrency;
 
@@ -1059,7 +1073,12 @@ export const useStatisticsStore = defineStore('statistics', () => {
                 item.primaryCategory = item.category;
             }
 
-            if (item.account && item.account.currency !== defaultCurrency) {
+            // use the value calculated by the server according to the historical prices and exchange rates (e.g. stock and cryptocurrency accounts) if it exists
+            const valueInDefaultCurrency = valuesInDefaultCurrency && item.accountId ? valuesInDefaultCurrency[item.accountId] : undefined;
+
+            if (isNumber(valueInDefaultCurrency)) {
+                item.amountInDefaultCurrency = valueInDefaultCurrency;
+            } else if (item.account && item.account.currency !== defaultCurrency) {
                 const amount = getExchangedAmount(item.amount, item.account.currency, defaultCurrency, exchangeRatesStore, cryptocurrencyPricesStore, stockPricesStore);
 
                 if (isNumber(amount)) {